@@ -0,0 +1,117 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package container
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// BlockedOnContainer is the type of the blocked reason of a container waiting for another
+	// container of the task to reach a condition
+	BlockedOnContainer = "ContainerDependency"
+	// BlockedOnExecutionCredentials is the type of the blocked reason of a container waiting for the
+	// execution role credentials of the task
+	BlockedOnExecutionCredentials = "ExecutionCredentials"
+	// BlockedOnResource is the type of the blocked reason of a container waiting for a resource of
+	// the task to reach a status
+	BlockedOnResource = "ResourceDependency"
+	// BlockedOnHostResources is the type of the blocked reason of the containers of a task waiting
+	// for enough host resources to be available to start the task
+	BlockedOnHostResources = "HostResources"
+	// BlockedOnOther is the type of any other blocked reason
+	BlockedOnOther = "Other"
+)
+
+// BlockedReason is the reason why a container can't progress to its next known status yet
+type BlockedReason struct {
+	// Type is the kind of condition the container is waiting for
+	Type string
+	// Container is the name of the container the container is waiting for
+	Container string
+	// Condition is the condition or the status the container or the resource waited for has to reach
+	Condition string
+	// Resource is the name of the task resource, or the list of host resources, the container is
+	// waiting for
+	Resource string
+	// Message describes the condition when it can't be described by the fields above
+	Message string
+	// Since is the time at which the container started to be blocked for this reason
+	Since time.Time
+}
+
+// String returns a human readable description of the blocked reason
+func (r *BlockedReason) String() string {
+	switch r.Type {
+	case BlockedOnContainer:
+		if r.Condition == "" {
+			return fmt.Sprintf("waiting for container %s", r.Container)
+		}
+		return fmt.Sprintf("waiting for container %s to be %s", r.Container, r.Condition)
+	case BlockedOnExecutionCredentials:
+		return "waiting for the execution role credentials of the task"
+	case BlockedOnResource:
+		if r.Condition == "" {
+			return fmt.Sprintf("waiting for resource %s", r.Resource)
+		}
+		return fmt.Sprintf("waiting for resource %s to be %s", r.Resource, r.Condition)
+	case BlockedOnHostResources:
+		if r.Resource == "" {
+			return "waiting for host resources to be available"
+		}
+		return fmt.Sprintf("waiting for host resources to be available: %s", r.Resource)
+	}
+	return r.Message
+}
+
+// sameAs returns true if both reasons describe the same condition, regardless of when they started
+func (r *BlockedReason) sameAs(other *BlockedReason) bool {
+	return r.Type == other.Type && r.Container == other.Container && r.Condition == other.Condition &&
+		r.Resource == other.Resource && r.Message == other.Message
+}
+
+// GetBlockedReason returns the reason why the container can't progress to its next known status
+// yet, or nil if it isn't blocked
+func (c *Container) GetBlockedReason() *BlockedReason {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.blockedReason == nil {
+		return nil
+	}
+	reason := *c.blockedReason
+	return &reason
+}
+
+// SetBlockedReason sets the reason why the container can't progress to its next known status yet,
+// nil clears it. The time since which the container is blocked is kept while the reason stays the
+// same. It returns true if the reason changed.
+func (c *Container) SetBlockedReason(reason *BlockedReason) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if reason == nil {
+		changed := c.blockedReason != nil
+		c.blockedReason = nil
+		return changed
+	}
+	if c.blockedReason != nil && c.blockedReason.sameAs(reason) {
+		return false
+	}
+	newReason := *reason
+	if newReason.Since.IsZero() {
+		newReason.Since = time.Now()
+	}
+	c.blockedReason = &newReason
+	return true
+}
@@ -0,0 +1,74 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package container
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetBlockedReason(t *testing.T) {
+	container := &Container{}
+	assert.Nil(t, container.GetBlockedReason())
+	assert.False(t, container.SetBlockedReason(nil))
+
+	assert.True(t, container.SetBlockedReason(&BlockedReason{Type: BlockedOnExecutionCredentials}))
+	reason := container.GetBlockedReason()
+	require.NotNil(t, reason)
+	assert.False(t, reason.Since.IsZero())
+	since := reason.Since
+
+	// The same reason keeps the time since which the container is blocked
+	time.Sleep(time.Millisecond)
+	assert.False(t, container.SetBlockedReason(&BlockedReason{Type: BlockedOnExecutionCredentials}))
+	assert.Equal(t, since, container.GetBlockedReason().Since)
+
+	assert.True(t, container.SetBlockedReason(&BlockedReason{Type: BlockedOnContainer, Container: "db",
+		Condition: "HEALTHY"}))
+	reason = container.GetBlockedReason()
+	assert.Equal(t, BlockedOnContainer, reason.Type)
+	assert.True(t, reason.Since.After(since))
+
+	assert.True(t, container.SetBlockedReason(nil))
+	assert.Nil(t, container.GetBlockedReason())
+}
+
+func TestBlockedReasonString(t *testing.T) {
+	testCases := []struct {
+		reason   BlockedReason
+		expected string
+	}{
+		{BlockedReason{Type: BlockedOnContainer, Container: "db", Condition: "HEALTHY"},
+			"waiting for container db to be HEALTHY"},
+		{BlockedReason{Type: BlockedOnContainer, Container: "db"}, "waiting for container db"},
+		{BlockedReason{Type: BlockedOnExecutionCredentials}, "waiting for the execution role credentials of the task"},
+		{BlockedReason{Type: BlockedOnResource, Resource: "cgroup", Condition: "CREATED"},
+			"waiting for resource cgroup to be CREATED"},
+		{BlockedReason{Type: BlockedOnHostResources}, "waiting for host resources to be available"},
+		{BlockedReason{Type: BlockedOnHostResources, Resource: "CPU, MEMORY"},
+			"waiting for host resources to be available: CPU, MEMORY"},
+		{BlockedReason{Type: BlockedOnOther, Message: "shutdown order"}, "shutdown order"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expected, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.reason.String())
+		})
+	}
+}
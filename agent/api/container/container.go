@@ -14,6 +14,7 @@
 package container
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -86,12 +87,21 @@ const (
 	// SecretTypeEnv is to show secret type being ENVIRONMENT_VARIABLE
 	SecretTypeEnv = "ENVIRONMENT_VARIABLE"
 
+	// SecretTypeMountPoint is to show secret type being MOUNT_POINT, the secret is written to a file on tmpfs which is
+	// mounted in the container and refreshed while the container runs
+	SecretTypeMountPoint = "MOUNT_POINT"
+
 	// SecretTargetLogDriver is to show secret target being "LOG_DRIVER", the default will be "CONTAINER"
 	SecretTargetLogDriver = "LOG_DRIVER"
 
 	// neuronVisibleDevicesEnvVar is the env which indicates that the container wants to use inferentia devices.
 	neuronVisibleDevicesEnvVar = "AWS_NEURON_VISIBLE_DEVICES"
 
+	// CDIDevicesEnvVar is the env with the comma separated CDI devices injected into the container. A device is
+	// either requested by fully qualified name, or by kind followed by the optional number of devices of the kind
+	// allocated by the agent, e.g. "vendor.com/fpga=0,vendor.com/nic:2".
+	CDIDevicesEnvVar = "ECS_CDI_DEVICES"
+
 	credentialSpecPrefix = "credentialspec"
 
 	credentialSpecDomainlessPrefix = credentialSpecPrefix + "domainless"
@@ -181,6 +191,12 @@ type Container struct {
 	CPU uint `json:"Cpu"`
 	// GPUIDs is the list of GPU ids for a container
 	GPUIDs []string
+	// CDIDeviceRequests is the list of CDI devices requested by the container with the ECS_CDI_DEVICES environment
+	// variable. It's only set when CDI support is enabled.
+	CDIDeviceRequests []string `json:"cdiDeviceRequests,omitempty"`
+	// AllocatedCDIDevices is the list of fully qualified names of the CDI devices allocated by the agent for the
+	// kinds of devices requested by the container
+	AllocatedCDIDevices []string `json:"allocatedCDIDevices,omitempty"`
 	// Memory is the memory limitation of the container which is specified in the task definition
 	Memory uint
 	// Links contains a list of containers to link, corresponding to docker option: --link
@@ -325,6 +341,9 @@ type Container struct {
 	ContainerTornDownUnsafe bool `json:"containerTornDown"`
 
 	createdAt time.Time
+	// blockedReason is the reason why the container can't progress to its next known status yet.
+	// It isn't saved in the state file as it's evaluated again on every transition of the task.
+	blockedReason *BlockedReason
 	// StartedAtUnsafe specifies the started at time of the container.
 	// It is exposed outside this container package so that it is marshalled/unmarshalled in JSON body while
 	// saving state.
@@ -544,6 +563,18 @@ func (c *Container) String() string {
 	return ret
 }
 
+// LogContext returns a copy of ctx carrying the logging fields identifying the container. The runtime ID is only
+// included once the container is created.
+func (c *Container) LogContext(ctx context.Context) context.Context {
+	fields := logger.Fields{
+		field.ContainerName: c.Name,
+	}
+	if runtimeID := c.GetRuntimeID(); runtimeID != "" {
+		fields[field.RuntimeID] = runtimeID
+	}
+	return logger.ContextWithFields(ctx, fields)
+}
+
 func (c *Container) Fields() logger.Fields {
 	exitCode := "nil"
 	if c.GetKnownExitCode() != nil {
@@ -664,6 +695,29 @@ func (c *Container) RestartPolicyEnabled() bool {
 	return c.RestartPolicy.Enabled
 }
 
+// RestartLimitReached returns whether the container exited after being restarted the maximum
+// number of times allowed by its restart policy. Such a container is treated as an essential
+// container, so that the task stops.
+func (c *Container) RestartLimitReached() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.RestartTracker == nil {
+		return false
+	}
+	return c.RestartTracker.IsRestartLimitReached()
+}
+
+// RestartPending returns whether the container exited and its restart is delayed by the restart
+// backoff of its restart policy.
+func (c *Container) RestartPending() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.RestartTracker == nil {
+		return false
+	}
+	return c.RestartTracker.IsRestartPending()
+}
+
 // AWSLogAuthExecutionRole returns true if the auth is by execution role
 func (c *Container) AWSLogAuthExecutionRole() bool {
 	return c.LogsAuthStrategy == awslogsAuthExecutionRole
@@ -1345,6 +1399,83 @@ func (c *Container) RequireNeuronRuntime() bool {
 	return ok
 }
 
+// GetCDIDevices returns the fully qualified names of the CDI devices requested by name by the container, followed
+// by the devices allocated for the kinds of devices it requests
+func (c *Container) GetCDIDevices() []string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	var devices []string
+	seen := make(map[string]struct{})
+	for _, device := range append(c.getCDIDeviceRequestsUnsafe(), c.AllocatedCDIDevices...) {
+		// The kinds of devices don't have the "=" separating the kind and the name of the devices
+		if _, ok := seen[device]; ok || !strings.Contains(device, "=") {
+			continue
+		}
+		seen[device] = struct{}{}
+		devices = append(devices, device)
+	}
+	return devices
+}
+
+// GetCDIDeviceKindRequests returns the number of CDI devices requested by the container for each kind of devices
+func (c *Container) GetCDIDeviceKindRequests() (map[string]int, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	requests := make(map[string]int)
+	for _, request := range c.getCDIDeviceRequestsUnsafe() {
+		if strings.Contains(request, "=") {
+			continue
+		}
+		kind, countStr, hasCount := strings.Cut(request, ":")
+		count := 1
+		if hasCount {
+			var err error
+			count, err = strconv.Atoi(countStr)
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("invalid number of CDI devices %q of kind %s", countStr, kind)
+			}
+		}
+		requests[kind] += count
+	}
+	return requests, nil
+}
+
+// InitCDIDeviceRequests parses the CDI devices requested by the container with the ECS_CDI_DEVICES environment
+// variable
+func (c *Container) InitCDIDeviceRequests() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.CDIDeviceRequests = nil
+	for _, request := range strings.Split(c.Environment[CDIDevicesEnvVar], ",") {
+		if request = strings.TrimSpace(request); request != "" {
+			c.CDIDeviceRequests = append(c.CDIDeviceRequests, request)
+		}
+	}
+}
+
+func (c *Container) getCDIDeviceRequestsUnsafe() []string {
+	return c.CDIDeviceRequests
+}
+
+// GetAllocatedCDIDevices returns the CDI devices allocated for the kinds of devices requested by the container
+func (c *Container) GetAllocatedCDIDevices() []string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.AllocatedCDIDevices
+}
+
+// SetAllocatedCDIDevices sets the CDI devices allocated for the kinds of devices requested by the container
+func (c *Container) SetAllocatedCDIDevices(devices []string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.AllocatedCDIDevices = devices
+}
+
 // SetTaskARN sets the task arn of the container.
 func (c *Container) SetTaskARN(arn string) {
 	c.lock.Lock()
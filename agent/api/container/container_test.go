@@ -17,6 +17,7 @@
 package container
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"reflect"
@@ -26,6 +27,8 @@ import (
 	resourcestatus "github.com/aws/amazon-ecs-agent/agent/taskresource/status"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/api/container/restart"
 	apicontainerstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/container/status"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
 	"github.com/docker/docker/api/types"
 
 	"github.com/aws/amazon-ecs-agent/agent/utils"
@@ -586,6 +589,16 @@ func TestSetRuntimeIDInContainer(t *testing.T) {
 	assert.Equal(t, "asdfghjkl1234", container.GetRuntimeID())
 }
 
+func TestLogContext(t *testing.T) {
+	container := &Container{Name: "app"}
+	assert.Equal(t, logger.Fields{field.ContainerName: "app"},
+		logger.FieldsFromContext(container.LogContext(context.Background())))
+
+	container.SetRuntimeID("asdfghjkl1234")
+	assert.Equal(t, logger.Fields{field.ContainerName: "app", field.RuntimeID: "asdfghjkl1234"},
+		logger.FieldsFromContext(container.LogContext(context.Background())))
+}
+
 func TestGetManagedAgents(t *testing.T) {
 	container := Container{}
 	assert.Nil(t, container.GetManagedAgents())
@@ -885,6 +898,42 @@ func TestRequireNeuronRuntime(t *testing.T) {
 	assert.True(t, c.RequireNeuronRuntime())
 }
 
+func TestGetCDIDevices(t *testing.T) {
+	c := &Container{
+		Environment: map[string]string{CDIDevicesEnvVar: "vendor.com/fpga=0, vendor.com/nic=nic0,,vendor.com/fpga=0"},
+	}
+	assert.Empty(t, c.GetCDIDevices(), "CDI devices should only be requested once the requests are parsed")
+	c.InitCDIDeviceRequests()
+	assert.Equal(t, []string{"vendor.com/fpga=0", "vendor.com/nic=nic0"}, c.GetCDIDevices())
+	assert.Empty(t, (&Container{}).GetCDIDevices())
+
+	c = &Container{
+		Environment:         map[string]string{CDIDevicesEnvVar: "vendor.com/fpga:2,vendor.com/nic=nic0"},
+		AllocatedCDIDevices: []string{"vendor.com/fpga=0", "vendor.com/fpga=1"},
+	}
+	c.InitCDIDeviceRequests()
+	assert.Equal(t, []string{"vendor.com/nic=nic0", "vendor.com/fpga=0", "vendor.com/fpga=1"}, c.GetCDIDevices())
+}
+
+func TestGetCDIDeviceKindRequests(t *testing.T) {
+	c := &Container{
+		Environment: map[string]string{
+			CDIDevicesEnvVar: "vendor.com/fpga:2, vendor.com/nic=nic0,vendor.com/nic,vendor.com/fpga",
+		},
+	}
+	c.InitCDIDeviceRequests()
+	requests, err := c.GetCDIDeviceKindRequests()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"vendor.com/fpga": 3, "vendor.com/nic": 1}, requests)
+
+	for _, request := range []string{"vendor.com/fpga:0", "vendor.com/fpga:two"} {
+		c = &Container{Environment: map[string]string{CDIDevicesEnvVar: request}}
+		c.InitCDIDeviceRequests()
+		_, err = c.GetCDIDeviceKindRequests()
+		assert.Error(t, err, request)
+	}
+}
+
 func TestHasNotAndWillNotStart(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -1447,6 +1496,17 @@ func TestRestartPolicy(t *testing.T) {
 	}
 }
 
+func TestRestartLimitReached(t *testing.T) {
+	c := &Container{}
+	require.False(t, c.RestartLimitReached())
+
+	c.RestartTracker = restart.NewRestartTracker(restart.RestartPolicy{Enabled: true})
+	require.False(t, c.RestartLimitReached())
+
+	c.RestartTracker.RestartLimitReached = true
+	require.True(t, c.RestartLimitReached())
+}
+
 func TestGetAndSetStartedAt(t *testing.T) {
 	testTime := time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC)
 	c := &Container{}
@@ -175,6 +175,11 @@ func NewContainerStateChangeEvent(task *apitask.Task, cont *apicontainer.Contain
 			"create container state change event api: status not recognized by ECS: %v",
 			contKnownStatus)}
 	}
+	if cont.RestartPending() {
+		return event, ErrShouldNotSendEvent{fmt.Sprintf(
+			"create container state change event api: restart of container %s pending, task %s",
+			cont.Name, task.Arn)}
+	}
 	if contKnownStatus == apicontainerstatus.ContainerManifestPulled && !cont.DigestResolved() {
 		// Transition to MANIFEST_PULLED state is sent to the backend only to report a resolved
 		// image manifest digest. No need to generate an event if the digest was not resolved
@@ -25,6 +25,7 @@ import (
 	"github.com/aws/amazon-ecs-agent/agent/api/serviceconnect"
 	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
 	"github.com/aws/amazon-ecs-agent/agent/engine/execcmd"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/api/container/restart"
 	apicontainerstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/container/status"
 	apitaskstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/task/status"
 
@@ -625,6 +626,24 @@ func TestNewContainerStateChangeEvent(t *testing.T) {
 				ImageDigest:   "digest",
 			},
 		},
+		{
+			name: "STOPPED state is not reported while the restart is pending",
+			task: &apitask.Task{
+				Arn: "arn",
+				Containers: []*apicontainer.Container{
+					{
+						Name:              "container",
+						KnownStatusUnsafe: apicontainerstatus.ContainerStopped,
+						RestartTracker: &restart.RestartTracker{
+							NextRestartAt: time.Now().Add(time.Minute),
+						},
+					},
+				},
+			},
+			expectedError: "should not send events for internal tasks or containers:" +
+				" create container state change event api:" +
+				" restart of container container pending, task arn",
+		},
 		{
 			name: "STOPPED state is reported",
 			task: &apitask.Task{
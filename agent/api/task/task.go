@@ -37,6 +37,7 @@ import (
 	"github.com/aws/amazon-ecs-agent/agent/taskresource/credentialspec"
 	"github.com/aws/amazon-ecs-agent/agent/taskresource/envFiles"
 	"github.com/aws/amazon-ecs-agent/agent/taskresource/firelens"
+	"github.com/aws/amazon-ecs-agent/agent/taskresource/secretfiles"
 	"github.com/aws/amazon-ecs-agent/agent/taskresource/ssmsecret"
 	resourcestatus "github.com/aws/amazon-ecs-agent/agent/taskresource/status"
 	resourcetype "github.com/aws/amazon-ecs-agent/agent/taskresource/types"
@@ -440,6 +441,8 @@ func (task *Task) PostUnmarshalTask(cfg *config.Config,
 		return apierrors.NewResourceInitError(task.Arn, err)
 	}
 
+	task.addCDIDeviceRequests(cfg)
+
 	task.initializeContainersV3MetadataEndpoint(utils.NewDynamicUUIDProvider())
 	task.initializeContainersV4MetadataEndpoint(utils.NewDynamicUUIDProvider())
 	task.initializeContainersV1AgentAPIEndpoint(utils.NewDynamicUUIDProvider())
@@ -487,7 +490,7 @@ func (task *Task) PostUnmarshalTask(cfg *config.Config,
 		}
 	}
 
-	task.initRestartTrackers()
+	task.initRestartTrackers(cfg)
 
 	for _, opt := range options {
 		if err := opt(task); err != nil {
@@ -547,11 +550,16 @@ func (task *Task) initNetworkMode(acsTaskNetworkMode *string) {
 }
 
 // initRestartTrackers initializes the restart policy tracker for each container
-// that has a restart policy configured and enabled.
-func (task *Task) initRestartTrackers() {
+// that has a restart policy configured and enabled. The restart backoff and the maximum
+// restart count configured on the instance apply to all of these containers.
+func (task *Task) initRestartTrackers(cfg *config.Config) {
 	for _, c := range task.Containers {
 		if c.RestartPolicyEnabled() {
-			c.RestartTracker = restart.NewRestartTracker(*c.RestartPolicy)
+			restartPolicy := *c.RestartPolicy
+			restartPolicy.BackoffInitialPeriod = int(cfg.ContainerRestartBackoffInitial.Seconds())
+			restartPolicy.BackoffMaxPeriod = int(cfg.ContainerRestartBackoffMax.Seconds())
+			restartPolicy.MaxRestartCount = int(cfg.ContainerRestartMaxCount)
+			c.RestartTracker = restart.NewRestartTracker(restartPolicy)
 		}
 	}
 }
@@ -668,7 +676,7 @@ func (task *Task) initSecretResources(cfg *config.Config, credentialsManager cre
 	}
 
 	if task.requiresASMSecret() {
-		task.initializeASMSecretResource(credentialsManager, resourceFields)
+		task.initializeASMSecretResource(cfg, credentialsManager, resourceFields)
 	}
 }
 
@@ -715,6 +723,17 @@ func (task *Task) addGPUResource(cfg *config.Config) error {
 	return nil
 }
 
+// addCDIDeviceRequests parses the CDI devices requested by the containers when CDI support is enabled. Otherwise the
+// devices are neither allocated, accounted for nor injected, and ECS_CDI_DEVICES is only passed to the containers.
+func (task *Task) addCDIDeviceRequests(cfg *config.Config) {
+	if !cfg.CDISupportEnabled {
+		return
+	}
+	for _, container := range task.Containers {
+		container.InitCDIDeviceRequests()
+	}
+}
+
 func (task *Task) isGPUEnabled() bool {
 	for _, association := range task.Associations {
 		if association.Type == GPUAssociationType {
@@ -1119,7 +1138,9 @@ func (task *Task) initializeSSMSecretResource(cfg *config.Config,
 	credentialsManager credentials.Manager,
 	resourceFields *taskresource.ResourceFields) {
 	ssmSecretResource := ssmsecret.NewSSMSecretResource(task.Arn, task.getAllSSMSecretRequirements(),
-		task.ExecutionCredentialsID, credentialsManager, resourceFields.SSMClientCreator, cfg.InstanceIPCompatibility)
+		task.getSecretFileRequirements(cfg, apicontainer.SecretProviderSSM), task.ExecutionCredentialsID,
+		credentialsManager, resourceFields.SSMClientCreator, cfg.InstanceIPCompatibility, cfg.SecretFilesDir,
+		cfg.SecretRefreshInterval, resourceFields.MetricsFactory)
 	task.AddResource(ssmsecret.ResourceName, ssmSecretResource)
 
 	// for every container that needs ssm secret vending as env, it needs to wait all secrets got retrieved
@@ -1184,10 +1205,13 @@ func (task *Task) requiresASMSecret() bool {
 }
 
 // initializeASMSecretResource builds the resource dependency map for the asmsecret resource
-func (task *Task) initializeASMSecretResource(credentialsManager credentials.Manager,
+func (task *Task) initializeASMSecretResource(cfg *config.Config,
+	credentialsManager credentials.Manager,
 	resourceFields *taskresource.ResourceFields) {
 	asmSecretResource := asmsecret.NewASMSecretResource(task.Arn, task.getAllASMSecretRequirements(),
-		task.ExecutionCredentialsID, credentialsManager, resourceFields.ASMClientCreator)
+		task.getSecretFileRequirements(cfg, apicontainer.SecretProviderASM), task.ExecutionCredentialsID,
+		credentialsManager, resourceFields.ASMClientCreator, cfg.SecretFilesDir, cfg.SecretRefreshInterval,
+		resourceFields.MetricsFactory)
 	task.AddResource(asmsecret.ResourceName, asmSecretResource)
 
 	// for every container that needs asm secret vending as envvar, it needs to wait all secrets got retrieved
@@ -1224,6 +1248,43 @@ func (task *Task) getAllASMSecretRequirements() map[string]apicontainer.Secret {
 	return reqs
 }
 
+// getSecretFileRequirements returns the secrets of the provider delivered as files, keyed by the name of their
+// container. When secret files aren't enabled, the secrets of type MOUNT_POINT are ignored as they were before
+// secret files were supported.
+func (task *Task) getSecretFileRequirements(cfg *config.Config, provider string) map[string][]apicontainer.Secret {
+	reqs := make(map[string][]apicontainer.Secret)
+
+	for _, container := range task.Containers {
+		for _, secret := range container.Secrets {
+			if secret.Provider != provider || secret.Type != apicontainer.SecretTypeMountPoint {
+				continue
+			}
+			if !cfg.SecretFilesEnabled.Enabled() {
+				logger.Warn("Ignoring secret of type MOUNT_POINT because secret files are not enabled", logger.Fields{
+					field.TaskARN:   task.Arn,
+					field.Container: container.Name,
+					"secret":        secret.Name,
+				})
+				continue
+			}
+			reqs[container.Name] = append(reqs[container.Name], secret)
+		}
+	}
+	return reqs
+}
+
+// AddSecretFilesBindMounts adds the read-only bind mounts of the directories with the secret files of the container
+// to its host config.
+func (task *Task) AddSecretFilesBindMounts(container *apicontainer.Container, hostConfig *dockercontainer.HostConfig,
+	config *config.Config) *apierrors.HostConfigError {
+	binds, err := secretfiles.Binds(config.SecretFilesDir, task.Arn, container)
+	if err != nil {
+		return &apierrors.HostConfigError{Msg: fmt.Sprintf("unable to add secret files bind mounts: %v", err)}
+	}
+	hostConfig.Binds = append(hostConfig.Binds, binds...)
+	return nil
+}
+
 // GetFirelensContainer returns the firelens container in the task, if there is one.
 func (task *Task) GetFirelensContainer() *apicontainer.Container {
 	for _, container := range task.Containers {
@@ -1694,8 +1755,9 @@ func (task *Task) updateTaskKnownStatus() (newStatus apitaskstatus.TaskStatus) {
 	containerEarliestKnownStatus := apicontainerstatus.ContainerZombie
 	var earliestKnownStatusContainer, essentialContainerStopped *apicontainer.Container
 	for _, container := range task.Containers {
-		containerKnownStatus := container.GetKnownStatus()
-		if containerKnownStatus == apicontainerstatus.ContainerStopped && container.Essential {
+		containerKnownStatus := containerKnownStatusForTask(container)
+		if containerKnownStatus == apicontainerstatus.ContainerStopped &&
+			(container.Essential || container.RestartLimitReached()) {
 			essentialContainerStopped = container
 		}
 		if containerKnownStatus < containerEarliestKnownStatus {
@@ -1756,7 +1818,8 @@ func (task *Task) getEarliestKnownTaskStatusForContainers() apitaskstatus.TaskSt
 	// Set earliest container status to an impossible to reach 'high' task status
 	earliest := apitaskstatus.TaskZombie
 	for _, container := range task.Containers {
-		containerTaskStatus := apitaskstatus.MapContainerToTaskStatus(container.GetKnownStatus(), container.GetSteadyStateStatus())
+		containerTaskStatus := apitaskstatus.MapContainerToTaskStatus(containerKnownStatusForTask(container),
+			container.GetSteadyStateStatus())
 		if containerTaskStatus < earliest {
 			earliest = containerTaskStatus
 		}
@@ -1765,6 +1828,15 @@ func (task *Task) getEarliestKnownTaskStatusForContainers() apitaskstatus.TaskSt
 	return earliest
 }
 
+// containerKnownStatusForTask returns the known status of the container as it counts towards the status
+// of the task. A container whose restart is delayed by its restart policy still counts as running.
+func containerKnownStatusForTask(container *apicontainer.Container) apicontainerstatus.ContainerStatus {
+	if container.RestartPending() {
+		return container.GetSteadyStateStatus()
+	}
+	return container.GetKnownStatus()
+}
+
 // DockerConfig converts the given container in this task to the format of
 // the Docker SDK 'Config' struct
 func (task *Task) DockerConfig(container *apicontainer.Container, apiVersion dockerclient.DockerVersion) (*dockercontainer.Config, *apierrors.DockerClientConfigError) {
@@ -2727,10 +2799,19 @@ func (task *Task) updateTaskDesiredStatusUnsafe() {
 		if task.DesiredStatusUnsafe == apitaskstatus.TaskStopped {
 			break
 		}
+		if cont.RestartPending() {
+			continue
+		}
 		if cont.Essential && (cont.KnownTerminal() || cont.DesiredTerminal()) {
 			task.DesiredStatusUnsafe = apitaskstatus.TaskStopped
 			logger.Info("Essential container stopped; updated task desired status to stopped",
 				task.fieldsUnsafe())
+		} else if cont.RestartLimitReached() && cont.KnownTerminal() {
+			// A container which kept failing after being restarted as many times as allowed by its
+			// restart policy fails the task like an essential container would.
+			task.DesiredStatusUnsafe = apitaskstatus.TaskStopped
+			logger.Info("Container stopped after reaching its restart limit; updated task desired status to stopped",
+				task.fieldsUnsafe())
 		}
 	}
 }
@@ -3047,10 +3128,18 @@ func (task *Task) GetID() string {
 	return task.id
 }
 
+// LogContext returns a copy of ctx carrying the logging fields identifying the task
+func (task *Task) LogContext(ctx context.Context) context.Context {
+	return logger.ContextWithFields(ctx, logger.Fields{
+		field.TaskARN: task.Arn,
+		field.TaskID:  task.GetID(),
+	})
+}
+
 // RecordExecutionStoppedAt checks if this is an essential container stopped
 // and set the task executionStoppedAt timestamps
 func (task *Task) RecordExecutionStoppedAt(container *apicontainer.Container) {
-	if !container.Essential {
+	if !container.Essential && !container.RestartLimitReached() {
 		return
 	}
 	if container.GetKnownStatus() != apicontainerstatus.ContainerStopped {
@@ -3720,6 +3809,10 @@ func (task *Task) IsLaunchTypeFargate() bool {
 //
 // * GPU
 //   - Concatenate each container's gpu ids
+//
+// * CDI
+//   - Concatenate each container's CDI devices without duplicates, only when the task requests some. The
+//     devices are only requested when CDI support is enabled.
 func (task *Task) ToHostResources() map[string]ecstypes.Resource {
 	resources := make(map[string]ecstypes.Resource)
 	// CPU
@@ -3823,6 +3916,26 @@ func (task *Task) ToHostResources() map[string]ecstypes.Resource {
 		Type:           utils.Strptr("STRINGSET"),
 		StringSetValue: gpus,
 	}
+
+	// CDI
+	// The containers of the task may share the same devices
+	var cdiDevices []string
+	seenCDIDevices := make(map[string]struct{})
+	for _, c := range task.Containers {
+		for _, device := range c.GetCDIDevices() {
+			if _, ok := seenCDIDevices[device]; !ok {
+				seenCDIDevices[device] = struct{}{}
+				cdiDevices = append(cdiDevices, device)
+			}
+		}
+	}
+	if len(cdiDevices) > 0 {
+		resources["CDI"] = ecstypes.Resource{
+			Name:           utils.Strptr("CDI"),
+			Type:           utils.Strptr("STRINGSET"),
+			StringSetValue: cdiDevices,
+		}
+	}
 	logger.Debug("Task host resources to account for", logger.Fields{
 		"taskArn":   task.Arn,
 		"CPU":       resources["CPU"].IntegerValue,
@@ -3830,6 +3943,7 @@ func (task *Task) ToHostResources() map[string]ecstypes.Resource {
 		"PORTS_TCP": resources["PORTS_TCP"].StringSetValue,
 		"PORTS_UDP": resources["PORTS_UDP"].StringSetValue,
 		"GPU":       resources["GPU"].StringSetValue,
+		"CDI":       resources["CDI"].StringSetValue,
 	})
 	return resources
 }
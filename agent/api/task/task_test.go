@@ -17,6 +17,7 @@
 package task
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"reflect"
@@ -55,6 +56,8 @@ import (
 	"github.com/aws/amazon-ecs-agent/ecs-agent/credentials"
 	mock_credentials "github.com/aws/amazon-ecs-agent/ecs-agent/credentials/mocks"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/ipcompatibility"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
 	ni "github.com/aws/amazon-ecs-agent/ecs-agent/netlib/model/networkinterface"
 	commonutils "github.com/aws/amazon-ecs-agent/ecs-agent/utils"
 	dockertypes "github.com/docker/docker/api/types"
@@ -2197,6 +2200,20 @@ func TestGetIDHappyPath(t *testing.T) {
 	assert.Equal(t, "task-id", taskID)
 }
 
+// TestLogContext validates the logging fields of the task carried by its log context
+func TestLogContext(t *testing.T) {
+	task := &Task{
+		Arn: "arn:aws:ecs:region:account-id:task/cluster-name/task-id",
+	}
+	ctx := logger.ContextWithFields(context.Background(), logger.Fields{field.Cluster: "cluster-name"})
+
+	assert.Equal(t, logger.Fields{
+		field.Cluster: "cluster-name",
+		field.TaskARN: "arn:aws:ecs:region:account-id:task/cluster-name/task-id",
+		field.TaskID:  "task-id",
+	}, logger.FieldsFromContext(task.LogContext(ctx)))
+}
+
 // TestTaskGetPrimaryENI tests the eni can be correctly acquired by calling GetTaskPrimaryENI
 func TestTaskGetPrimaryENI(t *testing.T) {
 	enisOfTask := []*ni.NetworkInterface{
@@ -2526,6 +2543,7 @@ func TestContainerHealthConfig(t *testing.T) {
 func TestRecordExecutionStoppedAt(t *testing.T) {
 	testCases := []struct {
 		essential             bool
+		restartLimitReached   bool
 		status                apicontainerstatus.ContainerStatus
 		executionStoppedAtSet bool
 		msg                   string
@@ -2548,14 +2566,25 @@ func TestRecordExecutionStoppedAt(t *testing.T) {
 			executionStoppedAtSet: false,
 			msg:                   "essential non-stop status change should not cause executionStoppedAt set",
 		},
+
+		{
+			essential:             false,
+			restartLimitReached:   true,
+			status:                apicontainerstatus.ContainerStopped,
+			executionStoppedAtSet: true,
+			msg:                   "non essential container stopped after reaching its restart limit should have executionStoppedAt set",
+		},
 	}
 
 	for _, tc := range testCases {
-		t.Run(fmt.Sprintf("Container status: %s, essential: %v, executionStoppedAt should be set: %v", tc.status, tc.essential, tc.executionStoppedAtSet), func(t *testing.T) {
+		t.Run(fmt.Sprintf("Container status: %s, essential: %v, restart limit reached: %v, executionStoppedAt should be set: %v", tc.status, tc.essential, tc.restartLimitReached, tc.executionStoppedAtSet), func(t *testing.T) {
 			task := &Task{}
 			task.RecordExecutionStoppedAt(&apicontainer.Container{
 				Essential:         tc.essential,
 				KnownStatusUnsafe: tc.status,
+				RestartTracker: &restart.RestartTracker{
+					RestartLimitReached: tc.restartLimitReached,
+				},
 			})
 			assert.Equal(t, !tc.executionStoppedAtSet, task.GetExecutionStoppedAt().IsZero(), tc.msg)
 		})
@@ -3214,6 +3243,59 @@ func TestGetAllASMSecretRequirements(t *testing.T) {
 	assert.Equal(t, 2, len(reqs))
 }
 
+func TestSecretFiles(t *testing.T) {
+	envSecret := apicontainer.Secret{
+		Provider:  "asm",
+		Name:      "DB_USERNAME",
+		Region:    "us-west-2",
+		Type:      apicontainer.SecretTypeEnv,
+		ValueFrom: "/test/secretName1",
+	}
+	asmFileSecret := apicontainer.Secret{
+		Provider:  "asm",
+		Name:      "db_password",
+		Region:    "us-west-2",
+		Type:      apicontainer.SecretTypeMountPoint,
+		ValueFrom: "/test/secretName2",
+	}
+	ssmFileSecret := apicontainer.Secret{
+		Provider:      "ssm",
+		Name:          "api_key",
+		Region:        "us-west-2",
+		Type:          apicontainer.SecretTypeMountPoint,
+		ContainerPath: "/etc/app",
+		ValueFrom:     "/test/secretName3",
+	}
+
+	container := &apicontainer.Container{
+		Name:    "myName",
+		Image:   "image:tag",
+		Secrets: []apicontainer.Secret{envSecret, asmFileSecret, ssmFileSecret},
+	}
+	task := &Task{
+		Arn:                "arn:aws:ecs:us-west-2:123456789012:task/cluster/task-id",
+		ResourcesMapUnsafe: make(map[string][]taskresource.TaskResource),
+		Containers:         []*apicontainer.Container{container},
+	}
+
+	cfg := &config.Config{SecretFilesEnabled: config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled}}
+	assert.Equal(t, map[string][]apicontainer.Secret{"myName": {asmFileSecret}},
+		task.getSecretFileRequirements(cfg, apicontainer.SecretProviderASM))
+	assert.Equal(t, map[string][]apicontainer.Secret{"myName": {ssmFileSecret}},
+		task.getSecretFileRequirements(cfg, apicontainer.SecretProviderSSM))
+
+	// the secrets of type MOUNT_POINT are ignored when secret files aren't enabled
+	assert.Empty(t, task.getSecretFileRequirements(&config.Config{}, apicontainer.SecretProviderASM))
+	assert.Empty(t, task.getSecretFileRequirements(&config.Config{}, apicontainer.SecretProviderSSM))
+
+	hostConfig := &dockercontainer.HostConfig{}
+	require.Nil(t, task.AddSecretFilesBindMounts(container, hostConfig, &config.Config{SecretFilesDir: "/run/ecs/secrets"}))
+	assert.Equal(t, []string{
+		"/run/ecs/secrets/task-id/myName/%2Fetc%2Fapp:/etc/app:ro",
+		"/run/ecs/secrets/task-id/myName/%2Frun%2Fsecrets:/run/secrets:ro",
+	}, hostConfig.Binds)
+}
+
 func TestInitializeAndGetASMSecretResource(t *testing.T) {
 	secret := apicontainer.Secret{
 		Provider:  "asm",
@@ -3255,7 +3337,7 @@ func TestInitializeAndGetASMSecretResource(t *testing.T) {
 		},
 	}
 
-	task.initializeASMSecretResource(credentialsManager, resFields)
+	task.initializeASMSecretResource(&config.Config{}, credentialsManager, resFields)
 
 	resourceDep := apicontainer.ResourceDependency{
 		Name:           asmsecret.ResourceName,
@@ -3952,6 +4034,113 @@ func TestPostUnmarshalTaskContainerRestartPolicy(t *testing.T) {
 	}
 }
 
+func TestPostUnmarshalTaskContainerRestartBackoff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		ContainerRestartBackoffInitial: 10 * time.Second,
+		ContainerRestartBackoffMax:     5 * time.Minute,
+		ContainerRestartMaxCount:       3,
+	}
+	credentialsManager := mock_credentials.NewMockManager(ctrl)
+	resFields := &taskresource.ResourceFields{
+		ResourceFieldsCommon: &taskresource.ResourceFieldsCommon{
+			CredentialsManager: credentialsManager,
+		},
+	}
+
+	container := &apicontainer.Container{
+		Name:  "container",
+		Image: "image:tag",
+		RestartPolicy: &restart.RestartPolicy{
+			Enabled:              true,
+			RestartAttemptPeriod: 60,
+		},
+		TransitionDependenciesMap: make(map[apicontainerstatus.ContainerStatus]apicontainer.TransitionDependencySet),
+	}
+	task := &Task{
+		Arn:                testTaskARN,
+		ResourcesMapUnsafe: make(map[string][]taskresource.TaskResource),
+		Containers:         []*apicontainer.Container{container},
+	}
+
+	require.NoError(t, task.PostUnmarshalTask(cfg, credentialsManager, resFields, nil, nil))
+
+	assert.Equal(t, restart.RestartPolicy{
+		Enabled:              true,
+		RestartAttemptPeriod: 60,
+		BackoffInitialPeriod: 10,
+		BackoffMaxPeriod:     300,
+		MaxRestartCount:      3,
+	}, container.RestartTracker.RestartPolicy)
+	// The restart policy received from the backend is left untouched
+	assert.Equal(t, 0, container.RestartPolicy.MaxRestartCount)
+
+	// The backoff and the maximum restart count only come from the instance config, even when the restart policy
+	// has other values, e.g. from a task restored from the state saved with another config
+	container.RestartPolicy.BackoffInitialPeriod = 5
+	container.RestartPolicy.MaxRestartCount = 10
+	task.initRestartTrackers(&config.Config{
+		ContainerRestartBackoffInitial: 30 * time.Second,
+		ContainerRestartBackoffMax:     10 * time.Minute,
+		ContainerRestartMaxCount:       5,
+	})
+	assert.Equal(t, restart.RestartPolicy{
+		Enabled:              true,
+		RestartAttemptPeriod: 60,
+		BackoffInitialPeriod: 30,
+		BackoffMaxPeriod:     600,
+		MaxRestartCount:      5,
+	}, container.RestartTracker.RestartPolicy)
+}
+
+func TestUpdateTaskStatusRestartPending(t *testing.T) {
+	container := &apicontainer.Container{
+		Name:              "app",
+		Essential:         true,
+		KnownStatusUnsafe: apicontainerstatus.ContainerStopped,
+		RestartTracker: &restart.RestartTracker{
+			NextRestartAt: time.Now().Add(time.Minute),
+		},
+	}
+	task := &Task{
+		Arn:                 testTaskARN,
+		KnownStatusUnsafe:   apitaskstatus.TaskRunning,
+		DesiredStatusUnsafe: apitaskstatus.TaskRunning,
+		Containers:          []*apicontainer.Container{container},
+	}
+
+	// An essential container whose restart is delayed doesn't stop the task
+	assert.False(t, task.UpdateStatus())
+	assert.Equal(t, apitaskstatus.TaskRunning, task.GetKnownStatus())
+	assert.Equal(t, apitaskstatus.TaskRunning, task.GetDesiredStatus())
+
+	container.RestartTracker.CancelDelayedRestart()
+	assert.True(t, task.UpdateStatus())
+	assert.Equal(t, apitaskstatus.TaskStopped, task.GetKnownStatus())
+	assert.Equal(t, apitaskstatus.TaskStopped, task.GetDesiredStatus())
+}
+
+func TestUpdateTaskDesiredStatusRestartLimitReached(t *testing.T) {
+	container := &apicontainer.Container{
+		Name:              "sidecar",
+		Essential:         false,
+		KnownStatusUnsafe: apicontainerstatus.ContainerStopped,
+		RestartTracker: &restart.RestartTracker{
+			RestartLimitReached: true,
+		},
+	}
+	task := &Task{
+		Arn:                 testTaskARN,
+		DesiredStatusUnsafe: apitaskstatus.TaskRunning,
+		Containers:          []*apicontainer.Container{container},
+	}
+
+	task.UpdateDesiredStatus()
+	assert.Equal(t, apitaskstatus.TaskStopped, task.GetDesiredStatus())
+}
+
 func TestInitializeAndGetEnvfilesResource(t *testing.T) {
 	envfile1 := apicontainer.EnvironmentFile{
 		Value: "s3://bucket/envfile1",
@@ -5341,6 +5530,33 @@ func TestToHostResources(t *testing.T) {
 	}
 }
 
+func TestToHostResourcesCDIDevices(t *testing.T) {
+	testTask := &Task{
+		Containers: []*apicontainer.Container{
+			{
+				Environment: map[string]string{
+					apicontainer.CDIDevicesEnvVar: "vendor.com/fpga=0,vendor.com/fpga=1",
+				},
+			},
+			{
+				Environment: map[string]string{
+					apicontainer.CDIDevicesEnvVar: "vendor.com/fpga=1,vendor.com/nic=nic0",
+				},
+			},
+		},
+	}
+	_, ok := testTask.ToHostResources()["CDI"]
+	assert.False(t, ok, "CDI devices should not be accounted for when CDI support is disabled")
+
+	testTask.addCDIDeviceRequests(&config.Config{CDISupportEnabled: true})
+	resources := testTask.ToHostResources()
+	assert.Equal(t, []string{"vendor.com/fpga=0", "vendor.com/fpga=1", "vendor.com/nic=nic0"},
+		resources["CDI"].StringSetValue)
+
+	_, ok = (&Task{Containers: []*apicontainer.Container{{}}}).ToHostResources()["CDI"]
+	assert.False(t, ok, "Tasks without CDI devices should not account for CDI resources")
+}
+
 func TestRemoveVolumes(t *testing.T) {
 	task := &Task{
 		Volumes: []TaskVolume{
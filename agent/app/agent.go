@@ -36,6 +36,7 @@ import (
 	dm "github.com/aws/amazon-ecs-agent/agent/engine/daemonmanager"
 	"github.com/aws/amazon-ecs-agent/agent/engine/dockerstate"
 	"github.com/aws/amazon-ecs-agent/agent/engine/execcmd"
+	"github.com/aws/amazon-ecs-agent/agent/engine/imagesignature"
 	engineserviceconnect "github.com/aws/amazon-ecs-agent/agent/engine/serviceconnect"
 	"github.com/aws/amazon-ecs-agent/agent/eni/pause"
 	"github.com/aws/amazon-ecs-agent/agent/eni/watcher"
@@ -47,7 +48,9 @@ import (
 	"github.com/aws/amazon-ecs-agent/agent/statemanager"
 	"github.com/aws/amazon-ecs-agent/agent/stats"
 	"github.com/aws/amazon-ecs-agent/agent/stats/reporter"
+	"github.com/aws/amazon-ecs-agent/agent/supportbundle"
 	"github.com/aws/amazon-ecs-agent/agent/taskresource"
+	"github.com/aws/amazon-ecs-agent/agent/taskresource/secretfiles"
 	"github.com/aws/amazon-ecs-agent/agent/utils"
 	"github.com/aws/amazon-ecs-agent/agent/utils/loader"
 	"github.com/aws/amazon-ecs-agent/agent/utils/mobypkgwrapper"
@@ -67,6 +70,8 @@ import (
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
 	metricsfactory "github.com/aws/amazon-ecs-agent/ecs-agent/metrics"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/tcs/model/ecstcs"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tracing"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/utils/retry"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/wsclient"
 
@@ -106,6 +111,11 @@ const (
 	// as the number of messages in the channel is equal to the number of times we call `getInstanceMetrics`, which collects
 	// metrics from all tasks and containers and put them into one TelemetryMessage object.
 	telemetryChannelDefaultBufferSize = 15
+
+	// tracingServiceName is the service name of the spans exported by the agent
+	tracingServiceName = "amazon-ecs-agent"
+	// tracingShutdownTimeout is the maximum duration of the export of the pending spans when the agent exits
+	tracingShutdownTimeout = 5 * time.Second
 )
 
 var (
@@ -155,11 +165,17 @@ type ecsAgent struct {
 	mac                         string
 	metadataManager             containermetadata.Manager
 	terminationHandler          sighandlers.TerminationHandler
+	instanceDrainer             *instanceDrainer
 	mobyPlugins                 mobypkgwrapper.Plugins
 	resourceFields              *taskresource.ResourceFields
 	availabilityZone            string
 	availabilityZoneID          string
 	latestSeqNumberTaskManifest *int64
+	metricsFactory              metricsfactory.EntryFactory
+	imagePrewarmer              *engine.ImagePrewarmer
+	// loadedCfg is a copy of the configuration as loaded, before the agent adjusted cfg at runtime. The
+	// configuration reloaded on SIGHUP is compared against it.
+	loadedCfg *config.Config
 }
 
 // newAgent returns a new ecsAgent object, but does not start anything
@@ -196,6 +212,7 @@ func newAgent(blackholeEC2Metadata bool, acceptInsecureCert *bool) (agent, error
 		cancel()
 		return nil, err
 	}
+	loadedCfg := *cfg
 	cfg.AcceptInsecureCert = aws.ToBool(acceptInsecureCert)
 	if cfg.AcceptInsecureCert {
 		seelog.Warn("SSL certificate verification disabled. This is not recommended.")
@@ -258,6 +275,21 @@ func newAgent(blackholeEC2Metadata bool, acceptInsecureCert *bool) (agent, error
 		providers.NewRotatingSharedCredentialsProviderV2(),
 		nil,
 	)
+	metricsFactory := metricsfactory.NewNopEntryFactory()
+	if cfg.PrometheusMetricsEnabled {
+		logger.Info("Prometheus metrics enabled, exposing them on the introspection server", logger.Fields{
+			"path": metricsfactory.PrometheusMetricsPath,
+		})
+		metricsFactory = metricsfactory.NewPrometheusEntryFactory()
+	}
+
+	drainer := newInstanceDrainer(cfg.DataDir, dataClient)
+	terminationHandler := sighandlers.StartDefaultTerminationHandler
+	if cfg.TerminationDrainingEnabled.Enabled() {
+		terminationHandler = sighandlers.NewDrainingTerminationHandler(drainer.drain, drainer.shuttingDown,
+			cfg.TerminationDrainingTimeout)
+	}
+
 	initialSeqNumber := int64(-1)
 	return &ecsAgent{
 		ctx:               ctx,
@@ -265,6 +297,7 @@ func newAgent(blackholeEC2Metadata bool, acceptInsecureCert *bool) (agent, error
 		ec2MetadataClient: ec2MetadataClient,
 		ec2Client:         ec2Client,
 		cfg:               cfg,
+		loadedCfg:         &loadedCfg,
 		dockerClient:      dockerClient,
 		dataClient:        dataClient,
 		// We instantiate our own credentialProvider for use in acs/tcs. This tries
@@ -278,9 +311,11 @@ func newAgent(blackholeEC2Metadata bool, acceptInsecureCert *bool) (agent, error
 		daemonManagers:              make(map[string]dm.DaemonManager),
 		cniClient:                   ecscni.NewClient(cfg.CNIPluginsPath),
 		metadataManager:             metadataManager,
-		terminationHandler:          sighandlers.StartDefaultTerminationHandler,
+		terminationHandler:          terminationHandler,
+		instanceDrainer:             drainer,
 		mobyPlugins:                 mobypkgwrapper.NewPlugins(),
 		latestSeqNumberTaskManifest: &initialSeqNumber,
+		metricsFactory:              metricsFactory,
 	}, nil
 }
 
@@ -288,6 +323,29 @@ func (agent *ecsAgent) getConfig() *config.Config {
 	return agent.cfg
 }
 
+// getMetricsFactory returns the factory used to emit agent metrics, falling back
+// to a no-op factory if none has been configured.
+func (agent *ecsAgent) getMetricsFactory() metricsfactory.EntryFactory {
+	if agent.metricsFactory == nil {
+		return metricsfactory.NewNopEntryFactory()
+	}
+	return agent.metricsFactory
+}
+
+// secretFilesInit disables secret files when their directory can't be used, so that the secrets of type
+// MOUNT_POINT are ignored instead of failing the tasks
+func (agent *ecsAgent) secretFilesInit() {
+	if !agent.cfg.SecretFilesEnabled.Enabled() {
+		return
+	}
+	if err := secretfiles.CheckDir(agent.cfg.SecretFilesDir); err != nil {
+		logger.Error("Disabling secret files, the secrets of type MOUNT_POINT will be ignored", logger.Fields{
+			field.Error: err,
+		})
+		agent.cfg.SecretFilesEnabled = config.BooleanDefaultFalse{Value: config.ExplicitlyDisabled}
+	}
+}
+
 // printECSAttributes prints the Agent's ECS Attributes based on its
 // environment
 func (agent *ecsAgent) printECSAttributes() int {
@@ -310,6 +368,32 @@ func (agent *ecsAgent) setTerminationHandler(handler sighandlers.TerminationHand
 func (agent *ecsAgent) start() int {
 	sighandlers.StartDebugHandler()
 
+	if agent.cfg.TracingEndpoint != "" {
+		shutdownTracing, err := tracing.Init(tracing.Config{
+			Endpoint:       agent.cfg.TracingEndpoint,
+			ServiceName:    tracingServiceName,
+			ServiceVersion: version.Version,
+		})
+		if err != nil {
+			logger.Error("Unable to initialize tracing, the task lifecycle operations won't be traced", logger.Fields{
+				field.Error: err,
+			})
+		} else {
+			logger.Info("Exporting the traces of the task lifecycle operations", logger.Fields{
+				"endpoint": agent.cfg.TracingEndpoint,
+			})
+			defer func() {
+				ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+				defer cancel()
+				if err := shutdownTracing(ctx); err != nil {
+					logger.Warn("Unable to export the pending spans", logger.Fields{
+						field.Error: err,
+					})
+				}
+			}()
+		}
+	}
+
 	containerChangeEventStream := eventstream.NewEventStream(containerChangeEventStreamName, agent.ctx)
 	credentialsManager := credentials.NewManager()
 	state := dockerstate.NewTaskEngineState()
@@ -333,6 +417,8 @@ func (agent *ecsAgent) start() int {
 
 		// If instance is IPv6-only then we must use dualstack ECS endpoints
 		ecsclient.WithDualStackEnabled(agent.cfg.InstanceIPCompatibility.IsIPv6Only()),
+
+		ecsclient.WithMetricsFactory(agent.getMetricsFactory()),
 	}
 
 	clientFactory := ecsclient.NewECSClientFactory(agent.credentialsCache, cfgAccessor, agent.ec2MetadataClient,
@@ -362,6 +448,14 @@ func (agent *ecsAgent) doStart(containerChangeEventStream *eventstream.EventStre
 		return exitcode
 	}
 
+	// The images of every container would be rejected if a verifier of the image trust policy is missing
+	if agent.cfg.ImageTrustPolicyFile != "" {
+		if err := imagesignature.CheckVerifiers(agent.cfg.ImageTrustPolicyFile); err != nil {
+			seelog.Criticalf("Unable to verify the image signatures: %v", err)
+			return exitcodes.ExitTerminal
+		}
+	}
+
 	// Conditionally create '/ecs' cgroup root
 	if agent.cfg.TaskCPUMemLimit.Enabled() {
 		if err := agent.cgroupInit(); err != nil {
@@ -369,6 +463,7 @@ func (agent *ecsAgent) doStart(containerChangeEventStream *eventstream.EventStre
 			return exitcodes.ExitTerminal
 		}
 	}
+	agent.secretFilesInit()
 	hostResources, err := client.GetHostResources()
 	if err != nil {
 		seelog.Critical("Unable to fetch host resources")
@@ -396,6 +491,20 @@ func (agent *ecsAgent) doStart(containerChangeEventStream *eventstream.EventStre
 		StringSetValue: gpuIDs,
 	}
 
+	cdiDevices := []string{}
+	if agent.cfg.CDISupportEnabled {
+		if err := agent.initializeCDIDeviceManager(); err != nil {
+			seelog.Criticalf("Could not initialize CDI device manager: %v", err)
+			return exitcodes.ExitError
+		}
+		cdiDevices = append(cdiDevices, agent.getCDIDevices()...)
+	}
+	hostResources["CDI"] = types.Resource{
+		Name:           utils.Strptr("CDI"),
+		Type:           utils.Strptr("STRINGSET"),
+		StringSetValue: cdiDevices,
+	}
+
 	// Create the task engine
 	taskEngine, currentEC2InstanceID, err := agent.newTaskEngine(
 		containerChangeEventStream, credentialsManager, state, imageManager, hostResources, execCmdMgr,
@@ -408,6 +517,13 @@ func (agent *ecsAgent) doStart(containerChangeEventStream *eventstream.EventStre
 	// Start termination handler in goroutine
 	go agent.terminationHandler(state, agent.dataClient, taskEngine, agent.cancel)
 
+	// Start pre-warming images before waiting for the instance to go in service, so that the images are
+	// already pulled when warm pool instances are placed in service
+	agent.imagePrewarmer = engine.NewImagePrewarmer(agent.ctx, agent.cfg, agent.dockerClient, imageManager)
+	if len(agent.cfg.ImagePrewarmList) > 0 {
+		agent.imagePrewarmer.Prewarm(agent.cfg.ImagePrewarmList)
+	}
+
 	// If part of ASG, wait until instance is being set up to go in service before registering with cluster
 	if agent.cfg.WarmPoolsSupport.Enabled() {
 		err := agent.waitUntilInstanceInService(asgLifecyclePollWait, asgLifecyclePollMax)
@@ -498,9 +614,26 @@ func (agent *ecsAgent) doStart(containerChangeEventStream *eventstream.EventStre
 		return exitcodes.ExitError
 	}
 
+	// The log messages of the routines started from now on carry the cluster and the container instance
+	agent.ctx = logger.ContextWithFields(agent.ctx, logger.Fields{
+		field.Cluster:              agent.cfg.Cluster,
+		field.ContainerInstanceARN: agent.containerInstanceARN,
+	})
+
 	// Load Managed Daemon images asynchronously
 	agent.loadManagedDaemonImagesAsync(imageManager)
 
+	// Now that the container instance is registered, it can be drained on termination, and set back to ACTIVE
+	// if it was drained when the agent was last terminated
+	if agent.instanceDrainer != nil {
+		agent.instanceDrainer.setContainerInstance(client, agent.containerInstanceARN)
+		if err := agent.instanceDrainer.reactivate(); err != nil {
+			logger.Error("Unable to set the drained container instance back to ACTIVE", logger.Fields{
+				field.Error: err,
+			})
+		}
+	}
+
 	scManager := agent.serviceconnectManager
 	scManager.SetECSClient(client, agent.containerInstanceARN)
 	if loaded, _ := scManager.IsLoaded(agent.dockerClient); loaded {
@@ -976,24 +1109,54 @@ func (agent *ecsAgent) startAsyncRoutines(
 		go imdsRefresher.Start()
 	}
 
-	// Agent introspection api
-	go handlers.ServeIntrospectionHTTPEndpoint(agent.ctx, &agent.containerInstanceARN, taskEngine, agent.cfg)
-
 	telemetryMessages := make(chan ecstcs.TelemetryMessage, telemetryChannelDefaultBufferSize)
 	healthMessages := make(chan ecstcs.HealthMessage, telemetryChannelDefaultBufferSize)
 
 	statsEngine := stats.NewDockerStatsEngine(agent.cfg, agent.dockerClient, containerChangeEventStream, telemetryMessages, healthMessages, agent.dataClient)
 
-	// Start serving the endpoint to fetch IAM Role credentials and other task metadata
+	availabilityZone, availabilityZoneID := agent.availabilityZone, agent.availabilityZoneID
 	if agent.cfg.TaskMetadataAZDisabled {
 		// send empty availability zone
-		go handlers.ServeTaskHTTPEndpoint(agent.ctx, credentialsManager, state, client, agent.containerInstanceARN, agent.cfg, statsEngine, "", "", agent.vpc)
-	} else {
-		go handlers.ServeTaskHTTPEndpoint(agent.ctx, credentialsManager, state, client, agent.containerInstanceARN, agent.cfg, statsEngine, agent.availabilityZone, agent.availabilityZoneID, agent.vpc)
+		availabilityZone, availabilityZoneID = "", ""
 	}
+	// The fault handler is shared by the task metadata endpoint, which injects the faults, and the introspection
+	// endpoint, which lists them
+	faultHandler := handlers.NewFaultHandler(state, client, agent.containerInstanceARN, agent.cfg, statsEngine,
+		availabilityZone, availabilityZoneID, agent.vpc, agent.getMetricsFactory(), agent.dataClient)
+
+	// Agent introspection api
+	supportBundleCollector := supportbundle.NewCollector(agent.cfg, state, agent.dockerClient, doctor)
+	go handlers.ServeIntrospectionHTTPEndpoint(agent.ctx, &agent.containerInstanceARN, taskEngine, agent.cfg,
+		agent.getMetricsFactory(), faultHandler, agent.imagePrewarmer, supportBundleCollector)
+
+	tmdsRateLimiter := tmds.NewRateLimiter(float64(agent.cfg.TaskMetadataSteadyStateRate), agent.cfg.TaskMetadataBurstRate)
 
-	// Start sending events to the backend
-	go eventhandler.HandleEngineEvents(agent.ctx, taskEngine, client, taskHandler, attachmentEventHandler)
+	// Apply the reloadable configuration changes when the agent receives SIGHUP
+	reloader := newConfigReloader(agent.loadedCfg, func() (*config.Config, error) {
+		return config.NewConfig(agent.ec2MetadataClient)
+	}, imageManager, statsEngine, tmdsRateLimiter)
+	sighandlers.StartReloadHandler(agent.ctx, reloader.reload)
+
+	// Start serving the endpoint to fetch IAM Role credentials and other task metadata
+	go handlers.ServeTaskHTTPEndpoint(agent.ctx, credentialsManager, state, client, agent.containerInstanceARN, agent.cfg, statsEngine, availabilityZone, availabilityZoneID, agent.vpc,
+		agent.getMetricsFactory(), taskEngine, faultHandler, tmdsRateLimiter)
+
+	// Start sending events to the backend, and to the event sink if one is configured
+	var eventSink *eventhandler.EventSink
+	if agent.cfg.EventSinkEndpoint != "" {
+		var err error
+		eventSink, err = eventhandler.NewEventSink(agent.ctx, agent.cfg.EventSinkEndpoint, agent.cfg.EventSinkQueueSize)
+		if err != nil {
+			logger.Error("Unable to create the event sink, state change events will only be sent to ECS", logger.Fields{
+				field.Error: err,
+			})
+		} else {
+			logger.Info("Delivering state change events to the event sink", logger.Fields{
+				"endpoint": agent.cfg.EventSinkEndpoint,
+			})
+		}
+	}
+	go eventhandler.HandleEngineEvents(agent.ctx, taskEngine, client, taskHandler, attachmentEventHandler, eventSink)
 
 	err := statsEngine.MustInit(agent.ctx, taskEngine, agent.cfg.Cluster, agent.containerInstanceARN)
 	if err != nil {
@@ -1003,7 +1166,7 @@ func (agent *ecsAgent) startAsyncRoutines(
 	go statsEngine.StartMetricsPublish()
 
 	session, err := reporter.NewDockerTelemetrySession(agent.containerInstanceARN, agent.credentialsCache, agent.cfg, deregisterInstanceEventStream,
-		client, taskEngine, telemetryMessages, healthMessages, doctor)
+		client, taskEngine, telemetryMessages, healthMessages, doctor, agent.getMetricsFactory())
 	if err != nil {
 		seelog.Warnf("Error creating telemetry session: %v", err)
 		return
@@ -1132,7 +1295,7 @@ func (agent *ecsAgent) startACSSession(
 		agent.credentialsCache,
 		inactiveInstanceCB,
 		acsclient.NewACSClientFactory(),
-		metricsfactory.NewNopEntryFactory(),
+		agent.getMetricsFactory(),
 		version.Version,
 		version.GitHashString(),
 		dockerVersion,
@@ -19,7 +19,10 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 
+	"github.com/aws/amazon-ecs-agent/agent/cdi"
 	"github.com/aws/amazon-ecs-agent/agent/config"
 	"github.com/aws/amazon-ecs-agent/agent/dockerclient"
 	dm "github.com/aws/amazon-ecs-agent/agent/engine/daemonmanager"
@@ -83,6 +86,7 @@ const (
 	capabilityExternal                                     = "external"
 	capabilityServiceConnect                               = "service-connect-v1"
 	capabilityGpuDriverVersion                             = "gpu-driver-version"
+	capabilityCDIDeviceInfix                               = "cdi-device."
 	capabilityEBSTaskAttach                                = "storage.ebs-task-volume-attach"
 	capabilityEBSTANonRootUser                             = "storage.ebsta-non-root-user"
 	capabilityContainerRestartPolicy                       = "container-restart-policy"
@@ -218,6 +222,7 @@ var (
 //	ecs.capability.network.container-port-range
 //	ecs.capability.container-restart-policy
 //	ecs.capability.fault-injection
+//	ecs.capability.cdi-device.<kind>
 func (agent *ecsAgent) capabilities() ([]types.Attribute, error) {
 	var capabilities []types.Attribute
 
@@ -276,6 +281,10 @@ func (agent *ecsAgent) capabilities() ([]types.Attribute, error) {
 		capabilities = agent.appendNvidiaDriverVersionAttribute(capabilities)
 	}
 
+	if agent.cfg.CDISupportEnabled {
+		capabilities = agent.appendCDIDeviceKindAttributes(capabilities)
+	}
+
 	// ecs agent version 1.22.0 supports sharing PID namespaces and IPC resource namespaces
 	// with host EC2 instance and among containers within the task
 	capabilities = agent.appendPIDAndIPCNamespaceSharingCapabilities(capabilities)
@@ -576,6 +585,35 @@ func (agent *ecsAgent) appendEBSTaskAttachCapabilities(capabilities []types.Attr
 	return capabilities
 }
 
+// appendCDIDeviceKindAttributes advertises each kind of CDI devices of the host with an attribute named after the
+// kind, e.g. ecs.capability.cdi-device.vendor.com/fpga, whose value is the number of devices of the kind. Tasks
+// requesting devices can then be placed on the instances having them with placement constraints.
+func (agent *ecsAgent) appendCDIDeviceKindAttributes(capabilities []types.Attribute) []types.Attribute {
+	devicesByKind := agent.getCDIDevicesByKind()
+	kinds := make([]string, 0, len(devicesByKind))
+	for kind := range devicesByKind {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		capabilities = append(capabilities, types.Attribute{
+			Name:  aws.String(attributePrefix + capabilityCDIDeviceInfix + kind),
+			Value: aws.String(strconv.Itoa(len(devicesByKind[kind]))),
+		})
+	}
+	return capabilities
+}
+
+// getCDIDevicesByKind returns the CDI devices of the host grouped by kind
+func (agent *ecsAgent) getCDIDevicesByKind() map[string][]string {
+	devicesByKind := make(map[string][]string)
+	for _, device := range agent.getCDIDevices() {
+		kind := cdi.Kind(device)
+		devicesByKind[kind] = append(devicesByKind[kind], device)
+	}
+	return devicesByKind
+}
+
 func (agent *ecsAgent) appendFaultInjectionCapabilities(capabilities []types.Attribute) []types.Attribute {
 	// Check if the agent is running in EXTERNAL launch type
 	if agent.cfg.External.Enabled() {
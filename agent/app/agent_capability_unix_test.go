@@ -28,6 +28,7 @@ import (
 	"testing"
 
 	app_mocks "github.com/aws/amazon-ecs-agent/agent/app/mocks"
+	mock_cdi "github.com/aws/amazon-ecs-agent/agent/cdi/mocks"
 	"github.com/aws/amazon-ecs-agent/agent/config"
 	"github.com/aws/amazon-ecs-agent/agent/dockerclient"
 	mock_dockerapi "github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi/mocks"
@@ -266,6 +267,36 @@ func TestNvidiaDriverCapabilitiesUnix(t *testing.T) {
 	}
 }
 
+func TestCDIDeviceKindAttributesUnix(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deviceManager := mock_cdi.NewMockDeviceManager(ctrl)
+	deviceManager.EXPECT().GetDevices().Return([]string{
+		"vendor.com/nic=nic0",
+		"vendor.com/fpga=0",
+		"vendor.com/fpga=1",
+	})
+	agent := &ecsAgent{
+		cfg: &config.Config{CDISupportEnabled: true},
+		resourceFields: &taskresource.ResourceFields{
+			CDIDeviceManager: deviceManager,
+		},
+	}
+
+	// The kinds are advertised as attributes sorted by kind, with the number of devices of the kind
+	assert.Equal(t, []types.Attribute{
+		{
+			Name:  aws.String(attributePrefix + capabilityCDIDeviceInfix + "vendor.com/fpga"),
+			Value: aws.String("2"),
+		},
+		{
+			Name:  aws.String(attributePrefix + capabilityCDIDeviceInfix + "vendor.com/nic"),
+			Value: aws.String("1"),
+		},
+	}, agent.appendCDIDeviceKindAttributes(nil))
+}
+
 func TestEmptyNvidiaDriverCapabilitiesUnix(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -21,6 +21,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
 	"sort"
 	"testing"
 	"time"
@@ -198,6 +200,39 @@ func TestDoStartMinimumSupportedDockerVersionError(t *testing.T) {
 	assert.Equal(t, exitcodes.ExitError, exitCode)
 }
 
+func TestDoStartImageSignatureVerifierMissing(t *testing.T) {
+	ctrl, credentialsManager, state, imageManager, client,
+		dockerClient, stateManagerFactory, saveableOptionFactory, execCmdMgr, _ := setup(t)
+	defer ctrl.Finish()
+
+	gomock.InOrder(
+		dockerClient.EXPECT().SupportedVersions().Return(apiVersions),
+	)
+
+	// The cosign binary isn't in the PATH
+	t.Setenv("PATH", t.TempDir())
+	policyFile := filepath.Join(t.TempDir(), "trust-policy.json")
+	require.NoError(t, os.WriteFile(policyFile, []byte(`{"policies": [{"repositories": ["*"], "verifier": "cosign", `+
+		`"publicKeys": ["/etc/ecs/cosign.pub"]}]}`), 0600))
+
+	cfg := getTestConfig()
+	cfg.ImageTrustPolicyFile = policyFile
+	ctx, cancel := context.WithCancel(context.TODO())
+	// Cancel the context to cancel async routines
+	defer cancel()
+	agent := &ecsAgent{
+		ctx:                   ctx,
+		cfg:                   &cfg,
+		dockerClient:          dockerClient,
+		stateManagerFactory:   stateManagerFactory,
+		saveableOptionFactory: saveableOptionFactory,
+	}
+
+	exitCode := agent.doStart(eventstream.NewEventStream("events", ctx),
+		credentialsManager, state, imageManager, client, execCmdMgr)
+	assert.Equal(t, exitcodes.ExitTerminal, exitCode)
+}
+
 func TestDoStartNewTaskEngineError(t *testing.T) {
 	ctrl, credentialsManager, _, imageManager, client,
 		dockerClient, stateManagerFactory, saveableOptionFactory, execCmdMgr, _ := setup(t)
@@ -2166,3 +2201,13 @@ func TestGetIMDSCredentialRefresher(t *testing.T) {
 		})
 	}
 }
+
+func TestSecretFilesInitDisablesSecretFilesWhenDirIsUnusable(t *testing.T) {
+	cfg := getTestConfig()
+	cfg.SecretFilesEnabled = config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled}
+	cfg.SecretFilesDir = filepath.Join(t.TempDir(), "missing")
+	agent := &ecsAgent{cfg: &cfg}
+
+	agent.secretFilesInit()
+	assert.False(t, cfg.SecretFilesEnabled.Enabled(), "secret files should be disabled when their directory is unusable")
+}
@@ -20,6 +20,7 @@ import (
 	"os"
 
 	asmfactory "github.com/aws/amazon-ecs-agent/agent/asm/factory"
+	"github.com/aws/amazon-ecs-agent/agent/cdi"
 	"github.com/aws/amazon-ecs-agent/agent/config"
 	"github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi"
 	ebs "github.com/aws/amazon-ecs-agent/agent/ebs"
@@ -176,11 +177,13 @@ func (agent *ecsAgent) initializeResourceFields(credentialsManager credentials.M
 			SSMClientCreator:   ssmfactory.NewSSMClientCreator(),
 			S3ClientCreator:    s3factory.NewS3ClientCreator(),
 			CredentialsManager: credentialsManager,
+			MetricsFactory:     agent.getMetricsFactory(),
 			EC2InstanceID:      ec2InstanceID,
 		},
 		Ctx:              agent.ctx,
 		DockerClient:     agent.dockerClient,
 		NvidiaGPUManager: gpu.NewNvidiaGPUManager(),
+		CDIDeviceManager: cdi.NewDeviceManager(cdi.DefaultSpecDirs),
 	}
 }
 
@@ -215,6 +218,22 @@ func (agent *ecsAgent) getPlatformDevices() []types.PlatformDevice {
 	return nil
 }
 
+func (agent *ecsAgent) initializeCDIDeviceManager() error {
+	if agent.resourceFields != nil && agent.resourceFields.CDIDeviceManager != nil {
+		return agent.resourceFields.CDIDeviceManager.Initialize()
+	}
+	return nil
+}
+
+func (agent *ecsAgent) getCDIDevices() []string {
+	if agent.cfg.CDISupportEnabled {
+		if agent.resourceFields != nil && agent.resourceFields.CDIDeviceManager != nil {
+			return agent.resourceFields.CDIDeviceManager.GetDevices()
+		}
+	}
+	return nil
+}
+
 func (agent *ecsAgent) loadPauseContainer() error {
 	// Load the pause container's image from the 'disk'
 	_, err := agent.pauseLoader.LoadImage(agent.ctx, agent.cfg, agent.dockerClient)
@@ -51,6 +51,14 @@ func (agent *ecsAgent) getPlatformDevices() []types.PlatformDevice {
 	return nil
 }
 
+func (agent *ecsAgent) initializeCDIDeviceManager() error {
+	return nil
+}
+
+func (agent *ecsAgent) getCDIDevices() []string {
+	return nil
+}
+
 func (agent *ecsAgent) loadPauseContainer() error {
 	return nil
 }
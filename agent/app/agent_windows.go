@@ -323,6 +323,7 @@ func (agent *ecsAgent) initializeResourceFields(credentialsManager credentials.M
 			FSxClientCreator:   fsxfactory.NewFSxClientCreator(),
 			S3ClientCreator:    s3factory.NewS3ClientCreator(),
 			CredentialsManager: credentialsManager,
+			MetricsFactory:     agent.getMetricsFactory(),
 		},
 		Ctx:          agent.ctx,
 		DockerClient: agent.dockerClient,
@@ -342,6 +343,14 @@ func (agent *ecsAgent) getPlatformDevices() []types.PlatformDevice {
 	return nil
 }
 
+func (agent *ecsAgent) initializeCDIDeviceManager() error {
+	return nil
+}
+
+func (agent *ecsAgent) getCDIDevices() []string {
+	return nil
+}
+
 func (agent *ecsAgent) loadPauseContainer() error {
 	// The pause image would be cached in th ECS-Optimized Windows AMI's and will be available. We will throw an error if the image is not loaded.
 	// If the agent is run on non-supported instances then pause image has to be loaded manually by the client.
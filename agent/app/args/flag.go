@@ -15,6 +15,7 @@ package args
 
 import (
 	"flag"
+	"time"
 )
 
 const (
@@ -28,6 +29,10 @@ const (
 	blacholeEC2MetadataUsage = "Blackhole the EC2 Metadata requests. Setting this option can cause the ECS Agent to fail to work properly.  We do not recommend setting this option"
 	windowsServiceUsage      = "Run the ECS agent as a Windows Service"
 	healthcheckServiceUsage  = "Run the agent healthcheck"
+	validateConfigUsage      = "Load and validate the agent configuration, print a report of the effective configuration and exit"
+	faultStressUsage         = "Internal use only. Consume the cpu-stress or memory-pressure resource of the cgroup the agent is moved into"
+	faultStressAmountUsage   = "Internal use only. Millicores of CPU or bytes of memory consumed by the fault stress"
+	faultStressDurationUsage = "Internal use only. Duration after which the fault stress stops"
 
 	versionFlagName              = "version"
 	logLevelFlagName             = "loglevel"
@@ -39,6 +44,12 @@ const (
 	blackholeEC2MetadataFlagName = "blackhole-ec2-metadata"
 	windowsServiceFlagName       = "windows-service"
 	healthCheckFlagName          = "healthcheck"
+	validateConfigFlagName       = "validate-config"
+	// FaultStressFlagName, FaultStressAmountFlagName and FaultStressDurationFlagName are used by the agent to
+	// start itself in a task cgroup for CPU stress and memory pressure faults.
+	FaultStressFlagName         = "fault-stress"
+	FaultStressAmountFlagName   = "fault-stress-amount"
+	FaultStressDurationFlagName = "fault-stress-duration"
 )
 
 // Args wraps various ECS Agent arguments
@@ -65,6 +76,15 @@ type Args struct {
 	WindowsService *bool
 	// Healthcheck indicates that agent should run healthcheck
 	Healthcheck *bool
+	// ValidateConfig indicates that the agent should validate its configuration and exit
+	ValidateConfig *bool
+	// FaultStress is the resource the agent should consume instead of running, either cpu-stress or
+	// memory-pressure
+	FaultStress *string
+	// FaultStressAmount is the amount of the resource to consume
+	FaultStressAmount *uint64
+	// FaultStressDuration is how long the resource is consumed for
+	FaultStressDuration *time.Duration
 }
 
 // New creates a new Args object from the argument list
@@ -82,6 +102,10 @@ func New(arguments []string) (*Args, error) {
 		ECSAttributes:        flagset.Bool(ecsAttributesFlagName, false, ecsAttributesUsage),
 		WindowsService:       flagset.Bool(windowsServiceFlagName, false, windowsServiceUsage),
 		Healthcheck:          flagset.Bool(healthCheckFlagName, false, healthcheckServiceUsage),
+		ValidateConfig:       flagset.Bool(validateConfigFlagName, false, validateConfigUsage),
+		FaultStress:          flagset.String(FaultStressFlagName, "", faultStressUsage),
+		FaultStressAmount:    flagset.Uint64(FaultStressAmountFlagName, 0, faultStressAmountUsage),
+		FaultStressDuration:  flagset.Duration(FaultStressDurationFlagName, 0, faultStressDurationUsage),
 	}
 
 	err := flagset.Parse(arguments)
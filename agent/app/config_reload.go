@@ -0,0 +1,107 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package app
+
+import (
+	"os"
+	"sync"
+
+	"github.com/aws/amazon-ecs-agent/agent/config"
+	"github.com/aws/amazon-ecs-agent/agent/engine"
+	"github.com/aws/amazon-ecs-agent/agent/stats"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds"
+)
+
+// configReloader loads the configuration again and applies the changes of the reloadable keys
+// to the logger, the image manager, the stats engine and the TMDS rate limiter. The changes of the
+// other keys are logged and ignored. The configuration shared by the rest of the agent isn't
+// modified; the reloader keeps its own copy with the changes applied, to compare the next reloaded
+// configuration against.
+type configReloader struct {
+	cfg             *config.Config
+	loadConfig      func() (*config.Config, error)
+	imageManager    engine.ImageManager
+	statsEngine     *stats.DockerStatsEngine
+	tmdsRateLimiter *tmds.RateLimiter
+	lock            sync.Mutex
+}
+
+// newConfigReloader returns a configReloader applying the changes made to cfg
+func newConfigReloader(cfg *config.Config, loadConfig func() (*config.Config, error),
+	imageManager engine.ImageManager, statsEngine *stats.DockerStatsEngine,
+	tmdsRateLimiter *tmds.RateLimiter) *configReloader {
+	return &configReloader{
+		cfg:             cfg,
+		loadConfig:      loadConfig,
+		imageManager:    imageManager,
+		statsEngine:     statsEngine,
+		tmdsRateLimiter: tmdsRateLimiter,
+	}
+}
+
+// reload loads the configuration and applies the changes of the reloadable keys
+func (reloader *configReloader) reload() {
+	reloader.lock.Lock()
+	defer reloader.lock.Unlock()
+
+	reloaded, err := reloader.loadConfig()
+	if err != nil {
+		logger.Error("Unable to reload the configuration, keeping the current configuration", logger.Fields{
+			field.Error: err,
+		})
+		return
+	}
+	applied, changes := config.ReloadConfig(reloader.cfg, reloaded)
+	if len(changes) == 0 {
+		logger.Info("Configuration unchanged")
+		return
+	}
+	for _, change := range changes {
+		fields := logger.Fields{
+			"key":      change.Name,
+			"oldValue": change.OldValue,
+			"newValue": change.NewValue,
+		}
+		if change.Reloadable {
+			logger.Info("Applying configuration change", fields)
+		} else {
+			logger.Warn("Ignoring configuration change which requires restarting the agent", fields)
+		}
+	}
+	reloader.apply(reloader.cfg, applied)
+	reloader.cfg = applied
+}
+
+// apply applies the reloadable keys of the configuration to the running components
+func (reloader *configReloader) apply(previous, cfg *config.Config) {
+	if cfg.LogLevel != previous.LogLevel {
+		level := cfg.LogLevel
+		if level == "" {
+			// Go back to the level set by the environment
+			level = os.Getenv(logger.LOGLEVEL_ENV_VAR)
+		}
+		if level == "" {
+			level = logger.DEFAULT_LOGLEVEL
+		}
+		logger.SetLogLevel(level)
+	}
+	reloader.imageManager.UpdateCleanupConfig(cfg)
+	reloader.statsEngine.UpdatePollingMetricsConfig(cfg)
+	if cfg.TaskMetadataSteadyStateRate != previous.TaskMetadataSteadyStateRate ||
+		cfg.TaskMetadataBurstRate != previous.TaskMetadataBurstRate {
+		reloader.tmdsRateLimiter.SetRates(float64(cfg.TaskMetadataSteadyStateRate), cfg.TaskMetadataBurstRate)
+	}
+}
@@ -0,0 +1,113 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package app
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ecs-agent/agent/config"
+	mock_engine "github.com/aws/amazon-ecs-agent/agent/engine/mocks"
+	"github.com/aws/amazon-ecs-agent/agent/stats"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/ipcompatibility"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigReloaderReload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := config.DefaultConfig(ipcompatibility.NewIPv4OnlyCompatibility())
+	cfg.Cluster = "cluster"
+	reloaded := cfg
+	reloaded.Cluster = "other-cluster"
+	reloaded.ImageCleanupInterval = time.Hour
+	reloaded.TaskMetadataSteadyStateRate = 10
+	reloaded.TaskMetadataBurstRate = 20
+	reloaded.LogLevel = "debug"
+	reloaded.PollingMetricsWaitDuration = 20 * time.Second
+
+	imageManager := mock_engine.NewMockImageManager(ctrl)
+	imageManager.EXPECT().UpdateCleanupConfig(gomock.Any()).Do(func(applied *config.Config) {
+		assert.Equal(t, time.Hour, applied.ImageCleanupInterval)
+	})
+	rateLimiter := tmds.NewRateLimiter(float64(cfg.TaskMetadataSteadyStateRate), cfg.TaskMetadataBurstRate)
+	reloader := newConfigReloader(&cfg, func() (*config.Config, error) {
+		return &reloaded, nil
+	}, imageManager, stats.NewDockerStatsEngine(&cfg, nil, nil, nil, nil, nil), rateLimiter)
+
+	reloader.reload()
+	assert.Equal(t, "cluster", reloader.cfg.Cluster, "changes which require a restart should be ignored")
+	assert.Equal(t, config.DefaultImageCleanupTimeInterval, cfg.ImageCleanupInterval,
+		"the configuration shared by the agent should not be modified")
+	assert.Equal(t, time.Hour, reloader.cfg.ImageCleanupInterval)
+	assert.Equal(t, "debug", reloader.cfg.LogLevel)
+	assert.Equal(t, 20*time.Second, reloader.cfg.PollingMetricsWaitDuration)
+	steadyStateRate, burstRate := rateLimiter.Rates()
+	assert.Equal(t, float64(10), steadyStateRate)
+	assert.Equal(t, 20, burstRate)
+
+	// Nothing is applied when the configuration is unchanged
+	reloaded.Cluster = "cluster"
+	reloader.reload()
+	logger.SetLogLevel(logger.DEFAULT_LOGLEVEL)
+}
+
+func TestConfigReloaderReloadError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := config.DefaultConfig(ipcompatibility.NewIPv4OnlyCompatibility())
+	imageManager := mock_engine.NewMockImageManager(ctrl)
+	reloader := newConfigReloader(&cfg, func() (*config.Config, error) {
+		return nil, errors.New("invalid config")
+	}, imageManager, stats.NewDockerStatsEngine(&cfg, nil, nil, nil, nil, nil),
+		tmds.NewRateLimiter(float64(cfg.TaskMetadataSteadyStateRate), cfg.TaskMetadataBurstRate))
+
+	reloader.reload()
+	assert.Equal(t, &cfg, reloader.cfg)
+}
+
+func TestConfigReloaderReloadAfterRuntimeChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	loaded := config.DefaultConfig(ipcompatibility.NewIPv4OnlyCompatibility())
+	cfg := loaded
+	agent := &ecsAgent{cfg: &cfg, loadedCfg: &loaded}
+	// The agent adjusts non-reloadable keys of its configuration at runtime
+	agent.cfg.Cluster = "restored-cluster"
+	agent.cfg.TaskCPUMemLimit.Value = config.ExplicitlyDisabled
+	agent.cfg.AcceptInsecureCert = true
+
+	// Nothing is applied to the image manager when the reloaded configuration is the same as the one loaded
+	imageManager := mock_engine.NewMockImageManager(ctrl)
+	reloader := newConfigReloader(agent.loadedCfg, func() (*config.Config, error) {
+		reloaded := config.DefaultConfig(ipcompatibility.NewIPv4OnlyCompatibility())
+		return &reloaded, nil
+	}, imageManager, stats.NewDockerStatsEngine(agent.cfg, nil, nil, nil, nil, nil),
+		tmds.NewRateLimiter(float64(cfg.TaskMetadataSteadyStateRate), cfg.TaskMetadataBurstRate))
+
+	reloader.reload()
+	assert.Equal(t, &loaded, reloader.cfg)
+	assert.Equal(t, "restored-cluster", agent.cfg.Cluster)
+}
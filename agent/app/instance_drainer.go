@@ -0,0 +1,102 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/aws/amazon-ecs-agent/agent/data"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/api/ecs"
+
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/cihub/seelog"
+)
+
+// instanceShutdownFileName is the name of the file that ecs-init creates in the data directory when it stops
+// the agent because the instance is shutting down
+const instanceShutdownFileName = "instance-shutdown"
+
+// instanceDrainer sets the container instance to DRAINING when the agent is terminated because the instance
+// is shutting down, and sets it back to ACTIVE when the agent starts again. The ECS client and the container
+// instance ARN are only known once the agent has registered the container instance, which can happen after
+// the termination handler is started.
+type instanceDrainer struct {
+	dataDir              string
+	dataClient           data.Client
+	client               ecs.ECSClient
+	containerInstanceARN string
+	lock                 sync.RWMutex
+}
+
+func newInstanceDrainer(dataDir string, dataClient data.Client) *instanceDrainer {
+	return &instanceDrainer{
+		dataDir:    dataDir,
+		dataClient: dataClient,
+	}
+}
+
+// setContainerInstance sets the client and the ARN of the registered container instance
+func (d *instanceDrainer) setContainerInstance(client ecs.ECSClient, containerInstanceARN string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.client = client
+	d.containerInstanceARN = containerInstanceARN
+}
+
+// shuttingDown returns whether the instance is shutting down, as opposed to the agent being restarted. Only
+// the former drains the container instance, otherwise a plain restart would leave it DRAINING.
+func (d *instanceDrainer) shuttingDown() bool {
+	_, err := os.Stat(filepath.Join(d.dataDir, instanceShutdownFileName))
+	return err == nil
+}
+
+// drain sets the container instance to DRAINING, so that ECS stops its tasks
+func (d *instanceDrainer) drain() error {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	if d.client == nil || d.containerInstanceARN == "" {
+		return errors.New("container instance is not registered")
+	}
+	seelog.Infof("Setting container instance [ARN: %s] state to DRAINING", d.containerInstanceARN)
+	if err := d.client.UpdateContainerInstancesState(d.containerInstanceARN,
+		types.ContainerInstanceStatusDraining); err != nil {
+		return err
+	}
+	// Remember the drained container instance, so that it's set back to ACTIVE when the agent starts again
+	if err := d.dataClient.SaveMetadata(data.DrainedContainerInstanceARNKey, d.containerInstanceARN); err != nil {
+		seelog.Errorf("Unable to save the drained container instance [ARN: %s]: %v", d.containerInstanceARN, err)
+	}
+	return nil
+}
+
+// reactivate sets the container instance back to ACTIVE if the agent drained it when it was last terminated.
+// The container instance isn't changed if it was drained by anyone else.
+func (d *instanceDrainer) reactivate() error {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	drainedARN, err := d.dataClient.GetMetadata(data.DrainedContainerInstanceARNKey)
+	if err != nil || drainedARN == "" || drainedARN != d.containerInstanceARN {
+		return nil
+	}
+	seelog.Infof("Setting container instance [ARN: %s] state back to ACTIVE after draining it on termination",
+		d.containerInstanceARN)
+	if err := d.client.UpdateContainerInstancesState(d.containerInstanceARN,
+		types.ContainerInstanceStatusActive); err != nil {
+		return err
+	}
+	return d.dataClient.SaveMetadata(data.DrainedContainerInstanceARNKey, "")
+}
@@ -0,0 +1,97 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/amazon-ecs-agent/agent/data"
+	mock_ecs "github.com/aws/amazon-ecs-agent/ecs-agent/api/ecs/mocks"
+
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstanceDrainer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataClient := newTestDataClient(t)
+	drainer := newInstanceDrainer(t.TempDir(), dataClient)
+	assert.Error(t, drainer.drain(), "unregistered container instance should not be drained")
+
+	client := mock_ecs.NewMockECSClient(ctrl)
+	drainer.setContainerInstance(client, containerInstanceARN)
+	gomock.InOrder(
+		client.EXPECT().UpdateContainerInstancesState(containerInstanceARN, types.ContainerInstanceStatusDraining).
+			Return(errors.New("error")),
+		client.EXPECT().UpdateContainerInstancesState(containerInstanceARN, types.ContainerInstanceStatusDraining).
+			Return(nil),
+	)
+	assert.Error(t, drainer.drain())
+	assert.NoError(t, drainer.drain())
+	drainedARN, err := dataClient.GetMetadata(data.DrainedContainerInstanceARNKey)
+	require.NoError(t, err)
+	assert.Equal(t, containerInstanceARN, drainedARN)
+}
+
+func TestInstanceDrainerShuttingDown(t *testing.T) {
+	dataDir := t.TempDir()
+	drainer := newInstanceDrainer(dataDir, data.NewNoopClient())
+	assert.False(t, drainer.shuttingDown(), "instance should not be shutting down without the shutdown file")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, instanceShutdownFileName), nil, 0644))
+	assert.True(t, drainer.shuttingDown(), "instance should be shutting down with the shutdown file")
+}
+
+func TestInstanceDrainerReactivate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataClient := newTestDataClient(t)
+	drainer := newInstanceDrainer(t.TempDir(), dataClient)
+	client := mock_ecs.NewMockECSClient(ctrl)
+	drainer.setContainerInstance(client, containerInstanceARN)
+
+	// Nothing was drained
+	assert.NoError(t, drainer.reactivate())
+
+	// Another container instance was drained
+	require.NoError(t, dataClient.SaveMetadata(data.DrainedContainerInstanceARNKey, "other-container-instance-arn"))
+	assert.NoError(t, drainer.reactivate())
+
+	require.NoError(t, dataClient.SaveMetadata(data.DrainedContainerInstanceARNKey, containerInstanceARN))
+	gomock.InOrder(
+		client.EXPECT().UpdateContainerInstancesState(containerInstanceARN, types.ContainerInstanceStatusActive).
+			Return(errors.New("error")),
+		client.EXPECT().UpdateContainerInstancesState(containerInstanceARN, types.ContainerInstanceStatusActive).
+			Return(nil),
+	)
+	assert.Error(t, drainer.reactivate())
+	assert.NoError(t, drainer.reactivate())
+	drainedARN, err := dataClient.GetMetadata(data.DrainedContainerInstanceARNKey)
+	require.NoError(t, err)
+	assert.Empty(t, drainedARN)
+
+	// The container instance is only set back to ACTIVE once
+	assert.NoError(t, drainer.reactivate())
+}
@@ -20,8 +20,10 @@ import (
 
 	"github.com/aws/amazon-ecs-agent/agent/app/args"
 	"github.com/aws/amazon-ecs-agent/agent/sighandlers/exitcodes"
+	"github.com/aws/amazon-ecs-agent/agent/taskresource/cgroup"
 	"github.com/aws/amazon-ecs-agent/agent/version"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	log "github.com/cihub/seelog"
@@ -31,11 +33,26 @@ const (
 	ECS_AGENT_HEALTHCHECK_HOST_ENV_VAR = "ECS_AGENT_HEALTHCHECK_HOST"
 )
 
+// logWarningsToStderr replaces the logger with one writing warnings and errors to stderr, for the
+// commands which write their output to stdout
+func logWarningsToStderr() {
+	stderrLogger, err := log.LoggerFromWriterWithMinLevelAndFormat(os.Stderr, log.WarnLvl, "%EcsAgentLogfmt")
+	if err != nil {
+		return
+	}
+	log.ReplaceLogger(stderrLogger)
+}
+
 // Run runs the ECS Agent App. It returns an exit code, which is used by
 // main() to set the status code for the program
 func Run(arguments []string) int {
 	defer log.Flush()
 
+	if len(arguments) > 0 && arguments[0] == stateToolCommand {
+		// The state tool has its own subcommands and flags
+		return runStateTool(arguments[1:], os.Stdout)
+	}
+
 	parsedArgs, err := args.New(arguments)
 	if err != nil {
 		return exitcodes.ExitTerminal
@@ -58,6 +75,19 @@ func Run(arguments []string) int {
 		}
 		healthcheckUrl := fmt.Sprintf("http://%s:51678/v1/metadata", localhost)
 		return runHealthcheck(healthcheckUrl, time.Second*25)
+	} else if *parsedArgs.ValidateConfig {
+		// Validate the configuration without starting the agent
+		return validateConfig(aws.ToBool(parsedArgs.BlackholeEC2Metadata), os.Stdout)
+	} else if *parsedArgs.FaultStress != "" {
+		// The agent was started in a task cgroup to consume its resources for a fault
+		err := cgroup.RunStress(*parsedArgs.FaultStress, *parsedArgs.FaultStressAmount, *parsedArgs.FaultStressDuration)
+		if err != nil {
+			logger.Error("Unable to run fault stress", logger.Fields{
+				field.Error: err,
+			})
+			return exitcodes.ExitError
+		}
+		return exitcodes.ExitSuccess
 	}
 
 	if *parsedArgs.LogLevel != "" {
@@ -80,6 +110,12 @@ func Run(arguments []string) int {
 		return exitcodes.ExitError
 	}
 
+	if logLevel := agent.getConfig().LogLevel; logLevel != "" && *parsedArgs.LogLevel == "" &&
+		*parsedArgs.DriverLogLevel == "" && *parsedArgs.InstanceLogLevel == "" {
+		// The log level set in the config file takes precedence over the environment
+		logger.SetLogLevel(logLevel)
+	}
+
 	if agent.getConfig().EnableRuntimeStats.Enabled() {
 		defer logger.StartRuntimeStatsLogger(agent.getConfig().RuntimeStatsLogFile)()
 	}
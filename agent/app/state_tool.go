@@ -0,0 +1,144 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package app
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aws/amazon-ecs-agent/agent/config"
+	"github.com/aws/amazon-ecs-agent/agent/data"
+	"github.com/aws/amazon-ecs-agent/agent/sighandlers/exitcodes"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/ipcompatibility"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+)
+
+const (
+	// stateToolCommand is the agent subcommand inspecting and repairing the agent database
+	stateToolCommand = "state"
+
+	stateToolDump      = "dump"
+	stateToolSummary   = "summary"
+	stateToolTransform = "transform"
+	stateToolOrphans   = "orphans"
+	stateToolRemove    = "remove"
+
+	stateToolDataDirUsage = "Directory holding the agent database. Defaults to ECS_DATADIR, or the default agent data directory"
+)
+
+// stateToolUsage returns the usage of the state tool
+func stateToolUsage() string {
+	return fmt.Sprintf(`Usage: agent %[1]s [-data-dir <dir>] <command> [arguments]
+
+Inspects and repairs the agent database. The agent must be stopped.
+
+Commands:
+  %[2]s [bucket...]       Print the records of the buckets as JSON. Buckets: %[7]s
+  %[3]s                Print a summary of the tasks and containers
+  %[4]s              Run the task model transformations the agent runs after an upgrade, without saving them
+  %[5]s                List the container, attachment and fault records of tasks missing from the database
+  %[6]s <bucket> <key>  Remove a record
+`, stateToolCommand, stateToolDump, stateToolSummary, stateToolTransform, stateToolOrphans, stateToolRemove,
+		strings.Join(data.Buckets(), ", "))
+}
+
+// runStateTool runs a state tool command and writes its JSON output
+func runStateTool(arguments []string, w io.Writer) int {
+	// Keep the logs out of stdout so that the output can be parsed
+	logWarningsToStderr()
+
+	flagset := flag.NewFlagSet("Amazon ECS Agent state tool", flag.ContinueOnError)
+	flagset.Usage = func() {
+		fmt.Fprint(flagset.Output(), stateToolUsage())
+		flagset.PrintDefaults()
+	}
+	dataDir := flagset.String("data-dir", defaultStateToolDataDir(), stateToolDataDirUsage)
+	if err := flagset.Parse(arguments); err != nil {
+		return exitcodes.ExitError
+	}
+	commandArgs := flagset.Args()
+	if len(commandArgs) == 0 {
+		flagset.Usage()
+		return exitcodes.ExitError
+	}
+
+	command, commandArgs := commandArgs[0], commandArgs[1:]
+	var readOnly bool
+	switch command {
+	case stateToolDump, stateToolSummary, stateToolTransform, stateToolOrphans:
+		readOnly = true
+	case stateToolRemove:
+		if len(commandArgs) != 2 {
+			flagset.Usage()
+			return exitcodes.ExitError
+		}
+	default:
+		flagset.Usage()
+		return exitcodes.ExitError
+	}
+
+	inspector, err := data.NewInspector(*dataDir, readOnly)
+	if err != nil {
+		logger.Error("Unable to open the agent database", logger.Fields{
+			field.Error: err,
+		})
+		return exitcodes.ExitError
+	}
+	defer inspector.Close()
+
+	var output interface{}
+	switch command {
+	case stateToolDump:
+		output, err = inspector.Dump(commandArgs)
+	case stateToolSummary:
+		output, err = inspector.Summary()
+	case stateToolTransform:
+		output, err = inspector.TransformDryRun()
+	case stateToolOrphans:
+		output, err = inspector.Orphans()
+	case stateToolRemove:
+		err = inspector.Remove(commandArgs[0], commandArgs[1])
+		output = map[string]string{"bucket": commandArgs[0], "removed": commandArgs[1]}
+	}
+	if err != nil {
+		logger.Error("State tool command failed", logger.Fields{
+			"command":   command,
+			field.Error: err,
+		})
+		return exitcodes.ExitError
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(output); err != nil {
+		logger.Error("Unable to write the state tool output", logger.Fields{
+			field.Error: err,
+		})
+		return exitcodes.ExitError
+	}
+	return exitcodes.ExitSuccess
+}
+
+// defaultStateToolDataDir returns the data directory the agent uses by default
+func defaultStateToolDataDir() string {
+	if dataDir := os.Getenv("ECS_DATADIR"); dataDir != "" {
+		return dataDir
+	}
+	return config.DefaultConfig(ipcompatibility.NewIPv4OnlyCompatibility()).DataDir
+}
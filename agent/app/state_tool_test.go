@@ -0,0 +1,65 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
+	"github.com/aws/amazon-ecs-agent/agent/data"
+	"github.com/aws/amazon-ecs-agent/agent/sighandlers/exitcodes"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunStateTool(t *testing.T) {
+	dataDir := t.TempDir()
+	dataClient, err := data.NewWithSetup(dataDir)
+	require.NoError(t, err)
+	require.NoError(t, dataClient.SaveTask(&apitask.Task{
+		Arn: "arn:aws:ecs:us-west-2:1234567890:task/test-cluster/abc",
+	}))
+	require.NoError(t, dataClient.Close())
+
+	var out bytes.Buffer
+	require.Equal(t, exitcodes.ExitSuccess, runStateTool([]string{"-data-dir", dataDir, "summary"}, &out))
+	summary := data.StateSummary{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &summary))
+	require.Len(t, summary.Tasks, 1)
+	assert.Equal(t, "abc", summary.Tasks[0].ID)
+
+	out.Reset()
+	assert.Equal(t, exitcodes.ExitSuccess, runStateTool([]string{"-data-dir", dataDir, "remove", "tasks", "abc"}, &out))
+	assert.Equal(t, exitcodes.ExitError, runStateTool([]string{"-data-dir", dataDir, "remove", "tasks", "abc"}, &out))
+}
+
+func TestRunStateToolInvalidArguments(t *testing.T) {
+	dataDir := t.TempDir()
+	for _, arguments := range [][]string{
+		{"-data-dir", dataDir},
+		{"-data-dir", dataDir, "unknown"},
+		{"-data-dir", dataDir, "remove", "task"},
+		{"-data-dir", dataDir, "summary"},
+	} {
+		var out bytes.Buffer
+		assert.Equal(t, exitcodes.ExitError, runStateTool(arguments, &out), arguments)
+		assert.Empty(t, out.String())
+	}
+}
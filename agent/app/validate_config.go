@@ -0,0 +1,65 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package app
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/aws/amazon-ecs-agent/agent/config"
+	"github.com/aws/amazon-ecs-agent/agent/sighandlers/exitcodes"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/ec2"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+)
+
+// validateConfig loads the agent configuration without starting the agent and writes the
+// validation report as JSON. It returns a non-zero exit code if the agent would fail to start
+// with the configuration.
+func validateConfig(blackholeEC2Metadata bool, w io.Writer) int {
+	// Write the warnings and errors logged while loading the config to stderr, so that the report
+	// written to stdout can be parsed
+	logWarningsToStderr()
+
+	var ec2MetadataClient ec2.EC2MetadataClient
+	if blackholeEC2Metadata {
+		ec2MetadataClient = ec2.NewBlackholeEC2MetadataClient()
+	} else {
+		var err error
+		ec2MetadataClient, err = ec2.NewEC2MetadataClient(nil)
+		if err != nil {
+			logger.Critical("Error creating EC2 metadata client", logger.Fields{
+				field.Error: err,
+			})
+			return exitcodes.ExitError
+		}
+	}
+	return writeConfigValidationReport(config.ValidateConfig(ec2MetadataClient), w)
+}
+
+// writeConfigValidationReport writes the config validation report and returns the matching exit code
+func writeConfigValidationReport(report *config.ValidationReport, w io.Writer) int {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		logger.Error("Unable to write the config validation report", logger.Fields{
+			field.Error: err,
+		})
+		return exitcodes.ExitError
+	}
+	if !report.Valid {
+		return exitcodes.ExitTerminal
+	}
+	return exitcodes.ExitSuccess
+}
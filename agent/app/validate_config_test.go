@@ -0,0 +1,66 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/amazon-ecs-agent/agent/config"
+	"github.com/aws/amazon-ecs-agent/agent/sighandlers/exitcodes"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteConfigValidationReport(t *testing.T) {
+	tcs := []struct {
+		name             string
+		report           *config.ValidationReport
+		expectedExitCode int
+	}{
+		{
+			name: "valid",
+			report: &config.ValidationReport{
+				Valid: true,
+				Settings: []config.ConfigSetting{
+					{Name: "ECS_CLUSTER", ConfigFileKey: "Cluster", Value: "default", Source: config.ConfigSourceDefault},
+				},
+			},
+			expectedExitCode: exitcodes.ExitSuccess,
+		},
+		{
+			name: "invalid",
+			report: &config.ValidationReport{
+				Valid:  false,
+				Errors: []string{"Missing required fields: AWSRegion"},
+			},
+			expectedExitCode: exitcodes.ExitTerminal,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			assert.Equal(t, tc.expectedExitCode, writeConfigValidationReport(tc.report, &out))
+			var written config.ValidationReport
+			require.NoError(t, json.Unmarshal(out.Bytes(), &written))
+			assert.Equal(t, *tc.report, written)
+		})
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cdi
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+
+	dockercontainer "github.com/docker/docker/api/types/container"
+)
+
+const (
+	// defaultDevicePermissions are the cgroup permissions of the device nodes without permissions
+	defaultDevicePermissions = "rwm"
+)
+
+// DefaultSpecDirs are the directories of the CDI spec files. The devices of the specs of the later directories
+// override the devices with the same name of the earlier ones.
+var DefaultSpecDirs = []string{"/etc/cdi", "/var/run/cdi"}
+
+// DeviceManager gets the CDI devices of the host and the edits injecting them into the containers
+type DeviceManager interface {
+	// Initialize loads the devices of the spec files
+	Initialize() error
+	// GetDevices returns the sorted fully qualified names of the devices
+	GetDevices() []string
+	// GetUnsupportedDevices returns the reasons why the devices which can't be injected into the containers are
+	// unsupported, by fully qualified name
+	GetUnsupportedDevices() map[string]string
+	// GetContainerEdits returns the edits injecting the devices with the fully qualified names into a container
+	GetContainerEdits(devices []string) (*ContainerEdits, error)
+}
+
+// device is a device loaded from a spec file
+type device struct {
+	specPath  string
+	specEdits *ContainerEdits
+	edits     *ContainerEdits
+}
+
+type deviceManager struct {
+	specDirs    []string
+	devices     map[string]device
+	unsupported map[string]string
+	lock        sync.RWMutex
+}
+
+// NewDeviceManager returns a DeviceManager loading the spec files of the directories
+func NewDeviceManager(specDirs []string) DeviceManager {
+	return &deviceManager{
+		specDirs:    specDirs,
+		devices:     make(map[string]device),
+		unsupported: make(map[string]string),
+	}
+}
+
+// Initialize loads the devices of the JSON and YAML spec files of the spec directories. The invalid spec files are
+// ignored. The devices whose edits can't be applied to the containers, such as the devices requiring hooks, are
+// reported as unsupported so that the tasks requesting them fail.
+func (manager *deviceManager) Initialize() error {
+	devices := make(map[string]device)
+	unsupported := make(map[string]string)
+	for _, specDir := range manager.specDirs {
+		entries, err := os.ReadDir(specDir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("unable to read CDI spec directory %s: %w", specDir, err)
+		}
+		// The entries are sorted by name, the devices of the later spec files override the earlier ones
+		for _, entry := range entries {
+			switch filepath.Ext(entry.Name()) {
+			case ".json", ".yaml", ".yml":
+			default:
+				continue
+			}
+			if entry.IsDir() {
+				continue
+			}
+			manager.loadSpec(filepath.Join(specDir, entry.Name()), devices, unsupported)
+		}
+	}
+
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+	manager.devices = devices
+	manager.unsupported = unsupported
+	logger.Info("Loaded CDI devices", logger.Fields{
+		"devices": manager.getDevicesUnsafe(),
+	})
+	if len(unsupported) > 0 {
+		logger.Error("CDI devices can't be injected through docker, the tasks requesting them will fail",
+			logger.Fields{
+				"devices": unsupported,
+			})
+	}
+	return nil
+}
+
+func (manager *deviceManager) loadSpec(specPath string, devices map[string]device, unsupported map[string]string) {
+	data, err := os.ReadFile(specPath)
+	if err == nil {
+		var spec *Spec
+		spec, err = parseSpec(specPath, data)
+		if err == nil {
+			manager.addSpecDevices(specPath, spec, devices, unsupported)
+			return
+		}
+	}
+	logger.Warn("Ignoring invalid CDI spec file", logger.Fields{
+		"specFile":  specPath,
+		field.Error: err,
+	})
+}
+
+func (manager *deviceManager) addSpecDevices(specPath string, spec *Spec, devices map[string]device,
+	unsupported map[string]string) {
+	specEdits := spec.ContainerEdits
+	specReason := specEdits.unsupported()
+	for i := range spec.Devices {
+		name := QualifiedName(spec.Kind, spec.Devices[i].Name)
+		edits := spec.Devices[i].ContainerEdits
+		reason := specReason
+		if reason == "" {
+			reason = edits.unsupported()
+		}
+		if reason != "" {
+			delete(devices, name)
+			unsupported[name] = fmt.Sprintf("%s in spec file %s", reason, specPath)
+			continue
+		}
+		delete(unsupported, name)
+		devices[name] = device{
+			specPath:  specPath,
+			specEdits: &specEdits,
+			edits:     &edits,
+		}
+	}
+}
+
+// GetDevices returns the sorted fully qualified names of the devices
+func (manager *deviceManager) GetDevices() []string {
+	manager.lock.RLock()
+	defer manager.lock.RUnlock()
+	return manager.getDevicesUnsafe()
+}
+
+// GetUnsupportedDevices returns the reasons why the devices which can't be injected into the containers are
+// unsupported, by fully qualified name
+func (manager *deviceManager) GetUnsupportedDevices() map[string]string {
+	manager.lock.RLock()
+	defer manager.lock.RUnlock()
+	unsupported := make(map[string]string, len(manager.unsupported))
+	for name, reason := range manager.unsupported {
+		unsupported[name] = reason
+	}
+	return unsupported
+}
+
+func (manager *deviceManager) getDevicesUnsafe() []string {
+	names := make([]string, 0, len(manager.devices))
+	for name := range manager.devices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetContainerEdits returns the edits of the devices, preceded by the edits of their spec files. The edits of a
+// spec file are applied once for all of its devices.
+func (manager *deviceManager) GetContainerEdits(devices []string) (*ContainerEdits, error) {
+	manager.lock.RLock()
+	defer manager.lock.RUnlock()
+
+	edits := &ContainerEdits{}
+	specs := make(map[string]struct{})
+	for _, name := range devices {
+		device, ok := manager.devices[name]
+		if !ok {
+			if reason, ok := manager.unsupported[name]; ok {
+				return nil, fmt.Errorf("CDI device %s can't be injected through docker: %s", name, reason)
+			}
+			return nil, fmt.Errorf("CDI device %s not found", name)
+		}
+		if _, ok := specs[device.specPath]; !ok {
+			specs[device.specPath] = struct{}{}
+			edits.append(device.specEdits)
+		}
+		edits.append(device.edits)
+	}
+	return edits, nil
+}
+
+// EnvironmentVariables returns the environment variables of the edits. The later variables override the earlier
+// ones with the same name.
+func (edits *ContainerEdits) EnvironmentVariables() map[string]string {
+	envVars := make(map[string]string, len(edits.Env))
+	for _, env := range edits.Env {
+		name, value, _ := strings.Cut(env, "=")
+		envVars[name] = value
+	}
+	return envVars
+}
+
+// ApplyToHostConfig adds the device nodes, the bind mounts and the additional groups of the edits to the host
+// config of a container
+func (edits *ContainerEdits) ApplyToHostConfig(hostConfig *dockercontainer.HostConfig) {
+	for _, deviceNode := range edits.DeviceNodes {
+		hostPath := deviceNode.HostPath
+		if hostPath == "" {
+			hostPath = deviceNode.Path
+		}
+		permissions := deviceNode.Permissions
+		if permissions == "" {
+			permissions = defaultDevicePermissions
+		}
+		hostConfig.Devices = append(hostConfig.Devices, dockercontainer.DeviceMapping{
+			PathOnHost:        hostPath,
+			PathInContainer:   deviceNode.Path,
+			CgroupPermissions: permissions,
+		})
+	}
+	for _, mount := range edits.Mounts {
+		bind := mount.HostPath + ":" + mount.ContainerPath
+		for _, option := range mount.Options {
+			if option == "ro" {
+				bind += ":ro"
+				break
+			}
+		}
+		hostConfig.Binds = append(hostConfig.Binds, bind)
+	}
+	for _, gid := range edits.AdditionalGIDs {
+		hostConfig.GroupAdd = append(hostConfig.GroupAdd, strconv.FormatUint(uint64(gid), 10))
+	}
+}
@@ -0,0 +1,243 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cdi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	dockercontainer "github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	fpgaSpec = `
+cdiVersion: "0.6.0"
+kind: vendor.com/fpga
+containerEdits:
+  env:
+    - FPGA_DRIVER=1.2
+  mounts:
+    - hostPath: /usr/lib/fpga
+      containerPath: /usr/lib/fpga
+      options: ["ro", "nosuid", "bind"]
+devices:
+  - name: "0"
+    containerEdits:
+      env:
+        - FPGA_VISIBLE=0
+      deviceNodes:
+        - path: /dev/fpga0
+          permissions: rw
+  - name: "1"
+    containerEdits:
+      env:
+        - FPGA_VISIBLE=1
+      deviceNodes:
+        - path: /dev/fpga1
+      additionalGids: [42]
+  - name: hooked
+    containerEdits:
+      hooks:
+        - hookName: createContainer
+          path: /usr/bin/fpga-hook
+`
+	nicSpec = `{
+  "cdiVersion": "0.5.0",
+  "kind": "vendor.com/nic",
+  "devices": [
+    {
+      "name": "nic0",
+      "containerEdits": {
+        "deviceNodes": [{"path": "/dev/net/nic0", "hostPath": "/dev/vendor-nic0"}],
+        "mounts": [{"hostPath": "/var/lib/nic", "containerPath": "/nic"}]
+      }
+    }
+  ]
+}`
+)
+
+func writeSpec(t *testing.T, dir, name, content string) {
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+}
+
+func TestDeviceManagerInitialize(t *testing.T) {
+	etcDir := t.TempDir()
+	runDir := t.TempDir()
+	writeSpec(t, etcDir, "fpga.yaml", fpgaSpec)
+	writeSpec(t, etcDir, "invalid.json", `{"cdiVersion": "0.6.0", "kind": "invalid"}`)
+	writeSpec(t, etcDir, "README.txt", "not a spec")
+	writeSpec(t, runDir, "nic.json", nicSpec)
+
+	manager := NewDeviceManager([]string{etcDir, runDir, filepath.Join(etcDir, "missing")})
+	require.NoError(t, manager.Initialize())
+	assert.Equal(t, []string{"vendor.com/fpga=0", "vendor.com/fpga=1", "vendor.com/nic=nic0"},
+		manager.GetDevices(), "Invalid specs and devices with hooks should be ignored")
+	unsupported := manager.GetUnsupportedDevices()
+	assert.Len(t, unsupported, 1)
+	assert.Contains(t, unsupported["vendor.com/fpga=hooked"], "hooks are not supported")
+}
+
+func TestKind(t *testing.T) {
+	assert.Equal(t, "vendor.com/fpga", Kind("vendor.com/fpga=0"))
+	assert.Equal(t, "vendor.com/nic", Kind(QualifiedName("vendor.com/nic", "nic:0")))
+}
+
+func TestDeviceManagerSpecOverride(t *testing.T) {
+	etcDir := t.TempDir()
+	runDir := t.TempDir()
+	writeSpec(t, etcDir, "nic.json", nicSpec)
+	writeSpec(t, runDir, "nic.yaml", `
+cdiVersion: "0.6.0"
+kind: vendor.com/nic
+devices:
+  - name: nic0
+    containerEdits:
+      deviceNodes:
+        - path: /dev/net/nic0
+          hostPath: /dev/vendor-nic1
+`)
+
+	manager := NewDeviceManager([]string{etcDir, runDir})
+	require.NoError(t, manager.Initialize())
+	edits, err := manager.GetContainerEdits([]string{"vendor.com/nic=nic0"})
+	require.NoError(t, err)
+	require.Len(t, edits.DeviceNodes, 1)
+	assert.Equal(t, "/dev/vendor-nic1", edits.DeviceNodes[0].HostPath)
+	assert.Empty(t, edits.Mounts)
+}
+
+func TestDeviceManagerGetContainerEdits(t *testing.T) {
+	specDir := t.TempDir()
+	writeSpec(t, specDir, "fpga.yaml", fpgaSpec)
+	writeSpec(t, specDir, "nic.json", nicSpec)
+	manager := NewDeviceManager([]string{specDir})
+	require.NoError(t, manager.Initialize())
+
+	edits, err := manager.GetContainerEdits([]string{"vendor.com/fpga=0", "vendor.com/fpga=1", "vendor.com/nic=nic0"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"FPGA_DRIVER":  "1.2",
+		"FPGA_VISIBLE": "1",
+	}, edits.EnvironmentVariables())
+
+	hostConfig := &dockercontainer.HostConfig{
+		Binds: []string{"/data:/data"},
+	}
+	edits.ApplyToHostConfig(hostConfig)
+	assert.Equal(t, []dockercontainer.DeviceMapping{
+		{PathOnHost: "/dev/fpga0", PathInContainer: "/dev/fpga0", CgroupPermissions: "rw"},
+		{PathOnHost: "/dev/fpga1", PathInContainer: "/dev/fpga1", CgroupPermissions: "rwm"},
+		{PathOnHost: "/dev/vendor-nic0", PathInContainer: "/dev/net/nic0", CgroupPermissions: "rwm"},
+	}, hostConfig.Devices)
+	assert.Equal(t, []string{"/data:/data", "/usr/lib/fpga:/usr/lib/fpga:ro", "/var/lib/nic:/nic"},
+		hostConfig.Binds, "The mounts of the fpga spec should be added once")
+	assert.Equal(t, []string{"42"}, hostConfig.GroupAdd)
+
+	_, err = manager.GetContainerEdits([]string{"vendor.com/fpga=hooked"})
+	assert.ErrorContains(t, err, "hooks are not supported")
+	_, err = manager.GetContainerEdits([]string{"vendor.com/fpga=2"})
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestParseSpecErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		spec string
+	}{
+		{
+			name: "missing version",
+			spec: `{"kind": "vendor.com/fpga"}`,
+		},
+		{
+			name: "invalid kind",
+			spec: `{"cdiVersion": "0.6.0", "kind": "fpga"}`,
+		},
+		{
+			name: "invalid device name",
+			spec: `{"cdiVersion": "0.6.0", "kind": "vendor.com/fpga", "devices": [{"name": "a/b"}]}`,
+		},
+		{
+			name: "duplicate device",
+			spec: `{"cdiVersion": "0.6.0", "kind": "vendor.com/fpga", "devices": [{"name": "0"}, {"name": "0"}]}`,
+		},
+		{
+			name: "invalid env",
+			spec: `{"cdiVersion": "0.6.0", "kind": "vendor.com/fpga", "containerEdits": {"env": ["=1"]}}`,
+		},
+		{
+			name: "relative device node",
+			spec: `{"cdiVersion": "0.6.0", "kind": "vendor.com/fpga",
+				"containerEdits": {"deviceNodes": [{"path": "dev/fpga0"}]}}`,
+		},
+		{
+			name: "invalid permissions",
+			spec: `{"cdiVersion": "0.6.0", "kind": "vendor.com/fpga",
+				"containerEdits": {"deviceNodes": [{"path": "/dev/fpga0", "permissions": "x"}]}}`,
+		},
+		{
+			name: "relative mount",
+			spec: `{"cdiVersion": "0.6.0", "kind": "vendor.com/fpga",
+				"containerEdits": {"mounts": [{"hostPath": "lib", "containerPath": "/lib"}]}}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseSpec("spec.json", []byte(tc.spec))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestContainerEditsUnsupported(t *testing.T) {
+	spec, err := parseSpec("spec.yaml", []byte(`
+cdiVersion: "0.6.0"
+kind: vendor.com/fpga
+containerEdits:
+  mounts:
+    - hostPath: /tmp
+      containerPath: /tmp
+      type: tmpfs
+devices:
+  - name: "0"
+    containerEdits:
+      intelRdt:
+        closID: fpga
+`))
+	require.NoError(t, err)
+	assert.NotEmpty(t, spec.ContainerEdits.unsupported())
+	assert.NotEmpty(t, spec.Devices[0].ContainerEdits.unsupported())
+
+	specDir := t.TempDir()
+	writeSpec(t, specDir, "fpga.yaml", `
+cdiVersion: "0.6.0"
+kind: vendor.com/fpga
+containerEdits:
+  mounts:
+    - hostPath: /tmp
+      containerPath: /tmp
+      type: tmpfs
+devices:
+  - name: "0"
+`)
+	manager := NewDeviceManager([]string{specDir})
+	require.NoError(t, manager.Initialize())
+	assert.Empty(t, manager.GetDevices(), "Devices of specs with unsupported edits should be ignored")
+	assert.Contains(t, manager.GetUnsupportedDevices()["vendor.com/fpga=0"], "mounts of type \"tmpfs\"")
+}
@@ -0,0 +1,186 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package cdi discovers the devices of the host described by Container Device Interface (CDI) spec files, and
+// injects the device nodes, mounts and environment variables of the devices requested by the containers.
+package cdi
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// kindRegexp matches the kind of the devices of a spec, e.g. "vendor.com/fpga"
+	kindRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*/[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	// deviceNameRegexp matches the names of the devices of a spec, e.g. "0" or "fpga-1"
+	deviceNameRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.:-]*$`)
+)
+
+// Spec is a CDI spec file describing the devices of a kind
+type Spec struct {
+	Version        string         `json:"cdiVersion"`
+	Kind           string         `json:"kind"`
+	Devices        []Device       `json:"devices"`
+	ContainerEdits ContainerEdits `json:"containerEdits,omitempty"`
+}
+
+// Device is a device of a CDI spec. The edits of the spec are applied to the containers along with the edits of
+// the device.
+type Device struct {
+	Name           string         `json:"name"`
+	ContainerEdits ContainerEdits `json:"containerEdits"`
+}
+
+// ContainerEdits are the changes made to a container to inject devices
+type ContainerEdits struct {
+	Env            []string     `json:"env,omitempty"`
+	DeviceNodes    []DeviceNode `json:"deviceNodes,omitempty"`
+	Mounts         []Mount      `json:"mounts,omitempty"`
+	AdditionalGIDs []uint32     `json:"additionalGids,omitempty"`
+	// Hooks and IntelRdt can't be applied to the containers created through docker, the devices requiring them
+	// are unsupported
+	Hooks    []json.RawMessage `json:"hooks,omitempty"`
+	IntelRdt json.RawMessage   `json:"intelRdt,omitempty"`
+}
+
+// DeviceNode is a device node of the host exposed in the container
+type DeviceNode struct {
+	// Path is the path of the device node in the container
+	Path string `json:"path"`
+	// HostPath is the path of the device node on the host, which is Path when it's empty
+	HostPath string `json:"hostPath,omitempty"`
+	// Permissions are the cgroup permissions of the device node, "rwm" when empty
+	Permissions string `json:"permissions,omitempty"`
+}
+
+// Mount is a directory or file of the host bind mounted in the container
+type Mount struct {
+	HostPath      string   `json:"hostPath"`
+	ContainerPath string   `json:"containerPath"`
+	Options       []string `json:"options,omitempty"`
+	Type          string   `json:"type,omitempty"`
+}
+
+// QualifiedName returns the fully qualified name of a device of the kind, e.g. "vendor.com/fpga=0"
+func QualifiedName(kind, name string) string {
+	return kind + "=" + name
+}
+
+// Kind returns the kind of the device with the fully qualified name, e.g. "vendor.com/fpga" for "vendor.com/fpga=0"
+func Kind(qualifiedName string) string {
+	kind, _, _ := strings.Cut(qualifiedName, "=")
+	return kind
+}
+
+// parseSpec parses the JSON or YAML spec file with the given path
+func parseSpec(path string, data []byte) (*Spec, error) {
+	if filepath.Ext(path) != ".json" {
+		// The YAML spec files are converted to JSON so that the specs are decoded with the same field names
+		var content interface{}
+		if err := yaml.Unmarshal(data, &content); err != nil {
+			return nil, err
+		}
+		var err error
+		if data, err = json.Marshal(content); err != nil {
+			return nil, err
+		}
+	}
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+func (spec *Spec) validate() error {
+	if spec.Version == "" {
+		return fmt.Errorf("missing cdiVersion")
+	}
+	if !kindRegexp.MatchString(spec.Kind) {
+		return fmt.Errorf("invalid kind %q", spec.Kind)
+	}
+	if err := spec.ContainerEdits.validate(); err != nil {
+		return err
+	}
+	names := make(map[string]struct{}, len(spec.Devices))
+	for _, device := range spec.Devices {
+		if !deviceNameRegexp.MatchString(device.Name) {
+			return fmt.Errorf("invalid device name %q", device.Name)
+		}
+		if _, ok := names[device.Name]; ok {
+			return fmt.Errorf("duplicate device %q", device.Name)
+		}
+		names[device.Name] = struct{}{}
+	}
+	return nil
+}
+
+// validate returns an error when the edits are invalid. Unsupported edits are reported by unsupported.
+func (edits *ContainerEdits) validate() error {
+	for _, env := range edits.Env {
+		if strings.Index(env, "=") <= 0 {
+			return fmt.Errorf("invalid environment variable %q", env)
+		}
+	}
+	for _, deviceNode := range edits.DeviceNodes {
+		if !filepath.IsAbs(deviceNode.Path) {
+			return fmt.Errorf("invalid device node path %q", deviceNode.Path)
+		}
+		if deviceNode.HostPath != "" && !filepath.IsAbs(deviceNode.HostPath) {
+			return fmt.Errorf("invalid device node host path %q", deviceNode.HostPath)
+		}
+		if strings.Trim(deviceNode.Permissions, "rwm") != "" {
+			return fmt.Errorf("invalid permissions %q of device node %s", deviceNode.Permissions, deviceNode.Path)
+		}
+	}
+	for _, mount := range edits.Mounts {
+		if !filepath.IsAbs(mount.HostPath) || !filepath.IsAbs(mount.ContainerPath) {
+			return fmt.Errorf("invalid mount of %q to %q", mount.HostPath, mount.ContainerPath)
+		}
+	}
+	return nil
+}
+
+// unsupported returns the reason why the edits can't be applied to a container created through docker, or an
+// empty string when they can
+func (edits *ContainerEdits) unsupported() string {
+	if len(edits.Hooks) > 0 {
+		return "hooks are not supported"
+	}
+	if len(edits.IntelRdt) > 0 && string(edits.IntelRdt) != "null" {
+		return "intelRdt is not supported"
+	}
+	for _, mount := range edits.Mounts {
+		if mount.Type != "" && mount.Type != "bind" {
+			return fmt.Sprintf("mounts of type %q are not supported", mount.Type)
+		}
+	}
+	return ""
+}
+
+// append appends the other edits to the edits
+func (edits *ContainerEdits) append(other *ContainerEdits) {
+	edits.Env = append(edits.Env, other.Env...)
+	edits.DeviceNodes = append(edits.DeviceNodes, other.DeviceNodes...)
+	edits.Mounts = append(edits.Mounts, other.Mounts...)
+	edits.AdditionalGIDs = append(edits.AdditionalGIDs, other.AdditionalGIDs...)
+}
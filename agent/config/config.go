@@ -25,6 +25,7 @@ import (
 
 	"github.com/aws/amazon-ecs-agent/agent/dockerclient"
 	"github.com/aws/amazon-ecs-agent/agent/utils"
+	referenceutil "github.com/aws/amazon-ecs-agent/agent/utils/reference"
 	apierrors "github.com/aws/amazon-ecs-agent/ecs-agent/api/errors"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/ec2"
 	commonutils "github.com/aws/amazon-ecs-agent/ecs-agent/utils"
@@ -74,6 +75,32 @@ const (
 	// image cleanup.
 	DefaultNumImagesToDeletePerCycle = 5
 
+	// DefaultImageCleanupDiskUsageThreshold specifies the default percentage of disk usage below which the
+	// disk usage image cleanup policy stops removing images.
+	DefaultImageCleanupDiskUsageThreshold = 80
+
+	// DefaultImageCleanupKeepVersions specifies the default number of images of each repository kept by the
+	// keep versions image cleanup policy.
+	DefaultImageCleanupKeepVersions = 2
+
+	// DefaultEventSinkQueueSize specifies the default number of state change events waiting to be delivered to
+	// the event sink.
+	DefaultEventSinkQueueSize = 1000
+
+	// DefaultSecretRefreshInterval specifies the default interval at which the secrets delivered as files are
+	// refreshed.
+	DefaultSecretRefreshInterval = 15 * time.Minute
+
+	// DefaultImagePrewarmProtectionDuration specifies the default duration for which pre-warmed images are
+	// protected from image cleanup.
+	DefaultImagePrewarmProtectionDuration = 24 * time.Hour
+
+	// ImagePrewarmAuthECR specifies that images hosted in ECR are pre-warmed with the instance credentials.
+	ImagePrewarmAuthECR = "ecr"
+
+	// ImagePrewarmAuthEngine specifies that images are pre-warmed with the engine auth data.
+	ImagePrewarmAuthEngine = "engine"
+
 	// DefaultNumNonECSContainersToDeletePerCycle specifies the default number of nonecs containers to delete when agent performs
 	// nonecs containers cleanup.
 	DefaultNumNonECSContainersToDeletePerCycle = 5
@@ -89,6 +116,11 @@ const (
 	//DefaultImagePullTimeout specifies the timeout for PullImage API.
 	DefaultImagePullTimeout = 2 * time.Hour
 
+	// DefaultTerminationDrainingTimeout specifies the default duration for which the agent waits for the tasks
+	// to be stopped after setting the container instance to DRAINING on termination. It needs to stay below the
+	// stop timeout ecs-init derives from it (see StopAgent in ecs-init/docker/docker.go).
+	DefaultTerminationDrainingTimeout = 1 * time.Minute
+
 	// minimumTaskCleanupWaitDuration specifies the minimum duration to wait before cleaning up
 	// a task's container. This is used to enforce sane values for the config.TaskCleanupWaitDuration field.
 	minimumTaskCleanupWaitDuration = time.Second
@@ -97,6 +129,15 @@ const (
 	// 'stuck' in the pull / unpack step. Very small values are unsafe and lead to high failure rate.
 	minimumImagePullInactivityTimeout = 1 * time.Minute
 
+	// minimumTerminationDrainingTimeout specifies the minimum duration for which the agent waits for the tasks
+	// to be stopped after setting the container instance to DRAINING on termination.
+	minimumTerminationDrainingTimeout = time.Second
+
+	// maximumTerminationDrainingTimeout specifies the maximum duration for which the agent waits for the tasks
+	// to be stopped after setting the container instance to DRAINING on termination. Longer values would exceed
+	// the stop timeout of the ecs service, after which the agent is killed before saving its state.
+	maximumTerminationDrainingTimeout = 5 * time.Minute
+
 	// minimumPollingMetricsWaitDuration specifies the minimum duration to wait before polling for new stats
 	// from docker. This is only used when PollMetrics is set to true
 	minimumPollingMetricsWaitDuration = 5 * time.Second
@@ -105,6 +146,10 @@ const (
 	// from docker. This is only used when PollMetrics is set to true
 	maximumPollingMetricsWaitDuration = DefaultContainerMetricsPublishInterval
 
+	// minimumSecretRefreshInterval specifies the minimum interval at which the secrets delivered as files are
+	// refreshed, so that the tasks don't get throttled by SSM Parameter Store and Secrets Manager
+	minimumSecretRefreshInterval = 1 * time.Minute
+
 	// minimumDockerStopTimeout specifies the minimum value for docker StopContainer API
 	minimumDockerStopTimeout = 1 * time.Second
 
@@ -168,6 +213,26 @@ const (
 	ImagePullPreferCachedBehavior
 )
 
+const (
+	// ImageCleanupLRUPolicy specifies that the least recently used images are removed first, up to
+	// NumImagesToDeletePerCycle images every cleanup cycle.
+	ImageCleanupLRUPolicy ImageCleanupPolicyType = iota
+
+	// ImageCleanupDiskUsagePolicy specifies that the least recently used images are removed until the
+	// usage of the disk holding the Docker data root falls below ImageCleanupDiskUsageThreshold.
+	ImageCleanupDiskUsagePolicy
+
+	// ImageCleanupSizeWeightedPolicy specifies that the images are removed in the order of their size
+	// weighted by the time since they were last used, up to NumImagesToDeletePerCycle images every
+	// cleanup cycle.
+	ImageCleanupSizeWeightedPolicy
+
+	// ImageCleanupKeepVersionsPolicy specifies that the least recently used images are removed first,
+	// except for the ImageCleanupKeepVersions most recently pulled images of each repository, up to
+	// NumImagesToDeletePerCycle images every cleanup cycle.
+	ImageCleanupKeepVersionsPolicy
+)
+
 const (
 	// When ContainerInstancePropagateTagsFromNoneType is specified, no DescribeTags
 	// API call will be made.
@@ -360,12 +425,52 @@ func (cfg *Config) validateAndOverrideBounds() error {
 		cfg.NumImagesToDeletePerCycle = DefaultNumImagesToDeletePerCycle
 	}
 
+	if cfg.ImageCleanupDiskUsageThreshold <= 0 || cfg.ImageCleanupDiskUsageThreshold >= 100 {
+		seelog.Warnf("Invalid value for ECS_IMAGE_CLEANUP_DISK_USAGE_THRESHOLD, will be overridden with the default value: %d. Parsed value: %d, expected a percentage between 1 and 99.", DefaultImageCleanupDiskUsageThreshold, cfg.ImageCleanupDiskUsageThreshold)
+		cfg.ImageCleanupDiskUsageThreshold = DefaultImageCleanupDiskUsageThreshold
+	}
+
+	if cfg.ImageCleanupKeepVersions < 1 {
+		seelog.Warnf("Invalid value for ECS_IMAGE_CLEANUP_KEEP_VERSIONS, will be overridden with the default value: %d. Parsed value: %d, minimum value: 1.", DefaultImageCleanupKeepVersions, cfg.ImageCleanupKeepVersions)
+		cfg.ImageCleanupKeepVersions = DefaultImageCleanupKeepVersions
+	}
+
+	if cfg.EventSinkQueueSize < 1 {
+		seelog.Warnf("Invalid value for ECS_EVENT_SINK_QUEUE_SIZE, will be overridden with the default value: %d. Parsed value: %d, minimum value: 1.", DefaultEventSinkQueueSize, cfg.EventSinkQueueSize)
+		cfg.EventSinkQueueSize = DefaultEventSinkQueueSize
+	}
+
+	if cfg.SecretRefreshInterval < minimumSecretRefreshInterval {
+		seelog.Warnf("Invalid value for ECS_SECRET_REFRESH_INTERVAL, will be overridden with the default value: %s. Parsed value: %v, minimum value: %v.", DefaultSecretRefreshInterval.String(), cfg.SecretRefreshInterval, minimumSecretRefreshInterval)
+		cfg.SecretRefreshInterval = DefaultSecretRefreshInterval
+	}
+
+	for pattern, mirror := range cfg.RegistryMirrors {
+		if err := referenceutil.ValidateRegistryMirror(pattern, mirror); err != nil {
+			seelog.Warnf("Invalid registry mirror rule %s -> %s, it will be ignored: %v", pattern, mirror, err)
+			delete(cfg.RegistryMirrors, pattern)
+		}
+	}
+
 	if cfg.TaskMetadataSteadyStateRate <= 0 || cfg.TaskMetadataBurstRate <= 0 {
 		seelog.Warnf("Invalid values for rate limits, will be overridden with default values: %d,%d.", DefaultTaskMetadataSteadyStateRate, DefaultTaskMetadataBurstRate)
 		cfg.TaskMetadataSteadyStateRate = DefaultTaskMetadataSteadyStateRate
 		cfg.TaskMetadataBurstRate = DefaultTaskMetadataBurstRate
 	}
 
+	if cfg.TerminationDrainingTimeout < minimumTerminationDrainingTimeout {
+		seelog.Warnf("Invalid value for ECS_TERMINATION_DRAINING_TIMEOUT, will be overridden with the default value: %s. Parsed value: %v, minimum value: %v.", DefaultTerminationDrainingTimeout.String(), cfg.TerminationDrainingTimeout, minimumTerminationDrainingTimeout)
+		cfg.TerminationDrainingTimeout = DefaultTerminationDrainingTimeout
+	} else if cfg.TerminationDrainingTimeout > maximumTerminationDrainingTimeout {
+		seelog.Warnf("Invalid value for ECS_TERMINATION_DRAINING_TIMEOUT, will be overridden with the maximum value: %s. Parsed value: %v.", maximumTerminationDrainingTimeout.String(), cfg.TerminationDrainingTimeout)
+		cfg.TerminationDrainingTimeout = maximumTerminationDrainingTimeout
+	}
+
+	if cfg.LogLevel != "" && !isValidLogLevel(cfg.LogLevel) {
+		seelog.Warnf("Invalid value for LogLevel, will be ignored. Parsed value: %s, expected one of: %s.", cfg.LogLevel, strings.Join(validLogLevels, ", "))
+		cfg.LogLevel = ""
+	}
+
 	// check the PollMetrics specific configurations
 	cfg.pollMetricsOverrides()
 
@@ -522,6 +627,8 @@ func environmentConfig() (Config, error) {
 
 	containerInstanceTags, errs := parseContainerInstanceTags(errs)
 
+	registryMirrors, errs := parseRegistryMirrors(errs)
+
 	additionalLocalRoutes, errs := parseAdditionalLocalRoutes(errs)
 
 	var err error
@@ -549,6 +656,9 @@ func environmentConfig() (Config, error) {
 		AppArmorCapable:                     parseBooleanDefaultFalseConfig("ECS_APPARMOR_CAPABLE"),
 		TaskCleanupWaitDuration:             parseEnvVariableDuration("ECS_ENGINE_TASK_CLEANUP_WAIT_DURATION"),
 		TaskCleanupWaitDurationJitter:       parseEnvVariableDuration("ECS_ENGINE_TASK_CLEANUP_WAIT_DURATION_JITTER"),
+		ContainerRestartBackoffInitial:      parseEnvVariableDuration("ECS_CONTAINER_RESTART_BACKOFF_INITIAL_PERIOD"),
+		ContainerRestartBackoffMax:          parseEnvVariableDuration("ECS_CONTAINER_RESTART_BACKOFF_MAX_PERIOD"),
+		ContainerRestartMaxCount:            parseEnvVariableUint16("ECS_CONTAINER_RESTART_MAX_COUNT"),
 		TaskENIEnabled:                      parseBooleanDefaultFalseConfig("ECS_ENABLE_TASK_ENI"),
 		TaskIAMRoleEnabled:                  parseBooleanDefaultFalseConfig("ECS_ENABLE_TASK_IAM_ROLE"),
 		DeleteNonECSImagesEnabled:           parseBooleanDefaultFalseConfig("ECS_ENABLE_UNTRACKED_IMAGE_CLEANUP"),
@@ -562,6 +672,8 @@ func environmentConfig() (Config, error) {
 		ImagePullTimeout:                    parseEnvVariableDuration("ECS_IMAGE_PULL_TIMEOUT"),
 		CredentialsAuditLogFile:             os.Getenv("ECS_AUDIT_LOGFILE"),
 		CredentialsAuditLogDisabled:         utils.ParseBool(os.Getenv("ECS_AUDIT_LOGFILE_DISABLED"), false),
+		FaultAuditLogFile:                   os.Getenv("ECS_FAULT_AUDIT_LOGFILE"),
+		FaultAuditLogDisabled:               utils.ParseBool(os.Getenv("ECS_FAULT_AUDIT_LOGFILE_DISABLED"), false),
 		TaskIAMRoleEnabledForNetworkHost:    utils.ParseBool(os.Getenv("ECS_ENABLE_TASK_IAM_ROLE_NETWORK_HOST"), false),
 		ImageCleanupDisabled:                parseBooleanDefaultFalseConfig("ECS_DISABLE_IMAGE_CLEANUP"),
 		MinimumImageDeletionAge:             parseEnvVariableDuration("ECS_IMAGE_MINIMUM_CLEANUP_AGE"),
@@ -570,6 +682,15 @@ func environmentConfig() (Config, error) {
 		NumImagesToDeletePerCycle:           parseNumImagesToDeletePerCycle(),
 		NumNonECSContainersToDeletePerCycle: parseNumNonECSContainersToDeletePerCycle(),
 		ImagePullBehavior:                   parseImagePullBehavior(),
+		RegistryMirrors:                     registryMirrors,
+		ImageTrustPolicyFile:                os.Getenv("ECS_IMAGE_TRUST_POLICY_FILE"),
+		ImageCleanupPolicy:                  parseImageCleanupPolicy(),
+		ImageCleanupDiskUsageThreshold:      parseEnvVariableInt("ECS_IMAGE_CLEANUP_DISK_USAGE_THRESHOLD"),
+		ImageCleanupKeepVersions:            parseEnvVariableInt("ECS_IMAGE_CLEANUP_KEEP_VERSIONS"),
+		ImagePrewarmList:                    parseEnvVariableList("ECS_IMAGE_PREWARM_LIST"),
+		ImagePrewarmAuth:                    parseImagePrewarmAuth(),
+		ImagePrewarmProtectionDuration:      parseEnvVariableDuration("ECS_IMAGE_PREWARM_PROTECTION_DURATION"),
+		ImagePrewarmAPIEnabled:              parseBooleanDefaultFalseConfig("ECS_ENABLE_IMAGE_PREWARM_API"),
 		ImageCleanupExclusionList:           parseImageCleanupExclusionList("ECS_EXCLUDE_UNTRACKED_IMAGE"),
 		InstanceAttributes:                  instanceAttributes,
 		CNIPluginsPath:                      os.Getenv("ECS_CNI_PLUGINS_PATH"),
@@ -590,16 +711,26 @@ func environmentConfig() (Config, error) {
 		GPUSupportEnabled:                   utils.ParseBool(os.Getenv("ECS_ENABLE_GPU_SUPPORT"), false),
 		EBSTASupportEnabled:                 utils.ParseBool(os.Getenv("ECS_EBSTA_SUPPORTED"), true),
 		InferentiaSupportEnabled:            utils.ParseBool(os.Getenv("ECS_ENABLE_INF_SUPPORT"), false),
+		CDISupportEnabled:                   utils.ParseBool(os.Getenv("ECS_ENABLE_CDI_SUPPORT"), false),
 		NvidiaRuntime:                       os.Getenv("ECS_NVIDIA_RUNTIME"),
 		TaskMetadataAZDisabled:              utils.ParseBool(os.Getenv("ECS_DISABLE_TASK_METADATA_AZ"), false),
 		CgroupCPUPeriod:                     parseCgroupCPUPeriod(),
 		SpotInstanceDrainingEnabled:         parseBooleanDefaultFalseConfig("ECS_ENABLE_SPOT_INSTANCE_DRAINING"),
+		TerminationDrainingEnabled:          parseBooleanDefaultFalseConfig("ECS_ENABLE_TERMINATION_DRAINING"),
+		TerminationDrainingTimeout:          parseEnvVariableDuration("ECS_TERMINATION_DRAINING_TIMEOUT"),
 		GMSACapable:                         parseGMSACapability(),
 		GMSADomainlessCapable:               parseGMSADomainlessCapability(),
 		VolumePluginCapabilities:            parseVolumePluginCapabilities(),
 		FSxWindowsFileServerCapable:         parseFSxWindowsFileServerCapability(),
 		External:                            parseBooleanDefaultFalseConfig("ECS_EXTERNAL"),
 		EnableRuntimeStats:                  parseBooleanDefaultFalseConfig("ECS_ENABLE_RUNTIME_STATS"),
+		SupportBundleAPIEnabled:             parseBooleanDefaultFalseConfig("ECS_ENABLE_SUPPORT_BUNDLE_API"),
+		TracingEndpoint:                     os.Getenv("ECS_TRACING_ENDPOINT"),
+		EventSinkEndpoint:                   os.Getenv("ECS_EVENT_SINK_ENDPOINT"),
+		EventSinkQueueSize:                  parseEnvVariableInt("ECS_EVENT_SINK_QUEUE_SIZE"),
+		SecretFilesEnabled:                  parseBooleanDefaultFalseConfig("ECS_ENABLE_SECRET_FILES"),
+		SecretFilesDir:                      os.Getenv("ECS_SECRET_FILES_DIR"),
+		SecretRefreshInterval:               parseEnvVariableDuration("ECS_SECRET_REFRESH_INTERVAL"),
 		ShouldExcludeIPv6PortBinding:        parseBooleanDefaultTrueConfig("ECS_EXCLUDE_IPV6_PORTBINDING"),
 		WarmPoolsSupport:                    parseBooleanDefaultFalseConfig("ECS_WARM_POOLS_CHECK"),
 		DynamicHostPortRange:                parseDynamicHostPortRange("ECS_DYNAMIC_HOST_PORT_RANGE"),
@@ -671,6 +671,24 @@ func TestCredentialsAuditLogDisabled(t *testing.T) {
 	assert.True(t, cfg.CredentialsAuditLogDisabled, "Wrong value for CredentialsAuditLogDisabled")
 }
 
+func TestFaultAuditLogFile(t *testing.T) {
+	defer setTestRegion()()
+	dummyLocation := "/foo/faults.log"
+	defer setTestEnv("ECS_FAULT_AUDIT_LOGFILE", dummyLocation)()
+	cfg, err := NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.NoError(t, err)
+	assert.Equal(t, dummyLocation, cfg.FaultAuditLogFile, "Wrong value for FaultAuditLogFile")
+}
+
+func TestFaultAuditLogDisabled(t *testing.T) {
+	defer setTestRegion()()
+	defer setTestEnv("ECS_FAULT_AUDIT_LOGFILE_DISABLED", "true")()
+	cfg, err := NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.NoError(t, err)
+	assert.True(t, cfg.FaultAuditLogDisabled, "Wrong value for FaultAuditLogDisabled")
+	assert.False(t, cfg.CredentialsAuditLogDisabled, "Wrong value for CredentialsAuditLogDisabled")
+}
+
 func TestImageCleanupMinimumInterval(t *testing.T) {
 	defer setTestRegion()()
 	defer setTestEnv("ECS_IMAGE_CLEANUP_INTERVAL", "1m")()
@@ -753,6 +771,262 @@ func TestParseImagePullBehavior(t *testing.T) {
 	}
 }
 
+func TestParseImageCleanupPolicy(t *testing.T) {
+	testcases := []struct {
+		name                       string
+		envVarVal                  string
+		expectedImageCleanupPolicy ImageCleanupPolicyType
+	}{
+		{
+			name:                       "unset",
+			envVarVal:                  "",
+			expectedImageCleanupPolicy: ImageCleanupLRUPolicy,
+		},
+		{
+			name:                       "lru",
+			envVarVal:                  "lru",
+			expectedImageCleanupPolicy: ImageCleanupLRUPolicy,
+		},
+		{
+			name:                       "disk usage",
+			envVarVal:                  "disk-usage",
+			expectedImageCleanupPolicy: ImageCleanupDiskUsagePolicy,
+		},
+		{
+			name:                       "size weighted",
+			envVarVal:                  "size-weighted",
+			expectedImageCleanupPolicy: ImageCleanupSizeWeightedPolicy,
+		},
+		{
+			name:                       "keep versions",
+			envVarVal:                  "keep-versions",
+			expectedImageCleanupPolicy: ImageCleanupKeepVersionsPolicy,
+		},
+		{
+			name:                       "invalid",
+			envVarVal:                  "invalid",
+			expectedImageCleanupPolicy: ImageCleanupLRUPolicy,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer setTestEnv("ECS_IMAGE_CLEANUP_POLICY", tc.envVarVal)()
+			assert.Equal(t, tc.expectedImageCleanupPolicy, parseImageCleanupPolicy(), "Wrong value for ImageCleanupPolicy")
+		})
+	}
+}
+
+func TestImageCleanupPolicyConfig(t *testing.T) {
+	defer setTestRegion()()
+	defer setTestEnv("ECS_IMAGE_CLEANUP_POLICY", "disk-usage")()
+	defer setTestEnv("ECS_IMAGE_CLEANUP_DISK_USAGE_THRESHOLD", "70")()
+	defer setTestEnv("ECS_IMAGE_CLEANUP_KEEP_VERSIONS", "3")()
+	cfg, err := NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.NoError(t, err)
+	assert.Equal(t, ImageCleanupDiskUsagePolicy, cfg.ImageCleanupPolicy, "Wrong value for ImageCleanupPolicy")
+	assert.Equal(t, 70, cfg.ImageCleanupDiskUsageThreshold, "Wrong value for ImageCleanupDiskUsageThreshold")
+	assert.Equal(t, 3, cfg.ImageCleanupKeepVersions, "Wrong value for ImageCleanupKeepVersions")
+}
+
+func TestInvalidImageCleanupPolicyConfig(t *testing.T) {
+	defer setTestRegion()()
+	defer setTestEnv("ECS_IMAGE_CLEANUP_DISK_USAGE_THRESHOLD", "150")()
+	defer setTestEnv("ECS_IMAGE_CLEANUP_KEEP_VERSIONS", "-1")()
+	cfg, err := NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultImageCleanupDiskUsageThreshold, cfg.ImageCleanupDiskUsageThreshold, "Wrong value for ImageCleanupDiskUsageThreshold")
+	assert.Equal(t, DefaultImageCleanupKeepVersions, cfg.ImageCleanupKeepVersions, "Wrong value for ImageCleanupKeepVersions")
+}
+
+func TestParseImagePrewarmAuth(t *testing.T) {
+	testcases := []struct {
+		name                     string
+		envVarVal                string
+		expectedImagePrewarmAuth string
+	}{
+		{
+			name:                     "unset",
+			envVarVal:                "",
+			expectedImagePrewarmAuth: "",
+		},
+		{
+			name:                     "ecr",
+			envVarVal:                "ecr",
+			expectedImagePrewarmAuth: ImagePrewarmAuthECR,
+		},
+		{
+			name:                     "engine",
+			envVarVal:                "engine",
+			expectedImagePrewarmAuth: ImagePrewarmAuthEngine,
+		},
+		{
+			name:                     "invalid",
+			envVarVal:                "invalid",
+			expectedImagePrewarmAuth: ImagePrewarmAuthECR,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer setTestEnv("ECS_IMAGE_PREWARM_AUTH", tc.envVarVal)()
+			assert.Equal(t, tc.expectedImagePrewarmAuth, parseImagePrewarmAuth(), "Wrong value for ImagePrewarmAuth")
+		})
+	}
+}
+
+func TestImagePrewarmConfig(t *testing.T) {
+	defer setTestRegion()()
+	defer setTestEnv("ECS_IMAGE_PREWARM_LIST", " busybox:latest, ,nginx:latest,")()
+	defer setTestEnv("ECS_IMAGE_PREWARM_AUTH", "engine")()
+	defer setTestEnv("ECS_IMAGE_PREWARM_PROTECTION_DURATION", "2h")()
+	defer setTestEnv("ECS_ENABLE_IMAGE_PREWARM_API", "true")()
+	cfg, err := NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"busybox:latest", "nginx:latest"}, cfg.ImagePrewarmList, "Wrong value for ImagePrewarmList")
+	assert.Equal(t, ImagePrewarmAuthEngine, cfg.ImagePrewarmAuth, "Wrong value for ImagePrewarmAuth")
+	assert.Equal(t, 2*time.Hour, cfg.ImagePrewarmProtectionDuration, "Wrong value for ImagePrewarmProtectionDuration")
+	assert.True(t, cfg.ImagePrewarmAPIEnabled.Enabled(), "Image pre-warm API should be enabled")
+}
+
+func TestImagePrewarmConfigDefaults(t *testing.T) {
+	defer setTestRegion()()
+	cfg, err := NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.ImagePrewarmList, "Wrong value for ImagePrewarmList")
+	assert.Equal(t, ImagePrewarmAuthECR, cfg.ImagePrewarmAuth, "Wrong value for ImagePrewarmAuth")
+	assert.Equal(t, DefaultImagePrewarmProtectionDuration, cfg.ImagePrewarmProtectionDuration,
+		"Wrong value for ImagePrewarmProtectionDuration")
+	assert.False(t, cfg.ImagePrewarmAPIEnabled.Enabled(), "Image pre-warm API should be disabled")
+}
+
+func TestSupportBundleAPIConfig(t *testing.T) {
+	defer setTestRegion()()
+	cfg, err := NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.NoError(t, err)
+	assert.False(t, cfg.SupportBundleAPIEnabled.Enabled(), "Support bundle API should be disabled by default")
+
+	defer setTestEnv("ECS_ENABLE_SUPPORT_BUNDLE_API", "true")()
+	cfg, err = NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.NoError(t, err)
+	assert.True(t, cfg.SupportBundleAPIEnabled.Enabled(), "Support bundle API should be enabled")
+}
+
+func TestTracingEndpointConfig(t *testing.T) {
+	defer setTestRegion()()
+	cfg, err := NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.TracingEndpoint, "Tracing should be disabled by default")
+
+	defer setTestEnv("ECS_TRACING_ENDPOINT", "http://localhost:4318/v1/traces")()
+	cfg, err = NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:4318/v1/traces", cfg.TracingEndpoint)
+}
+
+func TestEventSinkConfig(t *testing.T) {
+	defer setTestRegion()()
+	cfg, err := NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.EventSinkEndpoint, "Event sink should be disabled by default")
+	assert.Equal(t, DefaultEventSinkQueueSize, cfg.EventSinkQueueSize)
+
+	defer setTestEnv("ECS_EVENT_SINK_ENDPOINT", "unix:///var/run/ecs/events.sock")()
+	defer setTestEnv("ECS_EVENT_SINK_QUEUE_SIZE", "50")()
+	cfg, err = NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.NoError(t, err)
+	assert.Equal(t, "unix:///var/run/ecs/events.sock", cfg.EventSinkEndpoint)
+	assert.Equal(t, 50, cfg.EventSinkQueueSize)
+}
+
+func TestInvalidEventSinkQueueSize(t *testing.T) {
+	defer setTestRegion()()
+	defer setTestEnv("ECS_EVENT_SINK_QUEUE_SIZE", "-1")()
+	cfg, err := NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultEventSinkQueueSize, cfg.EventSinkQueueSize)
+}
+
+func TestRegistryMirrorsConfig(t *testing.T) {
+	defer setTestRegion()()
+	defer setTestEnv("ECS_REGISTRY_MIRRORS",
+		`{"docker.io/*": "mirror.internal/dockerhub/*", "ghcr.io": "mirror.internal/ghcr"}`)()
+	cfg, err := NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.NoError(t, err)
+	// The invalid rule is ignored
+	assert.Equal(t, map[string]string{"docker.io/*": "mirror.internal/dockerhub/*"}, cfg.RegistryMirrors)
+}
+
+func TestInvalidFormatRegistryMirrors(t *testing.T) {
+	defer setTestRegion()()
+	defer setTestEnv("ECS_REGISTRY_MIRRORS", "docker.io/*=mirror.internal/dockerhub/*")()
+	_, err := NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.Error(t, err)
+}
+
+func TestImageTrustPolicyFileConfig(t *testing.T) {
+	defer setTestRegion()()
+	defer setTestEnv("ECS_IMAGE_TRUST_POLICY_FILE", "/etc/ecs/image-trust-policy.json")()
+	cfg, err := NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.NoError(t, err)
+	assert.Equal(t, "/etc/ecs/image-trust-policy.json", cfg.ImageTrustPolicyFile)
+}
+
+func TestSecretFilesConfig(t *testing.T) {
+	defer setTestRegion()()
+	cfg, err := NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.NoError(t, err)
+	assert.False(t, cfg.SecretFilesEnabled.Enabled(), "Secret files should be disabled by default")
+
+	defer setTestEnv("ECS_ENABLE_SECRET_FILES", "true")()
+	defer setTestEnv("ECS_SECRET_FILES_DIR", "/dev/shm/ecs/secrets")()
+	defer setTestEnv("ECS_SECRET_REFRESH_INTERVAL", "5m")()
+	cfg, err = NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.NoError(t, err)
+	assert.True(t, cfg.SecretFilesEnabled.Enabled(), "Secret files should be enabled")
+	assert.Equal(t, "/dev/shm/ecs/secrets", cfg.SecretFilesDir)
+	assert.Equal(t, 5*time.Minute, cfg.SecretRefreshInterval)
+}
+
+func TestInvalidSecretRefreshInterval(t *testing.T) {
+	defer setTestRegion()()
+	defer setTestEnv("ECS_SECRET_REFRESH_INTERVAL", "1s")()
+	cfg, err := NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultSecretRefreshInterval, cfg.SecretRefreshInterval)
+}
+
+func TestTerminationDrainingConfig(t *testing.T) {
+	defer setTestRegion()()
+	cfg, err := NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.NoError(t, err)
+	assert.False(t, cfg.TerminationDrainingEnabled.Enabled(), "Termination draining should be disabled by default")
+	assert.Equal(t, DefaultTerminationDrainingTimeout, cfg.TerminationDrainingTimeout)
+
+	defer setTestEnv("ECS_ENABLE_TERMINATION_DRAINING", "true")()
+	defer setTestEnv("ECS_TERMINATION_DRAINING_TIMEOUT", "3m")()
+	cfg, err = NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.NoError(t, err)
+	assert.True(t, cfg.TerminationDrainingEnabled.Enabled(), "Termination draining should be enabled")
+	assert.Equal(t, 3*time.Minute, cfg.TerminationDrainingTimeout)
+}
+
+func TestInvalidTerminationDrainingTimeout(t *testing.T) {
+	defer setTestRegion()()
+	defer setTestEnv("ECS_TERMINATION_DRAINING_TIMEOUT", "-1s")()
+	cfg, err := NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultTerminationDrainingTimeout, cfg.TerminationDrainingTimeout)
+}
+
+func TestTerminationDrainingTimeoutAboveMaximum(t *testing.T) {
+	defer setTestRegion()()
+	defer setTestEnv("ECS_TERMINATION_DRAINING_TIMEOUT", "10m")()
+	cfg, err := NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.NoError(t, err)
+	assert.Equal(t, maximumTerminationDrainingTimeout, cfg.TerminationDrainingTimeout)
+}
+
 func TestTaskResourceLimitsOverride(t *testing.T) {
 	defer setTestRegion()()
 	defer setTestEnv("ECS_ENABLE_TASK_CPU_MEM_LIMIT", "false")()
@@ -952,6 +1226,14 @@ func TestGPUSupportEnabled(t *testing.T) {
 	assert.True(t, cfg.GPUSupportEnabled, "Wrong value for GPUSupportEnabled")
 }
 
+func TestCDISupportEnabled(t *testing.T) {
+	defer setTestRegion()()
+	defer setTestEnv("ECS_ENABLE_CDI_SUPPORT", "true")()
+	cfg, err := NewConfig(ec2testutil.FakeEC2MetadataClient{})
+	assert.NoError(t, err)
+	assert.True(t, cfg.CDISupportEnabled, "Wrong value for CDISupportEnabled")
+}
+
 func TestInferentiaSupportEnabled(t *testing.T) {
 	defer setTestRegion()()
 	defer setTestEnv("ECS_ENABLE_INF_SUPPORT", "true")()
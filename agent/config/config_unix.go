@@ -38,6 +38,8 @@ const (
 	AgentCredentialsAddress = "" // this is left blank right now for net=bridge
 	// defaultAuditLogFile specifies the default audit log filename
 	defaultCredentialsAuditLogFile = "/log/audit.log"
+	// defaultFaultAuditLogFile specifies the default filename of the audit log of the injected faults
+	defaultFaultAuditLogFile = "/log/fault-audit.log"
 
 	// defaultRuntimeStatsLogFile stores the path where the golang runtime stats are periodically logged
 	defaultRuntimeStatsLogFile = `/log/agent-runtime-stats.log`
@@ -73,6 +75,8 @@ const (
 	nodeStageTimeout = 2 * time.Second
 	// nodeUnstageTimeout is the deafult timeout for unstaging an EBS TA volume
 	nodeUnstageTimeout = 30 * time.Second
+	// defaultSecretFilesDir is on the tmpfs mounted at /run
+	defaultSecretFilesDir = "/run/ecs/secrets"
 )
 
 var (
@@ -103,6 +107,8 @@ func DefaultConfig(ipCompatOverride ipcompatibility.IPCompatibility) Config {
 		DependentContainersPullUpfront:      BooleanDefaultFalse{Value: ExplicitlyDisabled},
 		CredentialsAuditLogFile:             defaultCredentialsAuditLogFile,
 		CredentialsAuditLogDisabled:         false,
+		FaultAuditLogFile:                   defaultFaultAuditLogFile,
+		FaultAuditLogDisabled:               false,
 		ImageCleanupDisabled:                BooleanDefaultFalse{Value: ExplicitlyDisabled},
 		MinimumImageDeletionAge:             DefaultImageDeletionAge,
 		NonECSMinimumImageDeletionAge:       DefaultNonECSImageDeletionAge,
@@ -111,6 +117,12 @@ func DefaultConfig(ipCompatOverride ipcompatibility.IPCompatibility) Config {
 		ImagePullTimeout:                    DefaultImagePullTimeout,
 		NumImagesToDeletePerCycle:           DefaultNumImagesToDeletePerCycle,
 		NumNonECSContainersToDeletePerCycle: DefaultNumNonECSContainersToDeletePerCycle,
+		ImageCleanupDiskUsageThreshold:      DefaultImageCleanupDiskUsageThreshold,
+		ImageCleanupKeepVersions:            DefaultImageCleanupKeepVersions,
+		ImagePrewarmAuth:                    ImagePrewarmAuthECR,
+		ImagePrewarmProtectionDuration:      DefaultImagePrewarmProtectionDuration,
+		ImagePrewarmAPIEnabled:              BooleanDefaultFalse{Value: NotSet},
+		TerminationDrainingTimeout:          DefaultTerminationDrainingTimeout,
 		CNIPluginsPath:                      defaultCNIPluginsPath,
 		PauseContainerTarballPath:           pauseContainerTarballPath,
 		PauseContainerImageName:             DefaultPauseContainerImageName,
@@ -133,6 +145,11 @@ func DefaultConfig(ipCompatOverride ipcompatibility.IPCompatibility) Config {
 		FSxWindowsFileServerCapable:         BooleanDefaultTrue{Value: ExplicitlyDisabled},
 		RuntimeStatsLogFile:                 defaultRuntimeStatsLogFile,
 		EnableRuntimeStats:                  BooleanDefaultFalse{Value: NotSet},
+		SupportBundleAPIEnabled:             BooleanDefaultFalse{Value: NotSet},
+		EventSinkQueueSize:                  DefaultEventSinkQueueSize,
+		SecretFilesEnabled:                  BooleanDefaultFalse{Value: NotSet},
+		SecretFilesDir:                      defaultSecretFilesDir,
+		SecretRefreshInterval:               DefaultSecretRefreshInterval,
 		CSIDriverSocketPath:                 defaultCSIDriverSocketPath,
 		NodeStageTimeout:                    nodeStageTimeout,
 		NodeUnstageTimeout:                  nodeUnstageTimeout,
@@ -39,6 +39,8 @@ const (
 
 	// defaultAuditLogFile specifies the default audit log filename
 	defaultCredentialsAuditLogFile = `log\audit.log`
+	// defaultFaultAuditLogFile specifies the default filename of the audit log of the injected faults
+	defaultFaultAuditLogFile = `log\fault-audit.log`
 
 	// defaultRuntimeStatsLogFile stores the path where the golang runtime stats are periodically logged
 	defaultRuntimeStatsLogFile = `log\agent-runtime-stats.log`
@@ -142,12 +144,20 @@ func DefaultConfig(instanceIPCompatibility ipcompatibility.IPCompatibility) Conf
 		ImagePullTimeout:                    DefaultImagePullTimeout,
 		CredentialsAuditLogFile:             filepath.Join(ecsRoot, defaultCredentialsAuditLogFile),
 		CredentialsAuditLogDisabled:         false,
+		FaultAuditLogFile:                   filepath.Join(ecsRoot, defaultFaultAuditLogFile),
+		FaultAuditLogDisabled:               false,
 		ImageCleanupDisabled:                BooleanDefaultFalse{Value: ExplicitlyDisabled},
 		MinimumImageDeletionAge:             DefaultImageDeletionAge,
 		NonECSMinimumImageDeletionAge:       DefaultNonECSImageDeletionAge,
 		ImageCleanupInterval:                DefaultImageCleanupTimeInterval,
 		NumImagesToDeletePerCycle:           DefaultNumImagesToDeletePerCycle,
 		NumNonECSContainersToDeletePerCycle: DefaultNumNonECSContainersToDeletePerCycle,
+		ImageCleanupDiskUsageThreshold:      DefaultImageCleanupDiskUsageThreshold,
+		ImageCleanupKeepVersions:            DefaultImageCleanupKeepVersions,
+		ImagePrewarmAuth:                    ImagePrewarmAuthECR,
+		ImagePrewarmProtectionDuration:      DefaultImagePrewarmProtectionDuration,
+		ImagePrewarmAPIEnabled:              BooleanDefaultFalse{Value: NotSet},
+		TerminationDrainingTimeout:          DefaultTerminationDrainingTimeout,
 		ContainerMetadataEnabled:            BooleanDefaultFalse{Value: ExplicitlyDisabled},
 		TaskCPUMemLimit:                     BooleanDefaultTrue{Value: ExplicitlyDisabled},
 		PlatformVariables:                   platformVariables,
@@ -164,6 +174,10 @@ func DefaultConfig(instanceIPCompatibility ipcompatibility.IPCompatibility) Conf
 		CNIPluginsPath:                      filepath.Join(ecsBinaryDir, defaultCNIPluginDirName),
 		RuntimeStatsLogFile:                 filepath.Join(ecsRoot, defaultRuntimeStatsLogFile),
 		EnableRuntimeStats:                  BooleanDefaultFalse{Value: NotSet},
+		SupportBundleAPIEnabled:             BooleanDefaultFalse{Value: NotSet},
+		EventSinkQueueSize:                  DefaultEventSinkQueueSize,
+		SecretFilesEnabled:                  BooleanDefaultFalse{Value: NotSet},
+		SecretRefreshInterval:               DefaultSecretRefreshInterval,
 		ShouldExcludeIPv6PortBinding:        BooleanDefaultTrue{Value: ExplicitlyEnabled},
 		CSIDriverSocketPath:                 defaultCSIDriverSocketPath,
 		NodeStageTimeout:                    nodeStageTimeout,
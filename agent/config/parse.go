@@ -213,6 +213,35 @@ func parseImagePullBehavior() ImagePullBehaviorType {
 	}
 }
 
+func parseImageCleanupPolicy() ImageCleanupPolicyType {
+	imageCleanupPolicyString := os.Getenv("ECS_IMAGE_CLEANUP_POLICY")
+	switch imageCleanupPolicyString {
+	case "", "lru":
+		return ImageCleanupLRUPolicy
+	case "disk-usage":
+		return ImageCleanupDiskUsagePolicy
+	case "size-weighted":
+		return ImageCleanupSizeWeightedPolicy
+	case "keep-versions":
+		return ImageCleanupKeepVersionsPolicy
+	default:
+		seelog.Warnf("Invalid value for \"ECS_IMAGE_CLEANUP_POLICY\", expected one of lru, disk-usage, size-weighted or keep-versions, using lru. value: %s", imageCleanupPolicyString)
+		return ImageCleanupLRUPolicy
+	}
+}
+
+func parseImagePrewarmAuth() string {
+	imagePrewarmAuth := os.Getenv("ECS_IMAGE_PREWARM_AUTH")
+	switch imagePrewarmAuth {
+	case "", ImagePrewarmAuthECR, ImagePrewarmAuthEngine:
+		return imagePrewarmAuth
+	default:
+		seelog.Warnf("Invalid value for \"ECS_IMAGE_PREWARM_AUTH\", expected one of %s or %s, using %s. value: %s",
+			ImagePrewarmAuthECR, ImagePrewarmAuthEngine, ImagePrewarmAuthECR, imagePrewarmAuth)
+		return ImagePrewarmAuthECR
+	}
+}
+
 func parseInstanceAttributes(errs []error) (map[string]string, []error) {
 	var instanceAttributes map[string]string
 	instanceAttributesEnv := os.Getenv("ECS_INSTANCE_ATTRIBUTES")
@@ -231,6 +260,21 @@ func parseInstanceAttributes(errs []error) (map[string]string, []error) {
 	return instanceAttributes, errs
 }
 
+func parseRegistryMirrors(errs []error) (map[string]string, []error) {
+	var registryMirrors map[string]string
+	registryMirrorsEnv := os.Getenv("ECS_REGISTRY_MIRRORS")
+	if registryMirrorsEnv != "" {
+		err := json.Unmarshal([]byte(registryMirrorsEnv), &registryMirrors)
+		if err != nil {
+			wrappedErr := fmt.Errorf("Invalid format for ECS_REGISTRY_MIRRORS. Expected a json hash: %v", err)
+			seelog.Error(wrappedErr)
+			errs = append(errs, wrappedErr)
+		}
+	}
+
+	return registryMirrors, errs
+}
+
 func parseAdditionalLocalRoutes(errs []error) ([]cniTypes.IPNet, []error) {
 	var additionalLocalRoutes []cniTypes.IPNet
 	additionalLocalRoutesEnv := os.Getenv("ECS_AWSVPC_ADDITIONAL_LOCAL_ROUTES")
@@ -359,6 +403,18 @@ func parseEnvVariableUint16(envVar string) uint16 {
 	return var16
 }
 
+func parseEnvVariableInt(envVar string) int {
+	envVal := os.Getenv(envVar)
+	if envVal == "" {
+		return 0
+	}
+	val, err := strconv.Atoi(envVal)
+	if err != nil {
+		seelog.Warnf("Invalid format for \"%s\" environment variable; expected an integer. err %v", envVar, err)
+	}
+	return val
+}
+
 func parseEnvVariableDuration(envVar string) time.Duration {
 	var duration time.Duration
 	envVal := os.Getenv(envVar)
@@ -387,6 +443,18 @@ func parseImageCleanupExclusionList(envVar string) []string {
 	return imageCleanupExclusionList
 }
 
+// parseEnvVariableList parses the comma separated list of the environment variable, ignoring the whitespace around
+// the entries and the empty entries
+func parseEnvVariableList(envVar string) []string {
+	var list []string
+	for _, entry := range strings.Split(os.Getenv(envVar), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
+
 func parseCgroupCPUPeriod() time.Duration {
 	duration := parseEnvVariableDuration("ECS_CGROUP_CPU_PERIOD")
 
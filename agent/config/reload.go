@@ -0,0 +1,90 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package config
+
+import (
+	"reflect"
+)
+
+// validLogLevels are the values accepted for LogLevel
+var validLogLevels = []string{"debug", "info", "warn", "error", "crit", "none"}
+
+// reloadableFields are the configuration keys which can be changed without restarting the agent.
+// They are only read by the logger, the image manager, the stats engine and the TMDS rate limiter,
+// which are given the new values, so that the changes don't have to be made to the configuration
+// shared by the rest of the agent.
+var reloadableFields = map[string]struct{}{
+	"LogLevel":                      {},
+	"ImageCleanupInterval":          {},
+	"MinimumImageDeletionAge":       {},
+	"NonECSMinimumImageDeletionAge": {},
+	"NumImagesToDeletePerCycle":     {},
+	"ImageCleanupExclusionList":     {},
+	"TaskMetadataSteadyStateRate":   {},
+	"TaskMetadataBurstRate":         {},
+	"PollMetrics":                   {},
+	"PollingMetricsWaitDuration":    {},
+}
+
+// ConfigChange is a change of the value of a configuration key
+type ConfigChange struct {
+	Name     string
+	OldValue string
+	NewValue string
+	// Reloadable is true if the change can be applied without restarting the agent
+	Reloadable bool
+}
+
+// isValidLogLevel returns whether the log level is one of validLogLevels
+func isValidLogLevel(level string) bool {
+	for _, validLevel := range validLogLevels {
+		if level == validLevel {
+			return true
+		}
+	}
+	return false
+}
+
+// ReloadConfig compares the configuration the agent runs with to the configuration loaded
+// again, and returns a copy of the former with the changes of the reloadable keys applied.
+// The configuration the agent runs with isn't modified.
+// All the changes are returned, the ones which can't be applied without restarting the agent
+// being marked as not reloadable.
+func ReloadConfig(current, reloaded *Config) (*Config, []ConfigChange) {
+	applied := *current
+	appliedElem := reflect.ValueOf(&applied).Elem()
+	reloadedElem := reflect.ValueOf(reloaded).Elem()
+	cfgType := appliedElem.Type()
+
+	var changes []ConfigChange
+	for i := 0; i < appliedElem.NumField(); i++ {
+		currentValue := appliedElem.Field(i).Interface()
+		reloadedValue := reloadedElem.Field(i).Interface()
+		if reflect.DeepEqual(currentValue, reloadedValue) {
+			continue
+		}
+		name := cfgType.Field(i).Name
+		_, reloadable := reloadableFields[name]
+		changes = append(changes, ConfigChange{
+			Name:       name,
+			OldValue:   configValueString(currentValue),
+			NewValue:   configValueString(reloadedValue),
+			Reloadable: reloadable,
+		})
+		if reloadable {
+			appliedElem.Field(i).Set(reloadedElem.Field(i))
+		}
+	}
+	return &applied, changes
+}
@@ -0,0 +1,74 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ecs-agent/ecs-agent/ipcompatibility"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReloadConfig(t *testing.T) {
+	current := DefaultConfig(ipcompatibility.NewIPv4OnlyCompatibility())
+	current.Cluster = "cluster"
+	reloaded := current
+	reloaded.Cluster = "other-cluster"
+	reloaded.LogLevel = "debug"
+	reloaded.ImageCleanupInterval = 2 * time.Hour
+	reloaded.ImageCleanupExclusionList = []string{"image"}
+	reloaded.TaskMetadataBurstRate = 100
+
+	applied, changes := ReloadConfig(&current, &reloaded)
+	assert.ElementsMatch(t, []ConfigChange{
+		{Name: "Cluster", OldValue: "cluster", NewValue: "other-cluster", Reloadable: false},
+		{Name: "LogLevel", OldValue: "", NewValue: "debug", Reloadable: true},
+		{Name: "ImageCleanupInterval", OldValue: "30m0s", NewValue: "2h0m0s", Reloadable: true},
+		{Name: "ImageCleanupExclusionList", OldValue: "[]", NewValue: "[image]", Reloadable: true},
+		{Name: "TaskMetadataBurstRate", OldValue: "60", NewValue: "100", Reloadable: true},
+	}, changes)
+
+	assert.Equal(t, "cluster", applied.Cluster, "changes of keys which aren't reloadable should be ignored")
+	assert.Equal(t, "debug", applied.LogLevel)
+	assert.Equal(t, 2*time.Hour, applied.ImageCleanupInterval)
+	assert.Equal(t, []string{"image"}, applied.ImageCleanupExclusionList)
+	assert.Equal(t, 100, applied.TaskMetadataBurstRate)
+	assert.Equal(t, DefaultImageCleanupTimeInterval, current.ImageCleanupInterval, "the current config should not be modified")
+}
+
+func TestReloadConfigUnchanged(t *testing.T) {
+	current := DefaultConfig(ipcompatibility.NewIPv4OnlyCompatibility())
+	reloaded := current
+	applied, changes := ReloadConfig(&current, &reloaded)
+	assert.Empty(t, changes)
+	assert.Equal(t, current, *applied)
+}
+
+func TestInvalidLogLevelIgnored(t *testing.T) {
+	cfg := DefaultConfig(ipcompatibility.NewIPv4OnlyCompatibility())
+	cfg.Cluster = "cluster"
+	cfg.AWSRegion = "us-west-2"
+	cfg.LogLevel = "verbose"
+	assert.NoError(t, cfg.validateAndOverrideBounds())
+	assert.Equal(t, "", cfg.LogLevel)
+
+	cfg.LogLevel = "warn"
+	assert.NoError(t, cfg.validateAndOverrideBounds())
+	assert.Equal(t, "warn", cfg.LogLevel)
+}
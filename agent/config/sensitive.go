@@ -15,8 +15,12 @@ package config
 
 import (
 	"encoding/json"
+	"reflect"
 )
 
+// redactedRawMessage replaces the contents of the sensitive values in a redacted config
+var redactedRawMessage = json.RawMessage(`"[redacted]"`)
+
 // SensitiveRawMessage is a struct to store some data that should not be logged
 // or printed.
 // This struct is a Stringer which will not print its contents with 'String'.
@@ -55,3 +59,19 @@ func (data *SensitiveRawMessage) UnmarshalJSON(jsonData []byte) error {
 	data.contents = json.RawMessage(jsonData)
 	return nil
 }
+
+// Redacted returns a copy of the config where the contents of every SensitiveRawMessage are
+// replaced, so that it can be marshaled to json without leaking credentials.
+func (cfg *Config) Redacted() *Config {
+	redacted := *cfg
+	sensitiveType := reflect.TypeOf(&SensitiveRawMessage{})
+	cfgElem := reflect.ValueOf(&redacted).Elem()
+	for i := 0; i < cfgElem.NumField(); i++ {
+		field := cfgElem.Field(i)
+		if field.Type() != sensitiveType || field.IsNil() {
+			continue
+		}
+		field.Set(reflect.ValueOf(NewSensitiveRawMessage(redactedRawMessage)))
+	}
+	return &redacted
+}
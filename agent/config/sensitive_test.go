@@ -51,3 +51,20 @@ func TestEmptySensitiveRawMessage(t *testing.T) {
 
 	assert.Nil(t, sensitive, "empty message should return nil")
 }
+
+func TestConfigRedacted(t *testing.T) {
+	cfg := &Config{
+		Cluster:        "cluster",
+		EngineAuthData: NewSensitiveRawMessage(json.RawMessage(`{"registry":{"password":"secret"}}`)),
+	}
+
+	redacted := cfg.Redacted()
+	data, err := json.Marshal(redacted)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "secret")
+	assert.Contains(t, string(data), `"EngineAuthData":"[redacted]"`)
+	assert.Equal(t, "cluster", redacted.Cluster)
+	assert.Contains(t, string(cfg.EngineAuthData.Contents()), "secret", "original config should not be modified")
+
+	assert.Nil(t, (&Config{}).Redacted().EngineAuthData)
+}
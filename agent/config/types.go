@@ -26,6 +26,10 @@ import (
 // behaviors including default, always, never and once.
 type ImagePullBehaviorType int8
 
+// ImageCleanupPolicyType is an enum variable type corresponding to the different policies
+// selecting the images removed by the automated image cleanup.
+type ImageCleanupPolicyType int8
+
 // ContainerInstancePropagateTagsFromType is an enum variable type corresponding to different
 // ways to propagate tags, it includes none (default) and ec2_instance.
 type ContainerInstancePropagateTagsFromType int8
@@ -158,6 +162,17 @@ type Config struct {
 	// TaskCleanupWaitDurationJitter].
 	TaskCleanupWaitDurationJitter time.Duration
 
+	// ContainerRestartBackoffInitial and ContainerRestartBackoffMax specify the restart backoff of the containers
+	// with a restart policy. Restarts are delayed by the initial period at first, and twice as long after every
+	// consecutive restart up to the max period, which defaults to 5 minutes. Restarts aren't delayed if the initial
+	// period isn't set.
+	ContainerRestartBackoffInitial time.Duration
+	ContainerRestartBackoffMax     time.Duration
+
+	// ContainerRestartMaxCount specifies the number of consecutive times containers with a restart policy can be
+	// restarted. They can be restarted any number of times if it's not set.
+	ContainerRestartMaxCount uint16
+
 	// TaskIAMRoleEnabled specifies if the Agent is capable of launching
 	// tasks with IAM Roles.
 	TaskIAMRoleEnabled BooleanDefaultFalse
@@ -174,6 +189,12 @@ type Config struct {
 	// CredentialsAuditLogEnabled specifies whether audit logging is disabled.
 	CredentialsAuditLogDisabled bool
 
+	// FaultAuditLogFile specifies the path/filename of the audit log of the faults injected into tasks.
+	FaultAuditLogFile string
+
+	// FaultAuditLogDisabled specifies whether the audit logging of the faults injected into tasks is disabled.
+	FaultAuditLogDisabled bool
+
 	// TaskIAMRoleEnabledForNetworkHost specifies if the Agent is capable of launching
 	// tasks with IAM Roles when networkMode is set to 'host'
 	TaskIAMRoleEnabledForNetworkHost bool
@@ -209,10 +230,50 @@ type Config struct {
 	// when Agent performs cleanup
 	NumNonECSContainersToDeletePerCycle int
 
+	// ImageCleanupPolicy specifies how the agent selects the images removed every time it
+	// performs cleanup
+	ImageCleanupPolicy ImageCleanupPolicyType
+
+	// ImageCleanupDiskUsageThreshold is the percentage of usage of the disk holding the Docker
+	// data root below which the disk usage image cleanup policy stops removing images
+	ImageCleanupDiskUsageThreshold int
+
+	// ImageCleanupKeepVersions is the number of most recently pulled images of each repository
+	// that the keep versions image cleanup policy never removes
+	ImageCleanupKeepVersions int
+
+	// ImagePrewarmList specifies the images pulled in the background when the agent starts,
+	// before any task uses them
+	ImagePrewarmList []string
+
+	// ImagePrewarmAuth specifies how the agent authenticates to registries when pre-warming
+	// images. If set to ImagePrewarmAuthECR, the images hosted in ECR are pulled with the
+	// instance credentials. Otherwise, the engine auth data is used
+	ImagePrewarmAuth string
+
+	// ImagePrewarmProtectionDuration specifies for how long pre-warmed images are protected
+	// from image cleanup
+	ImagePrewarmProtectionDuration time.Duration
+
+	// ImagePrewarmAPIEnabled specifies whether images can be pre-warmed through the
+	// introspection server
+	ImagePrewarmAPIEnabled BooleanDefaultFalse
+
 	// ImagePullBehavior specifies the agent's behavior for pulling image and loading
 	// local Docker image cache
 	ImagePullBehavior ImagePullBehaviorType
 
+	// RegistryMirrors maps image repository patterns, e.g. "docker.io/*", to the registry mirrors from which the
+	// matching images are pulled, e.g. "mirror.internal/dockerhub/*". The longest matching pattern is applied, and
+	// the image is pulled from its original registry when the pull from the mirror fails. It can be set by the
+	// ECS_REGISTRY_MIRRORS environment variable as a JSON hash.
+	RegistryMirrors map[string]string
+
+	// ImageTrustPolicyFile is the path of the image trust policy file on the host. When set, the signatures of the
+	// images are verified against the policy before the containers are created from them, and the containers whose
+	// image can't be trusted fail. It can be set by the ECS_IMAGE_TRUST_POLICY_FILE environment variable.
+	ImageTrustPolicyFile string
+
 	// InstanceAttributes contains key/value pairs representing
 	// attributes to be associated with this instance within the
 	// ECS service and used to influence behavior such as launch
@@ -306,6 +367,10 @@ type Config struct {
 	// GPUSupportEnabled specifies if the Agent is capable of launching GPU tasks
 	GPUSupportEnabled bool
 
+	// CDISupportEnabled specifies if the Agent advertises the devices of the CDI spec files of the host, and injects
+	// them into the containers requesting them
+	CDISupportEnabled bool
+
 	// EBSTASupportEnabled specifies if the Agent can support tasks needing EBS Task Attach, set in ecs-init
 	// Initially Agent always advertised EBSTA capability. This defaults to true to make it compatible with older ecs-init
 	EBSTASupportEnabled bool
@@ -337,6 +402,15 @@ type Config struct {
 	// see https://docs.aws.amazon.com/AmazonECS/latest/developerguide/container-instance-draining.html
 	SpotInstanceDrainingEnabled BooleanDefaultFalse
 
+	// TerminationDrainingEnabled, if true, agent will set the container instance's state to DRAINING when it receives
+	//   a termination signal, and wait up to TerminationDrainingTimeout for the tasks to be stopped before exiting.
+	// Defaults to false.
+	TerminationDrainingEnabled BooleanDefaultFalse
+
+	// TerminationDrainingTimeout specifies for how long the agent waits for the tasks to be stopped after setting the
+	// container instance's state to DRAINING on termination. Only used when TerminationDrainingEnabled is true.
+	TerminationDrainingTimeout time.Duration
+
 	// GMSACapable is the config option to indicate if gMSA is supported.
 	// It should be enabled by default only if the container instance is part of a valid active directory domain.
 	GMSACapable BooleanDefaultFalse
@@ -362,10 +436,51 @@ type Config struct {
 	// RuntimeStatsLogFile stores the path where the golang runtime stats are periodically logged
 	RuntimeStatsLogFile string
 
+	// LogLevel specifies the level of the agent logs, both on the instance and for the logging
+	// driver. It can only be set in the config file and takes precedence over the ECS_LOGLEVEL
+	// environment variables, but not over the log level flags.
+	LogLevel string `trim:"true"`
+
 	// EnableRuntimeStats specifies if pprof should be enabled through the agent introspection port. By default, this configuration
 	// is set to false and can be overridden by means of the ECS_ENABLE_RUNTIME_STATS environment variable.
 	EnableRuntimeStats BooleanDefaultFalse
 
+	// SupportBundleAPIEnabled specifies whether diagnostic support bundles can be collected through the agent
+	// introspection port. Defaults to false and can be overridden by the ECS_ENABLE_SUPPORT_BUNDLE_API environment variable.
+	SupportBundleAPIEnabled BooleanDefaultFalse
+
+	// TracingEndpoint is the URL of the OTLP/HTTP traces endpoint to which the spans of the task lifecycle operations
+	// are exported, e.g. http://localhost:4318/v1/traces. Tracing is disabled when empty, which is the default. It can
+	// be set by the ECS_TRACING_ENDPOINT environment variable.
+	TracingEndpoint string
+
+	// EventSinkEndpoint is where the task, container, managed agent and attachment state change events are delivered
+	// in addition to ECS. It is either an HTTP(S) URL to which each event is POSTed, or a unix:// or file:// URL to
+	// which the events are written as newline delimited JSON. No event is delivered when empty, which is the default.
+	// It can be set by the ECS_EVENT_SINK_ENDPOINT environment variable.
+	EventSinkEndpoint string
+
+	// EventSinkQueueSize is the maximum number of state change events waiting to be delivered to the event sink, the
+	// events received while the queue is full are dropped. Defaults to 1000 and can be overridden by the
+	// ECS_EVENT_SINK_QUEUE_SIZE environment variable.
+	EventSinkQueueSize int
+
+	// SecretFilesEnabled specifies whether the secrets of type MOUNT_POINT are delivered to the containers as files.
+	// When it's disabled, or SecretFilesDir isn't on a tmpfs when the agent starts, the secrets of type MOUNT_POINT
+	// are ignored. Defaults to false and can be overridden by the ECS_ENABLE_SECRET_FILES environment variable.
+	SecretFilesEnabled BooleanDefaultFalse
+
+	// SecretFilesDir is the directory where the secrets delivered as files are written before being bind mounted
+	// read-only into the containers. It must be on a tmpfs so that the secrets are never written to disk, and be
+	// mounted at the same path in the agent container and on the host. Defaults to /run/ecs/secrets on Linux and can
+	// be overridden by the ECS_SECRET_FILES_DIR environment variable.
+	SecretFilesDir string
+
+	// SecretRefreshInterval is the interval at which the secrets delivered as files are fetched again from SSM
+	// Parameter Store and Secrets Manager while their task runs. Defaults to 15 minutes and can be overridden by the
+	// ECS_SECRET_REFRESH_INTERVAL environment variable.
+	SecretRefreshInterval time.Duration
+
 	// ShouldExcludeIPv6PortBinding specifies whether agent should exclude IPv6 port bindings reported from docker. This configuration
 	// is set to true by default, and can be overridden by the ECS_EXCLUDE_IPV6_PORTBINDING environment variable. This is a workaround
 	// for docker's bug as detailed in https://github.com/aws/amazon-ecs-agent/issues/2870.
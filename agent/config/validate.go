@@ -0,0 +1,283 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/aws/amazon-ecs-agent/ecs-agent/ec2"
+	commonutils "github.com/aws/amazon-ecs-agent/ecs-agent/utils"
+)
+
+const (
+	// ConfigSourceEnvironment is the source of the settings read from the environment
+	ConfigSourceEnvironment = "environment"
+	// ConfigSourceFile is the source of the settings read from the config file
+	ConfigSourceFile = "file"
+	// ConfigSourceUserData is the source of the settings read from the instance user data
+	ConfigSourceUserData = "user-data"
+	// ConfigSourceInstanceMetadata is the source of the settings inferred from the EC2 instance metadata
+	ConfigSourceInstanceMetadata = "instance-metadata"
+	// ConfigSourceDefault is the source of the settings which weren't set and use their default value
+	ConfigSourceDefault = "default"
+	// ConfigSourceOverride is the source of the settings whose configured value was replaced by the agent,
+	// usually because it was invalid
+	ConfigSourceOverride = "override"
+)
+
+// ConfigSetting is the effective value of a configuration key along with where it was read from
+type ConfigSetting struct {
+	// Name is the environment variable setting the key, or the key of the config file when the key can't be set
+	// from the environment
+	Name string `json:"name"`
+	// ConfigFileKey is the key of the config file and of the instance user data setting the key
+	ConfigFileKey string `json:"configFileKey"`
+	Value         string `json:"value"`
+	Source        string `json:"source"`
+}
+
+// ValidationReport is the result of the validation of the agent configuration
+type ValidationReport struct {
+	// Valid is false if the agent would fail to start with the configuration
+	Valid bool `json:"valid"`
+	// Settings lists the effective value of every configuration key
+	Settings []ConfigSetting `json:"settings"`
+	// Deprecated lists the deprecated configuration keys which are set
+	Deprecated []string `json:"deprecated,omitempty"`
+	// Warnings lists the configured values replaced by the agent
+	Warnings []string `json:"warnings,omitempty"`
+	// Errors lists the fatal issues found in the configuration
+	Errors []string `json:"errors,omitempty"`
+}
+
+// configEnvVarNames are the environment variables setting the configuration keys read by environmentConfig
+var configEnvVarNames = map[string]string{
+	"Cluster":                             "ECS_CLUSTER",
+	"APIEndpoint":                         "ECS_BACKEND_HOST",
+	"AWSRegion":                           "AWS_DEFAULT_REGION",
+	"DockerEndpoint":                      "DOCKER_HOST",
+	"ReservedPorts":                       "ECS_RESERVED_PORTS",
+	"ReservedPortsUDP":                    "ECS_RESERVED_PORTS_UDP",
+	"DataDir":                             "ECS_DATADIR",
+	"Checkpoint":                          "ECS_CHECKPOINT",
+	"EngineAuthType":                      "ECS_ENGINE_AUTH_TYPE",
+	"EngineAuthData":                      "ECS_ENGINE_AUTH_DATA",
+	"UpdatesEnabled":                      "ECS_UPDATES_ENABLED",
+	"UpdateDownloadDir":                   "ECS_UPDATE_DOWNLOAD_DIR",
+	"DisableMetrics":                      "ECS_DISABLE_METRICS",
+	"ReservedMemory":                      "ECS_RESERVED_MEMORY",
+	"AvailableLoggingDrivers":             "ECS_AVAILABLE_LOGGING_DRIVERS",
+	"PrivilegedDisabled":                  "ECS_DISABLE_PRIVILEGED",
+	"SELinuxCapable":                      "ECS_SELINUX_CAPABLE",
+	"AppArmorCapable":                     "ECS_APPARMOR_CAPABLE",
+	"TaskCleanupWaitDuration":             "ECS_ENGINE_TASK_CLEANUP_WAIT_DURATION",
+	"TaskCleanupWaitDurationJitter":       "ECS_ENGINE_TASK_CLEANUP_WAIT_DURATION_JITTER",
+	"ContainerRestartBackoffInitial":      "ECS_CONTAINER_RESTART_BACKOFF_INITIAL_PERIOD",
+	"ContainerRestartBackoffMax":          "ECS_CONTAINER_RESTART_BACKOFF_MAX_PERIOD",
+	"ContainerRestartMaxCount":            "ECS_CONTAINER_RESTART_MAX_COUNT",
+	"TaskENIEnabled":                      "ECS_ENABLE_TASK_ENI",
+	"TaskIAMRoleEnabled":                  "ECS_ENABLE_TASK_IAM_ROLE",
+	"DeleteNonECSImagesEnabled":           "ECS_ENABLE_UNTRACKED_IMAGE_CLEANUP",
+	"TaskCPUMemLimit":                     "ECS_ENABLE_TASK_CPU_MEM_LIMIT",
+	"DockerStopTimeout":                   "ECS_CONTAINER_STOP_TIMEOUT",
+	"ManifestPullTimeout":                 "ECS_MANIFEST_PULL_TIMEOUT",
+	"ContainerStartTimeout":               "ECS_CONTAINER_START_TIMEOUT",
+	"ContainerCreateTimeout":              "ECS_CONTAINER_CREATE_TIMEOUT",
+	"DependentContainersPullUpfront":      "ECS_PULL_DEPENDENT_CONTAINERS_UPFRONT",
+	"ImagePullInactivityTimeout":          "ECS_IMAGE_PULL_INACTIVITY_TIMEOUT",
+	"ImagePullTimeout":                    "ECS_IMAGE_PULL_TIMEOUT",
+	"CredentialsAuditLogFile":             "ECS_AUDIT_LOGFILE",
+	"CredentialsAuditLogDisabled":         "ECS_AUDIT_LOGFILE_DISABLED",
+	"FaultAuditLogFile":                   "ECS_FAULT_AUDIT_LOGFILE",
+	"FaultAuditLogDisabled":               "ECS_FAULT_AUDIT_LOGFILE_DISABLED",
+	"TaskIAMRoleEnabledForNetworkHost":    "ECS_ENABLE_TASK_IAM_ROLE_NETWORK_HOST",
+	"ImageCleanupDisabled":                "ECS_DISABLE_IMAGE_CLEANUP",
+	"MinimumImageDeletionAge":             "ECS_IMAGE_MINIMUM_CLEANUP_AGE",
+	"NonECSMinimumImageDeletionAge":       "NON_ECS_IMAGE_MINIMUM_CLEANUP_AGE",
+	"ImageCleanupInterval":                "ECS_IMAGE_CLEANUP_INTERVAL",
+	"NumImagesToDeletePerCycle":           "ECS_NUM_IMAGES_DELETE_PER_CYCLE",
+	"NumNonECSContainersToDeletePerCycle": "NONECS_NUM_CONTAINERS_DELETE_PER_CYCLE",
+	"ImagePullBehavior":                   "ECS_IMAGE_PULL_BEHAVIOR",
+	"RegistryMirrors":                     "ECS_REGISTRY_MIRRORS",
+	"ImageTrustPolicyFile":                "ECS_IMAGE_TRUST_POLICY_FILE",
+	"ImageCleanupPolicy":                  "ECS_IMAGE_CLEANUP_POLICY",
+	"ImageCleanupDiskUsageThreshold":      "ECS_IMAGE_CLEANUP_DISK_USAGE_THRESHOLD",
+	"ImageCleanupKeepVersions":            "ECS_IMAGE_CLEANUP_KEEP_VERSIONS",
+	"ImagePrewarmList":                    "ECS_IMAGE_PREWARM_LIST",
+	"ImagePrewarmAuth":                    "ECS_IMAGE_PREWARM_AUTH",
+	"ImagePrewarmProtectionDuration":      "ECS_IMAGE_PREWARM_PROTECTION_DURATION",
+	"ImagePrewarmAPIEnabled":              "ECS_ENABLE_IMAGE_PREWARM_API",
+	"ImageCleanupExclusionList":           "ECS_EXCLUDE_UNTRACKED_IMAGE",
+	"InstanceAttributes":                  "ECS_INSTANCE_ATTRIBUTES",
+	"CNIPluginsPath":                      "ECS_CNI_PLUGINS_PATH",
+	"AWSVPCBlockInstanceMetdata":          "ECS_AWSVPC_BLOCK_IMDS",
+	"AWSVPCAdditionalLocalRoutes":         "ECS_AWSVPC_ADDITIONAL_LOCAL_ROUTES",
+	"ContainerMetadataEnabled":            "ECS_ENABLE_CONTAINER_METADATA",
+	"DataDirOnHost":                       "ECS_HOST_DATA_DIR",
+	"OverrideAWSLogsExecutionRole":        "ECS_ENABLE_AWSLOGS_EXECUTIONROLE_OVERRIDE",
+	"CgroupPath":                          "ECS_CGROUP_PATH",
+	"TaskMetadataSteadyStateRate":         "ECS_TASK_METADATA_RPS_LIMIT",
+	"TaskMetadataBurstRate":               "ECS_TASK_METADATA_RPS_LIMIT",
+	"SharedVolumeMatchFullConfig":         "ECS_SHARED_VOLUME_MATCH_FULL_CONFIG",
+	"ContainerInstanceTags":               "ECS_CONTAINER_INSTANCE_TAGS",
+	"ContainerInstancePropagateTagsFrom":  "ECS_CONTAINER_INSTANCE_PROPAGATE_TAGS_FROM",
+	"PollMetrics":                         "ECS_POLL_METRICS",
+	"PollingMetricsWaitDuration":          "ECS_POLLING_METRICS_WAIT_DURATION",
+	"DisableDockerHealthCheck":            "ECS_DISABLE_DOCKER_HEALTH_CHECK",
+	"GPUSupportEnabled":                   "ECS_ENABLE_GPU_SUPPORT",
+	"EBSTASupportEnabled":                 "ECS_EBSTA_SUPPORTED",
+	"InferentiaSupportEnabled":            "ECS_ENABLE_INF_SUPPORT",
+	"CDISupportEnabled":                   "ECS_ENABLE_CDI_SUPPORT",
+	"NvidiaRuntime":                       "ECS_NVIDIA_RUNTIME",
+	"TaskMetadataAZDisabled":              "ECS_DISABLE_TASK_METADATA_AZ",
+	"CgroupCPUPeriod":                     "ECS_CGROUP_CPU_PERIOD",
+	"SpotInstanceDrainingEnabled":         "ECS_ENABLE_SPOT_INSTANCE_DRAINING",
+	"TerminationDrainingEnabled":          "ECS_ENABLE_TERMINATION_DRAINING",
+	"TerminationDrainingTimeout":          "ECS_TERMINATION_DRAINING_TIMEOUT",
+	"GMSACapable":                         "ECS_GMSA_SUPPORTED",
+	"GMSADomainlessCapable":               "ECS_GMSA_SUPPORTED",
+	"VolumePluginCapabilities":            "ECS_VOLUME_PLUGIN_CAPABILITIES",
+	"FSxWindowsFileServerCapable":         "ECS_FSX_WINDOWS_FILE_SERVER_SUPPORTED",
+	"External":                            "ECS_EXTERNAL",
+	"EnableRuntimeStats":                  "ECS_ENABLE_RUNTIME_STATS",
+	"SupportBundleAPIEnabled":             "ECS_ENABLE_SUPPORT_BUNDLE_API",
+	"TracingEndpoint":                     "ECS_TRACING_ENDPOINT",
+	"EventSinkEndpoint":                   "ECS_EVENT_SINK_ENDPOINT",
+	"EventSinkQueueSize":                  "ECS_EVENT_SINK_QUEUE_SIZE",
+	"SecretFilesEnabled":                  "ECS_ENABLE_SECRET_FILES",
+	"SecretFilesDir":                      "ECS_SECRET_FILES_DIR",
+	"SecretRefreshInterval":               "ECS_SECRET_REFRESH_INTERVAL",
+	"ShouldExcludeIPv6PortBinding":        "ECS_EXCLUDE_IPV6_PORTBINDING",
+	"WarmPoolsSupport":                    "ECS_WARM_POOLS_CHECK",
+	"DynamicHostPortRange":                "ECS_DYNAMIC_HOST_PORT_RANGE",
+	"TaskPidsLimit":                       "ECS_TASK_PIDS_LIMIT",
+	"FirelensAsyncEnabled":                "ECS_ENABLE_FIRELENS_ASYNC",
+	"InstanceIPCompatibility":             "ECS_INSTANCE_IP_COMPATIBILITY",
+}
+
+// configSettingName returns the name operators set the configuration key with
+func configSettingName(fieldName string) string {
+	if envVarName, ok := configEnvVarNames[fieldName]; ok {
+		return envVarName
+	}
+	return fieldName
+}
+
+// configSource is the configuration read from a single source
+type configSource struct {
+	name string
+	cfg  Config
+}
+
+// ValidateConfig loads the configuration the same way NewConfig does when the agent starts, runs
+// all the validations, and reports the effective value and source of every configuration key.
+func ValidateConfig(ec2client ec2.EC2MetadataClient) *ValidationReport {
+	report := &ValidationReport{Valid: true}
+	cfg, err := NewConfig(ec2client)
+	if err != nil {
+		report.Valid = false
+		report.Errors = append(report.Errors, err.Error())
+	}
+	if cfg == nil {
+		return report
+	}
+
+	// Read each source again on its own to find where the effective values come from
+	envConfig, _ := environmentConfig()
+	fcfg, _ := fileConfig()
+	sources := []configSource{
+		{name: ConfigSourceEnvironment, cfg: envConfig},
+		{name: ConfigSourceFile, cfg: fcfg},
+	}
+	if !envConfig.External.Enabled() {
+		sources = append(sources, configSource{name: ConfigSourceUserData, cfg: userDataConfig(ec2client)})
+	}
+
+	// The environment parsers fill in the default value of some keys, which aren't reported as set in
+	// the environment
+	defaultConfig := reflect.ValueOf(DefaultConfig(cfg.InstanceIPCompatibility))
+
+	cfgElem := reflect.ValueOf(cfg).Elem()
+	cfgType := cfgElem.Type()
+	for i := 0; i < cfgElem.NumField(); i++ {
+		fieldName := cfgType.Field(i).Name
+		settingName := configSettingName(fieldName)
+		value := cfgElem.Field(i).Interface()
+		setting := ConfigSetting{
+			Name:          settingName,
+			ConfigFileKey: fieldName,
+			Value:         configValueString(value),
+			Source:        ConfigSourceDefault,
+		}
+		for _, source := range sources {
+			sourceValue := reflect.ValueOf(source.cfg).Field(i).Interface()
+			if !isConfigValueSet(sourceValue) {
+				continue
+			}
+			if source.name == ConfigSourceEnvironment &&
+				reflect.DeepEqual(sourceValue, defaultConfig.Field(i).Interface()) {
+				continue
+			}
+			setting.Source = source.name
+			if !reflect.DeepEqual(sourceValue, value) {
+				setting.Source = ConfigSourceOverride
+				report.Warnings = append(report.Warnings, fmt.Sprintf("%s: value %q from %s was replaced with %q",
+					settingName, configValueString(sourceValue), source.name, setting.Value))
+			}
+			break
+		}
+		if fieldName == "AWSRegion" && setting.Source == ConfigSourceDefault && cfg.AWSRegion != "" {
+			setting.Source = ConfigSourceInstanceMetadata
+		}
+		report.Settings = append(report.Settings, setting)
+
+		if deprecatedTag := cfgType.Field(i).Tag.Get("deprecated"); deprecatedTag != "" && isConfigValueSet(value) {
+			report.Deprecated = append(report.Deprecated, fmt.Sprintf("%s: %s", settingName, deprecatedTag))
+		}
+	}
+	return report
+}
+
+// isConfigValueSet returns whether the config value is set, the same way Merge decides whether to
+// override it
+func isConfigValueSet(value interface{}) bool {
+	switch v := value.(type) {
+	case BooleanDefaultFalse:
+		return v.Value == ExplicitlyEnabled || v.Value == ExplicitlyDisabled
+	case BooleanDefaultTrue:
+		return v.Value == ExplicitlyEnabled || v.Value == ExplicitlyDisabled
+	default:
+		return !commonutils.ZeroOrNil(value)
+	}
+}
+
+// configValueString returns a human readable representation of the config value. Sensitive values
+// are redacted.
+func configValueString(value interface{}) string {
+	switch v := value.(type) {
+	case BooleanDefaultFalse:
+		return fmt.Sprint(v.Enabled())
+	case BooleanDefaultTrue:
+		return fmt.Sprint(v.Enabled())
+	}
+	val := reflect.ValueOf(value)
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return ""
+		}
+		value = val.Elem().Interface()
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,102 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	ec2testutil "github.com/aws/amazon-ecs-agent/agent/utils/test/ec2util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findConfigSetting(t *testing.T, report *ValidationReport, name string) ConfigSetting {
+	for _, setting := range report.Settings {
+		if setting.Name == name {
+			return setting
+		}
+	}
+	require.Failf(t, "setting not found", "no setting named %s in the report", name)
+	return ConfigSetting{}
+}
+
+func TestValidateConfig(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "ecs.config.json")
+	require.NoError(t, os.WriteFile(filePath,
+		[]byte(`{"ClusterArn":"file-cluster","ReservedMemory":100,"EngineAuthData":{"auth":"secret"}}`), 0644))
+	defer setTestEnv("ECS_AGENT_CONFIG_FILE_PATH", filePath)()
+	defer setTestRegion()()
+	defer setTestEnv("ECS_ENGINE_AUTH_TYPE", "dockercfg")()
+	defer setTestEnv("ECS_IMAGE_CLEANUP_INTERVAL", "1s")()
+
+	report := ValidateConfig(ec2testutil.FakeEC2MetadataClient{})
+
+	assert.True(t, report.Valid)
+	assert.Empty(t, report.Errors)
+	assert.Equal(t, ConfigSetting{Name: "AWS_DEFAULT_REGION", ConfigFileKey: "AWSRegion", Value: "us-west-2",
+		Source: ConfigSourceEnvironment}, findConfigSetting(t, report, "AWS_DEFAULT_REGION"))
+	assert.Equal(t, ConfigSetting{Name: "ECS_CLUSTER", ConfigFileKey: "Cluster", Value: "file-cluster",
+		Source: ConfigSourceFile}, findConfigSetting(t, report, "ECS_CLUSTER"))
+	assert.Equal(t, ConfigSetting{Name: "ECS_RESERVED_MEMORY", ConfigFileKey: "ReservedMemory", Value: "100",
+		Source: ConfigSourceFile}, findConfigSetting(t, report, "ECS_RESERVED_MEMORY"))
+	assert.Equal(t, ConfigSetting{Name: "ECS_ENGINE_AUTH_DATA", ConfigFileKey: "EngineAuthData", Value: "[redacted]",
+		Source: ConfigSourceFile}, findConfigSetting(t, report, "ECS_ENGINE_AUTH_DATA"))
+	assert.Equal(t, ConfigSetting{Name: "ECS_IMAGE_CLEANUP_INTERVAL", ConfigFileKey: "ImageCleanupInterval",
+		Value: DefaultImageCleanupTimeInterval.String(), Source: ConfigSourceOverride},
+		findConfigSetting(t, report, "ECS_IMAGE_CLEANUP_INTERVAL"))
+	assert.Equal(t, ConfigSetting{Name: "ECS_NUM_IMAGES_DELETE_PER_CYCLE", ConfigFileKey: "NumImagesToDeletePerCycle",
+		Value: "5", Source: ConfigSourceDefault}, findConfigSetting(t, report, "ECS_NUM_IMAGES_DELETE_PER_CYCLE"))
+	assert.Equal(t, []string{"ClusterArn: Please use Cluster instead"}, report.Deprecated)
+	assert.Equal(t, []string{`ECS_IMAGE_CLEANUP_INTERVAL: value "1s" from environment was replaced with "30m0s"`},
+		report.Warnings)
+}
+
+func TestValidateConfigMissingRegion(t *testing.T) {
+	defer setTestEnv("ECS_AGENT_CONFIG_FILE_PATH", filepath.Join(t.TempDir(), "ecs.config.json"))()
+	defer setTestEnv("AWS_DEFAULT_REGION", "")()
+
+	report := ValidateConfig(ec2testutil.FakeEC2MetadataClient{})
+
+	assert.False(t, report.Valid)
+	require.Len(t, report.Errors, 1)
+	assert.Contains(t, report.Errors[0], "Missing required fields: AWSRegion")
+	assert.Equal(t, ConfigSetting{Name: "AWS_DEFAULT_REGION", ConfigFileKey: "AWSRegion", Value: "",
+		Source: ConfigSourceDefault}, findConfigSetting(t, report, "AWS_DEFAULT_REGION"))
+}
+
+func TestValidateConfigExternalWithoutRegion(t *testing.T) {
+	defer setTestEnv("ECS_EXTERNAL", "true")()
+	defer setTestEnv("AWS_DEFAULT_REGION", "")()
+
+	report := ValidateConfig(ec2testutil.FakeEC2MetadataClient{})
+
+	assert.False(t, report.Valid)
+	assert.Equal(t, []string{"AWS_DEFAULT_REGION has to be set when running on external capacity"}, report.Errors)
+	assert.Empty(t, report.Settings)
+}
+
+func TestConfigEnvVarNames(t *testing.T) {
+	cfgType := reflect.TypeOf(Config{})
+	for fieldName := range configEnvVarNames {
+		_, ok := cfgType.FieldByName(fieldName)
+		assert.True(t, ok, "%s is not a configuration key", fieldName)
+	}
+}
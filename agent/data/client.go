@@ -27,6 +27,7 @@ import (
 	generaldata "github.com/aws/amazon-ecs-agent/ecs-agent/data"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/modeltransformer"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/netlib/model/networkinterface"
+	faulttypes "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/types"
 	bolt "go.etcd.io/bbolt"
 )
 
@@ -40,6 +41,7 @@ const (
 	eniAttachmentsBucketName = "eniattachments"
 	resAttachmentsBucketName = "resattachments"
 	metadataBucketName       = "metadata"
+	faultsBucketName         = "faults"
 	emptyAgentVersionMsg     = "No version info available in boltDB. Either this is a fresh instance, or we were using state file to persist data. Transformer not applicable."
 	notDirectoryErrorMsg     = "path %s is not a valid directory"
 )
@@ -55,6 +57,7 @@ var (
 		eniAttachmentsBucketName,
 		resAttachmentsBucketName,
 		metadataBucketName,
+		faultsBucketName,
 	}
 	dirExists = checkDirectoryExists
 )
@@ -102,6 +105,13 @@ type Client interface {
 	// GetResourceAttachments gets the data of all the resouce attachments.
 	GetResourceAttachments() ([]*resource.ResourceAttachment, error)
 
+	// SaveFault saves the data of a fault injected into a task.
+	SaveFault(*faulttypes.InjectedFault) error
+	// DeleteFault deletes the data of an injected fault.
+	DeleteFault(string) error
+	// GetFaults gets the data of all the injected faults.
+	GetFaults() ([]*faulttypes.InjectedFault, error)
+
 	// SaveMetadata saves a key value pair of metadata.
 	SaveMetadata(string, string) error
 	// GetMetadata gets the value of a certain kind of metadata.
@@ -0,0 +1,56 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package data
+
+import (
+	"encoding/json"
+
+	faulttypes "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/types"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+func (c *client) SaveFault(fault *faulttypes.InjectedFault) error {
+	if fault.ID == "" {
+		return errors.New("failed to generate database id: fault ID is empty")
+	}
+	return c.DB.Batch(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(faultsBucketName))
+		return c.Accessor.PutObject(b, fault.ID, fault)
+	})
+}
+
+func (c *client) DeleteFault(id string) error {
+	return c.DB.Batch(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(faultsBucketName))
+		return b.Delete([]byte(id))
+	})
+}
+
+func (c *client) GetFaults() ([]*faulttypes.InjectedFault, error) {
+	var faults []*faulttypes.InjectedFault
+	err := c.DB.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(faultsBucketName))
+		return c.Accessor.Walk(bucket, func(id string, data []byte) error {
+			fault := faulttypes.InjectedFault{}
+			if err := json.Unmarshal(data, &fault); err != nil {
+				return err
+			}
+			faults = append(faults, &fault)
+			return nil
+		})
+	})
+	return faults, err
+}
@@ -0,0 +1,79 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package data
+
+import (
+	"testing"
+	"time"
+
+	faulttypes "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/types"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/v4/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testFaultID  = "/var/run/netns/test/network-latency"
+	testFaultID2 = "/var/run/netns/test/ingress-tcp-80"
+)
+
+func TestManageFaults(t *testing.T) {
+	testClient := newTestClient(t)
+
+	expiresAt := time.Now().Add(time.Minute).UTC().Truncate(time.Second)
+	testFault := &faulttypes.InjectedFault{
+		ID:        testFaultID,
+		TaskARN:   testTaskArn1,
+		FaultType: faulttypes.LatencyFaultType,
+		TaskNetworkConfig: &state.TaskNetworkConfig{
+			NetworkMode: "awsvpc",
+			NetworkNamespaces: []*state.NetworkNamespace{
+				{Path: "/var/run/netns/test"},
+			},
+		},
+		ExpiresAt: &expiresAt,
+	}
+	require.NoError(t, testClient.SaveFault(testFault))
+	res, err := testClient.GetFaults()
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, testFaultID, res[0].ID)
+	assert.Equal(t, testTaskArn1, res[0].TaskARN)
+	assert.Equal(t, "/var/run/netns/test", res[0].NetworkNamespacePath())
+	assert.True(t, expiresAt.Equal(*res[0].ExpiresAt))
+
+	testFault2 := &faulttypes.InjectedFault{
+		ID:          testFaultID2,
+		TaskARN:     testTaskArn1,
+		FaultType:   faulttypes.BlackHolePortFaultType,
+		Protocol:    "tcp",
+		Port:        "80",
+		TrafficType: "ingress",
+	}
+	require.NoError(t, testClient.SaveFault(testFault2))
+	res, err = testClient.GetFaults()
+	require.NoError(t, err)
+	assert.Len(t, res, 2)
+
+	assert.NoError(t, testClient.DeleteFault(testFaultID))
+	assert.NoError(t, testClient.DeleteFault(testFaultID2))
+	res, err = testClient.GetFaults()
+	require.NoError(t, err)
+	assert.Len(t, res, 0)
+
+	assert.Error(t, testClient.SaveFault(&faulttypes.InjectedFault{}))
+}
@@ -0,0 +1,380 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
+	"github.com/aws/amazon-ecs-agent/agent/data/transformationfunctions"
+	"github.com/aws/amazon-ecs-agent/agent/utils"
+	"github.com/aws/amazon-ecs-agent/agent/version"
+	generaldata "github.com/aws/amazon-ecs-agent/ecs-agent/data"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/modeltransformer"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// inspectorOpenTimeout is how long the inspector waits for the lock on the database, which is held
+	// by the agent while it's running
+	inspectorOpenTimeout = time.Second
+	modelTypeTask        = "Task"
+)
+
+// Inspector reads and repairs the agent database while the agent is stopped. Unlike the data client,
+// it never creates the database or its buckets, and it leaves the stored models untouched unless
+// records are explicitly removed.
+type Inspector struct {
+	generaldata.Client
+}
+
+// StateSummary summarizes the state stored in the agent database
+type StateSummary struct {
+	// AgentVersion is the version of the agent which last saved the state
+	AgentVersion string `json:"agentVersion"`
+	// Metadata holds the records of the metadata bucket
+	Metadata map[string]string `json:"metadata"`
+	// RecordCounts is the number of records in each bucket
+	RecordCounts map[string]int     `json:"recordCounts"`
+	Tasks        []TaskSummary      `json:"tasks"`
+	Containers   []ContainerSummary `json:"containers"`
+}
+
+// TaskSummary summarizes a task stored in the agent database
+type TaskSummary struct {
+	ID            string `json:"id"`
+	Arn           string `json:"arn"`
+	Family        string `json:"family"`
+	Version       string `json:"version"`
+	KnownStatus   string `json:"knownStatus"`
+	DesiredStatus string `json:"desiredStatus"`
+}
+
+// ContainerSummary summarizes a container stored in the agent database
+type ContainerSummary struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	TaskArn       string `json:"taskArn"`
+	DockerID      string `json:"dockerId"`
+	KnownStatus   string `json:"knownStatus"`
+	DesiredStatus string `json:"desiredStatus"`
+}
+
+// TransformReport is the result of a dry run of the task model transformations
+type TransformReport struct {
+	// AgentVersionInDB is the version of the agent which last saved the state
+	AgentVersionInDB string `json:"agentVersionInDB"`
+	// AgentVersion is the version of the running agent binary
+	AgentVersion string `json:"agentVersion"`
+	// Upgrade is true if the tasks are transformed when they're loaded by the agent
+	Upgrade bool `json:"upgrade"`
+	// NumTransformationFunctions is the number of task transformation functions registered
+	NumTransformationFunctions int                   `json:"numTransformationFunctions"`
+	Tasks                      []TaskTransformResult `json:"tasks"`
+}
+
+// TaskTransformResult is the result of the transformation of a single task
+type TaskTransformResult struct {
+	ID string `json:"id"`
+	// Changed is true if the transformations change the stored model
+	Changed bool `json:"changed"`
+	// Error is set if the transformed model can't be transformed or loaded by the agent
+	Error string `json:"error,omitempty"`
+}
+
+// OrphanedRecord is a record which belongs to a task missing from the tasks bucket
+type OrphanedRecord struct {
+	Bucket  string `json:"bucket"`
+	Key     string `json:"key"`
+	TaskArn string `json:"taskArn"`
+}
+
+// NewInspector opens the agent database in the data directory. The database is opened read-only
+// unless records need to be removed. It fails if the agent is running, since the agent holds the
+// lock on the database.
+func NewInspector(dataDir string, readOnly bool) (*Inspector, error) {
+	dbPath := filepath.Join(dataDir, dbName)
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, errors.Wrapf(err, "unable to find the agent database")
+	}
+	db, err := bolt.Open(dbPath, dbMode, &bolt.Options{
+		ReadOnly: readOnly,
+		Timeout:  inspectorOpenTimeout,
+	})
+	if err == bolt.ErrTimeout {
+		return nil, errors.Errorf("unable to lock the agent database %s, make sure the agent is stopped", dbPath)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open the agent database %s", dbPath)
+	}
+	transformer := modeltransformer.NewTransformer()
+	transformationfunctions.RegisterTaskTransformationFunctions(transformer)
+	return &Inspector{
+		generaldata.Client{
+			Accessor:    generaldata.DBAccessor{},
+			DB:          db,
+			Transformer: transformer,
+		},
+	}, nil
+}
+
+// Close closes the agent database.
+func (i *Inspector) Close() error {
+	return i.DB.Close()
+}
+
+// Buckets returns the names of the buckets of the agent database.
+func Buckets() []string {
+	return append([]string{}, buckets...)
+}
+
+// Dump returns the records of the buckets, keyed by bucket name and record key. All the buckets
+// are returned if no bucket names are given. The buckets missing from the database are empty.
+func (i *Inspector) Dump(bucketNames []string) (map[string]map[string]json.RawMessage, error) {
+	if len(bucketNames) == 0 {
+		bucketNames = buckets
+	}
+	dump := make(map[string]map[string]json.RawMessage)
+	err := i.DB.View(func(tx *bolt.Tx) error {
+		for _, bucketName := range bucketNames {
+			if !isKnownBucket(bucketName) {
+				return errors.Errorf("unknown bucket %s", bucketName)
+			}
+			records := make(map[string]json.RawMessage)
+			err := i.walkBucket(tx, bucketName, func(id string, data []byte) error {
+				records[id] = append(json.RawMessage{}, data...)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			dump[bucketName] = records
+		}
+		return nil
+	})
+	return dump, err
+}
+
+// Summary returns a summary of the tasks and containers stored in the agent database.
+func (i *Inspector) Summary() (*StateSummary, error) {
+	summary := &StateSummary{
+		Metadata:     make(map[string]string),
+		RecordCounts: make(map[string]int),
+		Tasks:        []TaskSummary{},
+		Containers:   []ContainerSummary{},
+	}
+	err := i.DB.View(func(tx *bolt.Tx) error {
+		for _, bucketName := range buckets {
+			if bucket := tx.Bucket([]byte(bucketName)); bucket != nil {
+				summary.RecordCounts[bucketName] = bucket.Stats().KeyN
+			} else {
+				summary.RecordCounts[bucketName] = 0
+			}
+		}
+
+		err := i.walkBucket(tx, metadataBucketName, func(id string, data []byte) error {
+			var val string
+			if err := json.Unmarshal(data, &val); err != nil {
+				return errors.Wrapf(err, "failed to unmarshal metadata %q", id)
+			}
+			summary.Metadata[id] = val
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		summary.AgentVersion = summary.Metadata[AgentVersionKey]
+
+		err = i.walkBucket(tx, tasksBucketName, func(id string, data []byte) error {
+			task := apitask.Task{}
+			if err := json.Unmarshal(data, &task); err != nil {
+				return errors.Wrapf(err, "failed to unmarshal task %q", id)
+			}
+			summary.Tasks = append(summary.Tasks, TaskSummary{
+				ID:            id,
+				Arn:           task.Arn,
+				Family:        task.Family,
+				Version:       task.Version,
+				KnownStatus:   task.GetKnownStatus().String(),
+				DesiredStatus: task.GetDesiredStatus().String(),
+			})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return i.walkBucket(tx, containersBucketName, func(id string, data []byte) error {
+			dockerContainer := apicontainer.DockerContainer{}
+			if err := json.Unmarshal(data, &dockerContainer); err != nil {
+				return errors.Wrapf(err, "failed to unmarshal container %q", id)
+			}
+			containerSummary := ContainerSummary{
+				ID:       id,
+				DockerID: dockerContainer.DockerID,
+			}
+			if container := dockerContainer.Container; container != nil {
+				containerSummary.Name = container.Name
+				containerSummary.TaskArn = container.GetTaskARN()
+				containerSummary.KnownStatus = container.GetKnownStatus().String()
+				containerSummary.DesiredStatus = container.GetDesiredStatus().String()
+			}
+			summary.Containers = append(summary.Containers, containerSummary)
+			return nil
+		})
+	})
+	return summary, err
+}
+
+// TransformDryRun runs the task model transformations the agent runs when it loads the tasks after
+// an upgrade, without saving the transformed models.
+func (i *Inspector) TransformDryRun() (*TransformReport, error) {
+	report := &TransformReport{
+		AgentVersion:               version.Version,
+		NumTransformationFunctions: i.Transformer.GetNumberOfTransformationFunctions(modelTypeTask),
+		Tasks:                      []TaskTransformResult{},
+	}
+	err := i.DB.View(func(tx *bolt.Tx) error {
+		if err := i.Accessor.GetObject(tx, metadataBucketName, AgentVersionKey, &report.AgentVersionInDB); err != nil {
+			// Without the agent version, the agent loads the tasks without transforming them
+			return nil
+		}
+		report.Upgrade = i.Transformer.IsUpgrade(version.Version, report.AgentVersionInDB)
+		if !report.Upgrade {
+			return nil
+		}
+		return i.walkBucket(tx, tasksBucketName, func(id string, data []byte) error {
+			result := TaskTransformResult{ID: id}
+			transformed, err := i.Transformer.TransformTask(report.AgentVersionInDB, data)
+			if err == nil {
+				err = json.Unmarshal(transformed, &apitask.Task{})
+			}
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Changed = !bytes.Equal(data, transformed)
+			}
+			report.Tasks = append(report.Tasks, result)
+			return nil
+		})
+	})
+	return report, err
+}
+
+// Orphans returns the container, attachment and fault records which belong to a task missing from
+// the tasks bucket.
+func (i *Inspector) Orphans() ([]OrphanedRecord, error) {
+	orphans := []OrphanedRecord{}
+	err := i.DB.View(func(tx *bolt.Tx) error {
+		tasksBucket := tx.Bucket([]byte(tasksBucketName))
+		isOrphan := func(taskArn string) bool {
+			if taskArn == "" {
+				// Records which don't belong to a task, such as the trunk ENI attachment
+				return false
+			}
+			taskID, err := utils.GetTaskID(taskArn)
+			return err != nil || tasksBucket == nil || tasksBucket.Get([]byte(taskID)) == nil
+		}
+
+		err := i.walkBucket(tx, containersBucketName, func(id string, data []byte) error {
+			var record struct {
+				Container struct {
+					TaskARN string `json:"taskARN"`
+				}
+			}
+			if err := json.Unmarshal(data, &record); err != nil {
+				return errors.Wrapf(err, "failed to unmarshal container %q", id)
+			}
+			if isOrphan(record.Container.TaskARN) {
+				orphans = append(orphans, OrphanedRecord{
+					Bucket:  containersBucketName,
+					Key:     id,
+					TaskArn: record.Container.TaskARN,
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, bucketName := range []string{eniAttachmentsBucketName, resAttachmentsBucketName, faultsBucketName} {
+			bucketName := bucketName
+			err = i.walkBucket(tx, bucketName, func(id string, data []byte) error {
+				// Attachments and faults all have a task ARN field, named taskArn for attachments and
+				// TaskARN for faults
+				var record struct {
+					TaskARN string
+				}
+				if err := json.Unmarshal(data, &record); err != nil {
+					return errors.Wrapf(err, "failed to unmarshal record %q of bucket %s", id, bucketName)
+				}
+				if isOrphan(record.TaskARN) {
+					orphans = append(orphans, OrphanedRecord{
+						Bucket:  bucketName,
+						Key:     id,
+						TaskArn: record.TaskARN,
+					})
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return orphans, err
+}
+
+// Remove removes a record from a bucket. It fails if the record doesn't exist.
+func (i *Inspector) Remove(bucketName, key string) error {
+	return i.DB.Update(func(tx *bolt.Tx) error {
+		bucket, err := i.Accessor.GetBucket(tx, bucketName)
+		if err != nil {
+			return err
+		}
+		if bucket.Get([]byte(key)) == nil {
+			return errors.Errorf("object %s not found in bucket %s", key, bucketName)
+		}
+		return bucket.Delete([]byte(key))
+	})
+}
+
+// isKnownBucket returns whether the bucket is one of the buckets of the agent database
+func isKnownBucket(bucketName string) bool {
+	for _, name := range buckets {
+		if name == bucketName {
+			return true
+		}
+	}
+	return false
+}
+
+// walkBucket calls the callback for every record of the bucket. A bucket missing from the database, such as
+// a bucket added after the agent which saved the state was released, has no records.
+func (i *Inspector) walkBucket(tx *bolt.Tx, bucketName string, callback func(id string, data []byte) error) error {
+	bucket := tx.Bucket([]byte(bucketName))
+	if bucket == nil {
+		return nil
+	}
+	return i.Accessor.Walk(bucket, callback)
+}
@@ -0,0 +1,164 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package data
+
+import (
+	"path/filepath"
+	"testing"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+// setupInspectorTestDB saves a task with a container, and an orphaned container of a task which
+// isn't saved
+func setupInspectorTestDB(t *testing.T) string {
+	dataDir := t.TempDir()
+	testClient, err := NewWithSetup(dataDir)
+	require.NoError(t, err)
+	defer testClient.Close()
+
+	container := &apicontainer.Container{Name: "container"}
+	require.NoError(t, testClient.SaveTask(&apitask.Task{
+		Arn:        testTaskArn1,
+		Family:     "family",
+		Containers: []*apicontainer.Container{container},
+	}))
+	container.SetTaskARN(testTaskArn1)
+	require.NoError(t, testClient.SaveContainer(container))
+
+	orphanedContainer := &apicontainer.Container{Name: "orphan"}
+	orphanedContainer.SetTaskARN(testTaskArn2)
+	require.NoError(t, testClient.SaveContainer(orphanedContainer))
+	require.NoError(t, testClient.SaveMetadata(AgentVersionKey, "1.0.0"))
+	return dataDir
+}
+
+func TestInspectorMissingDatabase(t *testing.T) {
+	_, err := NewInspector(t.TempDir(), true)
+	assert.Error(t, err)
+}
+
+func TestInspectorDump(t *testing.T) {
+	inspector, err := NewInspector(setupInspectorTestDB(t), true)
+	require.NoError(t, err)
+	defer inspector.Close()
+
+	dump, err := inspector.Dump(nil)
+	require.NoError(t, err)
+	assert.Len(t, dump, len(buckets))
+	assert.Contains(t, dump[tasksBucketName], "abc")
+	assert.Len(t, dump[containersBucketName], 2)
+
+	dump, err = inspector.Dump([]string{metadataBucketName})
+	require.NoError(t, err)
+	assert.Len(t, dump, 1)
+	assert.JSONEq(t, `"1.0.0"`, string(dump[metadataBucketName][AgentVersionKey]))
+
+	_, err = inspector.Dump([]string{"unknown"})
+	assert.Error(t, err)
+}
+
+func TestInspectorSummary(t *testing.T) {
+	inspector, err := NewInspector(setupInspectorTestDB(t), true)
+	require.NoError(t, err)
+	defer inspector.Close()
+
+	summary, err := inspector.Summary()
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", summary.AgentVersion)
+	assert.Equal(t, 1, summary.RecordCounts[tasksBucketName])
+	assert.Equal(t, 2, summary.RecordCounts[containersBucketName])
+	require.Len(t, summary.Tasks, 1)
+	assert.Equal(t, testTaskArn1, summary.Tasks[0].Arn)
+	assert.Equal(t, "family", summary.Tasks[0].Family)
+	require.Len(t, summary.Containers, 2)
+	assert.Equal(t, "abc-container", summary.Containers[0].ID)
+	assert.Equal(t, testTaskArn1, summary.Containers[0].TaskArn)
+}
+
+func TestInspectorTransformDryRun(t *testing.T) {
+	inspector, err := NewInspector(setupInspectorTestDB(t), true)
+	require.NoError(t, err)
+	defer inspector.Close()
+
+	report, err := inspector.TransformDryRun()
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", report.AgentVersionInDB)
+	assert.True(t, report.Upgrade)
+	require.Len(t, report.Tasks, 1)
+	assert.Equal(t, "abc", report.Tasks[0].ID)
+	assert.Empty(t, report.Tasks[0].Error)
+}
+
+func TestInspectorOrphansAndRemove(t *testing.T) {
+	dataDir := setupInspectorTestDB(t)
+	inspector, err := NewInspector(dataDir, true)
+	require.NoError(t, err)
+	orphans, err := inspector.Orphans()
+	require.NoError(t, err)
+	assert.Equal(t, []OrphanedRecord{{
+		Bucket:  containersBucketName,
+		Key:     "def-orphan",
+		TaskArn: testTaskArn2,
+	}}, orphans)
+	assert.Error(t, inspector.Remove(containersBucketName, "def-orphan"), "read-only database can't be updated")
+	require.NoError(t, inspector.Close())
+
+	inspector, err = NewInspector(dataDir, false)
+	require.NoError(t, err)
+	defer inspector.Close()
+	require.NoError(t, inspector.Remove(containersBucketName, "def-orphan"))
+	assert.Error(t, inspector.Remove(containersBucketName, "def-orphan"))
+
+	orphans, err = inspector.Orphans()
+	require.NoError(t, err)
+	assert.Empty(t, orphans)
+}
+
+// TestInspectorMissingBucket tests the database of an agent which predates the faults bucket
+func TestInspectorMissingBucket(t *testing.T) {
+	dataDir := setupInspectorTestDB(t)
+	db, err := bolt.Open(filepath.Join(dataDir, dbName), dbMode, nil)
+	require.NoError(t, err)
+	require.NoError(t, db.Update(func(tx *bolt.Tx) error {
+		return tx.DeleteBucket([]byte(faultsBucketName))
+	}))
+	require.NoError(t, db.Close())
+
+	inspector, err := NewInspector(dataDir, true)
+	require.NoError(t, err)
+	defer inspector.Close()
+
+	dump, err := inspector.Dump(nil)
+	require.NoError(t, err)
+	assert.Empty(t, dump[faultsBucketName])
+	assert.Len(t, dump[containersBucketName], 2)
+
+	summary, err := inspector.Summary()
+	require.NoError(t, err)
+	assert.Equal(t, 0, summary.RecordCounts[faultsBucketName])
+	assert.Len(t, summary.Tasks, 1)
+
+	orphans, err := inspector.Orphans()
+	require.NoError(t, err)
+	assert.Len(t, orphans, 1)
+}
@@ -25,6 +25,10 @@ const (
 	EC2InstanceIDKey        = "ec2-instance-id"
 	TaskManifestSeqNumKey   = "task-manifest-seq-num"
 
+	// DrainedContainerInstanceARNKey tracks the container instance that the agent set to DRAINING when the
+	// instance was shutting down, so that it's set back to ACTIVE when the agent starts again.
+	DrainedContainerInstanceARNKey = "drained-container-instance-arn"
+
 	// IMDSIAMRolesKey tracks whether this instance had previously enabled support
 	// for IMDS-based task credential retrieval.
 	//
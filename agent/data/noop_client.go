@@ -19,6 +19,7 @@ import (
 	"github.com/aws/amazon-ecs-agent/agent/engine/image"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/api/attachment/resource"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/netlib/model/networkinterface"
+	faulttypes "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/types"
 )
 
 type noopClient struct{}
@@ -93,6 +94,18 @@ func (c *noopClient) GetResourceAttachments() ([]*resource.ResourceAttachment, e
 	return nil, nil
 }
 
+func (c *noopClient) SaveFault(*faulttypes.InjectedFault) error {
+	return nil
+}
+
+func (c *noopClient) DeleteFault(string) error {
+	return nil
+}
+
+func (c *noopClient) GetFaults() ([]*faulttypes.InjectedFault, error) {
+	return nil, nil
+}
+
 func (c *noopClient) SaveMetadata(string, string) error {
 	return nil
 }
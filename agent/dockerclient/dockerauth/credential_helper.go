@@ -0,0 +1,203 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package dockerauth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	"github.com/aws/amazon-ecs-agent/agent/utils"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/async"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/utils/execwrapper"
+
+	"github.com/docker/docker/api/types/registry"
+)
+
+const (
+	// CredentialHelperAuthType is the engine auth type of the auth data configuring the docker credential helpers
+	CredentialHelperAuthType = "credentialhelper"
+
+	credentialHelperBinaryPrefix = "docker-credential-"
+	credentialHelperTimeout      = 10 * time.Second
+	credentialHelperCacheSize    = 100
+	// credentialHelperCacheTTL is short so that the rotated credentials are picked up soon
+	credentialHelperCacheTTL = 5 * time.Minute
+
+	// identityTokenUsername is the username returned by the credential helpers with an identity token as secret
+	identityTokenUsername = "<token>"
+	// credentialsNotFoundMessage is the output of the credential helpers which have no credentials for a registry
+	credentialsNotFoundMessage = "credentials not found in native keychain"
+)
+
+// credentialHelperConfig is the auth data of the "credentialhelper" auth type. It has the same format as the
+// "credHelpers" and "credsStore" keys of the docker CLI config file.
+type credentialHelperConfig struct {
+	CredHelpers map[string]string `json:"credHelpers"`
+	CredsStore  string            `json:"credsStore"`
+}
+
+// credentialHelperOutput is the output of the get command of a docker credential helper
+type credentialHelperOutput struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+// credentialHelperAuthProvider gets the credentials of the registries from the docker credential helpers
+type credentialHelperAuthProvider struct {
+	credHelpers map[string]string
+	credsStore  string
+	cache       async.Cache
+	exec        execwrapper.Exec
+}
+
+func newCredentialHelperAuthProvider(authData json.RawMessage, exec execwrapper.Exec,
+	cache async.Cache) *credentialHelperAuthProvider {
+	config := parseCredentialHelperConfig(authData)
+	return &credentialHelperAuthProvider{
+		credHelpers: config.CredHelpers,
+		credsStore:  config.CredsStore,
+		cache:       cache,
+		exec:        exec,
+	}
+}
+
+// parseCredentialHelperConfig returns the credential helpers of the auth data, with the registries keyed without
+// their schema. The invalid credential helper names are ignored.
+func parseCredentialHelperConfig(authData json.RawMessage) credentialHelperConfig {
+	var config credentialHelperConfig
+	if err := json.Unmarshal(authData, &config); err != nil {
+		logger.Warn("Could not parse 'credentialhelper' type auth config", logger.Fields{
+			field.Error: err,
+		})
+		return credentialHelperConfig{CredHelpers: map[string]string{}}
+	}
+
+	credHelpers := make(map[string]string)
+	for registryIdentifier, helper := range config.CredHelpers {
+		if !isValidCredentialHelperName(helper) {
+			logger.Warn("Invalid credential helper for registry", logger.Fields{
+				"registry":         registryIdentifier,
+				"credentialHelper": helper,
+			})
+			continue
+		}
+		credHelpers[stripRegistrySchema(registryIdentifier)] = helper
+	}
+	config.CredHelpers = credHelpers
+
+	if config.CredsStore != "" && !isValidCredentialHelperName(config.CredsStore) {
+		logger.Warn("Invalid credential store", logger.Fields{
+			"credentialHelper": config.CredsStore,
+		})
+		config.CredsStore = ""
+	}
+	return config
+}
+
+// isValidCredentialHelperName makes sure that the credential helper name can only refer to a
+// docker-credential-<name> binary in the PATH
+func isValidCredentialHelperName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, `/\`) && name != "." && name != ".."
+}
+
+// GetAuthconfig returns the credentials of the registry of the image from its credential helper. The registries
+// without a credential helper are pulled anonymously.
+func (authProvider *credentialHelperAuthProvider) GetAuthconfig(image string,
+	_ *apicontainer.RegistryAuthenticationData) (registry.AuthConfig, error) {
+	// Ignore 'tag', not used in auth determination
+	repository, _ := utils.ParseRepositoryTag(image)
+	indexName, _ := splitReposName(repository)
+
+	registryKey := indexName
+	serverURL := indexName
+	if isDockerhubHostname(indexName) {
+		registryKey = dockerRegistryKey
+		serverURL = "https://" + dockerRegistryKey
+	}
+
+	helper, ok := authProvider.credHelpers[registryKey]
+	if !ok {
+		helper = authProvider.credsStore
+	}
+	if helper == "" {
+		return registry.AuthConfig{}, nil
+	}
+
+	cacheKey := helper + "|" + serverURL
+	if cached, ok := authProvider.cache.Get(cacheKey); ok {
+		if auth, ok := cached.(registry.AuthConfig); ok {
+			return auth, nil
+		}
+		authProvider.cache.Delete(cacheKey)
+	}
+
+	auth, err := authProvider.getAuthConfigFromHelper(helper, serverURL)
+	if err != nil {
+		return registry.AuthConfig{}, err
+	}
+	authProvider.cache.Set(cacheKey, auth)
+	return auth, nil
+}
+
+// getAuthConfigFromHelper runs the get command of the credential helper for the registry
+func (authProvider *credentialHelperAuthProvider) getAuthConfigFromHelper(helper string,
+	serverURL string) (registry.AuthConfig, error) {
+	logger.Debug("Getting registry credentials from credential helper", logger.Fields{
+		"credentialHelper": helper,
+		"registry":         serverURL,
+	})
+	ctx, cancel := authProvider.exec.NewExecContextWithTimeout(context.Background(), credentialHelperTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := authProvider.exec.CommandContext(ctx, credentialHelperBinaryPrefix+helper, "get")
+	cmd.SetIOStreams(strings.NewReader(serverURL), &stdout, &stderr)
+	if err := cmd.Run(); err != nil {
+		output := strings.TrimSpace(stdout.String())
+		if output == credentialsNotFoundMessage {
+			return registry.AuthConfig{}, nil
+		}
+		if output == "" {
+			output = strings.TrimSpace(stderr.String())
+		}
+		return registry.AuthConfig{}, fmt.Errorf(
+			"dockerauth: credential helper %s failed to get the credentials of %s: %v: %s",
+			helper, serverURL, err, output)
+	}
+
+	var helperOutput credentialHelperOutput
+	if err := json.Unmarshal(stdout.Bytes(), &helperOutput); err != nil {
+		return registry.AuthConfig{}, fmt.Errorf(
+			"dockerauth: unable to parse the output of credential helper %s for %s: %v", helper, serverURL, err)
+	}
+
+	auth := registry.AuthConfig{
+		ServerAddress: serverURL,
+	}
+	if helperOutput.Username == identityTokenUsername {
+		auth.IdentityToken = helperOutput.Secret
+	} else {
+		auth.Username = helperOutput.Username
+		auth.Password = helperOutput.Secret
+	}
+	return auth, nil
+}
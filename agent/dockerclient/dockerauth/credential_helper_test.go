@@ -0,0 +1,205 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package dockerauth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ecs-agent/ecs-agent/async"
+	mock_execwrapper "github.com/aws/amazon-ecs-agent/ecs-agent/utils/execwrapper/mocks"
+
+	"github.com/docker/docker/api/types/registry"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const credentialHelperAuthData = `{
+	"credHelpers": {
+		"https://ghcr.io": "ghcr",
+		"artifactory.example.com": "artifactory",
+		"https://index.docker.io/v1/": "hub",
+		"evil.example.com": "../../tmp/evil"
+	},
+	"credsStore": "pass"
+}`
+
+// expectCredentialHelper expects the credential helper to be run for the server URL, and makes it output the
+// given output on its standard output
+func expectCredentialHelper(ctrl *gomock.Controller, exec *mock_execwrapper.MockExec, helper, serverURL,
+	output string, runErr error) {
+	cmd := mock_execwrapper.NewMockCmd(ctrl)
+	var stdout io.Writer
+	gomock.InOrder(
+		exec.EXPECT().NewExecContextWithTimeout(gomock.Any(), credentialHelperTimeout).
+			Return(context.WithTimeout(context.Background(), time.Minute)),
+		exec.EXPECT().CommandContext(gomock.Any(), "docker-credential-"+helper, "get").Return(cmd),
+		cmd.EXPECT().SetIOStreams(gomock.Any(), gomock.Any(), gomock.Any()).Do(
+			func(stdin io.Reader, out io.Writer, _ io.Writer) {
+				input, _ := io.ReadAll(stdin)
+				assert.Equal(ctrl.T, serverURL, string(input))
+				stdout = out
+			}),
+		cmd.EXPECT().Run().DoAndReturn(func() error {
+			stdout.Write([]byte(output))
+			return runErr
+		}),
+	)
+}
+
+func TestParseCredentialHelperConfig(t *testing.T) {
+	config := parseCredentialHelperConfig([]byte(credentialHelperAuthData))
+	assert.Equal(t, map[string]string{
+		"ghcr.io":                 "ghcr",
+		"artifactory.example.com": "artifactory",
+		"index.docker.io/v1/":     "hub",
+	}, config.CredHelpers)
+	assert.Equal(t, "pass", config.CredsStore)
+
+	config = parseCredentialHelperConfig([]byte(`{"credsStore": "/usr/bin/evil"}`))
+	assert.Empty(t, config.CredHelpers)
+	assert.Empty(t, config.CredsStore)
+
+	config = parseCredentialHelperConfig([]byte(`not json`))
+	assert.Empty(t, config.CredHelpers)
+	assert.Empty(t, config.CredsStore)
+}
+
+func TestCredentialHelperAuth(t *testing.T) {
+	testCases := []struct {
+		name      string
+		image     string
+		helper    string
+		serverURL string
+		output    string
+		expected  registry.AuthConfig
+	}{
+		{
+			name:      "registry credential helper",
+			image:     "ghcr.io/org/app:latest",
+			helper:    "ghcr",
+			serverURL: "ghcr.io",
+			output:    `{"ServerURL":"ghcr.io","Username":"user","Secret":"token"}`,
+			expected:  registry.AuthConfig{Username: "user", Password: "token", ServerAddress: "ghcr.io"},
+		},
+		{
+			name:      "docker hub credential helper",
+			image:     "busybox",
+			helper:    "hub",
+			serverURL: "https://index.docker.io/v1/",
+			output:    `{"Username":"dockerhub","Secret":"password"}`,
+			expected: registry.AuthConfig{Username: "dockerhub", Password: "password",
+				ServerAddress: "https://index.docker.io/v1/"},
+		},
+		{
+			name:      "identity token",
+			image:     "artifactory.example.com/team/app",
+			helper:    "artifactory",
+			serverURL: "artifactory.example.com",
+			output:    `{"Username":"<token>","Secret":"identity"}`,
+			expected:  registry.AuthConfig{IdentityToken: "identity", ServerAddress: "artifactory.example.com"},
+		},
+		{
+			name:      "credential store",
+			image:     "registry.example.com/app",
+			helper:    "pass",
+			serverURL: "registry.example.com",
+			output:    `{"Username":"user","Secret":"swordfish"}`,
+			expected:  registry.AuthConfig{Username: "user", Password: "swordfish", ServerAddress: "registry.example.com"},
+		},
+		{
+			name:      "invalid credential helper falls back to the credential store",
+			image:     "evil.example.com/app",
+			helper:    "pass",
+			serverURL: "evil.example.com",
+			output:    `{"Username":"user","Secret":"swordfish"}`,
+			expected:  registry.AuthConfig{Username: "user", Password: "swordfish", ServerAddress: "evil.example.com"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			exec := mock_execwrapper.NewMockExec(ctrl)
+			provider := newCredentialHelperAuthProvider([]byte(credentialHelperAuthData), exec,
+				async.NewLRUCache(credentialHelperCacheSize, credentialHelperCacheTTL))
+
+			expectCredentialHelper(ctrl, exec, tc.helper, tc.serverURL, tc.output, nil)
+			auth, err := provider.GetAuthconfig(tc.image, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, auth)
+
+			// The credentials are cached, the credential helper is only run once
+			auth, err = provider.GetAuthconfig(tc.image, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, auth)
+		})
+	}
+}
+
+func TestCredentialHelperAuthWithoutHelper(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	exec := mock_execwrapper.NewMockExec(ctrl)
+	provider := newCredentialHelperAuthProvider([]byte(`{"credHelpers":{"ghcr.io":"ghcr"}}`), exec,
+		async.NewLRUCache(credentialHelperCacheSize, credentialHelperCacheTTL))
+
+	auth, err := provider.GetAuthconfig("registry.example.com/app", nil)
+	require.NoError(t, err)
+	assert.Equal(t, registry.AuthConfig{}, auth)
+}
+
+func TestCredentialHelperAuthCredentialsNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	exec := mock_execwrapper.NewMockExec(ctrl)
+	provider := newCredentialHelperAuthProvider([]byte(credentialHelperAuthData), exec,
+		async.NewLRUCache(credentialHelperCacheSize, credentialHelperCacheTTL))
+
+	expectCredentialHelper(ctrl, exec, "ghcr", "ghcr.io", credentialsNotFoundMessage+"\n", errors.New("exit status 1"))
+	auth, err := provider.GetAuthconfig("ghcr.io/org/app", nil)
+	require.NoError(t, err)
+	assert.Equal(t, registry.AuthConfig{}, auth)
+}
+
+func TestCredentialHelperAuthErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	exec := mock_execwrapper.NewMockExec(ctrl)
+	provider := newCredentialHelperAuthProvider([]byte(credentialHelperAuthData), exec,
+		async.NewLRUCache(credentialHelperCacheSize, credentialHelperCacheTTL))
+
+	expectCredentialHelper(ctrl, exec, "ghcr", "ghcr.io", "", errors.New("exit status 1"))
+	_, err := provider.GetAuthconfig("ghcr.io/org/app", nil)
+	assert.Error(t, err)
+
+	// Failures are not cached
+	expectCredentialHelper(ctrl, exec, "ghcr", "ghcr.io", "not json", nil)
+	_, err = provider.GetAuthconfig("ghcr.io/org/app", nil)
+	assert.Error(t, err)
+}
+
+func TestNewDockerAuthProviderCredentialHelper(t *testing.T) {
+	provider := NewDockerAuthProvider(CredentialHelperAuthType, []byte(credentialHelperAuthData))
+	_, ok := provider.(*credentialHelperAuthProvider)
+	assert.True(t, ok)
+}
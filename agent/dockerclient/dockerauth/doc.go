@@ -26,7 +26,7 @@ These keys may be set by either setting the environment variables
 
 # Auth Types
 
-The two currently supported auth types are "docker" and "dockercfg".
+The currently supported auth types are "docker", "dockercfg" and "credentialhelper".
 
 Docker:
 
@@ -55,5 +55,29 @@ the "AuthData" to be a string containing the contents of that file. The contents
 of your ".dockercfg" will generally be a string of the following form:
 
 	'{"http://myregistry.com/v1/":{"auth":"dXNlcjpzd29yZGZpc2g=","email":"email"}}'
+
+Credentialhelper:
+
+The auth type "credentialhelper" gets the credentials of the registries from
+docker credential helpers, so that credentials which rotate don't need to be
+set in the configuration. The "AuthData" has the format of the "credHelpers"
+and "credsStore" keys of the docker CLI "config.json" file:
+
+	{
+		"credHelpers": {
+			"ghcr.io": "ghcr-login",
+			"artifactory.example.com": "artifactory"
+		},
+		"credsStore": "pass"
+	}
+
+When an image is pulled, the "docker-credential-<name> get" command of the
+credential helper of its registry, or of the "credsStore" helper for the
+registries without one, is run to get the credentials. The credential helper
+binaries must be in the PATH of the agent. When the agent is started by
+ecs-init, the statically linked binaries placed in the
+"/var/lib/ecs/credential-helpers" directory of the host are in its PATH. The
+credentials are cached for a few minutes. The images of the registries without
+a credential helper are pulled anonymously.
 */
 package dockerauth
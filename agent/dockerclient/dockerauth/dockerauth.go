@@ -22,12 +22,18 @@ import (
 
 	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
 	"github.com/aws/amazon-ecs-agent/agent/utils"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/async"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/utils/execwrapper"
 	"github.com/docker/docker/api/types/registry"
 
 	"github.com/cihub/seelog"
 )
 
 func NewDockerAuthProvider(authType string, authData json.RawMessage) DockerAuthProvider {
+	if authType == CredentialHelperAuthType {
+		return newCredentialHelperAuthProvider(authData, execwrapper.NewExec(),
+			async.NewLRUCache(credentialHelperCacheSize, credentialHelperCacheTTL))
+	}
 	return &dockerAuthProvider{
 		authMap: parseAuthData(authType, authData),
 	}
@@ -28,8 +28,14 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// containerIDKey is the span attribute key of the ID of the container whose network namespace is set up
+const containerIDKey = attribute.Key("aws.ecs.cni.container_id")
+
 // CNIClient defines the method of setting/cleaning up container namespace
 type CNIClient interface {
 	// Version returns the version of the plugin
@@ -94,9 +100,12 @@ func (client *cniClient) SetupNS(
 	ctx context.Context,
 	cfg *Config,
 	timeout time.Duration) (*cniTypesCurrent.Result, error) {
+	ctx, span := tracing.StartSpan(ctx, "SetupNetworkNamespace", containerIDKey.String(cfg.ContainerID))
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
-	return client.setupNS(ctx, cfg)
+	result, err := client.setupNS(ctx, cfg)
+	tracing.EndSpan(span, err)
+	return result, err
 }
 
 // CleanupNS will clean up the container namespace, including remove the veth
@@ -105,11 +114,13 @@ func (client *cniClient) CleanupNS(
 	ctx context.Context,
 	cfg *Config,
 	timeout time.Duration) error {
-
+	ctx, span := tracing.StartSpan(ctx, "CleanupNetworkNamespace", containerIDKey.String(cfg.ContainerID))
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	return client.cleanupNS(ctx, cfg)
+	err := client.cleanupNS(ctx, cfg)
+	tracing.EndSpan(span, err)
+	return err
 }
 
 // cleanupNS is called by CleanupNS to cleanup the task namespace by invoking DEL for given CNI configurations
@@ -134,7 +145,9 @@ func (client *cniClient) cleanupNS(ctx context.Context, cfg *Config) error {
 			cniNetworkConfig.Network.Type,
 			cfg.ContainerID)
 		runtimeConfig.IfName = networkConfig.IfName
+		_, span := startPluginSpan(ctx, "DeleteNetwork", cniNetworkConfig)
 		err := client.libcni.DelNetwork(ctx, cniNetworkConfig, &runtimeConfig)
+		tracing.EndSpan(span, err)
 		if err != nil {
 			// In case of error, continue cleanup as much as possible before conceding error.
 			seelog.Errorf("Delete network failed: %v", err)
@@ -220,3 +233,10 @@ func (cniGuard *guard) unlock() {
 		cniGuard.mutex.Unlock()
 	}
 }
+
+// startPluginSpan starts the span of the invocation of a CNI plugin for a network configuration
+func startPluginSpan(ctx context.Context, name string, cniNetworkConfig *libcni.NetworkConfig) (context.Context, trace.Span) {
+	return tracing.StartSpan(ctx, name,
+		attribute.String("aws.ecs.cni.network", cniNetworkConfig.Network.Name),
+		attribute.String("aws.ecs.cni.plugin", cniNetworkConfig.Network.Type))
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tracing"
 )
 
 const (
@@ -63,7 +64,9 @@ func (client *cniClient) setupNS(ctx context.Context, cfg *Config) (*cniTypesCur
 			cniNetworkConfig.Network.Type,
 			cfg.ContainerID)
 		runtimeConfig.IfName = networkConfig.IfName
+		_, span := startPluginSpan(ctx, "AddNetwork", cniNetworkConfig)
 		result, err := client.libcni.AddNetwork(ctx, cniNetworkConfig, &runtimeConfig)
+		tracing.EndSpan(span, err)
 		if err != nil {
 			return nil, errors.Wrap(err, "add network failed")
 		}
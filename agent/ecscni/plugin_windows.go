@@ -31,6 +31,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/aws/amazon-ecs-agent/agent/utils"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tracing"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/utils/retry"
 )
 
@@ -87,7 +88,9 @@ func (client *cniClient) doSetupNS(ctx context.Context, cfg *Config) (*cniTypesC
 			cniNetworkConfig.Network.Type,
 			cfg.ContainerID)
 		runtimeConfig.IfName = networkConfig.IfName
+		_, span := startPluginSpan(ctx, "AddNetwork", cniNetworkConfig)
 		result, err := client.libcni.AddNetwork(ctx, cniNetworkConfig, &runtimeConfig)
+		tracing.EndSpan(span, err)
 		if err != nil {
 			return nil, errors.Wrap(err, "add network failed")
 		}
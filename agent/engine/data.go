@@ -21,8 +21,9 @@ import (
 	"github.com/aws/amazon-ecs-agent/agent/engine/image"
 	"github.com/aws/amazon-ecs-agent/agent/utils"
 	apicontainerstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/container/status"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
 
-	"github.com/cihub/seelog"
 	"github.com/pkg/errors"
 )
 
@@ -221,75 +222,111 @@ func ensureCompatibleWithBoltDB(state dockerstate.TaskEngineState) {
 func (engine *DockerTaskEngine) saveTaskData(task *apitask.Task) {
 	err := engine.dataClient.SaveTask(task)
 	if err != nil {
-		seelog.Errorf("Failed to save data for task %s: %v", task.Arn, err)
+		logger.ErrorCtx(engine.taskLogContext(task), "Failed to save data for task", logger.Fields{
+			field.Error: err,
+		})
 	}
 }
 
 func (engine *DockerTaskEngine) saveContainerData(container *apicontainer.Container) {
 	err := engine.dataClient.SaveContainer(container)
 	if err != nil {
-		seelog.Errorf("Failed to save data for container %s: %v", container.Name, err)
+		logger.Error("Failed to save data for container", logger.Fields{
+			field.ContainerName: container.Name,
+			field.Error:         err,
+		})
 	}
 }
 
 func (engine *DockerTaskEngine) saveDockerContainerData(container *apicontainer.DockerContainer) {
 	err := engine.dataClient.SaveDockerContainer(container)
 	if err != nil {
-		seelog.Errorf("Failed to save data for docker container %s: %v", container.Container.Name, err)
+		logger.Error("Failed to save data for docker container", logger.Fields{
+			field.ContainerName: container.Container.Name,
+			field.RuntimeID:     container.DockerID,
+			field.Error:         err,
+		})
 	}
 }
 
 func (engine *DockerTaskEngine) removeTaskData(task *apitask.Task) {
+	ctx := engine.taskLogContext(task)
 	for _, c := range task.Containers {
 		id, err := data.GetContainerID(c)
 		if err != nil {
-			seelog.Errorf("Failed to get container id from container %s: %v", c.Name, err)
+			logger.ErrorCtx(c.LogContext(ctx), "Failed to get container id from container", logger.Fields{
+				field.Error: err,
+			})
 			continue
 		}
 		err = engine.dataClient.DeleteContainer(id)
 		if err != nil {
-			seelog.Errorf("Failed to remove data for container %s: %v", c.Name, err)
+			logger.ErrorCtx(c.LogContext(ctx), "Failed to remove data for container", logger.Fields{
+				field.Error: err,
+			})
 		}
 	}
 
 	id, err := utils.GetTaskID(task.Arn)
 	if err != nil {
-		seelog.Errorf("Failed to get task id from task ARN %s: %v", task.Arn, err)
+		logger.ErrorCtx(ctx, "Failed to get task id from task ARN", logger.Fields{
+			field.Error: err,
+		})
 		return
 	}
 	err = engine.dataClient.DeleteTask(id)
 	if err != nil {
-		seelog.Errorf("Failed to remove data for task %s: %v", task.Arn, err)
+		logger.ErrorCtx(ctx, "Failed to remove data for task", logger.Fields{
+			field.Error: err,
+		})
 	}
 }
 
 func (engine *DockerTaskEngine) removeENIAttachmentData(mac string) {
 	attachmentToRemove, ok := engine.state.ENIByMac(mac)
 	if !ok {
-		seelog.Warnf("Unable to retrieve ENI Attachment for mac address %s: ", mac)
+		logger.Warn("Unable to retrieve ENI Attachment for mac address", logger.Fields{
+			"mac": mac,
+		})
 		return
 	}
 	attachmentId, err := utils.GetAttachmentId(attachmentToRemove.AttachmentARN)
 	if err != nil {
-		seelog.Errorf("Failed to get attachment id for %s: %v", attachmentToRemove.AttachmentARN, err)
+		logger.Error("Failed to get attachment id", logger.Fields{
+			"attachmentARN": attachmentToRemove.AttachmentARN,
+			field.Error:     err,
+		})
 	} else {
 		err = engine.dataClient.DeleteENIAttachment(attachmentId)
 		if err != nil {
-			seelog.Errorf("Failed to remove data for eni attachment %s: %v", attachmentId, err)
+			logger.Error("Failed to remove data for eni attachment", logger.Fields{
+				"attachmentID": attachmentId,
+				field.Error:    err,
+			})
 		}
 	}
 }
 
 func (imageManager *dockerImageManager) saveImageStateData(imageState *image.ImageState) {
+	if imageManager.dataClient == nil {
+		// Images may be pre-warmed before the data client is set, they're saved once it is
+		return
+	}
 	err := imageManager.dataClient.SaveImageState(imageState)
 	if err != nil {
-		seelog.Errorf("Failed to save data for image state %s:, %v", imageState.GetImageID(), err)
+		logger.Error("Failed to save data for image state", logger.Fields{
+			field.ImageID: imageState.GetImageID(),
+			field.Error:   err,
+		})
 	}
 }
 
 func (imageManager *dockerImageManager) removeImageStateData(imageId string) {
 	err := imageManager.dataClient.DeleteImageState(imageId)
 	if err != nil {
-		seelog.Errorf("Failed to remove data for image state %s:, %v", imageId, err)
+		logger.Error("Failed to remove data for image state", logger.Fields{
+			field.ImageID: imageId,
+			field.Error:   err,
+		})
 	}
 }
@@ -0,0 +1,108 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package dependencygraph
+
+import (
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	"github.com/aws/amazon-ecs-agent/agent/taskresource"
+)
+
+// BlockedReason converts the result of DependenciesAreResolved for the `target` container into the
+// structured reason why the container can't progress to its next known status. The containers and
+// the resources of the task are used to find the dependency which isn't resolved yet. It returns nil
+// when the container isn't waiting for anything, including when it can't progress anymore because
+// of a terminal dependency error.
+func BlockedReason(target *apicontainer.Container,
+	blockedOn *apicontainer.DependsOn,
+	err DependencyError,
+	by []*apicontainer.Container,
+	resources []taskresource.TaskResource) *apicontainer.BlockedReason {
+	if err == nil || err == ContainerPastDesiredStatusErr || err.IsTerminal() {
+		return nil
+	}
+	if blockedOn != nil {
+		return &apicontainer.BlockedReason{
+			Type:      apicontainer.BlockedOnContainer,
+			Container: blockedOn.ContainerName,
+			Condition: blockedOn.Condition,
+		}
+	}
+
+	nameMap := make(map[string]*apicontainer.Container)
+	for _, cont := range by {
+		nameMap[cont.Name] = cont
+	}
+	switch err {
+	case CredentialsNotResolvedErr:
+		return &apicontainer.BlockedReason{Type: apicontainer.BlockedOnExecutionCredentials}
+	case DependentContainerNotResolvedErr:
+		for _, dependency := range target.SteadyStateDependencies {
+			dependencyContainer, ok := nameMap[dependency]
+			if !ok || !onSteadyStateIsResolved(target, dependencyContainer) {
+				return containerBlockedReason(dependency, dependencyContainer)
+			}
+		}
+	case ErrContainerDependencyNotResolved:
+		targetNext := target.GetNextKnownStateProgression()
+		for _, dependency := range target.TransitionDependenciesMap[targetNext].ContainerDependencies {
+			dependencyContainer, ok := nameMap[dependency.ContainerName]
+			if !ok || dependencyContainer.GetKnownStatus() < dependency.SatisfiedStatus {
+				return &apicontainer.BlockedReason{
+					Type:      apicontainer.BlockedOnContainer,
+					Container: dependency.ContainerName,
+					Condition: dependency.SatisfiedStatus.String(),
+				}
+			}
+		}
+	case ErrResourceDependencyNotResolved:
+		resourcesMap := make(map[string]taskresource.TaskResource)
+		for _, resource := range resources {
+			resourcesMap[resource.GetName()] = resource
+		}
+		targetNext := target.GetNextKnownStateProgression()
+		for _, dependency := range target.TransitionDependenciesMap[targetNext].ResourceDependencies {
+			resource, ok := resourcesMap[dependency.Name]
+			if !ok {
+				return &apicontainer.BlockedReason{
+					Type:     apicontainer.BlockedOnResource,
+					Resource: dependency.Name,
+				}
+			}
+			if resource.GetKnownStatus() < dependency.GetRequiredStatus() {
+				return &apicontainer.BlockedReason{
+					Type:      apicontainer.BlockedOnResource,
+					Resource:  dependency.Name,
+					Condition: resource.StatusString(dependency.GetRequiredStatus()),
+				}
+			}
+		}
+	}
+	return &apicontainer.BlockedReason{
+		Type:    apicontainer.BlockedOnOther,
+		Message: err.Error(),
+	}
+}
+
+// containerBlockedReason returns the reason of a container waiting for the steady state of the
+// `dependency` container, which may not exist
+func containerBlockedReason(dependency string, dependencyContainer *apicontainer.Container) *apicontainer.BlockedReason {
+	reason := &apicontainer.BlockedReason{
+		Type:      apicontainer.BlockedOnContainer,
+		Container: dependency,
+	}
+	if dependencyContainer != nil {
+		reason.Condition = dependencyContainer.GetSteadyStateStatus().String()
+	}
+	return reason
+}
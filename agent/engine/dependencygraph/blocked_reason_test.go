@@ -0,0 +1,122 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package dependencygraph
+
+import (
+	"errors"
+	"testing"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	"github.com/aws/amazon-ecs-agent/agent/taskresource"
+	mock_taskresource "github.com/aws/amazon-ecs-agent/agent/taskresource/mocks"
+	resourcestatus "github.com/aws/amazon-ecs-agent/agent/taskresource/status"
+	apicontainerstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/container/status"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockedReason(t *testing.T) {
+	db := steadyStateContainer("db", nil, apicontainerstatus.ContainerRunning, apicontainerstatus.ContainerRunning)
+	target := steadyStateContainer("app", nil, apicontainerstatus.ContainerRunning, apicontainerstatus.ContainerRunning)
+	target.SteadyStateDependencies = []string{"db"}
+	target.TransitionDependenciesMap = make(map[apicontainerstatus.ContainerStatus]apicontainer.TransitionDependencySet)
+	target.BuildContainerDependency("db", apicontainerstatus.ContainerCreated, apicontainerstatus.ContainerManifestPulled)
+	containers := []*apicontainer.Container{db, target}
+
+	testCases := []struct {
+		name      string
+		blockedOn *apicontainer.DependsOn
+		err       DependencyError
+		expected  *apicontainer.BlockedReason
+	}{
+		{
+			name: "not blocked",
+		},
+		{
+			name: "past desired status",
+			err:  ContainerPastDesiredStatusErr,
+		},
+		{
+			name: "terminal error",
+			err:  &dependencyError{err: errors.New("dependency will never start"), isTerminal: true},
+		},
+		{
+			name:      "container ordering dependency",
+			blockedOn: &apicontainer.DependsOn{ContainerName: "db", Condition: healthyCondition},
+			err:       &dependencyError{err: errors.New("container ordering dependency not resolved")},
+			expected: &apicontainer.BlockedReason{Type: apicontainer.BlockedOnContainer, Container: "db",
+				Condition: healthyCondition},
+		},
+		{
+			name:     "execution credentials",
+			err:      CredentialsNotResolvedErr,
+			expected: &apicontainer.BlockedReason{Type: apicontainer.BlockedOnExecutionCredentials},
+		},
+		{
+			name: "steady state dependency",
+			err:  DependentContainerNotResolvedErr,
+			expected: &apicontainer.BlockedReason{Type: apicontainer.BlockedOnContainer, Container: "db",
+				Condition: apicontainerstatus.ContainerRunning.String()},
+		},
+		{
+			name: "transition container dependency",
+			err:  ErrContainerDependencyNotResolved,
+			expected: &apicontainer.BlockedReason{Type: apicontainer.BlockedOnContainer, Container: "db",
+				Condition: apicontainerstatus.ContainerCreated.String()},
+		},
+		{
+			name:     "other error",
+			err:      &dependencyError{err: errors.New("needs other containers stopped")},
+			expected: &apicontainer.BlockedReason{Type: apicontainer.BlockedOnOther, Message: "needs other containers stopped"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, BlockedReason(target, tc.blockedOn, tc.err, containers, nil))
+		})
+	}
+}
+
+func TestBlockedReasonResourceDependency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	target := &apicontainer.Container{
+		Name:                      "app",
+		KnownStatusUnsafe:         apicontainerstatus.ContainerStatusNone,
+		TransitionDependenciesMap: make(map[apicontainerstatus.ContainerStatus]apicontainer.TransitionDependencySet),
+	}
+	target.BuildResourceDependency("cgroup", resourcestatus.ResourceStatus(1), apicontainerstatus.ContainerManifestPulled)
+
+	mockResource := mock_taskresource.NewMockTaskResource(ctrl)
+	mockResource.EXPECT().GetName().Return("cgroup").AnyTimes()
+	mockResource.EXPECT().GetKnownStatus().Return(resourcestatus.ResourceStatus(0)).AnyTimes()
+	mockResource.EXPECT().StatusString(resourcestatus.ResourceStatus(1)).Return("CREATED").AnyTimes()
+
+	assert.Equal(t, &apicontainer.BlockedReason{
+		Type:      apicontainer.BlockedOnResource,
+		Resource:  "cgroup",
+		Condition: "CREATED",
+	}, BlockedReason(target, nil, ErrResourceDependencyNotResolved, nil,
+		[]taskresource.TaskResource{mockResource}))
+
+	assert.Equal(t, &apicontainer.BlockedReason{
+		Type:     apicontainer.BlockedOnResource,
+		Resource: "cgroup",
+	}, BlockedReason(target, nil, ErrResourceDependencyNotResolved, nil, nil))
+}
@@ -48,6 +48,8 @@ type ImageManager interface {
 	StartImageCleanupProcess(ctx context.Context)
 	SetDataClient(dataClient data.Client)
 	AddImageToCleanUpExclusionList(image string)
+	RecordPrewarmedImage(imageName string, protectedUntil time.Time) error
+	UpdateCleanupConfig(cfg *config.Config)
 }
 
 // dockerImageManager accounts all the images and their states in the instance.
@@ -62,9 +64,11 @@ type dockerImageManager struct {
 	imageStatesConsideredForDeletion   map[string]*image.ImageState
 	minimumAgeBeforeDeletion           time.Duration
 	numImagesToDelete                  int
+	cleanupPolicy                      imageCleanupPolicy
 	imageCleanupTimeInterval           time.Duration
 	imagePullBehavior                  config.ImagePullBehaviorType
 	imageCleanupExclusionList          []string
+	addedCleanupExclusionList          []string
 	deleteNonECSImagesEnabled          config.BooleanDefaultFalse
 	nonECSContainerCleanupWaitDuration time.Duration
 	numNonECSContainersToDelete        int
@@ -81,6 +85,7 @@ func NewImageManager(cfg *config.Config, client dockerapi.DockerClient, state do
 		state:                              state,
 		minimumAgeBeforeDeletion:           cfg.MinimumImageDeletionAge,
 		numImagesToDelete:                  cfg.NumImagesToDeletePerCycle,
+		cleanupPolicy:                      newImageCleanupPolicy(cfg, client),
 		imageCleanupTimeInterval:           cfg.ImageCleanupInterval,
 		imagePullBehavior:                  cfg.ImagePullBehavior,
 		imageCleanupExclusionList:          buildImageCleanupExclusionList(cfg),
@@ -91,9 +96,17 @@ func NewImageManager(cfg *config.Config, client dockerapi.DockerClient, state do
 	}
 }
 
-// SetDataClient sets the saver that is used by the ImageManager.
+// SetDataClient sets the saver that is used by the ImageManager. The images pre-warmed
+// before the saver is set are saved then.
 func (imageManager *dockerImageManager) SetDataClient(dataClient data.Client) {
+	imageManager.updateLock.Lock()
+	defer imageManager.updateLock.Unlock()
 	imageManager.dataClient = dataClient
+	for _, imageState := range imageManager.getAllImageStates() {
+		if imageState.IsProtected(time.Now()) {
+			imageManager.saveImageStateData(imageState)
+		}
+	}
 }
 
 func buildImageCleanupExclusionList(cfg *config.Config) []string {
@@ -115,11 +128,32 @@ func (imageManager *dockerImageManager) AddImageToCleanUpExclusionList(image str
 	imageManager.updateLock.Lock()
 	defer imageManager.updateLock.Unlock()
 	imageManager.imageCleanupExclusionList = append(imageManager.imageCleanupExclusionList, image)
+	imageManager.addedCleanupExclusionList = append(imageManager.addedCleanupExclusionList, image)
 	logger.Info("Image excluded from cleanup", logger.Fields{
 		field.Image: image,
 	})
 }
 
+// UpdateCleanupConfig applies the image cleanup settings of a configuration reloaded while the
+// agent is running. The images excluded from cleanup through AddImageToCleanUpExclusionList
+// remain excluded.
+func (imageManager *dockerImageManager) UpdateCleanupConfig(cfg *config.Config) {
+	imageManager.updateLock.Lock()
+	defer imageManager.updateLock.Unlock()
+	imageManager.minimumAgeBeforeDeletion = cfg.MinimumImageDeletionAge
+	imageManager.nonECSMinimumAgeBeforeDeletion = cfg.NonECSMinimumImageDeletionAge
+	imageManager.numImagesToDelete = cfg.NumImagesToDeletePerCycle
+	imageManager.cleanupPolicy = newImageCleanupPolicy(cfg, imageManager.client)
+	imageManager.imageCleanupExclusionList = append(buildImageCleanupExclusionList(cfg),
+		imageManager.addedCleanupExclusionList...)
+	if imageManager.imageCleanupTimeInterval != cfg.ImageCleanupInterval {
+		imageManager.imageCleanupTimeInterval = cfg.ImageCleanupInterval
+		if imageManager.imageCleanupTicker != nil {
+			imageManager.imageCleanupTicker.Reset(cfg.ImageCleanupInterval)
+		}
+	}
+}
+
 func (imageManager *dockerImageManager) AddAllImageStates(imageStates []*image.ImageState) {
 	imageManager.updateLock.Lock()
 	defer imageManager.updateLock.Unlock()
@@ -229,6 +263,38 @@ func (imageManager *dockerImageManager) addContainerReferenceToNewImageState(con
 	}
 }
 
+// RecordPrewarmedImage records the state of an image pulled before any task uses it, and protects
+// the image from cleanup until protectedUntil.
+func (imageManager *dockerImageManager) RecordPrewarmedImage(imageName string, protectedUntil time.Time) error {
+	imageInspected, err := imageManager.client.InspectImage(imageName)
+	if err != nil {
+		return fmt.Errorf("unable to inspect pre-warmed image %s: %w", imageName, err)
+	}
+	imageManager.updateLock.Lock()
+	defer imageManager.updateLock.Unlock()
+	imageManager.removeExistingImageNameOfDifferentID(imageName, imageInspected.ID)
+	imageState, ok := imageManager.getImageState(imageInspected.ID)
+	if !ok {
+		imageState = &image.ImageState{
+			Image: &image.Image{
+				ImageID: imageInspected.ID,
+				Size:    imageInspected.Size,
+			},
+			PulledAt:   time.Now(),
+			LastUsedAt: time.Now(),
+		}
+		imageManager.imageStates = append(imageManager.imageStates, imageState)
+	}
+	imageState.AddImageName(imageName)
+	imageState.SetPullSucceeded(true)
+	imageState.SetProtectedUntil(protectedUntil)
+	logger.Info("Recorded pre-warmed image", imageState.Fields(), logger.Fields{
+		"protectedUntil": protectedUntil.UTC().Format(time.RFC3339),
+	})
+	imageManager.saveImageStateData(imageState)
+	return nil
+}
+
 // RemoveContainerReferenceFromImageState removes container reference from the corresponding imageState object
 func (imageManager *dockerImageManager) RemoveContainerReferenceFromImageState(container *apicontainer.Container) error {
 	// this lock is for reading image states and finding the one that the container belongs to
@@ -294,7 +360,8 @@ func (imageManager *dockerImageManager) getCandidateImagesForDeletion() []*image
 	}
 	var imagesForDeletion []*image.ImageState
 	for _, imageState := range imageManager.imageStatesConsideredForDeletion {
-		if imageManager.isImageOldEnough(imageState) && imageState.HasNoAssociatedContainers() {
+		if imageManager.isImageOldEnough(imageState) && imageState.HasNoAssociatedContainers() &&
+			!imageState.IsProtected(time.Now()) {
 			logger.Debug("Candidate image for deletion", imageState.Fields())
 			imagesForDeletion = append(imagesForDeletion, imageState)
 		}
@@ -327,14 +394,8 @@ func (imageStates ImageStatesForDeletion) Swap(i, j int) {
 }
 
 func (imageManager *dockerImageManager) getLeastRecentlyUsedImage(imagesForDeletion []*image.ImageState) *image.ImageState {
-	var candidateImages ImageStatesForDeletion
-	for _, imageState := range imagesForDeletion {
-		candidateImages = append(candidateImages, imageState)
-	}
-	// sort images in the order of last used times
-	sort.Sort(candidateImages)
 	// return only the top LRU image for deletion
-	return candidateImages[0]
+	return leastRecentlyUsedImage(imagesForDeletion)
 }
 
 func (imageManager *dockerImageManager) removeExistingImageNameOfDifferentID(containerImageName string, inspectedImageID string) {
@@ -356,17 +417,24 @@ func (imageManager *dockerImageManager) StartImageCleanupProcess(ctx context.Con
 		return
 	}
 	// passing the cleanup interval as argument which would help during testing
-	imageManager.performPeriodicImageCleanup(ctx, imageManager.imageCleanupTimeInterval)
+	imageManager.updateLock.RLock()
+	imageCleanupInterval := imageManager.imageCleanupTimeInterval
+	imageManager.updateLock.RUnlock()
+	imageManager.performPeriodicImageCleanup(ctx, imageCleanupInterval)
 }
 
 func (imageManager *dockerImageManager) performPeriodicImageCleanup(ctx context.Context, imageCleanupInterval time.Duration) {
-	imageManager.imageCleanupTicker = time.NewTicker(imageCleanupInterval)
+	// The ticker is reset by UpdateCleanupConfig when the cleanup interval changes
+	imageCleanupTicker := time.NewTicker(imageCleanupInterval)
+	imageManager.updateLock.Lock()
+	imageManager.imageCleanupTicker = imageCleanupTicker
+	imageManager.updateLock.Unlock()
 	for {
 		select {
-		case <-imageManager.imageCleanupTicker.C:
+		case <-imageCleanupTicker.C:
 			go imageManager.removeUnusedImages(ctx)
 		case <-ctx.Done():
-			imageManager.imageCleanupTicker.Stop()
+			imageCleanupTicker.Stop()
 			return
 		}
 	}
@@ -385,9 +453,9 @@ func (imageManager *dockerImageManager) removeUnusedImages(ctx context.Context)
 	var numECSImagesDeleted int
 	imageManager.imageStatesConsideredForDeletion = imageManager.imagesConsiderForDeletion(imageManager.getAllImageStates())
 
-	for i := 0; i < imageManager.numImagesToDelete; i++ {
-		err := imageManager.removeLeastRecentlyUsedImage(ctx)
-		numECSImagesDeleted = i
+	cleanupPolicy := imageManager.getCleanupPolicy()
+	for ; ; numECSImagesDeleted++ {
+		err := imageManager.removeNextImageForDeletion(ctx, cleanupPolicy, numECSImagesDeleted)
 		if err != nil {
 			logger.Info("End of eligible images for deletion", logger.Fields{
 				"managedImagesRemaining": len(imageManager.getAllImageStates()),
@@ -633,24 +701,36 @@ func (imageManager *dockerImageManager) isExcludedFromCleanup(imageState *image.
 	return false
 }
 
-func (imageManager *dockerImageManager) removeLeastRecentlyUsedImage(ctx context.Context) error {
-	leastRecentlyUsedImage := imageManager.getUnusedImageForDeletion()
-	if leastRecentlyUsedImage == nil {
+// getCleanupPolicy returns the image cleanup policy, which defaults to removing the least
+// recently used images first
+func (imageManager *dockerImageManager) getCleanupPolicy() imageCleanupPolicy {
+	if imageManager.cleanupPolicy == nil {
+		return &lruImageCleanupPolicy{numImagesToDelete: imageManager.numImagesToDelete}
+	}
+	return imageManager.cleanupPolicy
+}
+
+func (imageManager *dockerImageManager) removeNextImageForDeletion(ctx context.Context,
+	cleanupPolicy imageCleanupPolicy, numDeleted int) error {
+	imageForDeletion := imageManager.getUnusedImageForDeletion(ctx, cleanupPolicy, numDeleted)
+	if imageForDeletion == nil {
 		return fmt.Errorf("No more eligible images for deletion")
 	}
-	logger.Info("Image ready for deletion", leastRecentlyUsedImage.Fields())
-	imageManager.removeImage(ctx, leastRecentlyUsedImage)
+	logger.Info("Image ready for deletion", imageForDeletion.Fields())
+	imageManager.removeImage(ctx, imageForDeletion)
 	return nil
 }
 
-func (imageManager *dockerImageManager) getUnusedImageForDeletion() *image.ImageState {
+func (imageManager *dockerImageManager) getUnusedImageForDeletion(ctx context.Context,
+	cleanupPolicy imageCleanupPolicy, numDeleted int) *image.ImageState {
 	candidateImageStatesForDeletion := imageManager.getCandidateImagesForDeletion()
 	if len(candidateImageStatesForDeletion) < 1 {
 		logger.Debug("No eligible images for deletion for this cleanup cycle")
 		return nil
 	}
 	logger.Debug(fmt.Sprintf("Found %d eligible images for deletion", len(candidateImageStatesForDeletion)))
-	return imageManager.getLeastRecentlyUsedImage(candidateImageStatesForDeletion)
+	return cleanupPolicy.nextImageForDeletion(ctx, candidateImageStatesForDeletion,
+		imageManager.getAllImageStates(), numDeleted)
 }
 
 func (imageManager *dockerImageManager) removeImage(ctx context.Context, leastRecentlyUsedImage *image.ImageState) {
@@ -62,6 +62,37 @@ func TestNewImageManagerExcludesCachedImages(t *testing.T) {
 	assert.ElementsMatch(t, expected, dockerImageManager.imageCleanupExclusionList)
 }
 
+func TestUpdateCleanupConfig(t *testing.T) {
+	cfg := defaultTestConfig()
+	cfg.ImageCleanupExclusionList = []string{"excluded:1"}
+	imageManager := NewImageManager(cfg, nil, nil)
+	dockerImageManager, ok := imageManager.(*dockerImageManager)
+	require.True(t, ok, "imageManager must be *dockerImageManager")
+	imageManager.AddImageToCleanUpExclusionList("service-connect:1")
+	dockerImageManager.imageCleanupTicker = time.NewTicker(cfg.ImageCleanupInterval)
+	defer dockerImageManager.imageCleanupTicker.Stop()
+
+	reloaded := *cfg
+	reloaded.ImageCleanupExclusionList = []string{"excluded:2"}
+	reloaded.MinimumImageDeletionAge = 2 * time.Hour
+	reloaded.NonECSMinimumImageDeletionAge = 3 * time.Hour
+	reloaded.NumImagesToDeletePerCycle = 10
+	reloaded.ImageCleanupInterval = 20 * time.Minute
+	imageManager.UpdateCleanupConfig(&reloaded)
+
+	assert.ElementsMatch(t, []string{
+		"excluded:2",
+		cfg.PauseContainerImageName + ":" + cfg.PauseContainerTag,
+		config.DefaultPauseContainerImageName + ":" + config.DefaultPauseContainerTag,
+		config.CachedImageNameAgentContainer,
+		"service-connect:1",
+	}, dockerImageManager.imageCleanupExclusionList)
+	assert.Equal(t, 2*time.Hour, dockerImageManager.minimumAgeBeforeDeletion)
+	assert.Equal(t, 3*time.Hour, dockerImageManager.nonECSMinimumAgeBeforeDeletion)
+	assert.Equal(t, 10, dockerImageManager.numImagesToDelete)
+	assert.Equal(t, 20*time.Minute, dockerImageManager.imageCleanupTimeInterval)
+}
+
 // TestImagePullRemoveDeadlock tests if there's a deadlock when trying to
 // pull an image while image clean up is in progress
 func TestImagePullRemoveDeadlock(t *testing.T) {
@@ -1792,7 +1823,7 @@ func TestRemoveLeastRecentlyUsedImageNoImage(t *testing.T) {
 	imageManager.SetDataClient(data.NewNoopClient())
 	ctx, cancel := context.WithCancel(context.TODO())
 	defer cancel()
-	err := imageManager.removeLeastRecentlyUsedImage(ctx)
+	err := imageManager.removeNextImageForDeletion(ctx, imageManager.getCleanupPolicy(), 0)
 	if err == nil {
 		t.Error("Expected Error for no LRU image to remove")
 	}
@@ -1942,3 +1973,69 @@ func TestImageCleanupProcessNotStart(t *testing.T) {
 	imageManager.StartImageCleanupProcess(ctx)
 	// Nothing should happen.
 }
+
+func TestRecordPrewarmedImage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock_dockerapi.NewMockDockerClient(ctrl)
+
+	imageManager := NewImageManager(defaultTestConfig(), client, dockerstate.NewTaskEngineState())
+	imageManager.SetDataClient(data.NewNoopClient())
+	protectedUntil := time.Now().Add(time.Hour)
+	client.EXPECT().InspectImage("busybox:latest").Return(&types.ImageInspect{ID: "sha256:qwerty", Size: 1024}, nil)
+
+	require.NoError(t, imageManager.RecordPrewarmedImage("busybox:latest", protectedUntil))
+	imageState, ok := imageManager.GetImageStateFromImageName("busybox:latest")
+	require.True(t, ok)
+	assert.Equal(t, "sha256:qwerty", imageState.Image.ImageID)
+	assert.Equal(t, int64(1024), imageState.Image.Size)
+	assert.True(t, imageState.PullSucceeded)
+	assert.Equal(t, protectedUntil, imageState.ProtectedUntil)
+
+	// Recording the image again never shortens its protection
+	client.EXPECT().InspectImage("busybox:latest").Return(&types.ImageInspect{ID: "sha256:qwerty"}, nil)
+	require.NoError(t, imageManager.RecordPrewarmedImage("busybox:latest", time.Now()))
+	assert.Equal(t, protectedUntil, imageState.ProtectedUntil)
+	assert.Len(t, imageManager.(*dockerImageManager).imageStates, 1)
+}
+
+func TestRecordPrewarmedImageInspectError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock_dockerapi.NewMockDockerClient(ctrl)
+
+	imageManager := NewImageManager(defaultTestConfig(), client, dockerstate.NewTaskEngineState())
+	client.EXPECT().InspectImage("busybox:latest").Return(nil, errors.New("error inspecting"))
+
+	assert.Error(t, imageManager.RecordPrewarmedImage("busybox:latest", time.Now().Add(time.Hour)))
+	_, ok := imageManager.GetImageStateFromImageName("busybox:latest")
+	assert.False(t, ok)
+}
+
+func TestGetCandidateImagesForDeletionImageProtected(t *testing.T) {
+	imageManager := &dockerImageManager{
+		state:                    dockerstate.NewTaskEngineState(),
+		minimumAgeBeforeDeletion: config.DefaultImageDeletionAge,
+		numImagesToDelete:        config.DefaultNumImagesToDeletePerCycle,
+		imageCleanupTimeInterval: config.DefaultImageCleanupTimeInterval,
+	}
+	imageManager.SetDataClient(data.NewNoopClient())
+
+	protectedImageState := &image.ImageState{
+		Image:          &image.Image{ImageID: "sha256:protected"},
+		PulledAt:       time.Now().AddDate(0, -2, 0),
+		ProtectedUntil: time.Now().Add(time.Hour),
+	}
+	expiredImageState := &image.ImageState{
+		Image:          &image.Image{ImageID: "sha256:expired"},
+		PulledAt:       time.Now().AddDate(0, -2, 0),
+		ProtectedUntil: time.Now().Add(-time.Hour),
+	}
+	imageManager.addImageState(protectedImageState)
+	imageManager.addImageState(expiredImageState)
+	imageManager.imageStatesConsideredForDeletion = imageManager.imagesConsiderForDeletion(
+		imageManager.getAllImageStates())
+
+	imageStates := imageManager.getCandidateImagesForDeletion()
+	assert.Equal(t, []*image.ImageState{expiredImageState}, imageStates)
+}
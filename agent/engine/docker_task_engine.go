@@ -20,6 +20,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -29,6 +30,7 @@ import (
 	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
 	apierrormsgs "github.com/aws/amazon-ecs-agent/agent/api/errormessages"
 	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
+	"github.com/aws/amazon-ecs-agent/agent/cdi"
 	"github.com/aws/amazon-ecs-agent/agent/config"
 	"github.com/aws/amazon-ecs-agent/agent/containermetadata"
 	"github.com/aws/amazon-ecs-agent/agent/data"
@@ -39,6 +41,7 @@ import (
 	"github.com/aws/amazon-ecs-agent/agent/engine/dependencygraph"
 	"github.com/aws/amazon-ecs-agent/agent/engine/dockerstate"
 	"github.com/aws/amazon-ecs-agent/agent/engine/execcmd"
+	"github.com/aws/amazon-ecs-agent/agent/engine/imagesignature"
 	"github.com/aws/amazon-ecs-agent/agent/engine/serviceconnect"
 	"github.com/aws/amazon-ecs-agent/agent/statechange"
 	"github.com/aws/amazon-ecs-agent/agent/taskresource"
@@ -55,6 +58,7 @@ import (
 	"github.com/aws/amazon-ecs-agent/ecs-agent/eventstream"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tracing"
 	commonutils "github.com/aws/amazon-ecs-agent/ecs-agent/utils"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/utils/retry"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/utils/ttime"
@@ -214,6 +218,9 @@ type DockerTaskEngine struct {
 	daemonTasksLock sync.RWMutex
 	daemonTasks     map[string]*apitask.Task
 
+	faultCleanerLock sync.RWMutex
+	faultCleaner     func(taskARN string)
+
 	// taskSteadyStatePollInterval is the duration that a managed task waits
 	// once the task gets into steady state before polling the state of all of
 	// the task's containers to re-evaluate if the task is still in steady state
@@ -234,6 +241,9 @@ type DockerTaskEngine struct {
 	stopContainerBackoffMin   time.Duration
 	stopContainerBackoffMax   time.Duration
 	namespaceHelper           ecscni.NamespaceHelper
+	// imageVerifier verifies the signatures of the images before the containers are created from them, it's nil
+	// when no image trust policy is configured
+	imageVerifier imagesignature.Verifier
 }
 
 // NewDockerTaskEngine returns a created, but uninitialized, DockerTaskEngine.
@@ -285,6 +295,10 @@ func NewDockerTaskEngine(cfg *config.Config,
 		daemonTasks:                       make(map[string]*apitask.Task),
 	}
 
+	if cfg.ImageTrustPolicyFile != "" {
+		dockerTaskEngine.imageVerifier = imagesignature.NewVerifier(cfg.ImageTrustPolicyFile)
+	}
+
 	dockerTaskEngine.initializeContainerStatusToTransitionFunction()
 
 	return dockerTaskEngine
@@ -348,6 +362,16 @@ func (engine *DockerTaskEngine) MarshalJSON() ([]byte, error) {
 	return engine.state.MarshalJSON()
 }
 
+// taskLogContext returns the context of the log messages about the task. It carries the fields identifying the
+// task, in addition to the ones of the context of the engine, e.g. the cluster and the container instance.
+func (engine *DockerTaskEngine) taskLogContext(task *apitask.Task) context.Context {
+	ctx := engine.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return task.LogContext(ctx)
+}
+
 // Init initializes a DockerTaskEngine such that it may communicate with docker
 // and operate normally.
 // This function must be called before any other function, except serializing and deserializing, can succeed without error.
@@ -465,6 +489,15 @@ func (engine *DockerTaskEngine) tryDequeueWaitingTasks(task *managedTask) bool {
 		engine.returnWaitingTask()
 		return true
 	}
+	allocated, err := engine.allocateCDIDevices(task.Task)
+	if err != nil {
+		engine.failWaitingTask(err)
+		return true
+	}
+	if !allocated {
+		task.setHostResourcesBlockedReason([]string{CDI})
+		return false
+	}
 	taskHostResources := task.ToHostResources()
 	consumed, err := task.engine.hostResourceManager.consume(task.Arn, taskHostResources)
 	if err != nil {
@@ -475,10 +508,113 @@ func (engine *DockerTaskEngine) tryDequeueWaitingTasks(task *managedTask) bool {
 		engine.startWaitingTask()
 		return true
 	}
+	task.setHostResourcesBlockedReason(task.engine.hostResourceManager.unavailableResources(taskHostResources))
 	return false
 	// not consumed, go to wait
 }
 
+// allocateCDIDevices allocates free CDI devices of the host to the containers of the task, for the kinds of
+// devices they request. The devices are allocated again each time the task tries to consume the host resources,
+// and nothing is allocated when CDI support is disabled, as the containers don't request devices then.
+// It returns false when there aren't enough free devices yet, and an error when the host doesn't have enough
+// devices, or when the task requests devices which can't be injected through docker.
+func (engine *DockerTaskEngine) allocateCDIDevices(task *apitask.Task) (bool, error) {
+	unsupported := engine.getUnsupportedCDIDevices()
+	// The devices requested by name can't be allocated to the kind requests of the task
+	taken := make(map[string]struct{})
+	for _, container := range task.Containers {
+		container.SetAllocatedCDIDevices(nil)
+		for _, device := range container.GetCDIDevices() {
+			if reason, ok := unsupported[device]; ok {
+				return false, fmt.Errorf("CDI device %s can't be injected through docker: %s", device, reason)
+			}
+			taken[device] = struct{}{}
+		}
+	}
+
+	containerRequests := make([]map[string]int, len(task.Containers))
+	kindRequests := make(map[string]int)
+	for i, container := range task.Containers {
+		requests, err := container.GetCDIDeviceKindRequests()
+		if err != nil {
+			return false, fmt.Errorf("container %s: %w", container.Name, err)
+		}
+		containerRequests[i] = requests
+		for kind, count := range requests {
+			kindRequests[kind] += count
+		}
+	}
+	if len(kindRequests) == 0 {
+		return true, nil
+	}
+
+	freeDevices := make(map[string][]string, len(kindRequests))
+	for kind, count := range kindRequests {
+		devices, free := engine.hostResourceManager.cdiDevicesOfKind(kind)
+		available := 0
+		for _, device := range devices {
+			if _, ok := taken[device]; !ok {
+				available++
+			}
+		}
+		if available < count {
+			return false, newInsufficientCDIDevicesError(kind, count, available, unsupported)
+		}
+		freeDevices[kind] = free
+	}
+
+	for i, container := range task.Containers {
+		kinds := make([]string, 0, len(containerRequests[i]))
+		for kind := range containerRequests[i] {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		var devices []string
+		for _, kind := range kinds {
+			count := containerRequests[i][kind]
+			for len(freeDevices[kind]) > 0 && count > 0 {
+				device := freeDevices[kind][0]
+				freeDevices[kind] = freeDevices[kind][1:]
+				if _, ok := taken[device]; ok {
+					continue
+				}
+				taken[device] = struct{}{}
+				devices = append(devices, device)
+				count--
+			}
+			if count > 0 {
+				logger.Info("Not enough free CDI devices, task waiting for devices to be released", logger.Fields{
+					field.TaskARN: task.Arn,
+					"kind":        kind,
+				})
+				for _, container := range task.Containers {
+					container.SetAllocatedCDIDevices(nil)
+				}
+				return false, nil
+			}
+		}
+		container.SetAllocatedCDIDevices(devices)
+	}
+	return true, nil
+}
+
+// newInsufficientCDIDevicesError returns the error of a task requesting more devices of a kind than the host has,
+// with the reasons why the devices of the kind which can't be injected through docker are unsupported
+func newInsufficientCDIDevicesError(kind string, requested, available int, unsupported map[string]string) error {
+	var reasons []string
+	for device, reason := range unsupported {
+		if cdi.Kind(device) == kind {
+			reasons = append(reasons, fmt.Sprintf("CDI device %s can't be injected through docker: %s", device, reason))
+		}
+	}
+	sort.Strings(reasons)
+	msg := fmt.Sprintf("task requests %d CDI devices of kind %s, the host has %d", requested, kind, available)
+	if len(reasons) > 0 {
+		msg += "; " + strings.Join(reasons, "; ")
+	}
+	return errors.New(msg)
+}
+
 // To be called when resources are not to be consumed by host resource manager, just dequeues and returns
 func (engine *DockerTaskEngine) returnWaitingTask() {
 	task, _ := engine.dequeueTask()
@@ -539,8 +675,7 @@ func (engine *DockerTaskEngine) monitorExecAgentRunning(ctx context.Context,
 	task := mTask.Task
 	dockerID, err := engine.getDockerID(task, c)
 	if err != nil {
-		logger.Error("Could not retrieve docker id for container", logger.Fields{
-			field.TaskID:    task.GetID(),
+		logger.ErrorCtx(engine.taskLogContext(task), "Could not retrieve docker id for container", logger.Fields{
 			field.Container: c.Name,
 		})
 		return
@@ -553,8 +688,7 @@ func (engine *DockerTaskEngine) monitorExecAgentRunning(ctx context.Context,
 	time.Sleep(retry.AddJitter(time.Nanosecond, engine.monitorExecAgentsInterval/2))
 	status, err := engine.execCmdMgr.RestartAgentIfStopped(ctx, engine.client, task, c, dockerID)
 	if err != nil {
-		logger.Error("Failed to restart ExecCommandAgent Process for container", logger.Fields{
-			field.TaskID:    task.GetID(),
+		logger.ErrorCtx(engine.taskLogContext(task), "Failed to restart ExecCommandAgent Process for container", logger.Fields{
 			field.Container: c.Name,
 			field.DockerId:  dockerID,
 			field.Error:     err,
@@ -599,6 +733,14 @@ func (engine *DockerTaskEngine) SetDataClient(client data.Client) {
 	engine.dataClient = client
 }
 
+// SetTaskFaultCleaner sets the function that is used by the DockerTaskEngine to clean up the
+// faults injected into a stopped task.
+func (engine *DockerTaskEngine) SetTaskFaultCleaner(cleaner func(taskARN string)) {
+	engine.faultCleanerLock.Lock()
+	defer engine.faultCleanerLock.Unlock()
+	engine.faultCleaner = cleaner
+}
+
 func (engine *DockerTaskEngine) Context() context.Context {
 	return engine.ctx
 }
@@ -776,16 +918,14 @@ func updateContainerMetadata(metadata *dockerapi.DockerContainerMetadata, contai
 // synchronizeContainerStatus checks and updates the container status with docker
 func (engine *DockerTaskEngine) synchronizeContainerStatus(container *apicontainer.DockerContainer, task *apitask.Task) {
 	if container.DockerID == "" {
-		logger.Debug("Found container potentially created while we were down", logger.Fields{
-			field.TaskID:    task.GetID(),
+		logger.DebugCtx(engine.taskLogContext(task), "Found container potentially created while we were down", logger.Fields{
 			field.Container: container.DockerName,
 		})
 		// Figure out the dockerid
 		describedContainer, err := engine.client.InspectContainer(engine.ctx,
 			container.DockerName, dockerclient.InspectContainerTimeout)
 		if err != nil {
-			logger.Warn("Error getting container with name", logger.Fields{
-				field.TaskID:    task.GetID(),
+			logger.WarnCtx(engine.taskLogContext(task), "Error getting container with name", logger.Fields{
 				field.Container: container.DockerName,
 				field.Error:     err,
 			})
@@ -800,8 +940,7 @@ func (engine *DockerTaskEngine) synchronizeContainerStatus(container *apicontain
 			engine.state.AddContainer(container, task)
 			err := engine.imageManager.RecordContainerReference(container.Container)
 			if err != nil {
-				logger.Warn("Unable to add container reference to image state", logger.Fields{
-					field.TaskID:    task.GetID(),
+				logger.WarnCtx(engine.taskLogContext(task), "Unable to add container reference to image state", logger.Fields{
 					field.Container: container.DockerName,
 					field.Error:     err,
 				})
@@ -815,8 +954,7 @@ func (engine *DockerTaskEngine) synchronizeContainerStatus(container *apicontain
 		currentState = apicontainerstatus.ContainerStopped
 		// If this is a Docker API error
 		if metadata.Error.ErrorName() == dockerapi.CannotDescribeContainerErrorName {
-			logger.Warn("Could not describe previously known container; assuming dead", logger.Fields{
-				field.TaskID:    task.GetID(),
+			logger.WarnCtx(engine.taskLogContext(task), "Could not describe previously known container; assuming dead", logger.Fields{
 				field.Container: container.DockerName,
 				field.DockerId:  container.DockerID,
 				field.Error:     metadata.Error,
@@ -825,8 +963,7 @@ func (engine *DockerTaskEngine) synchronizeContainerStatus(container *apicontain
 				container.Container.ApplyingError = apierrors.NewNamedError(&ContainerVanishedError{})
 				err := engine.imageManager.RemoveContainerReferenceFromImageState(container.Container)
 				if err != nil {
-					logger.Warn("Could not remove container reference from image state", logger.Fields{
-						field.TaskID:    task.GetID(),
+					logger.WarnCtx(engine.taskLogContext(task), "Could not remove container reference from image state", logger.Fields{
 						field.Container: container.DockerName,
 						"image":         container.Container.Image,
 						field.Error:     err,
@@ -843,8 +980,7 @@ func (engine *DockerTaskEngine) synchronizeContainerStatus(container *apicontain
 		updateContainerMetadata(&metadata, container.Container, task)
 		err := engine.imageManager.RecordContainerReference(container.Container)
 		if err != nil {
-			logger.Warn("Unable to add container reference to image state", logger.Fields{
-				field.TaskID:    task.GetID(),
+			logger.WarnCtx(engine.taskLogContext(task), "Unable to add container reference to image state", logger.Fields{
 				field.Container: container.DockerName,
 				field.Error:     err,
 			})
@@ -891,8 +1027,7 @@ func (engine *DockerTaskEngine) sweepTask(task *apitask.Task) {
 	for _, cont := range task.Containers {
 		err := engine.removeContainer(task, cont)
 		if err != nil {
-			logger.Error("Unable to remove old container", logger.Fields{
-				field.TaskID:    task.GetID(),
+			logger.ErrorCtx(engine.taskLogContext(task), "Unable to remove old container", logger.Fields{
 				field.Container: cont.Name,
 				field.Error:     err,
 			})
@@ -903,8 +1038,7 @@ func (engine *DockerTaskEngine) sweepTask(task *apitask.Task) {
 		}
 		err = engine.imageManager.RemoveContainerReferenceFromImageState(cont)
 		if err != nil {
-			logger.Error("Unable to remove container reference from image state", logger.Fields{
-				field.TaskID:    task.GetID(),
+			logger.ErrorCtx(engine.taskLogContext(task), "Unable to remove container reference from image state", logger.Fields{
 				field.Container: cont.Name,
 				field.Error:     err,
 			})
@@ -915,9 +1049,8 @@ func (engine *DockerTaskEngine) sweepTask(task *apitask.Task) {
 	if engine.cfg.ContainerMetadataEnabled.Enabled() {
 		err := engine.metadataManager.Clean(task.Arn)
 		if err != nil {
-			logger.Warn("Error cleaning task metadata", logger.Fields{
-				field.TaskID: task.GetID(),
-				field.Error:  err,
+			logger.WarnCtx(engine.taskLogContext(task), "Error cleaning task metadata", logger.Fields{
+				field.Error: err,
 			})
 		}
 	}
@@ -929,14 +1062,12 @@ func (engine *DockerTaskEngine) deleteTask(task *apitask.Task) {
 	for _, resource := range task.GetResources() {
 		err := resource.Cleanup()
 		if err != nil {
-			logger.Warn("Unable to cleanup resource", logger.Fields{
-				field.TaskID:   task.GetID(),
+			logger.WarnCtx(engine.taskLogContext(task), "Unable to cleanup resource", logger.Fields{
 				field.Resource: resource.GetName(),
 				field.Error:    err,
 			})
 		} else {
-			logger.Info("Resource cleanup complete", logger.Fields{
-				field.TaskID:   task.GetID(),
+			logger.InfoCtx(engine.taskLogContext(task), "Resource cleanup complete", logger.Fields{
 				field.Resource: resource.GetName(),
 			})
 		}
@@ -972,16 +1103,14 @@ func (engine *DockerTaskEngine) deleteTask(task *apitask.Task) {
 		// ENIs that exist only as logical associations on another interface do not have
 		// attachments that need to be removed.
 		if taskENI.IsStandardENI() {
-			logger.Debug("Removing ENI from agent state", logger.Fields{
-				field.TaskID: task.GetID(),
-				"eni":        taskENI.ID,
+			logger.DebugCtx(engine.taskLogContext(task), "Removing ENI from agent state", logger.Fields{
+				"eni": taskENI.ID,
 			})
 			engine.removeENIAttachmentData(taskENI.MacAddress)
 			engine.state.RemoveENIAttachment(taskENI.MacAddress)
 		} else {
-			logger.Debug("Skipping removing logical ENI from agent state because it's not a standard ENI", logger.Fields{
-				field.TaskID: task.GetID(),
-				"eni":        taskENI.ID,
+			logger.DebugCtx(engine.taskLogContext(task), "Skipping removing logical ENI from agent state because it's not a standard ENI", logger.Fields{
+				"eni": taskENI.ID,
 			})
 		}
 	}
@@ -989,9 +1118,7 @@ func (engine *DockerTaskEngine) deleteTask(task *apitask.Task) {
 	// Remove task and container data from database.
 	engine.removeTaskData(task)
 
-	logger.Info("Finished removing task data, removing task from managed tasks", logger.Fields{
-		field.TaskID: task.GetID(),
-	})
+	logger.InfoCtx(engine.taskLogContext(task), "Finished removing task data, removing task from managed tasks")
 	delete(engine.managedTasks, task.Arn)
 	engine.tasksLock.Unlock()
 }
@@ -1009,17 +1136,15 @@ func (engine *DockerTaskEngine) EmitTaskEvent(task *apitask.Task, reason string)
 	event, err := api.NewTaskStateChangeEvent(task, reason)
 	if err != nil {
 		if _, ok := err.(api.ErrShouldNotSendEvent); ok {
-			logger.Debug(err.Error(), logger.Fields{field.TaskID: task.GetID()})
+			logger.DebugCtx(engine.taskLogContext(task), err.Error())
 		} else {
-			logger.Error("Unable to create task state change event", logger.Fields{
-				field.TaskID: task.GetID(),
-				field.Error:  err,
+			logger.ErrorCtx(engine.taskLogContext(task), "Unable to create task state change event", logger.Fields{
+				field.Error: err,
 			})
 		}
 		return
 	}
-	logger.Info("Preparing to send change event", logger.Fields{
-		field.TaskID: task.GetID(),
+	logger.InfoCtx(engine.taskLogContext(task), "Preparing to send change event", logger.Fields{
 		field.Status: event.Status.String(),
 		field.Reason: event.Reason,
 	})
@@ -1119,9 +1244,7 @@ func (engine *DockerTaskEngine) handleDockerEvent(event dockerapi.DockerContaine
 	}
 	cont, ok := engine.state.ContainerByID(event.DockerID)
 	if !ok {
-		logger.Debug("Unable to map container id to container", logger.Fields{
-			field.TaskID: task.GetID(),
-		}, eventFields)
+		logger.DebugCtx(engine.taskLogContext(task), "Unable to map container id to container", eventFields)
 		return
 	}
 
@@ -1129,15 +1252,23 @@ func (engine *DockerTaskEngine) handleDockerEvent(event dockerapi.DockerContaine
 	// no need to process this in task manager
 	if event.Type == apicontainer.ContainerHealthEvent {
 		if cont.Container.HealthStatusShouldBeReported() {
-			logger.Debug("Updating container health status", logger.Fields{
-				field.TaskID:    task.GetID(),
+			logger.DebugCtx(engine.taskLogContext(task), "Updating container health status", logger.Fields{
 				field.Container: cont.Container.Name,
 				field.DockerId:  cont.DockerID,
 				"status":        event.DockerContainerMetadata.Health.Status.String(),
 				"exitCode":      event.DockerContainerMetadata.Health.ExitCode,
 				"output":        event.DockerContainerMetadata.Health.Output,
 			})
-			cont.Container.SetHealthStatus(event.DockerContainerMetadata.Health)
+			health := event.DockerContainerMetadata.Health
+			if cont.Container.GetHealthStatus().Status != health.Status {
+				engine.tasksLock.RLock()
+				managedTask, ok := engine.managedTasks[task.Arn]
+				engine.tasksLock.RUnlock()
+				if ok {
+					managedTask.events().recordContainerHealth(cont.Container.Name, health.Status.String(), health.Output)
+				}
+			}
+			cont.Container.SetHealthStatus(health)
 		}
 		return
 	}
@@ -1146,18 +1277,12 @@ func (engine *DockerTaskEngine) handleDockerEvent(event dockerapi.DockerContaine
 	managedTask, ok := engine.managedTasks[task.Arn]
 	engine.tasksLock.RUnlock()
 	if !ok {
-		logger.Critical("Could not find managed task for docker event", logger.Fields{
-			field.TaskID: task.GetID(),
-		}, eventFields)
+		logger.CriticalCtx(engine.taskLogContext(task), "Could not find managed task for docker event", eventFields)
 		return
 	}
-	logger.Debug("Writing docker event to the task", logger.Fields{
-		field.TaskID: task.GetID(),
-	}, eventFields)
+	logger.DebugCtx(engine.taskLogContext(task), "Writing docker event to the task", eventFields)
 	managedTask.emitDockerContainerChange(dockerContainerChange{container: cont.Container, event: event})
-	logger.Debug("Wrote docker event to the task", logger.Fields{
-		field.TaskID: task.GetID(),
-	}, eventFields)
+	logger.DebugCtx(engine.taskLogContext(task), "Wrote docker event to the task", eventFields)
 }
 
 // StateChangeEvents returns channels to read task and container state changes. These
@@ -1172,9 +1297,8 @@ func (engine *DockerTaskEngine) AddTask(task *apitask.Task) {
 	err := task.PostUnmarshalTask(engine.cfg, engine.credentialsManager,
 		engine.resourceFields, engine.client, engine.ctx)
 	if err != nil {
-		logger.Error("Unable to add task to the engine", logger.Fields{
-			field.TaskID: task.GetID(),
-			field.Error:  err,
+		logger.ErrorCtx(engine.taskLogContext(task), "Unable to add task to the engine", logger.Fields{
+			field.Error: err,
 		})
 		task.SetKnownStatus(apitaskstatus.TaskStopped)
 		task.SetDesiredStatus(apitaskstatus.TaskStopped)
@@ -1182,9 +1306,7 @@ func (engine *DockerTaskEngine) AddTask(task *apitask.Task) {
 		return
 	}
 	if task.IsEBSTaskAttachEnabled() {
-		logger.Info("Task is EBS TaskAttachEnabled", logger.Fields{
-			field.TaskID: task.GetID(),
-		})
+		logger.InfoCtx(engine.taskLogContext(task), "Task is EBS TaskAttachEnabled")
 	}
 	// Check if ServiceConnect is Needed
 	if task.IsServiceConnectEnabled() {
@@ -1192,9 +1314,8 @@ func (engine *DockerTaskEngine) AddTask(task *apitask.Task) {
 			engine.serviceconnectRelay, err = engine.serviceconnectManager.CreateInstanceTask(engine.cfg)
 
 			if err != nil {
-				logger.Error("Unable to start relay for task in the engine", logger.Fields{
-					field.TaskID: task.GetID(),
-					field.Error:  err,
+				logger.ErrorCtx(engine.taskLogContext(task), "Unable to start relay for task in the engine", logger.Fields{
+					field.Error: err,
 				})
 				task.SetKnownStatus(apitaskstatus.TaskStopped)
 				task.SetDesiredStatus(apitaskstatus.TaskStopped)
@@ -1228,9 +1349,7 @@ func (engine *DockerTaskEngine) UpsertTask(task *apitask.Task) {
 		if dependencygraph.ValidDependencies(task, engine.cfg) {
 			engine.startTask(task)
 		} else {
-			logger.Error("Task has circular dependencies; unable to start", logger.Fields{
-				field.TaskID: task.GetID(),
-			})
+			logger.ErrorCtx(engine.taskLogContext(task), "Task has circular dependencies; unable to start")
 			task.SetKnownStatus(apitaskstatus.TaskStopped)
 			task.SetDesiredStatus(apitaskstatus.TaskStopped)
 			err := TaskDependencyError{task.Arn}
@@ -1444,8 +1563,7 @@ func (engine *DockerTaskEngine) pullContainer(task *apitask.Task, container *api
 			timestamp := engine.time().Now()
 			task.SetPullStoppedAt(timestamp)
 		}()
-		logger.Info("Pulling image for container concurrently", logger.Fields{
-			field.TaskID:    task.GetID(),
+		logger.InfoCtx(engine.taskLogContext(task), "Pulling image for container concurrently", logger.Fields{
 			field.Container: container.Name,
 			field.Image:     container.Image,
 		})
@@ -1513,19 +1631,16 @@ func (engine *DockerTaskEngine) imagePullRequired(imagePullBehavior config.Image
 }
 
 func (engine *DockerTaskEngine) concurrentPull(task *apitask.Task, container *apicontainer.Container) dockerapi.DockerContainerMetadata {
-	logger.Debug("Attempting to obtain ImagePullDeleteLock to pull image for container", logger.Fields{
-		field.TaskID:    task.GetID(),
+	logger.DebugCtx(engine.taskLogContext(task), "Attempting to obtain ImagePullDeleteLock to pull image for container", logger.Fields{
 		field.Container: container.Name,
 		field.Image:     container.Image,
 	})
 	ImagePullDeleteLock.RLock()
-	logger.Debug("Acquired ImagePullDeleteLock, start pulling image for container", logger.Fields{
-		field.TaskID:    task.GetID(),
+	logger.DebugCtx(engine.taskLogContext(task), "Acquired ImagePullDeleteLock, start pulling image for container", logger.Fields{
 		field.Container: container.Name,
 		field.Image:     container.Image,
 	})
-	defer logger.Debug("Released ImagePullDeleteLock after pulling image for container", logger.Fields{
-		field.TaskID:    task.GetID(),
+	defer logger.DebugCtx(engine.taskLogContext(task), "Released ImagePullDeleteLock after pulling image for container", logger.Fields{
 		field.Container: container.Name,
 		field.Image:     container.Image,
 	})
@@ -1535,8 +1650,7 @@ func (engine *DockerTaskEngine) concurrentPull(task *apitask.Task, container *ap
 	pullStart := engine.time().Now()
 	ok := task.SetPullStartedAt(pullStart)
 	if ok {
-		logger.Info("Recording start time for image pull", logger.Fields{
-			field.TaskID:    task.GetID(),
+		logger.InfoCtx(engine.taskLogContext(task), "Recording start time for image pull", logger.Fields{
 			field.Container: container.Name,
 			field.Image:     container.Image,
 			"pullStart":     pullStart.String(),
@@ -1546,16 +1660,14 @@ func (engine *DockerTaskEngine) concurrentPull(task *apitask.Task, container *ap
 	metadata := engine.pullAndUpdateContainerReference(task, container)
 	if metadata.Error == nil {
 		elapsed := time.Since(pullStart)
-		logger.Info("Finished pulling image for container", logger.Fields{
-			field.TaskID:    task.GetID(),
+		logger.InfoCtx(engine.taskLogContext(task), "Finished pulling image for container", logger.Fields{
 			field.Container: container.Name,
 			field.Image:     container.Image,
 			field.Elapsed:   elapsed.String(),
 			"elapsedMs":     elapsed.Milliseconds(),
 		})
 	} else {
-		logger.Error("Failed to pull image for container", logger.Fields{
-			field.TaskID:    task.GetID(),
+		logger.ErrorCtx(engine.taskLogContext(task), "Failed to pull image for container", logger.Fields{
 			field.Container: container.Name,
 			field.Image:     container.Image,
 			field.Error:     metadata.Error,
@@ -1568,8 +1680,7 @@ func (engine *DockerTaskEngine) pullAndUpdateContainerReference(task *apitask.Ta
 	// If a task is blocked here for some time, and before it starts pulling image,
 	// the task's desired status is set to stopped, then don't pull the image
 	if task.GetDesiredStatus() == apitaskstatus.TaskStopped {
-		logger.Warn("Task's desired status is stopped, skipping image pull for container", logger.Fields{
-			field.TaskID:    task.GetID(),
+		logger.WarnCtx(engine.taskLogContext(task), "Task's desired status is stopped, skipping image pull for container", logger.Fields{
 			field.Container: container.Name,
 			field.Image:     container.Image,
 		})
@@ -1593,8 +1704,7 @@ func (engine *DockerTaskEngine) pullAndUpdateContainerReference(task *apitask.Ta
 		// This ensures that the image version referenced by the digest is pulled.
 		canonicalRef, err := referenceutil.GetCanonicalRef(imageRef, imageDigest)
 		if err != nil {
-			logger.Error("Failed to prepare a canonical reference. Cannot pull image.", logger.Fields{
-				field.TaskID:      task.GetID(),
+			logger.ErrorCtx(engine.taskLogContext(task), "Failed to prepare a canonical reference. Cannot pull image.", logger.Fields{
 				field.Container:   container.Name,
 				field.Image:       imageRef,
 				field.ImageDigest: imageDigest,
@@ -1609,8 +1719,7 @@ func (engine *DockerTaskEngine) pullAndUpdateContainerReference(task *apitask.Ta
 			}
 		}
 		imageRef = canonicalRef.String()
-		logger.Info("Prepared a canonical reference for image pull", logger.Fields{
-			field.TaskID:      task.GetID(),
+		logger.InfoCtx(engine.taskLogContext(task), "Prepared a canonical reference for image pull", logger.Fields{
 			field.Container:   container.Name,
 			field.Image:       container.Image,
 			field.ImageDigest: imageDigest,
@@ -1618,7 +1727,7 @@ func (engine *DockerTaskEngine) pullAndUpdateContainerReference(task *apitask.Ta
 		})
 	}
 
-	metadata := engine.client.PullImage(engine.ctx, imageRef, container.RegistryAuthentication, engine.cfg.ImagePullTimeout)
+	metadata, pulledRef, createdMirrorRef := engine.pullImage(task, container, imageRef)
 
 	// Don't add internal images(created by ecs-agent) into image manager state
 	if container.IsInternal() {
@@ -1626,18 +1735,17 @@ func (engine *DockerTaskEngine) pullAndUpdateContainerReference(task *apitask.Ta
 	}
 	pullSucceeded := metadata.Error == nil
 
-	if pullSucceeded && imageRef != container.Image && !referenceutil.DigestExists(container.Image) {
-		// Resolved image manifest digest was used to pull the image.
+	if pullSucceeded && pulledRef != container.Image && !referenceutil.DigestExists(container.Image) {
+		// Resolved image manifest digest or a registry mirror was used to pull the image.
 		// Tag the pulled image so that it can be found using the image reference in the task.
 		ctx, cancel := context.WithTimeout(engine.ctx, tagImageTimeout)
 		defer cancel()
-		err := engine.client.TagImage(ctx, imageRef, container.Image)
+		err := engine.client.TagImage(ctx, pulledRef, container.Image)
 		if err != nil {
-			logger.Error("Failed to tag image after pull", logger.Fields{
-				field.TaskID:    task.GetID(),
+			logger.ErrorCtx(engine.taskLogContext(task), "Failed to tag image after pull", logger.Fields{
 				field.Container: container.Name,
 				field.Image:     container.Image,
-				field.ImageRef:  imageRef,
+				field.ImageRef:  pulledRef,
 				field.Error:     err,
 			})
 			if errors.Is(err, context.DeadlineExceeded) {
@@ -1650,12 +1758,14 @@ func (engine *DockerTaskEngine) pullAndUpdateContainerReference(task *apitask.Ta
 			}
 			pullSucceeded = false
 		} else {
-			logger.Info("Successfully tagged image", logger.Fields{
-				field.TaskID:    task.GetID(),
+			logger.InfoCtx(engine.taskLogContext(task), "Successfully tagged image", logger.Fields{
 				field.Container: container.Name,
 				field.Image:     container.Image,
-				field.ImageRef:  imageRef,
+				field.ImageRef:  pulledRef,
 			})
+			if createdMirrorRef {
+				engine.untagMirrorRef(task, container, pulledRef)
+			}
 		}
 	}
 
@@ -1669,8 +1779,7 @@ func (engine *DockerTaskEngine) pullAndUpdateContainerReference(task *apitask.Ta
 		// search the image in local cached images
 		if engine.cfg.DependentContainersPullUpfront.Enabled() && engine.cfg.ImagePullBehavior != config.ImagePullAlwaysBehavior {
 			if _, err := engine.client.InspectImage(imageRef); err != nil {
-				logger.Error("Failed to find cached image for container", logger.Fields{
-					field.TaskID:    task.GetID(),
+				logger.ErrorCtx(engine.taskLogContext(task), "Failed to find cached image for container", logger.Fields{
 					field.Container: container.Name,
 					field.Image:     imageRef,
 					field.Error:     err,
@@ -1683,8 +1792,7 @@ func (engine *DockerTaskEngine) pullAndUpdateContainerReference(task *apitask.Ta
 				}
 				return dockerapi.DockerContainerMetadata{Error: metadata.Error}
 			}
-			logger.Info("Found cached image, use it directly for container", logger.Fields{
-				field.TaskID:    task.GetID(),
+			logger.InfoCtx(engine.taskLogContext(task), "Found cached image, use it directly for container", logger.Fields{
 				field.Container: container.Name,
 				field.Image:     imageRef,
 			})
@@ -1703,6 +1811,79 @@ func (engine *DockerTaskEngine) pullAndUpdateContainerReference(task *apitask.Ta
 	return metadata
 }
 
+// pullImage pulls the image reference of the container. The image is pulled from its registry mirror first
+// when one is configured for it, and from its own registry when the pull from the mirror fails. The reference
+// of the pulled image is returned along with the metadata of the pull, and whether the pull created the
+// reference of the image in its registry mirror.
+func (engine *DockerTaskEngine) pullImage(task *apitask.Task, container *apicontainer.Container,
+	imageRef string) (dockerapi.DockerContainerMetadata, string, bool) {
+	// An image referenced by digest in the task can't be tagged with that reference after being
+	// pulled from a mirror, so it's always pulled from its own registry
+	if !referenceutil.DigestExists(container.Image) {
+		if mirrorRef, ok := referenceutil.GetMirrorRef(imageRef, engine.cfg.RegistryMirrors); ok {
+			logger.InfoCtx(engine.taskLogContext(task), "Pulling image for container from registry mirror", logger.Fields{
+				field.Container: container.Name,
+				field.ImageRef:  imageRef,
+				"mirrorRef":     mirrorRef,
+			})
+			// The mirror reference is only removed after the pull when it didn't exist before
+			_, err := engine.client.InspectImage(mirrorRef)
+			mirrorRefExisted := err == nil
+			metadata := engine.client.PullImage(engine.ctx, mirrorRef, engine.mirrorRegistryAuth(task, mirrorRef),
+				engine.cfg.ImagePullTimeout)
+			if metadata.Error == nil {
+				return metadata, mirrorRef, !mirrorRefExisted
+			}
+			logger.WarnCtx(engine.taskLogContext(task), "Failed to pull image from registry mirror, falling back to its registry",
+				logger.Fields{
+					field.Container: container.Name,
+					field.ImageRef:  imageRef,
+					"mirrorRef":     mirrorRef,
+					field.Error:     metadata.Error,
+				})
+		}
+	}
+	return engine.client.PullImage(engine.ctx, imageRef, container.RegistryAuthentication, engine.cfg.ImagePullTimeout),
+		imageRef, false
+}
+
+// mirrorRegistryAuth returns the registry auth data of a registry mirror. The registry authentication of the
+// container is specific to the registry of the image, so it isn't used for the mirror. The mirrors hosted in ECR,
+// e.g. ECR pull through caches, are authenticated with the task execution role when the task has one, and with
+// the instance credentials otherwise. The other mirrors are authenticated with the engine auth data.
+func (engine *DockerTaskEngine) mirrorRegistryAuth(task *apitask.Task,
+	mirrorRef string) *apicontainer.RegistryAuthenticationData {
+	authData := ecrRegistryAuth(mirrorRef)
+	if authData == nil {
+		return nil
+	}
+	if credentialsID := task.GetExecutionCredentialsID(); credentialsID != "" {
+		if executionCredentials, ok := engine.credentialsManager.GetTaskCredentials(credentialsID); ok {
+			authData.ECRAuthData.UseExecutionRole = true
+			authData.ECRAuthData.SetPullCredentials(executionCredentials.GetIAMRoleCredentials())
+		}
+	}
+	return authData
+}
+
+// untagMirrorRef removes the registry mirror reference created by the pull of an image once it's tagged with
+// the image reference of the task, so that the image manager can remove the image when it's no longer used.
+// The reference is kept when the image manager tracks it as the image of another container.
+func (engine *DockerTaskEngine) untagMirrorRef(task *apitask.Task, container *apicontainer.Container,
+	mirrorRef string) {
+	if _, ok := engine.imageManager.GetImageStateFromImageName(mirrorRef); ok {
+		return
+	}
+	if err := engine.client.RemoveImage(engine.ctx, mirrorRef, dockerclient.RemoveImageTimeout); err != nil {
+		logger.WarnCtx(engine.taskLogContext(task), "Failed to remove registry mirror reference of image", logger.Fields{
+			field.Container: container.Name,
+			field.Image:     container.Image,
+			"mirrorRef":     mirrorRef,
+			field.Error:     err,
+		})
+	}
+}
+
 // Sets image registry auth credentials for the container.
 // Returns a cleanup function that may be called to clear the credentials from the container
 // when they are no longer needed. The cleanup function is `nil` if no credentials are needed
@@ -1716,8 +1897,7 @@ func (engine *DockerTaskEngine) setRegistryCredentials(
 	if container.ShouldPullWithExecutionRole() {
 		executionCredentials, ok := engine.credentialsManager.GetTaskCredentials(task.GetExecutionCredentialsID())
 		if !ok {
-			logger.Error("Unable to acquire ECR credentials to pull image for container", logger.Fields{
-				field.TaskID:        task.GetID(),
+			logger.ErrorCtx(engine.taskLogContext(task), "Unable to acquire ECR credentials to pull image for container", logger.Fields{
 				field.Container:     container.Name,
 				field.Image:         container.Image,
 				field.CredentialsID: commonutils.TruncateString(task.GetExecutionCredentialsID(), commonutils.CredentialsIDLogTruncationLen),
@@ -1729,8 +1909,7 @@ func (engine *DockerTaskEngine) setRegistryCredentials(
 		}
 
 		iamCredentials := executionCredentials.GetIAMRoleCredentials()
-		logger.Info("Setting task execution credentials for image pull registry auth", logger.Fields{
-			field.TaskID:        task.GetID(),
+		logger.InfoCtx(engine.taskLogContext(task), "Setting task execution credentials for image pull registry auth", logger.Fields{
 			field.Container:     container.Name,
 			field.Image:         container.Image,
 			field.RoleType:      iamCredentials.RoleType,
@@ -1744,8 +1923,7 @@ func (engine *DockerTaskEngine) setRegistryCredentials(
 	// Apply registry auth data from ASM if required
 	if container.ShouldPullWithASMAuth() {
 		if err := task.PopulateASMAuthData(container); err != nil {
-			logger.Error("Unable to acquire Docker registry credentials to pull image for container", logger.Fields{
-				field.TaskID:    task.GetID(),
+			logger.ErrorCtx(engine.taskLogContext(task), "Unable to acquire Docker registry credentials to pull image for container", logger.Fields{
 				field.Container: container.Name,
 				field.Image:     container.Image,
 				field.Error:     err,
@@ -1789,15 +1967,29 @@ func (engine *DockerTaskEngine) updateContainerReference(pullSucceeded bool, con
 }
 
 func (engine *DockerTaskEngine) createContainer(task *apitask.Task, container *apicontainer.Container) dockerapi.DockerContainerMetadata {
-	logger.Info("Creating container", logger.Fields{
-		field.TaskID:    task.GetID(),
+	logger.InfoCtx(engine.taskLogContext(task), "Creating container", logger.Fields{
 		field.Container: container.Name,
 	})
+
+	// Verify the signature of the image before creating the container from it. The images of the internal
+	// containers and of the Service Connect container are managed by the agent.
+	if engine.imageVerifier != nil && !container.IsInternal() &&
+		!(task.IsServiceConnectEnabled() && container == task.GetServiceConnectContainer()) {
+		if err := engine.imageVerifier.Verify(engine.ctx, container.Image, container.GetImageDigest()); err != nil {
+			logger.ErrorCtx(engine.taskLogContext(task), "Image signature verification failed for container", logger.Fields{
+				field.Container:   container.Name,
+				field.Image:       container.Image,
+				field.ImageDigest: container.GetImageDigest(),
+				field.Error:       err,
+			})
+			return dockerapi.DockerContainerMetadata{Error: err}
+		}
+	}
+
 	client := engine.client
 	if container.DockerConfig.Version != nil {
 		minVersion := dockerclient.GetSupportedDockerAPIVersion(dockerclient.DockerVersion(*container.DockerConfig.Version))
-		logger.Debug("CreateContainer: overriding docker API version in task payload", logger.Fields{
-			field.TaskID:              task.GetID(),
+		logger.DebugCtx(engine.taskLogContext(task), "CreateContainer: overriding docker API version in task payload", logger.Fields{
 			field.Container:           container.Name,
 			"usingDockerAPIVersion":   minVersion,
 			"payloadDockerAPIVersion": *container.DockerConfig.Version,
@@ -1884,8 +2076,7 @@ func (engine *DockerTaskEngine) createContainer(task *apitask.Task, container *a
 		// We need the Docker server version in order to generate the appropriate firelens log config
 		dockerServerVersion, err := engine.Version()
 		if err != nil {
-			logger.Error("Failed to determine Docker server version for Firelens log config generation", logger.Fields{
-				field.TaskID:    task.GetID(),
+			logger.ErrorCtx(engine.taskLogContext(task), "Failed to determine Docker server version for Firelens log config generation", logger.Fields{
 				field.Container: container.Name,
 				field.Error:     err,
 			})
@@ -1897,8 +2088,7 @@ func (engine *DockerTaskEngine) createContainer(task *apitask.Task, container *a
 		}
 		logConfig, err := getFirelensLogConfig(task, container, hostConfig, engine.cfg, dockerServerVersion)
 		if err != nil {
-			logger.Error("Failed to generate the Firelens log config", logger.Fields{
-				field.TaskID:    task.GetID(),
+			logger.ErrorCtx(engine.taskLogContext(task), "Failed to generate the Firelens log config", logger.Fields{
 				field.Container: container.Name,
 				field.Error:     err,
 			})
@@ -1924,8 +2114,7 @@ func (engine *DockerTaskEngine) createContainer(task *apitask.Task, container *a
 				var err error
 				targetContainer, err = task.GetBridgeModePauseContainerForTaskContainer(targetContainer)
 				if err != nil {
-					logger.Error("Failed to create container", logger.Fields{
-						field.TaskID:    task.GetID(),
+					logger.ErrorCtx(engine.taskLogContext(task), "Failed to create container", logger.Fields{
 						field.Container: container.Name,
 						field.Error: errors.New(fmt.Sprintf(
 							"container uses awsfirelens log driver but we failed to resolve Firelens bridge IP: %v", err)),
@@ -1992,10 +2181,26 @@ func (engine *DockerTaskEngine) createContainer(task *apitask.Task, container *a
 		}
 	}
 
+	// Mount the directories with the secret files written by the secret resources
+	hasSecretAsFile := func(s apicontainer.Secret) bool {
+		return s.Type == apicontainer.SecretTypeMountPoint
+	}
+	if engine.cfg.SecretFilesEnabled.Enabled() && container.HasSecret(hasSecretAsFile) {
+		if err := task.AddSecretFilesBindMounts(container, hostConfig, engine.cfg); err != nil {
+			return dockerapi.DockerContainerMetadata{Error: apierrors.NamedError(err)}
+		}
+	}
+
+	// Inject the CDI devices accounted for by the host resource manager
+	if len(container.GetCDIDevices()) > 0 {
+		if err := engine.injectCDIDevices(task, container, hostConfig); err != nil {
+			return dockerapi.DockerContainerMetadata{Error: apierrors.NamedError(err)}
+		}
+	}
+
 	// Populate credentialspec resource
 	if container.RequiresAnyCredentialSpec() {
-		logger.Debug("Obtained container with credentialspec resource requirement for task", logger.Fields{
-			field.TaskID:    task.GetID(),
+		logger.DebugCtx(engine.taskLogContext(task), "Obtained container with credentialspec resource requirement for task", logger.Fields{
 			field.Container: container.Name,
 		})
 		var credSpecResource *credentialspec.CredentialSpecResource
@@ -2025,8 +2230,7 @@ func (engine *DockerTaskEngine) createContainer(task *apitask.Task, container *a
 	if container.ShouldCreateWithEnvFiles() {
 		err := task.MergeEnvVarsFromEnvfiles(container)
 		if err != nil {
-			logger.Error("Error populating environment variables from specified files into container", logger.Fields{
-				field.TaskID:    task.GetID(),
+			logger.ErrorCtx(engine.taskLogContext(task), "Error populating environment variables from specified files into container", logger.Fields{
 				field.Container: container.Name,
 				field.Error:     err,
 			})
@@ -2037,8 +2241,7 @@ func (engine *DockerTaskEngine) createContainer(task *apitask.Task, container *a
 	if execcmd.IsExecEnabledContainer(container) {
 		err := engine.execCmdMgr.InitializeContainer(task, container, hostConfig)
 		if err != nil {
-			logger.Warn("Error initializing ExecCommandAgent; proceeding to start container without exec feature", logger.Fields{
-				field.TaskID:    task.GetID(),
+			logger.WarnCtx(engine.taskLogContext(task), "Error initializing ExecCommandAgent; proceeding to start container without exec feature", logger.Fields{
 				field.Container: container.Name,
 				field.Error:     err,
 			})
@@ -2049,8 +2252,7 @@ func (engine *DockerTaskEngine) createContainer(task *apitask.Task, container *a
 			if ok {
 				mTask.emitManagedAgentEvent(mTask.Task, container, execcmd.ExecuteCommandAgentName, fmt.Sprintf("ExecuteCommandAgent Initialization failed - %v", err))
 			} else {
-				logger.Error("Failed to update status of ExecCommandAgent Process for container", logger.Fields{
-					field.TaskID:    task.GetID(),
+				logger.ErrorCtx(engine.taskLogContext(task), "Failed to update status of ExecCommandAgent Process for container", logger.Fields{
 					field.Container: container.Name,
 					field.Error:     "managed task not found",
 				})
@@ -2087,8 +2289,7 @@ func (engine *DockerTaskEngine) createContainer(task *apitask.Task, container *a
 			DockerName: dockerContainerName,
 			Container:  container,
 		}, task)
-		logger.Info("Created container name mapping for task", logger.Fields{
-			field.TaskID:          task.GetID(),
+		logger.InfoCtx(engine.taskLogContext(task), "Created container name mapping for task", logger.Fields{
 			field.Container:       container.Name,
 			"dockerContainerName": dockerContainerName,
 		})
@@ -2099,16 +2300,14 @@ func (engine *DockerTaskEngine) createContainer(task *apitask.Task, container *a
 	if engine.cfg.ContainerMetadataEnabled.Enabled() && !container.IsInternal() {
 		info, infoErr := engine.client.Info(engine.ctx, dockerclient.InfoTimeout)
 		if infoErr != nil {
-			logger.Warn("Unable to get docker info", logger.Fields{
-				field.TaskID:    task.GetID(),
+			logger.WarnCtx(engine.taskLogContext(task), "Unable to get docker info", logger.Fields{
 				field.Container: container.Name,
 				field.Error:     infoErr,
 			})
 		}
 		mderr := engine.metadataManager.Create(config, hostConfig, task, container.Name, info.SecurityOptions)
 		if mderr != nil {
-			logger.Warn("Unable to create metadata for container", logger.Fields{
-				field.TaskID:    task.GetID(),
+			logger.WarnCtx(engine.taskLogContext(task), "Unable to create metadata for container", logger.Fields{
 				field.Container: container.Name,
 				field.Error:     mderr,
 			})
@@ -2126,8 +2325,7 @@ func (engine *DockerTaskEngine) createContainer(task *apitask.Task, container *a
 		engine.saveDockerContainerData(dockerContainer)
 	}
 	container.SetLabels(config.Labels)
-	logger.Info("Created docker container for task", logger.Fields{
-		field.TaskID:    task.GetID(),
+	logger.InfoCtx(engine.taskLogContext(task), "Created docker container for task", logger.Fields{
 		field.Container: container.Name,
 		field.DockerId:  metadata.DockerID,
 		field.Elapsed:   time.Since(createContainerBegin),
@@ -2174,8 +2372,7 @@ func getFirelensLogConfig(task *apitask.Task,
 	// we need to continue using the older fluentd-async-connect option.
 	isAsyncCompatible, err := utils.Version(dockerServerVersion).Matches(">=20.10.0")
 	if err != nil {
-		logger.Error("Unable to determine whether the Docker server version is at least 20.10.0", logger.Fields{
-			field.TaskID:    task.GetID(),
+		logger.ErrorCtx(task.LogContext(context.Background()), "Unable to determine whether the Docker server version is at least 20.10.0", logger.Fields{
 			field.Container: container.Name,
 			field.Error:     err,
 		})
@@ -2188,8 +2385,7 @@ func getFirelensLogConfig(task *apitask.Task,
 		firelensConfig.Config[logDriverAsyncConnect] = strconv.FormatBool(cfg.FirelensAsyncEnabled.Enabled())
 	}
 
-	logger.Debug("Applying firelens log config for container", logger.Fields{
-		field.TaskID:    task.GetID(),
+	logger.DebugCtx(task.LogContext(context.Background()), "Applying firelens log config for container", logger.Fields{
 		field.Container: container.Name,
 		"config":        firelensConfig,
 	})
@@ -2197,16 +2393,14 @@ func getFirelensLogConfig(task *apitask.Task,
 }
 
 func (engine *DockerTaskEngine) startContainer(task *apitask.Task, container *apicontainer.Container) dockerapi.DockerContainerMetadata {
-	logger.Info("Starting container", logger.Fields{
-		field.TaskID:    task.GetID(),
+	logger.InfoCtx(engine.taskLogContext(task), "Starting container", logger.Fields{
 		field.Container: container.Name,
 		field.RuntimeID: container.GetRuntimeID(),
 	})
 	client := engine.client
 	if container.DockerConfig.Version != nil {
 		minVersion := dockerclient.GetSupportedDockerAPIVersion(dockerclient.DockerVersion(*container.DockerConfig.Version))
-		logger.Debug("StartContainer: overriding docker API version in task payload", logger.Fields{
-			field.TaskID:              task.GetID(),
+		logger.DebugCtx(engine.taskLogContext(task), "StartContainer: overriding docker API version in task payload", logger.Fields{
 			field.Container:           container.Name,
 			"usingDockerAPIVersion":   minVersion,
 			"payloadDockerAPIVersion": *container.DockerConfig.Version,
@@ -2230,8 +2424,7 @@ func (engine *DockerTaskEngine) startContainer(task *apitask.Task, container *ap
 		return dockerContainerMD
 	}
 
-	logger.Info("Started container", logger.Fields{
-		field.TaskID:    task.GetID(),
+	logger.InfoCtx(engine.taskLogContext(task), "Started container", logger.Fields{
 		field.Container: container.Name,
 		field.RuntimeID: container.GetRuntimeID(),
 		field.Elapsed:   time.Since(startContainerBegin),
@@ -2245,16 +2438,14 @@ func (engine *DockerTaskEngine) startContainer(task *apitask.Task, container *ap
 		go func() {
 			err := engine.metadataManager.Update(engine.ctx, dockerID, task, container.Name)
 			if err != nil {
-				logger.Warn("Failed to update metadata file for container", logger.Fields{
-					field.TaskID:    task.GetID(),
+				logger.WarnCtx(engine.taskLogContext(task), "Failed to update metadata file for container", logger.Fields{
 					field.Container: container.Name,
 					field.Error:     err,
 				})
 				return
 			}
 			container.SetMetadataFileUpdated()
-			logger.Debug("Updated metadata file for container", logger.Fields{
-				field.TaskID:    task.GetID(),
+			logger.DebugCtx(engine.taskLogContext(task), "Updated metadata file for container", logger.Fields{
 				field.Container: container.Name,
 			})
 		}()
@@ -2273,8 +2464,7 @@ func (engine *DockerTaskEngine) startContainer(task *apitask.Task, container *ap
 		if task.IsServiceConnectEnabled() {
 			targetContainer, err := task.GetBridgeModePauseContainerForTaskContainer(container)
 			if err != nil {
-				logger.Error("Failed to start Firelens container", logger.Fields{
-					field.TaskID:    task.GetID(),
+				logger.ErrorCtx(engine.taskLogContext(task), "Failed to start Firelens container", logger.Fields{
 					field.Container: container.Name,
 					field.Error:     err,
 				})
@@ -2316,8 +2506,7 @@ func (engine *DockerTaskEngine) startContainer(task *apitask.Task, container *ap
 				return errors.New("Bridge IP not available to use for firelens")
 			})
 			if err != nil {
-				logger.Error("Failed to start Firelens container", logger.Fields{
-					field.TaskID:    task.GetID(),
+				logger.ErrorCtx(engine.taskLogContext(task), "Failed to start Firelens container", logger.Fields{
 					field.Container: container.Name,
 					field.Error:     err,
 				})
@@ -2332,8 +2521,7 @@ func (engine *DockerTaskEngine) startContainer(task *apitask.Task, container *ap
 			reason := "ExecuteCommandAgent started"
 			if err := engine.execCmdMgr.StartAgent(engine.ctx, engine.client, task, container, dockerID); err != nil {
 				reason = err.Error()
-				logger.Error("Failed to start ExecCommandAgent Process for container", logger.Fields{
-					field.TaskID:    task.GetID(),
+				logger.ErrorCtx(engine.taskLogContext(task), "Failed to start ExecCommandAgent Process for container", logger.Fields{
 					field.Container: container.Name,
 					field.Error:     err,
 				})
@@ -2347,8 +2535,7 @@ func (engine *DockerTaskEngine) startContainer(task *apitask.Task, container *ap
 			if ok {
 				mTask.emitManagedAgentEvent(mTask.Task, container, execcmd.ExecuteCommandAgentName, reason)
 			} else {
-				logger.Error("Failed to update status of ExecCommandAgent Process for container", logger.Fields{
-					field.TaskID:    task.GetID(),
+				logger.ErrorCtx(engine.taskLogContext(task), "Failed to update status of ExecCommandAgent Process for container", logger.Fields{
 					field.Container: container.Name,
 					field.Error:     "managed task not found",
 				})
@@ -2385,8 +2572,7 @@ func (engine *DockerTaskEngine) startContainer(task *apitask.Task, container *ap
 }
 
 func (engine *DockerTaskEngine) provisionContainerResources(task *apitask.Task, container *apicontainer.Container) dockerapi.DockerContainerMetadata {
-	logger.Info("Setting up container resources for container", logger.Fields{
-		field.TaskID:    task.GetID(),
+	logger.InfoCtx(engine.taskLogContext(task), "Setting up container resources for container", logger.Fields{
 		field.Container: container.Name,
 	})
 	if task.IsNetworkModeAWSVPC() {
@@ -2420,8 +2606,7 @@ func (engine *DockerTaskEngine) provisionContainerResourcesAwsvpc(task *apitask.
 		}
 	}
 
-	logger.Info("Setting up CNI config for task", logger.Fields{
-		field.TaskID:        task.GetID(),
+	logger.InfoCtx(engine.taskLogContext(task), "Setting up CNI config for task", logger.Fields{
 		"cniContainerID":    cniConfig.ContainerID,
 		"cniPluginPath":     cniConfig.PluginsPath,
 		"cniID":             cniConfig.ID,
@@ -2430,11 +2615,10 @@ func (engine *DockerTaskEngine) provisionContainerResourcesAwsvpc(task *apitask.
 	})
 
 	// Invoke the libcni to config the network namespace for the container
-	result, err := engine.cniClient.SetupNS(engine.ctx, cniConfig, cniSetupTimeout)
+	result, err := engine.cniClient.SetupNS(tracing.TaskContext(engine.ctx, task.Arn), cniConfig, cniSetupTimeout)
 	if err != nil {
-		logger.Error("Unable to configure pause container namespace", logger.Fields{
-			field.TaskID: task.GetID(),
-			field.Error:  err,
+		logger.ErrorCtx(engine.taskLogContext(task), "Unable to configure pause container namespace", logger.Fields{
+			field.Error: err,
 		})
 		return dockerapi.DockerContainerMetadata{
 			DockerID: cniConfig.ContainerID,
@@ -2444,9 +2628,7 @@ func (engine *DockerTaskEngine) provisionContainerResourcesAwsvpc(task *apitask.
 	}
 
 	if result == nil {
-		logger.Error("Expect non-empty result from network namespace setup", logger.Fields{
-			field.TaskID: task.GetID(),
-		})
+		logger.ErrorCtx(engine.taskLogContext(task), "Expect non-empty result from network namespace setup")
 		return dockerapi.DockerContainerMetadata{
 			DockerID: cniConfig.ContainerID,
 			Error: ContainerNetworkingError{fmt.Errorf(
@@ -2456,9 +2638,8 @@ func (engine *DockerTaskEngine) provisionContainerResourcesAwsvpc(task *apitask.
 
 	// This is the IP of the task assigned on the bridge for IAM Task roles
 	taskIP := result.IPs[0].Address.IP.String()
-	logger.Info("Task associated with ip address", logger.Fields{
-		field.TaskID: task.GetID(),
-		"ip":         taskIP,
+	logger.InfoCtx(engine.taskLogContext(task), "Task associated with ip address", logger.Fields{
+		"ip": taskIP,
 	})
 	task.SetNetworkNamespace(cniConfig.ContainerNetNS)
 	// Note: By default, the interface name is set to eth0 within the CNI configs. We can also always assume that the first entry of the CNI network config to be
@@ -2473,9 +2654,8 @@ func (engine *DockerTaskEngine) provisionContainerResourcesAwsvpc(task *apitask.
 	// Invoke additional commands required to configure the task namespace routing.
 	err = engine.namespaceHelper.ConfigureTaskNamespaceRouting(engine.ctx, task.GetPrimaryENI(), cniConfig, result)
 	if err != nil {
-		logger.Error("Unable to configure pause container namespace", logger.Fields{
-			field.TaskID: task.GetID(),
-			field.Error:  err,
+		logger.ErrorCtx(engine.taskLogContext(task), "Unable to configure pause container namespace", logger.Fields{
+			field.Error: err,
 		})
 		return dockerapi.DockerContainerMetadata{
 			DockerID: cniConfig.ContainerID,
@@ -2515,11 +2695,10 @@ func (engine *DockerTaskEngine) provisionContainerResourcesBridgeMode(task *apit
 	}
 
 	// Invoke the libcni to config the network namespace for the container
-	_, err = engine.cniClient.SetupNS(engine.ctx, cniConfig, cniSetupTimeout)
+	_, err = engine.cniClient.SetupNS(tracing.TaskContext(engine.ctx, task.Arn), cniConfig, cniSetupTimeout)
 
 	if err != nil {
-		logger.Error("Unable to configure pause container namespace", logger.Fields{
-			field.TaskID:    task.GetID(),
+		logger.ErrorCtx(engine.taskLogContext(task), "Unable to configure pause container namespace", logger.Fields{
 			field.Container: container.Name,
 			field.Error:     err,
 		})
@@ -2529,13 +2708,26 @@ func (engine *DockerTaskEngine) provisionContainerResourcesBridgeMode(task *apit
 		}
 	}
 
-	logger.Info("Successfully configured pause netns", logger.Fields{
-		field.TaskID:    task.GetID(),
+	logger.InfoCtx(engine.taskLogContext(task), "Successfully configured pause netns", logger.Fields{
 		field.Container: container.Name,
 	})
 	return dockerapi.MetadataFromContainer(containerInspectOutput)
 }
 
+// cleanupTaskFaults cleans up the faults injected into a task that has stopped.
+func (engine *DockerTaskEngine) cleanupTaskFaults(task *apitask.Task) {
+	if !task.IsFaultInjectionEnabled() {
+		return
+	}
+	engine.faultCleanerLock.RLock()
+	cleaner := engine.faultCleaner
+	engine.faultCleanerLock.RUnlock()
+	if cleaner == nil {
+		return
+	}
+	cleaner(task.Arn)
+}
+
 // checkTearDownPauseContainer idempotently tears down the pause container network when the pause container's known
 // or desired status is stopped.
 func (engine *DockerTaskEngine) checkTearDownPauseContainer(task *apitask.Task) {
@@ -2549,9 +2741,8 @@ func (engine *DockerTaskEngine) checkTearDownPauseContainer(task *apitask.Task)
 			if container.KnownTerminal() || container.DesiredTerminal() {
 				err := engine.cleanupPauseContainerNetwork(task, container)
 				if err != nil {
-					logger.Error("Unable to cleanup pause container network namespace", logger.Fields{
-						field.TaskID: task.GetID(),
-						field.Error:  err,
+					logger.ErrorCtx(engine.taskLogContext(task), "Unable to cleanup pause container network namespace", logger.Fields{
+						field.Error: err,
 					})
 				}
 			}
@@ -2568,8 +2759,7 @@ func (engine *DockerTaskEngine) cleanupPauseContainerNetwork(task *apitask.Task,
 	}
 	delay := time.Duration(engine.cfg.ENIPauseContainerCleanupDelaySeconds) * time.Second
 	if engine.handleDelay != nil && delay > 0 {
-		logger.Info("Waiting before cleaning up pause container", logger.Fields{
-			field.TaskID:    task.GetID(),
+		logger.InfoCtx(engine.taskLogContext(task), "Waiting before cleaning up pause container", logger.Fields{
 			field.Container: container.Name,
 			"wait":          delay.String(),
 		})
@@ -2580,8 +2770,7 @@ func (engine *DockerTaskEngine) cleanupPauseContainerNetwork(task *apitask.Task,
 		return errors.Wrap(err, "engine: cannot cleanup task network namespace due to error inspecting pause container")
 	}
 
-	logger.Info("Cleaning up the network namespace", logger.Fields{
-		field.TaskID:    task.GetID(),
+	logger.InfoCtx(engine.taskLogContext(task), "Cleaning up the network namespace", logger.Fields{
 		field.Container: container.Name,
 	})
 
@@ -2599,14 +2788,13 @@ func (engine *DockerTaskEngine) cleanupPauseContainerNetwork(task *apitask.Task,
 			"engine: failed cleanup task network namespace, task: %s", task.String())
 	}
 
-	err = engine.cniClient.CleanupNS(engine.ctx, cniConfig, cniCleanupTimeout)
+	err = engine.cniClient.CleanupNS(tracing.TaskContext(engine.ctx, task.Arn), cniConfig, cniCleanupTimeout)
 	if err != nil {
 		return err
 	}
 
 	container.SetContainerTornDown(true)
-	logger.Info("Cleaned pause container network namespace", logger.Fields{
-		field.TaskID:    task.GetID(),
+	logger.InfoCtx(engine.taskLogContext(task), "Cleaned pause container network namespace", logger.Fields{
 		field.Container: container.Name,
 	})
 	return nil
@@ -2688,20 +2876,16 @@ func (engine *DockerTaskEngine) stopContainer(task *apitask.Task, container *api
 		adminSocketPath := serviceConnectConfig.AdminSocketPath
 		drainRequest := serviceConnectConfig.DrainRequest
 		if err := engine.appnetClient.DrainInboundConnections(adminSocketPath, drainRequest); err != nil {
-			logger.Error("Error sending drain signal to Appnet Agent", logger.Fields{
-				field.TaskID: task.GetID(),
-				field.Error:  err,
+			logger.ErrorCtx(engine.taskLogContext(task), "Error sending drain signal to Appnet Agent", logger.Fields{
+				field.Error: err,
 			})
 		} else {
 			task.SetServiceConnectConnectionDraining(true)
-			logger.Debug("Successfully sent drain signal to Appnet Agent", logger.Fields{
-				field.TaskID: task.GetID(),
-			})
+			logger.DebugCtx(engine.taskLogContext(task), "Successfully sent drain signal to Appnet Agent")
 		}
 	}
 
-	logger.Info("Stopping container", logger.Fields{
-		field.TaskID:    task.GetID(),
+	logger.InfoCtx(engine.taskLogContext(task), "Stopping container", logger.Fields{
 		field.Container: container.Name,
 	})
 	dockerID, err := engine.getDockerID(task, container)
@@ -2718,8 +2902,7 @@ func (engine *DockerTaskEngine) stopContainer(task *apitask.Task, container *api
 		if task.IsNetworkModeAWSVPC() || (task.IsNetworkModeBridge() && task.IsServiceConnectEnabled()) {
 			err := engine.cleanupPauseContainerNetwork(task, container)
 			if err != nil {
-				logger.Error("Unable to cleanup pause container network namespace", logger.Fields{
-					field.TaskID:    task.GetID(),
+				logger.ErrorCtx(engine.taskLogContext(task), "Unable to cleanup pause container network namespace", logger.Fields{
 					field.Container: container.Name,
 					field.Error:     err,
 				})
@@ -2767,9 +2950,7 @@ func (engine *DockerTaskEngine) stopDockerContainer(dockerID, containerName stri
 }
 
 func (engine *DockerTaskEngine) removeContainer(task *apitask.Task, container *apicontainer.Container) error {
-	logger.Info("Removing container", container.Fields(), logger.Fields{
-		field.TaskID: task.GetID(),
-	})
+	logger.InfoCtx(engine.taskLogContext(task), "Removing container", container.Fields())
 	dockerID, err := engine.getDockerID(task, container)
 	if err != nil {
 		return err
@@ -2794,15 +2975,13 @@ func (engine *DockerTaskEngine) updateTaskDesiredStatusUnsafe(task *apitask.Task
 	// also read in the order AddTask was called
 	// This does block the engine's ability to ingest any new events (including
 	// stops for past tasks, ack!), but this is necessary for correctness
-	logger.Debug("Putting update on the acs channel", logger.Fields{
-		field.TaskID:        task.GetID(),
+	logger.DebugCtx(engine.taskLogContext(task), "Putting update on the acs channel", logger.Fields{
 		field.DesiredStatus: updateDesiredStatus.String(),
 	})
 	managedTask.emitACSTransition(acsTransition{
 		desiredStatus: updateDesiredStatus,
 	})
-	logger.Debug("Update taken off the acs channel", logger.Fields{
-		field.TaskID:        task.GetID(),
+	logger.DebugCtx(engine.taskLogContext(task), "Update taken off the acs channel", logger.Fields{
 		field.DesiredStatus: updateDesiredStatus.String(),
 	})
 }
@@ -2834,25 +3013,27 @@ func (engine *DockerTaskEngine) transitionContainer(task *apitask.Task, containe
 func (engine *DockerTaskEngine) applyContainerState(task *apitask.Task, container *apicontainer.Container, nextState apicontainerstatus.ContainerStatus) dockerapi.DockerContainerMetadata {
 	transitionFunction, ok := engine.transitionFunctionMap()[nextState]
 	if !ok {
-		logger.Critical("Unsupported desired state transition for container", logger.Fields{
-			field.TaskID:    task.GetID(),
+		logger.CriticalCtx(engine.taskLogContext(task), "Unsupported desired state transition for container", logger.Fields{
 			field.Container: container.Name,
 			"nextState":     nextState.String(),
 		})
 		return dockerapi.DockerContainerMetadata{Error: &impossibleTransitionError{nextState}}
 	}
+	span := startContainerTransitionSpan(task, container, nextState)
 	metadata := transitionFunction(task, container)
+	if metadata.DockerID != "" {
+		span.SetAttributes(tracing.RuntimeIDKey.String(metadata.DockerID))
+	}
+	tracing.EndSpan(span, metadata.Error)
 	if metadata.Error != nil {
-		logger.Error("Error transitioning container", logger.Fields{
-			field.TaskID:    task.GetID(),
+		logger.ErrorCtx(engine.taskLogContext(task), "Error transitioning container", logger.Fields{
 			field.Container: container.Name,
 			field.RuntimeID: container.GetRuntimeID(),
 			"nextState":     nextState.String(),
 			field.Error:     metadata.Error,
 		})
 	} else {
-		logger.Debug("Transitioned container", logger.Fields{
-			field.TaskID:    task.GetID(),
+		logger.DebugCtx(engine.taskLogContext(task), "Transitioned container", logger.Fields{
 			field.Container: container.Name,
 			field.RuntimeID: container.GetRuntimeID(),
 			"nextState":     nextState.String(),
@@ -2878,6 +3059,19 @@ func (engine *DockerTaskEngine) State() dockerstate.TaskEngineState {
 	return engine.state
 }
 
+// TaskEvents returns the history of the transitions, errors and blocking conditions of the task
+// managed by the engine, from the oldest to the most recent event. It returns false if the engine
+// isn't managing the task.
+func (engine *DockerTaskEngine) TaskEvents(taskArn string) ([]TaskEvent, bool) {
+	engine.tasksLock.RLock()
+	managedTask, ok := engine.managedTasks[taskArn]
+	engine.tasksLock.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return managedTask.events().get(), true
+}
+
 // Version returns the underlying docker version.
 func (engine *DockerTaskEngine) Version() (string, error) {
 	return engine.client.Version(engine.ctx, dockerclient.VersionTimeout)
@@ -2886,15 +3080,13 @@ func (engine *DockerTaskEngine) Version() (string, error) {
 func (engine *DockerTaskEngine) updateMetadataFile(task *apitask.Task, cont *apicontainer.DockerContainer) {
 	err := engine.metadataManager.Update(engine.ctx, cont.DockerID, task, cont.Container.Name)
 	if err != nil {
-		logger.Error("Failed to update metadata file for container", logger.Fields{
-			field.TaskID:    task.GetID(),
+		logger.ErrorCtx(engine.taskLogContext(task), "Failed to update metadata file for container", logger.Fields{
 			field.Container: cont.Container.Name,
 			field.Error:     err,
 		})
 	} else {
 		cont.Container.SetMetadataFileUpdated()
-		logger.Debug("Updated metadata file for container", logger.Fields{
-			field.TaskID:    task.GetID(),
+		logger.DebugCtx(engine.taskLogContext(task), "Updated metadata file for container", logger.Fields{
 			field.Container: cont.Container.Name,
 		})
 	}
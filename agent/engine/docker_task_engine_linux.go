@@ -27,6 +27,7 @@ import (
 	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
 	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
 	"github.com/aws/amazon-ecs-agent/agent/utils"
+	apierrors "github.com/aws/amazon-ecs-agent/ecs-agent/api/errors"
 	apitaskstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/task/status"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
@@ -154,3 +155,36 @@ func (engine *DockerTaskEngine) updateCredentialSpecMapping(taskID string, conta
 		}
 	}
 }
+
+// injectCDIDevices injects the device nodes, mounts, environment variables and additional groups of the CDI devices
+// requested by the container
+func (engine *DockerTaskEngine) injectCDIDevices(task *apitask.Task, container *apicontainer.Container,
+	hostConfig *dockercontainer.HostConfig) *apierrors.HostConfigError {
+	devices := container.GetCDIDevices()
+	if engine.resourceFields == nil || engine.resourceFields.CDIDeviceManager == nil {
+		return &apierrors.HostConfigError{
+			Msg: fmt.Sprintf("unable to inject CDI devices %v: CDI device manager is not available", devices),
+		}
+	}
+	edits, err := engine.resourceFields.CDIDeviceManager.GetContainerEdits(devices)
+	if err != nil {
+		return &apierrors.HostConfigError{Msg: fmt.Sprintf("unable to inject CDI devices: %v", err)}
+	}
+	edits.ApplyToHostConfig(hostConfig)
+	container.MergeEnvironmentVariables(edits.EnvironmentVariables())
+	logger.Info("Injecting CDI devices into container", logger.Fields{
+		field.TaskID:    task.GetID(),
+		field.Container: container.Name,
+		"devices":       devices,
+	})
+	return nil
+}
+
+// getUnsupportedCDIDevices returns the reasons why the CDI devices of the host which can't be injected through docker
+// are unsupported, by fully qualified name
+func (engine *DockerTaskEngine) getUnsupportedCDIDevices() map[string]string {
+	if engine.resourceFields == nil || engine.resourceFields.CDIDeviceManager == nil {
+		return nil
+	}
+	return engine.resourceFields.CDIDeviceManager.GetUnsupportedDevices()
+}
@@ -31,6 +31,8 @@ import (
 	"github.com/aws/amazon-ecs-agent/agent/api/serviceconnect"
 	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
 	mock_asm_factory "github.com/aws/amazon-ecs-agent/agent/asm/factory/mocks"
+	"github.com/aws/amazon-ecs-agent/agent/cdi"
+	mock_cdi "github.com/aws/amazon-ecs-agent/agent/cdi/mocks"
 	"github.com/aws/amazon-ecs-agent/agent/config"
 	"github.com/aws/amazon-ecs-agent/agent/data"
 	"github.com/aws/amazon-ecs-agent/agent/dockerclient"
@@ -50,6 +52,7 @@ import (
 	"github.com/aws/amazon-ecs-agent/agent/taskresource/firelens"
 	"github.com/aws/amazon-ecs-agent/agent/taskresource/ssmsecret"
 	resourcestatus "github.com/aws/amazon-ecs-agent/agent/taskresource/status"
+	"github.com/aws/amazon-ecs-agent/agent/utils"
 	mock_ioutilwrapper "github.com/aws/amazon-ecs-agent/agent/utils/ioutilwrapper/mocks"
 	mock_appnet "github.com/aws/amazon-ecs-agent/ecs-agent/api/appnet/mocks"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/api/attachment"
@@ -61,6 +64,7 @@ import (
 	ni "github.com/aws/amazon-ecs-agent/ecs-agent/netlib/model/networkinterface"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
 	cniTypesCurrent "github.com/containernetworking/cni/pkg/types/100"
 	"github.com/docker/docker/api/types"
 	dockercontainer "github.com/docker/docker/api/types/container"
@@ -1624,3 +1628,114 @@ func TestCredentialSpecResourceTaskFile(t *testing.T) {
 	ret := taskEngine.(*DockerTaskEngine).createContainer(testTask, testTask.Containers[0])
 	assert.Nil(t, ret.Error)
 }
+
+func TestInjectCDIDevices(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cdiDeviceManager := mock_cdi.NewMockDeviceManager(ctrl)
+	engine := &DockerTaskEngine{
+		resourceFields: &taskresource.ResourceFields{
+			CDIDeviceManager: cdiDeviceManager,
+		},
+	}
+	task := &apitask.Task{Arn: testTaskARN}
+	container := &apicontainer.Container{
+		Name: "fpga",
+		Environment: map[string]string{
+			apicontainer.CDIDevicesEnvVar: "vendor.com/fpga=0",
+		},
+	}
+	container.InitCDIDeviceRequests()
+
+	cdiDeviceManager.EXPECT().GetContainerEdits([]string{"vendor.com/fpga=0"}).Return(&cdi.ContainerEdits{
+		Env:         []string{"FPGA_VISIBLE=0"},
+		DeviceNodes: []cdi.DeviceNode{{Path: "/dev/fpga0"}},
+	}, nil)
+	hostConfig := &dockercontainer.HostConfig{}
+	require.Nil(t, engine.injectCDIDevices(task, container, hostConfig))
+	assert.Equal(t, []dockercontainer.DeviceMapping{
+		{PathOnHost: "/dev/fpga0", PathInContainer: "/dev/fpga0", CgroupPermissions: "rwm"},
+	}, hostConfig.Devices)
+	assert.Equal(t, "0", container.Environment["FPGA_VISIBLE"])
+
+	cdiDeviceManager.EXPECT().GetContainerEdits(gomock.Any()).Return(nil, errors.New("CDI device not found"))
+	assert.NotNil(t, engine.injectCDIDevices(task, container, &dockercontainer.HostConfig{}))
+}
+
+func TestAllocateCDIDevices(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cdiDeviceManager := mock_cdi.NewMockDeviceManager(ctrl)
+	cdiDeviceManager.EXPECT().GetUnsupportedDevices().Return(map[string]string{
+		"vendor.com/nic=nic1": "hooks are not supported",
+	}).AnyTimes()
+	hostResourceManager := getTestHostResourceManager(int32(2048), int32(2048), []string{}, []string{}, []string{})
+	hostResourceManager.initialHostResource[CDI] = ecstypes.Resource{
+		Name:           utils.Strptr(CDI),
+		Type:           utils.Strptr("STRINGSET"),
+		StringSetValue: []string{"vendor.com/fpga=0", "vendor.com/fpga=1", "vendor.com/fpga=2", "vendor.com/nic=nic0"},
+	}
+	engine := &DockerTaskEngine{
+		resourceFields: &taskresource.ResourceFields{
+			CDIDeviceManager: cdiDeviceManager,
+		},
+		hostResourceManager: hostResourceManager,
+	}
+	newTaskWithCDISupport := func(cdiSupportEnabled bool, requests ...string) *apitask.Task {
+		task := &apitask.Task{Arn: testTaskARN}
+		for i, request := range requests {
+			container := &apicontainer.Container{
+				Name:        fmt.Sprintf("c%d", i),
+				Environment: map[string]string{apicontainer.CDIDevicesEnvVar: request},
+			}
+			// The requests are only parsed when CDI support is enabled
+			if cdiSupportEnabled {
+				container.InitCDIDeviceRequests()
+			}
+			task.Containers = append(task.Containers, container)
+		}
+		return task
+	}
+	newTask := func(requests ...string) *apitask.Task {
+		return newTaskWithCDISupport(true, requests...)
+	}
+
+	// The devices requested by name aren't allocated to the kind requests
+	task := newTask("vendor.com/fpga=0", "vendor.com/fpga:2")
+	allocated, err := engine.allocateCDIDevices(task)
+	require.NoError(t, err)
+	assert.True(t, allocated)
+	assert.Empty(t, task.Containers[0].GetAllocatedCDIDevices())
+	assert.Equal(t, []string{"vendor.com/fpga=1", "vendor.com/fpga=2"}, task.Containers[1].GetAllocatedCDIDevices())
+
+	// The task waits for the devices consumed by other tasks to be released
+	consumed, err := hostResourceManager.consume("arn:task1", map[string]ecstypes.Resource{
+		CDI: {
+			Name:           utils.Strptr(CDI),
+			Type:           utils.Strptr("STRINGSET"),
+			StringSetValue: []string{"vendor.com/fpga=1"},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, consumed)
+	task = newTask("vendor.com/fpga:3")
+	allocated, err = engine.allocateCDIDevices(task)
+	require.NoError(t, err)
+	assert.False(t, allocated)
+	assert.Empty(t, task.Containers[0].GetAllocatedCDIDevices())
+
+	// The task fails when the host doesn't have enough devices, or the devices can't be injected
+	_, err = engine.allocateCDIDevices(newTask("vendor.com/nic:2"))
+	assert.ErrorContains(t, err, "vendor.com/nic=nic1 can't be injected through docker")
+	_, err = engine.allocateCDIDevices(newTask("vendor.com/nic=nic1"))
+	assert.ErrorContains(t, err, "hooks are not supported")
+	_, err = engine.allocateCDIDevices(newTask("vendor.com/fpga:two"))
+	assert.Error(t, err)
+
+	// Nothing is allocated when CDI support is disabled
+	task = newTaskWithCDISupport(false, "vendor.com/fpga:3")
+	allocated, err = engine.allocateCDIDevices(task)
+	require.NoError(t, err)
+	assert.True(t, allocated)
+	assert.Empty(t, task.Containers[0].GetAllocatedCDIDevices())
+}
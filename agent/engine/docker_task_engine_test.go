@@ -46,6 +46,8 @@ import (
 	"github.com/aws/amazon-ecs-agent/agent/engine/execcmd"
 	mock_execcmdagent "github.com/aws/amazon-ecs-agent/agent/engine/execcmd/mocks"
 	"github.com/aws/amazon-ecs-agent/agent/engine/image"
+	"github.com/aws/amazon-ecs-agent/agent/engine/imagesignature"
+	mock_imagesignature "github.com/aws/amazon-ecs-agent/agent/engine/imagesignature/mock"
 	mock_engine "github.com/aws/amazon-ecs-agent/agent/engine/mocks"
 	mock_engineserviceconnect "github.com/aws/amazon-ecs-agent/agent/engine/serviceconnect/mock"
 	"github.com/aws/amazon-ecs-agent/agent/engine/testdata"
@@ -663,6 +665,55 @@ func TestCreateContainerSaveDockerIDAndName(t *testing.T) {
 	assert.Contains(t, containers[0].DockerName, sleepContainer.Name)
 }
 
+func TestCreateContainerImageSignatureVerification(t *testing.T) {
+	testDigest := "sha256:c3839dd800b9eb7603340509769c43e146a74c63dca3045a8e7dc8ee07e53966"
+	verificationErr := imagesignature.ImageSignatureVerificationError{FromError: errors.New("no matching signatures")}
+	testCases := []struct {
+		name      string
+		verifyErr apierrors.NamedError
+	}{
+		{
+			name: "container is created from trusted image",
+		},
+		{
+			name:      "container fails with untrusted image",
+			verifyErr: verificationErr,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+			ctrl, client, _, privateTaskEngine, _, _, _, _ := mocks(t, ctx, &defaultConfig)
+			defer ctrl.Finish()
+
+			taskEngine, _ := privateTaskEngine.(*DockerTaskEngine)
+			verifier := mock_imagesignature.NewMockVerifier(ctrl)
+			taskEngine.imageVerifier = verifier
+
+			sleepTask := testdata.LoadTask("sleep5")
+			sleepContainer, _ := sleepTask.ContainerByName("sleep5")
+			sleepContainer.SetImageDigest(testDigest)
+
+			verifier.EXPECT().Verify(gomock.Any(), sleepContainer.Image, testDigest).Return(tc.verifyErr)
+			if tc.verifyErr == nil {
+				client.EXPECT().APIVersion().Return(defaultDockerClientAPIVersion, nil).AnyTimes()
+				client.EXPECT().CreateContainer(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(dockerapi.DockerContainerMetadata{DockerID: testDockerID})
+			}
+
+			metadata := taskEngine.createContainer(sleepTask, sleepContainer)
+			if tc.verifyErr == nil {
+				assert.NoError(t, metadata.Error)
+			} else {
+				assert.Equal(t, tc.verifyErr, metadata.Error)
+				assert.Equal(t, "ImageSignatureVerificationError", metadata.Error.ErrorName())
+			}
+		})
+	}
+}
+
 func TestCreateContainerMetadata(t *testing.T) {
 	testcases := []struct {
 		name  string
@@ -1353,6 +1404,31 @@ func TestCheckTearDownPauseContainerAwsvpc(t *testing.T) {
 	taskEngine.(*DockerTaskEngine).checkTearDownPauseContainer(testTask)
 }
 
+// TestCleanupTaskFaults tests that the faults of a stopped task are cleaned up only when fault injection
+// is enabled for the task and a fault cleaner is set
+func TestCleanupTaskFaults(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	ctrl, _, _, taskEngine, _, _, _, _ := mocks(t, ctx, &defaultConfig)
+	defer ctrl.Finish()
+
+	testTask := testdata.LoadTask("sleep5")
+	dockerTaskEngine := taskEngine.(*DockerTaskEngine)
+	// No fault cleaner is set
+	dockerTaskEngine.cleanupTaskFaults(testTask)
+
+	var cleanedUpTasks []string
+	dockerTaskEngine.SetTaskFaultCleaner(func(taskARN string) {
+		cleanedUpTasks = append(cleanedUpTasks, taskARN)
+	})
+	dockerTaskEngine.cleanupTaskFaults(testTask)
+	assert.Empty(t, cleanedUpTasks)
+
+	testTask.EnableFaultInjection = true
+	dockerTaskEngine.cleanupTaskFaults(testTask)
+	assert.Equal(t, []string{testTask.Arn}, cleanedUpTasks)
+}
+
 // TestTaskWithCircularDependency tests the task with containers of which the
 // dependencies can't be resolved
 func TestTaskWithCircularDependency(t *testing.T) {
@@ -1785,6 +1861,180 @@ func TestPullAndUpdateContainerReference(t *testing.T) {
 	}
 }
 
+func TestPullAndUpdateContainerReferenceWithRegistryMirror(t *testing.T) {
+	testDigest := "sha256:c3839dd800b9eb7603340509769c43e146a74c63dca3045a8e7dc8ee07e53966"
+	mirrorRef := "mirror.internal/dockerhub/library/nginx:1.27"
+	testcases := []struct {
+		name             string
+		image            string
+		mirrorPullErr    apierrors.NamedError
+		mirrorRefExists  bool
+		mirrorRefTracked bool
+		expectMirror     bool
+		expectUntag      bool
+	}{
+		{
+			name:         "image is pulled from the mirror",
+			image:        "nginx:1.27",
+			expectMirror: true,
+			expectUntag:  true,
+		},
+		{
+			name:            "mirror reference which existed before the pull is kept",
+			image:           "nginx:1.27",
+			mirrorRefExists: true,
+			expectMirror:    true,
+		},
+		{
+			name:             "mirror reference tracked as the image of another container is kept",
+			image:            "nginx:1.27",
+			mirrorRefTracked: true,
+			expectMirror:     true,
+		},
+		{
+			name:          "image is pulled from its registry when the mirror fails",
+			image:         "nginx:1.27",
+			mirrorPullErr: dockerapi.CannotPullContainerError{FromError: errors.New("not found")},
+			expectMirror:  true,
+		},
+		{
+			name:  "image referenced by digest is pulled from its registry",
+			image: "nginx@" + testDigest,
+		},
+		{
+			name:  "image without mirror is pulled from its registry",
+			image: "ghcr.io/org/app:latest",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+			cfg := &config.Config{
+				ImagePullBehavior: config.ImagePullDefaultBehavior,
+				RegistryMirrors:   map[string]string{"docker.io/*": "mirror.internal/dockerhub/*"},
+			}
+			ctrl, client, _, privateTaskEngine, _, imageManager, _, _ := mocks(t, ctx, cfg)
+			defer ctrl.Finish()
+
+			taskEngine, _ := privateTaskEngine.(*DockerTaskEngine)
+			taskEngine._time = nil
+			container := &apicontainer.Container{
+				Type:      apicontainer.ContainerNormal,
+				Image:     tc.image,
+				Essential: true,
+			}
+			task := &apitask.Task{
+				Arn:        "taskArn",
+				Containers: []*apicontainer.Container{container},
+			}
+
+			if tc.expectMirror {
+				if tc.mirrorRefExists {
+					client.EXPECT().InspectImage(mirrorRef).Return(&types.ImageInspect{}, nil)
+				} else {
+					client.EXPECT().InspectImage(mirrorRef).Return(nil, errors.New("no such image"))
+				}
+				// The mirror isn't hosted in ECR, it's authenticated with the engine auth data
+				client.EXPECT().PullImage(gomock.Any(), mirrorRef, nil, gomock.Any()).
+					Return(dockerapi.DockerContainerMetadata{Error: tc.mirrorPullErr})
+			}
+			if tc.expectMirror && tc.mirrorPullErr == nil {
+				// The image is tracked by its reference in the task
+				client.EXPECT().TagImage(gomock.Any(), mirrorRef, tc.image).Return(nil)
+			} else {
+				client.EXPECT().PullImage(gomock.Any(), tc.image, nil, gomock.Any()).
+					Return(dockerapi.DockerContainerMetadata{})
+			}
+			if tc.expectMirror && tc.mirrorPullErr == nil && !tc.mirrorRefExists {
+				imageManager.EXPECT().GetImageStateFromImageName(mirrorRef).Return(&image.ImageState{}, tc.mirrorRefTracked)
+			}
+			if tc.expectUntag {
+				client.EXPECT().RemoveImage(gomock.Any(), mirrorRef, dockerclient.RemoveImageTimeout).Return(nil)
+			}
+			imageManager.EXPECT().RecordContainerReference(container)
+			imageManager.EXPECT().GetImageStateFromImageName(tc.image).Return(nil, false)
+
+			metadata := taskEngine.pullAndUpdateContainerReference(task, container)
+			assert.NoError(t, metadata.Error)
+			pulledContainersMap, _ := taskEngine.State().PulledContainerMapByArn("taskArn")
+			assert.Len(t, pulledContainersMap, 1)
+		})
+	}
+}
+
+func TestPullImageFromECRRegistryMirror(t *testing.T) {
+	mirrorRef := "123456789012.dkr.ecr.us-west-2.amazonaws.com/dockerhub/library/nginx:1.27"
+	testcases := []struct {
+		name                   string
+		executionCredentialsID string
+		expectExecutionRole    bool
+	}{
+		{
+			name:                   "mirror is authenticated with the task execution role",
+			executionCredentialsID: credentialsID,
+			expectExecutionRole:    true,
+		},
+		{
+			name: "mirror is authenticated with the instance credentials",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+			cfg := &config.Config{
+				RegistryMirrors: map[string]string{
+					"docker.io/*": "123456789012.dkr.ecr.us-west-2.amazonaws.com/dockerhub/*",
+				},
+			}
+			ctrl, client, _, privateTaskEngine, credentialsManager, _, _, _ := mocks(t, ctx, cfg)
+			defer ctrl.Finish()
+
+			taskEngine, _ := privateTaskEngine.(*DockerTaskEngine)
+			container := &apicontainer.Container{
+				Type:  apicontainer.ContainerNormal,
+				Image: "nginx:1.27",
+			}
+			task := &apitask.Task{
+				Arn:                    "taskArn",
+				Containers:             []*apicontainer.Container{container},
+				ExecutionCredentialsID: tc.executionCredentialsID,
+			}
+			roleCredentials := credentials.IAMRoleCredentials{
+				CredentialsID: "credsid",
+				RoleArn:       "arn:aws:iam::123456789012:role/execution-role",
+			}
+			if tc.executionCredentialsID != "" {
+				credentialsManager.EXPECT().GetTaskCredentials(tc.executionCredentialsID).Return(
+					credentials.TaskIAMRoleCredentials{IAMRoleCredentials: roleCredentials}, true)
+			}
+
+			client.EXPECT().InspectImage(mirrorRef).Return(nil, errors.New("no such image"))
+			client.EXPECT().PullImage(gomock.Any(), mirrorRef, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ string, authData *apicontainer.RegistryAuthenticationData,
+					_ time.Duration) dockerapi.DockerContainerMetadata {
+					require.NotNil(t, authData)
+					assert.Equal(t, apicontainer.AuthTypeECR, authData.Type)
+					assert.Equal(t, "123456789012", authData.ECRAuthData.RegistryID)
+					assert.Equal(t, "us-west-2", authData.ECRAuthData.Region)
+					assert.Equal(t, tc.expectExecutionRole, authData.ECRAuthData.UseExecutionRole)
+					if tc.expectExecutionRole {
+						assert.Equal(t, roleCredentials, authData.ECRAuthData.GetPullCredentials())
+					}
+					return dockerapi.DockerContainerMetadata{}
+				})
+
+			metadata, pulledRef, createdMirrorRef := taskEngine.pullImage(task, container, container.Image)
+			assert.NoError(t, metadata.Error)
+			assert.Equal(t, mirrorRef, pulledRef)
+			assert.True(t, createdMirrorRef)
+		})
+	}
+}
+
 func TestPullAndUpdateContainerReferenceErrorMessages(t *testing.T) {
 	testcases := []struct {
 		Name         string
@@ -2883,10 +3133,14 @@ func TestTaskSecretsEnvironmentVariables(t *testing.T) {
 			ssmSecretRes := ssmsecret.NewSSMSecretResource(
 				testTask.Arn,
 				ssmRequirements,
+				nil,
 				credentialsID,
 				credentialsManager,
 				ssmClientCreator,
-				testIPCompatibility)
+				testIPCompatibility,
+				"",
+				0,
+				nil)
 
 			// required for validating asm workflows
 			asmClientCreator := mock_asm_factory.NewMockClientCreator(ctrl)
@@ -2899,9 +3153,13 @@ func TestTaskSecretsEnvironmentVariables(t *testing.T) {
 			asmSecretRes := asmsecret.NewASMSecretResource(
 				testTask.Arn,
 				asmRequirements,
+				nil,
 				credentialsID,
 				credentialsManager,
-				asmClientCreator)
+				asmClientCreator,
+				"",
+				0,
+				nil)
 
 			testTask.ResourcesMapUnsafe = map[string][]taskresource.TaskResource{
 				ssmsecret.ResourceName: {ssmSecretRes},
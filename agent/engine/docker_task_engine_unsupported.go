@@ -22,6 +22,8 @@ import (
 
 	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
 	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
+	apierrors "github.com/aws/amazon-ecs-agent/ecs-agent/api/errors"
+	dockercontainer "github.com/docker/docker/api/types/container"
 )
 
 const (
@@ -55,3 +57,14 @@ func (engine *DockerTaskEngine) reloadAppNetImage() error {
 // with updated AppNet image
 func (engine *DockerTaskEngine) restartInstanceTask() {
 }
+
+// injectCDIDevices is not supported on this platform
+func (engine *DockerTaskEngine) injectCDIDevices(task *apitask.Task, container *apicontainer.Container,
+	hostConfig *dockercontainer.HostConfig) *apierrors.HostConfigError {
+	return &apierrors.HostConfigError{Msg: "CDI devices are not supported on this platform"}
+}
+
+// getUnsupportedCDIDevices returns no devices as CDI devices are not supported on this platform
+func (engine *DockerTaskEngine) getUnsupportedCDIDevices() map[string]string {
+	return nil
+}
@@ -23,8 +23,10 @@ import (
 
 	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
 	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
+	apierrors "github.com/aws/amazon-ecs-agent/ecs-agent/api/errors"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tracing"
 	dockercontainer "github.com/docker/docker/api/types/container"
 
 	"github.com/pkg/errors"
@@ -56,10 +58,9 @@ func (engine *DockerTaskEngine) invokePluginsForContainer(task *apitask.Task, co
 	}
 
 	// Invoke the cni plugin for the container using libcni
-	_, err = engine.cniClient.SetupNS(engine.ctx, cniConfig, cniSetupTimeout)
+	_, err = engine.cniClient.SetupNS(tracing.TaskContext(engine.ctx, task.Arn), cniConfig, cniSetupTimeout)
 	if err != nil {
-		logger.Error("Unable to configure container in the pause namespace", logger.Fields{
-			field.TaskID:    task.GetID(),
+		logger.ErrorCtx(engine.taskLogContext(task), "Unable to configure container in the pause namespace", logger.Fields{
 			field.Container: container.Name,
 			field.Error:     err,
 		})
@@ -98,3 +99,14 @@ func (engine *DockerTaskEngine) updateCredentialSpecMapping(taskID string, conta
 		}
 	}
 }
+
+// injectCDIDevices is not supported on this platform
+func (engine *DockerTaskEngine) injectCDIDevices(task *apitask.Task, container *apicontainer.Container,
+	hostConfig *dockercontainer.HostConfig) *apierrors.HostConfigError {
+	return &apierrors.HostConfigError{Msg: "CDI devices are not supported on this platform"}
+}
+
+// getUnsupportedCDIDevices returns no devices as CDI devices are not supported on this platform
+func (engine *DockerTaskEngine) getUnsupportedCDIDevices() map[string]string {
+	return nil
+}
@@ -14,6 +14,7 @@
 package dockerstate
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 	"sync"
@@ -22,8 +23,9 @@ import (
 	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
 	"github.com/aws/amazon-ecs-agent/agent/engine/image"
 	apiresource "github.com/aws/amazon-ecs-agent/ecs-agent/api/attachment/resource"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
 	ni "github.com/aws/amazon-ecs-agent/ecs-agent/netlib/model/networkinterface"
-	"github.com/cihub/seelog"
 )
 
 // TaskEngineState keeps track of all mappings between tasks we know about
@@ -245,7 +247,7 @@ func (state *DockerTaskEngineState) ENIByMac(mac string) (*ni.ENIAttachment, boo
 // AddENIAttachment adds the eni into the state
 func (state *DockerTaskEngineState) AddENIAttachment(eniAttachment *ni.ENIAttachment) {
 	if eniAttachment == nil {
-		seelog.Debug("Cannot add empty eni attachment information")
+		logger.Debug("Cannot add empty eni attachment information")
 		return
 	}
 
@@ -255,7 +257,9 @@ func (state *DockerTaskEngineState) AddENIAttachment(eniAttachment *ni.ENIAttach
 	if _, ok := state.eniAttachments[eniAttachment.MACAddress]; !ok {
 		state.eniAttachments[eniAttachment.MACAddress] = eniAttachment
 	} else {
-		seelog.Debugf("Duplicate eni attachment information: %v", eniAttachment)
+		logger.Debug("Duplicate eni attachment information", logger.Fields{
+			field.NetworkInterface: eniAttachment.String(),
+		})
 	}
 
 }
@@ -263,7 +267,7 @@ func (state *DockerTaskEngineState) AddENIAttachment(eniAttachment *ni.ENIAttach
 // RemoveENIAttachment removes the eni from state and stop managing
 func (state *DockerTaskEngineState) RemoveENIAttachment(mac string) {
 	if mac == "" {
-		seelog.Debug("Cannot remove empty eni attachment information")
+		logger.Debug("Cannot remove empty eni attachment information")
 		return
 	}
 	state.lock.Lock()
@@ -271,7 +275,9 @@ func (state *DockerTaskEngineState) RemoveENIAttachment(mac string) {
 	if _, ok := state.eniAttachments[mac]; ok {
 		delete(state.eniAttachments, mac)
 	} else {
-		seelog.Debugf("Delete non-existed eni attachment: %v", mac)
+		logger.Debug("Delete non-existed eni attachment", logger.Fields{
+			"mac": mac,
+		})
 	}
 }
 
@@ -331,7 +337,7 @@ func (state *DockerTaskEngineState) allPendingEBSAttachmentsWithKeyUnsafe() map[
 // AddEBSAttachment adds the ebs volume to state
 func (state *DockerTaskEngineState) AddEBSAttachment(ebsAttachment *apiresource.ResourceAttachment) {
 	if ebsAttachment == nil {
-		seelog.Debug("Cannot add empty ebs attachment information")
+		logger.Debug("Cannot add empty ebs attachment information")
 		return
 	}
 	state.lock.Lock()
@@ -339,25 +345,33 @@ func (state *DockerTaskEngineState) AddEBSAttachment(ebsAttachment *apiresource.
 	volumeId := ebsAttachment.AttachmentProperties[apiresource.VolumeIdKey]
 	if _, ok := state.ebsAttachments[volumeId]; !ok {
 		state.ebsAttachments[volumeId] = ebsAttachment
-		seelog.Debugf("Successfully added EBS attachment: %v", ebsAttachment.EBSToString())
+		logger.Debug("Successfully added EBS attachment", logger.Fields{
+			"ebsAttachment": ebsAttachment.EBSToString(),
+		})
 	} else {
-		seelog.Debugf("Duplicate ebs attachment information: %v", ebsAttachment.EBSToString())
+		logger.Debug("Duplicate ebs attachment information", logger.Fields{
+			"ebsAttachment": ebsAttachment.EBSToString(),
+		})
 	}
 }
 
 // RemoveEBSAttachment removes the ebs volume from state and stops managing
 func (state *DockerTaskEngineState) RemoveEBSAttachment(volumeId string) {
 	if volumeId == "" {
-		seelog.Debug("Cannot remove empty ebs attachment information")
+		logger.Debug("Cannot remove empty ebs attachment information")
 		return
 	}
 	state.lock.Lock()
 	defer state.lock.Unlock()
 	if ebs, ok := state.ebsAttachments[volumeId]; ok {
 		delete(state.ebsAttachments, volumeId)
-		seelog.Debugf("Successfully deleted EBS attachment: %v", ebs.EBSToString())
+		logger.Debug("Successfully deleted EBS attachment", logger.Fields{
+			"ebsAttachment": ebs.EBSToString(),
+		})
 	} else {
-		seelog.Debugf("RemoveEBSAttachment: The requested EBS attachment with volume ID: %v does not exist", volumeId)
+		logger.Debug("RemoveEBSAttachment: The requested EBS attachment does not exist", logger.Fields{
+			field.Volume: volumeId,
+		})
 	}
 }
 
@@ -479,13 +493,13 @@ func (state *DockerTaskEngineState) AddPulledContainer(container *apicontainer.D
 	state.lock.Lock()
 	defer state.lock.Unlock()
 	if task == nil || container == nil {
-		seelog.Critical("AddPulledContainer called with nil task/container")
+		logger.Critical("AddPulledContainer called with nil task/container")
 		return
 	}
 
 	_, exists := state.tasks[task.Arn]
 	if !exists {
-		seelog.Debugf("AddPulledContainer called with unknown task; adding", "arn", task.Arn)
+		logger.DebugCtx(task.LogContext(context.Background()), "AddPulledContainer called with unknown task; adding")
 		state.tasks[task.Arn] = task
 	}
 
@@ -504,20 +518,20 @@ func (state *DockerTaskEngineState) AddContainer(container *apicontainer.DockerC
 	state.lock.Lock()
 	defer state.lock.Unlock()
 	if task == nil || container == nil {
-		seelog.Critical("AddContainer called with nil task/container")
+		logger.Critical("AddContainer called with nil task/container")
 		return
 	}
 
 	_, exists := state.tasks[task.Arn]
 	if !exists {
-		seelog.Debugf("AddContainer called with unknown task; adding", "arn", task.Arn)
+		logger.DebugCtx(task.LogContext(context.Background()), "AddContainer called with unknown task; adding")
 		state.tasks[task.Arn] = task
 	}
 
 	_, pulledExist := state.taskToPulledContainer[task.Arn]
 	if pulledExist {
-		seelog.Debugf("Delete a pulled container named %s from the pulled container map associated with the"+
-			" task ARN %s since AddContainer is called", container.Container.Name, task.Arn)
+		logger.DebugCtx(container.Container.LogContext(task.LogContext(context.Background())),
+			"Delete the pulled container from the pulled container map of the task since AddContainer is called")
 		delete(state.taskToPulledContainer[task.Arn], container.Container.Name)
 		if len(state.taskToPulledContainer[task.Arn]) == 0 {
 			delete(state.taskToPulledContainer, task.Arn)
@@ -545,11 +559,11 @@ func (state *DockerTaskEngineState) AddContainer(container *apicontainer.DockerC
 // AddImageState adds an image.ImageState to be stored
 func (state *DockerTaskEngineState) AddImageState(imageState *image.ImageState) {
 	if imageState == nil {
-		seelog.Debug("Cannot add empty image state")
+		logger.Debug("Cannot add empty image state")
 		return
 	}
 	if imageState.Image.ImageID == "" {
-		seelog.Debug("Cannot add image state with empty image id")
+		logger.Debug("Cannot add image state with empty image id")
 		return
 	}
 	state.lock.Lock()
@@ -566,7 +580,7 @@ func (state *DockerTaskEngineState) RemoveTask(task *apitask.Task) {
 
 	task, ok := state.tasks[task.Arn]
 	if !ok {
-		seelog.Warnf("Failed to locate task %s for removal from state", task.Arn)
+		logger.WarnCtx(task.LogContext(context.Background()), "Failed to locate task for removal from state")
 		return
 	}
 	delete(state.tasks, task.Arn)
@@ -576,7 +590,7 @@ func (state *DockerTaskEngineState) RemoveTask(task *apitask.Task) {
 
 	containerMap, ok := state.taskToID[task.Arn]
 	if !ok {
-		seelog.Warnf("Failed to locate containerMap for task %s for removal from state", task.Arn)
+		logger.WarnCtx(task.LogContext(context.Background()), "Failed to locate containerMap for task for removal from state")
 		return
 	}
 	delete(state.taskToID, task.Arn)
@@ -644,7 +658,7 @@ func (state *DockerTaskEngineState) removeV3EndpointIDToTaskContainerUnsafe(v3En
 // RemoveImageState removes an image.ImageState
 func (state *DockerTaskEngineState) RemoveImageState(imageState *image.ImageState) {
 	if imageState == nil {
-		seelog.Debug("Cannot remove empty image state")
+		logger.Debug("Cannot remove empty image state")
 		return
 	}
 	state.lock.Lock()
@@ -652,7 +666,7 @@ func (state *DockerTaskEngineState) RemoveImageState(imageState *image.ImageStat
 
 	imageState, ok := state.imageStates[imageState.Image.ImageID]
 	if !ok {
-		seelog.Debug("Image State is not found. Cannot be removed")
+		logger.Debug("Image State is not found. Cannot be removed")
 		return
 	}
 	delete(state.imageStates, imageState.Image.ImageID)
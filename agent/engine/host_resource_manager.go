@@ -17,8 +17,10 @@ package engine
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
+	"github.com/aws/amazon-ecs-agent/agent/cdi"
 	"github.com/aws/amazon-ecs-agent/agent/utils"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
@@ -28,6 +30,7 @@ import (
 const (
 	CPU      = "CPU"
 	GPU      = "GPU"
+	CDI      = "CDI"
 	MEMORY   = "MEMORY"
 	PORTSTCP = "PORTS_TCP"
 	PORTSUDP = "PORTS_UDP"
@@ -59,6 +62,7 @@ func (h *HostResourceManager) logResources(msg string, taskArn string) {
 		"PORTS_TCP": h.consumedResource[PORTSTCP].StringSetValue,
 		"PORTS_UDP": h.consumedResource[PORTSUDP].StringSetValue,
 		"GPU":       h.consumedResource[GPU].StringSetValue,
+		"CDI":       h.consumedResource[CDI].StringSetValue,
 	})
 }
 
@@ -115,7 +119,7 @@ func (h *HostResourceManager) consume(taskArn string, resources map[string]types
 				// CPU, MEMORY
 				h.consumeIntType(resourceKey, resources)
 			} else if *resources[resourceKey].Type == "STRINGSET" {
-				// PORTS_TCP, PORTS_UDP, GPU
+				// PORTS_TCP, PORTS_UDP, GPU, CDI
 				h.consumeStringSetType(resourceKey, resources)
 			}
 		}
@@ -132,6 +136,19 @@ func (h *HostResourceManager) consume(taskArn string, resources map[string]types
 	return false, nil
 }
 
+// unavailableResources returns the sorted names of the host resources which can't be consumed for
+// the given resources of a task with the current account of the host resources
+func (h *HostResourceManager) unavailableResources(resources map[string]types.Resource) []string {
+	h.hostResourceManagerRWLock.Lock()
+	defer h.hostResourceManagerRWLock.Unlock()
+	_, resourcesNotConsumable, err := h.consumable(resources)
+	if err != nil {
+		return nil
+	}
+	sort.Strings(resourcesNotConsumable)
+	return resourcesNotConsumable
+}
+
 // Functions checkConsumableIntType and checkConsumableStringSetType to be called
 // only after checking for resource map health
 func (h *HostResourceManager) checkConsumableIntType(resourceName string, resources map[string]types.Resource) bool {
@@ -169,7 +186,7 @@ func (h *HostResourceManager) checkResourcesHealth(resources map[string]types.Re
 		}
 
 		// CPU, MEMORY are INTEGER;
-		// PORTS_TCP, PORTS_UDP, GPU are STRINGSET
+		// PORTS_TCP, PORTS_UDP, GPU, CDI are STRINGSET
 		// Check if either of these data types exist
 		if resourceVal.Type == nil || !(*resourceVal.Type == "INTEGER" || *resourceVal.Type == "STRINGSET") {
 			logger.Error(fmt.Sprintf("type not assigned for resource %s", resourceKey))
@@ -189,10 +206,47 @@ func (h *HostResourceManager) checkResourcesHealth(resources map[string]types.Re
 				}
 			}
 		}
+
+		// Verify the CDI devices of the task are devices of the host
+		if *resourceVal.Type == "STRINGSET" && resourceKey == CDI {
+			hostCDIDevices := make(map[string]struct{}, len(h.initialHostResource[CDI].StringSetValue))
+			for _, v := range h.initialHostResource[CDI].StringSetValue {
+				hostCDIDevices[v] = struct{}{}
+			}
+			for _, device := range resourceVal.StringSetValue {
+				if _, ok := hostCDIDevices[device]; !ok {
+					return fmt.Errorf("task CDI device %s not found in host CDI devices", device)
+				}
+			}
+		}
 	}
 	return nil
 }
 
+// cdiDevicesOfKind returns the sorted CDI devices of the kind of the host, and the ones not consumed by the tasks
+func (h *HostResourceManager) cdiDevicesOfKind(kind string) ([]string, []string) {
+	h.hostResourceManagerRWLock.Lock()
+	defer h.hostResourceManagerRWLock.Unlock()
+
+	consumedDevices := make(map[string]struct{}, len(h.consumedResource[CDI].StringSetValue))
+	for _, device := range h.consumedResource[CDI].StringSetValue {
+		consumedDevices[device] = struct{}{}
+	}
+	var devices, freeDevices []string
+	for _, device := range h.initialHostResource[CDI].StringSetValue {
+		if cdi.Kind(device) != kind {
+			continue
+		}
+		devices = append(devices, device)
+		if _, ok := consumedDevices[device]; !ok {
+			freeDevices = append(freeDevices, device)
+		}
+	}
+	sort.Strings(devices)
+	sort.Strings(freeDevices)
+	return devices, freeDevices
+}
+
 // Helper function for consume to check if resources are consumable with the current account
 // we have for the host resources. Should not call host resource manager lock in this func return values
 // This function returns a bool (indicating whether ALL requested resources are consumable), a list of non-consumable
@@ -350,6 +404,14 @@ func NewHostResourceManager(resourceMap map[string]types.Resource) HostResourceM
 		StringSetValue: gpuIDs,
 	}
 
+	// CDI devices
+	cdiDevices := []string{}
+	consumedResourceMap[CDI] = types.Resource{
+		Name:           utils.Strptr(CDI),
+		Type:           utils.Strptr("STRINGSET"),
+		StringSetValue: cdiDevices,
+	}
+
 	logger.Info("Initializing host resource manager, initialHostResource", logger.Fields{"initialHostResource": resourceMap})
 	logger.Info("Initializing host resource manager, consumed resource", logger.Fields{"consumedResource": consumedResourceMap})
 	return HostResourceManager{
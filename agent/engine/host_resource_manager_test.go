@@ -150,6 +150,20 @@ func TestHostResourceConsumeFail(t *testing.T) {
 	assert.Equal(t, len(h.consumedResource["GPU"].StringSetValue), 0, "Incorrect gpu resource accounting during consume")
 }
 
+func TestHostResourceUnavailableResources(t *testing.T) {
+	gpuIDs := []string{"gpu1", "gpu2"}
+	h := getTestHostResourceManager(int32(1024), int32(1024), []string{"22"}, []string{"1000"}, gpuIDs)
+
+	taskResources := getTestTaskResourceMap(int32(2048), int32(512), []string{"22"}, []string{"1001"}, []string{"gpu1"})
+	consumed, err := h.consume("arn:aws:ecs:us-east-1:<aws_account_id>:task/cluster-name/11111", taskResources)
+	assert.NoError(t, err)
+	assert.False(t, consumed)
+	assert.Equal(t, []string{"CPU", "PORTS_TCP"}, h.unavailableResources(taskResources))
+
+	taskResources = getTestTaskResourceMap(int32(512), int32(512), []string{"80"}, []string{"1001"}, []string{"gpu1"})
+	assert.Empty(t, h.unavailableResources(taskResources))
+}
+
 func TestHostResourceRelease(t *testing.T) {
 	hostResourcePort1 := "22"
 	hostResourcePort2 := "1000"
@@ -290,3 +304,39 @@ func TestResourceHealthGPUFalse(t *testing.T) {
 	err := h.checkResourcesHealth(resources)
 	assert.Error(t, err, "Error in checking unhealthy resource map status")
 }
+
+// Verify CDI devices are consumed exclusively and validated against the devices of the host
+func TestHostResourceConsumeCDIDevices(t *testing.T) {
+	h := getTestHostResourceManager(int32(2048), int32(2048), []string{"22"}, []string{"1000"}, []string{})
+	h.initialHostResource[CDI] = types.Resource{
+		Name:           utils.Strptr(CDI),
+		Type:           utils.Strptr("STRINGSET"),
+		StringSetValue: []string{"vendor.com/fpga=0", "vendor.com/fpga=1"},
+	}
+	taskResources := func(devices ...string) map[string]types.Resource {
+		resources := getTestTaskResourceMap(512, 512, []string{}, []string{}, []string{})
+		resources[CDI] = types.Resource{
+			Name:           utils.Strptr(CDI),
+			Type:           utils.Strptr("STRINGSET"),
+			StringSetValue: devices,
+		}
+		return resources
+	}
+
+	consumed, err := h.consume("arn:task1", taskResources("vendor.com/fpga=0"))
+	assert.NoError(t, err)
+	assert.True(t, consumed)
+	assert.Equal(t, []string{"vendor.com/fpga=0"}, h.consumedResource[CDI].StringSetValue)
+
+	consumed, err = h.consume("arn:task2", taskResources("vendor.com/fpga=0", "vendor.com/fpga=1"))
+	assert.NoError(t, err)
+	assert.False(t, consumed, "Device consumed by another task should not be consumable")
+
+	_, err = h.consume("arn:task3", taskResources("vendor.com/fpga=2"))
+	assert.Error(t, err, "Device not found on the host should fail the task")
+
+	assert.NoError(t, h.release("arn:task1", taskResources("vendor.com/fpga=0")))
+	consumed, err = h.consume("arn:task2", taskResources("vendor.com/fpga=0", "vendor.com/fpga=1"))
+	assert.NoError(t, err)
+	assert.True(t, consumed)
+}
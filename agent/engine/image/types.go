@@ -50,7 +50,10 @@ type ImageState struct {
 	// PullSucceeded defines whether this image has been pulled successfully before,
 	// this should be set to true when one of the pull image call succeeds.
 	PullSucceeded bool
-	lock          sync.RWMutex
+	// ProtectedUntil is the time until which this image isn't removed by the image
+	// cleanup, even if it's unused. It's set when the image is pre-warmed.
+	ProtectedUntil time.Time
+	lock           sync.RWMutex
 }
 
 // UpdateContainerReference updates container reference in image state
@@ -164,21 +167,45 @@ func (imageState *ImageState) GetPullSucceeded() bool {
 	return imageState.PullSucceeded
 }
 
+// SetProtectedUntil protects the image from cleanup until the given time
+func (imageState *ImageState) SetProtectedUntil(protectedUntil time.Time) {
+	imageState.lock.Lock()
+	defer imageState.lock.Unlock()
+
+	if protectedUntil.After(imageState.ProtectedUntil) {
+		imageState.ProtectedUntil = protectedUntil
+	}
+}
+
+// IsProtected returns whether the image is protected from cleanup at the given time
+func (imageState *ImageState) IsProtected(now time.Time) bool {
+	imageState.lock.RLock()
+	defer imageState.lock.RUnlock()
+
+	return now.Before(imageState.ProtectedUntil)
+}
+
 // MarshalJSON marshals image state
 func (imageState *ImageState) MarshalJSON() ([]byte, error) {
 	imageState.lock.Lock()
 	defer imageState.lock.Unlock()
 
+	var protectedUntil *time.Time
+	if !imageState.ProtectedUntil.IsZero() {
+		protectedUntil = &imageState.ProtectedUntil
+	}
 	return json.Marshal(&struct {
-		Image         *Image
-		PulledAt      time.Time
-		LastUsedAt    time.Time
-		PullSucceeded bool
+		Image          *Image
+		PulledAt       time.Time
+		LastUsedAt     time.Time
+		PullSucceeded  bool
+		ProtectedUntil *time.Time `json:",omitempty"`
 	}{
-		Image:         imageState.Image,
-		PulledAt:      imageState.PulledAt,
-		LastUsedAt:    imageState.LastUsedAt,
-		PullSucceeded: imageState.PullSucceeded,
+		Image:          imageState.Image,
+		PulledAt:       imageState.PulledAt,
+		LastUsedAt:     imageState.LastUsedAt,
+		PullSucceeded:  imageState.PullSucceeded,
+		ProtectedUntil: protectedUntil,
 	})
 }
 
@@ -0,0 +1,215 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-ecs-agent/agent/config"
+	"github.com/aws/amazon-ecs-agent/agent/dockerclient"
+	"github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi"
+	"github.com/aws/amazon-ecs-agent/agent/engine/image"
+	"github.com/aws/amazon-ecs-agent/agent/utils"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+)
+
+// imageCleanupPolicy selects the images removed by the image manager during an image cleanup cycle
+type imageCleanupPolicy interface {
+	// nextImageForDeletion returns the next image to remove among the candidate images, which are
+	// unused and old enough to be removed, or nil if no more images should be removed during this
+	// cleanup cycle. allImages holds all the images tracked by the image manager, and numDeleted
+	// the number of images already removed during this cleanup cycle.
+	nextImageForDeletion(ctx context.Context, candidates, allImages []*image.ImageState,
+		numDeleted int) *image.ImageState
+}
+
+// newImageCleanupPolicy returns the image cleanup policy selected in the config
+func newImageCleanupPolicy(cfg *config.Config, client dockerapi.DockerClient) imageCleanupPolicy {
+	lru := &lruImageCleanupPolicy{numImagesToDelete: cfg.NumImagesToDeletePerCycle}
+	switch cfg.ImageCleanupPolicy {
+	case config.ImageCleanupDiskUsagePolicy:
+		return &diskUsageImageCleanupPolicy{
+			client:        client,
+			threshold:     float64(cfg.ImageCleanupDiskUsageThreshold),
+			dockerRootDir: dockerDataRootPath,
+			diskUsage:     diskUsagePercent,
+			fallback:      lru,
+		}
+	case config.ImageCleanupSizeWeightedPolicy:
+		return &sizeWeightedImageCleanupPolicy{numImagesToDelete: cfg.NumImagesToDeletePerCycle}
+	case config.ImageCleanupKeepVersionsPolicy:
+		return &keepVersionsImageCleanupPolicy{
+			numImagesToDelete: cfg.NumImagesToDeletePerCycle,
+			keepVersions:      cfg.ImageCleanupKeepVersions,
+		}
+	default:
+		return lru
+	}
+}
+
+// lruImageCleanupPolicy removes the least recently used images first, up to numImagesToDelete
+// images every cleanup cycle
+type lruImageCleanupPolicy struct {
+	numImagesToDelete int
+}
+
+func (policy *lruImageCleanupPolicy) nextImageForDeletion(ctx context.Context, candidates,
+	allImages []*image.ImageState, numDeleted int) *image.ImageState {
+	if numDeleted >= policy.numImagesToDelete {
+		return nil
+	}
+	return leastRecentlyUsedImage(candidates)
+}
+
+// diskUsageImageCleanupPolicy removes the least recently used images first until the usage of
+// the disk holding the Docker data root falls below the threshold. It falls back to the LRU
+// policy if the disk usage can't be found.
+type diskUsageImageCleanupPolicy struct {
+	client dockerapi.DockerClient
+	// threshold is the percentage of disk usage below which images aren't removed
+	threshold float64
+	// dockerRootDir is the path of the Docker data root as seen by the agent. It's looked up from Docker if empty.
+	dockerRootDir string
+	diskUsage     func(path string) (float64, error)
+	fallback      imageCleanupPolicy
+}
+
+func (policy *diskUsageImageCleanupPolicy) nextImageForDeletion(ctx context.Context, candidates,
+	allImages []*image.ImageState, numDeleted int) *image.ImageState {
+	if policy.dockerRootDir == "" {
+		info, err := policy.client.Info(ctx, dockerclient.InfoTimeout)
+		if err != nil || info.DockerRootDir == "" {
+			logger.Warn("Unable to find the Docker data root, falling back to LRU image cleanup", logger.Fields{
+				field.Error: err,
+			})
+			return policy.fallback.nextImageForDeletion(ctx, candidates, allImages, numDeleted)
+		}
+		policy.dockerRootDir = info.DockerRootDir
+	}
+	usage, err := policy.diskUsage(policy.dockerRootDir)
+	if err != nil {
+		logger.Warn("Unable to find the disk usage of the Docker data root, falling back to LRU image cleanup",
+			logger.Fields{
+				"dockerRootDir": policy.dockerRootDir,
+				field.Error:     err,
+			})
+		return policy.fallback.nextImageForDeletion(ctx, candidates, allImages, numDeleted)
+	}
+	if usage < policy.threshold {
+		logger.Debug("Disk usage of the Docker data root is below the image cleanup threshold", logger.Fields{
+			"dockerRootDir": policy.dockerRootDir,
+			"diskUsage":     usage,
+			"threshold":     policy.threshold,
+		})
+		return nil
+	}
+	return leastRecentlyUsedImage(candidates)
+}
+
+// sizeWeightedImageCleanupPolicy removes the images with the largest size weighted by the time
+// since they were last used first, up to numImagesToDelete images every cleanup cycle. Large
+// images are therefore removed before small images that were used as long ago.
+type sizeWeightedImageCleanupPolicy struct {
+	numImagesToDelete int
+}
+
+func (policy *sizeWeightedImageCleanupPolicy) nextImageForDeletion(ctx context.Context, candidates,
+	allImages []*image.ImageState, numDeleted int) *image.ImageState {
+	if numDeleted >= policy.numImagesToDelete {
+		return nil
+	}
+	now := time.Now()
+	var next *image.ImageState
+	var nextWeight float64
+	for _, imageState := range candidates {
+		// Images which were never used are weighted as if they were last used when they were pulled
+		lastUsedAt := imageState.LastUsedAt
+		if lastUsedAt.IsZero() {
+			lastUsedAt = imageState.PulledAt
+		}
+		weight := float64(imageState.Image.Size) * now.Sub(lastUsedAt).Seconds()
+		if next == nil || weight > nextWeight {
+			next, nextWeight = imageState, weight
+		}
+	}
+	return next
+}
+
+// keepVersionsImageCleanupPolicy removes the least recently used images first, up to
+// numImagesToDelete images every cleanup cycle, but never removes the keepVersions most
+// recently pulled images of each repository.
+type keepVersionsImageCleanupPolicy struct {
+	numImagesToDelete int
+	keepVersions      int
+}
+
+func (policy *keepVersionsImageCleanupPolicy) nextImageForDeletion(ctx context.Context, candidates,
+	allImages []*image.ImageState, numDeleted int) *image.ImageState {
+	if numDeleted >= policy.numImagesToDelete {
+		return nil
+	}
+	imagesByRepository := make(map[string][]*image.ImageState)
+	for _, imageState := range allImages {
+		for _, repository := range imageRepositories(imageState) {
+			imagesByRepository[repository] = append(imagesByRepository[repository], imageState)
+		}
+	}
+	kept := make(map[*image.ImageState]bool)
+	for _, images := range imagesByRepository {
+		sort.SliceStable(images, func(i, j int) bool {
+			return images[i].PulledAt.After(images[j].PulledAt)
+		})
+		for i := 0; i < len(images) && i < policy.keepVersions; i++ {
+			kept[images[i]] = true
+		}
+	}
+	var removable []*image.ImageState
+	for _, imageState := range candidates {
+		if !kept[imageState] {
+			removable = append(removable, imageState)
+		}
+	}
+	return leastRecentlyUsedImage(removable)
+}
+
+// imageRepositories returns the repositories of the names of the image, without tags or digests
+func imageRepositories(imageState *image.ImageState) []string {
+	var repositories []string
+	for _, name := range imageState.Image.Names {
+		if i := strings.Index(name, "@"); i >= 0 {
+			name = name[:i]
+		}
+		repository, _ := utils.ParseRepositoryTag(name)
+		if !utils.StrSliceContains(repositories, repository) {
+			repositories = append(repositories, repository)
+		}
+	}
+	return repositories
+}
+
+// leastRecentlyUsedImage returns the least recently used image, or nil if there are no images
+func leastRecentlyUsedImage(imageStates []*image.ImageState) *image.ImageState {
+	if len(imageStates) == 0 {
+		return nil
+	}
+	candidateImages := make(ImageStatesForDeletion, len(imageStates))
+	copy(candidateImages, imageStates)
+	// sort images in the order of last used times
+	sort.Sort(candidateImages)
+	return candidateImages[0]
+}
@@ -0,0 +1,43 @@
+//go:build linux
+// +build linux
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package engine
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// dockerDataRootPath is where ecs-init mounts the Docker data root of the host, read-only, when the disk-usage image
+// cleanup policy is selected. The data root reported by Docker is a path on the host, which isn't visible in the
+// agent container.
+const dockerDataRootPath = "/docker-data-root"
+
+// diskUsagePercent returns the percentage of the disk holding the path that is used
+func diskUsagePercent(path string) (float64, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	if stat.Blocks == 0 {
+		return 0, fmt.Errorf("no blocks reported for the filesystem of %s", path)
+	}
+	used := stat.Blocks - stat.Bfree
+	// The blocks reserved for the root user aren't available to unprivileged users, so the usage is
+	// computed against the blocks available to them like df does.
+	return float64(used) * 100 / float64(used+stat.Bavail), nil
+}
@@ -0,0 +1,61 @@
+//go:build linux && unit
+// +build linux,unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ecs-agent/agent/config"
+	mock_dockerapi "github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi/mocks"
+	"github.com/aws/amazon-ecs-agent/agent/engine/image"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskUsagePercent(t *testing.T) {
+	usage, err := diskUsagePercent(t.TempDir())
+	require.NoError(t, err)
+	assert.True(t, usage >= 0 && usage <= 100, "disk usage should be a percentage: %f", usage)
+
+	_, err = diskUsagePercent(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func TestDiskUsageImageCleanupPolicyDockerDataRootMount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	// The Docker data root isn't looked up from Docker, since the path it reports is a host path
+	client := mock_dockerapi.NewMockDockerClient(ctrl)
+
+	cfg := defaultTestConfig()
+	cfg.ImageCleanupPolicy = config.ImageCleanupDiskUsagePolicy
+	cfg.ImageCleanupDiskUsageThreshold = 80
+	policy := newImageCleanupPolicy(cfg, client).(*diskUsageImageCleanupPolicy)
+	policy.diskUsage = func(path string) (float64, error) {
+		assert.Equal(t, "/docker-data-root", path, "the disk usage should be read from the data root mounted by ecs-init")
+		return 90, nil
+	}
+
+	now := time.Now()
+	candidates := []*image.ImageState{newCleanupTestImageState("image", 1, now, now, "image:latest")}
+	assert.Equal(t, candidates[0], policy.nextImageForDeletion(context.TODO(), candidates, candidates, 0))
+}
@@ -0,0 +1,178 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ecs-agent/agent/config"
+	"github.com/aws/amazon-ecs-agent/agent/data"
+	"github.com/aws/amazon-ecs-agent/agent/dockerclient"
+	mock_dockerapi "github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi/mocks"
+	"github.com/aws/amazon-ecs-agent/agent/engine/dockerstate"
+	"github.com/aws/amazon-ecs-agent/agent/engine/image"
+
+	"github.com/docker/docker/api/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCleanupTestImageState(id string, size int64, pulledAt, lastUsedAt time.Time, names ...string) *image.ImageState {
+	return &image.ImageState{
+		Image:      &image.Image{ImageID: id, Names: names, Size: size},
+		PulledAt:   pulledAt,
+		LastUsedAt: lastUsedAt,
+	}
+}
+
+func TestNewImageCleanupPolicy(t *testing.T) {
+	testCases := []struct {
+		policy   config.ImageCleanupPolicyType
+		expected imageCleanupPolicy
+	}{
+		{config.ImageCleanupLRUPolicy, &lruImageCleanupPolicy{}},
+		{config.ImageCleanupDiskUsagePolicy, &diskUsageImageCleanupPolicy{}},
+		{config.ImageCleanupSizeWeightedPolicy, &sizeWeightedImageCleanupPolicy{}},
+		{config.ImageCleanupKeepVersionsPolicy, &keepVersionsImageCleanupPolicy{}},
+	}
+	for _, tc := range testCases {
+		cfg := defaultTestConfig()
+		cfg.ImageCleanupPolicy = tc.policy
+		assert.IsType(t, tc.expected, newImageCleanupPolicy(cfg, nil))
+	}
+}
+
+func TestLRUImageCleanupPolicy(t *testing.T) {
+	now := time.Now()
+	older := newCleanupTestImageState("older", 1, now, now.Add(-2*time.Hour), "older:latest")
+	newer := newCleanupTestImageState("newer", 1, now, now.Add(-time.Hour), "newer:latest")
+	candidates := []*image.ImageState{newer, older}
+
+	policy := &lruImageCleanupPolicy{numImagesToDelete: 1}
+	assert.Equal(t, older, policy.nextImageForDeletion(context.TODO(), candidates, candidates, 0))
+	assert.Nil(t, policy.nextImageForDeletion(context.TODO(), candidates, candidates, 1))
+	assert.Nil(t, policy.nextImageForDeletion(context.TODO(), nil, candidates, 0))
+}
+
+func TestDiskUsageImageCleanupPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock_dockerapi.NewMockDockerClient(ctrl)
+
+	now := time.Now()
+	older := newCleanupTestImageState("older", 1, now, now.Add(-2*time.Hour), "older:latest")
+	newer := newCleanupTestImageState("newer", 1, now, now.Add(-time.Hour), "newer:latest")
+	candidates := []*image.ImageState{newer, older}
+
+	usage := 90.0
+	policy := &diskUsageImageCleanupPolicy{
+		client:    client,
+		threshold: 80,
+		diskUsage: func(path string) (float64, error) {
+			assert.Equal(t, "/var/lib/docker", path)
+			return usage, nil
+		},
+		fallback: &lruImageCleanupPolicy{numImagesToDelete: 0},
+	}
+	// The Docker data root is only looked up once
+	client.EXPECT().Info(gomock.Any(), dockerclient.InfoTimeout).Return(types.Info{DockerRootDir: "/var/lib/docker"}, nil)
+
+	// Images are removed regardless of how many were already removed while the disk usage is above the threshold
+	assert.Equal(t, older, policy.nextImageForDeletion(context.TODO(), candidates, candidates, 100))
+	usage = 70
+	assert.Nil(t, policy.nextImageForDeletion(context.TODO(), candidates, candidates, 0))
+}
+
+func TestDiskUsageImageCleanupPolicyFallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock_dockerapi.NewMockDockerClient(ctrl)
+
+	now := time.Now()
+	candidates := []*image.ImageState{newCleanupTestImageState("image", 1, now, now, "image:latest")}
+	policy := &diskUsageImageCleanupPolicy{
+		client:    client,
+		threshold: 80,
+		diskUsage: func(path string) (float64, error) {
+			return 0, errors.New("statfs error")
+		},
+		fallback: &lruImageCleanupPolicy{numImagesToDelete: 1},
+	}
+
+	client.EXPECT().Info(gomock.Any(), dockerclient.InfoTimeout).Return(types.Info{}, errors.New("docker error"))
+	assert.Equal(t, candidates[0], policy.nextImageForDeletion(context.TODO(), candidates, candidates, 0))
+
+	client.EXPECT().Info(gomock.Any(), dockerclient.InfoTimeout).Return(types.Info{DockerRootDir: "/var/lib/docker"}, nil)
+	assert.Equal(t, candidates[0], policy.nextImageForDeletion(context.TODO(), candidates, candidates, 0))
+	assert.Nil(t, policy.nextImageForDeletion(context.TODO(), candidates, candidates, 1))
+}
+
+func TestSizeWeightedImageCleanupPolicy(t *testing.T) {
+	now := time.Now()
+	// The large image was used more recently, but it's large enough to be removed first
+	large := newCleanupTestImageState("large", 10*1024*1024*1024, now.Add(-3*time.Hour), now.Add(-time.Hour), "large:latest")
+	small := newCleanupTestImageState("small", 10*1024*1024, now.Add(-3*time.Hour), now.Add(-2*time.Hour), "small:latest")
+	// Images which were never used are weighted from the time they were pulled
+	unused := newCleanupTestImageState("unused", 512*1024*1024, now.Add(-3*time.Hour), time.Time{}, "unused:latest")
+	candidates := []*image.ImageState{small, unused, large}
+
+	policy := &sizeWeightedImageCleanupPolicy{numImagesToDelete: 5}
+	assert.Equal(t, large, policy.nextImageForDeletion(context.TODO(), candidates, candidates, 0))
+	assert.Equal(t, unused, policy.nextImageForDeletion(context.TODO(), []*image.ImageState{small, unused}, candidates, 1))
+	assert.Nil(t, policy.nextImageForDeletion(context.TODO(), candidates, candidates, 5))
+}
+
+func TestKeepVersionsImageCleanupPolicy(t *testing.T) {
+	now := time.Now()
+	v1 := newCleanupTestImageState("v1", 1, now.Add(-3*time.Hour), now.Add(-time.Hour), "registry:5000/app:v1")
+	v2 := newCleanupTestImageState("v2", 1, now.Add(-2*time.Hour), now.Add(-3*time.Hour), "registry:5000/app:v2")
+	v3 := newCleanupTestImageState("v3", 1, now.Add(-time.Hour), now.Add(-4*time.Hour),
+		"registry:5000/app@sha256:9b8ea09b0b0f01d5d5d3b4c9aa5ab40ab6b8e8e5ad0da1d9f4c2e5e8f3c16a1a")
+	other := newCleanupTestImageState("other", 1, now.Add(-4*time.Hour), now.Add(-5*time.Hour), "other:latest")
+	allImages := []*image.ImageState{v1, v2, v3, other}
+
+	policy := &keepVersionsImageCleanupPolicy{numImagesToDelete: 5, keepVersions: 2}
+	// v2 and v3 are the most recently pulled versions of the app repository, and other is the only
+	// version of its repository, so only v1 can be removed even though it's the most recently used
+	assert.Equal(t, v1, policy.nextImageForDeletion(context.TODO(), allImages, allImages, 0))
+	assert.Nil(t, policy.nextImageForDeletion(context.TODO(), []*image.ImageState{v2, v3, other}, allImages, 0))
+	assert.Nil(t, policy.nextImageForDeletion(context.TODO(), allImages, allImages, 5))
+}
+
+func TestRemoveUnusedImagesWithCleanupPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock_dockerapi.NewMockDockerClient(ctrl)
+
+	cfg := defaultTestConfig()
+	cfg.ImageCleanupPolicy = config.ImageCleanupSizeWeightedPolicy
+	cfg.NumImagesToDeletePerCycle = 1
+	imageManager := NewImageManager(cfg, client, dockerstate.NewTaskEngineState()).(*dockerImageManager)
+	imageManager.SetDataClient(data.NewNoopClient())
+
+	now := time.Now()
+	large := newCleanupTestImageState("large", 1024*1024*1024, now.Add(-3*time.Hour), now.Add(-time.Hour), "large:latest")
+	small := newCleanupTestImageState("small", 1024, now.Add(-3*time.Hour), now.Add(-2*time.Hour), "small:latest")
+	imageManager.AddAllImageStates([]*image.ImageState{small, large})
+
+	client.EXPECT().RemoveImage(gomock.Any(), "large:latest", dockerclient.RemoveImageTimeout).Return(nil)
+	imageManager.removeUnusedImages(context.TODO())
+	assert.Equal(t, []*image.ImageState{small}, imageManager.getAllImageStates())
+}
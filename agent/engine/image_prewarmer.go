@@ -0,0 +1,191 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	"github.com/aws/amazon-ecs-agent/agent/config"
+	"github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+)
+
+const (
+	// ImagePrewarmPending is the status of images waiting to be pulled
+	ImagePrewarmPending = "PENDING"
+	// ImagePrewarmPulled is the status of images pulled successfully
+	ImagePrewarmPulled = "PULLED"
+	// ImagePrewarmFailed is the status of images which couldn't be pulled
+	ImagePrewarmFailed = "FAILED"
+)
+
+// ecrImageRegex matches the images hosted in ECR private registries, capturing the registry ID
+// and the region of the registry
+var ecrImageRegex = regexp.MustCompile(`^(\d{12})\.dkr\.ecr(?:-fips)?\.([a-z0-9-]+)\.amazonaws\.com(?:\.cn)?/`)
+
+// ImagePrewarmStatus is the status of an image pre-warmed by the agent
+type ImagePrewarmStatus struct {
+	Image          string
+	Status         string
+	Error          string
+	ProtectedUntil time.Time
+}
+
+// ImagePrewarmer pulls images in the background before any task uses them, so that the tasks
+// placed on the instance don't wait for their images to be pulled. Pre-warmed images are
+// protected from image cleanup for a configurable duration.
+type ImagePrewarmer struct {
+	ctx                context.Context
+	client             dockerapi.DockerClient
+	imageManager       ImageManager
+	auth               string
+	pullTimeout        time.Duration
+	protectionDuration time.Duration
+	statuses           map[string]*ImagePrewarmStatus
+	lock               sync.RWMutex
+}
+
+// NewImagePrewarmer returns an ImagePrewarmer recording the images it pulls in the image manager
+func NewImagePrewarmer(ctx context.Context, cfg *config.Config, client dockerapi.DockerClient,
+	imageManager ImageManager) *ImagePrewarmer {
+	return &ImagePrewarmer{
+		ctx:                ctx,
+		client:             client,
+		imageManager:       imageManager,
+		auth:               cfg.ImagePrewarmAuth,
+		pullTimeout:        cfg.ImagePullTimeout,
+		protectionDuration: cfg.ImagePrewarmProtectionDuration,
+		statuses:           make(map[string]*ImagePrewarmStatus),
+	}
+}
+
+// Prewarm pulls the images one after the other in the background. Images which are already
+// waiting to be pulled are skipped.
+func (prewarmer *ImagePrewarmer) Prewarm(images []string) {
+	var toPull []string
+	prewarmer.lock.Lock()
+	for _, imageName := range images {
+		if status, ok := prewarmer.statuses[imageName]; ok && status.Status == ImagePrewarmPending {
+			continue
+		}
+		prewarmer.statuses[imageName] = &ImagePrewarmStatus{
+			Image:  imageName,
+			Status: ImagePrewarmPending,
+		}
+		toPull = append(toPull, imageName)
+	}
+	prewarmer.lock.Unlock()
+
+	go func() {
+		for _, imageName := range toPull {
+			select {
+			case <-prewarmer.ctx.Done():
+				return
+			default:
+				prewarmer.prewarm(imageName)
+			}
+		}
+	}()
+}
+
+// Statuses returns the status of the images pre-warmed by the agent, sorted by image
+func (prewarmer *ImagePrewarmer) Statuses() []ImagePrewarmStatus {
+	prewarmer.lock.RLock()
+	defer prewarmer.lock.RUnlock()
+	statuses := make([]ImagePrewarmStatus, 0, len(prewarmer.statuses))
+	for _, status := range prewarmer.statuses {
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Image < statuses[j].Image
+	})
+	return statuses
+}
+
+// prewarm pulls the image and records it in the image manager
+func (prewarmer *ImagePrewarmer) prewarm(imageName string) {
+	logger.Info("Pre-warming image", logger.Fields{
+		field.Image: imageName,
+	})
+	err := prewarmer.pull(imageName)
+	var protectedUntil time.Time
+	if err == nil {
+		protectedUntil = time.Now().Add(prewarmer.protectionDuration)
+		err = prewarmer.imageManager.RecordPrewarmedImage(imageName, protectedUntil)
+	}
+
+	prewarmer.lock.Lock()
+	defer prewarmer.lock.Unlock()
+	status := prewarmer.statuses[imageName]
+	if err != nil {
+		logger.Error("Failed to pre-warm image", logger.Fields{
+			field.Image: imageName,
+			field.Error: err,
+		})
+		status.Status = ImagePrewarmFailed
+		status.Error = err.Error()
+		return
+	}
+	logger.Info("Pre-warmed image", logger.Fields{
+		field.Image:      imageName,
+		"protectedUntil": protectedUntil.UTC().Format(time.RFC3339),
+	})
+	status.Status = ImagePrewarmPulled
+	status.Error = ""
+	status.ProtectedUntil = protectedUntil
+}
+
+// pull pulls the image while holding the ImagePullDeleteLock, so that the image isn't removed
+// before it's recorded as pre-warmed
+func (prewarmer *ImagePrewarmer) pull(imageName string) error {
+	ImagePullDeleteLock.RLock()
+	defer ImagePullDeleteLock.RUnlock()
+	metadata := prewarmer.client.PullImage(prewarmer.ctx, imageName, prewarmer.registryAuth(imageName),
+		prewarmer.pullTimeout)
+	if metadata.Error != nil {
+		return metadata.Error
+	}
+	return nil
+}
+
+// registryAuth returns the registry auth data used to pull the image. Images hosted in ECR are
+// pulled with the instance credentials unless the engine auth data is configured to be used.
+func (prewarmer *ImagePrewarmer) registryAuth(imageName string) *apicontainer.RegistryAuthenticationData {
+	if prewarmer.auth != config.ImagePrewarmAuthECR {
+		return nil
+	}
+	return ecrRegistryAuth(imageName)
+}
+
+// ecrRegistryAuth returns the registry auth data pulling the image from ECR with the instance credentials,
+// or nil when the image isn't hosted in ECR
+func ecrRegistryAuth(imageName string) *apicontainer.RegistryAuthenticationData {
+	matches := ecrImageRegex.FindStringSubmatch(imageName)
+	if matches == nil {
+		return nil
+	}
+	return &apicontainer.RegistryAuthenticationData{
+		Type: apicontainer.AuthTypeECR,
+		ECRAuthData: &apicontainer.ECRAuthData{
+			RegistryID: matches[1],
+			Region:     matches[2],
+		},
+	}
+}
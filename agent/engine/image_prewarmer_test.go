@@ -0,0 +1,131 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	"github.com/aws/amazon-ecs-agent/agent/config"
+	"github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi"
+	mock_dockerapi "github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi/mocks"
+	mock_engine "github.com/aws/amazon-ecs-agent/agent/engine/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImagePrewarmerRegistryAuth(t *testing.T) {
+	tcs := []struct {
+		name         string
+		auth         string
+		image        string
+		expectedAuth *apicontainer.RegistryAuthenticationData
+	}{
+		{
+			name:  "ecr image",
+			auth:  config.ImagePrewarmAuthECR,
+			image: "123456789012.dkr.ecr.us-west-2.amazonaws.com/app:latest",
+			expectedAuth: &apicontainer.RegistryAuthenticationData{
+				Type:        apicontainer.AuthTypeECR,
+				ECRAuthData: &apicontainer.ECRAuthData{RegistryID: "123456789012", Region: "us-west-2"},
+			},
+		},
+		{
+			name:  "ecr fips image",
+			auth:  config.ImagePrewarmAuthECR,
+			image: "123456789012.dkr.ecr-fips.us-gov-west-1.amazonaws.com/app:latest",
+			expectedAuth: &apicontainer.RegistryAuthenticationData{
+				Type:        apicontainer.AuthTypeECR,
+				ECRAuthData: &apicontainer.ECRAuthData{RegistryID: "123456789012", Region: "us-gov-west-1"},
+			},
+		},
+		{
+			name:  "ecr china image",
+			auth:  config.ImagePrewarmAuthECR,
+			image: "123456789012.dkr.ecr.cn-north-1.amazonaws.com.cn/app:latest",
+			expectedAuth: &apicontainer.RegistryAuthenticationData{
+				Type:        apicontainer.AuthTypeECR,
+				ECRAuthData: &apicontainer.ECRAuthData{RegistryID: "123456789012", Region: "cn-north-1"},
+			},
+		},
+		{
+			name:  "docker hub image",
+			auth:  config.ImagePrewarmAuthECR,
+			image: "busybox:latest",
+		},
+		{
+			name:  "engine auth",
+			auth:  config.ImagePrewarmAuthEngine,
+			image: "123456789012.dkr.ecr.us-west-2.amazonaws.com/app:latest",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			prewarmer := &ImagePrewarmer{auth: tc.auth}
+			assert.Equal(t, tc.expectedAuth, prewarmer.registryAuth(tc.image))
+		})
+	}
+}
+
+func TestImagePrewarmerPrewarm(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock_dockerapi.NewMockDockerClient(ctrl)
+	imageManager := mock_engine.NewMockImageManager(ctrl)
+
+	cfg := defaultTestConfig()
+	cfg.ImagePrewarmAuth = config.ImagePrewarmAuthEngine
+	cfg.ImagePrewarmProtectionDuration = time.Hour
+	prewarmer := NewImagePrewarmer(context.Background(), cfg, client, imageManager)
+
+	pullErr := dockerapi.CannotPullContainerError{FromError: errors.New("not found")}
+	gomock.InOrder(
+		client.EXPECT().PullImage(gomock.Any(), "busybox:latest", nil, cfg.ImagePullTimeout).
+			Return(dockerapi.DockerContainerMetadata{}),
+		imageManager.EXPECT().RecordPrewarmedImage("busybox:latest", gomock.Any()).Return(nil),
+		client.EXPECT().PullImage(gomock.Any(), "missing:latest", nil, cfg.ImagePullTimeout).
+			Return(dockerapi.DockerContainerMetadata{Error: pullErr}),
+	)
+
+	before := time.Now()
+	prewarmer.Prewarm([]string{"busybox:latest", "missing:latest"})
+	require.Eventually(t, func() bool {
+		for _, status := range prewarmer.Statuses() {
+			if status.Status == ImagePrewarmPending {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+
+	statuses := prewarmer.Statuses()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "busybox:latest", statuses[0].Image)
+	assert.Equal(t, ImagePrewarmPulled, statuses[0].Status)
+	assert.Empty(t, statuses[0].Error)
+	assert.False(t, statuses[0].ProtectedUntil.Before(before.Add(time.Hour)))
+	assert.Equal(t, "missing:latest", statuses[1].Image)
+	assert.Equal(t, ImagePrewarmFailed, statuses[1].Status)
+	assert.Equal(t, pullErr.Error(), statuses[1].Error)
+	assert.True(t, statuses[1].ProtectedUntil.IsZero())
+}
@@ -0,0 +1,281 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package imagesignature verifies the signatures of the container images against the trust policy of the host
+// before the containers are created.
+package imagesignature
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	referenceutil "github.com/aws/amazon-ecs-agent/agent/utils/reference"
+	apierrors "github.com/aws/amazon-ecs-agent/ecs-agent/api/errors"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/async"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/utils/execwrapper"
+
+	"github.com/distribution/reference"
+)
+
+const (
+	// VerifierCosign verifies the cosign signatures of the images with the public keys of the policy
+	VerifierCosign = "cosign"
+	// VerifierNotation verifies the notation signatures of the images with the trust policy of notation
+	VerifierNotation = "notation"
+	// VerifierSkip accepts the images without verifying their signatures
+	VerifierSkip = "skip"
+	// VerifierReject rejects the images
+	VerifierReject = "reject"
+
+	verificationTimeout = 2 * time.Minute
+	// The successful verifications are cached so that the containers of a task sharing the same image, and the
+	// tasks started again on the host, don't verify it every time
+	verificationCacheSize = 500
+	verificationCacheTTL  = time.Hour
+)
+
+// Verifier verifies the signatures of the images
+type Verifier interface {
+	// Verify verifies the signature of the image pulled with the digest, and returns an
+	// ImageSignatureVerificationError when the image can't be trusted
+	Verify(ctx context.Context, image string, imageDigest string) apierrors.NamedError
+}
+
+// TrustPolicy is the trust policy file of the host. The first policy with a repository pattern matching an image is
+// applied to the image, and the images matching no policy are rejected.
+type TrustPolicy struct {
+	Policies []RepositoryPolicy `json:"policies"`
+}
+
+// RepositoryPolicy is the verification of the signatures of the images of some repositories
+type RepositoryPolicy struct {
+	// Repositories are the fully qualified repository names of the images, or prefixes of them followed by "/*",
+	// e.g. "docker.io/library/nginx" or "123456789012.dkr.ecr.us-west-2.amazonaws.com/*". "*" matches every image.
+	Repositories []string `json:"repositories"`
+	// Verifier is either "cosign", "notation", "skip" or "reject"
+	Verifier string `json:"verifier"`
+	// PublicKeys are the paths of the public keys verifying the cosign signatures. The image is trusted when its
+	// signature is verified by one of them.
+	PublicKeys []string `json:"publicKeys,omitempty"`
+}
+
+// ImageSignatureVerificationError is the error of a container whose image can't be trusted
+type ImageSignatureVerificationError struct {
+	FromError error
+}
+
+func (err ImageSignatureVerificationError) Error() string {
+	return err.FromError.Error()
+}
+
+// ErrorName returns the name of the error
+func (err ImageSignatureVerificationError) ErrorName() string {
+	return "ImageSignatureVerificationError"
+}
+
+type verifier struct {
+	policy  *TrustPolicy
+	loadErr error
+	cache   async.Cache
+	exec    execwrapper.Exec
+}
+
+// NewVerifier returns a Verifier applying the trust policy file. When the file can't be loaded, every image is
+// rejected.
+func NewVerifier(policyFile string) Verifier {
+	return newVerifier(policyFile, execwrapper.NewExec(), async.NewLRUCache(verificationCacheSize, verificationCacheTTL))
+}
+
+func newVerifier(policyFile string, exec execwrapper.Exec, cache async.Cache) *verifier {
+	policy, err := loadTrustPolicy(policyFile)
+	if err != nil {
+		logger.Error("Unable to load the image trust policy, all images will be rejected", logger.Fields{
+			"policyFile": policyFile,
+			field.Error:  err,
+		})
+	}
+	return &verifier{
+		policy:  policy,
+		loadErr: err,
+		cache:   cache,
+		exec:    exec,
+	}
+}
+
+func loadTrustPolicy(policyFile string) (*TrustPolicy, error) {
+	data, err := os.ReadFile(policyFile)
+	if err != nil {
+		return nil, err
+	}
+	var policy TrustPolicy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("invalid image trust policy %s: %w", policyFile, err)
+	}
+	for i, repositoryPolicy := range policy.Policies {
+		if len(repositoryPolicy.Repositories) == 0 {
+			return nil, fmt.Errorf("invalid image trust policy %s: policy %d has no repositories", policyFile, i)
+		}
+		switch repositoryPolicy.Verifier {
+		case VerifierCosign:
+			if len(repositoryPolicy.PublicKeys) == 0 {
+				return nil, fmt.Errorf("invalid image trust policy %s: cosign policy %d has no public keys",
+					policyFile, i)
+			}
+		case VerifierNotation, VerifierSkip, VerifierReject:
+		default:
+			return nil, fmt.Errorf("invalid image trust policy %s: unknown verifier %q of policy %d",
+				policyFile, repositoryPolicy.Verifier, i)
+		}
+	}
+	return &policy, nil
+}
+
+// CheckVerifiers checks that the binaries of the verifiers used by the trust policy file are in the PATH of the
+// agent, so that the agent fails to start instead of rejecting the images of every container when one is missing.
+// The trust policy file itself isn't checked, the images are rejected when it can't be loaded.
+func CheckVerifiers(policyFile string) error {
+	policy, err := loadTrustPolicy(policyFile)
+	if err != nil {
+		return nil
+	}
+	for _, repositoryPolicy := range policy.Policies {
+		switch repositoryPolicy.Verifier {
+		case VerifierCosign, VerifierNotation:
+			if _, err := exec.LookPath(repositoryPolicy.Verifier); err != nil {
+				return fmt.Errorf("verifier %s of the image trust policy %s not found: %w",
+					repositoryPolicy.Verifier, policyFile, err)
+			}
+		}
+	}
+	return nil
+}
+
+// policyFor returns the first policy whose repositories match the repository name
+func (policy *TrustPolicy) policyFor(name string) (RepositoryPolicy, bool) {
+	for _, repositoryPolicy := range policy.Policies {
+		for _, pattern := range repositoryPolicy.Repositories {
+			if pattern == "*" || pattern == name ||
+				(strings.HasSuffix(pattern, "/*") && strings.HasPrefix(name, strings.TrimSuffix(pattern, "*"))) {
+				return repositoryPolicy, true
+			}
+		}
+	}
+	return RepositoryPolicy{}, false
+}
+
+// Verify verifies the signature of the image against the policy of its repository
+func (v *verifier) Verify(ctx context.Context, image string, imageDigest string) apierrors.NamedError {
+	if v.loadErr != nil {
+		return ImageSignatureVerificationError{
+			FromError: fmt.Errorf("image %s rejected, unable to load the image trust policy: %w", image, v.loadErr),
+		}
+	}
+
+	namedRef, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return ImageSignatureVerificationError{
+			FromError: fmt.Errorf("image %s rejected, invalid image reference: %w", image, err),
+		}
+	}
+	repositoryPolicy, ok := v.policy.policyFor(namedRef.Name())
+	if !ok {
+		return ImageSignatureVerificationError{
+			FromError: fmt.Errorf("image %s rejected, no image trust policy matches its repository %s",
+				image, namedRef.Name()),
+		}
+	}
+
+	switch repositoryPolicy.Verifier {
+	case VerifierSkip:
+		return nil
+	case VerifierReject:
+		return ImageSignatureVerificationError{
+			FromError: fmt.Errorf("image %s rejected by the image trust policy", image),
+		}
+	}
+
+	// The signature is verified for the digest of the pulled image, so that the verified image is the one the
+	// container is created from
+	if imageDigest == "" {
+		return ImageSignatureVerificationError{
+			FromError: fmt.Errorf("image %s rejected, the digest of the pulled image is unknown", image),
+		}
+	}
+	canonicalRef, err := referenceutil.GetCanonicalRef(namedRef.String(), imageDigest)
+	if err != nil {
+		return ImageSignatureVerificationError{
+			FromError: fmt.Errorf("image %s rejected: %w", image, err),
+		}
+	}
+	imageRef := canonicalRef.String()
+
+	cacheKey := repositoryPolicy.Verifier + "|" + strings.Join(repositoryPolicy.PublicKeys, ",") + "|" + imageRef
+	if _, ok := v.cache.Get(cacheKey); ok {
+		return nil
+	}
+
+	switch repositoryPolicy.Verifier {
+	case VerifierCosign:
+		err = v.verifyCosign(ctx, imageRef, repositoryPolicy.PublicKeys)
+	case VerifierNotation:
+		err = v.run(ctx, VerifierNotation, "verify", imageRef)
+	}
+	if err != nil {
+		return ImageSignatureVerificationError{
+			FromError: fmt.Errorf("signature verification of image %s failed: %w", imageRef, err),
+		}
+	}
+
+	logger.Info("Verified image signature", logger.Fields{
+		field.Image:    image,
+		field.ImageRef: imageRef,
+		"verifier":     repositoryPolicy.Verifier,
+	})
+	v.cache.Set(cacheKey, true)
+	return nil
+}
+
+// verifyCosign verifies the cosign signature of the image with each public key until one verifies it
+func (v *verifier) verifyCosign(ctx context.Context, imageRef string, publicKeys []string) error {
+	var errs []string
+	for _, publicKey := range publicKeys {
+		err := v.run(ctx, VerifierCosign, "verify", "--key", publicKey, imageRef)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Sprintf("key %s: %v", publicKey, err))
+	}
+	return fmt.Errorf("no public key verified the signature: %s", strings.Join(errs, "; "))
+}
+
+// run runs the verification command, whose output describes why the verification failed
+func (v *verifier) run(ctx context.Context, name string, args ...string) error {
+	ctx, cancel := v.exec.NewExecContextWithTimeout(ctx, verificationTimeout)
+	defer cancel()
+
+	var output bytes.Buffer
+	cmd := v.exec.CommandContext(ctx, name, args...)
+	cmd.SetIOStreams(nil, &output, &output)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(output.String()))
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+//go:build linux && unit
+// +build linux,unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package imagesignature
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckVerifiers(t *testing.T) {
+	binDir := t.TempDir()
+	t.Setenv("PATH", binDir)
+	policyFile := writeTrustPolicy(t, testTrustPolicy)
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "cosign"), []byte("#!/bin/sh\n"), 0755))
+	assert.ErrorContains(t, CheckVerifiers(policyFile), "verifier notation")
+
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "notation"), []byte("#!/bin/sh\n"), 0755))
+	assert.NoError(t, CheckVerifiers(policyFile))
+
+	// The policies which don't run a verifier don't need any binary
+	t.Setenv("PATH", t.TempDir())
+	assert.NoError(t, CheckVerifiers(writeTrustPolicy(t, `{"policies": [{"repositories": ["*"], "verifier": "skip"}]}`)))
+	// The images are rejected when the trust policy is invalid, instead of failing the agent
+	assert.NoError(t, CheckVerifiers(writeTrustPolicy(t, `{"policies": [{"verifier": "cosign"}]}`)))
+}
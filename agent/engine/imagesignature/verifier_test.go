@@ -0,0 +1,166 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package imagesignature
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ecs-agent/ecs-agent/async"
+	mock_execwrapper "github.com/aws/amazon-ecs-agent/ecs-agent/utils/execwrapper/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testDigest      = "sha256:c3839dd800b9eb7603340509769c43e146a74c63dca3045a8e7dc8ee07e53966"
+	testECRImage    = "123456789012.dkr.ecr.us-west-2.amazonaws.com/app:v1"
+	testECRRef      = "123456789012.dkr.ecr.us-west-2.amazonaws.com/app@" + testDigest
+	testTrustPolicy = `{
+	"policies": [
+		{"repositories": ["docker.io/library/*"], "verifier": "skip"},
+		{"repositories": ["123456789012.dkr.ecr.us-west-2.amazonaws.com/*"], "verifier": "cosign",
+			"publicKeys": ["/etc/ecs/old.pub", "/etc/ecs/new.pub"]},
+		{"repositories": ["ghcr.io/org/app"], "verifier": "notation"},
+		{"repositories": ["ghcr.io/*"], "verifier": "reject"}
+	]
+}`
+)
+
+func writeTrustPolicy(t *testing.T, policy string) string {
+	policyFile := filepath.Join(t.TempDir(), "trust-policy.json")
+	require.NoError(t, os.WriteFile(policyFile, []byte(policy), 0600))
+	return policyFile
+}
+
+// expectCommand expects the verification command to be run, and makes it fail with the output when runErr is set
+func expectCommand(ctrl *gomock.Controller, exec *mock_execwrapper.MockExec, runErr error, output string,
+	name string, args ...string) {
+	cmd := mock_execwrapper.NewMockCmd(ctrl)
+	var stdout io.Writer
+	var commandArgs []interface{}
+	for _, arg := range args {
+		commandArgs = append(commandArgs, arg)
+	}
+	gomock.InOrder(
+		exec.EXPECT().NewExecContextWithTimeout(gomock.Any(), verificationTimeout).
+			Return(context.WithTimeout(context.Background(), time.Minute)),
+		exec.EXPECT().CommandContext(gomock.Any(), name, commandArgs...).Return(cmd),
+		cmd.EXPECT().SetIOStreams(nil, gomock.Any(), gomock.Any()).Do(func(_ io.Reader, out io.Writer, _ io.Writer) {
+			stdout = out
+		}),
+		cmd.EXPECT().Run().DoAndReturn(func() error {
+			stdout.Write([]byte(output))
+			return runErr
+		}),
+	)
+}
+
+func newTestVerifier(t *testing.T, policy string) (*verifier, *mock_execwrapper.MockExec, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+	exec := mock_execwrapper.NewMockExec(ctrl)
+	return newVerifier(writeTrustPolicy(t, policy), exec, async.NewLRUCache(verificationCacheSize,
+		verificationCacheTTL)), exec, ctrl
+}
+
+func TestVerifySkipAndReject(t *testing.T) {
+	v, _, ctrl := newTestVerifier(t, testTrustPolicy)
+	defer ctrl.Finish()
+
+	assert.NoError(t, v.Verify(context.TODO(), "nginx:latest", ""))
+
+	err := v.Verify(context.TODO(), "ghcr.io/org/other:latest", testDigest)
+	require.Error(t, err)
+	assert.Equal(t, "ImageSignatureVerificationError", err.(ImageSignatureVerificationError).ErrorName())
+
+	// Images matching no policy are rejected
+	err = v.Verify(context.TODO(), "quay.io/org/app:latest", testDigest)
+	assert.ErrorContains(t, err, "no image trust policy matches")
+}
+
+func TestVerifyCosign(t *testing.T) {
+	v, exec, ctrl := newTestVerifier(t, testTrustPolicy)
+	defer ctrl.Finish()
+
+	// The image is trusted when one of the keys verifies its signature
+	expectCommand(ctrl, exec, errors.New("exit status 1"), "no matching signatures",
+		"cosign", "verify", "--key", "/etc/ecs/old.pub", testECRRef)
+	expectCommand(ctrl, exec, nil, "", "cosign", "verify", "--key", "/etc/ecs/new.pub", testECRRef)
+	assert.NoError(t, v.Verify(context.TODO(), testECRImage, testDigest))
+
+	// The verification is cached
+	assert.NoError(t, v.Verify(context.TODO(), testECRImage, testDigest))
+}
+
+func TestVerifyCosignFailure(t *testing.T) {
+	v, exec, ctrl := newTestVerifier(t, testTrustPolicy)
+	defer ctrl.Finish()
+
+	expectCommand(ctrl, exec, errors.New("exit status 1"), "no matching signatures",
+		"cosign", "verify", "--key", "/etc/ecs/old.pub", testECRRef)
+	expectCommand(ctrl, exec, errors.New("exit status 1"), "no matching signatures",
+		"cosign", "verify", "--key", "/etc/ecs/new.pub", testECRRef)
+	err := v.Verify(context.TODO(), testECRImage, testDigest)
+	require.Error(t, err)
+	assert.IsType(t, ImageSignatureVerificationError{}, err)
+	assert.ErrorContains(t, err, "no matching signatures")
+}
+
+func TestVerifyNotation(t *testing.T) {
+	v, exec, ctrl := newTestVerifier(t, testTrustPolicy)
+	defer ctrl.Finish()
+
+	expectCommand(ctrl, exec, nil, "", "notation", "verify", "ghcr.io/org/app@"+testDigest)
+	assert.NoError(t, v.Verify(context.TODO(), "ghcr.io/org/app:v2", testDigest))
+}
+
+func TestVerifyWithoutDigest(t *testing.T) {
+	v, _, ctrl := newTestVerifier(t, testTrustPolicy)
+	defer ctrl.Finish()
+
+	err := v.Verify(context.TODO(), testECRImage, "")
+	assert.ErrorContains(t, err, "digest of the pulled image is unknown")
+}
+
+func TestInvalidTrustPolicy(t *testing.T) {
+	testCases := []struct {
+		name   string
+		policy string
+	}{
+		{name: "invalid json", policy: `policies`},
+		{name: "no repositories", policy: `{"policies": [{"verifier": "skip"}]}`},
+		{name: "cosign without keys", policy: `{"policies": [{"repositories": ["*"], "verifier": "cosign"}]}`},
+		{name: "unknown verifier", policy: `{"policies": [{"repositories": ["*"], "verifier": "gpg"}]}`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, _, ctrl := newTestVerifier(t, tc.policy)
+			defer ctrl.Finish()
+
+			// Every image is rejected
+			err := v.Verify(context.TODO(), "nginx:latest", testDigest)
+			assert.ErrorContains(t, err, "unable to load the image trust policy")
+		})
+	}
+}
@@ -38,6 +38,9 @@ type TaskEngine interface {
 	StateChangeEvents() chan statechange.Event
 	// SetDataClient sets the data client that is used by the task engine.
 	SetDataClient(data.Client)
+	// SetTaskFaultCleaner sets the function that is used by the task engine to clean up
+	// the faults injected into a task once it has stopped.
+	SetTaskFaultCleaner(func(taskARN string))
 
 	// AddTask adds a new task to the task engine and manages its container's
 	// lifecycle. If it returns an error, the task was not added.
@@ -21,9 +21,11 @@ package mock_engine
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	container "github.com/aws/amazon-ecs-agent/agent/api/container"
 	task "github.com/aws/amazon-ecs-agent/agent/api/task"
+	config "github.com/aws/amazon-ecs-agent/agent/config"
 	data "github.com/aws/amazon-ecs-agent/agent/data"
 	daemonmanager "github.com/aws/amazon-ecs-agent/agent/engine/daemonmanager"
 	image "github.com/aws/amazon-ecs-agent/agent/engine/image"
@@ -229,6 +231,18 @@ func (mr *MockTaskEngineMockRecorder) SetDataClient(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDataClient", reflect.TypeOf((*MockTaskEngine)(nil).SetDataClient), arg0)
 }
 
+// SetTaskFaultCleaner mocks base method.
+func (m *MockTaskEngine) SetTaskFaultCleaner(arg0 func(string)) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTaskFaultCleaner", arg0)
+}
+
+// SetTaskFaultCleaner indicates an expected call of SetTaskFaultCleaner.
+func (mr *MockTaskEngineMockRecorder) SetTaskFaultCleaner(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTaskFaultCleaner", reflect.TypeOf((*MockTaskEngine)(nil).SetTaskFaultCleaner), arg0)
+}
+
 // StateChangeEvents mocks base method.
 func (m *MockTaskEngine) StateChangeEvents() chan statechange.Event {
 	m.ctrl.T.Helper()
@@ -360,6 +374,20 @@ func (mr *MockImageManagerMockRecorder) RecordContainerReference(arg0 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordContainerReference", reflect.TypeOf((*MockImageManager)(nil).RecordContainerReference), arg0)
 }
 
+// RecordPrewarmedImage mocks base method.
+func (m *MockImageManager) RecordPrewarmedImage(arg0 string, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordPrewarmedImage", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordPrewarmedImage indicates an expected call of RecordPrewarmedImage.
+func (mr *MockImageManagerMockRecorder) RecordPrewarmedImage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordPrewarmedImage", reflect.TypeOf((*MockImageManager)(nil).RecordPrewarmedImage), arg0, arg1)
+}
+
 // RemoveContainerReferenceFromImageState mocks base method.
 func (m *MockImageManager) RemoveContainerReferenceFromImageState(arg0 *container.Container) error {
 	m.ctrl.T.Helper()
@@ -397,3 +425,15 @@ func (mr *MockImageManagerMockRecorder) StartImageCleanupProcess(arg0 interface{
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartImageCleanupProcess", reflect.TypeOf((*MockImageManager)(nil).StartImageCleanupProcess), arg0)
 }
+
+// UpdateCleanupConfig mocks base method.
+func (m *MockImageManager) UpdateCleanupConfig(arg0 *config.Config) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateCleanupConfig", arg0)
+}
+
+// UpdateCleanupConfig indicates an expected call of UpdateCleanupConfig.
+func (mr *MockImageManagerMockRecorder) UpdateCleanupConfig(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCleanupConfig", reflect.TypeOf((*MockImageManager)(nil).UpdateCleanupConfig), arg0)
+}
@@ -0,0 +1,149 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package engine
+
+import (
+	"sync"
+	"time"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+)
+
+const (
+	// maxTaskEvents is the maximum number of events kept in the history of each task. The oldest
+	// events are discarded first.
+	maxTaskEvents = 200
+
+	// TaskEventTypeTransition is the type of the events recorded when the task or a container
+	// transitions to a new known status
+	TaskEventTypeTransition = "Transition"
+	// TaskEventTypeDesiredStatus is the type of the events recorded when the desired status of the
+	// task changes
+	TaskEventTypeDesiredStatus = "DesiredStatus"
+	// TaskEventTypeHealth is the type of the events recorded when the health status of a
+	// container changes
+	TaskEventTypeHealth = "Health"
+	// TaskEventTypeError is the type of the events recorded when a container transition fails
+	TaskEventTypeError = "Error"
+	// TaskEventTypeBlocked is the type of the events recorded when a container can't transition
+	// because of its dependencies
+	TaskEventTypeBlocked = "Blocked"
+)
+
+// TaskEvent is an event in the history of a task
+type TaskEvent struct {
+	// Time is when the event was recorded
+	Time time.Time
+	// Type is the type of the event
+	Type string
+	// Container is the name of the container the event is about, empty for the task events
+	Container string
+	// Status is the status the task or the container transitioned to
+	Status string
+	// Message describes the error or the blocking condition
+	Message string
+}
+
+// taskEventHistory is a bounded history of the events of a task
+type taskEventHistory struct {
+	events []TaskEvent
+	// next is the index where the next event is recorded once the history is full
+	next int
+	// lastTaskStatus is the last task status recorded, used to skip redundant task transitions
+	lastTaskStatus string
+	// blockedBy is the last blocking condition recorded for each container, used to record each
+	// blocking condition only once
+	blockedBy map[string]string
+	lock      sync.RWMutex
+}
+
+func newTaskEventHistory() *taskEventHistory {
+	return &taskEventHistory{
+		blockedBy: make(map[string]string),
+	}
+}
+
+func (h *taskEventHistory) recordUnsafe(event TaskEvent) {
+	event.Time = time.Now()
+	if len(h.events) < maxTaskEvents {
+		h.events = append(h.events, event)
+		return
+	}
+	h.events[h.next] = event
+	h.next = (h.next + 1) % maxTaskEvents
+}
+
+// recordTaskTransition records the transition of the task to a new known status
+func (h *taskEventHistory) recordTaskTransition(status string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if status == h.lastTaskStatus {
+		return
+	}
+	h.lastTaskStatus = status
+	h.recordUnsafe(TaskEvent{Type: TaskEventTypeTransition, Status: status})
+}
+
+// recordDesiredStatus records the change of the desired status of the task
+func (h *taskEventHistory) recordDesiredStatus(status string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.recordUnsafe(TaskEvent{Type: TaskEventTypeDesiredStatus, Status: status})
+}
+
+// recordContainerTransition records the transition of a container to a new known status, along
+// with the error of the transition if any. The container is no longer blocked once it transitions.
+func (h *taskEventHistory) recordContainerTransition(container string, status string, err error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	delete(h.blockedBy, container)
+	h.recordUnsafe(TaskEvent{Type: TaskEventTypeTransition, Container: container, Status: status})
+	if err != nil {
+		h.recordUnsafe(TaskEvent{Type: TaskEventTypeError, Container: container, Status: status,
+			Message: err.Error()})
+	}
+}
+
+// recordContainerHealth records the change of the health status of a container
+func (h *taskEventHistory) recordContainerHealth(container string, status string, output string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.recordUnsafe(TaskEvent{Type: TaskEventTypeHealth, Container: container, Status: status, Message: output})
+}
+
+// recordContainerBlocked records the reason blocking the transition of a container. A reason is
+// recorded again only if it changed.
+func (h *taskEventHistory) recordContainerBlocked(container string, reason *apicontainer.BlockedReason) {
+	if reason == nil {
+		return
+	}
+	message := reason.String()
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.blockedBy[container] == message {
+		return
+	}
+	h.blockedBy[container] = message
+	h.recordUnsafe(TaskEvent{Type: TaskEventTypeBlocked, Container: container, Message: message})
+}
+
+// get returns the events from the oldest to the most recent
+func (h *taskEventHistory) get() []TaskEvent {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	events := make([]TaskEvent, 0, len(h.events))
+	events = append(events, h.events[h.next:]...)
+	events = append(events, h.events[:h.next]...)
+	return events
+}
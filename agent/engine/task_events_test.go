@@ -0,0 +1,129 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
+	"github.com/aws/amazon-ecs-agent/agent/config"
+	apicontainerstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/container/status"
+	apitaskstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/task/status"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskEventHistory(t *testing.T) {
+	history := newTaskEventHistory()
+	history.recordDesiredStatus("RUNNING")
+	history.recordTaskTransition("PULLED")
+	history.recordTaskTransition("PULLED")
+	blockedOn := &apicontainer.BlockedReason{Type: apicontainer.BlockedOnContainer, Container: "db", Condition: "HEALTHY"}
+	history.recordContainerBlocked("app", blockedOn)
+	history.recordContainerBlocked("app", blockedOn)
+	history.recordContainerBlocked("app", nil)
+	history.recordContainerHealth("db", "HEALTHY", "ok")
+	history.recordContainerTransition("app", "RUNNING", nil)
+	history.recordContainerBlocked("app", blockedOn)
+	history.recordContainerTransition("app", "STOPPED", errors.New("exited"))
+
+	events := history.get()
+	require.Len(t, events, 8)
+	expected := []TaskEvent{
+		{Type: TaskEventTypeDesiredStatus, Status: "RUNNING"},
+		{Type: TaskEventTypeTransition, Status: "PULLED"},
+		{Type: TaskEventTypeBlocked, Container: "app",
+			Message: "waiting for container db to be HEALTHY"},
+		{Type: TaskEventTypeHealth, Container: "db", Status: "HEALTHY", Message: "ok"},
+		{Type: TaskEventTypeTransition, Container: "app", Status: "RUNNING"},
+		{Type: TaskEventTypeBlocked, Container: "app",
+			Message: "waiting for container db to be HEALTHY"},
+		{Type: TaskEventTypeTransition, Container: "app", Status: "STOPPED"},
+		{Type: TaskEventTypeError, Container: "app", Status: "STOPPED", Message: "exited"},
+	}
+	for i, event := range events {
+		assert.False(t, event.Time.IsZero())
+		event.Time = expected[i].Time
+		assert.Equal(t, expected[i], event)
+	}
+}
+
+func TestTaskEventHistoryIsBounded(t *testing.T) {
+	history := newTaskEventHistory()
+	for i := 0; i < maxTaskEvents+10; i++ {
+		history.recordTaskTransition(fmt.Sprint(i))
+	}
+
+	events := history.get()
+	require.Len(t, events, maxTaskEvents)
+	assert.Equal(t, "10", events[0].Status, "oldest events should be discarded first")
+	assert.Equal(t, fmt.Sprint(maxTaskEvents+9), events[maxTaskEvents-1].Status)
+}
+
+func TestStartContainerTransitionsRecordsBlockingConditions(t *testing.T) {
+	dbContainer := &apicontainer.Container{
+		Name:                "db",
+		KnownStatusUnsafe:   apicontainerstatus.ContainerRunning,
+		DesiredStatusUnsafe: apicontainerstatus.ContainerRunning,
+		HealthCheckType:     apicontainer.DockerHealthCheckType,
+	}
+	appContainer := &apicontainer.Container{
+		Name:                "app",
+		KnownStatusUnsafe:   apicontainerstatus.ContainerCreated,
+		DesiredStatusUnsafe: apicontainerstatus.ContainerRunning,
+		DependsOnUnsafe:     []apicontainer.DependsOn{{ContainerName: "db", Condition: "HEALTHY"}},
+	}
+	taskARN := "arn:aws:ecs:us-west-2:123456789012:task/cluster/task-id"
+	mtask := &managedTask{
+		Task: &apitask.Task{
+			Arn:                 taskARN,
+			Containers:          []*apicontainer.Container{dbContainer, appContainer},
+			DesiredStatusUnsafe: apitaskstatus.TaskRunning,
+		},
+		cfg: &config.Config{},
+	}
+	engine := &DockerTaskEngine{managedTasks: map[string]*managedTask{taskARN: mtask}}
+	mtask.engine = engine
+
+	for i := 0; i < 2; i++ {
+		_, blocked, _, _ := mtask.startContainerTransitions(
+			func(cont *apicontainer.Container, nextStatus apicontainerstatus.ContainerStatus) {
+				t.Error("Transition function should not be called when the container is blocked")
+			})
+		require.Contains(t, blocked, "app")
+	}
+	blockedReason := appContainer.GetBlockedReason()
+	require.NotNil(t, blockedReason)
+	assert.Equal(t, apicontainer.BlockedOnContainer, blockedReason.Type)
+	assert.Equal(t, "db", blockedReason.Container)
+	assert.Equal(t, "HEALTHY", blockedReason.Condition)
+	assert.Nil(t, dbContainer.GetBlockedReason())
+
+	events, ok := engine.TaskEvents(taskARN)
+	require.True(t, ok)
+	require.Len(t, events, 1, "blocking condition should only be recorded once")
+	assert.Equal(t, TaskEventTypeBlocked, events[0].Type)
+	assert.Equal(t, "app", events[0].Container)
+	assert.Contains(t, events[0].Message, "waiting for container db to be HEALTHY")
+
+	_, ok = engine.TaskEvents("unknown")
+	assert.False(t, ok)
+}
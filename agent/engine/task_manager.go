@@ -43,9 +43,12 @@ import (
 	"github.com/aws/amazon-ecs-agent/ecs-agent/eventstream"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tracing"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/utils"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/utils/retry"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/utils/ttime"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -109,6 +112,14 @@ type resourceTransition struct {
 	reason error
 }
 
+// delayedContainerRestart is a restart of a container which is delayed by the restart backoff of its
+// restart policy.
+type delayedContainerRestart struct {
+	dockerContainerChange
+	// previousKnownStatus is the known status of the container before it stopped
+	previousKnownStatus apicontainerstatus.ContainerStatus
+}
+
 // managedTask is a type that is meant to manage the lifecycle of a task.
 // There should be only one managed task construct for a given task arn and the
 // managed task should be the only thing to modify the task's known or desired statuses.
@@ -145,6 +156,7 @@ type managedTask struct {
 	acsMessages                chan acsTransition
 	dockerMessages             chan dockerContainerChange
 	resourceStateChangeEvent   chan resourceStateChange
+	delayedRestarts            chan delayedContainerRestart
 	stateChangeEvents          chan statechange.Event
 	consumedHostResourceEvent  chan struct{}
 	containerChangeEventStream *eventstream.EventStream
@@ -158,9 +170,17 @@ type managedTask struct {
 	// thing managing the container.
 	unexpectedStart sync.Once
 
+	// launchSpan is the root span of the trace of the task until it reaches its steady state or
+	// stops. It's only accessed by the overseeTask goroutine.
+	launchSpan trace.Span
+
 	_time     ttime.Time
 	_timeOnce sync.Once
 
+	// _events is the history of the transitions, errors and blocking conditions of the task
+	_events     *taskEventHistory
+	_eventsOnce sync.Once
+
 	// steadyStatePollInterval is the duration that a managed task waits
 	// once the task gets into steady state before polling the state of all
 	// the task's containers to re-evaluate if the task is still in steady state
@@ -175,7 +195,7 @@ type managedTask struct {
 // This method must only be called when the engine.processTasks write lock is
 // already held.
 func (engine *DockerTaskEngine) newManagedTask(task *apitask.Task) *managedTask {
-	ctx, cancel := context.WithCancel(engine.ctx)
+	ctx, cancel := context.WithCancel(task.LogContext(engine.ctx))
 	t := &managedTask{
 		ctx:                           ctx,
 		cancel:                        cancel,
@@ -183,6 +203,7 @@ func (engine *DockerTaskEngine) newManagedTask(task *apitask.Task) *managedTask
 		acsMessages:                   make(chan acsTransition),
 		dockerMessages:                make(chan dockerContainerChange),
 		resourceStateChangeEvent:      make(chan resourceStateChange),
+		delayedRestarts:               make(chan delayedContainerRestart),
 		consumedHostResourceEvent:     make(chan struct{}, 1),
 		engine:                        engine,
 		cfg:                           engine.cfg,
@@ -206,6 +227,7 @@ func (mtask *managedTask) overseeTask() {
 	// `DesiredStatus`es which are a construct of the engine used only here,
 	// not present on the backend
 	mtask.UpdateStatus()
+	mtask.startLaunchSpan()
 
 	// Wait here until enough resources are available on host for the task to progress
 	// - Waits until host resource manager successfully 'consume's task resources and returns
@@ -217,6 +239,7 @@ func (mtask *managedTask) overseeTask() {
 
 	// If this was a 'state restore', send all unsent statuses
 	mtask.emitCurrentStatus()
+	mtask.resumeDelayedRestarts()
 
 	// Main infinite loop. This is where we receive messages and dispatch work.
 	for {
@@ -226,6 +249,7 @@ func (mtask *managedTask) overseeTask() {
 
 		// If it's steadyState, just spin until we need to do work
 		for mtask.steadyState() {
+			mtask.endLaunchSpan()
 			mtask.waitSteady()
 		}
 
@@ -236,9 +260,7 @@ func (mtask *managedTask) overseeTask() {
 		if !mtask.GetKnownStatus().Terminal() {
 			// If we aren't terminal, and we aren't steady state, we should be
 			// able to move some containers along.
-			logger.Debug("Task not steady state or terminal; progressing it", logger.Fields{
-				field.TaskID: mtask.GetID(),
-			})
+			logger.DebugCtx(mtask.ctx, "Task not steady state or terminal; progressing it")
 
 			mtask.progressTask()
 		}
@@ -249,9 +271,10 @@ func (mtask *managedTask) overseeTask() {
 	}
 	// We only break out of the above if this task is known to be stopped. Do
 	// onetime cleanup here, including removing the task after a timeout
-	logger.Info("Managed task has reached stopped; waiting for container cleanup", logger.Fields{
-		field.TaskID: mtask.GetID(),
-	})
+	mtask.endLaunchSpan()
+	logger.InfoCtx(mtask.ctx, "Managed task has reached stopped; waiting for container cleanup")
+	// Faults have to be cleaned up before the task network namespace is torn down.
+	mtask.engine.cleanupTaskFaults(mtask.Task)
 	mtask.engine.checkTearDownPauseContainer(mtask.Task)
 	// TODO [SC]: We need to also tear down pause containets in bridge mode for SC-enabled tasks
 	mtask.cleanupCredentials()
@@ -265,7 +288,7 @@ func (mtask *managedTask) overseeTask() {
 			csiclient.DefaultSocketName))
 		errors := mtask.UnstageVolumes(&csiClient)
 		for _, err := range errors {
-			logger.Error(fmt.Sprintf("Unable to unstage volumes: %v", err))
+			logger.ErrorCtx(mtask.ctx, fmt.Sprintf("Unable to unstage volumes: %v", err))
 		}
 	}
 
@@ -305,6 +328,9 @@ func (mtask *managedTask) waitForHostResources() {
 	if !mtask.IsLaunchTypeFargate() && !mtask.IsInternal && !mtask.engine.hostResourceManager.checkTaskConsumed(mtask.Arn) {
 		// Internal tasks are started right away as their resources are not accounted for
 		// Fargate (1.3.0) - rely on backend instance placement and skip resource accounting
+		_, span := tracing.StartSpan(taskContext(mtask.Task), waitForHostResourcesSpanName,
+			tracing.TaskARNKey.String(mtask.Arn))
+		mtask.setHostResourcesBlockedReason(nil)
 		mtask.engine.enqueueTask(mtask)
 		for !mtask.waitEvent(mtask.consumedHostResourceEvent) {
 			if mtask.GetDesiredStatus().Terminal() {
@@ -314,14 +340,31 @@ func (mtask *managedTask) waitForHostResources() {
 				break
 			}
 		}
+		span.End()
+		for _, container := range mtask.Containers {
+			container.SetBlockedReason(nil)
+		}
+	}
+}
+
+// setHostResourcesBlockedReason records on each container of the task that the task is waiting for
+// host resources, optionally listing the host resources which are not available
+func (mtask *managedTask) setHostResourcesBlockedReason(unavailableResources []string) {
+	reason := &apicontainer.BlockedReason{
+		Type:     apicontainer.BlockedOnHostResources,
+		Resource: strings.Join(unavailableResources, ", "),
+	}
+	for _, container := range mtask.Containers {
+		if container.SetBlockedReason(reason) {
+			mtask.events().recordContainerBlocked(container.Name, reason)
+		}
 	}
 }
 
 // waitSteady waits for a task to leave steady-state by waiting for a new
 // event, or a timeout.
 func (mtask *managedTask) waitSteady() {
-	logger.Debug("Managed task at steady state", logger.Fields{
-		field.TaskID:      mtask.GetID(),
+	logger.DebugCtx(mtask.ctx, "Managed task at steady state", logger.Fields{
 		field.KnownStatus: mtask.GetKnownStatus().String(),
 	})
 
@@ -334,9 +377,7 @@ func (mtask *managedTask) waitSteady() {
 	}
 
 	if timedOut {
-		logger.Debug("Checking to verify it's still at steady state", logger.Fields{
-			field.TaskID: mtask.GetID(),
-		})
+		logger.DebugCtx(mtask.ctx, "Checking to verify it's still at steady state")
 		go mtask.engine.checkTaskState(mtask.Task)
 	}
 }
@@ -346,9 +387,7 @@ func (mtask *managedTask) waitSteady() {
 func (mtask *managedTask) steadyState() bool {
 	select {
 	case <-mtask.ctx.Done():
-		logger.Info("Task manager exiting", logger.Fields{
-			field.TaskID: mtask.GetID(),
-		})
+		logger.InfoCtx(mtask.ctx, "Task manager exiting")
 		return false
 	default:
 		taskKnownStatus := mtask.GetKnownStatus()
@@ -370,13 +409,10 @@ func (mtask *managedTask) cleanupCredentials() {
 // channel. When the Done channel is signalled by the context, waitEvent will
 // return true.
 func (mtask *managedTask) waitEvent(stopWaiting <-chan struct{}) bool {
-	logger.Debug("Waiting for task event", logger.Fields{
-		field.TaskID: mtask.GetID(),
-	})
+	logger.DebugCtx(mtask.ctx, "Waiting for task event")
 	select {
 	case acsTransition := <-mtask.acsMessages:
-		logger.Info("Managed task got acs event", logger.Fields{
-			field.TaskID:        mtask.GetID(),
+		logger.InfoCtx(mtask.ctx, "Managed task got acs event", logger.Fields{
 			field.DesiredStatus: acsTransition.desiredStatus.String(),
 			field.Sequence:      acsTransition.seqnum,
 		})
@@ -388,6 +424,9 @@ func (mtask *managedTask) waitEvent(stopWaiting <-chan struct{}) bool {
 	case resChange := <-mtask.resourceStateChangeEvent:
 		mtask.handleResourceStateChange(resChange)
 		return false
+	case restart := <-mtask.delayedRestarts:
+		mtask.handleDelayedContainerRestart(restart)
+		return false
 	case <-stopWaiting:
 		return true
 	}
@@ -408,22 +447,24 @@ func (mtask *managedTask) handleDesiredStatusChange(desiredStatus apitaskstatus.
 	mtask.engine.monitorQueuedTasksLock.Lock()
 	defer mtask.engine.monitorQueuedTasksLock.Unlock()
 
-	logger.Info("New acs transition", logger.Fields{
-		field.TaskID:        mtask.GetID(),
+	logger.InfoCtx(mtask.ctx, "New acs transition", logger.Fields{
 		field.DesiredStatus: desiredStatus.String(),
 		field.Sequence:      seqnum,
 	})
 	if desiredStatus <= mtask.GetDesiredStatus() {
-		logger.Debug("Redundant task transition; ignoring", logger.Fields{
-			field.TaskID:        mtask.GetID(),
+		logger.DebugCtx(mtask.ctx, "Redundant task transition; ignoring", logger.Fields{
 			field.DesiredStatus: desiredStatus.String(),
 			field.Sequence:      seqnum,
 		})
 		return
 	}
 	mtask.SetDesiredStatus(desiredStatus)
+	mtask.events().recordDesiredStatus(desiredStatus.String())
 	mtask.UpdateDesiredStatus()
 	mtask.engine.saveTaskData(mtask.Task)
+	if desiredStatus.Terminal() {
+		mtask.cancelDelayedRestarts()
+	}
 }
 
 // handleContainerChange updates a container's known status. If the message
@@ -437,7 +478,6 @@ func (mtask *managedTask) handleContainerChange(containerChange dockerContainerC
 	containerKnownStatus := container.GetKnownStatus()
 
 	eventLogFields := logger.Fields{
-		field.TaskID:        mtask.GetID(),
 		field.Container:     container.Name,
 		field.RuntimeID:     runtimeID,
 		"changeEventStatus": event.Status.String(),
@@ -448,13 +488,13 @@ func (mtask *managedTask) handleContainerChange(containerChange dockerContainerC
 	if event.Status.String() == apicontainerstatus.ContainerStatusNone.String() ||
 		(event.Status == apicontainerstatus.ContainerRunning &&
 			containerKnownStatus == apicontainerstatus.ContainerResourcesProvisioned) {
-		logger.Debug("Handling container change event", eventLogFields)
+		logger.DebugCtx(mtask.ctx, "Handling container change event", eventLogFields)
 	} else if event.Status != containerKnownStatus { // This prevents noisy docker event logs for pause container
-		logger.Info("Handling container change event", eventLogFields)
+		logger.InfoCtx(mtask.ctx, "Handling container change event", eventLogFields)
 	}
 	found := mtask.isContainerFound(container)
 	if !found {
-		logger.Critical("State error; invoked with another task's container!", eventLogFields)
+		logger.CriticalCtx(mtask.ctx, "State error; invoked with another task's container!", eventLogFields)
 		return
 	}
 
@@ -462,7 +502,7 @@ func (mtask *managedTask) handleContainerChange(containerChange dockerContainerC
 	// to be known running so it will be stopped. Subsequently, ignore these backward transitions
 	mtask.handleStoppedToRunningContainerTransition(event.Status, container)
 	if event.Status <= containerKnownStatus {
-		logger.Debug("Container change is redundant", eventLogFields)
+		logger.DebugCtx(mtask.ctx, "Container change is redundant", eventLogFields)
 
 		// Only update container metadata when status stays RUNNING
 		if event.Status == containerKnownStatus && event.Status == apicontainerstatus.ContainerRunning {
@@ -478,13 +518,24 @@ func (mtask *managedTask) handleContainerChange(containerChange dockerContainerC
 	// the stop workflow and restart the container.
 	if event.Status == apicontainerstatus.ContainerStopped && container.RestartPolicyEnabled() {
 		exitCode := event.DockerContainerMetadata.ExitCode
+		container.RestartTracker.RecordExit(container.GetStartedAt())
 		shouldRestart, reason := container.RestartTracker.ShouldRestart(exitCode, container.GetStartedAt(),
 			container.GetDesiredStatus())
+		if shouldRestart && container.RestartTracker.IsRestartLimitExceeded() {
+			container.RestartTracker.SetRestartLimitReached()
+			shouldRestart, reason = false, "restart limit reached"
+		}
 		if shouldRestart {
+			if delay := container.RestartTracker.BackoffDelay(); delay > 0 {
+				mtask.delayContainerRestart(containerChange, delay, eventLogFields)
+				// return here because the container will be restarted once the backoff delay
+				// has elapsed, and we don't want to complete the rest of the "container stop" workflow
+				return
+			}
 			container.RestartTracker.RecordRestart()
 			resp := mtask.engine.startContainer(mtask.Task, container)
 			if resp.Error == nil {
-				logger.Info("Restarted container", eventLogFields,
+				logger.InfoCtx(mtask.ctx, "Restarted container", eventLogFields,
 					logger.Fields{
 						"restartCount":  container.RestartTracker.GetRestartCount(),
 						"lastRestartAt": container.RestartTracker.GetLastRestartAt().UTC().Format(time.RFC3339),
@@ -493,14 +544,14 @@ func (mtask *managedTask) handleContainerChange(containerChange dockerContainerC
 				// want to complete the rest of the "container stop" workflow
 				return
 			}
-			logger.Error("Restart container failed", eventLogFields,
+			logger.ErrorCtx(mtask.ctx, "Restart container failed", eventLogFields,
 				logger.Fields{
 					"restartCount":  container.RestartTracker.GetRestartCount(),
 					"lastRestartAt": container.RestartTracker.GetLastRestartAt().UTC().Format(time.RFC3339),
 					field.Error:     resp.Error,
 				})
 		} else {
-			logger.Info("Not Restarting container", eventLogFields,
+			logger.InfoCtx(mtask.ctx, "Not Restarting container", eventLogFields,
 				logger.Fields{
 					"restartCount":  container.RestartTracker.GetRestartCount(),
 					"lastRestartAt": container.RestartTracker.GetLastRestartAt().UTC().Format(time.RFC3339),
@@ -513,6 +564,11 @@ func (mtask *managedTask) handleContainerChange(containerChange dockerContainerC
 	currentKnownStatus := containerKnownStatus
 	container.SetKnownStatus(event.Status)
 	updateContainerMetadata(&event.DockerContainerMetadata, container, mtask.Task)
+	var eventErr error
+	if event.Error != nil {
+		eventErr = event.Error
+	}
+	mtask.events().recordContainerTransition(container.Name, event.Status.String(), eventErr)
 
 	if event.Error != nil {
 		proceedAnyway := mtask.handleEventError(containerChange, currentKnownStatus)
@@ -527,10 +583,10 @@ func (mtask *managedTask) handleContainerChange(containerChange dockerContainerC
 	}
 
 	mtask.RecordExecutionStoppedAt(container)
-	logger.Debug("Sending container change event to tcs", eventLogFields)
+	logger.DebugCtx(mtask.ctx, "Sending container change event to tcs", eventLogFields)
 	err := mtask.containerChangeEventStream.WriteToEventStream(event)
 	if err != nil {
-		logger.Warn("Failed to write container change event to tcs event stream",
+		logger.WarnCtx(mtask.ctx, "Failed to write container change event to tcs event stream",
 			eventLogFields,
 			logger.Fields{
 				field.Error: err,
@@ -556,9 +612,8 @@ func (mtask *managedTask) handleResourceStateChange(resChange resourceStateChang
 	// locate the resource
 	res := resChange.resource
 	if !mtask.isResourceFound(res) {
-		logger.Critical("State error; invoked with another task's resource",
+		logger.CriticalCtx(mtask.ctx, "State error; invoked with another task's resource",
 			logger.Fields{
-				field.TaskID:   mtask.GetID(),
 				field.Resource: res.GetName(),
 			})
 		return
@@ -574,8 +629,7 @@ func (mtask *managedTask) handleResourceStateChange(resChange resourceStateChang
 	}
 
 	if status < currentKnownStatus {
-		logger.Info("Backwards resource state change", logger.Fields{
-			field.TaskID:      mtask.GetID(),
+		logger.InfoCtx(mtask.ctx, "Backwards resource state change", logger.Fields{
 			field.Resource:    res.GetName(),
 			field.Status:      res.StatusString(status),
 			field.KnownStatus: res.StatusString(currentKnownStatus),
@@ -583,16 +637,14 @@ func (mtask *managedTask) handleResourceStateChange(resChange resourceStateChang
 		return
 	}
 	if status == currentKnownStatus {
-		logger.Debug("Redundant resource state change", logger.Fields{
-			field.TaskID:      mtask.GetID(),
+		logger.DebugCtx(mtask.ctx, "Redundant resource state change", logger.Fields{
 			field.Resource:    res.GetName(),
 			field.Status:      res.StatusString(status),
 			field.KnownStatus: res.StatusString(currentKnownStatus),
 		})
 		return
 	}
-	logger.Info("Handling resource state change", logger.Fields{
-		field.TaskID:      mtask.GetID(),
+	logger.InfoCtx(mtask.ctx, "Handling resource state change", logger.Fields{
 		field.Resource:    res.GetName(),
 		field.Status:      res.StatusString(status),
 		field.KnownStatus: res.StatusString(currentKnownStatus),
@@ -611,16 +663,13 @@ func (mtask *managedTask) handleResourceStateChange(resChange resourceStateChang
 	}
 
 	if status == res.SteadyState() { // Failed to create resource.
-		logger.Error("Failed to create task resource", logger.Fields{
-			field.TaskID:   mtask.GetID(),
+		logger.ErrorCtx(mtask.ctx, "Failed to create task resource", logger.Fields{
 			field.Resource: res.GetName(),
 			field.Error:    err,
 		})
 		res.SetKnownStatus(currentKnownStatus) // Set status back to None.
 
-		logger.Info("Marking task desired status to STOPPED", logger.Fields{
-			field.TaskID: mtask.GetID(),
-		})
+		logger.InfoCtx(mtask.ctx, "Marking task desired status to STOPPED")
 		mtask.SetDesiredStatus(apitaskstatus.TaskStopped)
 		mtask.Task.SetTerminalReason(res.GetTerminalReason())
 	}
@@ -629,9 +678,7 @@ func (mtask *managedTask) handleResourceStateChange(resChange resourceStateChang
 func (mtask *managedTask) emitResourceChange(change resourceStateChange) {
 	select {
 	case <-mtask.ctx.Done():
-		logger.Info("Unable to emit resource state change due to exit", logger.Fields{
-			field.TaskID: mtask.GetID(),
-		})
+		logger.InfoCtx(mtask.ctx, "Unable to emit resource state change due to exit")
 	case mtask.resourceStateChangeEvent <- change:
 	}
 }
@@ -658,19 +705,19 @@ func getContainerEventLogFields(c api.ContainerStateChange) logger.Fields {
 
 func (mtask *managedTask) emitTaskEvent(task *apitask.Task, reason string) {
 	taskKnownStatus := task.GetKnownStatus()
+	mtask.events().recordTaskTransition(taskKnownStatus.String())
 	// Always do (idempotent) release host resources whenever state change with
 	// known status == STOPPED is done to ensure sync between tasks and host resource manager
 	if taskKnownStatus.Terminal() {
 		resourcesToRelease := mtask.ToHostResources()
 		err := mtask.engine.hostResourceManager.release(mtask.Arn, resourcesToRelease)
 		if err != nil {
-			logger.Critical("Failed to release resources after task stopped",
+			logger.CriticalCtx(mtask.ctx, "Failed to release resources after task stopped",
 				logger.Fields{field.TaskARN: mtask.Arn})
 		}
 	}
 	if taskKnownStatus != apitaskstatus.TaskManifestPulled && !taskKnownStatus.BackendRecognized() {
-		logger.Debug("Skipping event emission for task", logger.Fields{
-			field.TaskID:      mtask.GetID(),
+		logger.DebugCtx(mtask.ctx, "Skipping event emission for task", logger.Fields{
 			field.Error:       "status not recognized by ECS",
 			field.KnownStatus: taskKnownStatus.String(),
 		})
@@ -679,35 +726,31 @@ func (mtask *managedTask) emitTaskEvent(task *apitask.Task, reason string) {
 	event, err := api.NewTaskStateChangeEvent(task, reason)
 	if err != nil {
 		if _, ok := err.(api.ErrShouldNotSendEvent); ok {
-			logger.Debug(err.Error(), logger.Fields{field.TaskID: mtask.GetID()})
+			logger.DebugCtx(mtask.ctx, err.Error())
 		} else {
-			logger.Error("Skipping emitting event for task due to error", logger.Fields{
-				field.TaskID: mtask.GetID(),
+			logger.ErrorCtx(mtask.ctx, "Skipping emitting event for task due to error", logger.Fields{
 				field.Reason: reason,
 				field.Error:  err,
 			})
 		}
 		return
 	}
-	logger.Debug("Sending task change event", logger.Fields{
-		field.TaskID:     mtask.GetID(),
+	logger.DebugCtx(mtask.ctx, "Sending task change event", logger.Fields{
 		field.Status:     event.Status.String(),
 		field.SentStatus: task.GetSentStatus().String(),
 		field.Event:      event.String(),
 	})
 	select {
 	case <-mtask.ctx.Done():
-		logger.Info("Unable to send task change event due to exit", logger.Fields{
-			field.TaskID:     mtask.GetID(),
+		logger.InfoCtx(mtask.ctx, "Unable to send task change event due to exit", logger.Fields{
 			field.Status:     event.Status.String(),
 			field.SentStatus: task.GetSentStatus().String(),
 			field.Event:      event.String(),
 		})
 	case mtask.stateChangeEvents <- event:
 	}
-	logger.Debug("Sent task change event", logger.Fields{
-		field.TaskID: mtask.GetID(),
-		field.Event:  event.String(),
+	logger.DebugCtx(mtask.ctx, "Sent task change event", logger.Fields{
+		field.Event: event.String(),
 	})
 }
 
@@ -718,28 +761,24 @@ func (mtask *managedTask) emitManagedAgentEvent(task *apitask.Task, cont *apicon
 	managedAgentName string, reason string) {
 	event, err := api.NewManagedAgentChangeEvent(task, cont, managedAgentName, reason)
 	if err != nil {
-		logger.Error("Skipping emitting ManagedAgent event for task", logger.Fields{
-			field.TaskID: mtask.GetID(),
+		logger.ErrorCtx(mtask.ctx, "Skipping emitting ManagedAgent event for task", logger.Fields{
 			field.Reason: reason,
 			field.Error:  err,
 		})
 		return
 	}
-	logger.Info("Sending ManagedAgent event", logger.Fields{
-		field.TaskID: mtask.GetID(),
-		field.Event:  event.String(),
+	logger.InfoCtx(mtask.ctx, "Sending ManagedAgent event", logger.Fields{
+		field.Event: event.String(),
 	})
 	select {
 	case <-mtask.ctx.Done():
-		logger.Info("Unable to send managed agent event due to exit", logger.Fields{
-			field.TaskID: mtask.GetID(),
-			field.Event:  event.String(),
+		logger.InfoCtx(mtask.ctx, "Unable to send managed agent event due to exit", logger.Fields{
+			field.Event: event.String(),
 		})
 	case mtask.stateChangeEvents <- event:
 	}
-	logger.Info("Sent managed agent event [%s]", logger.Fields{
-		field.TaskID: mtask.GetID(),
-		field.Event:  event.String(),
+	logger.InfoCtx(mtask.ctx, "Sent managed agent event [%s]", logger.Fields{
+		field.Event: event.String(),
 	})
 }
 
@@ -749,13 +788,11 @@ func (mtask *managedTask) emitContainerEvent(task *apitask.Task, cont *apicontai
 	event, err := api.NewContainerStateChangeEvent(task, cont, reason)
 	if err != nil {
 		if _, ok := err.(api.ErrShouldNotSendEvent); ok {
-			logger.Debug(err.Error(), logger.Fields{
-				field.TaskID:    mtask.GetID(),
+			logger.DebugCtx(mtask.ctx, err.Error(), logger.Fields{
 				field.Container: cont.Name,
 			})
 		} else {
-			logger.Error("Skipping emitting event for container due to error", logger.Fields{
-				field.TaskID:    mtask.GetID(),
+			logger.ErrorCtx(mtask.ctx, "Skipping emitting event for container due to error", logger.Fields{
 				field.Container: cont.Name,
 				field.Error:     err,
 			})
@@ -766,28 +803,150 @@ func (mtask *managedTask) emitContainerEvent(task *apitask.Task, cont *apicontai
 }
 
 func (mtask *managedTask) doEmitContainerEvent(event api.ContainerStateChange) {
-	logger.Debug("Sending container change event", getContainerEventLogFields(event), logger.Fields{
-		field.TaskID: mtask.GetID(),
-	})
+	logger.DebugCtx(mtask.ctx, "Sending container change event", getContainerEventLogFields(event))
 	select {
 	case <-mtask.ctx.Done():
-		logger.Info("Unable to send container change event due to exit", getContainerEventLogFields(event),
-			logger.Fields{
-				field.TaskID: mtask.GetID(),
-			})
+		logger.InfoCtx(mtask.ctx, "Unable to send container change event due to exit", getContainerEventLogFields(event))
 	case mtask.stateChangeEvents <- event:
 	}
-	logger.Debug("Sent container change event", getContainerEventLogFields(event), logger.Fields{
-		field.TaskID: mtask.GetID(),
-	})
+	logger.DebugCtx(mtask.ctx, "Sent container change event", getContainerEventLogFields(event))
 }
 
-func (mtask *managedTask) emitDockerContainerChange(change dockerContainerChange) {
+// delayContainerRestart applies the STOPPED status of a container whose restart is delayed by the
+// restart backoff, and schedules its restart. The stop isn't reported and doesn't change the status
+// of the task unless the container isn't restarted in the end.
+func (mtask *managedTask) delayContainerRestart(containerChange dockerContainerChange, delay time.Duration,
+	eventLogFields logger.Fields) {
+	container := containerChange.container
+	event := containerChange.event
+	restart := delayedContainerRestart{
+		dockerContainerChange: containerChange,
+		previousKnownStatus:   container.GetKnownStatus(),
+	}
+	container.RestartTracker.DelayRestart(mtask.time().Now().Add(delay))
+	container.SetKnownStatus(event.Status)
+	updateContainerMetadata(&event.DockerContainerMetadata, container, mtask.Task)
+	var eventErr error
+	if event.Error != nil {
+		eventErr = event.Error
+	}
+	mtask.events().recordContainerTransition(container.Name, event.Status.String(), eventErr)
+	logger.InfoCtx(mtask.ctx, "Delaying container restart", eventLogFields,
+		logger.Fields{
+			"restartCount": container.RestartTracker.GetRestartCount(),
+			"delay":        delay.String(),
+		})
+	go mtask.restartContainerAfter(restart, delay)
+}
+
+// resumeDelayedRestarts schedules the delayed restarts of the containers which were pending when the
+// agent stopped.
+func (mtask *managedTask) resumeDelayedRestarts() {
+	for _, container := range mtask.Containers {
+		if !container.RestartPending() {
+			continue
+		}
+		delay := container.RestartTracker.GetNextRestartAt().Sub(mtask.time().Now())
+		if delay < 0 {
+			delay = 0
+		}
+		logger.InfoCtx(mtask.ctx, "Resuming delayed container restart", logger.Fields{
+			field.Container: container.Name,
+			"delay":         delay.String(),
+		})
+		go mtask.restartContainerAfter(pendingContainerRestart(container), delay)
+	}
+}
+
+// cancelDelayedRestarts cancels the delayed restarts of the containers once the task is stopping, so that
+// they go through the rest of the "container stop" workflow right away instead of once their restart backoff
+// delay has elapsed.
+func (mtask *managedTask) cancelDelayedRestarts() {
+	for _, container := range mtask.Containers {
+		if !container.RestartPending() {
+			continue
+		}
+		logger.InfoCtx(mtask.ctx, "Cancelling delayed container restart of stopping task", logger.Fields{
+			field.Container: container.Name,
+		})
+		mtask.handleDelayedContainerRestart(pendingContainerRestart(container))
+	}
+}
+
+// pendingContainerRestart rebuilds the delayed restart of a container from its known state.
+func pendingContainerRestart(container *apicontainer.Container) delayedContainerRestart {
+	return delayedContainerRestart{
+		dockerContainerChange: dockerContainerChange{
+			container: container,
+			event: dockerapi.DockerContainerChangeEvent{
+				Status: apicontainerstatus.ContainerStopped,
+				DockerContainerMetadata: dockerapi.DockerContainerMetadata{
+					DockerID: container.GetRuntimeID(),
+					ExitCode: container.GetKnownExitCode(),
+				},
+			},
+		},
+		previousKnownStatus: apicontainerstatus.ContainerRunning,
+	}
+}
+
+// restartContainerAfter hands the delayed restart of a container over to the managed task once the
+// delay has elapsed.
+func (mtask *managedTask) restartContainerAfter(restart delayedContainerRestart, delay time.Duration) {
 	select {
 	case <-mtask.ctx.Done():
-		logger.Info("Unable to emit docker container change due to exit", logger.Fields{
-			field.TaskID: mtask.GetID(),
+		return
+	case <-mtask.time().After(delay):
+	}
+	select {
+	case <-mtask.ctx.Done():
+		logger.InfoCtx(mtask.ctx, "Unable to restart container due to exit", logger.Fields{
+			field.Container: restart.container.Name,
+		})
+	case mtask.delayedRestarts <- restart:
+	}
+}
+
+// handleDelayedContainerRestart restarts a container once its restart backoff delay has elapsed. If the
+// container isn't restarted, it goes through the rest of the "container stop" workflow.
+func (mtask *managedTask) handleDelayedContainerRestart(restart delayedContainerRestart) {
+	container := restart.container
+	if !container.RestartPending() {
+		return
+	}
+	defer mtask.engine.saveContainerData(container)
+
+	if container.GetDesiredStatus().Terminal() {
+		container.RestartTracker.CancelDelayedRestart()
+	} else {
+		container.RestartTracker.RecordRestart()
+		resp := mtask.engine.startContainer(mtask.Task, container)
+		if resp.Error == nil {
+			container.SetKnownStatus(apicontainerstatus.ContainerRunning)
+			logger.InfoCtx(mtask.ctx, "Restarted container", logger.Fields{
+				field.Container: container.Name,
+				"restartCount":  container.RestartTracker.GetRestartCount(),
+				"lastRestartAt": container.RestartTracker.GetLastRestartAt().UTC().Format(time.RFC3339),
+			})
+			return
+		}
+		logger.ErrorCtx(mtask.ctx, "Restart container failed", logger.Fields{
+			field.Container: container.Name,
+			"restartCount":  container.RestartTracker.GetRestartCount(),
+			"lastRestartAt": container.RestartTracker.GetLastRestartAt().UTC().Format(time.RFC3339),
+			field.Error:     resp.Error,
 		})
+	}
+	// Handle the stop of the container again from its previous status, so that the container
+	// goes through the rest of the "container stop" workflow unless it's restarted again.
+	container.SetKnownStatus(restart.previousKnownStatus)
+	mtask.handleContainerChange(restart.dockerContainerChange)
+}
+
+func (mtask *managedTask) emitDockerContainerChange(change dockerContainerChange) {
+	select {
+	case <-mtask.ctx.Done():
+		logger.InfoCtx(mtask.ctx, "Unable to emit docker container change due to exit")
 	case mtask.dockerMessages <- change:
 	}
 }
@@ -795,9 +954,7 @@ func (mtask *managedTask) emitDockerContainerChange(change dockerContainerChange
 func (mtask *managedTask) emitACSTransition(transition acsTransition) {
 	select {
 	case <-mtask.ctx.Done():
-		logger.Info("Unable to emit docker container change due to exit", logger.Fields{
-			field.TaskID: mtask.GetID(),
-		})
+		logger.InfoCtx(mtask.ctx, "Unable to emit docker container change due to exit")
 	case mtask.acsMessages <- transition:
 	}
 }
@@ -827,25 +984,21 @@ func (mtask *managedTask) releaseIPInIPAM() {
 	if !mtask.IsNetworkModeAWSVPC() {
 		return
 	}
-	logger.Info("IPAM releasing ip for task eni", logger.Fields{
-		field.TaskID: mtask.GetID(),
-	})
+	logger.InfoCtx(mtask.ctx, "IPAM releasing ip for task eni")
 
 	cfg, err := mtask.BuildCNIConfigAwsvpc(true, &ecscni.Config{
 		MinSupportedCNIVersion: config.DefaultMinSupportedCNIVersion,
 	})
 	if err != nil {
-		logger.Error("Failed to release ip; unable to build cni configuration", logger.Fields{
-			field.TaskID: mtask.GetID(),
-			field.Error:  err,
+		logger.ErrorCtx(mtask.ctx, "Failed to release ip; unable to build cni configuration", logger.Fields{
+			field.Error: err,
 		})
 		return
 	}
 	err = mtask.cniClient.ReleaseIPResource(mtask.ctx, cfg, ipamCleanupTmeout)
 	if err != nil {
-		logger.Error("Failed to release ip; IPAM error", logger.Fields{
-			field.TaskID: mtask.GetID(),
-			field.Error:  err,
+		logger.ErrorCtx(mtask.ctx, "Failed to release ip; IPAM error", logger.Fields{
+			field.Error: err,
 		})
 		return
 	}
@@ -875,8 +1028,7 @@ func (mtask *managedTask) handleStoppedToRunningContainerTransition(status apico
 	// because we got an error running it, and it ran anyway), the first time
 	// update it to 'known running' so that it will be driven back to stopped
 	mtask.unexpectedStart.Do(func() {
-		logger.Warn("Stopped container came back; re-stopping it once", logger.Fields{
-			field.TaskID:    mtask.GetID(),
+		logger.WarnCtx(mtask.ctx, "Stopped container came back; re-stopping it once", logger.Fields{
 			field.Container: container.Name,
 		})
 		go mtask.engine.transitionContainer(mtask.Task, container, apicontainerstatus.ContainerStopped)
@@ -891,8 +1043,7 @@ func (mtask *managedTask) handleManagedAgentStoppedTransition(container *apicont
 	switch managedAgentName {
 	case execcmd.ExecuteCommandAgentName:
 		if !container.UpdateManagedAgentStatus(managedAgentName, apicontainerstatus.ManagedAgentStopped) {
-			logger.Warn("Cannot find ManagedAgent for container", logger.Fields{
-				field.TaskID:       mtask.GetID(),
+			logger.WarnCtx(mtask.ctx, "Cannot find ManagedAgent for container", logger.Fields{
 				field.Container:    container.Name,
 				field.ManagedAgent: managedAgentName,
 			})
@@ -900,9 +1051,8 @@ func (mtask *managedTask) handleManagedAgentStoppedTransition(container *apicont
 		}
 		mtask.emitManagedAgentEvent(mtask.Task, container, managedAgentName, "Received Container Stopped event")
 	default:
-		logger.Warn("Unexpected ManagedAgent in container; unable to process ManagedAgent transition event",
+		logger.WarnCtx(mtask.ctx, "Unexpected ManagedAgent in container; unable to process ManagedAgent transition event",
 			logger.Fields{
-				field.TaskID:       mtask.GetID(),
 				field.Container:    container.Name,
 				field.ManagedAgent: managedAgentName,
 			})
@@ -928,8 +1078,7 @@ func (mtask *managedTask) handleEventError(containerChange dockerContainerChange
 		// don't want to use cached image for both cases.
 		if mtask.cfg.ImagePullBehavior == config.ImagePullAlwaysBehavior ||
 			mtask.cfg.ImagePullBehavior == config.ImagePullOnceBehavior {
-			logger.Error("Error while pulling image or its manifest; moving task to STOPPED", logger.Fields{
-				field.TaskID:    mtask.GetID(),
+			logger.ErrorCtx(mtask.ctx, "Error while pulling image or its manifest; moving task to STOPPED", logger.Fields{
 				field.Image:     container.Image,
 				field.Container: container.Name,
 				field.Error:     event.Error,
@@ -945,8 +1094,7 @@ func (mtask *managedTask) handleEventError(containerChange dockerContainerChange
 		// the task fail here, will let create container handle it instead.
 		// If the agent pull behavior is default, use local image cache directly,
 		// assuming it exists.
-		logger.Error("Error while pulling image or its manifest; will try to run anyway", logger.Fields{
-			field.TaskID:    mtask.GetID(),
+		logger.ErrorCtx(mtask.ctx, "Error while pulling image or its manifest; will try to run anyway", logger.Fields{
 			field.Image:     container.Image,
 			field.Container: container.Name,
 			field.Error:     event.Error,
@@ -961,8 +1109,7 @@ func (mtask *managedTask) handleEventError(containerChange dockerContainerChange
 		fallthrough
 	case apicontainerstatus.ContainerCreated:
 		// No need to explicitly stop containers if this is a * -> NONE/CREATED transition
-		logger.Warn("Error creating container; marking its desired status as STOPPED", logger.Fields{
-			field.TaskID:    mtask.GetID(),
+		logger.WarnCtx(mtask.ctx, "Error creating container; marking its desired status as STOPPED", logger.Fields{
 			field.Container: container.Name,
 			field.Error:     event.Error,
 		})
@@ -972,8 +1119,7 @@ func (mtask *managedTask) handleEventError(containerChange dockerContainerChange
 	default:
 		// If this is a * -> RUNNING / RESOURCES_PROVISIONED transition, we need to stop
 		// the container.
-		logger.Warn("Error starting/provisioning container[%s (Runtime ID: %s)];", logger.Fields{
-			field.TaskID:    mtask.GetID(),
+		logger.WarnCtx(mtask.ctx, "Error starting/provisioning container[%s (Runtime ID: %s)];", logger.Fields{
 			field.Container: container.Name,
 			field.RuntimeID: container.GetRuntimeID(),
 			field.Error:     event.Error,
@@ -995,8 +1141,7 @@ func (mtask *managedTask) handleEventError(containerChange dockerContainerChange
 		}
 
 		if shouldForceStop {
-			logger.Warn("Forcing container to stop", logger.Fields{
-				field.TaskID:    mtask.GetID(),
+			logger.WarnCtx(mtask.ctx, "Forcing container to stop", logger.Fields{
 				field.Container: container.Name,
 				field.RuntimeID: container.GetRuntimeID(),
 			})
@@ -1016,9 +1161,8 @@ func (mtask *managedTask) handleContainerStoppedTransitionError(event dockerapi.
 
 	pr := mtask.dockerClient.SystemPing(mtask.ctx, systemPingTimeout)
 	if pr.Error != nil {
-		logger.Info("Error stopping the container, but docker seems to be unresponsive; ignoring state change",
+		logger.InfoCtx(mtask.ctx, "Error stopping the container, but docker seems to be unresponsive; ignoring state change",
 			logger.Fields{
-				field.TaskID:      mtask.GetID(),
 				field.Container:   container.Name,
 				field.RuntimeID:   container.GetRuntimeID(),
 				"ErrorName":       event.Error.ErrorName(),
@@ -1035,8 +1179,7 @@ func (mtask *managedTask) handleContainerStoppedTransitionError(event dockerapi.
 	// enough) and get on with it
 	// This can happen in cases where the container we tried to stop
 	// was already stopped or did not exist at all.
-	logger.Warn("Error stopping the container; marking it as stopped anyway", logger.Fields{
-		field.TaskID:    mtask.GetID(),
+	logger.WarnCtx(mtask.ctx, "Error stopping the container; marking it as stopped anyway", logger.Fields{
 		field.Container: container.Name,
 		field.RuntimeID: container.GetRuntimeID(),
 		"ErrorName":     event.Error.ErrorName(),
@@ -1054,9 +1197,7 @@ func (mtask *managedTask) handleContainerStoppedTransitionError(event dockerapi.
 // docker completes.
 // Container changes may also prompt the task status to change as well.
 func (mtask *managedTask) progressTask() {
-	logger.Debug("Progressing containers and resources in task", logger.Fields{
-		field.TaskID: mtask.GetID(),
-	})
+	logger.DebugCtx(mtask.ctx, "Progressing containers and resources in task")
 	// max number of transitions length to ensure writes will never block on
 	// these and if we exit early transitions can exit the goroutine, and it'll
 	// get GC'd eventually
@@ -1126,9 +1267,7 @@ func (mtask *managedTask) progressTask() {
 	mtask.waitForTransition(transitions, transitionChange, transitionChangeEntity)
 	// update the task status
 	if mtask.UpdateStatus() {
-		logger.Info("Container or resource change also resulted in task change", logger.Fields{
-			field.TaskID: mtask.GetID(),
-		})
+		logger.InfoCtx(mtask.ctx, "Container or resource change also resulted in task change")
 
 		// If knownStatus changed, let it be known
 		var taskStateChangeReason string
@@ -1144,18 +1283,14 @@ func (mtask *managedTask) progressTask() {
 func (mtask *managedTask) isWaitingForACSExecutionCredentials(reasons []error) bool {
 	for _, reason := range reasons {
 		if reason == dependencygraph.CredentialsNotResolvedErr {
-			logger.Info("Waiting for execution role credentials to arrive from ACS", logger.Fields{
-				field.TaskID: mtask.GetID(),
-			})
+			logger.InfoCtx(mtask.ctx, "Waiting for execution role credentials to arrive from ACS")
 
 			timeoutCtx, timeoutCancel := context.WithTimeout(mtask.ctx, waitForExecutionRoleCredentialsTimeout)
 			defer timeoutCancel()
 
 			timedOut := mtask.waitEvent(timeoutCtx.Done())
 			if timedOut {
-				logger.Info("Timed out waiting for execution role credentials to arrive from ACS", logger.Fields{
-					field.TaskID: mtask.GetID(),
-				})
+				logger.InfoCtx(mtask.ctx, "Timed out waiting for execution role credentials to arrive from ACS")
 			}
 			return true
 		}
@@ -1174,6 +1309,10 @@ func (mtask *managedTask) startContainerTransitions(transitionFunc containerTran
 	for _, cont := range mtask.Containers {
 		transition := mtask.containerNextState(cont)
 		if transition.reason != nil {
+			blockedReason := dependencygraph.BlockedReason(cont, transition.blockedOn, transition.reason,
+				mtask.Containers, mtask.GetResources())
+			cont.SetBlockedReason(blockedReason)
+			mtask.events().recordContainerBlocked(cont.Name, blockedReason)
 			if transition.reason.IsTerminal() {
 				mtask.handleTerminalDependencyError(cont, transition.reason)
 			}
@@ -1184,6 +1323,7 @@ func (mtask *managedTask) startContainerTransitions(transitionFunc containerTran
 			}
 			continue
 		}
+		cont.SetBlockedReason(nil)
 
 		// If the container is already in a transition, skip
 		if transition.actionRequired && !cont.SetAppliedStatus(transition.nextState) {
@@ -1219,7 +1359,7 @@ func (mtask *managedTask) startContainerTransitions(transitionFunc containerTran
 
 func (mtask *managedTask) handleTerminalDependencyError(container *apicontainer.Container,
 	error dependencygraph.DependencyError) {
-	logger.Error("Terminal error detected during transition; marking container as stopped", logger.Fields{
+	logger.ErrorCtx(mtask.ctx, "Terminal error detected during transition; marking container as stopped", logger.Fields{
 		field.Container: container.Name,
 		field.Error:     error.Error(),
 	})
@@ -1253,9 +1393,8 @@ func (mtask *managedTask) startResourceTransitions(transitionFunc resourceTransi
 		knownStatus := res.GetKnownStatus()
 		desiredStatus := res.GetDesiredStatus()
 		if knownStatus >= desiredStatus {
-			logger.Debug("Resource has already transitioned to or beyond the desired status",
+			logger.DebugCtx(mtask.ctx, "Resource has already transitioned to or beyond the desired status",
 				logger.Fields{
-					field.TaskID:        mtask.GetID(),
 					field.Resource:      res.GetName(),
 					field.KnownStatus:   res.StatusString(knownStatus),
 					field.DesiredStatus: res.StatusString(desiredStatus),
@@ -1265,8 +1404,7 @@ func (mtask *managedTask) startResourceTransitions(transitionFunc resourceTransi
 
 		// Check if resource requires execution role credentials and whether they're available
 		if !mtask.taskExecutionRoleCredentialsResolved(res) {
-			logger.Info("Can't transition resource due to missing execution role credentials", logger.Fields{
-				field.TaskID:        mtask.GetID(),
+			logger.InfoCtx(mtask.ctx, "Can't transition resource due to missing execution role credentials", logger.Fields{
 				field.Resource:      res.GetName(),
 				field.KnownStatus:   res.StatusString(knownStatus),
 				field.DesiredStatus: res.StatusString(desiredStatus),
@@ -1342,10 +1480,14 @@ func (mtask *managedTask) applyResourceState(resource taskresource.TaskResource,
 	nextState resourcestatus.ResourceStatus) error {
 	resName := resource.GetName()
 	resStatus := resource.StatusString(nextState)
+	_, span := tracing.StartSpan(taskContext(mtask.Task), transitionResourceSpanName,
+		tracing.TaskARNKey.String(mtask.Arn),
+		tracing.ResourceNameKey.String(resName),
+		tracing.StatusKey.String(resStatus))
 	err := resource.ApplyTransition(nextState)
+	tracing.EndSpan(span, err)
 	if err != nil {
-		logger.Info("Error transitioning resource", logger.Fields{
-			field.TaskID:       mtask.GetID(),
+		logger.InfoCtx(mtask.ctx, "Error transitioning resource", logger.Fields{
 			field.Resource:     resName,
 			field.FailedStatus: resStatus,
 			field.Error:        err,
@@ -1353,8 +1495,7 @@ func (mtask *managedTask) applyResourceState(resource taskresource.TaskResource,
 
 		return err
 	}
-	logger.Info("Transitioned resource", logger.Fields{
-		field.TaskID:   mtask.GetID(),
+	logger.InfoCtx(mtask.ctx, "Transitioned resource", logger.Fields{
 		field.Resource: resName,
 		field.Status:   resStatus,
 	})
@@ -1383,8 +1524,7 @@ func (mtask *managedTask) containerNextState(container *apicontainer.Container)
 	containerDesiredStatus := container.GetDesiredStatus()
 
 	if containerKnownStatus == containerDesiredStatus {
-		logger.Debug("Container at desired status", logger.Fields{
-			field.TaskID:        mtask.GetID(),
+		logger.DebugCtx(mtask.ctx, "Container at desired status", logger.Fields{
 			field.Container:     container.Name,
 			field.RuntimeID:     container.GetRuntimeID(),
 			field.DesiredStatus: containerDesiredStatus.String(),
@@ -1397,8 +1537,7 @@ func (mtask *managedTask) containerNextState(container *apicontainer.Container)
 	}
 
 	if containerKnownStatus > containerDesiredStatus {
-		logger.Debug("Container has already transitioned beyond desired status", logger.Fields{
-			field.TaskID:        mtask.GetID(),
+		logger.DebugCtx(mtask.ctx, "Container has already transitioned beyond desired status", logger.Fields{
 			field.Container:     container.Name,
 			field.RuntimeID:     container.GetRuntimeID(),
 			field.KnownStatus:   containerKnownStatus.String(),
@@ -1412,8 +1551,7 @@ func (mtask *managedTask) containerNextState(container *apicontainer.Container)
 	}
 	if blocked, err := dependencygraph.DependenciesAreResolved(container, mtask.Containers,
 		mtask.Task.GetExecutionCredentialsID(), mtask.credentialsManager, mtask.GetResources(), mtask.cfg); err != nil {
-		logger.Info("Can't apply state to container yet due to unresolved dependencies", logger.Fields{
-			field.TaskID:    mtask.GetID(),
+		logger.InfoCtx(mtask.ctx, "Can't apply state to container yet due to unresolved dependencies", logger.Fields{
 			field.Container: container.Name,
 			field.RuntimeID: container.GetRuntimeID(),
 			field.Error:     err,
@@ -1475,8 +1613,7 @@ func (mtask *managedTask) resourceNextState(resource taskresource.TaskResource)
 	resDesiredStatus := resource.GetDesiredStatus()
 
 	if resKnownStatus >= resDesiredStatus {
-		logger.Debug("Task resource has already transitioned to or beyond desired status", logger.Fields{
-			field.TaskID:        mtask.GetID(),
+		logger.DebugCtx(mtask.ctx, "Task resource has already transitioned to or beyond desired status", logger.Fields{
 			field.Resource:      resource.GetName(),
 			field.KnownStatus:   resource.StatusString(resKnownStatus),
 			field.DesiredStatus: resource.StatusString(resDesiredStatus),
@@ -1489,9 +1626,8 @@ func (mtask *managedTask) resourceNextState(resource taskresource.TaskResource)
 		}
 	}
 	if err := dependencygraph.TaskResourceDependenciesAreResolved(resource, mtask.Containers); err != nil {
-		logger.Debug("Can't apply state to resource yet due to unresolved dependencies",
+		logger.DebugCtx(mtask.ctx, "Can't apply state to resource yet due to unresolved dependencies",
 			logger.Fields{
-				field.TaskID:   mtask.GetID(),
 				field.Resource: resource.GetName(),
 				field.Error:    err,
 			})
@@ -1524,9 +1660,7 @@ func (mtask *managedTask) handleContainersUnableToTransitionState() {
 	if mtask.GetDesiredStatus().Terminal() {
 		// Ack, really bad. We want it to stop but the containers don't think
 		// that's possible. let's just break out and hope for the best!
-		logger.Critical("The state is so bad that we're just giving up on it", logger.Fields{
-			field.TaskID: mtask.GetID(),
-		})
+		logger.CriticalCtx(mtask.ctx, "The state is so bad that we're just giving up on it")
 		mtask.SetKnownStatus(apitaskstatus.TaskStopped)
 		mtask.emitTaskEvent(mtask.Task, taskUnableToTransitionToStoppedReason)
 		// TODO we should probably panic here
@@ -1543,10 +1677,7 @@ func (mtask *managedTask) handleContainersUnableToTransitionState() {
 		}
 
 		if stopTask {
-			logger.Critical("Task in a bad state; it's not steady state but no containers want to transition",
-				logger.Fields{
-					field.TaskID: mtask.GetID(),
-				})
+			logger.CriticalCtx(mtask.ctx, "Task in a bad state; it's not steady state but no containers want to transition")
 			mtask.handleDesiredStatusChange(apitaskstatus.TaskStopped, 0)
 		}
 	}
@@ -1559,23 +1690,28 @@ func (mtask *managedTask) waitForTransition(transitions map[string]string,
 	// to ensure that there is at least one container or resource can be processed in the next
 	// progressTask call. This is done by waiting for one transition/acs/docker message.
 	if !mtask.waitEvent(transition) {
-		logger.Debug("Received non-transition events", logger.Fields{
-			field.TaskID: mtask.GetID(),
-		})
+		logger.DebugCtx(mtask.ctx, "Received non-transition events")
 		return
 	}
 	transitionedEntity := <-transitionChangeEntity
-	logger.Debug("Transition finished", logger.Fields{
-		field.TaskID:         mtask.GetID(),
+	logger.DebugCtx(mtask.ctx, "Transition finished", logger.Fields{
 		"TransitionedEntity": transitionedEntity,
 	})
 	delete(transitions, transitionedEntity)
-	logger.Debug("Task still waiting for: %v", logger.Fields{
-		field.TaskID:         mtask.GetID(),
+	logger.DebugCtx(mtask.ctx, "Task still waiting for: %v", logger.Fields{
 		"TransitionedEntity": fmt.Sprintf("%v", transitions),
 	})
 }
 
+func (mtask *managedTask) events() *taskEventHistory {
+	mtask._eventsOnce.Do(func() {
+		if mtask._events == nil {
+			mtask._events = newTaskEventHistory()
+		}
+	})
+	return mtask._events
+}
+
 func (mtask *managedTask) time() ttime.Time {
 	mtask._timeOnce.Do(func() {
 		if mtask._time == nil {
@@ -1590,9 +1726,7 @@ func (mtask *managedTask) cleanupTask(taskStoppedDuration time.Duration) {
 	cleanupTimeDuration := mtask.GetKnownStatusTime().Add(taskStoppedDuration).Sub(ttime.Now())
 	cleanupTime := make(<-chan time.Time)
 	if cleanupTimeDuration < 0 {
-		logger.Info("Cleanup Duration has been exceeded; starting cleanup now", logger.Fields{
-			field.TaskID: mtask.GetID(),
-		})
+		logger.InfoCtx(mtask.ctx, "Cleanup Duration has been exceeded; starting cleanup now")
 		cleanupTime = mtask.time().After(time.Nanosecond)
 	} else {
 		cleanupTime = mtask.time().After(cleanupTimeDuration)
@@ -1609,16 +1743,13 @@ func (mtask *managedTask) cleanupTask(taskStoppedDuration time.Duration) {
 	// wait for apitaskstatus.TaskStopped to be sent
 	ok := mtask.waitForStopReported()
 	if !ok {
-		logger.Error("Aborting cleanup for task as it is not reported as stopped", logger.Fields{
-			field.TaskID:     mtask.GetID(),
+		logger.ErrorCtx(mtask.ctx, "Aborting cleanup for task as it is not reported as stopped", logger.Fields{
 			field.SentStatus: mtask.GetSentStatus().String(),
 		})
 		return
 	}
 
-	logger.Info("Cleaning up task's containers and data", logger.Fields{
-		field.TaskID: mtask.GetID(),
-	})
+	logger.InfoCtx(mtask.ctx, "Cleaning up task's containers and data")
 
 	// For the duration of this, simply discard any task events; this ensures the
 	// speedy processing of other events for other tasks
@@ -1629,8 +1760,7 @@ func (mtask *managedTask) cleanupTask(taskStoppedDuration time.Duration) {
 
 	// Remove TaskExecutionCredentials from credentialsManager
 	if taskExecutionCredentialsID != "" {
-		logger.Info("Cleaning up task's execution credentials", logger.Fields{
-			field.TaskID:        mtask.GetID(),
+		logger.InfoCtx(mtask.ctx, "Cleaning up task's execution credentials", logger.Fields{
 			field.CredentialsID: utils.TruncateString(taskExecutionCredentialsID, utils.CredentialsIDLogTruncationLen),
 		})
 		mtask.credentialsManager.RemoveCredentials(taskExecutionCredentialsID)
@@ -1647,6 +1777,7 @@ func (mtask *managedTask) discardEvents() {
 		case <-mtask.dockerMessages:
 		case <-mtask.acsMessages:
 		case <-mtask.resourceStateChangeEvent:
+		case <-mtask.delayedRestarts:
 		case <-mtask.ctx.Done():
 			return
 		}
@@ -1666,8 +1797,7 @@ func (mtask *managedTask) waitForStopReported() bool {
 				taskStopped = true
 				break
 			}
-			logger.Warn("Blocking cleanup until the task has been reported stopped", logger.Fields{
-				field.TaskID:     mtask.GetID(),
+			logger.WarnCtx(mtask.ctx, "Blocking cleanup until the task has been reported stopped", logger.Fields{
 				field.SentStatus: sentStatus.String(),
 				"Attempt":        i + 1,
 				"MaxAttempts":    _maxStoppedWaitTimes,
@@ -1691,7 +1821,7 @@ func (mtask *managedTask) UnstageVolumes(csiClient csiclient.CSIClient) []error
 		return errors
 	}
 	if !task.IsEBSTaskAttachEnabled() {
-		logger.Debug("Task is not EBS-backed. Skip NodeUnstageVolume.")
+		logger.DebugCtx(mtask.ctx, "Task is not EBS-backed. Skip NodeUnstageVolume.")
 		return nil
 	}
 	for _, tv := range task.Volumes {
@@ -1715,9 +1845,7 @@ func (mtask *managedTask) unstageVolumeWithRetriesAndTimeout(csiClient csiclient
 	defer cancel()
 	backoff := retry.NewExponentialBackoff(unstageBackoffMin, unstageBackoffMax, unstageBackoffJitter, unstageBackoffMultiple)
 	err := retry.RetryNWithBackoff(backoff, unstageRetryAttempts, func() error {
-		logger.Debug("attempting CSI unstage with retries", logger.Fields{
-			field.TaskID: mtask.GetID(),
-		})
+		logger.DebugCtx(mtask.ctx, "attempting CSI unstage with retries")
 		return csiClient.NodeUnstageVolume(derivedCtx, volumeId, hostPath)
 	})
 	return err
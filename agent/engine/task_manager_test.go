@@ -2021,6 +2021,270 @@ func TestHandleContainerChangeStopped_WithRestartPolicy_RestartFails(t *testing.
 	assert.Equal(t, apitaskstatus.TaskStopped.String(), mTask.GetDesiredStatus().String(), "Expected task to be STOPPED since container had a restart policy, did restart, but the restart failed")
 }
 
+func TestHandleContainerChangeStopped_WithRestartPolicy_Backoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	containerChangeEventStream := eventstream.NewEventStream(t.Name(), ctx)
+	containerChangeEventStream.StartListening()
+
+	ctrl := gomock.NewController(t)
+	mockClient := mock_dockerapi.NewMockDockerClient(ctrl)
+	mockTime := mock_ttime.NewMockTime(ctrl)
+	defer ctrl.Finish()
+
+	cfg := getTestConfig()
+	hostResourceManager := NewHostResourceManager(getTestHostResources())
+	mTask := &managedTask{
+		Task:                       testdata.LoadTask("sleep5RestartPolicy"),
+		containerChangeEventStream: containerChangeEventStream,
+		stateChangeEvents:          make(chan statechange.Event),
+		delayedRestarts:            make(chan delayedContainerRestart),
+		ctx:                        context.TODO(),
+		_time:                      mockTime,
+		engine: &DockerTaskEngine{
+			ctx:                 context.TODO(),
+			cfg:                 &cfg,
+			dataClient:          data.NewNoopClient(),
+			hostResourceManager: &hostResourceManager,
+			client:              mockClient,
+		},
+	}
+	// Discard all the statechange events
+	defer discardEvents(mTask.stateChangeEvents)()
+
+	mTask.SetKnownStatus(apitaskstatus.TaskRunning)
+	mTask.SetSentStatus(apitaskstatus.TaskRunning)
+	container := mTask.Containers[0]
+	restartPolicy := *container.RestartPolicy
+	restartPolicy.BackoffInitialPeriod = 10
+	container.RestartTracker = restart.NewRestartTracker(restartPolicy)
+
+	exitCode := int(100)
+	containerChange := dockerContainerChange{
+		container: container,
+		event: dockerapi.DockerContainerChangeEvent{
+			Status: apicontainerstatus.ContainerStopped,
+			DockerContainerMetadata: dockerapi.DockerContainerMetadata{
+				ExitCode: &exitCode,
+			},
+		},
+	}
+
+	now := time.Now()
+	backoffElapsed := make(chan time.Time)
+	mockTime.EXPECT().Now().Return(now).AnyTimes()
+	mockTime.EXPECT().After(10 * time.Second).Return(backoffElapsed)
+	mockClient.EXPECT().StartContainer(gomock.Any(), container.RuntimeID, gomock.Any()).Return(dockerapi.DockerContainerMetadata{})
+
+	mTask.handleContainerChange(containerChange)
+	assert.Equal(t, 0, container.RestartTracker.GetRestartCount(), "Container should not be restarted before the backoff delay elapsed")
+	assert.Equal(t, now.Add(10*time.Second), container.RestartTracker.GetNextRestartAt())
+	assert.Equal(t, apicontainerstatus.ContainerStopped, container.GetKnownStatus(), "Container should be known stopped while its restart is delayed")
+	assert.Equal(t, exitCode, *container.GetKnownExitCode())
+	assert.True(t, container.RestartPending())
+	assert.Equal(t, apitaskstatus.TaskRunning, mTask.GetDesiredStatus(), "Task should keep running while the container restart is delayed")
+
+	backoffElapsed <- now.Add(10 * time.Second)
+	mTask.handleDelayedContainerRestart(<-mTask.delayedRestarts)
+	assert.Equal(t, 1, container.RestartTracker.GetRestartCount(), "Container should be restarted once the backoff delay elapsed")
+	assert.False(t, container.RestartPending())
+	assert.Equal(t, apicontainerstatus.ContainerRunning, container.GetKnownStatus())
+	assert.Equal(t, apitaskstatus.TaskRunning.String(), mTask.GetDesiredStatus().String(), "Expected task to be RUNNING since exited container should have restarted and task should be running")
+}
+
+func TestHandleDesiredStatusChangeStopped_WithRestartPolicy_Backoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	containerChangeEventStream := eventstream.NewEventStream(t.Name(), ctx)
+	containerChangeEventStream.StartListening()
+
+	ctrl := gomock.NewController(t)
+	mockClient := mock_dockerapi.NewMockDockerClient(ctrl)
+	mockTime := mock_ttime.NewMockTime(ctrl)
+	defer ctrl.Finish()
+
+	cfg := getTestConfig()
+	hostResourceManager := NewHostResourceManager(getTestHostResources())
+	mTask := &managedTask{
+		Task:                       testdata.LoadTask("sleep5RestartPolicy"),
+		containerChangeEventStream: containerChangeEventStream,
+		stateChangeEvents:          make(chan statechange.Event),
+		delayedRestarts:            make(chan delayedContainerRestart),
+		ctx:                        context.TODO(),
+		_time:                      mockTime,
+		engine: &DockerTaskEngine{
+			ctx:                 context.TODO(),
+			cfg:                 &cfg,
+			dataClient:          data.NewNoopClient(),
+			hostResourceManager: &hostResourceManager,
+			client:              mockClient,
+		},
+	}
+	// Discard all the statechange events
+	defer discardEvents(mTask.stateChangeEvents)()
+
+	mTask.SetKnownStatus(apitaskstatus.TaskRunning)
+	mTask.SetSentStatus(apitaskstatus.TaskRunning)
+	container := mTask.Containers[0]
+	restartPolicy := *container.RestartPolicy
+	restartPolicy.BackoffInitialPeriod = 300
+	container.RestartTracker = restart.NewRestartTracker(restartPolicy)
+
+	exitCode := int(100)
+	containerChange := dockerContainerChange{
+		container: container,
+		event: dockerapi.DockerContainerChangeEvent{
+			Status: apicontainerstatus.ContainerStopped,
+			DockerContainerMetadata: dockerapi.DockerContainerMetadata{
+				ExitCode: &exitCode,
+			},
+		},
+	}
+
+	// The backoff delay never elapses, the container must not be restarted
+	mockTime.EXPECT().Now().Return(time.Now()).AnyTimes()
+	mockTime.EXPECT().After(300 * time.Second).Return(make(chan time.Time))
+
+	mTask.handleContainerChange(containerChange)
+	assert.True(t, container.RestartPending())
+	assert.Equal(t, apitaskstatus.TaskRunning, mTask.GetKnownStatus(), "Task should keep running while the container restart is delayed")
+
+	mTask.handleDesiredStatusChange(apitaskstatus.TaskStopped, 1)
+	assert.False(t, container.RestartPending(), "Delayed restart should be cancelled once the task is stopping")
+	assert.Equal(t, 0, container.RestartTracker.GetRestartCount())
+	assert.Equal(t, apicontainerstatus.ContainerStopped, container.GetKnownStatus())
+	assert.Equal(t, apitaskstatus.TaskStopped, mTask.GetKnownStatus(), "Task should be stopped without waiting for the backoff delay")
+}
+
+func TestHandleContainerChangeStopped_WithRestartPolicy_BackoffRestartFails(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	containerChangeEventStream := eventstream.NewEventStream(t.Name(), ctx)
+	containerChangeEventStream.StartListening()
+
+	ctrl := gomock.NewController(t)
+	mockClient := mock_dockerapi.NewMockDockerClient(ctrl)
+	mockTime := mock_ttime.NewMockTime(ctrl)
+	defer ctrl.Finish()
+
+	cfg := getTestConfig()
+	hostResourceManager := NewHostResourceManager(getTestHostResources())
+	mTask := &managedTask{
+		Task:                       testdata.LoadTask("sleep5RestartPolicy"),
+		containerChangeEventStream: containerChangeEventStream,
+		stateChangeEvents:          make(chan statechange.Event),
+		delayedRestarts:            make(chan delayedContainerRestart),
+		ctx:                        context.TODO(),
+		_time:                      mockTime,
+		engine: &DockerTaskEngine{
+			ctx:                 context.TODO(),
+			cfg:                 &cfg,
+			dataClient:          data.NewNoopClient(),
+			hostResourceManager: &hostResourceManager,
+			client:              mockClient,
+		},
+	}
+	// Discard all the statechange events
+	defer discardEvents(mTask.stateChangeEvents)()
+
+	mTask.SetKnownStatus(apitaskstatus.TaskRunning)
+	mTask.SetSentStatus(apitaskstatus.TaskRunning)
+	container := mTask.Containers[0]
+	restartPolicy := *container.RestartPolicy
+	restartPolicy.BackoffInitialPeriod = 10
+	restartPolicy.RestartAttemptPeriod = 60
+	container.RestartTracker = restart.NewRestartTracker(restartPolicy)
+
+	exitCode := int(100)
+	containerChange := dockerContainerChange{
+		container: container,
+		event: dockerapi.DockerContainerChangeEvent{
+			Status: apicontainerstatus.ContainerStopped,
+			DockerContainerMetadata: dockerapi.DockerContainerMetadata{
+				ExitCode: &exitCode,
+			},
+		},
+	}
+
+	now := time.Now()
+	backoffElapsed := make(chan time.Time)
+	mockTime.EXPECT().Now().Return(now).AnyTimes()
+	mockTime.EXPECT().After(10 * time.Second).Return(backoffElapsed)
+	mockClient.EXPECT().StartContainer(gomock.Any(), container.RuntimeID, gomock.Any()).Return(dockerapi.DockerContainerMetadata{
+		Error: dockerapi.CannotStartContainerError{FromError: errors.New("cannot start container")},
+	})
+
+	mTask.handleContainerChange(containerChange)
+	assert.Equal(t, 0, container.RestartTracker.GetRestartCount(), "Container should not be restarted before the backoff delay elapsed")
+	assert.Equal(t, now.Add(10*time.Second), container.RestartTracker.GetNextRestartAt())
+	assert.Equal(t, apicontainerstatus.ContainerStopped, container.GetKnownStatus(), "Container should be known stopped while its restart is delayed")
+	assert.Equal(t, exitCode, *container.GetKnownExitCode())
+	assert.True(t, container.RestartPending())
+	assert.Equal(t, apitaskstatus.TaskRunning, mTask.GetDesiredStatus(), "Task should keep running while the container restart is delayed")
+
+	backoffElapsed <- now.Add(10 * time.Second)
+	mTask.handleDelayedContainerRestart(<-mTask.delayedRestarts)
+	// since the restart failed, expect the container to go through the stop workflow
+	waitForTaskDesiredStatus(mTask, apitaskstatus.TaskStopped)
+	assert.Equal(t, apitaskstatus.TaskStopped.String(), mTask.GetDesiredStatus().String(), "Expected task to be STOPPED since the essential container couldn't be restarted")
+	assert.Equal(t, 1, container.RestartTracker.GetRestartCount())
+	assert.False(t, container.RestartPending())
+	assert.Equal(t, apicontainerstatus.ContainerStopped, container.GetKnownStatus())
+}
+
+func TestHandleContainerChangeStopped_WithRestartPolicy_LimitReached(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	containerChangeEventStream := eventstream.NewEventStream(t.Name(), ctx)
+	containerChangeEventStream.StartListening()
+
+	cfg := getTestConfig()
+	hostResourceManager := NewHostResourceManager(getTestHostResources())
+	mTask := &managedTask{
+		Task:                       testdata.LoadTask("sleep5RestartPolicy"),
+		containerChangeEventStream: containerChangeEventStream,
+		stateChangeEvents:          make(chan statechange.Event),
+		ctx:                        context.TODO(),
+		engine: &DockerTaskEngine{
+			ctx:                 context.TODO(),
+			cfg:                 &cfg,
+			dataClient:          data.NewNoopClient(),
+			hostResourceManager: &hostResourceManager,
+		},
+	}
+	// Discard all the statechange events
+	defer discardEvents(mTask.stateChangeEvents)()
+
+	mTask.SetKnownStatus(apitaskstatus.TaskRunning)
+	mTask.SetSentStatus(apitaskstatus.TaskRunning)
+	container := mTask.Containers[0]
+	container.Essential = false
+	restartPolicy := *container.RestartPolicy
+	restartPolicy.MaxRestartCount = 1
+	container.RestartTracker = restart.NewRestartTracker(restartPolicy)
+	container.RestartTracker.RecordRestart()
+	container.RestartTracker.LastRestartAt = time.Now().Add(-time.Minute)
+
+	exitCode := int(100)
+	containerChange := dockerContainerChange{
+		container: container,
+		event: dockerapi.DockerContainerChangeEvent{
+			Status: apicontainerstatus.ContainerStopped,
+			DockerContainerMetadata: dockerapi.DockerContainerMetadata{
+				ExitCode: &exitCode,
+			},
+		},
+	}
+
+	mTask.handleContainerChange(containerChange)
+	// since the container reached its restart limit, expect task status to change to STOPPED
+	// even though the container isn't essential
+	waitForTaskDesiredStatus(mTask, apitaskstatus.TaskStopped)
+	assert.Equal(t, apitaskstatus.TaskStopped.String(), mTask.GetDesiredStatus().String(), "Expected task to change to stopped after container reached its restart limit")
+	assert.Equal(t, 1, container.RestartTracker.GetRestartCount(), "After stop event, container should NOT have been restarted")
+	assert.True(t, container.RestartTracker.IsRestartLimitReached())
+}
+
 func TestHandleContainerChangeStopped_WithRestartPolicy_DesiredStopped(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -0,0 +1,104 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"errors"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
+	apicontainerstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/container/status"
+	apitaskstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/task/status"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// launchTaskSpanName is the name of the root span of the trace of a task, which lasts until the
+	// task reaches its steady state or stops
+	launchTaskSpanName = "LaunchTask"
+	// waitForHostResourcesSpanName is the name of the span of a task waiting for host resources
+	waitForHostResourcesSpanName = "WaitForHostResources"
+	// transitionResourceSpanName is the name of the span of the transition of a task resource
+	transitionResourceSpanName = "TransitionResource"
+	// transitionContainerSpanName is the name of the span of the transitions of a container which
+	// don't have a more specific name
+	transitionContainerSpanName = "TransitionContainer"
+)
+
+// containerTransitionSpanNames are the names of the spans of the transitions of a container to
+// each known status
+var containerTransitionSpanNames = map[apicontainerstatus.ContainerStatus]string{
+	apicontainerstatus.ContainerManifestPulled:       "PullImageManifest",
+	apicontainerstatus.ContainerPulled:               "PullImage",
+	apicontainerstatus.ContainerCreated:              "CreateContainer",
+	apicontainerstatus.ContainerRunning:              "StartContainer",
+	apicontainerstatus.ContainerResourcesProvisioned: "ProvisionContainerResources",
+	apicontainerstatus.ContainerStopped:              "StopContainer",
+}
+
+// errTaskStoppedDuringLaunch is the error of the launch span of a task which stopped before reaching
+// its steady state
+var errTaskStoppedDuringLaunch = errors.New("task stopped before reaching its steady state")
+
+// taskContext returns the context of the spans of the operations on the task
+func taskContext(task *apitask.Task) context.Context {
+	return tracing.TaskContext(context.Background(), task.Arn)
+}
+
+// startContainerTransitionSpan starts the span of the transition of the container to the next status
+func startContainerTransitionSpan(task *apitask.Task, container *apicontainer.Container,
+	nextState apicontainerstatus.ContainerStatus) trace.Span {
+	name, ok := containerTransitionSpanNames[nextState]
+	if !ok {
+		name = transitionContainerSpanName
+	}
+	_, span := tracing.StartSpan(taskContext(task), name,
+		tracing.TaskARNKey.String(task.Arn),
+		tracing.ContainerNameKey.String(container.Name),
+		tracing.ContainerImageKey.String(container.Image),
+		tracing.StatusKey.String(nextState.String()))
+	return span
+}
+
+// startLaunchSpan starts the root span of the trace of the task. It's only started for the tasks
+// which didn't start to progress yet, the tasks restored from the state of a previous run of the
+// agent don't have one.
+func (mtask *managedTask) startLaunchSpan() {
+	if mtask.GetKnownStatus() != apitaskstatus.TaskStatusNone {
+		return
+	}
+	_, mtask.launchSpan = tracing.StartTaskSpan(context.Background(), mtask.Arn, launchTaskSpanName,
+		tracing.TaskIDKey.String(mtask.GetID()),
+		attribute.String("aws.ecs.task.family", mtask.Family),
+		attribute.String("aws.ecs.task.revision", mtask.Version))
+}
+
+// endLaunchSpan ends the root span of the trace of the task, if it's still recording
+func (mtask *managedTask) endLaunchSpan() {
+	if mtask.launchSpan == nil {
+		return
+	}
+	knownStatus := mtask.GetKnownStatus()
+	mtask.launchSpan.SetAttributes(tracing.StatusKey.String(knownStatus.String()))
+	var err error
+	if knownStatus.Terminal() {
+		err = errTaskStoppedDuringLaunch
+	}
+	tracing.EndSpan(mtask.launchSpan, err)
+	mtask.launchSpan = nil
+}
@@ -0,0 +1,114 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package engine
+
+import (
+	"testing"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
+	apicontainerstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/container/status"
+	apitaskstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/task/status"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tracing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const tracingTestTaskARN = "arn:aws:ecs:us-west-2:123456789012:task/cluster/c8a1b3bd0e5c4e6f9b2d3e4f5a6b7c8d"
+
+func setupTestTracerProvider() *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	return recorder
+}
+
+func TestContainerTransitionSpan(t *testing.T) {
+	recorder := setupTestTracerProvider()
+	task := &apitask.Task{Arn: tracingTestTaskARN}
+	container := &apicontainer.Container{Name: "app", Image: "nginx:latest"}
+
+	startContainerTransitionSpan(task, container, apicontainerstatus.ContainerPulled).End()
+	startContainerTransitionSpan(task, container, apicontainerstatus.ContainerZombie).End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "PullImage", spans[0].Name())
+	assert.Equal(t, transitionContainerSpanName, spans[1].Name())
+	for _, span := range spans {
+		assert.Equal(t, tracing.TaskTraceID(tracingTestTaskARN), span.SpanContext().TraceID())
+		assert.Contains(t, span.Attributes(), tracing.ContainerNameKey.String("app"))
+		assert.Contains(t, span.Attributes(), tracing.ContainerImageKey.String("nginx:latest"))
+	}
+}
+
+func TestLaunchSpan(t *testing.T) {
+	testCases := []struct {
+		name             string
+		finalStatus      apitaskstatus.TaskStatus
+		expectedStatus   codes.Code
+		expectedStatusKV string
+	}{
+		{
+			name:             "task reaches its steady state",
+			finalStatus:      apitaskstatus.TaskRunning,
+			expectedStatus:   codes.Unset,
+			expectedStatusKV: "RUNNING",
+		},
+		{
+			name:             "task stops during its launch",
+			finalStatus:      apitaskstatus.TaskStopped,
+			expectedStatus:   codes.Error,
+			expectedStatusKV: "STOPPED",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := setupTestTracerProvider()
+			mtask := &managedTask{Task: &apitask.Task{Arn: tracingTestTaskARN, Family: "web", Version: "3"}}
+
+			mtask.startLaunchSpan()
+			require.NotNil(t, mtask.launchSpan)
+			mtask.SetKnownStatus(tc.finalStatus)
+			mtask.endLaunchSpan()
+			assert.Nil(t, mtask.launchSpan)
+			// The span is only ended once
+			mtask.endLaunchSpan()
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			assert.Equal(t, launchTaskSpanName, spans[0].Name())
+			assert.Equal(t, tc.expectedStatus, spans[0].Status().Code)
+			assert.Contains(t, spans[0].Attributes(), tracing.StatusKey.String(tc.expectedStatusKV))
+		})
+	}
+}
+
+func TestLaunchSpanNotStartedForRestoredTask(t *testing.T) {
+	recorder := setupTestTracerProvider()
+	mtask := &managedTask{Task: &apitask.Task{Arn: tracingTestTaskARN}}
+	mtask.SetKnownStatus(apitaskstatus.TaskRunning)
+
+	mtask.startLaunchSpan()
+	assert.Nil(t, mtask.launchSpan)
+	mtask.endLaunchSpan()
+	assert.Empty(t, recorder.Ended())
+}
@@ -0,0 +1,343 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package eventhandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/aws/amazon-ecs-agent/agent/api"
+	"github.com/aws/amazon-ecs-agent/agent/statechange"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/utils/retry"
+)
+
+const (
+	// eventSinkMaxAttempts is the maximum number of attempts to deliver an event to the event sink
+	eventSinkMaxAttempts = 5
+	// eventSinkTimeout is the timeout of each attempt to deliver an event to the event sink
+	eventSinkTimeout = 10 * time.Second
+
+	eventSinkBackoffMin            = 500 * time.Millisecond
+	eventSinkBackoffMax            = 10 * time.Second
+	eventSinkBackoffJitterMultiple = 0.20
+	eventSinkBackoffMultiple       = 2
+
+	taskStateChangeEventType         = "TaskStateChange"
+	containerStateChangeEventType    = "ContainerStateChange"
+	managedAgentStateChangeEventType = "ManagedAgentStateChange"
+	attachmentStateChangeEventType   = "AttachmentStateChange"
+)
+
+// sinkEvent is the JSON document of a state change event delivered to the event sink
+type sinkEvent struct {
+	Type               string     `json:"type"`
+	Time               time.Time  `json:"time"`
+	TaskARN            string     `json:"taskArn,omitempty"`
+	ContainerName      string     `json:"containerName,omitempty"`
+	RuntimeID          string     `json:"runtimeId,omitempty"`
+	ManagedAgentName   string     `json:"managedAgentName,omitempty"`
+	AttachmentARN      string     `json:"attachmentArn,omitempty"`
+	AttachmentType     string     `json:"attachmentType,omitempty"`
+	Status             string     `json:"status"`
+	Reason             string     `json:"reason,omitempty"`
+	ExitCode           *int       `json:"exitCode,omitempty"`
+	ImageDigest        string     `json:"imageDigest,omitempty"`
+	PullStartedAt      *time.Time `json:"pullStartedAt,omitempty"`
+	PullStoppedAt      *time.Time `json:"pullStoppedAt,omitempty"`
+	ExecutionStoppedAt *time.Time `json:"executionStoppedAt,omitempty"`
+}
+
+// newSinkEvent converts a state change event to its sink document. The document is built when the event is
+// published so that it doesn't change with the task and container objects referenced by the event.
+func newSinkEvent(change statechange.Event) (*sinkEvent, error) {
+	now := time.Now().UTC()
+	switch event := change.(type) {
+	case api.TaskStateChange:
+		sinkEvent := &sinkEvent{
+			Type:               taskStateChangeEventType,
+			Time:               now,
+			TaskARN:            event.TaskARN,
+			Status:             event.Status.String(),
+			Reason:             event.Reason,
+			PullStartedAt:      event.PullStartedAt,
+			PullStoppedAt:      event.PullStoppedAt,
+			ExecutionStoppedAt: event.ExecutionStoppedAt,
+		}
+		if event.Attachment != nil {
+			sinkEvent.AttachmentARN = event.Attachment.GetAttachmentARN()
+		}
+		return sinkEvent, nil
+	case api.ContainerStateChange:
+		return &sinkEvent{
+			Type:          containerStateChangeEventType,
+			Time:          now,
+			TaskARN:       event.TaskArn,
+			ContainerName: event.ContainerName,
+			RuntimeID:     event.RuntimeID,
+			Status:        event.Status.String(),
+			Reason:        event.Reason,
+			ExitCode:      event.ExitCode,
+			ImageDigest:   event.ImageDigest,
+		}, nil
+	case api.ManagedAgentStateChange:
+		sinkEvent := &sinkEvent{
+			Type:             managedAgentStateChangeEventType,
+			Time:             now,
+			TaskARN:          event.TaskArn,
+			ManagedAgentName: event.Name,
+			Status:           event.Status.String(),
+			Reason:           event.Reason,
+		}
+		if event.Container != nil {
+			sinkEvent.ContainerName = event.Container.Name
+		}
+		return sinkEvent, nil
+	case api.AttachmentStateChange:
+		if event.Attachment == nil {
+			return nil, fmt.Errorf("attachment state change event has no attachment")
+		}
+		status := event.Attachment.GetAttachmentStatus()
+		return &sinkEvent{
+			Type:           attachmentStateChangeEventType,
+			Time:           now,
+			AttachmentARN:  event.Attachment.GetAttachmentARN(),
+			AttachmentType: event.Attachment.GetAttachmentType(),
+			Status:         status.String(),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported state change event type: %T", change)
+	}
+}
+
+// eventWriter delivers the JSON documents of the events to the destination of the event sink
+type eventWriter interface {
+	write(ctx context.Context, data []byte) error
+	close()
+}
+
+// httpEventWriter POSTs each event to an HTTP(S) endpoint
+type httpEventWriter struct {
+	url    string
+	client *http.Client
+}
+
+func (w *httpEventWriter) write(ctx context.Context, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status from event sink: %s", resp.Status)
+	}
+	return nil
+}
+
+func (w *httpEventWriter) close() {
+	w.client.CloseIdleConnections()
+}
+
+// unixSocketEventWriter writes the events as newline delimited JSON to a unix socket. The connection is kept
+// open between the events and established again after a failed write.
+type unixSocketEventWriter struct {
+	path string
+	conn net.Conn
+}
+
+func (w *unixSocketEventWriter) write(ctx context.Context, data []byte) error {
+	if w.conn == nil {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "unix", w.path)
+		if err != nil {
+			return err
+		}
+		w.conn = conn
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		w.conn.SetWriteDeadline(deadline)
+	}
+	if _, err := w.conn.Write(append(data, '\n')); err != nil {
+		w.close()
+		return err
+	}
+	return nil
+}
+
+func (w *unixSocketEventWriter) close() {
+	if w.conn != nil {
+		w.conn.Close()
+		w.conn = nil
+	}
+}
+
+// fileEventWriter appends the events as newline delimited JSON to a file. The file is opened for each event so
+// that it can be rotated.
+type fileEventWriter struct {
+	path string
+}
+
+func (w *fileEventWriter) write(_ context.Context, data []byte) error {
+	file, err := os.OpenFile(w.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (w *fileEventWriter) close() {}
+
+// newEventWriter returns the event writer of the endpoint of the event sink
+func newEventWriter(endpoint string) (eventWriter, error) {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid event sink endpoint %q: %w", endpoint, err)
+	}
+	switch endpointURL.Scheme {
+	case "http", "https":
+		if endpointURL.Host == "" {
+			return nil, fmt.Errorf("invalid event sink endpoint %q: missing host", endpoint)
+		}
+		return &httpEventWriter{url: endpoint, client: &http.Client{}}, nil
+	case "unix":
+		if endpointURL.Path == "" {
+			return nil, fmt.Errorf("invalid event sink endpoint %q: missing socket path", endpoint)
+		}
+		return &unixSocketEventWriter{path: endpointURL.Path}, nil
+	case "file":
+		if endpointURL.Path == "" {
+			return nil, fmt.Errorf("invalid event sink endpoint %q: missing file path", endpoint)
+		}
+		return &fileEventWriter{path: endpointURL.Path}, nil
+	default:
+		return nil, fmt.Errorf("invalid event sink endpoint %q: expected an http, https, unix or file URL", endpoint)
+	}
+}
+
+// EventSink delivers the task, container, managed agent and attachment state change events to a local
+// destination in addition to ECS, so that the agents running on the host can follow them in real time. The
+// events wait in a bounded queue and are delivered in order by a single routine, which retries each event a
+// few times before dropping it. The events received while the queue is full are dropped.
+type EventSink struct {
+	endpoint string
+	writer   eventWriter
+	queue    chan []byte
+	backoff  retry.Backoff
+	ctx      context.Context
+}
+
+// NewEventSink returns an EventSink delivering the events to the endpoint, and starts its delivery routine
+// which runs until ctx is cancelled
+func NewEventSink(ctx context.Context, endpoint string, queueSize int) (*EventSink, error) {
+	writer, err := newEventWriter(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	sink := &EventSink{
+		endpoint: endpoint,
+		writer:   writer,
+		queue:    make(chan []byte, queueSize),
+		backoff: retry.NewExponentialBackoff(eventSinkBackoffMin, eventSinkBackoffMax,
+			eventSinkBackoffJitterMultiple, eventSinkBackoffMultiple),
+		ctx: ctx,
+	}
+	go sink.deliverEvents()
+	return sink, nil
+}
+
+// Publish queues up the state change event to be delivered to the event sink. It never blocks.
+func (sink *EventSink) Publish(change statechange.Event) {
+	event, err := newSinkEvent(change)
+	if err != nil {
+		logger.Warn("Unable to convert state change event for the event sink", logger.Fields{
+			field.Error: err,
+		})
+		return
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		logger.Warn("Unable to marshal state change event for the event sink", logger.Fields{
+			"eventType": event.Type,
+			field.Error: err,
+		})
+		return
+	}
+	select {
+	case sink.queue <- data:
+	default:
+		logger.Warn("Event sink queue is full, dropping state change event", logger.Fields{
+			"eventType":   event.Type,
+			field.TaskARN: event.TaskARN,
+			"queueSize":   cap(sink.queue),
+		})
+	}
+}
+
+// deliverEvents delivers the queued events until the context of the sink is cancelled
+func (sink *EventSink) deliverEvents() {
+	defer sink.writer.close()
+	for {
+		select {
+		case <-sink.ctx.Done():
+			logger.Info("Stopping event sink delivery", logger.Fields{
+				"pendingEvents": len(sink.queue),
+			})
+			return
+		case data := <-sink.queue:
+			sink.deliver(data)
+		}
+	}
+}
+
+func (sink *EventSink) deliver(data []byte) {
+	sink.backoff.Reset()
+	err := retry.RetryNWithBackoffCtx(sink.ctx, sink.backoff, eventSinkMaxAttempts, func() error {
+		ctx, cancel := context.WithTimeout(sink.ctx, eventSinkTimeout)
+		defer cancel()
+		err := sink.writer.write(ctx, data)
+		if err != nil {
+			logger.Debug("Unable to deliver state change event to the event sink", logger.Fields{
+				"endpoint":  sink.endpoint,
+				field.Error: err,
+			})
+		}
+		return err
+	})
+	if err != nil {
+		logger.Warn("Dropping state change event which couldn't be delivered to the event sink", logger.Fields{
+			"endpoint":  sink.endpoint,
+			"attempts":  eventSinkMaxAttempts,
+			field.Error: err,
+		})
+	}
+}
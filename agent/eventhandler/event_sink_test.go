@@ -0,0 +1,255 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package eventhandler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ecs-agent/agent/api"
+	"github.com/aws/amazon-ecs-agent/agent/statechange"
+	apicontainerstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/container/status"
+	apitaskstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/task/status"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/utils/retry"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSinkEvent(t *testing.T) {
+	exitCode := 1
+	testCases := []struct {
+		name     string
+		event    statechange.Event
+		expected sinkEvent
+	}{
+		{
+			name: "task",
+			event: api.TaskStateChange{
+				TaskARN: taskARN,
+				Status:  apitaskstatus.TaskStopped,
+				Reason:  "Essential container in task exited",
+			},
+			expected: sinkEvent{
+				Type:    taskStateChangeEventType,
+				TaskARN: taskARN,
+				Status:  "STOPPED",
+				Reason:  "Essential container in task exited",
+			},
+		},
+		{
+			name: "container",
+			event: api.ContainerStateChange{
+				TaskArn:       taskARN,
+				ContainerName: "app",
+				RuntimeID:     "runtime-id",
+				Status:        apicontainerstatus.ContainerStopped,
+				ExitCode:      &exitCode,
+			},
+			expected: sinkEvent{
+				Type:          containerStateChangeEventType,
+				TaskARN:       taskARN,
+				ContainerName: "app",
+				RuntimeID:     "runtime-id",
+				Status:        "STOPPED",
+				ExitCode:      &exitCode,
+			},
+		},
+		{
+			name:  "managed agent",
+			event: managedAgentEvent(taskARN),
+			expected: sinkEvent{
+				Type:             managedAgentStateChangeEventType,
+				TaskARN:          taskARN,
+				ManagedAgentName: "ExecAgent",
+				Status:           "RUNNING",
+			},
+		},
+		{
+			name:  "attachment",
+			event: eniAttachmentEvent(attachmentARN),
+			expected: sinkEvent{
+				Type:           attachmentStateChangeEventType,
+				AttachmentARN:  attachmentARN,
+				AttachmentType: "instance-eni",
+				Status:         "NONE",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			event, err := newSinkEvent(tc.event)
+			require.NoError(t, err)
+			assert.False(t, event.Time.IsZero())
+			event.Time = time.Time{}
+			assert.Equal(t, tc.expected, *event)
+		})
+	}
+}
+
+func TestNewEventWriterInvalidEndpoint(t *testing.T) {
+	for _, endpoint := range []string{"localhost:8080", "http://", "unix://", "file://", "tcp://localhost:8080", "://"} {
+		t.Run(endpoint, func(t *testing.T) {
+			_, err := newEventWriter(endpoint)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// newTestEventSink returns an event sink with a short backoff, whose delivery routine isn't started
+func newTestEventSink(t *testing.T, endpoint string, queueSize int) *EventSink {
+	writer, err := newEventWriter(endpoint)
+	require.NoError(t, err)
+	return &EventSink{
+		endpoint: endpoint,
+		writer:   writer,
+		queue:    make(chan []byte, queueSize),
+		backoff:  retry.NewExponentialBackoff(time.Millisecond, 5*time.Millisecond, 0, 2),
+		ctx:      context.Background(),
+	}
+}
+
+func TestEventSinkHTTPDeliveryRetries(t *testing.T) {
+	var requests int32
+	received := make(chan sinkEvent, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		if atomic.AddInt32(&requests, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var event sinkEvent
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		received <- event
+	}))
+	defer server.Close()
+
+	sink := newTestEventSink(t, server.URL, 1)
+	sink.Publish(containerEvent(taskARN))
+	sink.deliver(<-sink.queue)
+
+	event := <-received
+	assert.Equal(t, containerStateChangeEventType, event.Type)
+	assert.Equal(t, "containerName", event.ContainerName)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+}
+
+func TestEventSinkHTTPDeliveryGivesUp(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	sink := newTestEventSink(t, server.URL, 1)
+	sink.Publish(containerEvent(taskARN))
+	sink.deliver(<-sink.queue)
+	assert.Equal(t, int32(eventSinkMaxAttempts), atomic.LoadInt32(&requests))
+}
+
+func TestEventSinkQueueFull(t *testing.T) {
+	sink := newTestEventSink(t, "file:///events.ndjson", 1)
+	sink.Publish(containerEvent(taskARN))
+	sink.Publish(taskEvent(taskARN))
+
+	require.Len(t, sink.queue, 1)
+	var event sinkEvent
+	require.NoError(t, json.Unmarshal(<-sink.queue, &event))
+	assert.Equal(t, containerStateChangeEventType, event.Type, "the event received when the queue is full is dropped")
+}
+
+func TestEventSinkFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sink, err := NewEventSink(ctx, "file://"+path, 10)
+	require.NoError(t, err)
+
+	sink.Publish(containerEvent(taskARN))
+	sink.Publish(taskEvent(taskARN))
+
+	var lines []string
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return false
+		}
+		lines = strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		return len(lines) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	var event sinkEvent
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &event))
+	assert.Equal(t, containerStateChangeEventType, event.Type)
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &event))
+	assert.Equal(t, taskStateChangeEventType, event.Type)
+	assert.Equal(t, taskARN, event.TaskARN)
+}
+
+func TestEventSinkUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.sock")
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	lines := make(chan string, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			lines <- line
+		}
+	}()
+
+	sink := newTestEventSink(t, "unix://"+path, 2)
+	defer sink.writer.close()
+	sink.Publish(containerEvent(taskARN))
+	sink.Publish(managedAgentEvent(taskARN))
+	sink.deliver(<-sink.queue)
+	sink.deliver(<-sink.queue)
+
+	for _, expectedType := range []string{containerStateChangeEventType, managedAgentStateChangeEventType} {
+		select {
+		case line := <-lines:
+			var event sinkEvent
+			require.NoError(t, json.Unmarshal([]byte(line), &event))
+			assert.Equal(t, expectedType, event.Type)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for the event on the unix socket")
+		}
+	}
+}
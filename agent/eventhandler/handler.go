@@ -24,9 +24,9 @@ import (
 )
 
 // HandleEngineEvents handles state change events from the state change event channel by sending it to
-// responsible event handler
+// responsible event handler. The events are also published to the event sink, when there is one.
 func HandleEngineEvents(ctx context.Context, taskEngine engine.TaskEngine, client ecs.ECSClient,
-	taskHandler *TaskHandler, attachmentEventHandler *AttachmentEventHandler) {
+	taskHandler *TaskHandler, attachmentEventHandler *AttachmentEventHandler, eventSink *EventSink) {
 
 	for {
 		stateChangeEvents := taskEngine.StateChangeEvents()
@@ -42,6 +42,9 @@ func HandleEngineEvents(ctx context.Context, taskEngine engine.TaskEngine, clien
 					seelog.Error("Unable to handle state change event. The events channel is closed")
 					break
 				}
+				if eventSink != nil {
+					eventSink.Publish(event)
+				}
 				err := handleEngineEvent(event, client, taskHandler, attachmentEventHandler)
 				if err != nil {
 					seelog.Errorf("Handler unable to add state change event %v: %v", event, err)
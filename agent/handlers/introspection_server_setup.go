@@ -22,6 +22,7 @@ import (
 	"github.com/aws/amazon-ecs-agent/agent/config"
 	"github.com/aws/amazon-ecs-agent/agent/engine"
 	v1 "github.com/aws/amazon-ecs-agent/agent/handlers/v1"
+	"github.com/aws/amazon-ecs-agent/agent/supportbundle"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/introspection"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/metrics"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/utils/retry"
@@ -29,8 +30,14 @@ import (
 	"github.com/cihub/seelog"
 )
 
-// ServeIntrospectionHTTPEndpoint serves information about this agent/containerInstance and tasks running on it.
-func ServeIntrospectionHTTPEndpoint(ctx context.Context, containerInstanceArn *string, taskEngine engine.TaskEngine, cfg *config.Config) {
+// ServeIntrospectionHTTPEndpoint serves information about this agent/containerInstance and tasks running on it,
+// including the history of the transitions of each task.
+// If the metrics factory exports Prometheus metrics, they are served on the metrics path as well. Images can be
+// pre-warmed through the endpoint if the image pre-warm API is enabled and the image pre-warmer isn't nil.
+// Support bundles are served if the support bundle API is enabled and the support bundle collector isn't nil.
+func ServeIntrospectionHTTPEndpoint(ctx context.Context, containerInstanceArn *string, taskEngine engine.TaskEngine,
+	cfg *config.Config, metricsFactory metrics.EntryFactory, faults v1.FaultsProvider,
+	imagePrewarmer *engine.ImagePrewarmer, supportBundleCollector *supportbundle.Collector) {
 	// Is this the right level to type assert, assuming we'd abstract multiple taskengines here?
 	// Revisit if we ever add another type..
 	dockerTaskEngine := taskEngine.(*engine.DockerTaskEngine)
@@ -38,15 +45,25 @@ func ServeIntrospectionHTTPEndpoint(ctx context.Context, containerInstanceArn *s
 		ContainerInstanceArn: containerInstanceArn,
 		ClusterName:          cfg.Cluster,
 		TaskEngine:           dockerTaskEngine,
+		Faults:               faults,
 	}
 
-	server, err := introspection.NewServer(
-		agentState,
-		metrics.NewNopEntryFactory(),
+	opts := []introspection.ConfigOpt{
 		introspection.WithReadTimeout(readTimeout),
 		introspection.WithWriteTimeout(writeTimeout),
 		introspection.WithRuntimeStats(cfg.EnableRuntimeStats.Enabled()),
-	)
+		introspection.WithTaskEventsProvider(&v1.TaskEventsProviderImpl{TaskEngine: dockerTaskEngine}),
+	}
+	if prometheusFactory, ok := metricsFactory.(metrics.PrometheusEntryFactory); ok {
+		opts = append(opts, introspection.WithMetricsHandler(prometheusFactory))
+	}
+	if cfg.ImagePrewarmAPIEnabled.Enabled() && imagePrewarmer != nil {
+		opts = append(opts, introspection.WithImagePrewarmer(&v1.ImagePrewarmerImpl{Prewarmer: imagePrewarmer}))
+	}
+	if cfg.SupportBundleAPIEnabled.Enabled() && supportBundleCollector != nil {
+		opts = append(opts, introspection.WithSupportBundleCollector(supportBundleCollector))
+	}
+	server, err := introspection.NewServer(agentState, metricsFactory, opts...)
 
 	if err != nil {
 		seelog.Criticalf("Failed to set up Introspection Server: %v", err)
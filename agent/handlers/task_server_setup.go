@@ -19,6 +19,8 @@ import (
 	"time"
 
 	"github.com/aws/amazon-ecs-agent/agent/config"
+	"github.com/aws/amazon-ecs-agent/agent/data"
+	"github.com/aws/amazon-ecs-agent/agent/engine"
 	"github.com/aws/amazon-ecs-agent/agent/engine/dockerstate"
 	tpfactory "github.com/aws/amazon-ecs-agent/agent/handlers/agentapi/taskprotection"
 	v2 "github.com/aws/amazon-ecs-agent/agent/handlers/v2"
@@ -26,6 +28,7 @@ import (
 	v4 "github.com/aws/amazon-ecs-agent/agent/handlers/v4"
 	"github.com/aws/amazon-ecs-agent/agent/logger/audit"
 	"github.com/aws/amazon-ecs-agent/agent/stats"
+	"github.com/aws/amazon-ecs-agent/agent/taskresource/cgroup"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/api/ecs"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/credentials"
 	auditinterface "github.com/aws/amazon-ecs-agent/ecs-agent/logger/audit"
@@ -66,13 +69,15 @@ func taskServerSetup(
 	ecsClient ecs.ECSClient,
 	cluster string,
 	statsEngine stats.Engine,
-	steadyStateRate int,
-	burstRate int,
+	rateLimiter *tmds.RateLimiter,
 	availabilityZone string,
 	availabilityZoneID string,
 	vpcID string,
 	containerInstanceArn string,
 	taskProtectionClientFactory tp.TaskProtectionClientFactoryInterface,
+	metricsFactory metrics.EntryFactory,
+	taskEngine engine.TaskEngine,
+	faultHandler *fault.FaultHandler,
 ) (*http.Server, error) {
 	muxRouter := mux.NewRouter()
 
@@ -84,7 +89,6 @@ func taskServerSetup(
 		tmdsv1.CredentialsHandler(credentialsManager, auditLogger))
 
 	tmdsAgentState := v4.NewTMDSAgentState(state, statsEngine, ecsClient, cluster, availabilityZone, availabilityZoneID, vpcID, containerInstanceArn)
-	metricsFactory := metrics.NewNopEntryFactory()
 
 	v2HandlersSetup(muxRouter, state, ecsClient, statsEngine, cluster, credentialsManager, auditLogger, availabilityZone, containerInstanceArn)
 
@@ -96,16 +100,17 @@ func taskServerSetup(
 	agentAPIV1HandlersSetup(muxRouter, state, credentialsManager, cluster, tmdsAgentState,
 		taskProtectionClientFactory, metricsFactory)
 
-	execWrapper := execwrapper.NewExec()
-	registerFaultHandlers(muxRouter, tmdsAgentState, metricsFactory, execWrapper)
+	if faultHandler != nil {
+		registerFaultHandlers(muxRouter, faultHandler, metricsFactory)
+		setupFaultCleanup(faultHandler, state, taskEngine)
+	}
 
 	return tmds.NewServer(auditLogger,
 		tmds.WithHandler(muxRouter),
 		tmds.WithListenAddress(tmds.AddressIPv4()),
 		tmds.WithReadTimeout(readTimeout),
 		tmds.WithWriteTimeout(writeTimeout),
-		tmds.WithSteadyStateRate(float64(steadyStateRate)),
-		tmds.WithBurstRate(burstRate))
+		tmds.WithRateLimiter(rateLimiter))
 }
 
 // v2HandlersSetup adds all handlers in v2 package to the mux router.
@@ -195,20 +200,81 @@ func agentAPIV1HandlersSetup(
 		Methods("GET")
 }
 
+// setupFaultCleanup makes the task engine clean up the faults injected into tasks once they stop, and
+// reconciles the faults injected before the agent restarted against the tasks known to the agent.
+func setupFaultCleanup(
+	handler *fault.FaultHandler,
+	state dockerstate.TaskEngineState,
+	taskEngine engine.TaskEngine,
+) {
+	if taskEngine != nil {
+		taskEngine.SetTaskFaultCleaner(handler.CleanupTaskFaults)
+	}
+	if state == nil {
+		return
+	}
+	go handler.ReconcileFaults(func(taskARN string) bool {
+		task, ok := state.TaskByArn(taskARN)
+		return ok && !task.GetKnownStatus().Terminal()
+	})
+}
+
+// taskCgroupResource returns the function looking up the cgroup resource of a task, which only exists if task
+// resource limits are enabled.
+func taskCgroupResource(state dockerstate.TaskEngineState) func(taskARN string) (*cgroup.CgroupResource, bool) {
+	return func(taskARN string) (*cgroup.CgroupResource, bool) {
+		if state == nil {
+			return nil, false
+		}
+		task, ok := state.TaskByArn(taskARN)
+		if !ok {
+			return nil, false
+		}
+		for _, resource := range task.GetResources() {
+			if cgroupResource, ok := resource.(*cgroup.CgroupResource); ok {
+				return cgroupResource, true
+			}
+		}
+		return nil, false
+	}
+}
+
+// NewFaultHandler creates the handler of the fault endpoints. It keeps track of the faults injected into the
+// tasks and persists them with the data client.
+func NewFaultHandler(
+	state dockerstate.TaskEngineState,
+	ecsClient ecs.ECSClient,
+	containerInstanceArn string,
+	cfg *config.Config,
+	statsEngine stats.Engine,
+	availabilityZone string,
+	availabilityZoneID string,
+	vpcID string,
+	metricsFactory metrics.EntryFactory,
+	dataClient data.Client,
+) *fault.FaultHandler {
+	// Create and initialize the audit log of the injected faults
+	faultLogger, err := seelog.LoggerFromConfigAsString(audit.FaultAuditLoggerConfig(cfg))
+	if err != nil {
+		seelog.Errorf("Error initializing the fault audit log: %v", err)
+		faultLogger = seelog.Disabled
+	}
+	faultAuditLogger := audit.NewFaultAuditLog(containerInstanceArn, cfg, faultLogger)
+
+	tmdsAgentState := v4.NewTMDSAgentState(state, statsEngine, ecsClient, cfg.Cluster, availabilityZone,
+		availabilityZoneID, vpcID, containerInstanceArn)
+	return fault.New(tmdsAgentState, metricsFactory, execwrapper.NewExec(),
+		fault.WithFaultStore(dataClient),
+		fault.WithResourceStressor(cgroup.NewResourceStressor(taskCgroupResource(state))),
+		fault.WithAuditLogger(faultAuditLogger))
+}
+
 // registerFaultHandlers adds handlers for fault endpoints
-// TODO: Will need to be called in taskServerSetup once all of the handlers have been implemented
 func registerFaultHandlers(
 	muxRouter *mux.Router,
-	agentState *v4.TMDSAgentState,
+	handler *fault.FaultHandler,
 	metricsFactory metrics.EntryFactory,
-	execWrapper execwrapper.Exec,
 ) {
-	handler := fault.New(agentState, metricsFactory, execWrapper)
-
-	if muxRouter == nil {
-		return
-	}
-
 	// Setting up handler endpoints for network blackhole port fault injections
 	muxRouter.Handle(
 		fault.NetworkFaultPath(faulttype.BlackHolePortFaultType, faulttype.StartNetworkFaultPostfix),
@@ -323,6 +389,272 @@ func registerFaultHandlers(
 		),
 	).Methods("POST")
 
+	// Setting up handler endpoints for network bandwidth fault injections
+	muxRouter.Handle(
+		fault.NetworkFaultPath(faulttype.BandwidthFaultType, faulttype.StartNetworkFaultPostfix),
+		fault.TelemetryMiddleware(
+			tollbooth.LimitFuncHandler(
+				createRateLimiter(),
+				handler.StartNetworkBandwidth(),
+			),
+			metricsFactory,
+			faulttype.StartNetworkFaultPostfix,
+			faulttype.BandwidthFaultType,
+		),
+	).Methods("POST")
+	muxRouter.Handle(
+		fault.NetworkFaultPath(faulttype.BandwidthFaultType, faulttype.StopNetworkFaultPostfix),
+		fault.TelemetryMiddleware(
+			tollbooth.LimitFuncHandler(
+				createRateLimiter(),
+				handler.StopNetworkBandwidth(),
+			),
+			metricsFactory,
+			faulttype.StopNetworkFaultPostfix,
+			faulttype.BandwidthFaultType,
+		),
+	).Methods("POST")
+	muxRouter.Handle(
+		fault.NetworkFaultPath(faulttype.BandwidthFaultType, faulttype.CheckNetworkFaultPostfix),
+		fault.TelemetryMiddleware(
+			tollbooth.LimitFuncHandler(
+				createRateLimiter(),
+				handler.CheckNetworkBandwidth(),
+			),
+			metricsFactory,
+			faulttype.CheckNetworkFaultPostfix,
+			faulttype.BandwidthFaultType,
+		),
+	).Methods("POST")
+
+	// Setting up handler endpoints for network packet corruption fault injections
+	muxRouter.Handle(
+		fault.NetworkFaultPath(faulttype.CorruptionFaultType, faulttype.StartNetworkFaultPostfix),
+		fault.TelemetryMiddleware(
+			tollbooth.LimitFuncHandler(
+				createRateLimiter(),
+				handler.StartNetworkCorruption(),
+			),
+			metricsFactory,
+			faulttype.StartNetworkFaultPostfix,
+			faulttype.CorruptionFaultType,
+		),
+	).Methods("POST")
+	muxRouter.Handle(
+		fault.NetworkFaultPath(faulttype.CorruptionFaultType, faulttype.StopNetworkFaultPostfix),
+		fault.TelemetryMiddleware(
+			tollbooth.LimitFuncHandler(
+				createRateLimiter(),
+				handler.StopNetworkCorruption(),
+			),
+			metricsFactory,
+			faulttype.StopNetworkFaultPostfix,
+			faulttype.CorruptionFaultType,
+		),
+	).Methods("POST")
+	muxRouter.Handle(
+		fault.NetworkFaultPath(faulttype.CorruptionFaultType, faulttype.CheckNetworkFaultPostfix),
+		fault.TelemetryMiddleware(
+			tollbooth.LimitFuncHandler(
+				createRateLimiter(),
+				handler.CheckNetworkCorruption(),
+			),
+			metricsFactory,
+			faulttype.CheckNetworkFaultPostfix,
+			faulttype.CorruptionFaultType,
+		),
+	).Methods("POST")
+
+	// Setting up handler endpoints for network packet duplication fault injections
+	muxRouter.Handle(
+		fault.NetworkFaultPath(faulttype.DuplicationFaultType, faulttype.StartNetworkFaultPostfix),
+		fault.TelemetryMiddleware(
+			tollbooth.LimitFuncHandler(
+				createRateLimiter(),
+				handler.StartNetworkDuplication(),
+			),
+			metricsFactory,
+			faulttype.StartNetworkFaultPostfix,
+			faulttype.DuplicationFaultType,
+		),
+	).Methods("POST")
+	muxRouter.Handle(
+		fault.NetworkFaultPath(faulttype.DuplicationFaultType, faulttype.StopNetworkFaultPostfix),
+		fault.TelemetryMiddleware(
+			tollbooth.LimitFuncHandler(
+				createRateLimiter(),
+				handler.StopNetworkDuplication(),
+			),
+			metricsFactory,
+			faulttype.StopNetworkFaultPostfix,
+			faulttype.DuplicationFaultType,
+		),
+	).Methods("POST")
+	muxRouter.Handle(
+		fault.NetworkFaultPath(faulttype.DuplicationFaultType, faulttype.CheckNetworkFaultPostfix),
+		fault.TelemetryMiddleware(
+			tollbooth.LimitFuncHandler(
+				createRateLimiter(),
+				handler.CheckNetworkDuplication(),
+			),
+			metricsFactory,
+			faulttype.CheckNetworkFaultPostfix,
+			faulttype.DuplicationFaultType,
+		),
+	).Methods("POST")
+
+	// Setting up handler endpoints for network packet reordering fault injections
+	muxRouter.Handle(
+		fault.NetworkFaultPath(faulttype.ReorderFaultType, faulttype.StartNetworkFaultPostfix),
+		fault.TelemetryMiddleware(
+			tollbooth.LimitFuncHandler(
+				createRateLimiter(),
+				handler.StartNetworkReorder(),
+			),
+			metricsFactory,
+			faulttype.StartNetworkFaultPostfix,
+			faulttype.ReorderFaultType,
+		),
+	).Methods("POST")
+	muxRouter.Handle(
+		fault.NetworkFaultPath(faulttype.ReorderFaultType, faulttype.StopNetworkFaultPostfix),
+		fault.TelemetryMiddleware(
+			tollbooth.LimitFuncHandler(
+				createRateLimiter(),
+				handler.StopNetworkReorder(),
+			),
+			metricsFactory,
+			faulttype.StopNetworkFaultPostfix,
+			faulttype.ReorderFaultType,
+		),
+	).Methods("POST")
+	muxRouter.Handle(
+		fault.NetworkFaultPath(faulttype.ReorderFaultType, faulttype.CheckNetworkFaultPostfix),
+		fault.TelemetryMiddleware(
+			tollbooth.LimitFuncHandler(
+				createRateLimiter(),
+				handler.CheckNetworkReorder(),
+			),
+			metricsFactory,
+			faulttype.CheckNetworkFaultPostfix,
+			faulttype.ReorderFaultType,
+		),
+	).Methods("POST")
+
+	// Setting up handler endpoints for network DNS fault injections
+	muxRouter.Handle(
+		fault.NetworkFaultPath(faulttype.DNSFaultType, faulttype.StartNetworkFaultPostfix),
+		fault.TelemetryMiddleware(
+			tollbooth.LimitFuncHandler(
+				createRateLimiter(),
+				handler.StartNetworkDNS(),
+			),
+			metricsFactory,
+			faulttype.StartNetworkFaultPostfix,
+			faulttype.DNSFaultType,
+		),
+	).Methods("POST")
+	muxRouter.Handle(
+		fault.NetworkFaultPath(faulttype.DNSFaultType, faulttype.StopNetworkFaultPostfix),
+		fault.TelemetryMiddleware(
+			tollbooth.LimitFuncHandler(
+				createRateLimiter(),
+				handler.StopNetworkDNS(),
+			),
+			metricsFactory,
+			faulttype.StopNetworkFaultPostfix,
+			faulttype.DNSFaultType,
+		),
+	).Methods("POST")
+	muxRouter.Handle(
+		fault.NetworkFaultPath(faulttype.DNSFaultType, faulttype.CheckNetworkFaultPostfix),
+		fault.TelemetryMiddleware(
+			tollbooth.LimitFuncHandler(
+				createRateLimiter(),
+				handler.CheckNetworkDNS(),
+			),
+			metricsFactory,
+			faulttype.CheckNetworkFaultPostfix,
+			faulttype.DNSFaultType,
+		),
+	).Methods("POST")
+
+	// Setting up handler endpoints for CPU stress fault injections
+	muxRouter.Handle(
+		fault.NetworkFaultPath(faulttype.CPUStressFaultType, faulttype.StartNetworkFaultPostfix),
+		fault.TelemetryMiddleware(
+			tollbooth.LimitFuncHandler(
+				createRateLimiter(),
+				handler.StartCPUStress(),
+			),
+			metricsFactory,
+			faulttype.StartNetworkFaultPostfix,
+			faulttype.CPUStressFaultType,
+		),
+	).Methods("POST")
+	muxRouter.Handle(
+		fault.NetworkFaultPath(faulttype.CPUStressFaultType, faulttype.StopNetworkFaultPostfix),
+		fault.TelemetryMiddleware(
+			tollbooth.LimitFuncHandler(
+				createRateLimiter(),
+				handler.StopCPUStress(),
+			),
+			metricsFactory,
+			faulttype.StopNetworkFaultPostfix,
+			faulttype.CPUStressFaultType,
+		),
+	).Methods("POST")
+	muxRouter.Handle(
+		fault.NetworkFaultPath(faulttype.CPUStressFaultType, faulttype.CheckNetworkFaultPostfix),
+		fault.TelemetryMiddleware(
+			tollbooth.LimitFuncHandler(
+				createRateLimiter(),
+				handler.CheckCPUStress(),
+			),
+			metricsFactory,
+			faulttype.CheckNetworkFaultPostfix,
+			faulttype.CPUStressFaultType,
+		),
+	).Methods("POST")
+
+	// Setting up handler endpoints for memory pressure fault injections
+	muxRouter.Handle(
+		fault.NetworkFaultPath(faulttype.MemoryPressureFaultType, faulttype.StartNetworkFaultPostfix),
+		fault.TelemetryMiddleware(
+			tollbooth.LimitFuncHandler(
+				createRateLimiter(),
+				handler.StartMemoryPressure(),
+			),
+			metricsFactory,
+			faulttype.StartNetworkFaultPostfix,
+			faulttype.MemoryPressureFaultType,
+		),
+	).Methods("POST")
+	muxRouter.Handle(
+		fault.NetworkFaultPath(faulttype.MemoryPressureFaultType, faulttype.StopNetworkFaultPostfix),
+		fault.TelemetryMiddleware(
+			tollbooth.LimitFuncHandler(
+				createRateLimiter(),
+				handler.StopMemoryPressure(),
+			),
+			metricsFactory,
+			faulttype.StopNetworkFaultPostfix,
+			faulttype.MemoryPressureFaultType,
+		),
+	).Methods("POST")
+	muxRouter.Handle(
+		fault.NetworkFaultPath(faulttype.MemoryPressureFaultType, faulttype.CheckNetworkFaultPostfix),
+		fault.TelemetryMiddleware(
+			tollbooth.LimitFuncHandler(
+				createRateLimiter(),
+				handler.CheckMemoryPressure(),
+			),
+			metricsFactory,
+			faulttype.CheckNetworkFaultPostfix,
+			faulttype.MemoryPressureFaultType,
+		),
+	).Methods("POST")
+
 	seelog.Debug("Successfully set up Fault TMDS handlers")
 }
 
@@ -334,7 +666,7 @@ func createRateLimiter() *limiter.Limiter {
 }
 
 // ServeTaskHTTPEndpoint serves task/container metadata, task/container stats, IAM Role Credentials, and Agent APIs
-// for tasks being managed by the agent.
+// for tasks being managed by the agent. The requests are throttled by the rate limiter.
 func ServeTaskHTTPEndpoint(
 	ctx context.Context,
 	credentialsManager credentials.Manager,
@@ -346,6 +678,10 @@ func ServeTaskHTTPEndpoint(
 	availabilityZone string,
 	availabilityZoneID string,
 	vpcID string,
+	metricsFactory metrics.EntryFactory,
+	taskEngine engine.TaskEngine,
+	faultHandler *fault.FaultHandler,
+	rateLimiter *tmds.RateLimiter,
 ) {
 	// Create and initialize the audit log
 	logger, err := seelog.LoggerFromConfigAsString(audit.AuditLoggerConfig(cfg))
@@ -361,8 +697,9 @@ func ServeTaskHTTPEndpoint(
 		Region: cfg.AWSRegion, Endpoint: cfg.APIEndpoint, AcceptInsecureCert: cfg.AcceptInsecureCert, IPCompatibility: cfg.InstanceIPCompatibility,
 	}
 	server, err := taskServerSetup(credentialsManager, auditLogger, state, ecsClient, cfg.Cluster,
-		statsEngine, cfg.TaskMetadataSteadyStateRate, cfg.TaskMetadataBurstRate,
-		availabilityZone, availabilityZoneID, vpcID, containerInstanceArn, taskProtectionClientFactory)
+		statsEngine, rateLimiter,
+		availabilityZone, availabilityZoneID, vpcID, containerInstanceArn, taskProtectionClientFactory, metricsFactory,
+		taskEngine, faultHandler)
 	if err != nil {
 		seelog.Criticalf("Failed to set up Task Metadata Server: %v", err)
 		return
@@ -29,6 +29,7 @@ import (
 	mock_stats "github.com/aws/amazon-ecs-agent/agent/stats/mock"
 	mock_ecs "github.com/aws/amazon-ecs-agent/ecs-agent/api/ecs/mocks"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/metrics"
+	faulthandler "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/handlers"
 	mock_execwrapper "github.com/aws/amazon-ecs-agent/ecs-agent/utils/execwrapper/mocks"
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
@@ -60,7 +61,7 @@ func startServer(t *testing.T) (*http.Server, int) {
 	metricsFactory := metrics.NewNopEntryFactory()
 	execWrapper := mock_execwrapper.NewMockExec(ctrl)
 
-	registerFaultHandlers(router, agentState, metricsFactory, execWrapper)
+	registerFaultHandlers(router, faulthandler.New(agentState, metricsFactory, execWrapper), metricsFactory)
 
 	server := &http.Server{
 		Addr:    ":0", // Lets the system allocate an available port
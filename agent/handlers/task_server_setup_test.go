@@ -34,7 +34,9 @@ import (
 	"github.com/aws/amazon-ecs-agent/agent/api/serviceconnect"
 	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
 	"github.com/aws/amazon-ecs-agent/agent/config"
+	"github.com/aws/amazon-ecs-agent/agent/data"
 	mock_dockerstate "github.com/aws/amazon-ecs-agent/agent/engine/dockerstate/mocks"
+	mock_engine "github.com/aws/amazon-ecs-agent/agent/engine/mocks"
 	v3 "github.com/aws/amazon-ecs-agent/agent/handlers/v3"
 	agentV4 "github.com/aws/amazon-ecs-agent/agent/handlers/v4"
 	mock_stats "github.com/aws/amazon-ecs-agent/agent/stats/mock"
@@ -45,9 +47,11 @@ import (
 	"github.com/aws/amazon-ecs-agent/ecs-agent/credentials"
 	mock_credentials "github.com/aws/amazon-ecs-agent/ecs-agent/credentials/mocks"
 	mock_audit "github.com/aws/amazon-ecs-agent/ecs-agent/logger/audit/mocks"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/metrics"
 	mock_metrics "github.com/aws/amazon-ecs-agent/ecs-agent/metrics/mocks"
 	ni "github.com/aws/amazon-ecs-agent/ecs-agent/netlib/model/networkinterface"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/stats"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds"
 	faulthandler "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/handlers"
 	faulttype "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/types"
 	tmdsresponse "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/response"
@@ -1063,8 +1067,8 @@ func testErrorResponsesFromServer(t *testing.T, path string, expectedErrorMessag
 	auditLog := mock_audit.NewMockAuditLogger(ctrl)
 	ecsClient := mock_ecs.NewMockECSClient(ctrl)
 	server, err := taskServerSetup(credentialsManager, auditLog, nil, ecsClient, "", nil,
-		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", "", vpcID,
-		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
+		tmds.NewRateLimiter(config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate), "", "", vpcID,
+		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl), metrics.NewNopEntryFactory(), nil, nil)
 	require.NoError(t, err)
 
 	recorder := httptest.NewRecorder()
@@ -1101,8 +1105,8 @@ func getResponseForCredentialsRequest(t *testing.T, expectedStatus int,
 	auditLog := mock_audit.NewMockAuditLogger(ctrl)
 	ecsClient := mock_ecs.NewMockECSClient(ctrl)
 	server, err := taskServerSetup(credentialsManager, auditLog, nil, ecsClient, "", nil,
-		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", "", vpcID,
-		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
+		tmds.NewRateLimiter(config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate), "", "", vpcID,
+		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl), metrics.NewNopEntryFactory(), nil, nil)
 	require.NoError(t, err)
 
 	recorder := httptest.NewRecorder()
@@ -1170,8 +1174,8 @@ func TestV3ContainerAssociations(t *testing.T) {
 		state.EXPECT().TaskByArn(taskARN).Return(standardTask(), true),
 	)
 	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine,
-		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", "", vpcID,
-		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
+		tmds.NewRateLimiter(config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate), "", "", vpcID,
+		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl), metrics.NewNopEntryFactory(), nil, nil)
 	require.NoError(t, err)
 	recorder := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", v3BasePath+v3EndpointID+"/associations/"+associationType, nil)
@@ -1202,8 +1206,8 @@ func TestV3ContainerAssociation(t *testing.T) {
 		state.EXPECT().TaskByArn(taskARN).Return(task, true),
 	)
 	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine,
-		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", "", vpcID,
-		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
+		tmds.NewRateLimiter(config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate), "", "", vpcID,
+		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl), metrics.NewNopEntryFactory(), nil, nil)
 	require.NoError(t, err)
 	recorder := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", v3BasePath+v3EndpointID+"/associations/"+associationType+"/"+associationName, nil)
@@ -1233,8 +1237,8 @@ func TestV4ContainerAssociations(t *testing.T) {
 		state.EXPECT().TaskByArn(taskARN).Return(task, true),
 	)
 	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine,
-		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", "", vpcID,
-		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
+		tmds.NewRateLimiter(config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate), "", "", vpcID,
+		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl), metrics.NewNopEntryFactory(), nil, nil)
 	require.NoError(t, err)
 	recorder := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", v4BasePath+v3EndpointID+"/associations/"+associationType, nil)
@@ -1265,8 +1269,8 @@ func TestV4ContainerAssociation(t *testing.T) {
 		state.EXPECT().TaskByArn(taskARN).Return(task, true),
 	)
 	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine,
-		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", "", vpcID,
-		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
+		tmds.NewRateLimiter(config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate), "", "", vpcID,
+		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl), metrics.NewNopEntryFactory(), nil, nil)
 	require.NoError(t, err)
 	recorder := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", v4BasePath+v3EndpointID+"/associations/"+associationType+"/"+associationName, nil)
@@ -1292,8 +1296,8 @@ func TestTaskHTTPEndpoint301Redirect(t *testing.T) {
 	ecsClient := mock_ecs.NewMockECSClient(ctrl)
 
 	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine,
-		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", "", vpcID,
-		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
+		tmds.NewRateLimiter(config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate), "", "", vpcID,
+		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl), metrics.NewNopEntryFactory(), nil, nil)
 	require.NoError(t, err)
 
 	for testPath, expectedPath := range testPathsMap {
@@ -1335,8 +1339,8 @@ func TestTaskHTTPEndpointErrorCode404(t *testing.T) {
 	ecsClient := mock_ecs.NewMockECSClient(ctrl)
 
 	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine,
-		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", "", vpcID,
-		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
+		tmds.NewRateLimiter(config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate), "", "", vpcID,
+		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl), metrics.NewNopEntryFactory(), nil, nil)
 	require.NoError(t, err)
 
 	for _, testPath := range testPaths {
@@ -1375,8 +1379,8 @@ func TestTaskHTTPEndpointErrorCode400(t *testing.T) {
 	ecsClient := mock_ecs.NewMockECSClient(ctrl)
 
 	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine,
-		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", "", vpcID,
-		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
+		tmds.NewRateLimiter(config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate), "", "", vpcID,
+		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl), metrics.NewNopEntryFactory(), nil, nil)
 	require.NoError(t, err)
 
 	for _, testPath := range testPaths {
@@ -1414,8 +1418,8 @@ func TestTaskHTTPEndpointErrorCode500(t *testing.T) {
 	ecsClient := mock_ecs.NewMockECSClient(ctrl)
 
 	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine,
-		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", "", vpcID,
-		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
+		tmds.NewRateLimiter(config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate), "", "", vpcID,
+		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl), metrics.NewNopEntryFactory(), nil, nil)
 	require.NoError(t, err)
 
 	for _, testPath := range testPaths {
@@ -1484,8 +1488,8 @@ func TestV4TaskNotFoundError404(t *testing.T) {
 			ecsClient := mock_ecs.NewMockECSClient(ctrl)
 
 			server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine,
-				config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", "", vpcID,
-				containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
+				tmds.NewRateLimiter(config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate), "", "", vpcID,
+				containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl), metrics.NewNopEntryFactory(), nil, nil)
 			require.NoError(t, err)
 
 			state.EXPECT().TaskARNByV3EndpointID(gomock.Any()).Return("", tc.taskFound).AnyTimes()
@@ -1540,8 +1544,8 @@ func TestV4Unexpected500Error(t *testing.T) {
 			ecsClient := mock_ecs.NewMockECSClient(ctrl)
 
 			server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine,
-				config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", "", vpcID,
-				containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
+				tmds.NewRateLimiter(config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate), "", "", vpcID,
+				containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl), metrics.NewNopEntryFactory(), nil, nil)
 			require.NoError(t, err)
 
 			// Initial lookups succeed
@@ -1648,8 +1652,8 @@ func testTMDSRequest[R TMDSResponse](t *testing.T, tc TMDSTestCase[R]) {
 	// Initialize server
 	server, err := taskServerSetup(credsManager, auditLog, state, ecsClient,
 		clusterName, statsEngine,
-		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, availabilityzone, availabilityZoneID, vpcID,
-		containerInstanceArn, taskProtectionClientFactory)
+		tmds.NewRateLimiter(config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate), availabilityzone, availabilityZoneID, vpcID,
+		containerInstanceArn, taskProtectionClientFactory, metrics.NewNopEntryFactory(), nil, nil)
 	require.NoError(t, err)
 
 	// Create the request
@@ -4292,6 +4296,45 @@ func TestRegisterCheckPacketLossFaultHandler(t *testing.T) {
 	testRegisterFaultHandler(t, tcs, faulthandler.NetworkFaultPath(faulttype.PacketLossFaultType, faulttype.CheckNetworkFaultPostfix), faulttype.CheckNetworkFaultPostfix, faulttype.PacketLossFaultType)
 }
 
+func TestRegisterCheckNetemFaultHandlers(t *testing.T) {
+	testCases := []struct {
+		faultType                string
+		faultExistsCommandOutput string
+	}{
+		{
+			faultType:                faulttype.BandwidthFaultType,
+			faultExistsCommandOutput: `[{"kind":"netem","handle":"10:","parent":"1:1","options":{"limit":1000,"rate":{"rate":12500,"packetoverhead":0,"cellsize":0,"celloverhead":0},"ecn":false,"gap":0}}]`,
+		},
+		{
+			faultType:                faulttype.CorruptionFaultType,
+			faultExistsCommandOutput: `[{"kind":"netem","handle":"10:","parent":"1:1","options":{"limit":1000,"corrupt":{"corrupt":0.06,"correlation":0},"ecn":false,"gap":0}}]`,
+		},
+		{
+			faultType:                faulttype.DuplicationFaultType,
+			faultExistsCommandOutput: `[{"kind":"netem","handle":"10:","parent":"1:1","options":{"limit":1000,"duplicate":{"duplicate":0.06,"correlation":0},"ecn":false,"gap":0}}]`,
+		},
+		{
+			faultType:                faulttype.ReorderFaultType,
+			faultExistsCommandOutput: `[{"kind":"netem","handle":"10:","parent":"1:1","options":{"limit":1000,"reorder":{"reorder":0.06,"correlation":0},"delay":{"delay":0.01,"jitter":0,"correlation":0},"ecn":false,"gap":0}}]`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.faultType, func(t *testing.T) {
+			setExecExpectations := func(exec *mock_execwrapper.MockExec, ctrl *gomock.Controller) {
+				ctx, cancel := context.WithTimeout(context.Background(), requestTimeoutDuration)
+				mockCMD := mock_execwrapper.NewMockCmd(ctrl)
+				gomock.InOrder(
+					exec.EXPECT().NewExecContextWithTimeout(gomock.Any(), gomock.Any()).Times(1).Return(ctx, cancel),
+					exec.EXPECT().CommandContext(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(mockCMD),
+					mockCMD.EXPECT().CombinedOutput().Times(1).Return([]byte(tc.faultExistsCommandOutput), nil),
+				)
+			}
+			tcs := generateCommonNetworkFaultInjectionTestCases("check "+tc.faultType, "running", setExecExpectations, nil)
+			testRegisterFaultHandler(t, tcs, faulthandler.NetworkFaultPath(tc.faultType, faulttype.CheckNetworkFaultPostfix), faulttype.CheckNetworkFaultPostfix, tc.faultType)
+		})
+	}
+}
+
 func testRegisterFaultHandler(t *testing.T, tcs []networkFaultTestCase, tmdsEndpoint, faultOperation, faultType string) {
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
@@ -4342,12 +4385,14 @@ func testRegisterFaultHandler(t *testing.T, tcs []networkFaultTestCase, tmdsEndp
 				tmdsAPI = "/api/%s/fault/v1/network-packet-loss/stop"
 			case faulthandler.NetworkFaultPath(faulttype.PacketLossFaultType, faulttype.CheckNetworkFaultPostfix):
 				tmdsAPI = "/api/%s/fault/v1/network-packet-loss/status"
+			case faulthandler.NetworkFaultPath(faultType, faulttype.CheckNetworkFaultPostfix):
+				tmdsAPI = "/api/%s/fault/v1/" + faultType + "/status"
 			default:
 				t.Error("Unrecognized TMDS Endpoint")
 			}
 
 			router := mux.NewRouter()
-			registerFaultHandlers(router, agentState, metricsFactory, execWrapper)
+			registerFaultHandlers(router, faulthandler.New(agentState, metricsFactory, execWrapper), metricsFactory)
 			var requestBody io.Reader
 			if tc.requestBody != "" {
 				reqBodyBytes, err := json.Marshal(tc.requestBody)
@@ -4372,3 +4417,34 @@ func testRegisterFaultHandler(t *testing.T, tcs []networkFaultTestCase, tmdsEndp
 		})
 	}
 }
+
+// TestSetupFaultCleanup tests that the fault handler is registered with the task engine and that the faults of
+// tasks that are no longer known to the agent are cleaned up on startup.
+func TestSetupFaultCleanup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataClient, err := data.NewWithSetup(t.TempDir())
+	require.NoError(t, err)
+	defer dataClient.Close()
+	require.NoError(t, dataClient.SaveFault(&faulttype.InjectedFault{
+		ID:        "/some/path/network-latency",
+		TaskARN:   taskARN,
+		FaultType: faulttype.LatencyFaultType,
+	}))
+
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	taskEngine := mock_engine.NewMockTaskEngine(ctrl)
+	execWrapper := mock_execwrapper.NewMockExec(ctrl)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	state.EXPECT().TaskByArn(taskARN).Return(nil, false)
+	execWrapper.EXPECT().NewExecContextWithTimeout(gomock.Any(), gomock.Any()).Return(ctx, cancel)
+	taskEngine.EXPECT().SetTaskFaultCleaner(gomock.Any())
+
+	handler := faulthandler.New(nil, metrics.NewNopEntryFactory(), execWrapper, faulthandler.WithFaultStore(dataClient))
+	setupFaultCleanup(handler, state, taskEngine)
+	require.Eventually(t, func() bool {
+		faults, err := dataClient.GetFaults()
+		return err == nil && len(faults) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
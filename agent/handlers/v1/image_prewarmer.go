@@ -0,0 +1,49 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1
+
+import (
+	"github.com/aws/amazon-ecs-agent/agent/engine"
+	v1 "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v1"
+)
+
+// ImagePrewarmerImpl is an implementation of the ImagePrewarmer interface in the introspection package.
+// It pre-warms images with the image pre-warmer of the agent.
+type ImagePrewarmerImpl struct {
+	Prewarmer *engine.ImagePrewarmer
+}
+
+// PrewarmImages starts pulling the images in the background.
+func (ip *ImagePrewarmerImpl) PrewarmImages(images []string) error {
+	ip.Prewarmer.Prewarm(images)
+	return nil
+}
+
+// GetImagePrewarmStatus returns the status of the images pre-warmed by the agent in v1 format.
+func (ip *ImagePrewarmerImpl) GetImagePrewarmStatus() (*v1.ImagesPrewarmResponse, error) {
+	imageResponses := []*v1.ImagePrewarmResponse{}
+	for _, status := range ip.Prewarmer.Statuses() {
+		imageResponse := &v1.ImagePrewarmResponse{
+			Image:  status.Image,
+			Status: status.Status,
+			Error:  status.Error,
+		}
+		if !status.ProtectedUntil.IsZero() {
+			protectedUntil := status.ProtectedUntil.UTC()
+			imageResponse.ProtectedUntil = &protectedUntil
+		}
+		imageResponses = append(imageResponses, imageResponse)
+	}
+	return &v1.ImagesPrewarmResponse{Images: imageResponses}, nil
+}
@@ -22,14 +22,22 @@ import (
 	"github.com/aws/amazon-ecs-agent/agent/utils"
 	agentversion "github.com/aws/amazon-ecs-agent/agent/version"
 	v1 "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v1"
+	faulttype "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/types"
 )
 
+// FaultsProvider provides the faults currently injected into the tasks.
+type FaultsProvider interface {
+	GetFaults() []*faulttype.InjectedFault
+}
+
 // AgentStateImpl is an implementation of the AgentState interface in the introspection package.
 // This struct supplies the introspection server with the necessary data to construct its responses.
 type AgentStateImpl struct {
 	ContainerInstanceArn *string
 	ClusterName          string
 	TaskEngine           handlerutils.DockerStateResolver
+	// Faults provides the faults injected into the tasks. No faults are listed if it's nil.
+	Faults FaultsProvider
 }
 
 var licenseProvider = utils.NewLicenseProvider()
@@ -105,3 +113,21 @@ func createTaskResponse(
 	containerMap, _ := agentState.ContainerMapByArn(task.Arn)
 	return NewTaskResponse(task, containerMap), nil
 }
+
+// GetFaultsMetadata returns the faults currently injected into the tasks on the host.
+func (as *AgentStateImpl) GetFaultsMetadata() (*v1.FaultsResponse, error) {
+	faultResponses := []*v1.FaultResponse{}
+	if as.Faults == nil {
+		return &v1.FaultsResponse{Faults: faultResponses}, nil
+	}
+	for _, fault := range as.Faults.GetFaults() {
+		faultResponses = append(faultResponses, &v1.FaultResponse{
+			TaskArn:    fault.TaskARN,
+			FaultType:  fault.FaultType,
+			Parameters: fault.Parameters,
+			StartedAt:  fault.StartedAt,
+			ExpiresAt:  fault.ExpiresAt,
+		})
+	}
+	return &v1.FaultsResponse{Faults: faultResponses}, nil
+}
@@ -19,16 +19,19 @@ package v1
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
 	mock_dockerstate "github.com/aws/amazon-ecs-agent/agent/engine/dockerstate/mocks"
 	mock_utils "github.com/aws/amazon-ecs-agent/agent/handlers/mocks"
 	agentversion "github.com/aws/amazon-ecs-agent/agent/version"
 	v1 "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v1"
+	faulttype "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/types"
 	"github.com/aws/aws-sdk-go-v2/aws"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -368,3 +371,47 @@ func TestCreateTaskResponse(t *testing.T) {
 		assert.Equal(t, expectedTaskWithoutContainersResponse(), *response)
 	})
 }
+
+// testFaultsProvider provides a fixed list of injected faults.
+type testFaultsProvider []*faulttype.InjectedFault
+
+func (p testFaultsProvider) GetFaults() []*faulttype.InjectedFault {
+	return p
+}
+
+func TestGetFaultsMetadata(t *testing.T) {
+	startedAt := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := startedAt.Add(time.Minute)
+	agentState := &AgentStateImpl{
+		ContainerInstanceArn: containerInstanceArn,
+		ClusterName:          clusterName,
+		Faults: testFaultsProvider{{
+			ID:         "/some/path/network-latency",
+			TaskARN:    taskARN,
+			FaultType:  faulttype.LatencyFaultType,
+			Parameters: `{"DelayMilliseconds":100}`,
+			StartedAt:  startedAt,
+			ExpiresAt:  &expiresAt,
+		}},
+	}
+	response, err := agentState.GetFaultsMetadata()
+	require.NoError(t, err)
+	require.Len(t, response.Faults, 1)
+	assert.Equal(t, &v1.FaultResponse{
+		TaskArn:    taskARN,
+		FaultType:  faulttype.LatencyFaultType,
+		Parameters: `{"DelayMilliseconds":100}`,
+		StartedAt:  startedAt,
+		ExpiresAt:  &expiresAt,
+	}, response.Faults[0])
+}
+
+func TestGetFaultsMetadataWithoutFaults(t *testing.T) {
+	agentState := &AgentStateImpl{
+		ContainerInstanceArn: containerInstanceArn,
+		ClusterName:          clusterName,
+	}
+	response, err := agentState.GetFaultsMetadata()
+	require.NoError(t, err)
+	assert.Empty(t, response.Faults)
+}
@@ -32,6 +32,20 @@ func NewTaskResponse(task *apitask.Task, containerMap map[string]*apicontainer.D
 		containerResponse := NewContainerResponse(container, task.GetPrimaryENI())
 		containers = append(containers, containerResponse)
 	}
+	// Containers which are not created yet are not in the container map, include the ones which
+	// are blocked so that the reason why the task is pending is visible.
+	for _, container := range task.Containers {
+		if container.IsInternal() || containerMapHasName(containerMap, container.Name) {
+			continue
+		}
+		if blockedReason := NewBlockedReasonResponse(container); blockedReason != nil {
+			containers = append(containers, v1.ContainerResponse{
+				Name:          container.Name,
+				Image:         container.Image,
+				BlockedReason: blockedReason,
+			})
+		}
+	}
 
 	knownStatus := task.GetKnownStatus()
 	knownBackendStatus := knownStatus.BackendStatus()
@@ -52,6 +66,16 @@ func NewTaskResponse(task *apitask.Task, containerMap map[string]*apicontainer.D
 	}
 }
 
+// containerMapHasName returns true if the container map has a container with the given name
+func containerMapHasName(containerMap map[string]*apicontainer.DockerContainer, name string) bool {
+	for _, container := range containerMap {
+		if container.Container.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // NewContainerResponse converts the given container to a v1 server ContainerResponse.
 func NewContainerResponse(dockerContainer *apicontainer.DockerContainer, eni *ni.NetworkInterface) v1.ContainerResponse {
 	container := dockerContainer.Container
@@ -87,9 +111,27 @@ func NewContainerResponse(dockerContainer *apicontainer.DockerContainer, eni *ni
 		restartCount := container.RestartTracker.GetRestartCount()
 		resp.RestartCount = &restartCount
 	}
+	resp.BlockedReason = NewBlockedReasonResponse(container)
 	return resp
 }
 
+// NewBlockedReasonResponse returns the reason why the container can't progress to its next status
+// yet, or nil if it isn't blocked.
+func NewBlockedReasonResponse(container *apicontainer.Container) *tmdsresponse.BlockedReasonResponse {
+	reason := container.GetBlockedReason()
+	if reason == nil {
+		return nil
+	}
+	return &tmdsresponse.BlockedReasonResponse{
+		Type:      reason.Type,
+		Container: reason.Container,
+		Condition: reason.Condition,
+		Resource:  reason.Resource,
+		Message:   reason.String(),
+		Since:     reason.Since.UTC(),
+	}
+}
+
 // NewPortBindingsResponse generates v1 server PortResponses for a container.
 func NewPortBindingsResponse(dockerContainer *apicontainer.DockerContainer, eni *ni.NetworkInterface) []tmdsresponse.PortResponse {
 	container := dockerContainer.Container
@@ -18,10 +18,13 @@ package v1
 
 import (
 	"testing"
+	"time"
 
 	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/api/container/restart"
+	v1 "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v1"
 	ni "github.com/aws/amazon-ecs-agent/ecs-agent/netlib/model/networkinterface"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/response"
 
 	"github.com/docker/docker/api/types"
 	"github.com/stretchr/testify/assert"
@@ -35,6 +38,36 @@ func TestTaskResponse(t *testing.T) {
 	assert.Equal(t, expectedTaskResponse(), *taskResponse)
 }
 
+func TestTaskResponseWithBlockedContainers(t *testing.T) {
+	task := testTask()
+	container := testContainer()
+	blockedContainer := &apicontainer.Container{Name: "app", Image: imageName}
+	since := time.Now()
+	blockedContainer.SetBlockedReason(&apicontainer.BlockedReason{
+		Type:      apicontainer.BlockedOnContainer,
+		Container: containerName,
+		Condition: "HEALTHY",
+		Since:     since,
+	})
+	pendingContainer := &apicontainer.Container{Name: "pending", Image: imageName}
+	task.Containers = []*apicontainer.Container{container, blockedContainer, pendingContainer}
+
+	taskResponse := NewTaskResponse(task, testContainerMap(container))
+	expected := expectedTaskResponse()
+	expected.Containers = append(expected.Containers, v1.ContainerResponse{
+		Name:  "app",
+		Image: imageName,
+		BlockedReason: &response.BlockedReasonResponse{
+			Type:      apicontainer.BlockedOnContainer,
+			Container: containerName,
+			Condition: "HEALTHY",
+			Message:   "waiting for container sleepy to be HEALTHY",
+			Since:     since.UTC(),
+		},
+	})
+	assert.Equal(t, expected, *taskResponse)
+}
+
 func TestContainerResponse(t *testing.T) {
 	container := testContainer()
 
@@ -0,0 +1,52 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-ecs-agent/agent/engine"
+	v1 "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v1"
+	v2 "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v2"
+)
+
+// TaskEventsResolver returns the history of the tasks managed by the task engine
+type TaskEventsResolver interface {
+	TaskEvents(taskArn string) ([]engine.TaskEvent, bool)
+}
+
+// TaskEventsProviderImpl is an implementation of the TaskEventsProvider interface in the introspection
+// package. It provides the history of the tasks recorded by the task engine.
+type TaskEventsProviderImpl struct {
+	TaskEngine TaskEventsResolver
+}
+
+// GetTaskEvents returns the history of the task with a matching task Arn in v2 format.
+func (tp *TaskEventsProviderImpl) GetTaskEvents(taskArn string) (*v2.TaskEventsResponse, error) {
+	events, ok := tp.TaskEngine.TaskEvents(taskArn)
+	if !ok {
+		return nil, v1.NewErrorNotFound(fmt.Sprintf("no task found with arn %s", taskArn))
+	}
+	eventResponses := make([]*v2.TaskEventResponse, 0, len(events))
+	for _, event := range events {
+		eventResponses = append(eventResponses, &v2.TaskEventResponse{
+			Time:      event.Time.UTC(),
+			Type:      event.Type,
+			Container: event.Container,
+			Status:    event.Status,
+			Message:   event.Message,
+		})
+	}
+	return &v2.TaskEventsResponse{TaskArn: taskArn, Events: eventResponses}, nil
+}
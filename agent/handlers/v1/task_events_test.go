@@ -0,0 +1,64 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ecs-agent/agent/engine"
+	v1 "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v1"
+	v2 "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// taskEventsResolverFunc resolves the task events with a function
+type taskEventsResolverFunc func(taskArn string) ([]engine.TaskEvent, bool)
+
+func (f taskEventsResolverFunc) TaskEvents(taskArn string) ([]engine.TaskEvent, bool) {
+	return f(taskArn)
+}
+
+func TestGetTaskEvents(t *testing.T) {
+	const testTaskARN = "arn:aws:ecs:us-west-2:123456789012:task/cluster/task-id"
+	eventTime := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.FixedZone("test", 3600))
+	provider := &TaskEventsProviderImpl{
+		TaskEngine: taskEventsResolverFunc(func(taskArn string) ([]engine.TaskEvent, bool) {
+			if taskArn != testTaskARN {
+				return nil, false
+			}
+			return []engine.TaskEvent{
+				{Time: eventTime, Type: engine.TaskEventTypeTransition, Container: "app", Status: "RUNNING"},
+			}, true
+		}),
+	}
+
+	response, err := provider.GetTaskEvents(testTaskARN)
+	require.NoError(t, err)
+	assert.Equal(t, &v2.TaskEventsResponse{
+		TaskArn: testTaskARN,
+		Events: []*v2.TaskEventResponse{
+			{Time: eventTime.UTC(), Type: engine.TaskEventTypeTransition, Container: "app", Status: "RUNNING"},
+		},
+	}, response)
+
+	_, err = provider.GetTaskEvents("unknown")
+	var errNotFound *v1.ErrorNotFound
+	assert.ErrorAs(t, err, &errNotFound)
+}
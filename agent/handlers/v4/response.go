@@ -17,6 +17,7 @@ import (
 	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
 	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
 	"github.com/aws/amazon-ecs-agent/agent/engine/dockerstate"
+	v1 "github.com/aws/amazon-ecs-agent/agent/handlers/v1"
 	v2 "github.com/aws/amazon-ecs-agent/agent/handlers/v2"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/api/ecs"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
@@ -149,7 +150,12 @@ func augmentContainerResponse(
 	if dockerContainer.Container.RestartPolicyEnabled() {
 		restartCount := dockerContainer.Container.RestartTracker.GetRestartCount()
 		v4Response.RestartCount = &restartCount
+		if nextRestartAt := dockerContainer.Container.RestartTracker.GetNextRestartAt(); !nextRestartAt.IsZero() {
+			v4Response.NextRestartAt = &nextRestartAt
+		}
+		v4Response.RestartLimitReached = dockerContainer.Container.RestartTracker.IsRestartLimitReached()
 	}
+	v4Response.BlockedReason = v1.NewBlockedReasonResponse(dockerContainer.Container)
 	return v4Response
 }
 
@@ -195,5 +201,6 @@ func NewPulledContainerResponse(
 	resp := v2.NewContainerResponse(dockerContainer, eni, true)
 	return tmdsv4.ContainerResponse{
 		ContainerResponse: &resp,
+		BlockedReason:     v1.NewBlockedReasonResponse(dockerContainer.Container),
 	}
 }
@@ -24,10 +24,12 @@ import (
 	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
 	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
 	mock_dockerstate "github.com/aws/amazon-ecs-agent/agent/engine/dockerstate/mocks"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/api/container/restart"
 	apicontainerstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/container/status"
 	mock_ecs "github.com/aws/amazon-ecs-agent/ecs-agent/api/ecs/mocks"
 	apitaskstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/task/status"
 	ni "github.com/aws/amazon-ecs-agent/ecs-agent/netlib/model/networkinterface"
+	tmdsv4 "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/v4/state"
 
 	"github.com/docker/docker/api/types"
 	"github.com/golang/mock/gomock"
@@ -161,3 +163,63 @@ func TestNewTaskContainerResponses(t *testing.T) {
 	assert.Equal(t, "192.168.0.0/24", containerResponse.Networks[0].IPV4SubnetCIDRBlock)
 	assert.Equal(t, subnetGatewayIPV4Address, containerResponse.Networks[0].SubnetGatewayIPV4Address)
 }
+
+func TestAugmentContainerResponseRestartBackoff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	nextRestartAt := time.Now().Add(time.Minute)
+	container := &apicontainer.Container{
+		Name:          containerName,
+		RestartPolicy: &restart.RestartPolicy{Enabled: true, BackoffInitialPeriod: 60},
+		RestartTracker: &restart.RestartTracker{
+			RestartCount:  2,
+			NextRestartAt: nextRestartAt,
+		},
+	}
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	state.EXPECT().ContainerByID(containerID).Return(&apicontainer.DockerContainer{
+		DockerID:  containerID,
+		Container: container,
+	}, true).Times(2)
+
+	containerResponse := augmentContainerResponse(containerID, state, &tmdsv4.ContainerResponse{})
+	require.NotNil(t, containerResponse.RestartCount)
+	assert.Equal(t, 2, *containerResponse.RestartCount)
+	require.NotNil(t, containerResponse.NextRestartAt)
+	assert.Equal(t, nextRestartAt, *containerResponse.NextRestartAt)
+	assert.False(t, containerResponse.RestartLimitReached)
+
+	container.RestartTracker.RecordRestart()
+	container.RestartTracker.RestartLimitReached = true
+	containerResponse = augmentContainerResponse(containerID, state, &tmdsv4.ContainerResponse{})
+	assert.Nil(t, containerResponse.NextRestartAt)
+	assert.True(t, containerResponse.RestartLimitReached)
+}
+
+func TestAugmentContainerResponseBlockedReason(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	container := &apicontainer.Container{Name: containerName}
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	state.EXPECT().ContainerByID(containerID).Return(&apicontainer.DockerContainer{
+		DockerID:  containerID,
+		Container: container,
+	}, true).Times(2)
+
+	containerResponse := augmentContainerResponse(containerID, state, &tmdsv4.ContainerResponse{})
+	assert.Nil(t, containerResponse.BlockedReason)
+
+	container.SetBlockedReason(&apicontainer.BlockedReason{
+		Type:      apicontainer.BlockedOnContainer,
+		Container: "db",
+		Condition: "HEALTHY",
+	})
+	containerResponse = augmentContainerResponse(containerID, state, &tmdsv4.ContainerResponse{})
+	require.NotNil(t, containerResponse.BlockedReason)
+	assert.Equal(t, apicontainer.BlockedOnContainer, containerResponse.BlockedReason.Type)
+	assert.Equal(t, "db", containerResponse.BlockedReason.Container)
+	assert.Equal(t, "waiting for container db to be HEALTHY", containerResponse.BlockedReason.Message)
+	assert.False(t, containerResponse.BlockedReason.Since.IsZero())
+}
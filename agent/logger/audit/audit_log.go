@@ -43,6 +43,18 @@ func NewAuditLog(containerInstanceArn string, cfg *config.Config, logger InfoLog
 	}
 }
 
+// NewFaultAuditLog returns the audit logger recording the faults injected into tasks. Faults are recorded in
+// their own log, see FaultAuditLoggerConfig.
+func NewFaultAuditLog(containerInstanceArn string, cfg *config.Config,
+	logger InfoLogger) auditinterface.FaultAuditLogger {
+	return &auditLog{
+		cluster:              cfg.Cluster,
+		containerInstanceArn: containerInstanceArn,
+		logger:               logger,
+		cfg:                  cfg,
+	}
+}
+
 // Log will construct an audit log entry log and log that entry to the audit log
 // using the underlying logger (which implements the audit.InfoLogger interface).
 func (a *auditLog) Log(r request.LogRequest, httpResponseCode int, eventType string) {
@@ -54,6 +66,18 @@ func (a *auditLog) Log(r request.LogRequest, httpResponseCode int, eventType str
 	}
 }
 
+// LogFault will construct a fault audit log entry and log that entry to the audit log
+// using the underlying logger (which implements the audit.InfoLogger interface).
+func (a *auditLog) LogFault(r request.LogRequest, httpResponseCode int, eventType, faultType, parameters string) {
+	if !a.cfg.FaultAuditLogDisabled {
+		commonAuditLogFields := constructCommonAuditLogEntryFields(r, httpResponseCode)
+		faultAuditLogFields := constructFaultAuditLogEntry(eventType, a.GetCluster(), a.GetContainerInstanceArn(),
+			faultType, parameters)
+
+		a.logger.Info(fmt.Sprintf("%s %s", commonAuditLogFields, faultAuditLogFields))
+	}
+}
+
 func constructAuditLogEntry(r request.LogRequest, httpResponseCode int, eventType string,
 	cluster string, containerInstanceArn string) string {
 	commonAuditLogFields := constructCommonAuditLogEntryFields(r, httpResponseCode)
@@ -71,18 +95,28 @@ func (a *auditLog) GetContainerInstanceArn() string {
 }
 
 func AuditLoggerConfig(cfg *config.Config) string {
+	return auditLoggerConfig(cfg.CredentialsAuditLogFile)
+}
+
+// FaultAuditLoggerConfig returns the configuration of the logger writing the audit log of the faults injected
+// into tasks.
+func FaultAuditLoggerConfig(cfg *config.Config) string {
+	return auditLoggerConfig(cfg.FaultAuditLogFile)
+}
+
+func auditLoggerConfig(logFile string) string {
 	config := `
 <seelog type="asyncloop" minlevel="info">
 	<outputs formatid="main">
 		<console />`
-	if cfg.CredentialsAuditLogFile != "" {
+	if logFile != "" {
 		if logger.Config.RolloverType == "size" {
 			config += `
-		<rollingfile filename="` + cfg.CredentialsAuditLogFile + `" type="size"
+		<rollingfile filename="` + logFile + `" type="size"
 		 maxsize="` + strconv.Itoa(int(logger.Config.MaxFileSizeMB*1000000)) + `" archivetype="none" maxrolls="` + strconv.Itoa(logger.Config.MaxRollCount) + `" />`
 		} else {
 			config += `
-		<rollingfile filename="` + cfg.CredentialsAuditLogFile + `" type="date"
+		<rollingfile filename="` + logFile + `" type="date"
 		 datepattern="2006-01-02-15" archivetype="none" maxrolls="` + strconv.Itoa(logger.Config.MaxRollCount) + `" />`
 		}
 	}
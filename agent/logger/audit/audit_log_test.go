@@ -45,9 +45,12 @@ const (
 	dummyResponseCode         = 400
 	dummyRoleType             = "TaskExecution"
 	taskARN                   = "task-arn-1"
+	dummyFaultType            = "network-latency"
+	dummyFaultParameters      = `{"DelayMilliseconds":100,"JitterMilliseconds":10}`
 
 	commonAuditLogEntryFieldCount = 6
 	getCredentialsEntryFieldCount = 4
+	faultEntryFieldCount          = 6
 )
 
 func TestWritingToAuditLog(t *testing.T) {
@@ -228,3 +231,86 @@ func TestConstructAuditLogEntryByTypeUnknownType(t *testing.T) {
 	result := constructAuditLogEntryByType("unknownEvent", dummyCluster, dummyContainerInstanceArn)
 	assert.Equal(t, "", result, "unknown event type should not return an entry")
 }
+
+func TestWritingFaultsToAuditLog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockInfoLogger := mock_infologger.NewMockInfoLogger(ctrl)
+
+	req, _ := http.NewRequest("POST", "foo", nil)
+	req.RemoteAddr = dummyRemoteAddress
+	parsedURL, err := url.Parse(dummyURL)
+	if err != nil {
+		t.Fatal("error parsing dummyUrl")
+	}
+	req.URL = parsedURL
+	req.Header.Set("User-Agent", dummyUserAgent)
+
+	cfg := &config.Config{
+		Cluster:           dummyCluster,
+		FaultAuditLogFile: "foo.txt",
+		// The credentials audit log doesn't affect the fault audit log.
+		CredentialsAuditLogDisabled: true,
+	}
+
+	auditLogger := NewFaultAuditLog(dummyContainerInstanceArn, cfg, mockInfoLogger)
+
+	gomock.InOrder(
+		mockInfoLogger.EXPECT().Info(gomock.Any()).Do(func(logLine string) {
+			tokens := strings.Split(logLine, " ")
+			assert.Equal(t, commonAuditLogEntryFieldCount+faultEntryFieldCount, len(tokens),
+				"Incorrect number of tokens in fault audit log entry")
+			verifyCommonAuditLogEntryFieldResult(strings.Join(tokens[:commonAuditLogEntryFieldCount], " "),
+				taskARN, dummyURLPath, t)
+			verifyFaultAuditLogEntryResult(tokens[commonAuditLogEntryFieldCount:],
+				auditinterface.StartFaultEventType, strconv.Quote(dummyFaultParameters), t)
+		}),
+		mockInfoLogger.EXPECT().Info(gomock.Any()).Do(func(logLine string) {
+			tokens := strings.Split(logLine, " ")
+			assert.Equal(t, commonAuditLogEntryFieldCount+faultEntryFieldCount, len(tokens),
+				"Incorrect number of tokens in fault audit log entry")
+			assert.Equal(t, []string{"-", "-", "-", taskARN}, tokens[2:commonAuditLogEntryFieldCount],
+				"request fields should be empty for events not caused by a request")
+			verifyFaultAuditLogEntryResult(tokens[commonAuditLogEntryFieldCount:],
+				auditinterface.ExpireFaultEventType, "-", t)
+		}),
+	)
+
+	auditLogger.LogFault(request.LogRequest{Request: req, ARN: taskARN}, dummyResponseCode,
+		auditinterface.StartFaultEventType, dummyFaultType, dummyFaultParameters)
+	auditLogger.LogFault(request.LogRequest{ARN: taskARN}, http.StatusOK,
+		auditinterface.ExpireFaultEventType, dummyFaultType, "")
+}
+
+func TestWritingFaultsToAuditLogWhenDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockInfoLogger := mock_infologger.NewMockInfoLogger(ctrl)
+
+	req, _ := http.NewRequest("POST", "foo", nil)
+
+	cfg := &config.Config{
+		Cluster:               dummyCluster,
+		FaultAuditLogFile:     "foo.txt",
+		FaultAuditLogDisabled: true,
+	}
+
+	auditLogger := NewFaultAuditLog(dummyContainerInstanceArn, cfg, mockInfoLogger)
+
+	mockInfoLogger.EXPECT().Info(gomock.Any()).Times(0)
+
+	auditLogger.LogFault(request.LogRequest{Request: req, ARN: taskARN}, dummyResponseCode,
+		auditinterface.StopFaultEventType, dummyFaultType, dummyFaultParameters)
+}
+
+func verifyFaultAuditLogEntryResult(tokens []string, expectedEventType, expectedParameters string, t *testing.T) {
+	assert.Equal(t, expectedEventType, tokens[0], "event type does not match")
+	auditLogVersion, _ := strconv.Atoi(tokens[1])
+	assert.Equal(t, faultAuditLogVersion, auditLogVersion, "version does not match")
+	assert.Equal(t, dummyCluster, tokens[2], "cluster does not match")
+	assert.Equal(t, dummyContainerInstanceArn, tokens[3], "containerInstanceArn does not match")
+	assert.Equal(t, dummyFaultType, tokens[4], "fault type does not match")
+	assert.Equal(t, expectedParameters, tokens[5], "fault parameters do not match")
+}
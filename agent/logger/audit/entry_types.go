@@ -15,6 +15,7 @@ package audit
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -42,6 +43,22 @@ const (
 	// 7. event type ('GetCredentials, GetCredentialsExecutionRole')
 
 	getCredentialsAuditLogVersion = 2
+
+	// faultAuditLogVersion is the version of the fault audit log
+	// Version '1', the fields are:
+	// 1. event time
+	// 2. response code
+	// 3. source ip address, '-' for the events not caused by a request
+	// 4. url, '-' for the events not caused by a request
+	// 5. user agent, '-' for the events not caused by a request
+	// 6. task arn
+	// 7. event type ('StartFault', 'StopFault', 'ExpireFault', 'CleanupFault')
+	// 8. version
+	// 9. cluster
+	// 10. container instance arn
+	// 11. fault type
+	// 12. fault parameters
+	faultAuditLogVersion = 1
 )
 
 type commonAuditLogEntryFields struct {
@@ -68,8 +85,34 @@ func (g *getCredentialsAuditLogEntryFields) string() string {
 	return fmt.Sprintf("%s %d %s %s", g.eventType, g.version, g.cluster, g.containerInstanceArn)
 }
 
+type faultAuditLogEntryFields struct {
+	eventType            string
+	version              int
+	cluster              string
+	containerInstanceArn string
+	faultType            string
+	parameters           string
+}
+
+func (f *faultAuditLogEntryFields) string() string {
+	return fmt.Sprintf("%s %d %s %s %s %s", f.eventType, f.version, f.cluster, f.containerInstanceArn,
+		f.faultType, f.parameters)
+}
+
 func constructCommonAuditLogEntryFields(r request.LogRequest, httpResponseCode int) string {
 	httpRequest := r.Request
+	if httpRequest == nil {
+		// The event wasn't caused by a request
+		fields := &commonAuditLogEntryFields{
+			eventTime:    time.Now().UTC().Format(time.RFC3339),
+			responseCode: httpResponseCode,
+			srcAddr:      populateField(""),
+			theURL:       populateField(""),
+			userAgent:    populateField(""),
+			arn:          populateField(r.ARN),
+		}
+		return fields.string()
+	}
 	url := httpRequest.URL.Path
 	// V2CredentialsPath contains the credentials ID, which should not be logged
 	if strings.HasPrefix(url, credentials.V2CredentialsPath+"/") {
@@ -110,6 +153,21 @@ func constructAuditLogEntryByType(eventType string, cluster string, containerIns
 	}
 }
 
+func constructFaultAuditLogEntry(eventType, cluster, containerInstanceArn, faultType, parameters string) string {
+	fields := &faultAuditLogEntryFields{
+		eventType:            eventType,
+		version:              faultAuditLogVersion,
+		cluster:              populateField(cluster),
+		containerInstanceArn: populateField(containerInstanceArn),
+		faultType:            populateField(faultType),
+		parameters:           populateField(parameters),
+	}
+	if parameters != "" {
+		fields.parameters = strconv.Quote(parameters)
+	}
+	return fields.string()
+}
+
 func populateField(logField string) string {
 	if logField == "" {
 		logField = "-"
@@ -0,0 +1,45 @@
+//go:build !windows
+// +build !windows
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sighandlers
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/cihub/seelog"
+)
+
+// StartReloadHandler calls reload every time the agent receives SIGHUP, until the context is
+// cancelled
+func StartReloadHandler(ctx context.Context, reload func()) {
+	signalChannel := make(chan os.Signal, 1)
+	signal.Notify(signalChannel, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(signalChannel)
+		for {
+			select {
+			case <-signalChannel:
+				seelog.Info("Agent received SIGHUP, reloading the configuration")
+				reload()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
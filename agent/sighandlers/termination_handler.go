@@ -14,7 +14,7 @@
 // Package sighandlers handle signals and behave appropriately.
 // SIGTERM:
 //
-//	Flush state to disk and exit
+//	Flush state to disk and exit, optionally draining the container instance first
 //
 // SIGUSR1:
 //
@@ -34,6 +34,7 @@ import (
 	"github.com/aws/amazon-ecs-agent/agent/engine/dockerstate"
 	"github.com/aws/amazon-ecs-agent/agent/sighandlers/exitcodes"
 	apierrors "github.com/aws/amazon-ecs-agent/ecs-agent/api/errors"
+	apitaskstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/task/status"
 
 	"github.com/cihub/seelog"
 )
@@ -43,6 +44,9 @@ const (
 	finalSaveTimeout     = 3 * time.Second
 )
 
+// drainingPollInterval is the interval at which the tasks are checked while waiting for them to be stopped
+var drainingPollInterval = 5 * time.Second
+
 // TerminationHandler defines a handler used for terminating the agent
 type TerminationHandler func(state dockerstate.TaskEngineState, dataClient data.Client, taskEngine engine.TaskEngine, cancel context.CancelFunc)
 
@@ -56,6 +60,93 @@ func StartDefaultTerminationHandler(state dockerstate.TaskEngineState, dataClien
 	sig := <-signalC
 	seelog.Infof("Agent received termination signal: %s", sig.String())
 
+	finalSaveAndCancel(state, dataClient, taskEngine, cancel)
+}
+
+// InstanceDrainer sets the state of the container instance to DRAINING, so that ECS stops its tasks
+// and doesn't place new tasks on it.
+type InstanceDrainer func() error
+
+// NewDrainingTerminationHandler returns a termination handler which drains the container instance
+// before the final save when the instance is shutting down. When a termination signal is received, the
+// state is saved first, so that it isn't lost if the agent is killed while draining. The container
+// instance is then set to DRAINING and the handler waits until all the tasks started by ECS are stopped,
+// the draining timeout expires, or another termination signal is received. If the instance isn't
+// shutting down, the agent is only being restarted and the handler behaves like the default one.
+func NewDrainingTerminationHandler(drainInstance InstanceDrainer, shuttingDown func() bool,
+	drainingTimeout time.Duration) TerminationHandler {
+	return func(state dockerstate.TaskEngineState, dataClient data.Client, taskEngine engine.TaskEngine, cancel context.CancelFunc) {
+		signalC := make(chan os.Signal, 2)
+		signal.Notify(signalC, os.Interrupt, syscall.SIGTERM)
+
+		sig := <-signalC
+		if !shuttingDown() {
+			seelog.Infof("Agent received termination signal: %s, not draining the container instance as the instance isn't shutting down",
+				sig.String())
+			finalSaveAndCancel(state, dataClient, taskEngine, cancel)
+			return
+		}
+		seelog.Infof("Agent received termination signal: %s, draining the container instance", sig.String())
+
+		saveAndDrain(state, dataClient, drainInstance, drainingTimeout, signalC)
+		finalSaveAndCancel(state, dataClient, taskEngine, cancel)
+	}
+}
+
+// saveAndDrain saves the state, so that it isn't lost if the agent is killed before the final save, and then
+// drains the container instance
+func saveAndDrain(state dockerstate.TaskEngineState, dataClient data.Client, drainInstance InstanceDrainer,
+	drainingTimeout time.Duration, signalC <-chan os.Signal) {
+	seelog.Debug("Saving state before draining the container instance")
+	saveStateAll(state, dataClient)
+	drainAndWait(state, drainInstance, drainingTimeout, signalC)
+}
+
+// drainAndWait drains the container instance and waits for the tasks started by ECS to be stopped
+func drainAndWait(state dockerstate.TaskEngineState, drainInstance InstanceDrainer, drainingTimeout time.Duration,
+	signalC <-chan os.Signal) {
+	if err := drainInstance(); err != nil {
+		seelog.Errorf("Unable to set the container instance to DRAINING, exiting without waiting for the tasks: %v", err)
+		return
+	}
+
+	timeout := time.NewTimer(drainingTimeout)
+	defer timeout.Stop()
+	ticker := time.NewTicker(drainingPollInterval)
+	defer ticker.Stop()
+	for {
+		running := runningTasks(state)
+		if running == 0 {
+			seelog.Info("All tasks stopped after draining the container instance")
+			return
+		}
+		seelog.Infof("Waiting for %d tasks to stop after draining the container instance", running)
+		select {
+		case <-timeout.C:
+			seelog.Warnf("Timed out after %s waiting for %d tasks to stop after draining the container instance",
+				drainingTimeout.String(), running)
+			return
+		case sig := <-signalC:
+			seelog.Warnf("Agent received termination signal: %s, no longer waiting for the tasks to stop", sig.String())
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runningTasks returns the number of tasks started by ECS which aren't stopped yet
+func runningTasks(state dockerstate.TaskEngineState) int {
+	running := 0
+	for _, task := range state.AllExternalTasks() {
+		if task.GetKnownStatus() < apitaskstatus.TaskStopped {
+			running++
+		}
+	}
+	return running
+}
+
+func finalSaveAndCancel(state dockerstate.TaskEngineState, dataClient data.Client, taskEngine engine.TaskEngine,
+	cancel context.CancelFunc) {
 	err := FinalSave(state, dataClient, taskEngine)
 	if err != nil {
 		seelog.Criticalf("Error saving state before final shutdown: %v", err)
@@ -17,7 +17,11 @@
 package sighandlers
 
 import (
+	"errors"
+	"os"
+	"syscall"
 	"testing"
+	"time"
 
 	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
 	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
@@ -27,6 +31,7 @@ import (
 	"github.com/aws/amazon-ecs-agent/agent/engine/dockerstate"
 	"github.com/aws/amazon-ecs-agent/agent/engine/image"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/api/attachment"
+	apitaskstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/task/status"
 	ni "github.com/aws/amazon-ecs-agent/ecs-agent/netlib/model/networkinterface"
 
 	"github.com/stretchr/testify/assert"
@@ -100,6 +105,90 @@ func TestFinalSave(t *testing.T) {
 	assert.Len(t, imageStates, 1)
 }
 
+func TestDrainAndWait(t *testing.T) {
+	defer func(interval time.Duration) {
+		drainingPollInterval = interval
+	}(drainingPollInterval)
+	drainingPollInterval = 10 * time.Millisecond
+
+	newState := func() (dockerstate.TaskEngineState, *apitask.Task) {
+		state := dockerstate.NewTaskEngineState()
+		task := &apitask.Task{Arn: taskARN, KnownStatusUnsafe: apitaskstatus.TaskRunning}
+		state.AddTask(task)
+		state.AddTask(&apitask.Task{
+			Arn:               "arn:aws:ecs:region:account-id:task/internal-task-id",
+			KnownStatusUnsafe: apitaskstatus.TaskRunning,
+			IsInternal:        true,
+		})
+		return state, task
+	}
+
+	t.Run("tasks stopped", func(t *testing.T) {
+		state, task := newState()
+		drained := false
+		drainInstance := func() error {
+			drained = true
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				task.SetKnownStatus(apitaskstatus.TaskStopped)
+			}()
+			return nil
+		}
+		done := make(chan struct{})
+		go func() {
+			drainAndWait(state, drainInstance, time.Minute, make(chan os.Signal))
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for the tasks to stop")
+		}
+		assert.True(t, drained)
+		assert.Equal(t, 0, runningTasks(state))
+	})
+
+	t.Run("draining timeout", func(t *testing.T) {
+		state, _ := newState()
+		start := time.Now()
+		drainAndWait(state, func() error { return nil }, 100*time.Millisecond, make(chan os.Signal))
+		assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+		assert.Equal(t, 1, runningTasks(state))
+	})
+
+	t.Run("second termination signal", func(t *testing.T) {
+		state, _ := newState()
+		signalC := make(chan os.Signal, 1)
+		signalC <- syscall.SIGTERM
+		start := time.Now()
+		drainAndWait(state, func() error { return nil }, time.Minute, signalC)
+		assert.Less(t, time.Since(start), time.Minute)
+	})
+
+	t.Run("draining error", func(t *testing.T) {
+		state, _ := newState()
+		start := time.Now()
+		drainAndWait(state, func() error { return errors.New("error") }, time.Minute, make(chan os.Signal))
+		assert.Less(t, time.Since(start), time.Minute)
+	})
+}
+
+func TestSaveAndDrain(t *testing.T) {
+	dataClient := newTestDataClient(t)
+	state := dockerstate.NewTaskEngineState()
+	state.AddTask(&apitask.Task{Arn: taskARN, KnownStatusUnsafe: apitaskstatus.TaskStopped})
+
+	savedBeforeDraining := false
+	drainInstance := func() error {
+		tasks, err := dataClient.GetTasks()
+		require.NoError(t, err)
+		savedBeforeDraining = len(tasks) == 1
+		return nil
+	}
+	saveAndDrain(state, dataClient, drainInstance, time.Minute, make(chan os.Signal))
+	assert.True(t, savedBeforeDraining, "state should be saved before draining the container instance")
+}
+
 func newTestDataClient(t *testing.T) data.Client {
 	testDir := t.TempDir()
 
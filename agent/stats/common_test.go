@@ -359,6 +359,9 @@ func (engine *MockTaskEngine) StateChangeEvents() chan statechange.Event {
 func (engine *MockTaskEngine) SetDataClient(data.Client) {
 }
 
+func (engine *MockTaskEngine) SetTaskFaultCleaner(func(taskARN string)) {
+}
+
 func (engine *MockTaskEngine) AddTask(*apitask.Task) {
 }
 
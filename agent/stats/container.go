@@ -27,7 +27,6 @@ import (
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
 	loggerfield "github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/utils/retry"
-	"github.com/cihub/seelog"
 	"github.com/pkg/errors"
 )
 
@@ -36,7 +35,11 @@ func newStatsContainer(dockerID string, client dockerapi.DockerClient, resolver
 	if err != nil {
 		return nil, err
 	}
-	ctx, cancel := context.WithCancel(context.Background())
+	// The log messages about the container carry its name and runtime ID through the context
+	ctx, cancel := context.WithCancel(logger.ContextWithFields(context.Background(), logger.Fields{
+		loggerfield.ContainerName: dockerContainer.Container.Name,
+		loggerfield.RuntimeID:     dockerID,
+	}))
 	return &StatsContainer{
 		containerMetadata: &ContainerMetadata{
 			DockerID:    dockerID,
@@ -73,22 +76,20 @@ func (container *StatsContainer) StopStatsCollection() {
 }
 
 func (container *StatsContainer) collect() {
-	dockerID := container.containerMetadata.DockerID
 	backoff := retry.NewExponentialBackoff(time.Second*1, time.Second*10, 0.5, 2)
 	for {
 		err := container.processStatsStream()
 
 		select {
 		case <-container.ctx.Done():
-			logger.Info("Stopping container stats collection", logger.Fields{"runtimeID": dockerID})
+			logger.InfoCtx(container.ctx, "Stopping container stats collection")
 			return
 		default:
 			if err != nil {
 				d := backoff.Duration()
-				logger.Debug(fmt.Sprintf(
+				logger.DebugCtx(container.ctx, fmt.Sprintf(
 					"Error processing stats stream of container, backing off %s before reopening", d), logger.Fields{
-					loggerfield.DockerId: dockerID,
-					loggerfield.Error:    err,
+					loggerfield.Error: err,
 				})
 				time.Sleep(d)
 			}
@@ -103,11 +104,14 @@ func (container *StatsContainer) collect() {
 				// docker task engine. If the docker task engine has already removed
 				// the container from its state, there's no point in stats engine tracking the
 				// container. So, clean-up anyway.
-				seelog.Warnf("Container [%s]: Error determining if the container is terminal, stopping stats collection: %v", dockerID, err)
+				logger.WarnCtx(container.ctx, "Error determining if the container is terminal, stopping stats collection",
+					logger.Fields{
+						loggerfield.Error: err,
+					})
 				container.StopStatsCollection()
 				return
 			} else if terminal {
-				logger.Info("Container is terminal, stopping stats collection", logger.Fields{"runtimeID": dockerID})
+				logger.InfoCtx(container.ctx, "Container is terminal, stopping stats collection")
 				container.StopStatsCollection()
 				return
 			}
@@ -140,7 +144,7 @@ func getNonDockerContainerStats(apiContainer *apicontainer.Container) NonDockerC
 
 func (container *StatsContainer) processStatsStream() error {
 	dockerID := container.containerMetadata.DockerID
-	seelog.Debugf("Collecting stats for container %s", dockerID)
+	logger.DebugCtx(container.ctx, "Collecting stats for container")
 	if container.client == nil {
 		return errors.New("container processStatsStream: Client is not set.")
 	}
@@ -148,10 +152,9 @@ func (container *StatsContainer) processStatsStream() error {
 
 	apiContainer, err := container.getApiContainer(dockerID)
 	if apiContainer == nil && err != nil {
-		logger.Error("apiContainer is nil - will not be able to get restart stats set or determine "+
+		logger.ErrorCtx(container.ctx, "apiContainer is nil - will not be able to get restart stats set or determine "+
 			"if container has restart policy enabled", logger.Fields{
-			loggerfield.DockerId: dockerID,
-			loggerfield.Error:    err,
+			loggerfield.Error: err,
 		})
 	}
 
@@ -165,7 +168,10 @@ func (container *StatsContainer) processStatsStream() error {
 			case <-container.ctx.Done():
 				// ignore error when container.ctx.Done()
 			default:
-				seelog.Warnf("Error encountered processing metrics stream from docker, this may affect cloudwatch metric accuracy: %s", err)
+				logger.WarnCtx(container.ctx, "Error encountered processing metrics stream from docker, "+
+					"this may affect cloudwatch metric accuracy", logger.Fields{
+					loggerfield.Error: err,
+				})
 				returnError = true
 			}
 		case rawStat, ok := <-dockerStats:
@@ -189,16 +195,17 @@ func (container *StatsContainer) processStatsStream() error {
 			err = container.statsQueue.AddContainerStat(rawStat, getNonDockerContainerStats(apiContainer),
 				&container.restartAggregationData.LastStatBeforeLastRestart, container.hasRestartedBefore())
 			if err != nil {
-				seelog.Warnf("Container [%s]: error converting stats for container: %v", dockerID, err)
+				logger.WarnCtx(container.ctx, "Error converting stats for container", logger.Fields{
+					loggerfield.Error: err,
+				})
 				continue
 			}
 			if isFirstStatAfterContainerRestart {
 				err = container.saveRestartAggregationData(apiContainer)
 				if err != nil {
-					logger.Error("Failed to update container's stats restart aggregation data in database",
+					logger.ErrorCtx(container.ctx, "Failed to update container's stats restart aggregation data in database",
 						logger.Fields{
-							loggerfield.DockerId: dockerID,
-							loggerfield.Error:    err,
+							loggerfield.Error: err,
 						})
 				}
 			}
@@ -226,9 +233,7 @@ func (container *StatsContainer) syncContainerRestartAggregationData() bool {
 	if restartDetected {
 		container.restartAggregationData.LastRestartDetectedAt = time.Now()
 
-		logger.Debug("Received first stat for container after a container restart", logger.Fields{
-			loggerfield.DockerId: container.containerMetadata.DockerID,
-		})
+		logger.DebugCtx(container.ctx, "Received first stat for container after a container restart")
 
 		lastStat := container.statsQueue.GetLastStat()
 		if lastStat != nil {
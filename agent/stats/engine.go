@@ -28,7 +28,6 @@ import (
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/utils"
 
-	"github.com/cihub/seelog"
 	"github.com/pborman/uuid"
 	"github.com/pkg/errors"
 
@@ -175,6 +174,19 @@ func NewDockerStatsEngine(cfg *config.Config, client dockerapi.DockerClient, con
 	}
 }
 
+// UpdatePollingMetricsConfig applies the metrics polling settings of a configuration reloaded
+// while the agent is running. The settings apply to the containers added to the stats engine
+// afterwards.
+func (engine *DockerStatsEngine) UpdatePollingMetricsConfig(cfg *config.Config) {
+	engine.lock.Lock()
+	defer engine.lock.Unlock()
+	// The containers being watched keep a reference to the previous config
+	updated := *engine.config
+	updated.PollMetrics = cfg.PollMetrics
+	updated.PollingMetricsWaitDuration = cfg.PollingMetricsWaitDuration
+	engine.config = &updated
+}
+
 // synchronizeState goes through all the containers on the instance to synchronize the state on agent start
 func (engine *DockerStatsEngine) synchronizeState() error {
 	listContainersResponse := engine.client.ListContainers(engine.ctx, false, dockerclient.ListContainersTimeout)
@@ -235,6 +247,15 @@ func (engine *DockerStatsEngine) addAndStartStatsContainer(containerID string) {
 
 }
 
+// taskLogContext returns the context carrying the log fields of the task
+func (engine *DockerStatsEngine) taskLogContext(taskARN string) context.Context {
+	ctx := engine.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return logger.ContextWithFields(ctx, logger.Fields{field.TaskARN: taskARN})
+}
+
 // MustInit initializes fields of the DockerStatsEngine object.
 func (engine *DockerStatsEngine) MustInit(ctx context.Context, taskEngine ecsengine.TaskEngine, cluster string, containerInstanceArn string) error {
 	derivedCtx, cancel := context.WithCancel(ctx)
@@ -341,7 +362,10 @@ func (engine *DockerStatsEngine) addTaskToServiceConnectStatsUnsafe(taskArn stri
 	if !taskExists {
 		serviceConnectStats, err := newServiceConnectStats()
 		if err != nil {
-			seelog.Errorf("Error adding task %s to the service connect stats watchlist : %v", taskArn, err)
+			logger.ErrorCtx(engine.taskLogContext(taskArn), "Error adding task to the service connect stats watchlist",
+				logger.Fields{
+					field.Error: err,
+				})
 			return
 		}
 		engine.taskToServiceConnectStats[taskArn] = serviceConnectStats
@@ -370,12 +394,17 @@ func (engine *DockerStatsEngine) addContainerUnsafe(dockerID string) (*StatsCont
 		return nil, nil, errors.Wrapf(err, "could not map docker container ID to container, ignoring container: %s", dockerID)
 	}
 
-	seelog.Debugf("Adding container to stats watch list, id: %s, task: %s", dockerID, task.Arn)
+	logger.DebugCtx(engine.taskLogContext(task.Arn), "Adding container to stats watch list", logger.Fields{
+		field.RuntimeID: dockerID,
+	})
 	engine.tasksToDefinitions[task.Arn] = &taskDefinition{family: task.Family, version: task.Version}
 
 	dockerContainer, errResolveContainer := engine.resolver.ResolveContainer(dockerID)
 	if errResolveContainer != nil {
-		seelog.Debugf("Could not map container ID to container, container: %s, err: %s", dockerID, err)
+		logger.DebugCtx(engine.taskLogContext(task.Arn), "Could not map container ID to container", logger.Fields{
+			field.RuntimeID: dockerID,
+			field.Error:     errResolveContainer,
+		})
 	}
 
 	watchStatsContainer := false
@@ -391,7 +420,9 @@ func (engine *DockerStatsEngine) addContainerUnsafe(dockerID string) (*StatsCont
 	if errResolveContainer == nil && dockerContainer.Container.HealthStatusShouldBeReported() {
 		// Track the container health status
 		engine.addToStatsContainerMapUnsafe(task.Arn, dockerID, statsContainer, engine.healthCheckContainerMapUnsafe)
-		seelog.Debugf("Adding container to stats health check watch list, id: %s, task: %s", dockerID, task.Arn)
+		logger.DebugCtx(engine.taskLogContext(task.Arn), "Adding container to stats health check watch list", logger.Fields{
+			field.RuntimeID: dockerID,
+		})
 	}
 
 	if errResolveContainer == nil && task.GetServiceConnectContainer() == dockerContainer.Container {
@@ -428,7 +459,9 @@ func (engine *DockerStatsEngine) addToStatsContainerMapUnsafe(
 		_, containerExists := taskToContainerMap[taskARN][containerID]
 		if containerExists {
 			// container arn exists in map.
-			seelog.Debugf("Container already being watched, ignoring, id: %s", containerID)
+			logger.DebugCtx(engine.taskLogContext(taskARN), "Container already being watched, ignoring", logger.Fields{
+				field.RuntimeID: containerID,
+			})
 			return false
 		}
 	} else {
@@ -443,7 +476,7 @@ func (engine *DockerStatsEngine) addToStatsContainerMapUnsafe(
 // StartMetricsPublish starts to collect and publish task and health metrics
 func (engine *DockerStatsEngine) StartMetricsPublish() {
 	if engine.publishMetricsTicker == nil {
-		seelog.Debug("Skipping reporting metrics through channel. Publish ticker is uninitialized")
+		logger.Debug("Skipping reporting metrics through channel. Publish ticker is uninitialized")
 		return
 	}
 
@@ -463,9 +496,9 @@ func (engine *DockerStatsEngine) StartMetricsPublish() {
 		engine.SetPublishServiceConnectTickerInterval(metricCounter)
 		select {
 		case <-engine.publishMetricsTicker.C:
-			seelog.Debugf("publishMetricsTicker triggered. Sending telemetry messages to tcsClient through channel")
+			logger.Debug("publishMetricsTicker triggered. Sending telemetry messages to tcsClient through channel")
 			if includeServiceConnectStats {
-				seelog.Debugf("service connect metrics included")
+				logger.Debug("service connect metrics included")
 			}
 			go engine.publishMetrics(includeServiceConnectStats)
 			go engine.publishHealth()
@@ -486,12 +519,14 @@ func (engine *DockerStatsEngine) publishMetrics(includeServiceConnectStats bool)
 		}
 		select {
 		case engine.metricsChannel <- metricsMessage:
-			seelog.Debugf("sent telemetry message")
+			logger.Debug("sent telemetry message")
 		case <-publishMetricsCtx.Done():
-			seelog.Errorf("timeout sending telemetry message, discarding metrics")
+			logger.Error("timeout sending telemetry message, discarding metrics")
 		}
 	} else {
-		seelog.Warnf("Error collecting task metrics: %v", metricsErr)
+		logger.Warn("Error collecting task metrics", logger.Fields{
+			field.Error: metricsErr,
+		})
 	}
 }
 
@@ -506,12 +541,14 @@ func (engine *DockerStatsEngine) publishHealth() {
 		}
 		select {
 		case engine.healthChannel <- healthMessage:
-			seelog.Debugf("sent health message")
+			logger.Debug("sent health message")
 		case <-publishHealthCtx.Done():
-			seelog.Errorf("timeout sending health message, discarding metrics")
+			logger.Error("timeout sending health message, discarding metrics")
 		}
 	} else {
-		seelog.Warnf("Error collecting health metrics: %v", healthErr)
+		logger.Warn("Error collecting health metrics", logger.Fields{
+			field.Error: healthErr,
+		})
 	}
 }
 
@@ -527,7 +564,7 @@ func (engine *DockerStatsEngine) GetInstanceMetrics(includeServiceConnectStats b
 
 	var taskMetrics []*ecstcs.TaskMetric
 	if idle {
-		seelog.Debug("Instance is idle. No task metrics to report")
+		logger.Debug("Instance is idle. No task metrics to report")
 		fin := true
 		metricsMetadata.Fin = &fin
 		return metricsMetadata, taskMetrics, nil
@@ -539,7 +576,9 @@ func (engine *DockerStatsEngine) GetInstanceMetrics(includeServiceConnectStats b
 	if includeServiceConnectStats {
 		err := engine.getServiceConnectStats()
 		if err != nil {
-			seelog.Errorf("Error getting service connect metrics: %v", err)
+			logger.Error("Error getting service connect metrics", logger.Fields{
+				field.Error: err,
+			})
 		}
 	}
 
@@ -548,7 +587,9 @@ func (engine *DockerStatsEngine) GetInstanceMetrics(includeServiceConnectStats b
 		_, isServiceConnectTask := engine.taskToServiceConnectStats[taskArn]
 		containerMetrics, err := engine.taskContainerMetricsUnsafe(taskArn)
 		if err != nil {
-			seelog.Debugf("Error getting container metrics for task: %s, err: %v", taskArn, err)
+			logger.DebugCtx(engine.taskLogContext(taskArn), "Error getting container metrics for task", logger.Fields{
+				field.Error: err,
+			})
 			// skip collecting service connect related metrics, if task is not service connect enabled.
 			// when task metrics and health metrics are both disabled and there is a service connect task,
 			// and we should not include service connect this time, we also need to skip following execution
@@ -559,7 +600,7 @@ func (engine *DockerStatsEngine) GetInstanceMetrics(includeServiceConnectStats b
 		}
 
 		if len(containerMetrics) == 0 {
-			seelog.Debugf("Empty containerMetrics for task, ignoring, task: %s", taskArn)
+			logger.DebugCtx(engine.taskLogContext(taskArn), "Empty containerMetrics for task, ignoring")
 			// skip collecting service connect related metrics, if task is not service connect enabled.
 			// when task metrics and health metrics are both disabled and there is a service connect task,
 			// and we should not include service connect this time, we also need to skip following execution
@@ -571,7 +612,7 @@ func (engine *DockerStatsEngine) GetInstanceMetrics(includeServiceConnectStats b
 
 		taskDef, exists := engine.tasksToDefinitions[taskArn]
 		if !exists {
-			seelog.Debugf("Could not map task to definition, task: %s", taskArn)
+			logger.DebugCtx(engine.taskLogContext(taskArn), "Could not map task to definition")
 			continue
 		}
 		volMetrics := engine.getEBSVolumeMetrics(taskArn)
@@ -588,7 +629,7 @@ func (engine *DockerStatsEngine) GetInstanceMetrics(includeServiceConnectStats b
 			if serviceConnectStats, ok := engine.taskToServiceConnectStats[taskArn]; ok {
 				if !serviceConnectStats.HasStatsBeenSent() {
 					taskMetric.ServiceConnectMetricsWrapper = serviceConnectStats.GetStats()
-					seelog.Debugf("Adding service connect stats for task : %s", taskArn)
+					logger.DebugCtx(engine.taskLogContext(taskArn), "Adding service connect stats for task")
 					serviceConnectStats.SetStatsSent(true)
 				}
 			}
@@ -598,7 +639,7 @@ func (engine *DockerStatsEngine) GetInstanceMetrics(includeServiceConnectStats b
 
 	if len(taskMetrics) == 0 {
 		// Not idle. Expect taskMetrics to be there.
-		seelog.Debugf("Return empty metrics error")
+		logger.Debug("Return empty metrics error")
 		return nil, nil, EmptyMetricsError
 	}
 
@@ -685,13 +726,13 @@ func (engine *DockerStatsEngine) getTaskHealthUnsafe(taskARN string) *ecstcs.Tas
 	// Acquire the task definition information
 	taskDefinition, ok := engine.tasksToDefinitions[taskARN]
 	if !ok {
-		seelog.Debugf("Could not map task to definitions, task: %s", taskARN)
+		logger.DebugCtx(engine.taskLogContext(taskARN), "Could not map task to definitions")
 		return nil
 	}
 	// Check all the stats container for the task
 	containers, ok := engine.tasksToHealthCheckContainers[taskARN]
 	if !ok {
-		seelog.Debugf("Could not map task to health containers, task: %s", taskARN)
+		logger.DebugCtx(engine.taskLogContext(taskARN), "Could not map task to health containers")
 		return nil
 	}
 
@@ -705,7 +746,7 @@ func (engine *DockerStatsEngine) getTaskHealthUnsafe(taskARN string) *ecstcs.Tas
 		}
 		dockerContainer, err := engine.resolver.ResolveContainer(container.containerMetadata.DockerID)
 		if err != nil {
-			seelog.Debugf("Could not resolve the Docker ID in agent state: %s", container.containerMetadata.DockerID)
+			logger.DebugCtx(container.ctx, "Could not resolve the Docker ID in agent state")
 			continue
 		}
 
@@ -756,8 +797,10 @@ func (engine *DockerStatsEngine) handleDockerEvents(events ...interface{}) error
 		case apicontainerstatus.ContainerStopped:
 			engine.removeContainer(dockerContainerChangeEvent.DockerID)
 		default:
-			seelog.Debugf("Ignoring event for container, id: %s, status: %d",
-				dockerContainerChangeEvent.DockerID, dockerContainerChangeEvent.Status)
+			logger.Debug("Ignoring event for container", logger.Fields{
+				field.RuntimeID: dockerContainerChangeEvent.DockerID,
+				field.Status:    dockerContainerChangeEvent.Status.String(),
+			})
 		}
 	}
 
@@ -773,13 +816,18 @@ func (engine *DockerStatsEngine) removeContainer(dockerID string) {
 	// Make sure that this container belongs to a task.
 	task, err := engine.resolver.ResolveTask(dockerID)
 	if err != nil {
-		seelog.Debugf("Could not map container to task, ignoring, err: %v, id: %s", err, dockerID)
+		logger.Debug("Could not map container to task, ignoring", logger.Fields{
+			field.RuntimeID: dockerID,
+			field.Error:     err,
+		})
 		return
 	}
 
 	_, taskExists := engine.tasksToContainers[task.Arn]
 	if !taskExists {
-		seelog.Debugf("Container not being watched, id: %s", dockerID)
+		logger.DebugCtx(engine.taskLogContext(task.Arn), "Container not being watched", logger.Fields{
+			field.RuntimeID: dockerID,
+		})
 		return
 	}
 
@@ -787,7 +835,9 @@ func (engine *DockerStatsEngine) removeContainer(dockerID string) {
 	container, containerExists := engine.tasksToContainers[task.Arn][dockerID]
 	if !containerExists {
 		// container arn does not exist in map.
-		seelog.Debugf("Container not being watched, id: %s", dockerID)
+		logger.DebugCtx(engine.taskLogContext(task.Arn), "Container not being watched", logger.Fields{
+			field.RuntimeID: dockerID,
+		})
 		return
 	}
 
@@ -905,7 +955,7 @@ func (engine *DockerStatsEngine) taskContainerMetricsUnsafe(taskArn string) ([]*
 				// send network stats for default/bridge/nat/awsvpc network modes
 				if task.IsNetworkModeBridge() {
 					if task.IsServiceConnectEnabled() && dockerContainer.Container.Type == apicontainer.ContainerCNIPause {
-						seelog.Debug("Skip adding network stats for pause container in Service Connect enabled task")
+						logger.DebugCtx(container.ctx, "Skip adding network stats for pause container in Service Connect enabled task")
 					} else {
 						networkStatsSet, err := container.statsQueue.GetNetworkStatsSet()
 						if err != nil && age > gracePeriod {
@@ -948,7 +998,7 @@ func (engine *DockerStatsEngine) doRemoveContainerUnsafe(container *StatsContain
 	container.StopStatsCollection()
 	dockerID := container.containerMetadata.DockerID
 	delete(engine.tasksToContainers[taskArn], dockerID)
-	seelog.Debugf("Deleted container from tasks, id: %s", dockerID)
+	logger.DebugCtx(container.ctx, "Deleted container from tasks")
 
 	if len(engine.tasksToContainers[taskArn]) == 0 {
 		// No containers in task, delete task arn from map.
@@ -956,11 +1006,11 @@ func (engine *DockerStatsEngine) doRemoveContainerUnsafe(container *StatsContain
 		// No need to verify if the key exists in tasksToDefinitions.
 		// Delete will do nothing if the specified key doesn't exist.
 		delete(engine.tasksToDefinitions, taskArn)
-		seelog.Debugf("Deleted task from tasks, arn: %s", taskArn)
+		logger.DebugCtx(engine.taskLogContext(taskArn), "Deleted task from tasks")
 
 		// Stop collecting service connect stats for this task
 		delete(engine.taskToServiceConnectStats, taskArn)
-		seelog.Debugf("Deleted task from the Service Connect stats watchlist, task ARN: %s", taskArn)
+		logger.DebugCtx(engine.taskLogContext(taskArn), "Deleted task from the Service Connect stats watchlist")
 	}
 
 	// Remove the container from health container watch list
@@ -971,7 +1021,7 @@ func (engine *DockerStatsEngine) doRemoveContainerUnsafe(container *StatsContain
 	delete(engine.tasksToHealthCheckContainers[taskArn], dockerID)
 	if len(engine.tasksToHealthCheckContainers[taskArn]) == 0 {
 		delete(engine.tasksToHealthCheckContainers, taskArn)
-		seelog.Debugf("Deleted task from container health watch list, arn: %s", taskArn)
+		logger.DebugCtx(engine.taskLogContext(taskArn), "Deleted task from container health watch list")
 	}
 }
 
@@ -950,3 +950,19 @@ func TestFetchEBSVolumeMetrics(t *testing.T) {
 	}
 
 }
+
+func TestUpdatePollingMetricsConfig(t *testing.T) {
+	testConfig := cfg
+	engine := NewDockerStatsEngine(&testConfig, nil, eventStream("TestUpdatePollingMetricsConfig"), nil, nil, nil)
+
+	reloaded := cfg
+	reloaded.PollMetrics = config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled}
+	reloaded.PollingMetricsWaitDuration = 20 * time.Second
+	reloaded.Cluster = "other-cluster"
+	engine.UpdatePollingMetricsConfig(&reloaded)
+
+	assert.True(t, engine.config.PollMetrics.Enabled())
+	assert.Equal(t, 20*time.Second, engine.config.PollingMetricsWaitDuration)
+	assert.Equal(t, cfg.Cluster, engine.config.Cluster, "only the polling settings should be updated")
+	assert.False(t, testConfig.PollMetrics.Enabled(), "the original config should not be modified")
+}
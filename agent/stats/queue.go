@@ -23,7 +23,6 @@ import (
 	loggerfield "github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/stats"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/tcs/model/ecstcs"
-	"github.com/cihub/seelog"
 	"github.com/docker/docker/api/types"
 	"github.com/pkg/errors"
 )
@@ -117,7 +116,7 @@ func (queue *Queue) add(rawStat *ContainerStats) {
 		timeSinceLastStat := float32(rawStat.timestamp.Sub(lastStat.Timestamp).Nanoseconds())
 		if timeSinceLastStat <= 0 {
 			// if we got a duplicate timestamp, set cpu percentage to the same value as the previous stat
-			seelog.Errorf("Received a docker stat object with duplicate timestamp")
+			logger.Error("Received a docker stat object with duplicate timestamp")
 			stat.CPUUsagePerc = lastStat.CPUUsagePerc
 			if stat.NetworkStats != nil && lastStat.NetworkStats != nil {
 				stat.NetworkStats.RxBytesPerSecond = lastStat.NetworkStats.RxBytesPerSecond
@@ -143,8 +142,15 @@ func (queue *Queue) add(rawStat *ContainerStats) {
 
 		if stat.CPUUsagePerc > MaxCPUUsagePerc {
 			// what in the world happened
-			seelog.Errorf("Calculated CPU usage percent (%.1f) is larger than backend maximum (%.1f). lastStatTS=%s lastStatCPUTime=%d thisStatTS=%s thisStatCPUTime=%d queueLength=%d",
-				stat.CPUUsagePerc, MaxCPUUsagePerc, lastStat.Timestamp.Format(time.RFC3339Nano), lastStat.cpuUsage, rawStat.timestamp.Format(time.RFC3339Nano), rawStat.cpuUsage, queueLength)
+			logger.Error("Calculated CPU usage percent is larger than backend maximum", logger.Fields{
+				"cpuUsagePercent":    fmt.Sprintf("%.1f", stat.CPUUsagePerc),
+				"maxCPUUsagePercent": fmt.Sprintf("%.1f", MaxCPUUsagePerc),
+				"lastStatTS":         lastStat.Timestamp.Format(time.RFC3339Nano),
+				"lastStatCPUTime":    lastStat.cpuUsage,
+				"thisStatTS":         rawStat.timestamp.Format(time.RFC3339Nano),
+				"thisStatCPUTime":    rawStat.cpuUsage,
+				"queueLength":        queueLength,
+			})
 		}
 
 		if stat.NetworkStats != nil {
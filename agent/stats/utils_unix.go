@@ -21,7 +21,7 @@ import (
 	"strings"
 
 	"github.com/aws/amazon-ecs-agent/agent/config"
-	"github.com/cihub/seelog"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
 	"github.com/docker/docker/api/types"
 )
 
@@ -79,7 +79,7 @@ func validateDockerStats(dockerStats *types.StatsJSON, containerEnabledRestartPo
 func getStorageStats(dockerStats *types.StatsJSON) (uint64, uint64) {
 	// initialize block io and loop over stats to aggregate
 	if dockerStats.BlkioStats.IoServiceBytesRecursive == nil {
-		seelog.Debug("Storage stats not reported for container")
+		logger.Debug("Storage stats not reported for container")
 		return uint64(0), uint64(0)
 	}
 	storageReadBytes := uint64(0)
@@ -0,0 +1,362 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package supportbundle collects the diagnostic information of the agent and of the host in a
+// compressed archive, which can be attached to support cases.
+package supportbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-ecs-agent/agent/config"
+	"github.com/aws/amazon-ecs-agent/agent/dockerclient"
+	"github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi"
+	"github.com/aws/amazon-ecs-agent/agent/engine/dockerstate"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/doctor"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+)
+
+const (
+	// maxLogFiles is the maximum number of agent log files included in the bundle
+	maxLogFiles = 5
+	// maxLogFileSize is the maximum number of bytes included from the end of each agent log file
+	maxLogFileSize = 10 * 1024 * 1024
+	// commandTimeout is the timeout of each diagnostic command
+	commandTimeout = 10 * time.Second
+	// networkDiagnosticsTimeout bounds the time spent running the diagnostic commands of the host
+	// and of all the tasks, so that the bundle is collected within the write timeout of the
+	// introspection server. The commands which don't run in time are skipped.
+	networkDiagnosticsTimeout = 30 * time.Second
+	// errorsFileName is the name of the file listing the sections which couldn't be collected
+	errorsFileName = "errors.txt"
+)
+
+// commandRunner runs a command and returns its combined output
+type commandRunner func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+// Collector writes support bundles with the agent logs, the agent state and configuration, the
+// results of the instance healthchecks, runtime profiles of the agent and network diagnostics of
+// the host and of the tasks.
+type Collector struct {
+	cfg          *config.Config
+	state        dockerstate.TaskEngineState
+	dockerClient dockerapi.DockerClient
+	doctor       *doctor.Doctor
+	logFile      string
+	runCommand   commandRunner
+
+	networkDiagnosticsTimeout time.Duration
+}
+
+// NewCollector creates a new support bundle collector
+func NewCollector(cfg *config.Config, state dockerstate.TaskEngineState, dockerClient dockerapi.DockerClient,
+	doctor *doctor.Doctor) *Collector {
+	return &Collector{
+		cfg:          cfg,
+		state:        state,
+		dockerClient: dockerClient,
+		doctor:       doctor,
+		logFile:      os.Getenv(logger.LOGFILE_ENV_VAR),
+		runCommand: func(ctx context.Context, name string, args ...string) ([]byte, error) {
+			return exec.CommandContext(ctx, name, args...).CombinedOutput()
+		},
+		networkDiagnosticsTimeout: networkDiagnosticsTimeout,
+	}
+}
+
+// bundleWriter writes files to the tar archive of a support bundle. It keeps the first write error
+// so that the collection stops as soon as the archive can't be written anymore.
+type bundleWriter struct {
+	tar     *tar.Writer
+	modTime time.Time
+	err     error
+}
+
+func (b *bundleWriter) writeFile(name string, data []byte) error {
+	if b.err != nil {
+		return b.err
+	}
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: b.modTime,
+	}
+	if err := b.tar.WriteHeader(header); err != nil {
+		b.err = err
+		return err
+	}
+	if _, err := b.tar.Write(data); err != nil {
+		b.err = err
+		return err
+	}
+	return nil
+}
+
+func (b *bundleWriter) writeJSON(name string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	return b.writeFile(name, data)
+}
+
+// WriteSupportBundle writes a gzip compressed tar archive with the diagnostic information to w.
+// Sections which can't be collected are listed in errors.txt instead of failing the whole bundle,
+// an error is only returned when the archive can't be written.
+func (c *Collector) WriteSupportBundle(w io.Writer) error {
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+	bundle := &bundleWriter{tar: tarWriter, modTime: time.Now()}
+
+	sections := []struct {
+		name    string
+		collect func(*bundleWriter) error
+	}{
+		{"config", c.collectConfig},
+		{"state", c.collectState},
+		{"healthchecks", c.collectHealthchecks},
+		{"docker info", c.collectDockerInfo},
+		{"profiles", c.collectProfiles},
+		{"logs", c.collectLogs},
+		{"network diagnostics", c.collectNetworkDiagnostics},
+	}
+	var sectionErrors []string
+	for _, section := range sections {
+		err := section.collect(bundle)
+		if bundle.err != nil {
+			return bundle.err
+		}
+		if err != nil {
+			logger.Warn("Unable to collect support bundle section", logger.Fields{
+				"section":   section.name,
+				field.Error: err,
+			})
+			sectionErrors = append(sectionErrors, fmt.Sprintf("%s: %v", section.name, err))
+		}
+	}
+	if len(sectionErrors) > 0 {
+		bundle.writeFile(errorsFileName, []byte(strings.Join(sectionErrors, "\n")+"\n"))
+		if bundle.err != nil {
+			return bundle.err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+func (c *Collector) collectConfig(b *bundleWriter) error {
+	if c.cfg == nil {
+		return fmt.Errorf("configuration is not available")
+	}
+	return b.writeJSON("config.json", c.cfg.Redacted())
+}
+
+func (c *Collector) collectState(b *bundleWriter) error {
+	if c.state == nil {
+		return fmt.Errorf("task engine state is not available")
+	}
+	data, err := c.state.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	// The environment variables of the containers include the values of their secrets
+	data, err = redactState(data)
+	if err != nil {
+		return err
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, data, "", "  "); err != nil {
+		return err
+	}
+	return b.writeFile("state.json", indented.Bytes())
+}
+
+// healthcheckResult is the result of an instance healthcheck in the support bundle
+type healthcheckResult struct {
+	Type                string    `json:"type"`
+	Status              string    `json:"status"`
+	Time                time.Time `json:"time"`
+	StatusChangeTime    time.Time `json:"statusChangeTime"`
+	LastStatus          string    `json:"lastStatus"`
+	LastHealthcheckTime time.Time `json:"lastHealthcheckTime"`
+}
+
+func (c *Collector) collectHealthchecks(b *bundleWriter) error {
+	if c.doctor == nil {
+		return fmt.Errorf("instance healthchecks are not available")
+	}
+	results := []healthcheckResult{}
+	for _, healthcheck := range *c.doctor.GetHealthchecks() {
+		results = append(results, healthcheckResult{
+			Type:                healthcheck.GetHealthcheckType(),
+			Status:              healthcheck.GetHealthcheckStatus().String(),
+			Time:                healthcheck.GetHealthcheckTime(),
+			StatusChangeTime:    healthcheck.GetStatusChangeTime(),
+			LastStatus:          healthcheck.GetLastHealthcheckStatus().String(),
+			LastHealthcheckTime: healthcheck.GetLastHealthcheckTime(),
+		})
+	}
+	return b.writeJSON("healthchecks.json", results)
+}
+
+func (c *Collector) collectDockerInfo(b *bundleWriter) error {
+	if c.dockerClient == nil {
+		return fmt.Errorf("docker client is not available")
+	}
+	info, err := c.dockerClient.Info(context.Background(), dockerclient.InfoTimeout)
+	if err != nil {
+		return err
+	}
+	return b.writeJSON("docker-info.json", info)
+}
+
+func (c *Collector) collectProfiles(b *bundleWriter) error {
+	var goroutines bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&goroutines, 2); err != nil {
+		return err
+	}
+	if err := b.writeFile("pprof/goroutine.txt", goroutines.Bytes()); err != nil {
+		return err
+	}
+	var heap bytes.Buffer
+	if err := pprof.Lookup("heap").WriteTo(&heap, 0); err != nil {
+		return err
+	}
+	return b.writeFile("pprof/heap.pb.gz", heap.Bytes())
+}
+
+// collectLogs adds the end of the most recent agent log files, including the rotated ones
+func (c *Collector) collectLogs(b *bundleWriter) error {
+	if c.logFile == "" {
+		return fmt.Errorf("agent is not logging to a file")
+	}
+	logFiles, err := filepath.Glob(c.logFile + "*")
+	if err != nil {
+		return err
+	}
+	type logFileInfo struct {
+		path    string
+		modTime time.Time
+	}
+	var infos []logFileInfo
+	for _, logFile := range logFiles {
+		info, err := os.Stat(logFile)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		infos = append(infos, logFileInfo{path: logFile, modTime: info.ModTime()})
+	}
+	if len(infos) == 0 {
+		return fmt.Errorf("no log file found at %s", c.logFile)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].modTime.After(infos[j].modTime)
+	})
+	if len(infos) > maxLogFiles {
+		infos = infos[:maxLogFiles]
+	}
+	for _, info := range infos {
+		data, err := readFileTail(info.path, maxLogFileSize)
+		if err != nil {
+			return err
+		}
+		if err := b.writeFile("logs/"+filepath.Base(info.path), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readFileTail returns at most the last maxSize bytes of the file
+func readFileTail(path string, maxSize int64) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxSize {
+		if _, err := file.Seek(info.Size()-maxSize, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+	return io.ReadAll(io.LimitReader(file, maxSize))
+}
+
+// collectNetworkDiagnostics adds the output of the network diagnostic commands run on the host and
+// in the network namespace of each task. Failed commands are recorded in the output.
+func (c *Collector) collectNetworkDiagnostics(b *bundleWriter) error {
+	if len(hostNetworkCommands) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.networkDiagnosticsTimeout)
+	defer cancel()
+	if err := b.writeFile("network/host.txt", c.runCommands(ctx, hostNetworkCommands)); err != nil {
+		return err
+	}
+	if c.state == nil {
+		return nil
+	}
+	for _, task := range c.state.AllTasks() {
+		commands := taskNetworkCommands(task)
+		if len(commands) == 0 {
+			continue
+		}
+		if err := b.writeFile("network/tasks/"+task.GetID()+".txt", c.runCommands(ctx, commands)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runCommands runs the commands until ctx is done, the commands left are recorded as skipped
+func (c *Collector) runCommands(ctx context.Context, commands [][]string) []byte {
+	var output bytes.Buffer
+	for _, command := range commands {
+		fmt.Fprintf(&output, "$ %s\n", strings.Join(command, " "))
+		if ctx.Err() != nil {
+			output.WriteString("skipped: network diagnostics timed out\n\n")
+			continue
+		}
+		ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+		out, err := c.runCommand(ctx, command[0], command[1:]...)
+		cancel()
+		output.Write(out)
+		if err != nil {
+			fmt.Fprintf(&output, "error: %v\n", err)
+		}
+		output.WriteString("\n")
+	}
+	return output.Bytes()
+}
@@ -0,0 +1,44 @@
+//go:build linux
+// +build linux
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package supportbundle
+
+import (
+	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
+)
+
+// hostNetworkCommands are the diagnostic commands run on the host
+var hostNetworkCommands = [][]string{
+	{"ip", "addr", "show"},
+	{"ip", "route", "show", "table", "all"},
+	{"ip", "rule", "show"},
+	{"iptables-save"},
+	{"tc", "qdisc", "show"},
+}
+
+// taskNetworkCommands returns the diagnostic commands run in the network namespace of the task.
+// Tasks sharing the host network namespace, or whose network namespace isn't known, have none.
+func taskNetworkCommands(task *apitask.Task) [][]string {
+	netns := task.GetNetworkNamespace()
+	if task.IsNetworkModeHost() || netns == "" {
+		return nil
+	}
+	var commands [][]string
+	for _, command := range hostNetworkCommands {
+		commands = append(commands, append([]string{"nsenter", "--net=" + netns}, command...))
+	}
+	return commands
+}
@@ -0,0 +1,70 @@
+//go:build linux && unit
+// +build linux,unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package supportbundle
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSupportBundleNetworkDiagnostics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	collector, dockerClient, commands := newTestCollector(t, ctrl)
+	dockerClient.EXPECT().Info(gomock.Any(), gomock.Any()).Return(types.Info{}, nil)
+
+	var bundle bytes.Buffer
+	require.NoError(t, collector.WriteSupportBundle(&bundle))
+	files := readBundle(t, bundle.Bytes())
+
+	assert.Contains(t, string(files["network/host.txt"]), "$ iptables-save\noutput\n")
+	assert.Contains(t, string(files["network/tasks/task-id.txt"]),
+		"$ nsenter --net="+testNetns+" iptables-save\noutput\n")
+	assert.Len(t, *commands, 2*len(hostNetworkCommands))
+	assert.Contains(t, *commands, []string{"nsenter", "--net=" + testNetns, "ip", "addr", "show"})
+}
+
+func TestWriteSupportBundleNetworkDiagnosticsTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	collector, dockerClient, _ := newTestCollector(t, ctrl)
+	dockerClient.EXPECT().Info(gomock.Any(), gomock.Any()).Return(types.Info{}, nil)
+	collector.networkDiagnosticsTimeout = 10 * time.Millisecond
+	var commands int
+	collector.runCommand = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		commands++
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	var bundle bytes.Buffer
+	require.NoError(t, collector.WriteSupportBundle(&bundle))
+	files := readBundle(t, bundle.Bytes())
+
+	assert.Equal(t, 1, commands, "the commands left should be skipped once the diagnostics timed out")
+	assert.Contains(t, string(files["network/host.txt"]), "$ ip addr show\nerror: context deadline exceeded\n")
+	assert.Contains(t, string(files["network/tasks/task-id.txt"]), "skipped: network diagnostics timed out")
+}
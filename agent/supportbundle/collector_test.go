@@ -0,0 +1,203 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package supportbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
+	"github.com/aws/amazon-ecs-agent/agent/config"
+	mock_dockerapi "github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi/mocks"
+	agentdoctor "github.com/aws/amazon-ecs-agent/agent/doctor"
+	"github.com/aws/amazon-ecs-agent/agent/engine/dockerstate"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/doctor"
+	"github.com/docker/docker/api/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testTaskARN       = "arn:aws:ecs:us-west-2:123456789012:task/cluster/task-id"
+	testNetns         = "/var/run/netns/task-id"
+	testSecretValue   = "resolved-secret-value"
+	testCredentialsID = "0123456789abcdef"
+)
+
+// readBundle returns the contents of the files in the support bundle
+func readBundle(t *testing.T, bundle []byte) map[string][]byte {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(bundle))
+	require.NoError(t, err)
+	tarReader := tar.NewReader(gzipReader)
+	files := make(map[string][]byte)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(tarReader)
+		require.NoError(t, err)
+		files[header.Name] = data
+	}
+	return files
+}
+
+func newTestCollector(t *testing.T, ctrl *gomock.Controller) (*Collector, *mock_dockerapi.MockDockerClient, *[][]string) {
+	dockerClient := mock_dockerapi.NewMockDockerClient(ctrl)
+	healthcheck := agentdoctor.NewDockerRuntimeHealthcheck(dockerClient)
+	testDoctor, err := doctor.NewDoctor([]doctor.Healthcheck{healthcheck}, "cluster", "container-instance")
+	require.NoError(t, err)
+
+	state := dockerstate.NewTaskEngineState()
+	dockerConfig := `{"Env":["DB_PASSWORD=` + testSecretValue + `"],"Labels":{"label":"value"}}`
+	state.AddTask(&apitask.Task{
+		Arn:              testTaskARN,
+		NetworkMode:      apitask.AWSVPCNetworkMode,
+		NetworkNamespace: testNetns,
+		Containers: []*apicontainer.Container{
+			{
+				Name: "app",
+				// The value of the secret is added to the environment once it's resolved
+				Secrets: []apicontainer.Secret{
+					{Name: "DB_PASSWORD", ValueFrom: "arn:aws:ssm:us-west-2:123456789012:parameter/db", Provider: "ssm"},
+				},
+				Environment:  map[string]string{"DB_PASSWORD": testSecretValue},
+				DockerConfig: apicontainer.DockerConfig{Config: &dockerConfig},
+			},
+		},
+		ExecutionCredentialsID: testCredentialsID,
+	})
+
+	cfg := &config.Config{
+		Cluster:        "cluster",
+		EngineAuthData: config.NewSensitiveRawMessage(json.RawMessage(`{"registry":{"password":"secret"}}`)),
+	}
+
+	logDir := t.TempDir()
+	logFile := filepath.Join(logDir, "ecs-agent.log")
+	require.NoError(t, os.WriteFile(logFile, []byte("current log"), 0644))
+	require.NoError(t, os.WriteFile(logFile+".2024-01-01-00", []byte("rotated log"), 0644))
+
+	var commands [][]string
+	collector := NewCollector(cfg, state, dockerClient, testDoctor)
+	collector.logFile = logFile
+	collector.runCommand = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		commands = append(commands, append([]string{name}, args...))
+		return []byte("output\n"), nil
+	}
+	return collector, dockerClient, &commands
+}
+
+func TestWriteSupportBundle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	collector, dockerClient, _ := newTestCollector(t, ctrl)
+	dockerClient.EXPECT().Info(gomock.Any(), gomock.Any()).Return(types.Info{ServerVersion: "20.10.0"}, nil)
+
+	var bundle bytes.Buffer
+	require.NoError(t, collector.WriteSupportBundle(&bundle))
+	files := readBundle(t, bundle.Bytes())
+
+	assert.Contains(t, string(files["config.json"]), `"Cluster": "cluster"`)
+	assert.NotContains(t, string(files["config.json"]), "secret")
+	assert.Contains(t, string(files["state.json"]), testTaskARN)
+	assert.Contains(t, string(files["healthchecks.json"]), `"type": "ContainerRuntime"`)
+	assert.Contains(t, string(files["docker-info.json"]), "20.10.0")
+	assert.Contains(t, string(files["pprof/goroutine.txt"]), "goroutine")
+	assert.NotEmpty(t, files["pprof/heap.pb.gz"])
+	assert.Equal(t, "current log", string(files["logs/ecs-agent.log"]))
+	assert.Equal(t, "rotated log", string(files["logs/ecs-agent.log.2024-01-01-00"]))
+	assert.NotContains(t, files, errorsFileName)
+}
+
+func TestWriteSupportBundleRedactsState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	collector, dockerClient, _ := newTestCollector(t, ctrl)
+	dockerClient.EXPECT().Info(gomock.Any(), gomock.Any()).Return(types.Info{}, nil)
+
+	var bundle bytes.Buffer
+	require.NoError(t, collector.WriteSupportBundle(&bundle))
+	state := string(readBundle(t, bundle.Bytes())["state.json"])
+
+	assert.NotContains(t, state, testSecretValue)
+	assert.NotContains(t, state, testCredentialsID)
+	assert.Contains(t, state, `"DB_PASSWORD": "[redacted]"`)
+	assert.Contains(t, state, `DB_PASSWORD=[redacted]`)
+	assert.Contains(t, state, "arn:aws:ssm:us-west-2:123456789012:parameter/db")
+}
+
+func TestWriteSupportBundleSectionErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	collector, dockerClient, _ := newTestCollector(t, ctrl)
+	collector.logFile = ""
+	dockerClient.EXPECT().Info(gomock.Any(), gomock.Any()).Return(types.Info{}, errors.New("docker is down"))
+
+	var bundle bytes.Buffer
+	require.NoError(t, collector.WriteSupportBundle(&bundle))
+	files := readBundle(t, bundle.Bytes())
+
+	assert.Contains(t, files, "state.json")
+	assert.NotContains(t, files, "docker-info.json")
+	assert.Contains(t, string(files[errorsFileName]), "docker info: docker is down")
+	assert.Contains(t, string(files[errorsFileName]), "logs: agent is not logging to a file")
+}
+
+// failingWriter fails all the writes
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("connection closed")
+}
+
+func TestWriteSupportBundleWriteError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	collector, dockerClient, _ := newTestCollector(t, ctrl)
+	dockerClient.EXPECT().Info(gomock.Any(), gomock.Any()).Return(types.Info{}, nil).AnyTimes()
+
+	assert.Error(t, collector.WriteSupportBundle(failingWriter{}))
+}
+
+func TestReadFileTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(path, []byte("0123456789"), 0644))
+
+	data, err := readFileTail(path, 4)
+	require.NoError(t, err)
+	assert.Equal(t, "6789", string(data))
+
+	data, err = readFileTail(path, 100)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", string(data))
+}
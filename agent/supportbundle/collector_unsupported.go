@@ -0,0 +1,29 @@
+//go:build !linux && !windows
+// +build !linux,!windows
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package supportbundle
+
+import (
+	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
+)
+
+// hostNetworkCommands are the diagnostic commands run on the host
+var hostNetworkCommands [][]string
+
+// taskNetworkCommands returns the diagnostic commands run for the task
+func taskNetworkCommands(task *apitask.Task) [][]string {
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package supportbundle
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/aws/amazon-ecs-agent/ecs-agent/utils"
+)
+
+const (
+	// redactedValue replaces the sensitive values in the support bundle
+	redactedValue = "[redacted]"
+
+	// environmentKey is the key of the environment variables of a container in the state. The
+	// values of the secrets of the container are added to them once they are resolved.
+	environmentKey = "environment"
+	// dockerConfigKey is the key of the docker configuration of a container in the state, whose
+	// config holds environment variables as well
+	dockerConfigKey = "dockerConfig"
+)
+
+// credentialsIDKeys are the keys of the credentials IDs in the state. A credentials ID gives
+// access to the credentials of the task through the credentials endpoint.
+var credentialsIDKeys = map[string]struct{}{
+	"credentialsID":          {},
+	"executionCredentialsID": {},
+}
+
+// redactState returns a copy of the marshaled task engine state where the values of the
+// environment variables of the containers, which include the values of their secrets, and the
+// credentials IDs are redacted.
+func redactState(data []byte) ([]byte, error) {
+	var state interface{}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	redactValue(state)
+	return json.Marshal(state)
+}
+
+func redactValue(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if _, ok := credentialsIDKeys[key]; ok {
+				if id, ok := child.(string); ok {
+					v[key] = utils.TruncateString(id, utils.CredentialsIDLogTruncationLen)
+				}
+				continue
+			}
+			switch key {
+			case environmentKey:
+				redactEnvironment(child)
+			case dockerConfigKey:
+				redactDockerConfig(child)
+			default:
+				redactValue(child)
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			redactValue(child)
+		}
+	}
+}
+
+// redactEnvironment redacts the values of a map of environment variables, keeping their names
+func redactEnvironment(value interface{}) {
+	environment, ok := value.(map[string]interface{})
+	if !ok {
+		return
+	}
+	for name := range environment {
+		environment[name] = redactedValue
+	}
+}
+
+// redactDockerConfig redacts the values of the environment variables set in the docker config of a
+// container, which is marshaled as a string. The config is dropped if it can't be parsed.
+func redactDockerConfig(value interface{}) {
+	dockerConfig, ok := value.(map[string]interface{})
+	if !ok {
+		return
+	}
+	config, ok := dockerConfig["config"].(string)
+	if !ok || config == "" {
+		return
+	}
+	var parsed map[string]interface{}
+	if err := json.Unmarshal([]byte(config), &parsed); err != nil {
+		dockerConfig["config"] = redactedValue
+		return
+	}
+	if env, ok := parsed["Env"].([]interface{}); ok {
+		for i, variable := range env {
+			if s, ok := variable.(string); ok {
+				name, _, _ := strings.Cut(s, "=")
+				env[i] = name + "=" + redactedValue
+			}
+		}
+	}
+	redacted, err := json.Marshal(parsed)
+	if err != nil {
+		dockerConfig["config"] = redactedValue
+		return
+	}
+	dockerConfig["config"] = string(redacted)
+}
@@ -28,10 +28,14 @@ import (
 	"github.com/aws/amazon-ecs-agent/agent/asm/factory"
 	"github.com/aws/amazon-ecs-agent/agent/config"
 	"github.com/aws/amazon-ecs-agent/agent/taskresource"
+	"github.com/aws/amazon-ecs-agent/agent/taskresource/secretfiles"
 	resourcestatus "github.com/aws/amazon-ecs-agent/agent/taskresource/status"
 	apicontainerstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/container/status"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/api/task/status"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/credentials"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/metrics"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
@@ -66,6 +70,15 @@ type ASMSecretResource struct {
 	requiredSecrets map[string]apicontainer.Secret
 	// map to store secret values, key is a combination of valueFrom and region
 	secretData map[string]string
+	// secretFiles are the secrets delivered as files, keyed by container name
+	secretFiles map[string][]apicontainer.Secret
+	// secretFilesDir is the directory where the secret files are written
+	secretFilesDir string
+	// refreshInterval is the interval at which the secret files are refreshed while the task runs
+	refreshInterval time.Duration
+	refresher       *secretfiles.Refresher
+	// metricsFactory records the refreshes of the secret files
+	metricsFactory metrics.EntryFactory
 
 	// ssmClientCreator is a factory interface that creates new SSM clients. This is
 	// needed mostly for testing.
@@ -83,13 +96,21 @@ type ASMSecretResource struct {
 // NewASMSecretResource creates a new ASMSecretResource object
 func NewASMSecretResource(taskARN string,
 	asmSecrets map[string]apicontainer.Secret,
+	secretFiles map[string][]apicontainer.Secret,
 	executionCredentialsID string,
 	credentialsManager credentials.Manager,
-	asmClientCreator factory.ClientCreator) *ASMSecretResource {
+	asmClientCreator factory.ClientCreator,
+	secretFilesDir string,
+	refreshInterval time.Duration,
+	metricsFactory metrics.EntryFactory) *ASMSecretResource {
 
 	s := &ASMSecretResource{
 		taskARN:                taskARN,
 		requiredSecrets:        asmSecrets,
+		secretFiles:            secretFiles,
+		secretFilesDir:         secretFilesDir,
+		refreshInterval:        refreshInterval,
+		metricsFactory:         metricsFactory,
 		credentialsManager:     credentialsManager,
 		executionCredentialsID: executionCredentialsID,
 		asmClientCreator:       asmClientCreator,
@@ -293,6 +314,15 @@ func (secret *ASMSecretResource) Create() error {
 		secret.setTerminalReason(errorString)
 		return errors.New(errorString)
 	}
+
+	if len(secret.getSecretFiles()) == 0 {
+		return nil
+	}
+	if err := secret.writeSecretFiles(nil); err != nil {
+		secret.setTerminalReason(err.Error())
+		return err
+	}
+	secret.startRefresher()
 	return nil
 }
 
@@ -301,36 +331,136 @@ func (secret *ASMSecretResource) Create() error {
 func (secret *ASMSecretResource) retrieveASMSecretValue(apiSecret apicontainer.Secret, iamCredentials credentials.IAMRoleCredentials, wg *sync.WaitGroup, errorEvents chan error) {
 	defer wg.Done()
 
-	asmClient, err := secret.asmClientCreator.NewASMClient(apiSecret.Region, iamCredentials)
+	secretValue, err := secret.fetchASMSecretValue(apiSecret, iamCredentials)
 	if err != nil {
-		errorEvents <- fmt.Errorf("unable to create ASM client: %v", err)
+		errorEvents <- err
 		return
 	}
+
+	secret.lock.Lock()
+	defer secret.lock.Unlock()
+
+	// put secret value in secretData
+	secretKey := apiSecret.GetSecretResourceCacheKey()
+	secret.secretData[secretKey] = secretValue
+}
+
+// fetchASMSecretValue fetches the secret value from AWS Secrets Manager
+func (secret *ASMSecretResource) fetchASMSecretValue(apiSecret apicontainer.Secret,
+	iamCredentials credentials.IAMRoleCredentials) (string, error) {
+	asmClient, err := secret.asmClientCreator.NewASMClient(apiSecret.Region, iamCredentials)
+	if err != nil {
+		return "", fmt.Errorf("unable to create ASM client: %v", err)
+	}
 	seelog.Debugf("ASM secret resource: retrieving resource for secret %v in region %s for task: [%s]", apiSecret.ValueFrom, apiSecret.Region, secret.taskARN)
 	input, jsonKey, err := getASMParametersFromInput(apiSecret.ValueFrom)
 	if err != nil {
-		errorEvents <- fmt.Errorf("trying to retrieve secret with value %s resulted in error: %v", apiSecret.ValueFrom, err)
-		return
+		return "", fmt.Errorf("trying to retrieve secret with value %s resulted in error: %v", apiSecret.ValueFrom, err)
 	}
 
 	if input.SecretId == nil {
-		errorEvents <- fmt.Errorf("could not find a secretsmanager secretID from value %s", apiSecret.ValueFrom)
-		return
+		return "", fmt.Errorf("could not find a secretsmanager secretID from value %s", apiSecret.ValueFrom)
+	}
 
+	return asm.GetSecretFromASMWithInput(input, asmClient, jsonKey)
+}
+
+// writeSecretFiles writes the secret files of the containers whose secret key is in keys, or all of them when keys
+// is nil
+func (secret *ASMSecretResource) writeSecretFiles(keys map[string]struct{}) error {
+	for containerName, secrets := range secret.getSecretFiles() {
+		for _, s := range secrets {
+			secretKey := s.GetSecretResourceCacheKey()
+			if _, ok := keys[secretKey]; keys != nil && !ok {
+				continue
+			}
+			secretValue, ok := secret.GetCachedSecretValue(secretKey)
+			if !ok {
+				return fmt.Errorf("ASM secret resource: no value for secret file %s of container %s", s.Name,
+					containerName)
+			}
+			if err := secretfiles.Write(secret.secretFilesDir, secret.taskARN, containerName, s,
+				secretValue); err != nil {
+				return fmt.Errorf("ASM secret resource: unable to write secret file %s of container %s: %w", s.Name,
+					containerName, err)
+			}
+		}
 	}
+	return nil
+}
 
-	secretValue, err := asm.GetSecretFromASMWithInput(input, asmClient, jsonKey)
-	if err != nil {
-		errorEvents <- err
+// startRefresher starts refreshing the secret files at the refresh interval until the task stops
+func (secret *ASMSecretResource) startRefresher() {
+	secret.lock.Lock()
+	defer secret.lock.Unlock()
+
+	if secret.refresher != nil || secret.refreshInterval <= 0 {
 		return
 	}
+	secret.refresher = secretfiles.StartRefresher(secret.refreshInterval, secret.refreshSecretFiles,
+		secret.DesiredTerminal, secret.metricsFactory, logger.Fields{
+			field.TaskARN: secret.taskARN,
+			"resource":    ResourceName,
+		})
+}
 
+// stopRefresher stops refreshing the secret files
+func (secret *ASMSecretResource) stopRefresher() {
 	secret.lock.Lock()
 	defer secret.lock.Unlock()
 
-	// put secret value in secretData
-	secretKey := apiSecret.GetSecretResourceCacheKey()
-	secret.secretData[secretKey] = secretValue
+	if secret.refresher != nil {
+		secret.refresher.Stop()
+		secret.refresher = nil
+	}
+}
+
+// refreshSecretFiles fetches the secrets delivered as files again with the execution role credentials, and rewrites
+// the files of the secrets whose value changed
+func (secret *ASMSecretResource) refreshSecretFiles() error {
+	executionCredentials, ok := secret.credentialsManager.GetTaskCredentials(secret.getExecutionCredentialsID())
+	if !ok {
+		return errors.New("ASM secret resource: unable to find execution role credentials")
+	}
+	iamCredentials := executionCredentials.GetIAMRoleCredentials()
+
+	changed := make(map[string]struct{})
+	fetched := make(map[string]struct{})
+	var errs []string
+	for _, secrets := range secret.getSecretFiles() {
+		for _, s := range secrets {
+			secretKey := s.GetSecretResourceCacheKey()
+			if _, ok := fetched[secretKey]; ok {
+				continue
+			}
+			fetched[secretKey] = struct{}{}
+			secretValue, err := secret.fetchASMSecretValue(s, iamCredentials)
+			if err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			if cachedValue, ok := secret.GetCachedSecretValue(secretKey); ok && cachedValue == secretValue {
+				continue
+			}
+			secret.SetCachedSecretValue(secretKey, secretValue)
+			changed[secretKey] = struct{}{}
+		}
+	}
+
+	if err := secret.writeSecretFiles(changed); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ";"))
+	}
+	if len(changed) > 0 {
+		logger.Info("Refreshed secret files", logger.Fields{
+			field.TaskARN:    secret.taskARN,
+			"resource":       ResourceName,
+			"changedSecrets": len(changed),
+		})
+	}
+	return nil
 }
 
 func pointerOrNil(in string) *string {
@@ -400,6 +530,14 @@ func (secret *ASMSecretResource) getRequiredSecrets() map[string]apicontainer.Se
 	return secret.requiredSecrets
 }
 
+// getSecretFiles returns the secrets delivered as files, keyed by container name
+func (secret *ASMSecretResource) getSecretFiles() map[string][]apicontainer.Secret {
+	secret.lock.RLock()
+	defer secret.lock.RUnlock()
+
+	return secret.secretFiles
+}
+
 // getExecutionCredentialsID returns the execution role's credential ID
 func (secret *ASMSecretResource) getExecutionCredentialsID() string {
 	secret.lock.RLock()
@@ -408,9 +546,13 @@ func (secret *ASMSecretResource) getExecutionCredentialsID() string {
 	return secret.executionCredentialsID
 }
 
-// Cleanup removes the secret value created for the task
+// Cleanup removes the secret value and the secret files created for the task
 func (secret *ASMSecretResource) Cleanup() error {
+	secret.stopRefresher()
 	secret.clearASMSecretValue()
+	if len(secret.getSecretFiles()) > 0 {
+		return secretfiles.Remove(secret.secretFilesDir, secret.taskARN)
+	}
 	return nil
 }
 
@@ -454,6 +596,9 @@ func (secret *ASMSecretResource) Initialize(
 	secret.initStatusToTransition()
 	secret.credentialsManager = resourceFields.CredentialsManager
 	secret.asmClientCreator = resourceFields.ASMClientCreator
+	secret.secretFilesDir = config.SecretFilesDir
+	secret.refreshInterval = config.SecretRefreshInterval
+	secret.metricsFactory = resourceFields.MetricsFactory
 
 	// if task hasn't turn to 'created' status, and it's desire status is 'running'
 	// the resource status needs to be reset to 'NONE' status so the secret value
@@ -462,15 +607,22 @@ func (secret *ASMSecretResource) Initialize(
 		taskDesiredStatus <= status.TaskRunning {
 		secret.SetKnownStatus(resourcestatus.ResourceStatusNone)
 	}
+
+	// the secret files of the running tasks keep being refreshed after the agent restarts
+	if config.SecretFilesEnabled.Enabled() && secret.KnownCreated() && !secret.DesiredTerminal() &&
+		len(secret.getSecretFiles()) > 0 {
+		secret.startRefresher()
+	}
 }
 
 type ASMSecretResourceJSON struct {
-	TaskARN                string                         `json:"taskARN"`
-	CreatedAt              *time.Time                     `json:"createdAt,omitempty"`
-	DesiredStatus          *ASMSecretStatus               `json:"desiredStatus"`
-	KnownStatus            *ASMSecretStatus               `json:"knownStatus"`
-	RequiredSecrets        map[string]apicontainer.Secret `json:"secretResources"`
-	ExecutionCredentialsID string                         `json:"executionCredentialsID"`
+	TaskARN                string                           `json:"taskARN"`
+	CreatedAt              *time.Time                       `json:"createdAt,omitempty"`
+	DesiredStatus          *ASMSecretStatus                 `json:"desiredStatus"`
+	KnownStatus            *ASMSecretStatus                 `json:"knownStatus"`
+	RequiredSecrets        map[string]apicontainer.Secret   `json:"secretResources"`
+	SecretFiles            map[string][]apicontainer.Secret `json:"secretFiles,omitempty"`
+	ExecutionCredentialsID string                           `json:"executionCredentialsID"`
 }
 
 // MarshalJSON serialises the ASMSecretResource struct to JSON
@@ -493,6 +645,7 @@ func (secret *ASMSecretResource) MarshalJSON() ([]byte, error) {
 			return &s
 		}(),
 		RequiredSecrets:        secret.getRequiredSecrets(),
+		SecretFiles:            secret.getSecretFiles(),
 		ExecutionCredentialsID: secret.getExecutionCredentialsID(),
 	})
 }
@@ -517,6 +670,7 @@ func (secret *ASMSecretResource) UnmarshalJSON(b []byte) error {
 	if temp.RequiredSecrets != nil {
 		secret.requiredSecrets = temp.RequiredSecrets
 	}
+	secret.secretFiles = temp.SecretFiles
 	secret.taskARN = temp.TaskARN
 	secret.executionCredentialsID = temp.ExecutionCredentialsID
 
@@ -378,6 +378,20 @@ func (cgroup *CgroupResource) GetCgroupMountPath() string {
 	return cgroup.cgroupMountPath
 }
 
+// GetResourceSpec returns the linux resources the cgroup is created with
+func (cgroup *CgroupResource) GetResourceSpec() specs.LinuxResources {
+	cgroup.lock.RLock()
+	defer cgroup.lock.RUnlock()
+	return cgroup.resourceSpec
+}
+
+// AddProcess moves the process into the cgroup of the task
+func (cgroup *CgroupResource) AddProcess(pid int) error {
+	cgroup.lock.RLock()
+	defer cgroup.lock.RUnlock()
+	return cgroup.control.AddProcess(cgroup.cgroupRoot, pid)
+}
+
 // Initialize initializes the resource fileds in cgroup
 func (cgroup *CgroupResource) Initialize(
 	config *config.Config,
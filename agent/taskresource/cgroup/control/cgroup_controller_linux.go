@@ -95,6 +95,22 @@ func (c *control) Exists(cgroupPath string) bool {
 	return true
 }
 
+// AddProcess moves the process into all the subsystems of the cgroup
+func (c *control) AddProcess(cgroupPath string, pid int) error {
+	seelog.Debugf("Adding process to cgroup cgroupPath=%s pid=%d", cgroupPath, pid)
+
+	controller, err := c.Load(cgroups.Default, cgroups.StaticPath(cgroupPath))
+	if err != nil {
+		return fmt.Errorf("cgroup add process: unable to obtain controller: %w", err)
+	}
+
+	err = controller.AddProc(uint64(pid))
+	if err != nil {
+		return fmt.Errorf("cgroup add process: unable to add process %d: %w", pid, err)
+	}
+	return nil
+}
+
 // Init is used to set up the cgroup root for ecs
 func (c *control) Init() error {
 	seelog.Debugf("Creating root ecs cgroup cgroupPath=%s", config.DefaultTaskCgroupV1Prefix)
@@ -139,6 +139,40 @@ func TestRemoveErrorCase(t *testing.T) {
 	assert.Error(t, control.Remove(testCgroupRoot))
 }
 
+func TestAddProcessHappyCase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCgroup := mock_cgroups.NewMockCgroup(ctrl)
+	mockCgroupFactory := mock_factory.NewMockCgroupFactory(ctrl)
+
+	gomock.InOrder(
+		mockCgroupFactory.EXPECT().Load(gomock.Any(), gomock.Any()).Return(mockCgroup, nil),
+		mockCgroup.EXPECT().AddProc(uint64(1234)).Return(nil),
+	)
+
+	control := newControl(mockCgroupFactory)
+
+	assert.NoError(t, control.AddProcess(testCgroupRoot, 1234))
+}
+
+func TestAddProcessErrorCase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCgroup := mock_cgroups.NewMockCgroup(ctrl)
+	mockCgroupFactory := mock_factory.NewMockCgroupFactory(ctrl)
+
+	gomock.InOrder(
+		mockCgroupFactory.EXPECT().Load(gomock.Any(), gomock.Any()).Return(mockCgroup, nil),
+		mockCgroup.EXPECT().AddProc(uint64(1234)).Return(errors.New("cgroup error")),
+	)
+
+	control := newControl(mockCgroupFactory)
+
+	assert.Error(t, control.AddProcess(testCgroupRoot, 1234))
+}
+
 func TestExistsHappyPath(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -92,6 +92,21 @@ func (c *controlv2) Exists(cgroupPath string) bool {
 	return true
 }
 
+// AddProcess moves the process into the cgroup
+func (c *controlv2) AddProcess(cgroupPath string, pid int) error {
+	seelog.Debugf("Adding process to cgroup cgroupv2root=%s parentSlice=%s cgroupPath=%s pid=%d", defaultCgroupv2Path, parentCgroupSlice, cgroupPath, pid)
+
+	m, err := cgroupsv2.LoadSystemd(parentCgroupSlice, cgroupPath)
+	if err != nil {
+		return fmt.Errorf("cgroupv2 add process: error loading systemd cgroup: %w", err)
+	}
+	err = m.AddProc(uint64(pid))
+	if err != nil {
+		return fmt.Errorf("cgroupv2 add process: unable to add process %d: %w", pid, err)
+	}
+	return nil
+}
+
 // Init is used to setup the cgroup root for ecs
 func (c *controlv2) Init() error {
 	// Load the "root" cgroup and verify cpu and memory cgroup controllers are available.
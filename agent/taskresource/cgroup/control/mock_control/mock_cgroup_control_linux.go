@@ -48,6 +48,20 @@ func (m *MockControl) EXPECT() *MockControlMockRecorder {
 	return m.recorder
 }
 
+// AddProcess mocks base method.
+func (m *MockControl) AddProcess(arg0 string, arg1 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddProcess", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddProcess indicates an expected call of AddProcess.
+func (mr *MockControlMockRecorder) AddProcess(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddProcess", reflect.TypeOf((*MockControl)(nil).AddProcess), arg0, arg1)
+}
+
 // Create mocks base method.
 func (m *MockControl) Create(arg0 *control.Spec) error {
 	m.ctrl.T.Helper()
@@ -34,4 +34,6 @@ type Control interface {
 	Remove(cgroupPath string) error
 	Exists(cgroupPath string) bool
 	Init() error
+	// AddProcess moves the process into the cgroup
+	AddProcess(cgroupPath string, pid int) error
 }
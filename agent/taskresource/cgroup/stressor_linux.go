@@ -0,0 +1,234 @@
+//go:build linux
+// +build linux
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cgroup
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/aws/amazon-ecs-agent/agent/app/args"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+	faulttype "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/types"
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+const (
+	// cpuStressPeriod is the period over which each CPU stress worker consumes its share of a CPU
+	cpuStressPeriod  = 100 * time.Millisecond
+	millicoresPerCPU = 1000
+)
+
+// ResourceStressor consumes the CPU or memory quota of task cgroups for the CPU stress and memory pressure
+// faults. The resource is consumed by a copy of the agent started in stress mode and moved into the task cgroup,
+// so that it's accounted to the task.
+type ResourceStressor struct {
+	// taskCgroup returns the cgroup resource of the task, which only exists if task resource limits are enabled
+	taskCgroup func(taskARN string) (*CgroupResource, bool)
+	executable func() (string, error)
+	// processes holds the stress processes keyed by the task ARN and the resource they consume
+	processes map[string]*os.Process
+	lock      sync.Mutex
+}
+
+// NewResourceStressor returns a ResourceStressor looking up the cgroups of the tasks with taskCgroup
+func NewResourceStressor(taskCgroup func(taskARN string) (*CgroupResource, bool)) *ResourceStressor {
+	return &ResourceStressor{
+		taskCgroup: taskCgroup,
+		executable: os.Executable,
+		processes:  make(map[string]*os.Process),
+	}
+}
+
+// Start starts consuming the percentage of the quota of the resource of the task cgroup for the duration
+func (s *ResourceStressor) Start(taskARN, resource string, percent uint64, duration time.Duration) error {
+	cgroup, ok := s.taskCgroup(taskARN)
+	if !ok {
+		return fmt.Errorf("cgroup stress: no cgroup found for task %s, task resource limits may be disabled", taskARN)
+	}
+	amount, err := stressAmount(cgroup.GetResourceSpec(), resource, percent)
+	if err != nil {
+		return fmt.Errorf("cgroup stress: task %s: %w", taskARN, err)
+	}
+	executable, err := s.executable()
+	if err != nil {
+		return fmt.Errorf("cgroup stress: unable to find agent executable: %w", err)
+	}
+
+	cmd := exec.Command(executable,
+		fmt.Sprintf("--%s=%s", args.FaultStressFlagName, resource),
+		fmt.Sprintf("--%s=%d", args.FaultStressAmountFlagName, amount),
+		fmt.Sprintf("--%s=%s", args.FaultStressDurationFlagName, duration))
+	// The stress process must not outlive the agent since it wouldn't be tracked anymore.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Pdeathsig: syscall.SIGKILL}
+	// The stress process waits for a byte on its standard input before consuming anything so that the
+	// resources are only consumed once it's in the task cgroup.
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("cgroup stress: unable to create stdin pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("cgroup stress: unable to start stress process: %w", err)
+	}
+	if err := cgroup.AddProcess(cmd.Process.Pid); err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return fmt.Errorf("cgroup stress: task %s: %w", taskARN, err)
+	}
+	if _, err := stdin.Write([]byte{0}); err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return fmt.Errorf("cgroup stress: unable to start consuming %s: %w", resource, err)
+	}
+	stdin.Close()
+
+	key := stressKey(taskARN, resource)
+	s.lock.Lock()
+	s.processes[key] = cmd.Process
+	s.lock.Unlock()
+	logger.Info("Started consuming task cgroup resource", logger.Fields{
+		field.TaskARN: taskARN,
+		"resource":    resource,
+		"amount":      amount,
+		"duration":    duration.String(),
+	})
+	go s.wait(key, cmd)
+	return nil
+}
+
+// wait waits for the stress process to exit and stops tracking it
+func (s *ResourceStressor) wait(key string, cmd *exec.Cmd) {
+	err := cmd.Wait()
+	logger.Debug("Stress process exited", logger.Fields{
+		"stress":    key,
+		field.Error: err,
+	})
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.processes[key] == cmd.Process {
+		delete(s.processes, key)
+	}
+}
+
+// Stop stops consuming the resource of the task cgroup
+func (s *ResourceStressor) Stop(taskARN, resource string) error {
+	key := stressKey(taskARN, resource)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	process, ok := s.processes[key]
+	if !ok {
+		return nil
+	}
+	if err := process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return fmt.Errorf("cgroup stress: unable to stop stress process: %w", err)
+	}
+	delete(s.processes, key)
+	return nil
+}
+
+// IsRunning returns whether the resource of the task cgroup is being consumed
+func (s *ResourceStressor) IsRunning(taskARN, resource string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, ok := s.processes[stressKey(taskARN, resource)]
+	return ok
+}
+
+func stressKey(taskARN, resource string) string {
+	return taskARN + "/" + resource
+}
+
+// stressAmount returns the millicores of CPU or the bytes of memory making up the percentage of the quota of the
+// task cgroup. Tasks without a CPU quota may use all the CPUs of the instance.
+func stressAmount(spec specs.LinuxResources, resource string, percent uint64) (uint64, error) {
+	switch resource {
+	case faulttype.CPUStressFaultType:
+		millicores := uint64(runtime.NumCPU()) * millicoresPerCPU
+		if spec.CPU != nil && spec.CPU.Quota != nil && *spec.CPU.Quota > 0 &&
+			spec.CPU.Period != nil && *spec.CPU.Period > 0 {
+			millicores = uint64(*spec.CPU.Quota) * millicoresPerCPU / *spec.CPU.Period
+		}
+		amount := millicores * percent / 100
+		if amount == 0 {
+			amount = 1
+		}
+		return amount, nil
+	case faulttype.MemoryPressureFaultType:
+		if spec.Memory == nil || spec.Memory.Limit == nil || *spec.Memory.Limit <= 0 {
+			return 0, errors.New("no memory limit set for the task cgroup")
+		}
+		return uint64(*spec.Memory.Limit) * percent / 100, nil
+	default:
+		return 0, fmt.Errorf("unknown resource %s", resource)
+	}
+}
+
+// RunStress consumes the millicores of CPU or the bytes of memory for the duration. It's meant to be run by the
+// stress process, which waits for the agent to move it into the task cgroup first.
+func RunStress(resource string, amount uint64, duration time.Duration) error {
+	if _, err := os.Stdin.Read(make([]byte, 1)); err != nil {
+		return fmt.Errorf("cgroup stress: stress process wasn't started: %w", err)
+	}
+	switch resource {
+	case faulttype.CPUStressFaultType:
+		runCPUStress(amount, duration)
+	case faulttype.MemoryPressureFaultType:
+		runMemoryPressure(amount, duration)
+	default:
+		return fmt.Errorf("cgroup stress: unknown resource %s", resource)
+	}
+	return nil
+}
+
+// runCPUStress spreads the millicores across as many workers as needed, each of them consuming at most a CPU.
+func runCPUStress(millicores uint64, duration time.Duration) {
+	workers := (millicores + millicoresPerCPU - 1) / millicoresPerCPU
+	busy := cpuStressPeriod * time.Duration(millicores/workers) / millicoresPerCPU
+	done := make(chan struct{})
+	for i := uint64(0); i < workers; i++ {
+		go func() {
+			for {
+				start := time.Now()
+				for time.Since(start) < busy {
+				}
+				select {
+				case <-done:
+					return
+				case <-time.After(cpuStressPeriod - busy):
+				}
+			}
+		}()
+	}
+	time.Sleep(duration)
+	close(done)
+}
+
+// runMemoryPressure allocates the bytes and writes to every page so that they're actually charged to the cgroup.
+func runMemoryPressure(bytes uint64, duration time.Duration) {
+	memory := make([]byte, bytes)
+	for i := 0; i < len(memory); i += os.Getpagesize() {
+		memory[i] = 1
+	}
+	time.Sleep(duration)
+	runtime.KeepAlive(memory)
+}
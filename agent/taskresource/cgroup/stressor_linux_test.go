@@ -0,0 +1,121 @@
+//go:build linux && unit
+// +build linux,unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cgroup
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ecs-agent/agent/taskresource/cgroup/control/mock_control"
+	faulttype "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/types"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/golang/mock/gomock"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStressAmount(t *testing.T) {
+	quota := int64(50000)
+	period := uint64(100000)
+	limit := int64(512 * 1024 * 1024)
+	tcs := []struct {
+		name           string
+		spec           specs.LinuxResources
+		resource       string
+		percent        uint64
+		expectedAmount uint64
+		expectedError  bool
+	}{
+		{
+			name:           "cpu quota",
+			spec:           specs.LinuxResources{CPU: &specs.LinuxCPU{Quota: &quota, Period: &period}},
+			resource:       faulttype.CPUStressFaultType,
+			percent:        80,
+			expectedAmount: 400,
+		},
+		{
+			name:           "no cpu quota",
+			spec:           specs.LinuxResources{CPU: &specs.LinuxCPU{Shares: aws.Uint64(1024)}},
+			resource:       faulttype.CPUStressFaultType,
+			percent:        50,
+			expectedAmount: uint64(runtime.NumCPU()) * 500,
+		},
+		{
+			name:           "memory limit",
+			spec:           specs.LinuxResources{Memory: &specs.LinuxMemory{Limit: &limit}},
+			resource:       faulttype.MemoryPressureFaultType,
+			percent:        25,
+			expectedAmount: 128 * 1024 * 1024,
+		},
+		{
+			name:          "no memory limit",
+			spec:          specs.LinuxResources{},
+			resource:      faulttype.MemoryPressureFaultType,
+			percent:       25,
+			expectedError: true,
+		},
+		{
+			name:          "unknown resource",
+			resource:      "disk-pressure",
+			percent:       25,
+			expectedError: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			amount, err := stressAmount(tc.spec, tc.resource, tc.percent)
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedAmount, amount)
+		})
+	}
+}
+
+func TestResourceStressorStartWithoutTaskCgroup(t *testing.T) {
+	stressor := NewResourceStressor(func(string) (*CgroupResource, bool) {
+		return nil, false
+	})
+	assert.Error(t, stressor.Start(validTaskArn, faulttype.CPUStressFaultType, 50, time.Minute))
+	assert.False(t, stressor.IsRunning(validTaskArn, faulttype.CPUStressFaultType))
+}
+
+func TestResourceStressorStartAddProcessError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockControl := mock_control.NewMockControl(ctrl)
+	cgroupRoot := "/ecs/" + taskID
+	resource := NewCgroupResource(validTaskArn, mockControl, nil, cgroupRoot, cgroupMountPath,
+		specs.LinuxResources{})
+	mockControl.EXPECT().AddProcess(cgroupRoot, gomock.Any()).Return(errors.New("cgroup error"))
+
+	stressor := NewResourceStressor(func(taskARN string) (*CgroupResource, bool) {
+		return resource, taskARN == validTaskArn
+	})
+	stressor.executable = func() (string, error) {
+		return "sleep", nil
+	}
+	assert.Error(t, stressor.Start(validTaskArn, faulttype.CPUStressFaultType, 50, time.Minute))
+	assert.False(t, stressor.IsRunning(validTaskArn, faulttype.CPUStressFaultType))
+	assert.NoError(t, stressor.Stop(validTaskArn, faulttype.CPUStressFaultType))
+}
@@ -0,0 +1,51 @@
+//go:build !linux
+// +build !linux
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cgroup
+
+import (
+	"errors"
+	"time"
+)
+
+// ResourceStressor consumes the CPU or memory quota of task cgroups for the CPU stress and memory pressure
+// faults
+type ResourceStressor struct{}
+
+// NewResourceStressor returns a ResourceStressor looking up the cgroups of the tasks with taskCgroup
+func NewResourceStressor(taskCgroup func(taskARN string) (*CgroupResource, bool)) *ResourceStressor {
+	return &ResourceStressor{}
+}
+
+// Start starts consuming the percentage of the quota of the resource of the task cgroup for the duration
+func (s *ResourceStressor) Start(taskARN, resource string, percent uint64, duration time.Duration) error {
+	return errors.New("unsupported platform")
+}
+
+// Stop stops consuming the resource of the task cgroup
+func (s *ResourceStressor) Stop(taskARN, resource string) error {
+	return nil
+}
+
+// IsRunning returns whether the resource of the task cgroup is being consumed
+func (s *ResourceStressor) IsRunning(taskARN, resource string) bool {
+	return false
+}
+
+// RunStress consumes the millicores of CPU or the bytes of memory for the duration
+func RunStress(resource string, amount uint64, duration time.Duration) error {
+	return errors.New("unsupported platform")
+}
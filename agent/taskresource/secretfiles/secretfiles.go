@@ -0,0 +1,228 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package secretfiles writes the secrets delivered as files to per-container directories on the tmpfs of the host,
+// which are bind mounted read-only into the containers, and refreshes them while the task runs.
+package secretfiles
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/metrics"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/utils/arn"
+)
+
+const (
+	// DefaultContainerPath is the directory of the container where the secret files are mounted when the secret
+	// has no container path
+	DefaultContainerPath = "/run/secrets"
+
+	// taskDirMode keeps the secret files of the tasks private on the host. The containers only see the directories
+	// mounted in them.
+	taskDirMode      = 0700
+	containerDirMode = 0755
+	secretFileMode   = 0444
+)
+
+// isTmpfs returns whether the path is on a tmpfs, so that the secrets are never written to disk
+var isTmpfs = isTmpfsPath
+
+// ContainerPath returns the directory of the container where the file of the secret is mounted
+func ContainerPath(secret apicontainer.Secret) string {
+	if secret.ContainerPath == "" {
+		return DefaultContainerPath
+	}
+	return filepath.Clean(secret.ContainerPath)
+}
+
+// HostDir returns the directory on the host with the secret files of the container mounted at the container path
+func HostDir(secretFilesDir, taskARN, containerName, containerPath string) (string, error) {
+	taskDir, err := taskDir(secretFilesDir, taskARN)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(taskDir, containerName, url.PathEscape(containerPath)), nil
+}
+
+func taskDir(secretFilesDir, taskARN string) (string, error) {
+	if secretFilesDir == "" {
+		return "", fmt.Errorf("secret files directory is not configured")
+	}
+	taskID, err := arn.TaskIdFromArn(taskARN)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(secretFilesDir, taskID), nil
+}
+
+// CheckDir returns an error when the secrets can't be written to files in the secret files directory, because it
+// isn't configured or isn't on a tmpfs
+func CheckDir(secretFilesDir string) error {
+	if secretFilesDir == "" {
+		return fmt.Errorf("secret files directory is not configured")
+	}
+	tmpfs, err := isTmpfs(secretFilesDir)
+	if err != nil {
+		return fmt.Errorf("unable to check the file system of the secret files directory %s: %w", secretFilesDir, err)
+	}
+	if !tmpfs {
+		return fmt.Errorf("secret files directory %s is not on a tmpfs", secretFilesDir)
+	}
+	return nil
+}
+
+// Binds returns the read-only bind mounts of the directories with the secret files of the container
+func Binds(secretFilesDir, taskARN string, container *apicontainer.Container) ([]string, error) {
+	containerPaths := make(map[string]struct{})
+	for _, secret := range container.Secrets {
+		if secret.Type == apicontainer.SecretTypeMountPoint {
+			containerPaths[ContainerPath(secret)] = struct{}{}
+		}
+	}
+
+	var binds []string
+	for containerPath := range containerPaths {
+		hostDir, err := HostDir(secretFilesDir, taskARN, container.Name, containerPath)
+		if err != nil {
+			return nil, err
+		}
+		binds = append(binds, hostDir+":"+containerPath+":ro")
+	}
+	sort.Strings(binds)
+	return binds, nil
+}
+
+// Write writes the value of the secret of the container to its file. The file is replaced atomically, so that the
+// container reads either the previous value or the new one.
+func Write(secretFilesDir, taskARN, containerName string, secret apicontainer.Secret, value string) error {
+	if secret.Name == "" || secret.Name == "." || secret.Name == ".." || strings.ContainsAny(secret.Name, `/\`) {
+		return fmt.Errorf("invalid secret file name %q", secret.Name)
+	}
+	taskDir, err := taskDir(secretFilesDir, taskARN)
+	if err != nil {
+		return err
+	}
+	if err := CheckDir(secretFilesDir); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(taskDir, taskDirMode); err != nil {
+		return err
+	}
+	hostDir, err := HostDir(secretFilesDir, taskARN, containerName, ContainerPath(secret))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(hostDir, containerDirMode); err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(hostDir, "."+secret.Name+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.WriteString(value); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Chmod(secretFileMode); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), filepath.Join(hostDir, secret.Name))
+}
+
+// Remove removes the secret files of the task
+func Remove(secretFilesDir, taskARN string) error {
+	taskDir, err := taskDir(secretFilesDir, taskARN)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(taskDir)
+}
+
+// Refresher refreshes the secret files of a task at an interval until it's stopped
+type Refresher struct {
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// StartRefresher calls refresh at each interval until the refresher is stopped or done returns true. Each refresh
+// is recorded as a SecretFiles.Refresh metric, the failures are also logged with the fields, and the secret files
+// keep their last value until a refresh succeeds.
+func StartRefresher(interval time.Duration, refresh func() error, done func() bool,
+	metricsFactory metrics.EntryFactory, fields logger.Fields) *Refresher {
+	if metricsFactory == nil {
+		metricsFactory = metrics.NewNopEntryFactory()
+	}
+	refresher := &Refresher{
+		stop: make(chan struct{}),
+	}
+	go refresher.run(interval, refresh, done, metricsFactory, fields)
+	return refresher
+}
+
+func (refresher *Refresher) run(interval time.Duration, refresh func() error, done func() bool,
+	metricsFactory metrics.EntryFactory, fields logger.Fields) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	consecutiveFailures := 0
+	for {
+		select {
+		case <-refresher.stop:
+			return
+		case <-ticker.C:
+		}
+		if done() {
+			return
+		}
+		err := refresh()
+		metricsFactory.New(metrics.SecretFilesRefreshMetricName).Done(err)
+		if err != nil {
+			consecutiveFailures++
+			logger.Warn("Unable to refresh secret files, keeping their last value", fields, logger.Fields{
+				"consecutiveFailures": consecutiveFailures,
+				field.Error:           err,
+			})
+			continue
+		}
+		if consecutiveFailures > 0 {
+			logger.Info("Refreshed secret files", fields, logger.Fields{
+				"previousFailures": consecutiveFailures,
+			})
+			consecutiveFailures = 0
+		}
+	}
+}
+
+// Stop stops the refresher
+func (refresher *Refresher) Stop() {
+	refresher.stopOnce.Do(func() {
+		close(refresher.stop)
+	})
+}
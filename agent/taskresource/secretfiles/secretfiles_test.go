@@ -0,0 +1,167 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package secretfiles
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/metrics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	taskARN = "arn:aws:ecs:us-west-2:123456789012:task/cluster/task-id"
+)
+
+func setTmpfs(t *testing.T, tmpfs bool) {
+	isTmpfs = func(string) (bool, error) {
+		return tmpfs, nil
+	}
+	t.Cleanup(func() {
+		isTmpfs = isTmpfsPath
+	})
+}
+
+func TestBinds(t *testing.T) {
+	container := &apicontainer.Container{
+		Name: "app",
+		Secrets: []apicontainer.Secret{
+			{Name: "password", Type: apicontainer.SecretTypeMountPoint},
+			{Name: "username", Type: apicontainer.SecretTypeMountPoint, ContainerPath: "/etc/db/"},
+			{Name: "token", Type: apicontainer.SecretTypeMountPoint, ContainerPath: "/etc/db"},
+			{Name: "API_KEY", Type: apicontainer.SecretTypeEnv},
+		},
+	}
+
+	binds, err := Binds("/run/ecs/secrets", taskARN, container)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"/run/ecs/secrets/task-id/app/%2Fetc%2Fdb:/etc/db:ro",
+		"/run/ecs/secrets/task-id/app/%2Frun%2Fsecrets:/run/secrets:ro",
+	}, binds)
+
+	_, err = Binds("", taskARN, container)
+	assert.Error(t, err)
+}
+
+func TestWrite(t *testing.T) {
+	setTmpfs(t, true)
+	secretFilesDir := t.TempDir()
+	secret := apicontainer.Secret{Name: "password", Type: apicontainer.SecretTypeMountPoint}
+
+	require.NoError(t, Write(secretFilesDir, taskARN, "app", secret, "value1"))
+	hostDir, err := HostDir(secretFilesDir, taskARN, "app", DefaultContainerPath)
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(hostDir, "password"))
+	require.NoError(t, err)
+	assert.Equal(t, "value1", string(data))
+
+	require.NoError(t, Write(secretFilesDir, taskARN, "app", secret, "value2"))
+	data, err = os.ReadFile(filepath.Join(hostDir, "password"))
+	require.NoError(t, err)
+	assert.Equal(t, "value2", string(data))
+	entries, err := os.ReadDir(hostDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files should be removed")
+
+	require.NoError(t, Remove(secretFilesDir, taskARN))
+	_, err = os.Stat(filepath.Join(secretFilesDir, "task-id"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestWriteInvalid(t *testing.T) {
+	setTmpfs(t, true)
+	secretFilesDir := t.TempDir()
+
+	for _, name := range []string{"", ".", "..", "../password", `a\b`} {
+		assert.Error(t, Write(secretFilesDir, taskARN, "app", apicontainer.Secret{Name: name}, "value"), name)
+	}
+	assert.Error(t, Write(secretFilesDir, "invalid-arn", "app", apicontainer.Secret{Name: "password"}, "value"))
+}
+
+func TestWriteNotOnTmpfs(t *testing.T) {
+	setTmpfs(t, false)
+	secretFilesDir := t.TempDir()
+
+	assert.Error(t, Write(secretFilesDir, taskARN, "app", apicontainer.Secret{Name: "password"}, "value"))
+	_, err := os.Stat(filepath.Join(secretFilesDir, "task-id"))
+	assert.True(t, os.IsNotExist(err), "no secret should be written outside of a tmpfs")
+}
+
+func TestRefresher(t *testing.T) {
+	var refreshes int32
+	refresh := func() error {
+		if atomic.AddInt32(&refreshes, 1) == 1 {
+			return errors.New("throttled")
+		}
+		return nil
+	}
+	metricsFactory := metrics.NewPrometheusEntryFactory()
+	refresher := StartRefresher(time.Millisecond, refresh, func() bool { return false }, metricsFactory,
+		logger.Fields{})
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&refreshes) >= 3
+	}, time.Second, time.Millisecond, "refresh should keep running after a failure")
+	refresher.Stop()
+	refresher.Stop()
+
+	recorder := httptest.NewRecorder()
+	metricsFactory.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, metrics.PrometheusMetricsPath, nil))
+	assert.Contains(t, recorder.Body.String(),
+		`ecs_agent_operations_total{op="SecretFiles.Refresh",error="true"} 1`,
+		"refresh failures should be recorded as metrics")
+	assert.Contains(t, recorder.Body.String(), `ecs_agent_operations_total{op="SecretFiles.Refresh",error="false"}`)
+}
+
+func TestCheckDir(t *testing.T) {
+	setTmpfs(t, true)
+	assert.NoError(t, CheckDir(t.TempDir()))
+	assert.Error(t, CheckDir(""))
+
+	setTmpfs(t, false)
+	assert.Error(t, CheckDir(t.TempDir()))
+}
+
+func TestRefresherStopsWhenDone(t *testing.T) {
+	var refreshes int32
+	refresh := func() error {
+		atomic.AddInt32(&refreshes, 1)
+		return nil
+	}
+	var done int32
+	StartRefresher(time.Millisecond, refresh, func() bool { return atomic.LoadInt32(&done) == 1 }, nil,
+		logger.Fields{})
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&refreshes) >= 1
+	}, time.Second, time.Millisecond)
+	atomic.StoreInt32(&done, 1)
+	time.Sleep(10 * time.Millisecond)
+	count := atomic.LoadInt32(&refreshes)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, count, atomic.LoadInt32(&refreshes))
+}
@@ -16,6 +16,7 @@ package ssmsecret
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -27,11 +28,15 @@ import (
 	"github.com/aws/amazon-ecs-agent/agent/ssm"
 	"github.com/aws/amazon-ecs-agent/agent/ssm/factory"
 	"github.com/aws/amazon-ecs-agent/agent/taskresource"
+	"github.com/aws/amazon-ecs-agent/agent/taskresource/secretfiles"
 	resourcestatus "github.com/aws/amazon-ecs-agent/agent/taskresource/status"
 	apicontainerstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/container/status"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/api/task/status"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/credentials"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/ipcompatibility"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/metrics"
 )
 
 const (
@@ -62,6 +67,15 @@ type SSMSecretResource struct {
 	requiredSecrets map[string][]apicontainer.Secret
 	// map to store secret values, key is a combination of valueFrom and region
 	secretData map[string]string
+	// secretFiles are the secrets delivered as files, keyed by container name
+	secretFiles map[string][]apicontainer.Secret
+	// secretFilesDir is the directory where the secret files are written
+	secretFilesDir string
+	// refreshInterval is the interval at which the secret files are refreshed while the task runs
+	refreshInterval time.Duration
+	refresher       *secretfiles.Refresher
+	// metricsFactory records the refreshes of the secret files
+	metricsFactory metrics.EntryFactory
 
 	// ssmClientCreator is a factory interface that creates new SSM clients. This is
 	// needed mostly for testing.
@@ -80,14 +94,22 @@ type SSMSecretResource struct {
 // NewSSMSecretResource creates a new SSMSecretResource object
 func NewSSMSecretResource(taskARN string,
 	ssmSecrets map[string][]apicontainer.Secret,
+	secretFiles map[string][]apicontainer.Secret,
 	executionCredentialsID string,
 	credentialsManager credentials.Manager,
 	ssmClientCreator factory.SSMClientCreator,
-	ipCompatibility ipcompatibility.IPCompatibility) *SSMSecretResource {
+	ipCompatibility ipcompatibility.IPCompatibility,
+	secretFilesDir string,
+	refreshInterval time.Duration,
+	metricsFactory metrics.EntryFactory) *SSMSecretResource {
 
 	s := &SSMSecretResource{
 		taskARN:                taskARN,
 		requiredSecrets:        ssmSecrets,
+		secretFiles:            secretFiles,
+		secretFilesDir:         secretFilesDir,
+		refreshInterval:        refreshInterval,
+		metricsFactory:         metricsFactory,
 		credentialsManager:     credentialsManager,
 		executionCredentialsID: executionCredentialsID,
 		ssmClientCreator:       ssmClientCreator,
@@ -289,8 +311,17 @@ func (secret *SSMSecretResource) Create() error {
 		secret.setTerminalReason(err.Error())
 		return err
 	default:
+	}
+
+	if len(secret.getSecretFiles()) == 0 {
 		return nil
 	}
+	if err := secret.writeSecretFiles(nil); err != nil {
+		secret.setTerminalReason(err.Error())
+		return err
+	}
+	secret.startRefresher()
+	return nil
 }
 
 // getGoRoutineMaxNum calculates the maximum number of goroutines that we need to spin up
@@ -339,26 +370,147 @@ func (secret *SSMSecretResource) retrieveSSMSecretValuesByRegion(region string,
 func (secret *SSMSecretResource) retrieveSSMSecretValues(region string, names []string, iamCredentials credentials.IAMRoleCredentials, wg *sync.WaitGroup, errorEvents chan error) {
 	defer wg.Done()
 
-	ssmClient, err := secret.ssmClientCreator.NewSSMClient(region, iamCredentials, secret.ipCompatibility)
+	secValueMap, err := secret.fetchSSMSecretValues(region, names, iamCredentials)
 	if err != nil {
-		errorEvents <- fmt.Errorf("unable to create SSM client in %s: %v", region, err)
+		errorEvents <- err
 		return
 	}
+
+	secret.lock.Lock()
+	defer secret.lock.Unlock()
+
+	// put secret value in secretData
+	for secretName, secretValue := range secValueMap {
+		secretKey := secretName + "_" + region
+		secret.secretData[secretKey] = secretValue
+	}
+}
+
+// fetchSSMSecretValues fetches the secret values from SSM parameter store, keyed by secret name
+func (secret *SSMSecretResource) fetchSSMSecretValues(region string, names []string,
+	iamCredentials credentials.IAMRoleCredentials) (map[string]string, error) {
+	ssmClient, err := secret.ssmClientCreator.NewSSMClient(region, iamCredentials, secret.ipCompatibility)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create SSM client in %s: %v", region, err)
+	}
 	seelog.Debugf("ssm secret resource: retrieving resource for secrets %v in region [%s] in task: [%s]", names, region, secret.taskARN)
 	secValueMap, err := ssm.GetSecretsFromSSM(names, ssmClient)
 	if err != nil {
-		errorEvents <- fmt.Errorf("fetching secret data from SSM Parameter Store in %s: %v", region, err)
+		return nil, fmt.Errorf("fetching secret data from SSM Parameter Store in %s: %v", region, err)
+	}
+	return secValueMap, nil
+}
+
+// writeSecretFiles writes the secret files of the containers whose secret key is in keys, or all of them when keys
+// is nil
+func (secret *SSMSecretResource) writeSecretFiles(keys map[string]struct{}) error {
+	for containerName, secrets := range secret.getSecretFiles() {
+		for _, s := range secrets {
+			secretKey := s.GetSecretResourceCacheKey()
+			if _, ok := keys[secretKey]; keys != nil && !ok {
+				continue
+			}
+			secretValue, ok := secret.GetCachedSecretValue(secretKey)
+			if !ok {
+				return fmt.Errorf("ssm secret resource: no value for secret file %s of container %s", s.Name,
+					containerName)
+			}
+			if err := secretfiles.Write(secret.secretFilesDir, secret.taskARN, containerName, s,
+				secretValue); err != nil {
+				return fmt.Errorf("ssm secret resource: unable to write secret file %s of container %s: %w", s.Name,
+					containerName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// startRefresher starts refreshing the secret files at the refresh interval until the task stops
+func (secret *SSMSecretResource) startRefresher() {
+	secret.lock.Lock()
+	defer secret.lock.Unlock()
+
+	if secret.refresher != nil || secret.refreshInterval <= 0 {
 		return
 	}
+	secret.refresher = secretfiles.StartRefresher(secret.refreshInterval, secret.refreshSecretFiles,
+		secret.DesiredTerminal, secret.metricsFactory, logger.Fields{
+			field.TaskARN: secret.taskARN,
+			"resource":    ResourceName,
+		})
+}
 
+// stopRefresher stops refreshing the secret files
+func (secret *SSMSecretResource) stopRefresher() {
 	secret.lock.Lock()
 	defer secret.lock.Unlock()
 
-	// put secret value in secretData
-	for secretName, secretValue := range secValueMap {
-		secretKey := secretName + "_" + region
-		secret.secretData[secretKey] = secretValue
+	if secret.refresher != nil {
+		secret.refresher.Stop()
+		secret.refresher = nil
+	}
+}
+
+// refreshSecretFiles fetches the secrets delivered as files again with the execution role credentials, and rewrites
+// the files of the secrets whose value changed
+func (secret *SSMSecretResource) refreshSecretFiles() error {
+	executionCredentials, ok := secret.credentialsManager.GetTaskCredentials(secret.getExecutionCredentialsID())
+	if !ok {
+		return errors.New("ssm secret resource: unable to find execution role credentials")
+	}
+	iamCredentials := executionCredentials.GetIAMRoleCredentials()
+
+	namesByRegion := make(map[string][]string)
+	seen := make(map[string]struct{})
+	for _, secrets := range secret.getSecretFiles() {
+		for _, s := range secrets {
+			secretKey := s.GetSecretResourceCacheKey()
+			if _, ok := seen[secretKey]; ok {
+				continue
+			}
+			seen[secretKey] = struct{}{}
+			namesByRegion[s.Region] = append(namesByRegion[s.Region], s.ValueFrom)
+		}
+	}
+
+	changed := make(map[string]struct{})
+	var errs []string
+	for region, names := range namesByRegion {
+		for start := 0; start < len(names); start += MaxBatchNum {
+			end := start + MaxBatchNum
+			if end > len(names) {
+				end = len(names)
+			}
+			secValueMap, err := secret.fetchSSMSecretValues(region, names[start:end], iamCredentials)
+			if err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			for secretName, secretValue := range secValueMap {
+				secretKey := secretName + "_" + region
+				if cachedValue, ok := secret.GetCachedSecretValue(secretKey); ok && cachedValue == secretValue {
+					continue
+				}
+				secret.SetCachedSecretValue(secretKey, secretValue)
+				changed[secretKey] = struct{}{}
+			}
+		}
+	}
+
+	if err := secret.writeSecretFiles(changed); err != nil {
+		errs = append(errs, err.Error())
 	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ";"))
+	}
+	if len(changed) > 0 {
+		logger.Info("Refreshed secret files", logger.Fields{
+			field.TaskARN:    secret.taskARN,
+			"resource":       ResourceName,
+			"changedSecrets": len(changed),
+		})
+	}
+	return nil
 }
 
 // getRequiredSecrets returns the requiredSecrets field of ssmsecret task resource
@@ -369,6 +521,14 @@ func (secret *SSMSecretResource) getRequiredSecrets() map[string][]apicontainer.
 	return secret.requiredSecrets
 }
 
+// getSecretFiles returns the secrets delivered as files, keyed by container name
+func (secret *SSMSecretResource) getSecretFiles() map[string][]apicontainer.Secret {
+	secret.lock.RLock()
+	defer secret.lock.RUnlock()
+
+	return secret.secretFiles
+}
+
 // getExecutionCredentialsID returns the execution role's credential ID
 func (secret *SSMSecretResource) getExecutionCredentialsID() string {
 	secret.lock.RLock()
@@ -377,9 +537,13 @@ func (secret *SSMSecretResource) getExecutionCredentialsID() string {
 	return secret.executionCredentialsID
 }
 
-// Cleanup removes the secret value created for the task
+// Cleanup removes the secret value and the secret files created for the task
 func (secret *SSMSecretResource) Cleanup() error {
+	secret.stopRefresher()
 	secret.clearSSMSecretValue()
+	if len(secret.getSecretFiles()) > 0 {
+		return secretfiles.Remove(secret.secretFilesDir, secret.taskARN)
+	}
 	return nil
 }
 
@@ -424,6 +588,9 @@ func (secret *SSMSecretResource) Initialize(
 	secret.credentialsManager = resourceFields.CredentialsManager
 	secret.ssmClientCreator = resourceFields.SSMClientCreator
 	secret.ipCompatibility = config.InstanceIPCompatibility
+	secret.secretFilesDir = config.SecretFilesDir
+	secret.refreshInterval = config.SecretRefreshInterval
+	secret.metricsFactory = resourceFields.MetricsFactory
 
 	// if task hasn't turn to 'created' status, and it's desire status is 'running'
 	// the resource status needs to be reset to 'NONE' status so the secret value
@@ -432,6 +599,12 @@ func (secret *SSMSecretResource) Initialize(
 		taskDesiredStatus <= status.TaskRunning {
 		secret.SetKnownStatus(resourcestatus.ResourceStatusNone)
 	}
+
+	// the secret files of the running tasks keep being refreshed after the agent restarts
+	if config.SecretFilesEnabled.Enabled() && secret.KnownCreated() && !secret.DesiredTerminal() &&
+		len(secret.getSecretFiles()) > 0 {
+		secret.startRefresher()
+	}
 }
 
 type SSMSecretResourceJSON struct {
@@ -440,6 +613,7 @@ type SSMSecretResourceJSON struct {
 	DesiredStatus          *SSMSecretStatus                 `json:"desiredStatus"`
 	KnownStatus            *SSMSecretStatus                 `json:"knownStatus"`
 	RequiredSecrets        map[string][]apicontainer.Secret `json:"secretResources"`
+	SecretFiles            map[string][]apicontainer.Secret `json:"secretFiles,omitempty"`
 	ExecutionCredentialsID string                           `json:"executionCredentialsID"`
 }
 
@@ -463,6 +637,7 @@ func (secret *SSMSecretResource) MarshalJSON() ([]byte, error) {
 			return &s
 		}(),
 		RequiredSecrets:        secret.getRequiredSecrets(),
+		SecretFiles:            secret.getSecretFiles(),
 		ExecutionCredentialsID: secret.getExecutionCredentialsID(),
 	})
 }
@@ -487,6 +662,7 @@ func (secret *SSMSecretResource) UnmarshalJSON(b []byte) error {
 	if temp.RequiredSecrets != nil {
 		secret.requiredSecrets = temp.RequiredSecrets
 	}
+	secret.secretFiles = temp.SecretFiles
 	secret.taskARN = temp.TaskARN
 	secret.executionCredentialsID = temp.ExecutionCredentialsID
 
@@ -0,0 +1,121 @@
+//go:build linux && unit
+// +build linux,unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package ssmsecret
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	mock_factory "github.com/aws/amazon-ecs-agent/agent/ssm/factory/mocks"
+	mock_ssm "github.com/aws/amazon-ecs-agent/agent/ssm/mocks"
+	"github.com/aws/amazon-ecs-agent/agent/taskresource/secretfiles"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/credentials"
+	mock_credentials "github.com/aws/amazon-ecs-agent/ecs-agent/credentials/mocks"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+const secretFilesTaskARN = "arn:aws:ecs:us-west-2:123456789012:task/cluster/task-id"
+
+// tmpfsDir returns a temporary directory on the tmpfs mounted at /dev/shm
+func tmpfsDir(t *testing.T) string {
+	var stat unix.Statfs_t
+	if err := unix.Statfs("/dev/shm", &stat); err != nil || stat.Type != unix.TMPFS_MAGIC {
+		t.Skip("no tmpfs mounted at /dev/shm")
+	}
+	dir, err := os.MkdirTemp("/dev/shm", "secretfiles")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+	return dir
+}
+
+func ssmParameters(value string) *ssm.GetParametersOutput {
+	return &ssm.GetParametersOutput{
+		Parameters: []ssmtypes.Parameter{
+			{
+				Name:  aws.String(valueFrom1),
+				Value: aws.String(value),
+			},
+		},
+	}
+}
+
+func TestCreateAndRefreshSecretFiles(t *testing.T) {
+	secretFilesDir := tmpfsDir(t)
+	fileSecret := apicontainer.Secret{
+		Name:      "db_password",
+		ValueFrom: valueFrom1,
+		Region:    region1,
+		Provider:  apicontainer.SecretProviderSSM,
+		Type:      apicontainer.SecretTypeMountPoint,
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	credentialsManager := mock_credentials.NewMockManager(ctrl)
+	ssmClientCreator := mock_factory.NewMockSSMClientCreator(ctrl)
+	mockSSMClient := mock_ssm.NewMockSSMClient(ctrl)
+
+	creds := credentials.TaskIAMRoleCredentials{}
+	credentialsManager.EXPECT().GetTaskCredentials(executionCredentialsID).Return(creds, true).Times(3)
+	ssmClientCreator.EXPECT().NewSSMClient(region1, gomock.Any(), testIPCompatibility).Return(mockSSMClient, nil).Times(3)
+	gomock.InOrder(
+		mockSSMClient.EXPECT().GetParameters(gomock.Any(), gomock.Any()).Return(ssmParameters("value1"), nil),
+		mockSSMClient.EXPECT().GetParameters(gomock.Any(), gomock.Any()).Return(ssmParameters("value2"), nil),
+		mockSSMClient.EXPECT().GetParameters(gomock.Any(), gomock.Any()).Return(nil, assert.AnError),
+	)
+
+	ssmRes := NewSSMSecretResource(secretFilesTaskARN,
+		map[string][]apicontainer.Secret{region1: {fileSecret}},
+		map[string][]apicontainer.Secret{"app": {fileSecret}},
+		executionCredentialsID, credentialsManager, ssmClientCreator, testIPCompatibility, secretFilesDir, 0,
+		nil)
+	require.NoError(t, ssmRes.Create())
+
+	hostDir, err := secretfiles.HostDir(secretFilesDir, secretFilesTaskARN, "app", secretfiles.DefaultContainerPath)
+	require.NoError(t, err)
+	secretFile := filepath.Join(hostDir, "db_password")
+	data, err := os.ReadFile(secretFile)
+	require.NoError(t, err)
+	assert.Equal(t, "value1", string(data))
+
+	require.NoError(t, ssmRes.refreshSecretFiles())
+	data, err = os.ReadFile(secretFile)
+	require.NoError(t, err)
+	assert.Equal(t, "value2", string(data))
+
+	// the secret file keeps its last value when the refresh fails
+	assert.Error(t, ssmRes.refreshSecretFiles())
+	data, err = os.ReadFile(secretFile)
+	require.NoError(t, err)
+	assert.Equal(t, "value2", string(data))
+
+	require.NoError(t, ssmRes.Cleanup())
+	_, err = os.Stat(secretFile)
+	assert.True(t, os.IsNotExist(err))
+}
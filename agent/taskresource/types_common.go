@@ -20,6 +20,7 @@ import (
 	ssmfactory "github.com/aws/amazon-ecs-agent/agent/ssm/factory"
 	"github.com/aws/amazon-ecs-agent/agent/utils/ioutilwrapper"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/credentials"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/metrics"
 )
 
 type ResourceFieldsCommon struct {
@@ -30,4 +31,5 @@ type ResourceFieldsCommon struct {
 	S3ClientCreator    s3factory.S3ClientCreator
 	CredentialsManager credentials.Manager
 	EC2InstanceID      string
+	MetricsFactory     metrics.EntryFactory
 }
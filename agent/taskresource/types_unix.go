@@ -19,6 +19,7 @@ package taskresource
 import (
 	"context"
 
+	"github.com/aws/amazon-ecs-agent/agent/cdi"
 	"github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi"
 	"github.com/aws/amazon-ecs-agent/agent/gpu"
 	cgroup "github.com/aws/amazon-ecs-agent/agent/taskresource/cgroup/control"
@@ -32,4 +33,5 @@ type ResourceFields struct {
 	Ctx              context.Context
 	DockerClient     dockerapi.DockerClient
 	NvidiaGPUManager gpu.GPUManager
+	CDIDeviceManager cdi.DeviceManager
 }
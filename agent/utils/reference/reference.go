@@ -16,6 +16,7 @@ package reference
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
@@ -117,3 +118,92 @@ func DigestExists(imageRef string) bool {
 	_, ok := parsedImageRef.(reference.Digested)
 	return ok
 }
+
+// mirrorPrefixPlaceholder completes a registry mirror prefix into a repository name with at least two path
+// components, so that the first component of the prefix is only normalized as a registry when it is one
+const mirrorPrefixPlaceholder = "placeholder/image"
+
+// ValidateRegistryMirror checks a registry mirror rule, e.g. "docker.io/*" mirrored by
+// "mirror.internal/dockerhub/*". Both the pattern and the mirror must be repository name prefixes
+// followed by "/*".
+func ValidateRegistryMirror(pattern string, mirror string) error {
+	if _, err := normalizeMirrorPattern(pattern); err != nil {
+		return err
+	}
+	if _, err := mirrorPrefix(mirror); err != nil {
+		return err
+	}
+	return nil
+}
+
+// mirrorPrefix returns the repository name prefix of a registry mirror rule pattern or mirror
+func mirrorPrefix(prefix string) (string, error) {
+	if !strings.HasSuffix(prefix, "/*") {
+		return "", fmt.Errorf("'%s' must end with '/*'", prefix)
+	}
+	prefix = strings.TrimSuffix(prefix, "*")
+	// The prefix must be the start of a valid repository name
+	if _, err := reference.ParseNormalizedNamed(prefix + mirrorPrefixPlaceholder); err != nil {
+		return "", fmt.Errorf("'%s*' is not a valid repository prefix: %w", prefix, err)
+	}
+	return prefix, nil
+}
+
+// normalizeMirrorPattern returns the repository name prefix of a registry mirror rule pattern, normalized like
+// the image names it's matched against, e.g. "library/*" is "docker.io/library/"
+func normalizeMirrorPattern(pattern string) (string, error) {
+	prefix, err := mirrorPrefix(pattern)
+	if err != nil {
+		return "", err
+	}
+	namedRef, err := reference.ParseNormalizedNamed(prefix + mirrorPrefixPlaceholder)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(namedRef.Name(), mirrorPrefixPlaceholder), nil
+}
+
+// GetMirrorRef returns the reference of the image in its registry mirror, and whether a mirror
+// applies to the image. The mirrors map the patterns to the mirrors as validated by
+// ValidateRegistryMirror, and the longest pattern matching the fully qualified name of the image
+// is applied, e.g. "nginx:latest" is "mirror.internal/dockerhub/library/nginx:latest" with the
+// "docker.io/*" pattern mirrored by "mirror.internal/dockerhub/*". The patterns are normalized like
+// the image names, so "library/*" matches "nginx" too. The tag and digest of the image are kept.
+func GetMirrorRef(imageRef string, mirrors map[string]string) (string, bool) {
+	if len(mirrors) == 0 {
+		return "", false
+	}
+	namedRef, err := reference.ParseNormalizedNamed(imageRef)
+	if err != nil {
+		return "", false
+	}
+	name := namedRef.Name()
+
+	longestPrefix := ""
+	replacement := ""
+	for pattern, mirror := range mirrors {
+		prefix, err := normalizeMirrorPattern(pattern)
+		if err != nil {
+			continue
+		}
+		if strings.HasPrefix(name, prefix) && len(prefix) > len(longestPrefix) {
+			longestPrefix = prefix
+			replacement = strings.TrimSuffix(mirror, "*")
+		}
+	}
+	if longestPrefix == "" {
+		return "", false
+	}
+
+	// Keep the tag and digest following the name
+	mirrorRef := replacement + strings.TrimPrefix(namedRef.String(), longestPrefix)
+	if _, err := reference.ParseNormalizedNamed(mirrorRef); err != nil {
+		logger.Warn("Invalid image reference in registry mirror, not using the mirror", logger.Fields{
+			field.Image: imageRef,
+			"mirrorRef": mirrorRef,
+			field.Error: err,
+		})
+		return "", false
+	}
+	return mirrorRef, true
+}
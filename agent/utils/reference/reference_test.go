@@ -292,3 +292,137 @@ func TestDigestExists(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateRegistryMirror(t *testing.T) {
+	tcs := []struct {
+		name        string
+		pattern     string
+		mirror      string
+		expectError bool
+	}{
+		{name: "registry", pattern: "docker.io/*", mirror: "mirror.internal/dockerhub/*"},
+		{name: "repository prefix", pattern: "ghcr.io/org/*", mirror: "mirror.internal:5000/ghcr/org/*"},
+		{name: "pattern without wildcard", pattern: "docker.io", mirror: "mirror.internal/dockerhub/*", expectError: true},
+		{name: "mirror without wildcard", pattern: "docker.io/*", mirror: "mirror.internal/dockerhub", expectError: true},
+		{name: "invalid mirror", pattern: "docker.io/*", mirror: "Mirror Internal/*", expectError: true},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateRegistryMirror(tc.pattern, tc.mirror)
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetMirrorRef(t *testing.T) {
+	mirrors := map[string]string{
+		"docker.io/*":          "mirror.internal/dockerhub/*",
+		"docker.io/myorg/*":    "mirror.internal/myorg/*",
+		"public.ecr.aws/lib/*": "mirror.internal/ecr-public-lib/*",
+	}
+	tcs := []struct {
+		name        string
+		imageRef    string
+		expectedRef string
+		expectedOk  bool
+	}{
+		{
+			name:        "docker hub official image",
+			imageRef:    "nginx",
+			expectedRef: "mirror.internal/dockerhub/library/nginx",
+			expectedOk:  true,
+		},
+		{
+			name:        "tag is kept",
+			imageRef:    "docker.io/library/nginx:1.27",
+			expectedRef: "mirror.internal/dockerhub/library/nginx:1.27",
+			expectedOk:  true,
+		},
+		{
+			name:        "longest pattern is applied",
+			imageRef:    "myorg/app:v1",
+			expectedRef: "mirror.internal/myorg/app:v1",
+			expectedOk:  true,
+		},
+		{
+			name:        "digest is kept",
+			imageRef:    "nginx@sha256:c3839dd800b9eb7603340509769c43e146a74c63dca3045a8e7dc8ee07e53966",
+			expectedRef: "mirror.internal/dockerhub/library/nginx@sha256:c3839dd800b9eb7603340509769c43e146a74c63dca3045a8e7dc8ee07e53966",
+			expectedOk:  true,
+		},
+		{
+			name:       "pattern only matches whole path components",
+			imageRef:   "public.ecr.aws/library/alpine",
+			expectedOk: false,
+		},
+		{
+			name:       "no matching pattern",
+			imageRef:   "ghcr.io/org/app:latest",
+			expectedOk: false,
+		},
+		{
+			name:       "invalid imageRef",
+			imageRef:   "invalid imageRef",
+			expectedOk: false,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			mirrorRef, ok := GetMirrorRef(tc.imageRef, mirrors)
+			assert.Equal(t, tc.expectedOk, ok)
+			assert.Equal(t, tc.expectedRef, mirrorRef)
+		})
+	}
+
+	_, ok := GetMirrorRef("nginx", nil)
+	assert.False(t, ok)
+}
+
+func TestGetMirrorRefNormalizesPatterns(t *testing.T) {
+	tcs := []struct {
+		name        string
+		pattern     string
+		imageRef    string
+		expectedRef string
+		expectedOk  bool
+	}{
+		{
+			name:        "docker hub official images without registry",
+			pattern:     "library/*",
+			imageRef:    "nginx:1.27",
+			expectedRef: "mirror.internal/hub/nginx:1.27",
+			expectedOk:  true,
+		},
+		{
+			name:        "docker hub organization without registry",
+			pattern:     "myorg/*",
+			imageRef:    "docker.io/myorg/app",
+			expectedRef: "mirror.internal/hub/app",
+			expectedOk:  true,
+		},
+		{
+			name:       "docker hub organization doesn't match other registries",
+			pattern:    "myorg/*",
+			imageRef:   "ghcr.io/myorg/app",
+			expectedOk: false,
+		},
+		{
+			name:        "registry with port",
+			pattern:     "registry.internal:5000/*",
+			imageRef:    "registry.internal:5000/app",
+			expectedRef: "mirror.internal/hub/app",
+			expectedOk:  true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			mirrorRef, ok := GetMirrorRef(tc.imageRef, map[string]string{tc.pattern: "mirror.internal/hub/*"})
+			assert.Equal(t, tc.expectedOk, ok)
+			assert.Equal(t, tc.expectedRef, mirrorRef)
+		})
+	}
+}
@@ -21,11 +21,30 @@ import (
 	apicontainerstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/container/status"
 )
 
+const (
+	// defaultBackoffMaxPeriod is the maximum time a restart is delayed by when the restart policy
+	// doesn't specify a maximum backoff period.
+	defaultBackoffMaxPeriod = 5 * time.Minute
+	// minBackoffResetPeriod is the minimum time a container has to run for before its restart backoff
+	// and its consecutive restart count are reset. It's extended to twice the maximum backoff period
+	// when that's longer.
+	minBackoffResetPeriod = 10 * time.Minute
+)
+
 type RestartTracker struct {
 	RestartCount  int           `json:"restartCount,omitempty"`
 	LastRestartAt time.Time     `json:"lastRestartAt,omitempty"`
 	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty"`
-	lock          sync.RWMutex
+	// ConsecutiveRestartCount is the number of times the container has been restarted since it
+	// last ran for longer than the backoff reset period. The restart backoff and the restart limit
+	// are based on it.
+	ConsecutiveRestartCount int `json:"consecutiveRestartCount,omitempty"`
+	// NextRestartAt is set while a restart is delayed by the restart backoff.
+	NextRestartAt time.Time `json:"nextRestartAt,omitempty"`
+	// RestartLimitReached is set once the container exited after being restarted the
+	// maximum number of consecutive times allowed by the restart policy.
+	RestartLimitReached bool `json:"restartLimitReached,omitempty"`
+	lock                sync.RWMutex
 }
 
 // RestartPolicy represents a policy that contains key information considered when
@@ -34,6 +53,17 @@ type RestartPolicy struct {
 	Enabled              bool  `json:"enabled"`
 	IgnoredExitCodes     []int `json:"ignoredExitCodes"`
 	RestartAttemptPeriod int   `json:"restartAttemptPeriod"`
+	// BackoffInitialPeriod is the number of seconds the first restart is delayed by. Each
+	// following consecutive restart is delayed twice as long as the previous one, up to
+	// BackoffMaxPeriod. Restarts aren't delayed if it's not set.
+	BackoffInitialPeriod int `json:"backoffInitialPeriod,omitempty"`
+	// BackoffMaxPeriod is the maximum number of seconds a restart is delayed by. It defaults
+	// to 5 minutes.
+	BackoffMaxPeriod int `json:"backoffMaxPeriod,omitempty"`
+	// MaxRestartCount is the number of consecutive times the container can be restarted. The
+	// container isn't restarted anymore once it's reached, and is then treated as an essential
+	// container. The container can be restarted any number of times if it's not set.
+	MaxRestartCount int `json:"maxRestartCount,omitempty"`
 }
 
 func NewRestartTracker(restartPolicy RestartPolicy) *RestartTracker {
@@ -54,6 +84,59 @@ func (rt *RestartTracker) GetRestartCount() int {
 	return rt.RestartCount
 }
 
+func (rt *RestartTracker) GetConsecutiveRestartCount() int {
+	rt.lock.RLock()
+	defer rt.lock.RUnlock()
+	return rt.ConsecutiveRestartCount
+}
+
+func (rt *RestartTracker) GetNextRestartAt() time.Time {
+	rt.lock.RLock()
+	defer rt.lock.RUnlock()
+	return rt.NextRestartAt
+}
+
+// IsRestartPending returns whether a restart of the container is delayed by the restart backoff.
+func (rt *RestartTracker) IsRestartPending() bool {
+	rt.lock.RLock()
+	defer rt.lock.RUnlock()
+	return !rt.NextRestartAt.IsZero()
+}
+
+func (rt *RestartTracker) IsRestartLimitReached() bool {
+	rt.lock.RLock()
+	defer rt.lock.RUnlock()
+	return rt.RestartLimitReached
+}
+
+// IsRestartLimitExceeded returns whether the container has been restarted the maximum number of
+// consecutive times allowed by the restart policy.
+func (rt *RestartTracker) IsRestartLimitExceeded() bool {
+	rt.lock.RLock()
+	defer rt.lock.RUnlock()
+	return rt.RestartPolicy.MaxRestartCount > 0 && rt.ConsecutiveRestartCount >= rt.RestartPolicy.MaxRestartCount
+}
+
+// SetRestartLimitReached records that the container isn't restarted anymore because it reached the
+// restart limit of the restart policy.
+func (rt *RestartTracker) SetRestartLimitReached() {
+	rt.lock.Lock()
+	defer rt.lock.Unlock()
+	rt.RestartLimitReached = true
+}
+
+// RecordExit updates the restart tracker's metadata after the container has exited. The
+// consecutive restart count is reset if the container ran for longer than the backoff reset
+// period, so that the restart backoff and the restart limit only account for the restarts of a
+// container which keeps failing. It must be called before ShouldRestart and BackoffDelay.
+func (rt *RestartTracker) RecordExit(startedAt time.Time) {
+	rt.lock.Lock()
+	defer rt.lock.Unlock()
+	if time.Since(rt.startTime(startedAt)) >= rt.backoffResetPeriod() {
+		rt.ConsecutiveRestartCount = 0
+	}
+}
+
 // RecordRestart updates the restart tracker's metadata after a restart has occurred.
 // This metadata is used to calculate when restarts should occur and track how many
 // have occurred. It is not the job of this method to determine if a restart should
@@ -62,12 +145,52 @@ func (rt *RestartTracker) RecordRestart() {
 	rt.lock.Lock()
 	defer rt.lock.Unlock()
 	rt.RestartCount++
+	rt.ConsecutiveRestartCount++
 	rt.LastRestartAt = time.Now()
+	rt.NextRestartAt = time.Time{}
+}
+
+// BackoffDelay returns how long the next restart should be delayed by. The delay doubles
+// with every consecutive restart, starting from the initial backoff period and capped by
+// the maximum backoff period.
+func (rt *RestartTracker) BackoffDelay() time.Duration {
+	rt.lock.RLock()
+	defer rt.lock.RUnlock()
+
+	initial := time.Duration(rt.RestartPolicy.BackoffInitialPeriod) * time.Second
+	if initial <= 0 {
+		return 0
+	}
+	max := rt.backoffMaxPeriod()
+	delay := initial
+	for i := 0; i < rt.ConsecutiveRestartCount && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
+
+// DelayRestart records that the next restart is delayed until the given time by the
+// restart backoff.
+func (rt *RestartTracker) DelayRestart(nextRestartAt time.Time) {
+	rt.lock.Lock()
+	defer rt.lock.Unlock()
+	rt.NextRestartAt = nextRestartAt
+}
+
+// CancelDelayedRestart records that the restart delayed by the restart backoff won't occur.
+func (rt *RestartTracker) CancelDelayedRestart() {
+	rt.lock.Lock()
+	defer rt.lock.Unlock()
+	rt.NextRestartAt = time.Time{}
 }
 
 // ShouldRestart returns whether the container should restart and a reason string
 // explaining why not. The reset attempt period will be calculated first
 // with LastRestart at, using the passed in startedAt if it does not exist.
+// The restart limit isn't considered, see IsRestartLimitExceeded.
 func (rt *RestartTracker) ShouldRestart(exitCode *int, startedAt time.Time,
 	desiredStatus apicontainerstatus.ContainerStatus) (bool, string) {
 	rt.lock.RLock()
@@ -88,12 +211,35 @@ func (rt *RestartTracker) ShouldRestart(exitCode *int, startedAt time.Time,
 		}
 	}
 
-	startTime := startedAt
-	if !rt.LastRestartAt.IsZero() {
-		startTime = rt.LastRestartAt
-	}
-	if time.Since(startTime).Seconds() < float64(rt.RestartPolicy.RestartAttemptPeriod) {
+	if time.Since(rt.startTime(startedAt)).Seconds() < float64(rt.RestartPolicy.RestartAttemptPeriod) {
 		return false, "attempt reset period has not elapsed"
 	}
 	return true, ""
 }
+
+// startTime returns when the container last started, which is when it was last restarted
+// if it was, or the passed in startedAt otherwise.
+func (rt *RestartTracker) startTime(startedAt time.Time) time.Time {
+	if !rt.LastRestartAt.IsZero() {
+		return rt.LastRestartAt
+	}
+	return startedAt
+}
+
+func (rt *RestartTracker) backoffMaxPeriod() time.Duration {
+	max := time.Duration(rt.RestartPolicy.BackoffMaxPeriod) * time.Second
+	if max <= 0 {
+		max = defaultBackoffMaxPeriod
+	}
+	if initial := time.Duration(rt.RestartPolicy.BackoffInitialPeriod) * time.Second; max < initial {
+		max = initial
+	}
+	return max
+}
+
+func (rt *RestartTracker) backoffResetPeriod() time.Duration {
+	if period := 2 * rt.backoffMaxPeriod(); period > minBackoffResetPeriod {
+		return period
+	}
+	return minBackoffResetPeriod
+}
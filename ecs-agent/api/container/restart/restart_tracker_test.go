@@ -184,3 +184,183 @@ func TestRecordRestartPolicy(t *testing.T) {
 	assert.Equal(t, 0, len(rt.RestartPolicy.IgnoredExitCodes))
 	assert.NotNil(t, rt.RestartPolicy)
 }
+
+func TestRestartLimit(t *testing.T) {
+	rt := NewRestartTracker(RestartPolicy{
+		Enabled:         true,
+		MaxRestartCount: 2,
+	})
+	exitCode := 1
+	startedAt := time.Now().Add(-time.Minute)
+
+	for i := 0; i < 2; i++ {
+		rt.RecordExit(startedAt)
+		shouldRestart, _ := rt.ShouldRestart(&exitCode, startedAt, apicontainerstatus.ContainerRunning)
+		require.True(t, shouldRestart)
+		require.False(t, rt.IsRestartLimitExceeded())
+		rt.RecordRestart()
+	}
+
+	rt.RecordExit(startedAt)
+	assert.True(t, rt.IsRestartLimitExceeded())
+	// Checking whether the container should restart doesn't record that the limit was reached
+	shouldRestart, _ := rt.ShouldRestart(&exitCode, startedAt, apicontainerstatus.ContainerRunning)
+	assert.True(t, shouldRestart)
+	assert.False(t, rt.IsRestartLimitReached())
+	rt.SetRestartLimitReached()
+	assert.True(t, rt.IsRestartLimitReached())
+}
+
+func TestRecordExitResetsConsecutiveRestartCount(t *testing.T) {
+	testCases := []struct {
+		name                            string
+		rp                              RestartPolicy
+		ranFor                          time.Duration
+		expectedConsecutiveRestartCount int
+	}{
+		{
+			name:                            "short run",
+			rp:                              RestartPolicy{Enabled: true, MaxRestartCount: 5},
+			ranFor:                          time.Minute,
+			expectedConsecutiveRestartCount: 2,
+		},
+		{
+			name:                            "stable run",
+			rp:                              RestartPolicy{Enabled: true, MaxRestartCount: 5},
+			ranFor:                          minBackoffResetPeriod,
+			expectedConsecutiveRestartCount: 0,
+		},
+		{
+			name: "run shorter than twice the maximum backoff period",
+			rp: RestartPolicy{
+				Enabled:              true,
+				BackoffInitialPeriod: 60,
+				BackoffMaxPeriod:     600,
+			},
+			ranFor:                          15 * time.Minute,
+			expectedConsecutiveRestartCount: 2,
+		},
+		{
+			name: "run longer than twice the maximum backoff period",
+			rp: RestartPolicy{
+				Enabled:              true,
+				BackoffInitialPeriod: 60,
+				BackoffMaxPeriod:     600,
+			},
+			ranFor:                          20 * time.Minute,
+			expectedConsecutiveRestartCount: 0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rt := NewRestartTracker(tc.rp)
+			rt.RecordRestart()
+			rt.RecordRestart()
+			rt.LastRestartAt = time.Now().Add(-tc.ranFor)
+
+			rt.RecordExit(time.Time{})
+			assert.Equal(t, tc.expectedConsecutiveRestartCount, rt.GetConsecutiveRestartCount())
+			assert.Equal(t, 2, rt.GetRestartCount())
+		})
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	testCases := []struct {
+		name           string
+		rp             RestartPolicy
+		expectedDelays []time.Duration
+	}{
+		{
+			name:           "no backoff",
+			rp:             RestartPolicy{Enabled: true},
+			expectedDelays: []time.Duration{0, 0, 0},
+		},
+		{
+			name: "exponential backoff with cap",
+			rp: RestartPolicy{
+				Enabled:              true,
+				BackoffInitialPeriod: 10,
+				BackoffMaxPeriod:     60,
+			},
+			expectedDelays: []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second,
+				60 * time.Second, 60 * time.Second},
+		},
+		{
+			name: "exponential backoff with default cap",
+			rp: RestartPolicy{
+				Enabled:              true,
+				BackoffInitialPeriod: 60,
+			},
+			expectedDelays: []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute,
+				defaultBackoffMaxPeriod, defaultBackoffMaxPeriod},
+		},
+		{
+			name: "maximum period lower than initial period",
+			rp: RestartPolicy{
+				Enabled:              true,
+				BackoffInitialPeriod: 10,
+				BackoffMaxPeriod:     5,
+			},
+			expectedDelays: []time.Duration{10 * time.Second, 10 * time.Second},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rt := NewRestartTracker(tc.rp)
+			for _, expectedDelay := range tc.expectedDelays {
+				assert.Equal(t, expectedDelay, rt.BackoffDelay())
+				rt.RecordRestart()
+			}
+		})
+	}
+}
+
+func TestBackoffDelayResetAfterStableRun(t *testing.T) {
+	rt := NewRestartTracker(RestartPolicy{
+		Enabled:              true,
+		BackoffInitialPeriod: 10,
+	})
+	for i := 0; i < 3; i++ {
+		rt.RecordRestart()
+	}
+	rt.RecordExit(time.Time{})
+	assert.Equal(t, 80*time.Second, rt.BackoffDelay())
+
+	rt.LastRestartAt = time.Now().Add(-time.Hour)
+	rt.RecordExit(time.Time{})
+	assert.Equal(t, 10*time.Second, rt.BackoffDelay())
+}
+
+func TestDelayRestart(t *testing.T) {
+	rt := NewRestartTracker(RestartPolicy{
+		Enabled:              true,
+		BackoffInitialPeriod: 10,
+	})
+	nextRestartAt := time.Now().Add(10 * time.Second)
+	rt.DelayRestart(nextRestartAt)
+	assert.Equal(t, nextRestartAt, rt.GetNextRestartAt())
+	assert.True(t, rt.IsRestartPending())
+
+	rt.RecordRestart()
+	assert.False(t, rt.IsRestartPending())
+	assert.Equal(t, 1, rt.GetRestartCount())
+
+	rt.DelayRestart(nextRestartAt)
+	rt.CancelDelayedRestart()
+	assert.False(t, rt.IsRestartPending())
+	assert.Equal(t, 1, rt.GetRestartCount())
+}
+
+func TestUnmarshalRestartPolicyWithBackoff(t *testing.T) {
+	rp := RestartPolicy{}
+	jsonBlob := `{"enabled": true, "restartAttemptPeriod": 60, "backoffInitialPeriod": 10, "backoffMaxPeriod": 300, "maxRestartCount": 5}`
+	require.NoError(t, json.Unmarshal([]byte(jsonBlob), &rp))
+	assert.Equal(t, RestartPolicy{
+		Enabled:              true,
+		RestartAttemptPeriod: 60,
+		BackoffInitialPeriod: 10,
+		BackoffMaxPeriod:     300,
+		MaxRestartCount:      5,
+	}, rp)
+}
@@ -24,6 +24,8 @@ import (
 
 	v1 "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v1"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v1/handlers"
+	v2 "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v2"
+	v2handlers "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v2/handlers"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/metrics"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/logging"
 )
@@ -39,6 +41,9 @@ const (
 	pprofProfilePath     = pprofBasePath + "profile"
 	pprofSymbolPath      = pprofBasePath + "symbol"
 	pprofTracePath       = pprofBasePath + "trace"
+	// Collecting a support bundle runs diagnostic commands for every task, which may take longer
+	// than the default write timeout. The collector bounds the time spent running them below it.
+	writeTimeoutForSupportBundle = time.Minute
 )
 
 var (
@@ -56,10 +61,14 @@ type rootResponse struct {
 
 // Configuration for Introspection Server
 type Config struct {
-	readTimeout        time.Duration // http server read timeout
-	writeTimeout       time.Duration // http server write timeout
-	enableRuntimeStats bool          // enable profiling handlers
-	hideAgentVersion   bool          // if true, do not show Version in metadata
+	readTimeout            time.Duration             // http server read timeout
+	writeTimeout           time.Duration             // http server write timeout
+	enableRuntimeStats     bool                      // enable profiling handlers
+	hideAgentVersion       bool                      // if true, do not show Version in metadata
+	metricsHandler         http.Handler              // serves Prometheus metrics if set
+	imagePrewarmer         v1.ImagePrewarmer         // pre-warms images if set
+	supportBundleCollector v1.SupportBundleCollector // collects support bundles if set
+	taskEventsProvider     v2.TaskEventsProvider     // provides the history of the tasks if set
 }
 
 // Function type for updating Introspection Server config
@@ -94,6 +103,38 @@ func HideAgentVersion(isHidden bool) ConfigOpt {
 	}
 }
 
+// Set the handler serving agent metrics in the Prometheus exposition format.
+// If set, the handler is registered on the metrics.PrometheusMetricsPath path.
+func WithMetricsHandler(metricsHandler http.Handler) ConfigOpt {
+	return func(c *Config) {
+		c.metricsHandler = metricsHandler
+	}
+}
+
+// Set the image pre-warmer. If set, the handler pre-warming images is registered on the
+// handlers.V1ImagesPrewarmPath path.
+func WithImagePrewarmer(imagePrewarmer v1.ImagePrewarmer) ConfigOpt {
+	return func(c *Config) {
+		c.imagePrewarmer = imagePrewarmer
+	}
+}
+
+// Set the support bundle collector. If set, the handler collecting support bundles is registered
+// on the handlers.V1SupportBundlePath path.
+func WithSupportBundleCollector(supportBundleCollector v1.SupportBundleCollector) ConfigOpt {
+	return func(c *Config) {
+		c.supportBundleCollector = supportBundleCollector
+	}
+}
+
+// Set the task events provider. If set, the handler serving the history of the tasks is registered
+// on the v2handlers.V2TasksPath path.
+func WithTaskEventsProvider(taskEventsProvider v2.TaskEventsProvider) ConfigOpt {
+	return func(c *Config) {
+		c.taskEventsProvider = taskEventsProvider
+	}
+}
+
 // Create a new HTTP Introspection Server
 func NewServer(agentState v1.AgentState, metricsFactory metrics.EntryFactory, options ...ConfigOpt) (*http.Server, error) {
 	config := new(Config)
@@ -109,6 +150,7 @@ func v1HandlersSetup(serverMux *http.ServeMux,
 	hideAgentVersion bool) {
 	serverMux.HandleFunc(handlers.V1AgentMetadataPath, handlers.AgentMetadataHandler(agentState, metricsFactory, hideAgentVersion))
 	serverMux.HandleFunc(handlers.V1TasksMetadataPath, handlers.TasksMetadataHandler(agentState, metricsFactory))
+	serverMux.HandleFunc(handlers.V1FaultsMetadataPath, handlers.FaultsMetadataHandler(agentState, metricsFactory))
 	serverMux.HandleFunc(licensePath, licenseHandler(agentState, metricsFactory))
 }
 
@@ -132,11 +174,24 @@ func setup(
 		return nil, errors.New("metrics factory cannot be nil")
 	}
 
-	paths := []string{handlers.V1AgentMetadataPath, handlers.V1TasksMetadataPath, licensePath}
+	paths := []string{handlers.V1AgentMetadataPath, handlers.V1TasksMetadataPath, handlers.V1FaultsMetadataPath,
+		licensePath}
 
 	if config.enableRuntimeStats {
 		paths = append(paths, pprofBasePath, pprofCMDLinePath, pprofProfilePath, pprofSymbolPath, pprofTracePath)
 	}
+	if config.metricsHandler != nil {
+		paths = append(paths, metrics.PrometheusMetricsPath)
+	}
+	if config.imagePrewarmer != nil {
+		paths = append(paths, handlers.V1ImagesPrewarmPath)
+	}
+	if config.supportBundleCollector != nil {
+		paths = append(paths, handlers.V1SupportBundlePath)
+	}
+	if config.taskEventsProvider != nil {
+		paths = append(paths, v2handlers.V2TasksPath+"{taskArn}"+v2handlers.TaskEventsPathSuffix)
+	}
 
 	availableCommands := &rootResponse{paths}
 	// Autogenerated list of the above serverFunctions paths
@@ -158,6 +213,24 @@ func setup(
 		pprofHandlerSetup(serveMux)
 		wTimeout = writeTimeoutForPprof
 	}
+	if config.metricsHandler != nil {
+		serveMux.Handle(metrics.PrometheusMetricsPath, config.metricsHandler)
+	}
+	if config.imagePrewarmer != nil {
+		serveMux.HandleFunc(handlers.V1ImagesPrewarmPath,
+			handlers.ImagesPrewarmHandler(config.imagePrewarmer, metricsFactory))
+	}
+	if config.supportBundleCollector != nil {
+		serveMux.HandleFunc(handlers.V1SupportBundlePath,
+			handlers.SupportBundleHandler(config.supportBundleCollector, metricsFactory))
+		if wTimeout < writeTimeoutForSupportBundle {
+			wTimeout = writeTimeoutForSupportBundle
+		}
+	}
+	if config.taskEventsProvider != nil {
+		serveMux.HandleFunc(v2handlers.V2TasksPath,
+			v2handlers.TaskEventsHandler(config.taskEventsProvider, metricsFactory))
+	}
 
 	loggingServeMux := http.NewServeMux()
 	loggingServeMux.Handle("/", logging.NewLoggingHandler(serveMux))
@@ -23,7 +23,10 @@ import (
 	"testing"
 	"time"
 
+	v1 "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v1"
 	mock_v1 "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v1/mocks"
+	v2 "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v2"
+	mock_v2 "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v2/mocks"
 	mock_metrics "github.com/aws/amazon-ecs-agent/ecs-agent/metrics/mocks"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -105,7 +108,7 @@ func TestNewServerDefaults(t *testing.T) {
 
 		// Assert status code and body
 		assert.Equal(t, http.StatusOK, recorder.Code)
-		assert.Equal(t, `{"AvailableCommands":["/v1/metadata","/v1/tasks","/license"]}`, recorder.Body.String())
+		assert.Equal(t, `{"AvailableCommands":["/v1/metadata","/v1/tasks","/v1/faults","/license"]}`, recorder.Body.String())
 	})
 }
 
@@ -175,7 +178,7 @@ func TestPProfHandlerSetup(t *testing.T) {
 					assert.Equal(t, p, recorder.Body.String())
 				} else {
 					assert.Equal(t, http.StatusOK, recorder.Code)
-					assert.Equal(t, `{"AvailableCommands":["/v1/metadata","/v1/tasks","/license"]}`, recorder.Body.String())
+					assert.Equal(t, `{"AvailableCommands":["/v1/metadata","/v1/tasks","/v1/faults","/license"]}`, recorder.Body.String())
 				}
 			})
 		}
@@ -193,13 +196,146 @@ func TestPProfHandlerSetup(t *testing.T) {
 
 			if runtimeStatsConfigForTest {
 				assert.Equal(t, http.StatusOK, recorder.Code)
-				assert.Equal(t, `{"AvailableCommands":["/v1/metadata","/v1/tasks","/license",`+
+				assert.Equal(t, `{"AvailableCommands":["/v1/metadata","/v1/tasks","/v1/faults","/license",`+
 					`"/debug/pprof/","/debug/pprof/cmdline","/debug/pprof/profile","/debug/pprof/symbol","/debug/pprof/trace"]}`, recorder.Body.String())
 			} else {
 				assert.Equal(t, http.StatusOK, recorder.Code)
-				assert.Equal(t, `{"AvailableCommands":["/v1/metadata","/v1/tasks","/license"]}`, recorder.Body.String())
+				assert.Equal(t, `{"AvailableCommands":["/v1/metadata","/v1/tasks","/v1/faults","/license"]}`, recorder.Body.String())
 
 			}
 		})
 	}
 }
+
+func TestMetricsHandlerSetup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	agentState := mock_v1.NewMockAgentState(ctrl)
+	metricsFactory := mock_metrics.NewMockEntryFactory(ctrl)
+	metricsHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("metrics"))
+	})
+	server, err := NewServer(agentState, metricsFactory, WithMetricsHandler(metricsHandler))
+	require.NoError(t, err)
+
+	t.Run("metrics path", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/metrics", nil)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		server.Handler.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "metrics", recorder.Body.String())
+	})
+
+	t.Run("default route", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/", nil)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		server.Handler.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `{"AvailableCommands":["/v1/metadata","/v1/tasks","/v1/faults","/license","/metrics"]}`, recorder.Body.String())
+	})
+}
+
+func TestImagePrewarmerSetup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	agentState := mock_v1.NewMockAgentState(ctrl)
+	metricsFactory := mock_metrics.NewMockEntryFactory(ctrl)
+	prewarmer := mock_v1.NewMockImagePrewarmer(ctrl)
+	server, err := NewServer(agentState, metricsFactory, WithImagePrewarmer(prewarmer))
+	require.NoError(t, err)
+
+	t.Run("prewarm path", func(t *testing.T) {
+		prewarmer.EXPECT().GetImagePrewarmStatus().Return(&v1.ImagesPrewarmResponse{}, nil)
+		req, err := http.NewRequest("GET", "/v1/images/prewarm", nil)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		server.Handler.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `{"Images":null}`, recorder.Body.String())
+	})
+
+	t.Run("default route", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/", nil)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		server.Handler.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `{"AvailableCommands":["/v1/metadata","/v1/tasks","/v1/faults","/license","/v1/images/prewarm"]}`,
+			recorder.Body.String())
+	})
+}
+
+func TestSupportBundleCollectorSetup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	agentState := mock_v1.NewMockAgentState(ctrl)
+	metricsFactory := mock_metrics.NewMockEntryFactory(ctrl)
+	collector := mock_v1.NewMockSupportBundleCollector(ctrl)
+	server, err := NewServer(agentState, metricsFactory, WithSupportBundleCollector(collector),
+		WithWriteTimeout(5*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, writeTimeoutForSupportBundle, server.WriteTimeout)
+
+	t.Run("support bundle path", func(t *testing.T) {
+		collector.EXPECT().WriteSupportBundle(gomock.Any()).Return(nil)
+		req, err := http.NewRequest("GET", "/v1/support-bundle", nil)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		server.Handler.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("default route", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/", nil)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		server.Handler.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `{"AvailableCommands":["/v1/metadata","/v1/tasks","/v1/faults","/license","/v1/support-bundle"]}`,
+			recorder.Body.String())
+	})
+}
+
+func TestTaskEventsProviderSetup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	agentState := mock_v1.NewMockAgentState(ctrl)
+	metricsFactory := mock_metrics.NewMockEntryFactory(ctrl)
+	provider := mock_v2.NewMockTaskEventsProvider(ctrl)
+	server, err := NewServer(agentState, metricsFactory, WithTaskEventsProvider(provider))
+	require.NoError(t, err)
+
+	t.Run("task events path", func(t *testing.T) {
+		taskArn := "arn:aws:ecs:us-west-2:123456789012:task/cluster/task-id"
+		provider.EXPECT().GetTaskEvents(taskArn).Return(&v2.TaskEventsResponse{TaskArn: taskArn}, nil)
+		req, err := http.NewRequest("GET", "/v2/tasks/"+taskArn+"/events", nil)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		server.Handler.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("default route", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/", nil)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		server.Handler.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `{"AvailableCommands":["/v1/metadata","/v1/tasks","/v1/faults","/license","/v2/tasks/{taskArn}/events"]}`,
+			recorder.Body.String())
+	})
+}
@@ -14,9 +14,12 @@
 package handlers
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	v1 "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v1"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
@@ -31,9 +34,20 @@ const (
 	dockerShortIDLen   = 12
 	requestTypeAgent   = "introspection/agent"
 	requestTypeTasks   = "introspection/tasks"
+	requestTypeFaults  = "introspection/faults"
+	requestTypePrewarm = "introspection/images/prewarm"
+	requestTypeBundle  = "introspection/support-bundle"
 
-	V1AgentMetadataPath = "/v1/metadata"
-	V1TasksMetadataPath = "/v1/tasks"
+	V1AgentMetadataPath  = "/v1/metadata"
+	V1TasksMetadataPath  = "/v1/tasks"
+	V1FaultsMetadataPath = "/v1/faults"
+	V1ImagesPrewarmPath  = "/v1/images/prewarm"
+	V1SupportBundlePath  = "/v1/support-bundle"
+
+	supportBundleContentType = "application/gzip"
+	// supportBundleFileNameFormat is the format of the file name suggested to the clients, with
+	// the time the bundle was collected at
+	supportBundleFileNameFormat = "ecs-agent-support-bundle-%s.tar.gz"
 )
 
 // getHTTPErrorCode returns an appropriate HTTP response status code and metric name for a given error.
@@ -179,3 +193,129 @@ func getTaskMetadata(
 	}
 	tmdsutils.WriteJSONResponse(w, http.StatusOK, taskMetadata, requestTypeTasks)
 }
+
+// FaultsMetadataHandler returns the HTTP handler function for handling faults metadata requests. The faults
+// are filtered by task if the request contains a task Arn.
+func FaultsMetadataHandler(
+	agentState v1.AgentState,
+	metricsFactory metrics.EntryFactory,
+) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		faultsMetadata, err := agentState.GetFaultsMetadata()
+		if err != nil {
+			logger.Error("Failed to get v1 faults metadata.", logger.Fields{
+				field.Error: err,
+			})
+			responseCode, metricName := getHTTPErrorCode(err)
+			metricsFactory.New(metricName).Done(err)
+			tmdsutils.WriteJSONResponse(w, responseCode, v1.FaultsResponse{}, requestTypeFaults)
+			return
+		}
+		if taskArn, ok := tmdsutils.ValueFromRequest(r, taskARNQueryField); ok {
+			taskFaults := &v1.FaultsResponse{Faults: []*v1.FaultResponse{}}
+			for _, fault := range faultsMetadata.Faults {
+				if fault.TaskArn == taskArn {
+					taskFaults.Faults = append(taskFaults.Faults, fault)
+				}
+			}
+			faultsMetadata = taskFaults
+		}
+		tmdsutils.WriteJSONResponse(w, http.StatusOK, faultsMetadata, requestTypeFaults)
+	}
+}
+
+// ImagesPrewarmHandler returns the HTTP handler function for handling image pre-warm requests. GET requests
+// return the status of the pre-warmed images, and POST requests start pre-warming the images of the request.
+func ImagesPrewarmHandler(
+	prewarmer v1.ImagePrewarmer,
+	metricsFactory metrics.EntryFactory,
+) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeImagesPrewarmStatus(prewarmer, metricsFactory, http.StatusOK, w)
+		case http.MethodPost:
+			var request v1.ImagesPrewarmRequest
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil || len(request.Images) == 0 {
+				if err == nil {
+					err = errors.New("request contains no images")
+				}
+				logger.Error("Bad request for v1 image pre-warm.", logger.Fields{
+					field.Error: err,
+				})
+				metricsFactory.New(metrics.IntrospectionBadRequest).Done(err)
+				tmdsutils.WriteJSONResponse(w, http.StatusBadRequest, v1.ImagesPrewarmResponse{}, requestTypePrewarm)
+				return
+			}
+			if err := prewarmer.PrewarmImages(request.Images); err != nil {
+				logger.Error("Failed to pre-warm images.", logger.Fields{
+					field.Error: err,
+				})
+				responseCode, metricName := getHTTPErrorCode(err)
+				metricsFactory.New(metricName).Done(err)
+				tmdsutils.WriteJSONResponse(w, responseCode, v1.ImagesPrewarmResponse{}, requestTypePrewarm)
+				return
+			}
+			writeImagesPrewarmStatus(prewarmer, metricsFactory, http.StatusAccepted, w)
+		default:
+			w.Header().Set("Allow", fmt.Sprintf("%s, %s", http.MethodGet, http.MethodPost))
+			tmdsutils.WriteJSONResponse(w, http.StatusMethodNotAllowed, v1.ImagesPrewarmResponse{}, requestTypePrewarm)
+		}
+	}
+}
+
+// writeImagesPrewarmStatus writes the status of the pre-warmed images to the response
+func writeImagesPrewarmStatus(
+	prewarmer v1.ImagePrewarmer,
+	metricsFactory metrics.EntryFactory,
+	statusCode int,
+	w http.ResponseWriter,
+) {
+	status, err := prewarmer.GetImagePrewarmStatus()
+	if err != nil {
+		logger.Error("Failed to get v1 image pre-warm status.", logger.Fields{
+			field.Error: err,
+		})
+		responseCode, metricName := getHTTPErrorCode(err)
+		metricsFactory.New(metricName).Done(err)
+		tmdsutils.WriteJSONResponse(w, responseCode, v1.ImagesPrewarmResponse{}, requestTypePrewarm)
+		return
+	}
+	tmdsutils.WriteJSONResponse(w, statusCode, status, requestTypePrewarm)
+}
+
+// SupportBundleHandler returns a handler collecting a diagnostic support bundle and writing it as
+// a gzipped tar archive.
+func SupportBundleHandler(
+	collector v1.SupportBundleCollector,
+	metricsFactory metrics.EntryFactory,
+) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			tmdsutils.WriteJSONResponse(w, http.StatusMethodNotAllowed, struct{}{}, requestTypeBundle)
+			return
+		}
+		// Collect the whole bundle first so that an error can still be reported with the
+		// status code
+		var bundle bytes.Buffer
+		if err := collector.WriteSupportBundle(&bundle); err != nil {
+			logger.Error("Failed to collect support bundle.", logger.Fields{
+				field.Error: err,
+			})
+			responseCode, metricName := getHTTPErrorCode(err)
+			metricsFactory.New(metricName).Done(err)
+			tmdsutils.WriteJSONResponse(w, responseCode, struct{}{}, requestTypeBundle)
+			return
+		}
+		fileName := fmt.Sprintf(supportBundleFileNameFormat, time.Now().UTC().Format("20060102T150405Z"))
+		w.Header().Set("Content-Type", supportBundleContentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+		w.WriteHeader(http.StatusOK)
+		if _, err := bundle.WriteTo(w); err != nil {
+			logger.Warn("Failed to write support bundle.", logger.Fields{
+				field.Error: err,
+			})
+		}
+	}
+}
@@ -20,9 +20,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	v1 "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v1"
 	mock_v1 "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v1/mocks"
@@ -134,7 +137,8 @@ type IntrospectionResponse interface {
 	string |
 		*v1.AgentMetadataResponse |
 		*v1.TaskResponse |
-		*v1.TasksResponse
+		*v1.TasksResponse |
+		*v1.FaultsResponse
 }
 
 type IntrospectionTestCase[R IntrospectionResponse] struct {
@@ -566,6 +570,259 @@ func TestTasksHandler(t *testing.T) {
 
 }
 
+func TestFaultsHandler(t *testing.T) {
+	startedAt := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := startedAt.Add(time.Minute)
+	latencyFault := &v1.FaultResponse{
+		TaskArn:    taskARN,
+		FaultType:  "network-latency",
+		Parameters: `{"DelayMilliseconds":100}`,
+		StartedAt:  startedAt,
+		ExpiresAt:  &expiresAt,
+	}
+	stressFault := &v1.FaultResponse{
+		TaskArn:   "t2",
+		FaultType: "cpu-stress",
+		StartedAt: startedAt,
+	}
+	testFaults := &v1.FaultsResponse{Faults: []*v1.FaultResponse{latencyFault, stressFault}}
+
+	var performMockFaultsRequest = func(t *testing.T, testCase IntrospectionTestCase[*v1.FaultsResponse]) *httptest.ResponseRecorder {
+		mockCtrl, mockAgentState, mockMetricsFactory, req, recorder := testHandlerSetup(t, testCase)
+		mockAgentState.EXPECT().
+			GetFaultsMetadata().
+			Return(testCase.AgentResponse, testCase.Err)
+		if testCase.Err != nil {
+			mockEntry := mock_metrics.NewMockEntry(mockCtrl)
+			mockEntry.EXPECT().Done(testCase.Err)
+			mockMetricsFactory.EXPECT().
+				New(testCase.MetricName).Return(mockEntry)
+		}
+		FaultsMetadataHandler(mockAgentState, mockMetricsFactory)(recorder, req)
+		return recorder
+	}
+
+	t.Run("all faults", func(t *testing.T) {
+		recorder := performMockFaultsRequest(t, IntrospectionTestCase[*v1.FaultsResponse]{
+			Path:          V1FaultsMetadataPath,
+			AgentResponse: testFaults,
+		})
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		testFaultsJSON, _ := json.Marshal(testFaults)
+		assert.Equal(t, string(testFaultsJSON), recorder.Body.String())
+	})
+
+	t.Run("task faults", func(t *testing.T) {
+		recorder := performMockFaultsRequest(t, IntrospectionTestCase[*v1.FaultsResponse]{
+			Path:          fmt.Sprintf("%s?%s=%s", V1FaultsMetadataPath, taskARNQueryField, taskARN),
+			AgentResponse: testFaults,
+		})
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		taskFaultsJSON, _ := json.Marshal(&v1.FaultsResponse{Faults: []*v1.FaultResponse{latencyFault}})
+		assert.Equal(t, string(taskFaultsJSON), recorder.Body.String())
+	})
+
+	t.Run("task without faults", func(t *testing.T) {
+		recorder := performMockFaultsRequest(t, IntrospectionTestCase[*v1.FaultsResponse]{
+			Path:          fmt.Sprintf("%s?%s=%s", V1FaultsMetadataPath, taskARNQueryField, "t3"),
+			AgentResponse: testFaults,
+		})
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `{"Faults":[]}`, recorder.Body.String())
+	})
+
+	t.Run("fetch failed", func(t *testing.T) {
+		recorder := performMockFaultsRequest(t, IntrospectionTestCase[*v1.FaultsResponse]{
+			Path:       V1FaultsMetadataPath,
+			Err:        v1.NewErrorFetchFailure(internalErrorText),
+			MetricName: metrics.IntrospectionFetchFailure,
+		})
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		assert.Equal(t, `{"Faults":null}`, recorder.Body.String())
+	})
+}
+
+func TestImagesPrewarmHandler(t *testing.T) {
+	protectedUntil := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	testStatus := &v1.ImagesPrewarmResponse{Images: []*v1.ImagePrewarmResponse{
+		{Image: "busybox:latest", Status: "PULLED", ProtectedUntil: &protectedUntil},
+		{Image: "nginx:latest", Status: "PENDING"},
+	}}
+	testStatusJSON := `{"Images":[{"Image":"busybox:latest","Status":"PULLED","ProtectedUntil":"2024-01-01T00:00:00Z"},` +
+		`{"Image":"nginx:latest","Status":"PENDING"}]}`
+
+	tcs := []struct {
+		name               string
+		method             string
+		body               string
+		setExpectations    func(*mock_v1.MockImagePrewarmer)
+		expectedMetricName string
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:   "get status",
+			method: http.MethodGet,
+			setExpectations: func(prewarmer *mock_v1.MockImagePrewarmer) {
+				prewarmer.EXPECT().GetImagePrewarmStatus().Return(testStatus, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   testStatusJSON,
+		},
+		{
+			name:   "get status failed",
+			method: http.MethodGet,
+			setExpectations: func(prewarmer *mock_v1.MockImagePrewarmer) {
+				prewarmer.EXPECT().GetImagePrewarmStatus().Return(nil, v1.NewErrorFetchFailure(internalErrorText))
+			},
+			expectedMetricName: metrics.IntrospectionFetchFailure,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   `{"Images":null}`,
+		},
+		{
+			name:   "prewarm",
+			method: http.MethodPost,
+			body:   `{"Images":["busybox:latest","nginx:latest"]}`,
+			setExpectations: func(prewarmer *mock_v1.MockImagePrewarmer) {
+				gomock.InOrder(
+					prewarmer.EXPECT().PrewarmImages([]string{"busybox:latest", "nginx:latest"}).Return(nil),
+					prewarmer.EXPECT().GetImagePrewarmStatus().Return(testStatus, nil),
+				)
+			},
+			expectedStatusCode: http.StatusAccepted,
+			expectedResponse:   testStatusJSON,
+		},
+		{
+			name:   "prewarm failed",
+			method: http.MethodPost,
+			body:   `{"Images":["busybox:latest"]}`,
+			setExpectations: func(prewarmer *mock_v1.MockImagePrewarmer) {
+				prewarmer.EXPECT().PrewarmImages([]string{"busybox:latest"}).Return(errors.New(internalErrorText))
+			},
+			expectedMetricName: metrics.IntrospectionInternalServerError,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   `{"Images":null}`,
+		},
+		{
+			name:               "prewarm invalid body",
+			method:             http.MethodPost,
+			body:               `{"Images":`,
+			expectedMetricName: metrics.IntrospectionBadRequest,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"Images":null}`,
+		},
+		{
+			name:               "prewarm without images",
+			method:             http.MethodPost,
+			body:               `{"Images":[]}`,
+			expectedMetricName: metrics.IntrospectionBadRequest,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"Images":null}`,
+		},
+		{
+			name:               "method not allowed",
+			method:             http.MethodDelete,
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedResponse:   `{"Images":null}`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			prewarmer := mock_v1.NewMockImagePrewarmer(ctrl)
+			metricsFactory := mock_metrics.NewMockEntryFactory(ctrl)
+			if tc.setExpectations != nil {
+				tc.setExpectations(prewarmer)
+			}
+			if tc.expectedMetricName != "" {
+				entry := mock_metrics.NewMockEntry(ctrl)
+				entry.EXPECT().Done(gomock.Any())
+				metricsFactory.EXPECT().New(tc.expectedMetricName).Return(entry)
+			}
+
+			req, err := http.NewRequest(tc.method, V1ImagesPrewarmPath, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			ImagesPrewarmHandler(prewarmer, metricsFactory)(recorder, req)
+
+			assert.Equal(t, tc.expectedStatusCode, recorder.Code)
+			assert.Equal(t, tc.expectedResponse, recorder.Body.String())
+		})
+	}
+}
+
+func TestSupportBundleHandler(t *testing.T) {
+	tcs := []struct {
+		name               string
+		method             string
+		setExpectations    func(*mock_v1.MockSupportBundleCollector)
+		expectedMetricName string
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:   "collect",
+			method: http.MethodGet,
+			setExpectations: func(collector *mock_v1.MockSupportBundleCollector) {
+				collector.EXPECT().WriteSupportBundle(gomock.Any()).DoAndReturn(func(w io.Writer) error {
+					_, err := w.Write([]byte("bundle"))
+					return err
+				})
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   "bundle",
+		},
+		{
+			name:   "collect failed",
+			method: http.MethodGet,
+			setExpectations: func(collector *mock_v1.MockSupportBundleCollector) {
+				collector.EXPECT().WriteSupportBundle(gomock.Any()).DoAndReturn(func(w io.Writer) error {
+					w.Write([]byte("partial"))
+					return errors.New(internalErrorText)
+				})
+			},
+			expectedMetricName: metrics.IntrospectionInternalServerError,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   `{}`,
+		},
+		{
+			name:               "method not allowed",
+			method:             http.MethodPost,
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedResponse:   `{}`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			collector := mock_v1.NewMockSupportBundleCollector(ctrl)
+			metricsFactory := mock_metrics.NewMockEntryFactory(ctrl)
+			if tc.setExpectations != nil {
+				tc.setExpectations(collector)
+			}
+			if tc.expectedMetricName != "" {
+				entry := mock_metrics.NewMockEntry(ctrl)
+				entry.EXPECT().Done(gomock.Any())
+				metricsFactory.EXPECT().New(tc.expectedMetricName).Return(entry)
+			}
+
+			req, err := http.NewRequest(tc.method, V1SupportBundlePath, nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			SupportBundleHandler(collector, metricsFactory)(recorder, req)
+
+			assert.Equal(t, tc.expectedStatusCode, recorder.Code)
+			assert.Equal(t, tc.expectedResponse, recorder.Body.String())
+			if tc.expectedStatusCode == http.StatusOK {
+				assert.Equal(t, "application/gzip", recorder.Header().Get("Content-Type"))
+				assert.Contains(t, recorder.Header().Get("Content-Disposition"), "ecs-agent-support-bundle-")
+			}
+		})
+	}
+}
+
 func TestGetErrorResponse(t *testing.T) {
 
 	t.Run("multiple tasks found error", func(t *testing.T) {
@@ -13,12 +13,13 @@
 //
 
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v1 (interfaces: AgentState)
+// Source: github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v1 (interfaces: AgentState,ImagePrewarmer,SupportBundleCollector)
 
 // Package mock_v1 is a generated GoMock package.
 package mock_v1
 
 import (
+	io "io"
 	reflect "reflect"
 
 	v1 "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v1"
@@ -63,6 +64,21 @@ func (mr *MockAgentStateMockRecorder) GetAgentMetadata() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgentMetadata", reflect.TypeOf((*MockAgentState)(nil).GetAgentMetadata))
 }
 
+// GetFaultsMetadata mocks base method.
+func (m *MockAgentState) GetFaultsMetadata() (*v1.FaultsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFaultsMetadata")
+	ret0, _ := ret[0].(*v1.FaultsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFaultsMetadata indicates an expected call of GetFaultsMetadata.
+func (mr *MockAgentStateMockRecorder) GetFaultsMetadata() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFaultsMetadata", reflect.TypeOf((*MockAgentState)(nil).GetFaultsMetadata))
+}
+
 // GetLicenseText mocks base method.
 func (m *MockAgentState) GetLicenseText() (string, error) {
 	m.ctrl.T.Helper()
@@ -137,3 +153,92 @@ func (mr *MockAgentStateMockRecorder) GetTasksMetadata() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasksMetadata", reflect.TypeOf((*MockAgentState)(nil).GetTasksMetadata))
 }
+
+// MockImagePrewarmer is a mock of ImagePrewarmer interface.
+type MockImagePrewarmer struct {
+	ctrl     *gomock.Controller
+	recorder *MockImagePrewarmerMockRecorder
+}
+
+// MockImagePrewarmerMockRecorder is the mock recorder for MockImagePrewarmer.
+type MockImagePrewarmerMockRecorder struct {
+	mock *MockImagePrewarmer
+}
+
+// NewMockImagePrewarmer creates a new mock instance.
+func NewMockImagePrewarmer(ctrl *gomock.Controller) *MockImagePrewarmer {
+	mock := &MockImagePrewarmer{ctrl: ctrl}
+	mock.recorder = &MockImagePrewarmerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImagePrewarmer) EXPECT() *MockImagePrewarmerMockRecorder {
+	return m.recorder
+}
+
+// GetImagePrewarmStatus mocks base method.
+func (m *MockImagePrewarmer) GetImagePrewarmStatus() (*v1.ImagesPrewarmResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImagePrewarmStatus")
+	ret0, _ := ret[0].(*v1.ImagesPrewarmResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImagePrewarmStatus indicates an expected call of GetImagePrewarmStatus.
+func (mr *MockImagePrewarmerMockRecorder) GetImagePrewarmStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImagePrewarmStatus", reflect.TypeOf((*MockImagePrewarmer)(nil).GetImagePrewarmStatus))
+}
+
+// PrewarmImages mocks base method.
+func (m *MockImagePrewarmer) PrewarmImages(arg0 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PrewarmImages", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PrewarmImages indicates an expected call of PrewarmImages.
+func (mr *MockImagePrewarmerMockRecorder) PrewarmImages(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrewarmImages", reflect.TypeOf((*MockImagePrewarmer)(nil).PrewarmImages), arg0)
+}
+
+// MockSupportBundleCollector is a mock of SupportBundleCollector interface.
+type MockSupportBundleCollector struct {
+	ctrl     *gomock.Controller
+	recorder *MockSupportBundleCollectorMockRecorder
+}
+
+// MockSupportBundleCollectorMockRecorder is the mock recorder for MockSupportBundleCollector.
+type MockSupportBundleCollectorMockRecorder struct {
+	mock *MockSupportBundleCollector
+}
+
+// NewMockSupportBundleCollector creates a new mock instance.
+func NewMockSupportBundleCollector(ctrl *gomock.Controller) *MockSupportBundleCollector {
+	mock := &MockSupportBundleCollector{ctrl: ctrl}
+	mock.recorder = &MockSupportBundleCollectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSupportBundleCollector) EXPECT() *MockSupportBundleCollectorMockRecorder {
+	return m.recorder
+}
+
+// WriteSupportBundle mocks base method.
+func (m *MockSupportBundleCollector) WriteSupportBundle(arg0 io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteSupportBundle", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteSupportBundle indicates an expected call of WriteSupportBundle.
+func (mr *MockSupportBundleCollectorMockRecorder) WriteSupportBundle(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteSupportBundle", reflect.TypeOf((*MockSupportBundleCollector)(nil).WriteSupportBundle), arg0)
+}
@@ -63,6 +63,40 @@ type ContainerResponse struct {
 	Networks     []response.Network        `json:"Networks,omitempty"`
 	Volumes      []response.VolumeResponse `json:"Volumes,omitempty"`
 	RestartCount *int                      `json:"RestartCount,omitempty"`
+	// BlockedReason is the reason why the container can't progress to its next status yet
+	BlockedReason *response.BlockedReasonResponse `json:"BlockedReason,omitempty"`
+}
+
+// FaultResponse is the schema for the fault response JSON object.
+type FaultResponse struct {
+	TaskArn    string     `json:"TaskArn"`
+	FaultType  string     `json:"FaultType"`
+	Parameters string     `json:"Parameters,omitempty"`
+	StartedAt  time.Time  `json:"StartedAt"`
+	ExpiresAt  *time.Time `json:"ExpiresAt,omitempty"`
+}
+
+// FaultsResponse is the schema for the faults response JSON object.
+type FaultsResponse struct {
+	Faults []*FaultResponse `json:"Faults"`
+}
+
+// ImagePrewarmResponse is the schema for the pre-warmed image response JSON object.
+type ImagePrewarmResponse struct {
+	Image          string     `json:"Image"`
+	Status         string     `json:"Status"`
+	Error          string     `json:"Error,omitempty"`
+	ProtectedUntil *time.Time `json:"ProtectedUntil,omitempty"`
+}
+
+// ImagesPrewarmResponse is the schema for the pre-warmed images response JSON object.
+type ImagesPrewarmResponse struct {
+	Images []*ImagePrewarmResponse `json:"Images"`
+}
+
+// ImagesPrewarmRequest is the schema for the image pre-warm request JSON object.
+type ImagesPrewarmRequest struct {
+	Images []string `json:"Images"`
 }
 
 // ErrorMultipleTasksFound should be returned when a task cannot be uniquely identified for a given request.
@@ -13,6 +13,8 @@
 
 package v1
 
+import "io"
+
 // AgentState is the interface for interacting with agent state relevant to the Introspection Server.
 type AgentState interface {
 	// Returns the agent's license text
@@ -27,4 +29,22 @@ type AgentState interface {
 	GetTaskMetadataByID(dockerID string) (*TaskResponse, error)
 	// Returns task metadata in v1 format for the task with a matching short docker ID.
 	GetTaskMetadataByShortID(shortDockerID string) (*TaskResponse, error)
+	// Returns the faults currently injected into the tasks on the host.
+	GetFaultsMetadata() (*FaultsResponse, error)
+}
+
+// ImagePrewarmer is the interface for pre-warming images through the Introspection Server.
+type ImagePrewarmer interface {
+	// Starts pulling the images in the background.
+	PrewarmImages(images []string) error
+	// Returns the status of the images pre-warmed by the agent.
+	GetImagePrewarmStatus() (*ImagesPrewarmResponse, error)
+}
+
+// SupportBundleCollector is the interface for collecting diagnostic support bundles through the
+// Introspection Server.
+type SupportBundleCollector interface {
+	// Writes a gzipped tar archive with the diagnostic information collected from the agent and
+	// the host.
+	WriteSupportBundle(w io.Writer) error
 }
@@ -0,0 +1,100 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	v1 "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v1"
+	v2 "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v2"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/metrics"
+	tmdsutils "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/utils"
+)
+
+const (
+	requestTypeTaskEvents = "introspection/v2/task/events"
+
+	// V2TasksPath is the prefix of the v2 task paths. The events of a task are served on the
+	// V2TasksPath + "{taskArn}" + TaskEventsPathSuffix path.
+	V2TasksPath          = "/v2/tasks/"
+	TaskEventsPathSuffix = "/events"
+)
+
+// getHTTPErrorCode returns an appropriate HTTP response status code and metric name for a given error.
+func getHTTPErrorCode(err error) (int, string) {
+	// The requested task was not found
+	var errNotFound *v1.ErrorNotFound
+	if errors.As(err, &errNotFound) {
+		return errNotFound.StatusCode(), errNotFound.MetricName()
+	}
+
+	// There was an error finding the task events
+	var errFetchFailure *v1.ErrorFetchFailure
+	if errors.As(err, &errFetchFailure) {
+		return errFetchFailure.StatusCode(), errFetchFailure.MetricName()
+	}
+
+	// Some unknown error has occurred
+	return http.StatusInternalServerError, metrics.IntrospectionInternalServerError
+}
+
+// taskArnFromEventsPath returns the task Arn from a task events request path. Task Arns contain
+// slashes, so the Arn is everything between the tasks prefix and the events suffix.
+func taskArnFromEventsPath(path string) (string, bool) {
+	if !strings.HasPrefix(path, V2TasksPath) || !strings.HasSuffix(path, TaskEventsPathSuffix) {
+		return "", false
+	}
+	taskArn := strings.TrimSuffix(strings.TrimPrefix(path, V2TasksPath), TaskEventsPathSuffix)
+	if taskArn == "" {
+		return "", false
+	}
+	return taskArn, true
+}
+
+// TaskEventsHandler returns the HTTP handler function for handling task events requests.
+func TaskEventsHandler(
+	provider v2.TaskEventsProvider,
+	metricsFactory metrics.EntryFactory,
+) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskArn, ok := taskArnFromEventsPath(r.URL.Path)
+		if !ok {
+			err := fmt.Errorf("invalid path %s, expected %s{taskArn}%s", r.URL.Path, V2TasksPath,
+				TaskEventsPathSuffix)
+			logger.Error("Bad request for v2 task events.", logger.Fields{
+				field.Error: err,
+			})
+			metricsFactory.New(metrics.IntrospectionBadRequest).Done(err)
+			tmdsutils.WriteJSONResponse(w, http.StatusBadRequest, v2.TaskEventsResponse{}, requestTypeTaskEvents)
+			return
+		}
+		taskEvents, err := provider.GetTaskEvents(taskArn)
+		if err != nil {
+			logger.Error("Failed to get v2 task events.", logger.Fields{
+				field.Error:   err,
+				field.TaskARN: taskArn,
+			})
+			responseCode, metricName := getHTTPErrorCode(err)
+			metricsFactory.New(metricName).Done(err)
+			tmdsutils.WriteJSONResponse(w, responseCode, v2.TaskEventsResponse{}, requestTypeTaskEvents)
+			return
+		}
+		tmdsutils.WriteJSONResponse(w, http.StatusOK, taskEvents, requestTypeTaskEvents)
+	}
+}
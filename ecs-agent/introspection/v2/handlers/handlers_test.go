@@ -0,0 +1,97 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v1"
+	v2 "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v2"
+	mock_v2 "github.com/aws/amazon-ecs-agent/ecs-agent/introspection/v2/mocks"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/metrics"
+	mock_metrics "github.com/aws/amazon-ecs-agent/ecs-agent/metrics/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const taskARN = "arn:aws:ecs:us-west-2:123456789012:task/cluster/task-id"
+
+func TestTaskEventsHandler(t *testing.T) {
+	eventTime := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	testEvents := &v2.TaskEventsResponse{
+		TaskArn: taskARN,
+		Events: []*v2.TaskEventResponse{
+			{Time: eventTime, Type: "Blocked", Container: "app",
+				Message: "waiting for container db to be HEALTHY"},
+			{Time: eventTime.Add(time.Second), Type: "Transition", Container: "app", Status: "RUNNING"},
+		},
+	}
+
+	performRequest := func(t *testing.T, path string, expectedArn string, response *v2.TaskEventsResponse,
+		err error, metricName string) *httptest.ResponseRecorder {
+		ctrl := gomock.NewController(t)
+		provider := mock_v2.NewMockTaskEventsProvider(ctrl)
+		metricsFactory := mock_metrics.NewMockEntryFactory(ctrl)
+		if expectedArn != "" {
+			provider.EXPECT().GetTaskEvents(expectedArn).Return(response, err)
+		}
+		if metricName != "" {
+			entry := mock_metrics.NewMockEntry(ctrl)
+			entry.EXPECT().Done(gomock.Any())
+			metricsFactory.EXPECT().New(metricName).Return(entry)
+		}
+		req, reqErr := http.NewRequest("GET", path, nil)
+		require.NoError(t, reqErr)
+		recorder := httptest.NewRecorder()
+		TaskEventsHandler(provider, metricsFactory)(recorder, req)
+		return recorder
+	}
+
+	t.Run("task events", func(t *testing.T) {
+		recorder := performRequest(t, V2TasksPath+taskARN+TaskEventsPathSuffix, taskARN, testEvents, nil, "")
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		expectedJSON, _ := json.Marshal(testEvents)
+		assert.Equal(t, string(expectedJSON), recorder.Body.String())
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		recorder := performRequest(t, V2TasksPath+"unknown"+TaskEventsPathSuffix, "unknown", nil,
+			v1.NewErrorNotFound("task not found"), metrics.IntrospectionNotFound)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.Equal(t, `{"TaskArn":"","Events":null}`, recorder.Body.String())
+	})
+
+	t.Run("unknown error", func(t *testing.T) {
+		recorder := performRequest(t, V2TasksPath+taskARN+TaskEventsPathSuffix, taskARN, nil,
+			errors.New("error"), metrics.IntrospectionInternalServerError)
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	})
+
+	for _, path := range []string{V2TasksPath, V2TasksPath + taskARN, V2TasksPath + TaskEventsPathSuffix} {
+		t.Run("bad path "+path, func(t *testing.T) {
+			recorder := performRequest(t, path, "", nil, nil, metrics.IntrospectionBadRequest)
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v2
+
+import (
+	"time"
+)
+
+// TaskEventResponse is the schema for the task event response JSON object.
+type TaskEventResponse struct {
+	Time      time.Time `json:"Time"`
+	Type      string    `json:"Type"`
+	Container string    `json:"Container,omitempty"`
+	Status    string    `json:"Status,omitempty"`
+	Message   string    `json:"Message,omitempty"`
+}
+
+// TaskEventsResponse is the schema for the task events response JSON object. The events are
+// ordered from the oldest to the most recent.
+type TaskEventsResponse struct {
+	TaskArn string               `json:"TaskArn"`
+	Events  []*TaskEventResponse `json:"Events"`
+}
@@ -0,0 +1,22 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v2
+
+// TaskEventsProvider is the interface for retrieving the history of the tasks through the
+// Introspection Server.
+type TaskEventsProvider interface {
+	// Returns the history of the transitions, errors and blocking conditions of the task with a
+	// matching task Arn. Returns a v1.ErrorNotFound error if the task isn't found.
+	GetTaskEvents(taskArn string) (*TaskEventsResponse, error)
+}
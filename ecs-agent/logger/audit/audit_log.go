@@ -11,7 +11,7 @@
 // express or implied. See the License for the specific language governing
 // permissions and limitations under the License.
 
-//go:generate mockgen -destination=mocks/mock_audit_logger.go -copyright_file=../../../scripts/copyright_file . AuditLogger
+//go:generate mockgen -destination=mocks/mock_audit_logger.go -copyright_file=../../../scripts/copyright_file . AuditLogger,FaultAuditLogger
 
 package audit
 
@@ -24,6 +24,12 @@ const (
 	GetCredentialsEventType                = "GetCredentials"
 	GetCredentialsTaskExecutionEventType   = "GetCredentialsExecutionRole"
 	GetCredentialsInvalidRoleTypeEventType = "GetCredentialsInvalidRoleType"
+	// Fault injection event types. Faults are expired and cleaned up by the agent itself once their duration
+	// has elapsed and once their task has stopped respectively.
+	StartFaultEventType   = "StartFault"
+	StopFaultEventType    = "StopFault"
+	ExpireFaultEventType  = "ExpireFault"
+	CleanupFaultEventType = "CleanupFault"
 )
 
 type AuditLogger interface {
@@ -32,6 +38,14 @@ type AuditLogger interface {
 	GetCluster() string
 }
 
+// FaultAuditLogger records the faults started and stopped in tasks.
+type FaultAuditLogger interface {
+	// LogFault records a fault event for the task identified by the ARN of the request. The HTTP request is nil
+	// if the event wasn't caused by a request, e.g. when the fault expired. The parameters are the ones the
+	// fault was started with.
+	LogFault(r request.LogRequest, httpResponseCode int, eventType, faultType, parameters string)
+}
+
 // Returns a suitable audit log event type for the credentials role type
 func GetCredentialsEventTypeFromRoleType(roleType string) string {
 	switch roleType {
@@ -13,7 +13,7 @@
 //
 
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/aws/amazon-ecs-agent/ecs-agent/logger/audit (interfaces: AuditLogger)
+// Source: github.com/aws/amazon-ecs-agent/ecs-agent/logger/audit (interfaces: AuditLogger,FaultAuditLogger)
 
 // Package mock_audit is a generated GoMock package.
 package mock_audit
@@ -87,3 +87,38 @@ func (mr *MockAuditLoggerMockRecorder) Log(arg0, arg1, arg2 interface{}) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Log", reflect.TypeOf((*MockAuditLogger)(nil).Log), arg0, arg1, arg2)
 }
+
+// MockFaultAuditLogger is a mock of FaultAuditLogger interface.
+type MockFaultAuditLogger struct {
+	ctrl     *gomock.Controller
+	recorder *MockFaultAuditLoggerMockRecorder
+}
+
+// MockFaultAuditLoggerMockRecorder is the mock recorder for MockFaultAuditLogger.
+type MockFaultAuditLoggerMockRecorder struct {
+	mock *MockFaultAuditLogger
+}
+
+// NewMockFaultAuditLogger creates a new mock instance.
+func NewMockFaultAuditLogger(ctrl *gomock.Controller) *MockFaultAuditLogger {
+	mock := &MockFaultAuditLogger{ctrl: ctrl}
+	mock.recorder = &MockFaultAuditLoggerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFaultAuditLogger) EXPECT() *MockFaultAuditLoggerMockRecorder {
+	return m.recorder
+}
+
+// LogFault mocks base method.
+func (m *MockFaultAuditLogger) LogFault(arg0 request.LogRequest, arg1 int, arg2, arg3, arg4 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "LogFault", arg0, arg1, arg2, arg3, arg4)
+}
+
+// LogFault indicates an expected call of LogFault.
+func (mr *MockFaultAuditLoggerMockRecorder) LogFault(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogFault", reflect.TypeOf((*MockFaultAuditLogger)(nil).LogFault), arg0, arg1, arg2, arg3, arg4)
+}
@@ -0,0 +1,77 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+
+package logger
+
+import (
+	"context"
+)
+
+// contextFieldsKey is the key of the logging fields carried by a context
+type contextFieldsKey struct{}
+
+// ContextWithFields returns a copy of ctx carrying the logging fields of ctx and fields. The fields replace the
+// ones of ctx with the same name. The fields carried by a context are added to every message logged with it by
+// the Ctx logging functions, e.g. the task ARN and the container name of the operation the context belongs to.
+func ContextWithFields(ctx context.Context, fields Fields) context.Context {
+	merged := FieldsFromContext(ctx)
+	merged.Merge(fields)
+	return context.WithValue(ctx, contextFieldsKey{}, merged)
+}
+
+// FieldsFromContext returns a copy of the logging fields carried by ctx
+func FieldsFromContext(ctx context.Context) Fields {
+	fields := Fields{}
+	if ctx == nil {
+		return fields
+	}
+	if ctxFields, ok := ctx.Value(contextFieldsKey{}).(Fields); ok {
+		fields.Merge(ctxFields)
+	}
+	return fields
+}
+
+// withContextFields prepends the fields carried by ctx to fields, so that the fields of the message take
+// precedence over the ones of the context
+func withContextFields(ctx context.Context, fields []Fields) []Fields {
+	ctxFields := FieldsFromContext(ctx)
+	if len(ctxFields) == 0 {
+		return fields
+	}
+	return append([]Fields{ctxFields}, fields...)
+}
+
+func TraceCtx(ctx context.Context, message string, fields ...Fields) {
+	Trace(message, withContextFields(ctx, fields)...)
+}
+
+func DebugCtx(ctx context.Context, message string, fields ...Fields) {
+	Debug(message, withContextFields(ctx, fields)...)
+}
+
+func InfoCtx(ctx context.Context, message string, fields ...Fields) {
+	Info(message, withContextFields(ctx, fields)...)
+}
+
+func WarnCtx(ctx context.Context, message string, fields ...Fields) {
+	Warn(message, withContextFields(ctx, fields)...)
+}
+
+func ErrorCtx(ctx context.Context, message string, fields ...Fields) {
+	Error(message, withContextFields(ctx, fields)...)
+}
+
+func CriticalCtx(ctx context.Context, message string, fields ...Fields) {
+	Critical(message, withContextFields(ctx, fields)...)
+}
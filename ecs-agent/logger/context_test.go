@@ -0,0 +1,81 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cihub/seelog"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mock_seelog "github.com/aws/amazon-ecs-agent/ecs-agent/logger/mocks"
+)
+
+func TestContextWithFields(t *testing.T) {
+	assert.Empty(t, FieldsFromContext(context.Background()))
+
+	parent := ContextWithFields(context.Background(), Fields{"cluster": "default", "taskARN": "task1"})
+	child := ContextWithFields(parent, Fields{"taskARN": "task2", "container": "app"})
+
+	assert.Equal(t, Fields{"cluster": "default", "taskARN": "task1"}, FieldsFromContext(parent))
+	assert.Equal(t, Fields{"cluster": "default", "taskARN": "task2", "container": "app"}, FieldsFromContext(child))
+
+	// The returned fields are a copy
+	FieldsFromContext(child)["container"] = "other"
+	assert.Equal(t, "app", FieldsFromContext(child)["container"])
+}
+
+func TestCtxLogger(t *testing.T) {
+	defer globalLoggerBackup()()
+	ctx := ContextWithFields(context.Background(), Fields{"cluster": "default", "taskARN": "task1"})
+	for _, tc := range []struct {
+		expectedLevel seelog.LogLevel
+		logfunc       func(context.Context, string, ...Fields)
+	}{
+		{seelog.TraceLvl, TraceCtx},
+		{seelog.DebugLvl, DebugCtx},
+		{seelog.InfoLvl, InfoCtx},
+		{seelog.WarnLvl, WarnCtx},
+		{seelog.ErrorLvl, ErrorCtx},
+		{seelog.CriticalLvl, CriticalCtx},
+	} {
+		t.Run(tc.expectedLevel.String(), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockReceiver := mock_seelog.NewMockCustomReceiver(ctrl)
+
+			seeLog, err := seelog.LoggerFromCustomReceiver(mockReceiver)
+			require.NoError(t, err)
+			setGlobalLogger(seeLog, jsonFmt)
+
+			// The fields of the message take precedence over the ones of the context
+			mockReceiver.EXPECT().ReceiveMessage(
+				`"logger":"structured","msg":"Container started","cluster":"default","container":"app","taskARN":"task2"`,
+				tc.expectedLevel,
+				gomock.Any(),
+			)
+			mockReceiver.EXPECT().Flush().AnyTimes()
+			mockReceiver.EXPECT().Close().AnyTimes()
+
+			tc.logfunc(ctx, "Container started", Fields{"taskARN": "task2", "container": "app"})
+		})
+	}
+}
@@ -266,6 +266,20 @@ func SetDriverLogLevel(driverLogLevel string) {
 	}
 }
 
+// SetLogLevel sets the log level for both the instance logs and the custom driver.
+func SetLogLevel(logLevel string) {
+	parsedLevel, ok := logLevels[strings.ToLower(logLevel)]
+	if ok {
+		Config.lock.Lock()
+		defer Config.lock.Unlock()
+		Config.driverLevel = parsedLevel
+		Config.instanceLevel = parsedLevel
+		reloadConfig()
+	} else {
+		seelog.Error("Log level mapping not found")
+	}
+}
+
 // SetConfigLogFile sets the default output file of the logger.
 func SetConfigLogFile(logFile string) {
 	if logFile != "" {
@@ -254,6 +254,26 @@ func TestSetLogLevels(t *testing.T) {
 	}
 }
 
+func TestSetLogLevel(t *testing.T) {
+	Config = &logConfig{
+		logfile:       "foo.log",
+		driverLevel:   DEFAULT_LOGLEVEL,
+		instanceLevel: DEFAULT_LOGLEVEL,
+		RolloverType:  DEFAULT_ROLLOVER_TYPE,
+		outputFormat:  DEFAULT_OUTPUT_FORMAT,
+		MaxFileSizeMB: DEFAULT_MAX_FILE_SIZE,
+		MaxRollCount:  DEFAULT_MAX_ROLL_COUNT,
+	}
+
+	SetLogLevel("crit")
+	require.Equal(t, "critical", Config.driverLevel)
+	require.Equal(t, "critical", Config.instanceLevel)
+
+	SetLogLevel("verbose")
+	require.Equal(t, "critical", Config.driverLevel, "unknown log levels should be ignored")
+	require.Equal(t, "critical", Config.instanceLevel, "unknown log levels should be ignored")
+}
+
 type LogContextMock struct{}
 
 // Caller's function name.
@@ -85,4 +85,8 @@ const (
 	DeleteNetworkNamespaceMetricName      = networkBuilderNamespace + ".DeleteNetworkNamespace"
 	V2NDestinationPortExhaustedMetricName = networkBuilderNamespace + ".V2NDestinationPortExhausted"
 	ReleaseGeneveDstPortMetricName        = dbClientMetricNamespace + ".ReleaseGeneveDstPort"
+
+	// Secret Files
+	secretFilesNamespace         = "SecretFiles"
+	SecretFilesRefreshMetricName = secretFilesNamespace + ".Refresh"
 )
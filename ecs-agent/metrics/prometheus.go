@@ -0,0 +1,301 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package metrics
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// PrometheusMetricsPath is the path on which the collected metrics are exposed.
+	PrometheusMetricsPath = "/metrics"
+
+	prometheusNamespace = "ecs_agent"
+
+	// OperationsTotalMetricName counts completed operations, labelled by op and error.
+	OperationsTotalMetricName = prometheusNamespace + "_operations_total"
+	// OperationCountMetricName accumulates the values passed to Entry.WithCount, labelled by op.
+	OperationCountMetricName = prometheusNamespace + "_operation_count_total"
+	// OperationGaugeMetricName holds the last value passed to Entry.WithGauge, labelled by op.
+	OperationGaugeMetricName = prometheusNamespace + "_operation_gauge"
+	// OperationDurationMetricName is a histogram of the time between New and Done, labelled by op and error.
+	OperationDurationMetricName = prometheusNamespace + "_operation_duration_seconds"
+
+	opLabel    = "op"
+	errorLabel = "error"
+)
+
+// durationBuckets are the upper bounds (in seconds) of the operation duration histogram. They cover
+// everything from local handler latencies up to slow network operations such as ACS/TCS connects.
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// PrometheusEntryFactory is an EntryFactory that aggregates every completed metric entry in memory
+// and exposes the aggregates in the Prometheus text exposition format when served over HTTP.
+type PrometheusEntryFactory interface {
+	EntryFactory
+	http.Handler
+}
+
+// seriesKey identifies a single time series of a metric family.
+type seriesKey struct {
+	op  string
+	err string
+}
+
+type histogram struct {
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+func (h *histogram) observe(v float64) {
+	for i, upperBound := range durationBuckets {
+		if v <= upperBound {
+			h.buckets[i]++
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+// prometheusEntryFactory implements the PrometheusEntryFactory interface.
+type prometheusEntryFactory struct {
+	lock       sync.RWMutex
+	operations map[seriesKey]float64
+	counts     map[seriesKey]float64
+	gauges     map[seriesKey]float64
+	durations  map[seriesKey]*histogram
+}
+
+// NewPrometheusEntryFactory creates a metric entry factory that records metrics as Prometheus
+// counters, gauges and duration histograms.
+func NewPrometheusEntryFactory() PrometheusEntryFactory {
+	return &prometheusEntryFactory{
+		operations: make(map[seriesKey]float64),
+		counts:     make(map[seriesKey]float64),
+		gauges:     make(map[seriesKey]float64),
+		durations:  make(map[seriesKey]*histogram),
+	}
+}
+
+func (f *prometheusEntryFactory) New(op string) Entry {
+	return &prometheusEntry{
+		factory:   f,
+		op:        op,
+		startTime: time.Now(),
+	}
+}
+
+// Flush is a no-op since metrics are pulled by the Prometheus server rather than pushed.
+func (f *prometheusEntryFactory) Flush() {}
+
+// ServeHTTP writes all the collected metrics in the Prometheus text exposition format.
+func (f *prometheusEntryFactory) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	format := expfmt.NewFormat(expfmt.TypeTextPlain)
+	w.Header().Set("Content-Type", string(format))
+	encoder := expfmt.NewEncoder(w, format)
+	for _, mf := range f.gather() {
+		if err := encoder.Encode(mf); err != nil {
+			logger.Warn("Unable to encode prometheus metric family", logger.Fields{
+				"metric":    mf.GetName(),
+				field.Error: err,
+			})
+			return
+		}
+	}
+}
+
+func (f *prometheusEntryFactory) record(e *prometheusEntry, err error) {
+	key := seriesKey{op: e.op, err: strconv.FormatBool(err != nil)}
+	duration := time.Since(e.startTime).Seconds()
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.operations[key]++
+	h, ok := f.durations[key]
+	if !ok {
+		h = &histogram{buckets: make([]uint64, len(durationBuckets))}
+		f.durations[key] = h
+	}
+	h.observe(duration)
+	if e.count != nil {
+		f.counts[seriesKey{op: e.op}] += float64(*e.count)
+	}
+	if e.gauge != nil {
+		f.gauges[seriesKey{op: e.op}] = *e.gauge
+	}
+}
+
+// gather builds the metric families from a snapshot of the recorded values.
+func (f *prometheusEntryFactory) gather() []*dto.MetricFamily {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	operations := newMetricFamily(OperationsTotalMetricName, "Number of completed agent operations.",
+		dto.MetricType_COUNTER)
+	for _, key := range sortedKeys(f.operations) {
+		operations.Metric = append(operations.Metric, &dto.Metric{
+			Label:   labelsWithError(key),
+			Counter: &dto.Counter{Value: proto.Float64(f.operations[key])},
+		})
+	}
+
+	counts := newMetricFamily(OperationCountMetricName, "Sum of the counts reported by agent operations.",
+		dto.MetricType_COUNTER)
+	for _, key := range sortedKeys(f.counts) {
+		counts.Metric = append(counts.Metric, &dto.Metric{
+			Label:   labels(key),
+			Counter: &dto.Counter{Value: proto.Float64(f.counts[key])},
+		})
+	}
+
+	gauges := newMetricFamily(OperationGaugeMetricName, "Last value reported by agent operations.",
+		dto.MetricType_GAUGE)
+	for _, key := range sortedKeys(f.gauges) {
+		gauges.Metric = append(gauges.Metric, &dto.Metric{
+			Label: labels(key),
+			Gauge: &dto.Gauge{Value: proto.Float64(f.gauges[key])},
+		})
+	}
+
+	durations := newMetricFamily(OperationDurationMetricName, "Duration of agent operations in seconds.",
+		dto.MetricType_HISTOGRAM)
+	for _, key := range sortedKeys(f.durations) {
+		h := f.durations[key]
+		buckets := make([]*dto.Bucket, len(durationBuckets))
+		for i, upperBound := range durationBuckets {
+			buckets[i] = &dto.Bucket{
+				UpperBound:      proto.Float64(upperBound),
+				CumulativeCount: proto.Uint64(h.buckets[i]),
+			}
+		}
+		durations.Metric = append(durations.Metric, &dto.Metric{
+			Label: labelsWithError(key),
+			Histogram: &dto.Histogram{
+				SampleCount: proto.Uint64(h.count),
+				SampleSum:   proto.Float64(h.sum),
+				Bucket:      buckets,
+			},
+		})
+	}
+
+	var families []*dto.MetricFamily
+	for _, mf := range []*dto.MetricFamily{operations, counts, gauges, durations} {
+		// Metric families without any samples are not valid in the exposition format.
+		if len(mf.Metric) > 0 {
+			families = append(families, mf)
+		}
+	}
+	return families
+}
+
+func newMetricFamily(name, help string, metricType dto.MetricType) *dto.MetricFamily {
+	return &dto.MetricFamily{
+		Name: proto.String(name),
+		Help: proto.String(help),
+		Type: metricType.Enum(),
+	}
+}
+
+func labels(key seriesKey) []*dto.LabelPair {
+	return []*dto.LabelPair{{Name: proto.String(opLabel), Value: proto.String(key.op)}}
+}
+
+func labelsWithError(key seriesKey) []*dto.LabelPair {
+	return append(labels(key), &dto.LabelPair{Name: proto.String(errorLabel), Value: proto.String(key.err)})
+}
+
+// sortedKeys returns the keys of the map in a stable order so that the exposition output is deterministic.
+func sortedKeys[V any](m map[seriesKey]V) []seriesKey {
+	keys := make([]seriesKey, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].op != keys[j].op {
+			return keys[i].op < keys[j].op
+		}
+		return keys[i].err < keys[j].err
+	})
+	return keys
+}
+
+// prometheusEntry implements the Entry interface. Fields set via WithFields are intentionally not
+// converted into labels since they usually carry high cardinality values such as task ARNs.
+type prometheusEntry struct {
+	factory   *prometheusEntryFactory
+	op        string
+	startTime time.Time
+	count     *int
+	gauge     *float64
+}
+
+func (e *prometheusEntry) WithFields(f map[string]interface{}) Entry { return e }
+
+func (e *prometheusEntry) WithCount(count int) Entry {
+	e.count = &count
+	return e
+}
+
+func (e *prometheusEntry) WithGauge(value interface{}) Entry {
+	if v, ok := toFloat64(value); ok {
+		e.gauge = &v
+	}
+	return e
+}
+
+func (e *prometheusEntry) Done(err error) {
+	e.factory.record(e, err)
+}
+
+// toFloat64 converts the numeric gauge values reported by callers into a float64.
+func toFloat64(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		if math.IsNaN(v) {
+			return 0, false
+		}
+		return v, true
+	case time.Duration:
+		return v.Seconds(), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,136 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOp = "Test.Op"
+
+func scrape(t *testing.T, factory PrometheusEntryFactory) map[string]*dto.MetricFamily {
+	req, err := http.NewRequest("GET", PrometheusMetricsPath, nil)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	factory.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.True(t, strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain"))
+
+	parser := expfmt.NewTextParser(model.UTF8Validation)
+	families, err := parser.TextToMetricFamilies(recorder.Body)
+	require.NoError(t, err)
+	return families
+}
+
+func findMetric(t *testing.T, mf *dto.MetricFamily, op, errLabel string) *dto.Metric {
+	require.NotNil(t, mf)
+	for _, m := range mf.GetMetric() {
+		labels := make(map[string]string)
+		for _, l := range m.GetLabel() {
+			labels[l.GetName()] = l.GetValue()
+		}
+		if labels[opLabel] != op {
+			continue
+		}
+		if errLabel != "" && labels[errorLabel] != errLabel {
+			continue
+		}
+		return m
+	}
+	require.Failf(t, "metric not found", "op %s error %s in %s", op, errLabel, mf.GetName())
+	return nil
+}
+
+func TestPrometheusEntryFactoryNoMetrics(t *testing.T) {
+	factory := NewPrometheusEntryFactory()
+	assert.Empty(t, scrape(t, factory))
+}
+
+func TestPrometheusEntryFactoryOperations(t *testing.T) {
+	factory := NewPrometheusEntryFactory()
+	factory.New(testOp).Done(nil)
+	factory.New(testOp).Done(nil)
+	factory.New(testOp).Done(errors.New("error"))
+
+	families := scrape(t, factory)
+	operations := families[OperationsTotalMetricName]
+	assert.Equal(t, dto.MetricType_COUNTER, operations.GetType())
+	assert.Equal(t, float64(2), findMetric(t, operations, testOp, "false").GetCounter().GetValue())
+	assert.Equal(t, float64(1), findMetric(t, operations, testOp, "true").GetCounter().GetValue())
+
+	durations := families[OperationDurationMetricName]
+	assert.Equal(t, dto.MetricType_HISTOGRAM, durations.GetType())
+	histogram := findMetric(t, durations, testOp, "false").GetHistogram()
+	assert.Equal(t, uint64(2), histogram.GetSampleCount())
+	// The parser adds the implicit +Inf bucket.
+	assert.Len(t, histogram.GetBucket(), len(durationBuckets)+1)
+
+	assert.NotContains(t, families, OperationCountMetricName)
+	assert.NotContains(t, families, OperationGaugeMetricName)
+}
+
+func TestPrometheusEntryFactoryCount(t *testing.T) {
+	factory := NewPrometheusEntryFactory()
+	factory.New(testOp).WithCount(1).Done(nil)
+	factory.New(testOp).WithCount(0).Done(nil)
+	factory.New(testOp).WithCount(1).WithFields(map[string]interface{}{"TaskARN": "arn"}).Done(nil)
+
+	families := scrape(t, factory)
+	counts := families[OperationCountMetricName]
+	assert.Equal(t, float64(2), findMetric(t, counts, testOp, "").GetCounter().GetValue())
+	assert.Len(t, counts.GetMetric()[0].GetLabel(), 1)
+}
+
+func TestPrometheusEntryFactoryGauge(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    interface{}
+		expected float64
+	}{
+		{name: "int64", value: int64(42), expected: 42},
+		{name: "int", value: 7, expected: 7},
+		{name: "float64", value: 1.5, expected: 1.5},
+		{name: "duration", value: 2 * time.Second, expected: 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			factory := NewPrometheusEntryFactory()
+			factory.New(testOp).WithGauge(tc.value).Done(nil)
+
+			gauges := scrape(t, factory)[OperationGaugeMetricName]
+			assert.Equal(t, dto.MetricType_GAUGE, gauges.GetType())
+			assert.Equal(t, tc.expected, findMetric(t, gauges, testOp, "").GetGauge().GetValue())
+		})
+	}
+
+	t.Run("non numeric value is ignored", func(t *testing.T) {
+		factory := NewPrometheusEntryFactory()
+		factory.New(testOp).WithGauge("value").Done(nil)
+		assert.NotContains(t, scrape(t, factory), OperationGaugeMetricName)
+	})
+}
@@ -61,7 +61,7 @@ func (c *cniClient) Add(ctx context.Context, config PluginConfig) (types.Result,
 		err = errors.New("Failed to build network config, net.Network is nil.")
 		return nil, err
 	}
-	logger.Debug("Built network config.", logger.Fields{"Type": net.Network.Type})
+	logger.DebugCtx(ctx, "Built network config.", logger.Fields{"Type": net.Network.Type})
 
 	return c.cni.AddNetwork(ctx, net, rt)
 }
@@ -25,12 +25,14 @@ import (
 	"github.com/aws/amazon-ecs-agent/ecs-agent/netlib/model/status"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/netlib/model/tasknetworkconfig"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/netlib/platform"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tracing"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/utils/netwrapper"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/volume"
 
 	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type NetworkBuilder interface {
@@ -90,11 +92,12 @@ func (nb *networkBuilder) Start(
 		"ServiceConnectEnabled": netNS.ServiceConnectConfig != nil,
 	}
 	metricEntry := nb.metricsFactory.New(metrics.BuildNetworkNamespaceMetricName).WithFields(logFields)
+	_, span := tracing.StartSpan(ctx, "StartNetworkNamespace", networkSpanAttributes(mode, taskID, netNS)...)
 
 	netNS.Mutex.Lock()
 	defer netNS.Mutex.Unlock()
 
-	logger.Info("Starting network namespace setup", logFields)
+	logger.InfoCtx(ctx, "Starting network namespace setup", logFields)
 
 	var err error
 	switch mode {
@@ -109,6 +112,7 @@ func (nb *networkBuilder) Start(
 	}
 
 	metricEntry.Done(err)
+	tracing.EndSpan(span, err)
 
 	return err
 }
@@ -122,11 +126,12 @@ func (nb *networkBuilder) Stop(ctx context.Context, mode types.NetworkMode, task
 		"ServiceConnectEnabled": netNS.ServiceConnectConfig != nil,
 	}
 	metricEntry := nb.metricsFactory.New(metrics.DeleteNetworkNamespaceMetricName).WithFields(logFields)
+	_, span := tracing.StartSpan(ctx, "StopNetworkNamespace", networkSpanAttributes(mode, taskID, netNS)...)
 
 	netNS.Mutex.Lock()
 	defer netNS.Mutex.Unlock()
 
-	logger.Info("Deleting network namespace setup", logFields)
+	logger.InfoCtx(ctx, "Deleting network namespace setup", logFields)
 
 	var err error
 	switch mode {
@@ -141,10 +146,21 @@ func (nb *networkBuilder) Stop(ctx context.Context, mode types.NetworkMode, task
 	}
 
 	metricEntry.Done(err)
+	tracing.EndSpan(span, err)
 
 	return err
 }
 
+// networkSpanAttributes returns the attributes of the spans of the operations on a network namespace
+func networkSpanAttributes(mode types.NetworkMode, taskID string,
+	netNS *tasknetworkconfig.NetworkNamespace) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		tracing.TaskIDKey.String(taskID),
+		attribute.String("aws.ecs.network.mode", string(mode)),
+		attribute.String("aws.ecs.network.namespace", netNS.Name),
+	}
+}
+
 // startAWSVPC executes the required platform API methods in order to configure
 // the task's network namespace running in AWSVPC mode.
 func (nb *networkBuilder) startAWSVPC(ctx context.Context, taskID string, netNS *tasknetworkconfig.NetworkNamespace) error {
@@ -158,14 +174,14 @@ func (nb *networkBuilder) startAWSVPC(ctx context.Context, taskID string, netNS
 	if netNS.KnownState == status.NetworkNone &&
 		netNS.DesiredState == status.NetworkReadyPull {
 
-		logger.Debug("Creating netns: " + netNS.Path)
+		logger.DebugCtx(ctx, "Creating netns: "+netNS.Path)
 		// Create network namespace on the host.
 		err = nb.platformAPI.CreateNetNS(netNS.Path)
 		if err != nil {
 			return err
 		}
 
-		logger.Debug("Creating DNS config files")
+		logger.DebugCtx(ctx, "Creating DNS config files")
 
 		// Create necessary DNS config files for the netns.
 		err = nb.platformAPI.CreateDNSConfig(taskID, netNS)
@@ -183,7 +199,7 @@ func (nb *networkBuilder) startAWSVPC(ctx context.Context, taskID string, netNS
 	if netNS.KnownState == status.NetworkReadyPull &&
 		netNS.DesiredState == status.NetworkReady {
 		if netNS.AppMeshConfig != nil {
-			logger.Debug("Configuring AppMesh", logger.Fields{
+			logger.DebugCtx(ctx, "Configuring AppMesh", logger.Fields{
 				"AppMeshConfig": netNS.AppMeshConfig,
 			})
 
@@ -194,7 +210,7 @@ func (nb *networkBuilder) startAWSVPC(ctx context.Context, taskID string, netNS
 		}
 
 		if netNS.ServiceConnectConfig != nil {
-			logger.Debug("Configuring ServiceConnect", logger.Fields{
+			logger.DebugCtx(ctx, "Configuring ServiceConnect", logger.Fields{
 				"ServiceConnectConfig": netNS.ServiceConnectConfig,
 			})
 
@@ -220,18 +236,18 @@ func (nb *networkBuilder) configureNetNSInterfaces(ctx context.Context, netNS *t
 			"DesiredStatus": iface.DesiredStatus,
 		}
 		if iface.KnownStatus == netNS.DesiredState {
-			logger.Debug("Interface already in desired state", logFields)
+			logger.DebugCtx(ctx, "Interface already in desired state", logFields)
 			continue
 		}
 
 		// The interface desired status is driven by the network namespace's desired state.
-		logger.Debug("Configuring interface", logFields)
+		logger.DebugCtx(ctx, "Configuring interface", logFields)
 		iface.DesiredStatus = netNS.DesiredState
 
 		err := nb.platformAPI.ConfigureInterface(ctx, netNS.Path, iface, nb.networkDAO)
 		if err != nil {
 			if netNS.DesiredState == status.NetworkDeleted {
-				logger.Error(fmt.Sprintf("Failed to configure interface: %v", err), logFields)
+				logger.ErrorCtx(ctx, fmt.Sprintf("Failed to configure interface: %v", err), logFields)
 				errs = multierror.Append(err, errs)
 			} else {
 				return err
@@ -242,7 +258,7 @@ func (nb *networkBuilder) configureNetNSInterfaces(ctx context.Context, netNS *t
 		// Save new state of the network interface in the database.
 		if err = nb.networkDAO.SaveNetworkNamespace(netNS); err != nil {
 			if netNS.DesiredState == status.NetworkDeleted {
-				logger.Error(fmt.Sprintf("Failed to persist interface state: %v", err), logFields)
+				logger.ErrorCtx(ctx, fmt.Sprintf("Failed to persist interface state: %v", err), logFields)
 				errs = multierror.Append(err, errs)
 			} else {
 				return err
@@ -264,19 +280,19 @@ func (nb *networkBuilder) stopAWSVPC(ctx context.Context, netNS *tasknetworkconf
 	}
 	err := nb.configureNetNSInterfaces(ctx, netNS)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to cleanup interfaces in netns: %v", err), logFields)
+		logger.ErrorCtx(ctx, fmt.Sprintf("Failed to cleanup interfaces in netns: %v", err), logFields)
 		errs = multierror.Append(err, errs)
 	}
 
 	err = nb.platformAPI.DeleteDNSConfig(netNS.Name)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to cleanup DNS config files: %v", err))
+		logger.ErrorCtx(ctx, fmt.Sprintf("Failed to cleanup DNS config files: %v", err))
 		errs = multierror.Append(err, errs)
 	}
 
 	err = nb.platformAPI.DeleteNetNS(netNS.Path)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to delete network namespace: %v", err), logFields)
+		logger.ErrorCtx(ctx, fmt.Sprintf("Failed to delete network namespace: %v", err), logFields)
 		errs = multierror.Append(err, errs)
 	}
 
@@ -683,7 +683,7 @@ func (c *common) configureInterface(
 
 // configureRegularENI configures a network interface for an ENI.
 func (c *common) configureRegularENI(ctx context.Context, netNSPath string, eni *networkinterface.NetworkInterface) error {
-	logger.Info("Configuring regular ENI", map[string]interface{}{
+	logger.InfoCtx(ctx, "Configuring regular ENI", map[string]interface{}{
 		"ENIName":   eni.Name,
 		"NetNSPath": netNSPath,
 	})
@@ -719,7 +719,7 @@ func (c *common) configureRegularENI(ctx context.Context, netNSPath string, eni
 
 // configureBranchENI configures a network interface for a branch ENI.
 func (c *common) configureBranchENI(ctx context.Context, netNSPath string, eni *networkinterface.NetworkInterface) error {
-	logger.Info("Configuring branch ENI", map[string]interface{}{
+	logger.InfoCtx(ctx, "Configuring branch ENI", map[string]interface{}{
 		"ENIName":   eni.Name,
 		"NetNSPath": netNSPath,
 	})
@@ -760,7 +760,7 @@ func (c *common) configureGENEVEInterface(
 	iface *networkinterface.NetworkInterface,
 	netDAO netlibdata.NetworkDataClient,
 ) error {
-	logger.Info("Configuring GENEVE interface", map[string]interface{}{
+	logger.InfoCtx(ctx, "Configuring GENEVE interface", map[string]interface{}{
 		"ENIName":   iface.Name,
 		"NetNSPath": netNSPath,
 	})
@@ -788,7 +788,7 @@ func (c *common) configureGENEVEInterface(
 		return err
 	}
 
-	logger.Info("GENEVE interface configured", map[string]interface{}{
+	logger.InfoCtx(ctx, "GENEVE interface configured", map[string]interface{}{
 		"ENIName":   iface.Name,
 		"NetNSPath": netNSPath,
 	})
@@ -836,7 +836,7 @@ func (c *common) configureAppMesh(
 	netNSPath string,
 	cfg *appmesh.AppMesh,
 ) error {
-	logger.Info("Configuring AppMesh", map[string]interface{}{
+	logger.InfoCtx(ctx, "Configuring AppMesh", map[string]interface{}{
 		"NetNSPath": netNSPath,
 	})
 
@@ -861,7 +861,7 @@ func (c *common) configureServiceConnect(
 	taskENI *networkinterface.NetworkInterface,
 	scConfig *serviceconnect.ServiceConnectConfig,
 ) error {
-	logger.Info("Configuring ServiceConnect", map[string]interface{}{
+	logger.InfoCtx(ctx, "Configuring ServiceConnect", map[string]interface{}{
 		"NetNSPath": netNSPath,
 	})
 
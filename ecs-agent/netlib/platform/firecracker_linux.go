@@ -193,7 +193,7 @@ func assignInterfacesToNamespaces(taskPayload *ecsacs.Task) (map[string]string,
 
 // configureBranchENI configures a network interface for a branch ENI.
 func (f *firecraker) configureBranchENI(ctx context.Context, netNSPath string, eni *networkinterface.NetworkInterface) error {
-	logger.Info("Configuring branch ENI", map[string]interface{}{
+	logger.InfoCtx(ctx, "Configuring branch ENI", map[string]interface{}{
 		"ENIName":   eni.Name,
 		"NetNSPath": netNSPath,
 	})
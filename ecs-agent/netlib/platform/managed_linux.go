@@ -176,7 +176,7 @@ func (m *managedLinux) configureInterface(
 }
 
 func (m *managedLinux) configureRegularENI(ctx context.Context, netNSPath string, eni *networkinterface.NetworkInterface) error {
-	logger.Info("Configuring regular ENI", map[string]interface{}{
+	logger.InfoCtx(ctx, "Configuring regular ENI", map[string]interface{}{
 		"ENIName":   eni.Name,
 		"NetNSPath": netNSPath,
 	})
@@ -216,7 +216,7 @@ func (m *managedLinux) configureRegularENI(ctx context.Context, netNSPath string
 
 // configureBranchENI configures a network interface for a branch ENI.
 func (m *managedLinux) configureBranchENI(ctx context.Context, netNSPath string, eni *networkinterface.NetworkInterface) error {
-	logger.Info("Configuring branch ENI", map[string]interface{}{
+	logger.InfoCtx(ctx, "Configuring branch ENI", map[string]interface{}{
 		"ENIName":   eni.Name,
 		"NetNSPath": netNSPath,
 	})
@@ -508,7 +508,7 @@ func (m *managedLinux) configureDaemonNetNS(ctx context.Context, taskID string,
 	if netNS.KnownState == status.NetworkNone &&
 		netNS.DesiredState == status.NetworkReadyPull {
 
-		logger.Debug("Creating daemon netns: " + netNS.Path)
+		logger.DebugCtx(ctx, "Creating daemon netns: "+netNS.Path)
 
 		// Serialize the entire namespace setup to prevent concurrent goroutines from
 		// racing on CreateNetNS, isDaemonNamespaceConfigured, and CNI plugin execution.
@@ -521,7 +521,7 @@ func (m *managedLinux) configureDaemonNetNS(ctx context.Context, taskID string,
 			return err
 		}
 
-		logger.Debug("Creating DNS config files for daemon NS")
+		logger.DebugCtx(ctx, "Creating DNS config files for daemon NS")
 
 		// Create necessary DNS config files for the netns.
 		err = m.CreateDNSConfig(taskID, netNS)
@@ -555,12 +555,12 @@ func (m *managedLinux) configureDaemonNetNS(ctx context.Context, taskID string,
 			// Add NAT masquerade rule for external connectivity
 			err = m.addDaemonBridgeNATRule(ipComp)
 			if err != nil {
-				logger.Warn("Failed to add NAT rule for daemon-bridge", logger.Fields{
+				logger.WarnCtx(ctx, "Failed to add NAT rule for daemon-bridge", logger.Fields{
 					loggerfield.Error: err,
 				})
 			}
 		} else {
-			logger.Info("Daemon namespace already configured, skipping CNI plugin setup")
+			logger.InfoCtx(ctx, "Daemon namespace already configured, skipping CNI plugin setup")
 		}
 	}
 	return nil
@@ -630,7 +630,7 @@ func (m *managedLinux) getIPCompatibilityFromNetNS(netNS *tasknetworkconfig.Netw
 
 // StopDaemonNetNS stops and cleans up a daemon network namespace.
 func (m *managedLinux) StopDaemonNetNS(ctx context.Context, netNS *tasknetworkconfig.NetworkNamespace) error {
-	logger.Info("Starting StopDaemonNetNS", logger.Fields{
+	logger.InfoCtx(ctx, "Starting StopDaemonNetNS", logger.Fields{
 		"netNSPath":  netNS.Path,
 		"knownState": netNS.KnownState,
 	})
@@ -647,7 +647,7 @@ func (m *managedLinux) StopDaemonNetNS(ctx context.Context, netNS *tasknetworkco
 	bridgeConfig, err := createDaemonBridgePluginConfig(netNS.Path, ipComp)
 	if err != nil {
 		err = errors.Wrap(err, "failed to create daemon bridge plugin config to stop daemon netns")
-		logger.Error("StopDaemonNetNS failed", logger.Fields{
+		logger.ErrorCtx(ctx, "StopDaemonNetNS failed", logger.Fields{
 			"netNSPath":       netNS.Path,
 			loggerfield.Error: err,
 		})
@@ -661,12 +661,12 @@ func (m *managedLinux) StopDaemonNetNS(ctx context.Context, netNS *tasknetworkco
 	_, err = m.common.executeCNIPlugin(ctx, add, cniNetConf...)
 	if err != nil {
 		err = errors.Wrap(err, "failed to stop daemon network namespace bridge")
-		logger.Error("StopDaemonNetNS failed", logger.Fields{
+		logger.ErrorCtx(ctx, "StopDaemonNetNS failed", logger.Fields{
 			"netNSPath":       netNS.Path,
 			loggerfield.Error: err,
 		})
 	} else {
-		logger.Info("StopDaemonNetNS completed successfully", logger.Fields{
+		logger.InfoCtx(ctx, "StopDaemonNetNS completed successfully", logger.Fields{
 			"netNSPath": netNS.Path,
 		})
 	}
@@ -0,0 +1,571 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/audit"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/types"
+	handlerutils "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/utils"
+	state "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/v4/state"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+const (
+	// Commands that will be used to start/stop/check network DNS faults. The chain of the fault is inserted
+	// into the OUTPUT chain of the filter table to drop the matching queries, and of the nat table to
+	// redirect them to the DNS responder.
+	iptablesNewDNSChainCmd         = "%s -w %d -t %s -N %s"
+	iptablesAppendDNSMarkRuleCmd   = "%s -w %d -t %s -A %s -m mark --mark %d -j RETURN"
+	iptablesAppendDNSQueryRuleCmd  = "%s -w %d -t %s -A %s -p %s --dport 53 -m string --algo bm --icase --hex-string |%s| -j %s"
+	iptablesInsertDNSChainCmd      = "%s -w %d -t %s -I OUTPUT -j %s"
+	iptablesListOutputChainCmd     = "%s -w %d -t %s -S OUTPUT"
+	iptablesClearDNSChainCmd       = "%s -w %d -t %s -F %s"
+	iptablesDeleteDNSFromOutputCmd = "%s -w %d -t %s -D OUTPUT -j %s"
+	iptablesDeleteDNSChainCmd      = "%s -w %d -t %s -X %s"
+	iptablesFilterTable            = "filter"
+	iptablesNATTable               = "nat"
+	dnsFaultChainPrefix            = "dns-"
+	redirectTargetFormat           = "REDIRECT --to-ports %d"
+	// dnsResponderMark marks the queries forwarded by the DNS responder so that they aren't redirected back
+	// to it.
+	dnsResponderMark = 0xecd5
+	// defaultDNSResolver is the Amazon provided DNS server, which is reachable from any VPC.
+	defaultDNSResolver       = "169.254.169.253"
+	dnsFaultNetworkModeError = "%s faults are only supported for tasks in awsvpc network mode"
+	// DNS response codes
+	dnsRcodeServFail = 2
+	dnsRcodeNXDomain = 3
+	dnsHeaderLength  = 12
+)
+
+// dnsFaultActions are the actions of network DNS faults, in the order their chains are looked for.
+var dnsFaultActions = []string{
+	types.DNSActionBlackhole,
+	types.DNSActionDelay,
+	types.DNSActionNXDomain,
+	types.DNSActionServFail,
+}
+
+// DNSResponder answers the DNS queries redirected by network DNS faults from within task network namespaces.
+type DNSResponder interface {
+	// Start starts answering the DNS queries sent to the returned port within the network namespace according
+	// to the action of the fault, which is any action but blackhole. The delay action forwards the queries to
+	// the resolver once delayed.
+	Start(netNSPath, action string, delay time.Duration, resolver string) (uint16, error)
+	// Stop stops answering the DNS queries within the network namespace.
+	Stop(netNSPath string) error
+	// IsRunning returns whether DNS queries are answered within the network namespace.
+	IsRunning(netNSPath string) bool
+}
+
+// StartNetworkDNS starts a network DNS fault in the associated network namespace if no existing same fault.
+func (h *FaultHandler) StartNetworkDNS() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request types.NetworkDNSRequest
+		requestType := fmt.Sprintf(startFaultRequestType, types.DNSFaultType)
+		// Parse the fault request
+		err := decodeRequest(w, &request, requestType, r)
+		if err != nil {
+			return
+		}
+
+		// Validate the fault request
+		err = validateRequest(w, request, requestType)
+		if err != nil {
+			return
+		}
+
+		// Obtain the task metadata via the endpoint container ID
+		taskMetadata, err := validateDNSFaultTaskMetadata(w, h.AgentState, requestType, r)
+		if err != nil {
+			return
+		}
+
+		// To avoid multiple requests to manipulate same network resource
+		networkNSPath := taskMetadata.TaskNetworkConfig.NetworkNamespaces[0].Path
+		rwMu := h.loadLock(networkNSPath)
+		rwMu.Lock()
+		defer rwMu.Unlock()
+
+		var responseBody types.NetworkFaultInjectionResponse
+		var httpStatusCode int
+		stringToBeLogged := "Failed to start fault"
+		// All command executions for the start fault workflow all together should finish within 5 seconds.
+		// Thus, create the context here so that it can be shared by all os/exec calls.
+		ctx, cancel := h.osExecWrapper.NewExecContextWithTimeout(context.Background(), requestTimeoutSeconds*time.Second)
+		defer cancel()
+		// Check the status of current fault injection.
+		runningAction, err := h.checkNetworkDNSFault(ctx, taskMetadata)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			responseBody = types.NewNetworkFaultInjectionErrorResponse(fmt.Sprintf(requestTimedOutError, requestType))
+			httpStatusCode = http.StatusInternalServerError
+		} else if err != nil {
+			responseBody = types.NewNetworkFaultInjectionErrorResponse(internalError)
+			httpStatusCode = http.StatusInternalServerError
+		} else if runningAction != "" {
+			// If there already exists a DNS fault in the task network namespace.
+			responseBody = types.NewNetworkFaultInjectionErrorResponse(faultAlreadyRunningError(types.DNSFaultType))
+			httpStatusCode = http.StatusConflict
+		} else {
+			// Invoke the start fault injection functionality if not running.
+			err := h.startNetworkDNSFault(ctx, taskMetadata, request)
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				responseBody = types.NewNetworkFaultInjectionErrorResponse(fmt.Sprintf(requestTimedOutError, requestType))
+				httpStatusCode = http.StatusInternalServerError
+			} else if err != nil {
+				responseBody = types.NewNetworkFaultInjectionErrorResponse(internalError)
+				httpStatusCode = http.StatusInternalServerError
+			} else {
+				stringToBeLogged = "Successfully started fault"
+				responseBody = types.NewNetworkFaultInjectionSuccessResponse("running")
+				fault := h.newInjectedFault(taskMetadata, types.DNSFaultType,
+					faultID(networkNSPath, types.DNSFaultType), request.ToString(), request.DurationSeconds)
+				fault.DNSAction = aws.ToString(request.Action)
+				h.recordFault(fault)
+				responseBody.RemainingDurationSeconds = request.DurationSeconds
+				httpStatusCode = http.StatusOK
+			}
+		}
+		h.auditFault(r, taskMetadata.TaskARN, httpStatusCode, audit.StartFaultEventType, types.DNSFaultType,
+			request.ToString())
+		logger.Info(stringToBeLogged, logger.Fields{
+			field.RequestType: requestType,
+			field.Request:     request.ToString(),
+			field.Response:    responseBody.ToString(),
+		})
+		handlerutils.WriteJSONResponse(
+			w,
+			httpStatusCode,
+			responseBody,
+			requestType,
+		)
+	}
+}
+
+// StopNetworkDNS stops a network DNS fault in the associated network namespace if there is one existing same
+// fault.
+func (h *FaultHandler) StopNetworkDNS() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request types.NetworkDNSRequest
+		requestType := fmt.Sprintf(stopFaultRequestType, types.DNSFaultType)
+		logRequest(requestType, r)
+
+		// Obtain the task metadata via the endpoint container ID
+		taskMetadata, err := validateDNSFaultTaskMetadata(w, h.AgentState, requestType, r)
+		if err != nil {
+			return
+		}
+
+		// To avoid multiple requests to manipulate same network resource
+		networkNSPath := taskMetadata.TaskNetworkConfig.NetworkNamespaces[0].Path
+		rwMu := h.loadLock(networkNSPath)
+		rwMu.Lock()
+		defer rwMu.Unlock()
+
+		var responseBody types.NetworkFaultInjectionResponse
+		var httpStatusCode int
+		stringToBeLogged := "Failed to stop fault"
+		// All command executions for the stop fault workflow all together should finish within 5 seconds.
+		// Thus, create the context here so that it can be shared by all os/exec calls.
+		ctx, cancel := h.osExecWrapper.NewExecContextWithTimeout(context.Background(), requestTimeoutSeconds*time.Second)
+		defer cancel()
+		// Check the status of current fault injection.
+		runningAction, err := h.checkNetworkDNSFault(ctx, taskMetadata)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			responseBody = types.NewNetworkFaultInjectionErrorResponse(fmt.Sprintf(requestTimedOutError, requestType))
+			httpStatusCode = http.StatusInternalServerError
+		} else if err != nil {
+			responseBody = types.NewNetworkFaultInjectionErrorResponse(internalError)
+			httpStatusCode = http.StatusInternalServerError
+		} else if runningAction == "" {
+			// If there doesn't already exist a DNS fault
+			stringToBeLogged = "No fault running"
+			responseBody = types.NewNetworkFaultInjectionSuccessResponse("stopped")
+			httpStatusCode = http.StatusOK
+		} else {
+			// Invoke the stop fault injection functionality if running.
+			err := h.stopNetworkDNSFault(ctx, taskMetadata, runningAction)
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				responseBody = types.NewNetworkFaultInjectionErrorResponse(fmt.Sprintf(requestTimedOutError, requestType))
+				httpStatusCode = http.StatusInternalServerError
+			} else if err != nil {
+				responseBody = types.NewNetworkFaultInjectionErrorResponse(internalError)
+				httpStatusCode = http.StatusInternalServerError
+			} else {
+				stringToBeLogged = "Successfully stopped fault"
+				responseBody = types.NewNetworkFaultInjectionSuccessResponse("stopped")
+				httpStatusCode = http.StatusOK
+			}
+		}
+		parameters := h.faultParameters(faultID(networkNSPath, types.DNSFaultType))
+		if httpStatusCode == http.StatusOK {
+			h.forgetFault(faultID(networkNSPath, types.DNSFaultType))
+		}
+		h.auditFault(r, taskMetadata.TaskARN, httpStatusCode, audit.StopFaultEventType, types.DNSFaultType,
+			parameters)
+		logger.Info(stringToBeLogged, logger.Fields{
+			field.RequestType: requestType,
+			field.Request:     request.ToString(),
+			field.Response:    responseBody.ToString(),
+		})
+		handlerutils.WriteJSONResponse(
+			w,
+			httpStatusCode,
+			responseBody,
+			requestType,
+		)
+	}
+}
+
+// CheckNetworkDNS checks the status of given network DNS fault.
+func (h *FaultHandler) CheckNetworkDNS() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request types.NetworkDNSRequest
+		requestType := fmt.Sprintf(checkStatusFaultRequestType, types.DNSFaultType)
+		logRequest(requestType, r)
+
+		// Obtain the task metadata via the endpoint container ID
+		taskMetadata, err := validateDNSFaultTaskMetadata(w, h.AgentState, requestType, r)
+		if err != nil {
+			return
+		}
+
+		// To avoid multiple requests to manipulate same network resource
+		networkNSPath := taskMetadata.TaskNetworkConfig.NetworkNamespaces[0].Path
+		rwMu := h.loadLock(networkNSPath)
+		rwMu.RLock()
+		defer rwMu.RUnlock()
+
+		var responseBody types.NetworkFaultInjectionResponse
+		var httpStatusCode int
+		stringToBeLogged := "Failed to check status for fault"
+		// All command executions for the check fault workflow all together should finish within 5 seconds.
+		// Thus, create the context here so that it can be shared by all os/exec calls.
+		ctx, cancel := h.osExecWrapper.NewExecContextWithTimeout(context.Background(), requestTimeoutSeconds*time.Second)
+		defer cancel()
+		// Check the status of current fault injection.
+		runningAction, err := h.checkNetworkDNSFault(ctx, taskMetadata)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			responseBody = types.NewNetworkFaultInjectionErrorResponse(fmt.Sprintf(requestTimedOutError, requestType))
+			httpStatusCode = http.StatusInternalServerError
+		} else if err != nil {
+			responseBody = types.NewNetworkFaultInjectionErrorResponse(internalError)
+			httpStatusCode = http.StatusInternalServerError
+		} else {
+			stringToBeLogged = "Successfully checked fault status"
+			if runningAction != "" {
+				responseBody = types.NewNetworkFaultInjectionSuccessResponse("running")
+				responseBody.RemainingDurationSeconds = h.faultRemainingDuration(
+					faultID(networkNSPath, types.DNSFaultType))
+			} else {
+				responseBody = types.NewNetworkFaultInjectionSuccessResponse("not-running")
+			}
+			httpStatusCode = http.StatusOK
+		}
+		logger.Info(stringToBeLogged, logger.Fields{
+			field.RequestType: requestType,
+			field.Request:     request.ToString(),
+			field.Response:    responseBody.ToString(),
+		})
+		handlerutils.WriteJSONResponse(
+			w,
+			httpStatusCode,
+			responseBody,
+			requestType,
+		)
+	}
+}
+
+// validateDNSFaultTaskMetadata obtains the task metadata like validateTaskMetadata and additionally requires
+// the task to use the awsvpc network mode. Network DNS faults are not supported in host mode since they would
+// impact every DNS query sent from the host.
+func validateDNSFaultTaskMetadata(w http.ResponseWriter, agentState state.AgentState, requestType string,
+	r *http.Request) (*state.TaskResponse, error) {
+	taskMetadata, err := validateTaskMetadata(w, agentState, requestType, r)
+	if err != nil {
+		return nil, err
+	}
+	if ecstypes.NetworkMode(taskMetadata.TaskNetworkConfig.NetworkMode) != ecstypes.NetworkModeAwsvpc {
+		err = fmt.Errorf(dnsFaultNetworkModeError, types.DNSFaultType)
+		logger.Error("Error: Unsupported network mode", logger.Fields{
+			field.RequestType: requestType,
+			field.TaskARN:     taskMetadata.TaskARN,
+			field.Error:       err,
+		})
+		handlerutils.WriteJSONResponse(
+			w,
+			http.StatusBadRequest,
+			types.NewNetworkFaultInjectionErrorResponse(err.Error()),
+			requestType,
+		)
+		return nil, err
+	}
+	return taskMetadata, nil
+}
+
+// dnsFaultChainName returns the name of the iptables chain of a network DNS fault with the given action.
+func dnsFaultChainName(action string) string {
+	return dnsFaultChainPrefix + action
+}
+
+// dnsFaultTable returns the iptables table the chain of a network DNS fault with the given action is in.
+func dnsFaultTable(action string) string {
+	if action == types.DNSActionBlackhole {
+		return iptablesFilterTable
+	}
+	return iptablesNATTable
+}
+
+// encodeDNSName returns the hex encoded wire format of the domain name of the pattern, as it appears in the
+// question section of DNS queries. A query for a subdomain ends with the encoded domain name as well.
+func encodeDNSName(pattern string) string {
+	domain := strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(pattern, "*."), "."))
+	var encoded strings.Builder
+	for _, label := range strings.Split(domain, ".") {
+		encoded.WriteString(fmt.Sprintf("%02x", len(label)))
+		encoded.WriteString(hex.EncodeToString([]byte(label)))
+	}
+	encoded.WriteString("00")
+	return encoded.String()
+}
+
+// startNetworkDNSFault starts a network DNS fault within the task network namespace. The general workflow is
+// as followed:
+// 1. Starts the DNS responder answering the redirected queries unless the action is blackhole
+// 2. Creates a new chain in the filter table for the blackhole action and the nat table otherwise
+// 3. Appends rules to the chain matching the UDP and TCP queries of each domain via the string match module,
+// and either dropping them or redirecting them to the DNS responder
+// 4. Inserts the chain into the built-in OUTPUT chain
+func (h *FaultHandler) startNetworkDNSFault(ctx context.Context, taskMetadata *state.TaskResponse,
+	request types.NetworkDNSRequest) error {
+	netNs := taskMetadata.TaskNetworkConfig.NetworkNamespaces[0].Path
+	action := aws.ToString(request.Action)
+	table := dnsFaultTable(action)
+	chain := dnsFaultChainName(action)
+	protocols := []string{"udp"}
+	target := dropTarget
+	if action == types.DNSActionBlackhole {
+		// Queries over TCP can only be matched once the connection is established, which is fine to drop them
+		// but too late to redirect them.
+		protocols = append(protocols, "tcp")
+	} else {
+		resolver := defaultDNSResolver
+		if request.Resolver != nil {
+			resolver = aws.ToString(request.Resolver)
+		}
+		port, err := h.dnsResponder.Start(netNs, action,
+			time.Duration(aws.ToUint64(request.DelayMilliseconds))*time.Millisecond, resolver)
+		if err != nil {
+			logger.Error("Unable to start the DNS responder", logger.Fields{
+				"netns":       netNs,
+				field.TaskARN: taskMetadata.TaskARN,
+				field.Error:   err,
+			})
+			return err
+		}
+		target = fmt.Sprintf(redirectTargetFormat, port)
+	}
+
+	logger.Info("Attempting to start network DNS fault", logger.Fields{
+		"netns":       netNs,
+		"chain":       chain,
+		field.TaskARN: taskMetadata.TaskARN,
+	})
+	err := func() error {
+		for _, iptablesUtilityTool := range []string{iptablesUtilityToolV4, iptablesUtilityToolV6} {
+			cmdStrings := []string{fmt.Sprintf(iptablesNewDNSChainCmd, iptablesUtilityTool, requestTimeoutSeconds,
+				table, chain)}
+			if action != types.DNSActionBlackhole {
+				// Let the queries forwarded by the DNS responder through.
+				cmdStrings = append(cmdStrings, fmt.Sprintf(iptablesAppendDNSMarkRuleCmd, iptablesUtilityTool,
+					requestTimeoutSeconds, table, chain, dnsResponderMark))
+			}
+			for _, domain := range request.Domains {
+				for _, protocol := range protocols {
+					cmdStrings = append(cmdStrings, fmt.Sprintf(iptablesAppendDNSQueryRuleCmd, iptablesUtilityTool,
+						requestTimeoutSeconds, table, chain, protocol, encodeDNSName(aws.ToString(domain)), target))
+				}
+			}
+			cmdStrings = append(cmdStrings, fmt.Sprintf(iptablesInsertDNSChainCmd, iptablesUtilityTool,
+				requestTimeoutSeconds, table, chain))
+			for _, cmdString := range cmdStrings {
+				if err := h.runDNSFaultCommand(ctx, taskMetadata, cmdString,
+					iptablesUtilityTool == iptablesUtilityToolV6); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}()
+	if err != nil && action != types.DNSActionBlackhole {
+		if stopErr := h.dnsResponder.Stop(netNs); stopErr != nil {
+			logger.Warn("Unable to stop the DNS responder", logger.Fields{
+				"netns":       netNs,
+				field.TaskARN: taskMetadata.TaskARN,
+				field.Error:   stopErr,
+			})
+		}
+	}
+	return err
+}
+
+// stopNetworkDNSFault stops the network DNS fault with the given action within the task network namespace.
+// The general workflow is as followed:
+// 1. Removes the chain from the built-in OUTPUT chain
+// 2. Clears all rules within the chain and deletes it
+// 3. Stops the DNS responder unless the action is blackhole
+func (h *FaultHandler) stopNetworkDNSFault(ctx context.Context, taskMetadata *state.TaskResponse,
+	action string) error {
+	netNs := taskMetadata.TaskNetworkConfig.NetworkNamespaces[0].Path
+	table := dnsFaultTable(action)
+	chain := dnsFaultChainName(action)
+	logger.Info("Attempting to stop network DNS fault", logger.Fields{
+		"netns":       netNs,
+		"chain":       chain,
+		field.TaskARN: taskMetadata.TaskARN,
+	})
+	for _, iptablesUtilityTool := range []string{iptablesUtilityToolV4, iptablesUtilityToolV6} {
+		for _, cmdString := range []string{
+			fmt.Sprintf(iptablesDeleteDNSFromOutputCmd, iptablesUtilityTool, requestTimeoutSeconds, table, chain),
+			fmt.Sprintf(iptablesClearDNSChainCmd, iptablesUtilityTool, requestTimeoutSeconds, table, chain),
+			fmt.Sprintf(iptablesDeleteDNSChainCmd, iptablesUtilityTool, requestTimeoutSeconds, table, chain),
+		} {
+			if err := h.runDNSFaultCommand(ctx, taskMetadata, cmdString,
+				iptablesUtilityTool == iptablesUtilityToolV6); err != nil {
+				return err
+			}
+		}
+	}
+	if action != types.DNSActionBlackhole {
+		return h.dnsResponder.Stop(netNs)
+	}
+	return nil
+}
+
+// checkNetworkDNSFault checks if there's a running network DNS fault within the task network namespace and
+// returns its action, or an empty string if there is none. Like for network blackhole port faults, only the
+// IPv4 tables are checked.
+func (h *FaultHandler) checkNetworkDNSFault(ctx context.Context, taskMetadata *state.TaskResponse) (string, error) {
+	netNs := taskMetadata.TaskNetworkConfig.NetworkNamespaces[0].Path
+	for _, table := range []string{iptablesFilterTable, iptablesNATTable} {
+		cmdString := fmt.Sprintf(nsenterCommandString, netNs) +
+			fmt.Sprintf(iptablesListOutputChainCmd, iptablesUtilityToolV4, requestTimeoutSeconds, table)
+		cmdOutput, err := h.runExecCommand(ctx, strings.Split(cmdString, " "))
+		if err != nil {
+			logger.Error("Error: Unable to check status of network DNS fault", logger.Fields{
+				"netns":       netNs,
+				"command":     cmdString,
+				"output":      string(cmdOutput),
+				field.TaskARN: taskMetadata.TaskARN,
+				field.Error:   err,
+			})
+			return "", fmt.Errorf("failed to check existing network DNS fault: '%s' command failed with the following error: '%s'. std output: '%s'. TaskArn: %s",
+				cmdString, err, string(cmdOutput), taskMetadata.TaskARN)
+		}
+		for _, rule := range strings.Split(string(cmdOutput), "\n") {
+			for _, action := range dnsFaultActions {
+				if dnsFaultTable(action) == table &&
+					strings.TrimSpace(rule) == fmt.Sprintf("-A OUTPUT -j %s", dnsFaultChainName(action)) {
+					return action, nil
+				}
+			}
+		}
+	}
+	return "", nil
+}
+
+// runDNSFaultCommand runs the iptables command within the task network namespace. To be backwards compatible,
+// failures of IPv6 table updates are ignored unless the task is IPv6 only, like for network blackhole port
+// faults.
+func (h *FaultHandler) runDNSFaultCommand(ctx context.Context, taskMetadata *state.TaskResponse, cmdString string,
+	isIP6TableUpdate bool) error {
+	netNs := taskMetadata.TaskNetworkConfig.NetworkNamespaces[0].Path
+	cmdString = fmt.Sprintf(nsenterCommandString, netNs) + cmdString
+	cmdOutput, err := h.runExecCommand(ctx, strings.Split(cmdString, " "))
+	if err != nil {
+		if isIP6TableUpdate && !isIPv6OnlyTask(taskMetadata) {
+			logger.Warn("Ignore the failure for IPv6 table updates for non IPv6 only tasks", logger.Fields{
+				"netns":       netNs,
+				"command":     cmdString,
+				"output":      string(cmdOutput),
+				field.TaskARN: taskMetadata.TaskARN,
+				field.Error:   err,
+			})
+			return nil
+		}
+		logger.Error("Unable to execute the command", logger.Fields{
+			"netns":       netNs,
+			"command":     cmdString,
+			"output":      string(cmdOutput),
+			field.TaskARN: taskMetadata.TaskARN,
+			field.Error:   err,
+		})
+		return fmt.Errorf("'%s' command failed with the following error: '%s'. std output: '%s'",
+			cmdString, err, string(cmdOutput))
+	}
+	return nil
+}
+
+// dnsErrorResponse returns the response to the DNS query with the given response code and no records.
+func dnsErrorResponse(query []byte, rcode byte) ([]byte, error) {
+	if len(query) < dnsHeaderLength {
+		return nil, errors.New("DNS query is shorter than its header")
+	}
+	// Skip the names and the types and classes of the questions to only answer them.
+	offset := dnsHeaderLength
+	for i := 0; i < int(binary.BigEndian.Uint16(query[4:6])); i++ {
+		for {
+			if offset >= len(query) {
+				return nil, errors.New("DNS query question is truncated")
+			}
+			length := int(query[offset])
+			if length&0xc0 != 0 {
+				return nil, errors.New("DNS query question name is compressed")
+			}
+			offset++
+			if length == 0 {
+				break
+			}
+			offset += length
+		}
+		offset += 4
+		if offset > len(query) {
+			return nil, errors.New("DNS query question is truncated")
+		}
+	}
+	response := make([]byte, offset)
+	copy(response, query[:offset])
+	// Keep the ID, the opcode and the recursion desired bit, and set the response and recursion available
+	// bits as well as the response code.
+	response[2] = 0x80 | query[2]&0x79
+	response[3] = 0x80 | rcode&0x0f
+	// Only the questions are in the response.
+	binary.BigEndian.PutUint16(response[6:8], 0)
+	binary.BigEndian.PutUint16(response[8:10], 0)
+	binary.BigEndian.PutUint16(response[10:12], 0)
+	return response, nil
+}
@@ -0,0 +1,233 @@
+//go:build linux
+// +build linux
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package handlers
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/types"
+
+	cnins "github.com/containernetworking/plugins/pkg/ns"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// dnsForwardTimeout is how long the DNS responder waits for the resolver to answer a delayed query.
+	dnsForwardTimeout = 5 * time.Second
+	maxDNSMessageSize = 65535
+)
+
+// dnsResponder answers the DNS queries redirected by network DNS faults with one UDP server per network
+// namespace. The sockets of a server are created within the network namespace, so they keep sending and
+// receiving packets there even though the server runs in the agent network namespace.
+type dnsResponder struct {
+	servers     map[string]*dnsServer
+	serversLock sync.Mutex
+}
+
+type dnsServer struct {
+	netNS    cnins.NetNS
+	conn     net.PacketConn
+	action   string
+	delay    time.Duration
+	resolver string
+	done     chan struct{}
+}
+
+func newDNSResponder() DNSResponder {
+	return &dnsResponder{
+		servers: make(map[string]*dnsServer),
+	}
+}
+
+func (r *dnsResponder) Start(netNSPath, action string, delay time.Duration, resolver string) (uint16, error) {
+	r.serversLock.Lock()
+	defer r.serversLock.Unlock()
+	if _, ok := r.servers[netNSPath]; ok {
+		return 0, fmt.Errorf("DNS responder is already running in network namespace %s", netNSPath)
+	}
+
+	netNS, err := cnins.GetNS(netNSPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open network namespace %s: %w", netNSPath, err)
+	}
+	var conn net.PacketConn
+	err = netNS.Do(func(cnins.NetNS) error {
+		// The redirected IPv4 and IPv6 queries are received on the loopback addresses.
+		var listenErr error
+		conn, listenErr = net.ListenPacket("udp", ":0")
+		return listenErr
+	})
+	if err != nil {
+		netNS.Close()
+		return 0, fmt.Errorf("failed to listen in network namespace %s: %w", netNSPath, err)
+	}
+
+	server := &dnsServer{
+		netNS:    netNS,
+		conn:     conn,
+		action:   action,
+		delay:    delay,
+		resolver: resolver,
+		done:     make(chan struct{}),
+	}
+	r.servers[netNSPath] = server
+	go server.serve(netNSPath)
+	return uint16(conn.LocalAddr().(*net.UDPAddr).Port), nil
+}
+
+func (r *dnsResponder) Stop(netNSPath string) error {
+	r.serversLock.Lock()
+	server, ok := r.servers[netNSPath]
+	delete(r.servers, netNSPath)
+	r.serversLock.Unlock()
+	if !ok {
+		return nil
+	}
+	close(server.done)
+	err := server.conn.Close()
+	if closeErr := server.netNS.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (r *dnsResponder) IsRunning(netNSPath string) bool {
+	r.serversLock.Lock()
+	defer r.serversLock.Unlock()
+	_, ok := r.servers[netNSPath]
+	return ok
+}
+
+// serve answers the queries received by the server until it is stopped.
+func (s *dnsServer) serve(netNSPath string) {
+	buf := make([]byte, maxDNSMessageSize)
+	for {
+		n, client, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			select {
+			case <-s.done:
+			default:
+				logger.Error("DNS responder stopped unexpectedly", logger.Fields{
+					"netns":     netNSPath,
+					field.Error: err,
+				})
+			}
+			return
+		}
+		query := make([]byte, n)
+		copy(query, buf[:n])
+		switch s.action {
+		case types.DNSActionDelay:
+			go s.forward(netNSPath, query, client)
+		case types.DNSActionNXDomain:
+			s.respond(netNSPath, query, client, dnsRcodeNXDomain)
+		default:
+			s.respond(netNSPath, query, client, dnsRcodeServFail)
+		}
+	}
+}
+
+// respond answers the query with the response code.
+func (s *dnsServer) respond(netNSPath string, query []byte, client net.Addr, rcode byte) {
+	response, err := dnsErrorResponse(query, rcode)
+	if err != nil {
+		logger.Warn("DNS responder ignored invalid query", logger.Fields{
+			"netns":     netNSPath,
+			field.Error: err,
+		})
+		return
+	}
+	if _, err := s.conn.WriteTo(response, client); err != nil {
+		logger.Warn("DNS responder failed to answer query", logger.Fields{
+			"netns":     netNSPath,
+			field.Error: err,
+		})
+	}
+}
+
+// forward sends the query to the resolver once delayed and relays its response back.
+func (s *dnsServer) forward(netNSPath string, query []byte, client net.Addr) {
+	timer := time.NewTimer(s.delay)
+	defer timer.Stop()
+	select {
+	case <-s.done:
+		return
+	case <-timer.C:
+	}
+
+	response, err := s.exchange(query)
+	if err != nil {
+		logger.Warn("DNS responder failed to forward delayed query", logger.Fields{
+			"netns":     netNSPath,
+			"resolver":  s.resolver,
+			field.Error: err,
+		})
+		return
+	}
+	if _, err := s.conn.WriteTo(response, client); err != nil {
+		logger.Warn("DNS responder failed to answer query", logger.Fields{
+			"netns":     netNSPath,
+			field.Error: err,
+		})
+	}
+}
+
+// exchange sends the query to the resolver from within the network namespace and returns its response. The
+// query is marked so that the fault doesn't redirect it back to the server.
+func (s *dnsServer) exchange(query []byte) ([]byte, error) {
+	dialer := net.Dialer{
+		Timeout: dnsForwardTimeout,
+		Control: func(network, address string, c syscall.RawConn) error {
+			var markErr error
+			if err := c.Control(func(fd uintptr) {
+				markErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_MARK, dnsResponderMark)
+			}); err != nil {
+				return err
+			}
+			return markErr
+		},
+	}
+	var conn net.Conn
+	err := s.netNS.Do(func(cnins.NetNS) error {
+		var dialErr error
+		conn, dialErr = dialer.Dial("udp", net.JoinHostPort(s.resolver, "53"))
+		return dialErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(dnsForwardTimeout)); err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, maxDNSMessageSize)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf[:n], nil
+}
@@ -0,0 +1,41 @@
+//go:build !linux
+// +build !linux
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package handlers
+
+import (
+	"errors"
+	"time"
+)
+
+// dnsResponder is not supported since network DNS faults rely on Linux network namespaces.
+type dnsResponder struct{}
+
+func newDNSResponder() DNSResponder {
+	return &dnsResponder{}
+}
+
+func (*dnsResponder) Start(string, string, time.Duration, string) (uint16, error) {
+	return 0, errors.New("DNS responder is not supported on this platform")
+}
+
+func (*dnsResponder) Stop(string) error {
+	return nil
+}
+
+func (*dnsResponder) IsRunning(string) bool {
+	return false
+}
@@ -0,0 +1,398 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mock_metrics "github.com/aws/amazon-ecs-agent/ecs-agent/metrics/mocks"
+	mock_handlers "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/handlers/mocks"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/types"
+	state "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/v4/state"
+	mock_state "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/v4/state/mocks"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/utils/netconfig"
+	mock_execwrapper "github.com/aws/amazon-ecs-agent/ecs-agent/utils/execwrapper/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	dnsResponderPort          = 5353
+	iptablesOutputChainPolicy = "-P OUTPUT ACCEPT\n"
+	// example.com in the wire format of DNS names
+	exampleDNSName = "076578616d706c6503636f6d00"
+)
+
+var happyNetworkDNSReqBody = map[string]interface{}{
+	"Domains": []string{"*.example.com"},
+	"Action":  types.DNSActionNXDomain,
+}
+
+// expectDNSFaultCheck expects the OUTPUT chains of the filter and nat tables to be listed. The nat table isn't
+// expected to be listed if natOutput is empty.
+func expectDNSFaultCheck(exec *mock_execwrapper.MockExec, mockCMD *mock_execwrapper.MockCmd, filterOutput,
+	natOutput string) []*gomock.Call {
+	var expectations []*gomock.Call
+	for _, tc := range []struct{ table, output string }{
+		{iptablesFilterTable, filterOutput},
+		{iptablesNATTable, natOutput},
+	} {
+		if tc.output == "" {
+			continue
+		}
+		expectations = append(expectations,
+			exec.EXPECT().CommandContext(gomock.Any(), "nsenter",
+				[]string{"--net=" + nspath, "iptables", "-w", "5", "-t", tc.table, "-S", "OUTPUT"}).Return(mockCMD),
+			mockCMD.EXPECT().CombinedOutput().Return([]byte(tc.output), nil),
+		)
+	}
+	return expectations
+}
+
+// dnsFaultStartCommands returns the commands starting a network DNS fault for example.com.
+func dnsFaultStartCommands(action string) []string {
+	var commands []string
+	table := dnsFaultTable(action)
+	chain := dnsFaultChainName(action)
+	for _, tool := range []string{"iptables", "ip6tables"} {
+		commands = append(commands, fmt.Sprintf("%s -w 5 -t %s -N %s", tool, table, chain))
+		if action == types.DNSActionBlackhole {
+			for _, protocol := range []string{"udp", "tcp"} {
+				commands = append(commands, fmt.Sprintf(
+					"%s -w 5 -t filter -A %s -p %s --dport 53 -m string --algo bm --icase --hex-string |%s| -j DROP",
+					tool, chain, protocol, exampleDNSName))
+			}
+		} else {
+			commands = append(commands,
+				fmt.Sprintf("%s -w 5 -t nat -A %s -m mark --mark %d -j RETURN", tool, chain, dnsResponderMark),
+				fmt.Sprintf(
+					"%s -w 5 -t nat -A %s -p udp --dport 53 -m string --algo bm --icase --hex-string |%s| -j REDIRECT --to-ports %d",
+					tool, chain, exampleDNSName, dnsResponderPort))
+		}
+		commands = append(commands, fmt.Sprintf("%s -w 5 -t %s -I OUTPUT -j %s", tool, table, chain))
+	}
+	return commands
+}
+
+// dnsFaultStopCommands returns the commands stopping a network DNS fault.
+func dnsFaultStopCommands(action string) []string {
+	var commands []string
+	table := dnsFaultTable(action)
+	chain := dnsFaultChainName(action)
+	for _, tool := range []string{"iptables", "ip6tables"} {
+		commands = append(commands,
+			fmt.Sprintf("%s -w 5 -t %s -D OUTPUT -j %s", tool, table, chain),
+			fmt.Sprintf("%s -w 5 -t %s -F %s", tool, table, chain),
+			fmt.Sprintf("%s -w 5 -t %s -X %s", tool, table, chain),
+		)
+	}
+	return commands
+}
+
+func TestNetworkDNSFault(t *testing.T) {
+	nsenterPrefix := fmt.Sprintf(nsenterCommandString, nspath)
+	tcs := []struct {
+		name                 string
+		operation            string
+		taskResponse         state.TaskResponse
+		requestBody          interface{}
+		setExpectations      func(*mock_execwrapper.MockExec, *mock_execwrapper.MockCmd, *mock_handlers.MockDNSResponder)
+		expectedStatusCode   int
+		expectedResponseJSON string
+	}{
+		{
+			name:         "start blackhole",
+			operation:    types.StartNetworkFaultPostfix,
+			taskResponse: happyTaskResponse,
+			requestBody: map[string]interface{}{
+				"Domains": []string{"example.com"},
+				"Action":  types.DNSActionBlackhole,
+			},
+			setExpectations: func(exec *mock_execwrapper.MockExec, mockCMD *mock_execwrapper.MockCmd,
+				_ *mock_handlers.MockDNSResponder) {
+				expectations := expectDNSFaultCheck(exec, mockCMD, iptablesOutputChainPolicy, iptablesOutputChainPolicy)
+				expectations = addExpectedCommandsWithPrefix(exec, nsenterPrefix,
+					dnsFaultStartCommands(types.DNSActionBlackhole), expectations, mockCMD)
+				gomock.InOrder(expectations...)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseJSON: happyFaultRunningResponse,
+		},
+		{
+			name:         "start nxdomain",
+			operation:    types.StartNetworkFaultPostfix,
+			taskResponse: happyTaskResponse,
+			requestBody:  happyNetworkDNSReqBody,
+			setExpectations: func(exec *mock_execwrapper.MockExec, mockCMD *mock_execwrapper.MockCmd,
+				responder *mock_handlers.MockDNSResponder) {
+				expectations := expectDNSFaultCheck(exec, mockCMD, iptablesOutputChainPolicy, iptablesOutputChainPolicy)
+				expectations = append(expectations, responder.EXPECT().
+					Start(nspath, types.DNSActionNXDomain, time.Duration(0), defaultDNSResolver).
+					Return(uint16(dnsResponderPort), nil))
+				expectations = addExpectedCommandsWithPrefix(exec, nsenterPrefix,
+					dnsFaultStartCommands(types.DNSActionNXDomain), expectations, mockCMD)
+				gomock.InOrder(expectations...)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseJSON: happyFaultRunningResponse,
+		},
+		{
+			name:         "start delay",
+			operation:    types.StartNetworkFaultPostfix,
+			taskResponse: happyTaskResponse,
+			requestBody: map[string]interface{}{
+				"Domains":           []string{"example.com."},
+				"Action":            types.DNSActionDelay,
+				"DelayMilliseconds": 1500,
+				"Resolver":          "10.0.0.2",
+			},
+			setExpectations: func(exec *mock_execwrapper.MockExec, mockCMD *mock_execwrapper.MockCmd,
+				responder *mock_handlers.MockDNSResponder) {
+				expectations := expectDNSFaultCheck(exec, mockCMD, iptablesOutputChainPolicy, iptablesOutputChainPolicy)
+				expectations = append(expectations, responder.EXPECT().
+					Start(nspath, types.DNSActionDelay, 1500*time.Millisecond, "10.0.0.2").
+					Return(uint16(dnsResponderPort), nil))
+				expectations = addExpectedCommandsWithPrefix(exec, nsenterPrefix,
+					dnsFaultStartCommands(types.DNSActionDelay), expectations, mockCMD)
+				gomock.InOrder(expectations...)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseJSON: happyFaultRunningResponse,
+		},
+		{
+			name:         "start existing fault",
+			operation:    types.StartNetworkFaultPostfix,
+			taskResponse: happyTaskResponse,
+			requestBody:  happyNetworkDNSReqBody,
+			setExpectations: func(exec *mock_execwrapper.MockExec, mockCMD *mock_execwrapper.MockCmd,
+				_ *mock_handlers.MockDNSResponder) {
+				gomock.InOrder(expectDNSFaultCheck(exec, mockCMD,
+					iptablesOutputChainPolicy+"-A OUTPUT -j dns-blackhole\n", "")...)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponseJSON: fmt.Sprintf(errorResponse,
+				"There is already one network dns fault running"),
+		},
+		{
+			name:         "start responder error",
+			operation:    types.StartNetworkFaultPostfix,
+			taskResponse: happyTaskResponse,
+			requestBody:  happyNetworkDNSReqBody,
+			setExpectations: func(exec *mock_execwrapper.MockExec, mockCMD *mock_execwrapper.MockCmd,
+				responder *mock_handlers.MockDNSResponder) {
+				gomock.InOrder(expectDNSFaultCheck(exec, mockCMD, iptablesOutputChainPolicy,
+					iptablesOutputChainPolicy)...)
+				responder.EXPECT().Start(nspath, types.DNSActionNXDomain, gomock.Any(), gomock.Any()).
+					Return(uint16(0), errors.New("error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseJSON: fmt.Sprintf(errorResponse, internalError),
+		},
+		{
+			name:         "start iptables error",
+			operation:    types.StartNetworkFaultPostfix,
+			taskResponse: happyTaskResponse,
+			requestBody:  happyNetworkDNSReqBody,
+			setExpectations: func(exec *mock_execwrapper.MockExec, mockCMD *mock_execwrapper.MockCmd,
+				responder *mock_handlers.MockDNSResponder) {
+				gomock.InOrder(append(expectDNSFaultCheck(exec, mockCMD, iptablesOutputChainPolicy,
+					iptablesOutputChainPolicy),
+					responder.EXPECT().Start(nspath, types.DNSActionNXDomain, gomock.Any(), gomock.Any()).
+						Return(uint16(dnsResponderPort), nil),
+					exec.EXPECT().CommandContext(gomock.Any(), "nsenter", gomock.Any()).Return(mockCMD),
+					mockCMD.EXPECT().CombinedOutput().Return([]byte("iptables: Chain already exists."),
+						errors.New("exit status 1")),
+					responder.EXPECT().Stop(nspath).Return(nil),
+				)...)
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseJSON: fmt.Sprintf(errorResponse, internalError),
+		},
+		{
+			name:               "start host mode",
+			operation:          types.StartNetworkFaultPostfix,
+			taskResponse:       happyTaskResponseTwoInterfaces,
+			requestBody:        happyNetworkDNSReqBody,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponseJSON: fmt.Sprintf(errorResponse,
+				"network-dns faults are only supported for tasks in awsvpc network mode"),
+		},
+		{
+			name:         "start invalid request",
+			operation:    types.StartNetworkFaultPostfix,
+			taskResponse: happyTaskResponse,
+			requestBody: map[string]interface{}{
+				"Domains": []string{"example.com"},
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseJSON: fmt.Sprintf(errorResponse, "required parameter Action is missing"),
+		},
+		{
+			name:         "stop existing fault",
+			operation:    types.StopNetworkFaultPostfix,
+			taskResponse: happyTaskResponse,
+			setExpectations: func(exec *mock_execwrapper.MockExec, mockCMD *mock_execwrapper.MockCmd,
+				responder *mock_handlers.MockDNSResponder) {
+				expectations := expectDNSFaultCheck(exec, mockCMD, iptablesOutputChainPolicy,
+					iptablesOutputChainPolicy+"-A OUTPUT -j dns-servfail\n")
+				expectations = addExpectedCommandsWithPrefix(exec, nsenterPrefix,
+					dnsFaultStopCommands(types.DNSActionServFail), expectations, mockCMD)
+				expectations = append(expectations, responder.EXPECT().Stop(nspath).Return(nil))
+				gomock.InOrder(expectations...)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseJSON: happyFaultStoppedResponse,
+		},
+		{
+			name:         "stop no existing fault",
+			operation:    types.StopNetworkFaultPostfix,
+			taskResponse: happyTaskResponse,
+			setExpectations: func(exec *mock_execwrapper.MockExec, mockCMD *mock_execwrapper.MockCmd,
+				_ *mock_handlers.MockDNSResponder) {
+				gomock.InOrder(expectDNSFaultCheck(exec, mockCMD, iptablesOutputChainPolicy,
+					iptablesOutputChainPolicy)...)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseJSON: happyFaultStoppedResponse,
+		},
+		{
+			name:         "check existing fault",
+			operation:    types.CheckNetworkFaultPostfix,
+			taskResponse: happyTaskResponse,
+			setExpectations: func(exec *mock_execwrapper.MockExec, mockCMD *mock_execwrapper.MockCmd,
+				_ *mock_handlers.MockDNSResponder) {
+				gomock.InOrder(expectDNSFaultCheck(exec, mockCMD, iptablesOutputChainPolicy,
+					iptablesOutputChainPolicy+"-A OUTPUT -j dns-delay\n")...)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseJSON: happyFaultRunningResponse,
+		},
+		{
+			name:         "check no existing fault",
+			operation:    types.CheckNetworkFaultPostfix,
+			taskResponse: happyTaskResponse,
+			setExpectations: func(exec *mock_execwrapper.MockExec, mockCMD *mock_execwrapper.MockCmd,
+				_ *mock_handlers.MockDNSResponder) {
+				gomock.InOrder(expectDNSFaultCheck(exec, mockCMD, iptablesOutputChainPolicy,
+					iptablesOutputChainPolicy)...)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseJSON: happyFaultNotRunningResponse,
+		},
+		{
+			name:         "check command error",
+			operation:    types.CheckNetworkFaultPostfix,
+			taskResponse: happyTaskResponse,
+			setExpectations: func(exec *mock_execwrapper.MockExec, mockCMD *mock_execwrapper.MockCmd,
+				_ *mock_handlers.MockDNSResponder) {
+				exec.EXPECT().CommandContext(gomock.Any(), "nsenter", gomock.Any()).Return(mockCMD)
+				mockCMD.EXPECT().CombinedOutput().Return([]byte(""), errors.New("error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseJSON: fmt.Sprintf(errorResponse, internalError),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			agentState := mock_state.NewMockAgentState(ctrl)
+			mockExec := mock_execwrapper.NewMockExec(ctrl)
+			mockCMD := mock_execwrapper.NewMockCmd(ctrl)
+			mockResponder := mock_handlers.NewMockDNSResponder(ctrl)
+			handler := New(agentState, mock_metrics.NewMockEntryFactory(ctrl), mockExec)
+			handler.dnsResponder = mockResponder
+
+			agentState.EXPECT().GetTaskMetadataWithTaskNetworkConfig(endpointId, netconfig.NewNetworkConfigClient()).
+				Return(tc.taskResponse, nil).MaxTimes(1)
+			if tc.setExpectations != nil {
+				ctx, cancel := context.WithTimeout(context.Background(), ctxTimeoutDuration)
+				mockExec.EXPECT().NewExecContextWithTimeout(gomock.Any(), gomock.Any()).Return(ctx, cancel)
+				tc.setExpectations(mockExec, mockCMD, mockResponder)
+			}
+
+			var handleMethod func(http.ResponseWriter, *http.Request)
+			switch tc.operation {
+			case types.StartNetworkFaultPostfix:
+				handleMethod = handler.StartNetworkDNS()
+			case types.StopNetworkFaultPostfix:
+				handleMethod = handler.StopNetworkDNS()
+			default:
+				handleMethod = handler.CheckNetworkDNS()
+			}
+			router := mux.NewRouter()
+			router.HandleFunc(NetworkFaultPath(types.DNSFaultType, tc.operation), handleMethod).
+				Methods(http.MethodPost)
+
+			var requestBody []byte
+			if tc.requestBody != nil {
+				var err error
+				requestBody, err = json.Marshal(tc.requestBody)
+				require.NoError(t, err)
+			}
+			req, err := http.NewRequest(http.MethodPost,
+				fmt.Sprintf("/api/%s/fault/v1/%s/%s", endpointId, types.DNSFaultType, tc.operation),
+				bytes.NewReader(requestBody))
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tc.expectedStatusCode, recorder.Code)
+			assert.Equal(t, tc.expectedResponseJSON, recorder.Body.String())
+		})
+	}
+}
+
+func TestEncodeDNSName(t *testing.T) {
+	assert.Equal(t, exampleDNSName, encodeDNSName("example.com"))
+	assert.Equal(t, exampleDNSName, encodeDNSName("*.Example.COM."))
+	assert.Equal(t, "0361777303636f6d00", encodeDNSName("aws.com"))
+}
+
+func TestDNSErrorResponse(t *testing.T) {
+	// A recursive query for the A record of example.com with an EDNS OPT record.
+	question := []byte{0x07, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 0x03, 'c', 'o', 'm', 0x00, 0x00, 0x01, 0x00, 0x01}
+	query := append([]byte{0xab, 0xcd, 0x01, 0x20, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}, question...)
+	query = append(query, 0x00, 0x00, 0x29, 0x10, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00)
+
+	response, err := dnsErrorResponse(query, dnsRcodeNXDomain)
+	require.NoError(t, err)
+	expected := append([]byte{0xab, 0xcd, 0x81, 0x83, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, question...)
+	assert.Equal(t, expected, response)
+
+	_, err = dnsErrorResponse(query[:10], dnsRcodeServFail)
+	assert.Error(t, err)
+	_, err = dnsErrorResponse(query[:20], dnsRcodeServFail)
+	assert.Error(t, err)
+	compressed := append([]byte{}, query[:12]...)
+	compressed = append(compressed, 0xc0, 0x0c, 0x00, 0x01, 0x00, 0x01)
+	_, err = dnsErrorResponse(compressed, dnsRcodeServFail)
+	assert.Error(t, err)
+}
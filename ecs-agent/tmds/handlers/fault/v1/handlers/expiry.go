@@ -0,0 +1,134 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/audit"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/types"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/utils/ttime"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// faultExpiry tracks the timer that automatically stops a fault started with a duration.
+type faultExpiry struct {
+	timer     ttime.Timer
+	expiresAt time.Time
+}
+
+// scheduleFaultExpiry arranges for the fault to be stopped once its expiry time is reached. Any expiry
+// previously scheduled for the same fault is cancelled, so that the most recent start request determines the
+// lifetime of the fault. Faults without an expiry time are not scheduled.
+//
+// The caller is expected to hold the lock of the fault, see faultLockKey.
+func (h *FaultHandler) scheduleFaultExpiry(fault *types.InjectedFault) {
+	h.cancelFaultExpiry(fault.ID)
+	if fault.ExpiresAt == nil {
+		return
+	}
+
+	expiry := &faultExpiry{expiresAt: *fault.ExpiresAt}
+	duration := expiry.expiresAt.Sub(h.getTime().Now())
+
+	h.expiriesLock.Lock()
+	defer h.expiriesLock.Unlock()
+	if h.expiries == nil {
+		h.expiries = make(map[string]*faultExpiry)
+	}
+	expiry.timer = h.getTime().AfterFunc(duration, func() {
+		h.expireFault(fault, expiry)
+	})
+	h.expiries[fault.ID] = expiry
+	logger.Info("Scheduled automatic stop of fault", logger.Fields{
+		field.TaskARN: fault.TaskARN,
+		"fault":       fault.ID,
+		"expiresAt":   expiry.expiresAt.Format(time.RFC3339),
+	})
+}
+
+// cancelFaultExpiry cancels the automatic stop of the fault, if any.
+func (h *FaultHandler) cancelFaultExpiry(id string) {
+	h.expiriesLock.Lock()
+	defer h.expiriesLock.Unlock()
+	if expiry, ok := h.expiries[id]; ok {
+		expiry.timer.Stop()
+		delete(h.expiries, id)
+	}
+}
+
+// faultRemainingDuration returns the number of seconds, rounded up, left before the fault is automatically
+// stopped, or nil if the fault does not expire.
+func (h *FaultHandler) faultRemainingDuration(id string) *uint64 {
+	h.expiriesLock.Lock()
+	defer h.expiriesLock.Unlock()
+	expiry, ok := h.expiries[id]
+	if !ok {
+		return nil
+	}
+	remaining := expiry.expiresAt.Sub(h.getTime().Now())
+	if remaining < 0 {
+		remaining = 0
+	}
+	return aws.Uint64(uint64(math.Ceil(remaining.Seconds())))
+}
+
+// expireFault is invoked when the duration of a fault elapses. It stops the fault unless it was stopped or
+// restarted in the meantime.
+func (h *FaultHandler) expireFault(fault *types.InjectedFault, expiry *faultExpiry) {
+	// To avoid racing with requests manipulating the same network resource
+	rwMu := h.loadLock(faultLockKey(fault))
+	rwMu.Lock()
+	defer rwMu.Unlock()
+
+	h.expiriesLock.Lock()
+	if current, ok := h.expiries[fault.ID]; !ok || current != expiry {
+		h.expiriesLock.Unlock()
+		return
+	}
+	delete(h.expiries, fault.ID)
+	h.expiriesLock.Unlock()
+
+	ctx, cancel := h.osExecWrapper.NewExecContextWithTimeout(context.Background(), requestTimeoutSeconds*time.Second)
+	defer cancel()
+	if err := h.stopInjectedFault(ctx, fault); err != nil {
+		logger.Error("Failed to automatically stop fault after its duration elapsed", logger.Fields{
+			field.TaskARN: fault.TaskARN,
+			"fault":       fault.ID,
+			field.Error:   err,
+		})
+		h.auditFault(nil, fault.TaskARN, http.StatusInternalServerError, audit.ExpireFaultEventType,
+			fault.FaultType, fault.Parameters)
+		return
+	}
+	h.auditFault(nil, fault.TaskARN, http.StatusOK, audit.ExpireFaultEventType, fault.FaultType, fault.Parameters)
+	h.forgetFault(fault.ID)
+	logger.Info("Automatically stopped fault after its duration elapsed", logger.Fields{
+		field.TaskARN: fault.TaskARN,
+		"fault":       fault.ID,
+	})
+}
+
+func (h *FaultHandler) getTime() ttime.Time {
+	if h.time == nil {
+		return &ttime.DefaultTime{}
+	}
+	return h.time
+}
@@ -0,0 +1,248 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/audit"
+	mock_audit "github.com/aws/amazon-ecs-agent/ecs-agent/logger/audit/mocks"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/audit/request"
+	mock_metrics "github.com/aws/amazon-ecs-agent/ecs-agent/metrics/mocks"
+	mock_handlers "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/handlers/mocks"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/types"
+	mock_state "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/v4/state/mocks"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/utils/netconfig"
+	mock_execwrapper "github.com/aws/amazon-ecs-agent/ecs-agent/utils/execwrapper/mocks"
+	mock_ttime "github.com/aws/amazon-ecs-agent/ecs-agent/utils/ttime/mocks"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupFaultRecordTest returns a fault handler whose clock and store are controlled by the returned mocks.
+func setupFaultRecordTest(t *testing.T) (*FaultHandler, *mock_ttime.MockTime, *mock_execwrapper.MockExec,
+	*mock_handlers.MockFaultStore, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+	mockExec := mock_execwrapper.NewMockExec(ctrl)
+	mockTime := mock_ttime.NewMockTime(ctrl)
+	mockStore := mock_handlers.NewMockFaultStore(ctrl)
+	handler := New(mock_state.NewMockAgentState(ctrl), mock_metrics.NewMockEntryFactory(ctrl), mockExec,
+		WithFaultStore(mockStore))
+	handler.time = mockTime
+	return handler, mockTime, mockExec, mockStore, ctrl
+}
+
+// newTestLatencyFault returns the record of a network latency fault injected into a host mode task.
+func newTestLatencyFault(expiresAt *time.Time) *types.InjectedFault {
+	return &types.InjectedFault{
+		ID:                faultID(nspathHost, types.LatencyFaultType),
+		TaskARN:           taskARN,
+		FaultType:         types.LatencyFaultType,
+		TaskNetworkConfig: &happyTaskNetworkConfigTwoInterfaces,
+		ExpiresAt:         expiresAt,
+	}
+}
+
+// expectAfterFunc expects a timer to be scheduled for the duration and captures the function it invokes.
+func expectAfterFunc(mockTime *mock_ttime.MockTime, timer *mock_ttime.MockTimer, d interface{},
+	expire *func()) *gomock.Call {
+	return mockTime.EXPECT().AfterFunc(d, gomock.Any()).DoAndReturn(
+		func(d time.Duration, f func()) *mock_ttime.MockTimer {
+			*expire = f
+			return timer
+		})
+}
+
+func TestScheduleFaultExpiryStopsFault(t *testing.T) {
+	handler, mockTime, mockExec, mockStore, ctrl := setupFaultRecordTest(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	expiresAt := now.Add(60 * time.Second)
+	fault := newTestLatencyFault(&expiresAt)
+	var expire func()
+	gomock.InOrder(
+		mockTime.EXPECT().Now().Return(now),
+		expectAfterFunc(mockTime, mock_ttime.NewMockTimer(ctrl), 60*time.Second, &expire),
+		mockTime.EXPECT().Now().Return(now.Add(20*time.Second+500*time.Millisecond)),
+	)
+
+	handler.scheduleFaultExpiry(fault)
+	assert.Equal(t, aws.Uint64(40), handler.faultRemainingDuration(fault.ID))
+
+	auditLogger := mock_audit.NewMockFaultAuditLogger(ctrl)
+	handler.auditLogger = auditLogger
+	setStopTCFaultExpectations(mockExec, ctrl, []string{deviceName, deviceName2}, tcLatencyFaultExistsCommandOutput)
+	auditLogger.EXPECT().LogFault(request.LogRequest{ARN: taskARN}, http.StatusOK, audit.ExpireFaultEventType,
+		types.LatencyFaultType, fault.Parameters)
+	mockStore.EXPECT().DeleteFault(fault.ID).Return(nil)
+	require.NotNil(t, expire)
+	expire()
+	assert.Nil(t, handler.faultRemainingDuration(fault.ID))
+}
+
+func TestScheduleFaultExpiryWithoutExpiry(t *testing.T) {
+	handler, _, _, _, ctrl := setupFaultRecordTest(t)
+	defer ctrl.Finish()
+
+	fault := newTestLatencyFault(nil)
+	handler.scheduleFaultExpiry(fault)
+	assert.Nil(t, handler.faultRemainingDuration(fault.ID))
+}
+
+func TestScheduleFaultExpiryLatestStartWins(t *testing.T) {
+	handler, mockTime, mockExec, mockStore, ctrl := setupFaultRecordTest(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	firstExpiresAt := now.Add(10 * time.Second)
+	secondExpiresAt := now.Add(30 * time.Second)
+	firstFault := newTestLatencyFault(&firstExpiresAt)
+	secondFault := newTestLatencyFault(&secondExpiresAt)
+
+	var firstExpire, secondExpire func()
+	firstTimer := mock_ttime.NewMockTimer(ctrl)
+	mockTime.EXPECT().Now().Return(now).AnyTimes()
+	gomock.InOrder(
+		expectAfterFunc(mockTime, firstTimer, 10*time.Second, &firstExpire),
+		firstTimer.EXPECT().Stop().Return(true),
+		expectAfterFunc(mockTime, mock_ttime.NewMockTimer(ctrl), 30*time.Second, &secondExpire),
+	)
+
+	handler.scheduleFaultExpiry(firstFault)
+	handler.scheduleFaultExpiry(secondFault)
+	assert.Equal(t, aws.Uint64(30), handler.faultRemainingDuration(secondFault.ID))
+
+	// The first timer was superseded, so firing it must not stop the fault.
+	firstExpire()
+	assert.Equal(t, aws.Uint64(30), handler.faultRemainingDuration(secondFault.ID))
+
+	setStopTCFaultExpectations(mockExec, ctrl, []string{deviceName, deviceName2}, tcLatencyFaultExistsCommandOutput)
+	mockStore.EXPECT().DeleteFault(secondFault.ID).Return(nil)
+	secondExpire()
+	assert.Nil(t, handler.faultRemainingDuration(secondFault.ID))
+}
+
+func TestCancelFaultExpiry(t *testing.T) {
+	handler, mockTime, _, _, ctrl := setupFaultRecordTest(t)
+	defer ctrl.Finish()
+
+	expiresAt := time.Now().Add(10 * time.Second)
+	fault := newTestLatencyFault(&expiresAt)
+	var expire func()
+	mockTimer := mock_ttime.NewMockTimer(ctrl)
+	mockTime.EXPECT().Now().Return(time.Now())
+	expectAfterFunc(mockTime, mockTimer, gomock.Any(), &expire)
+	mockTimer.EXPECT().Stop().Return(true)
+
+	handler.scheduleFaultExpiry(fault)
+	handler.cancelFaultExpiry(fault.ID)
+	assert.Nil(t, handler.faultRemainingDuration(fault.ID))
+
+	// A timer that fires after being cancelled is a no-op.
+	expire()
+}
+
+func TestExpireFaultStopError(t *testing.T) {
+	handler, mockTime, mockExec, _, ctrl := setupFaultRecordTest(t)
+	defer ctrl.Finish()
+
+	expiresAt := time.Now().Add(10 * time.Second)
+	fault := newTestLatencyFault(&expiresAt)
+	fault.FaultType = "unknown"
+	handler.trackFault(fault)
+
+	var expire func()
+	mockTime.EXPECT().Now().Return(time.Now())
+	expectAfterFunc(mockTime, mock_ttime.NewMockTimer(ctrl), gomock.Any(), &expire)
+	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeoutDuration)
+	mockExec.EXPECT().NewExecContextWithTimeout(gomock.Any(), gomock.Any()).Return(ctx, cancel)
+
+	handler.scheduleFaultExpiry(fault)
+	expire()
+	assert.Nil(t, handler.faultRemainingDuration(fault.ID))
+	// The fault is still tracked so that it gets cleaned up with the task.
+	assert.Len(t, handler.taskFaults(taskARN), 1)
+}
+
+// TestStartNetworkLatencyWithDuration verifies that a network latency fault started with a duration
+// is recorded, reports its remaining time and is stopped once the duration elapses.
+func TestStartNetworkLatencyWithDuration(t *testing.T) {
+	handler, mockTime, mockExec, mockStore, ctrl := setupFaultRecordTest(t)
+	defer ctrl.Finish()
+	agentState := mock_state.NewMockAgentState(ctrl)
+	handler.AgentState = agentState
+
+	networkConfigClient := netconfig.NewNetworkConfigClient()
+	agentState.EXPECT().GetTaskMetadataWithTaskNetworkConfig(endpointId, networkConfigClient).
+		Return(happyTaskResponseTwoInterfaces, nil)
+	setStartTCFaultExpectations(mockExec, ctrl, []string{deviceName, deviceName2},
+		fmt.Sprintf(netemLatencyArgumentsString, delayMilliseconds, jitterMilliseconds),
+		ipSourcesToFilter, ipSources, flowsPercent, noNsenterPrefix)
+
+	now := time.Now()
+	var expire func()
+	mockTime.EXPECT().Now().Return(now).AnyTimes()
+	expectAfterFunc(mockTime, mock_ttime.NewMockTimer(ctrl), 120*time.Second, &expire)
+	mockStore.EXPECT().SaveFault(gomock.Any()).Do(func(fault *types.InjectedFault) {
+		assert.Equal(t, faultID(nspathHost, types.LatencyFaultType), fault.ID)
+		assert.Equal(t, taskARN, fault.TaskARN)
+		assert.Equal(t, types.LatencyFaultType, fault.FaultType)
+		assert.Equal(t, now.Add(120*time.Second), aws.ToTime(fault.ExpiresAt))
+	}).Return(nil)
+
+	requestBody := map[string]interface{}{
+		"DelayMilliseconds":  delayMilliseconds,
+		"JitterMilliseconds": jitterMilliseconds,
+		"Sources":            ipSources,
+		"SourcesToFilter":    ipSourcesToFilter,
+		"DurationSeconds":    120,
+	}
+	reqBodyBytes, err := json.Marshal(requestBody)
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/%s/fault/v1/network-latency/start", endpointId),
+		bytes.NewReader(reqBodyBytes))
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	router.HandleFunc(NetworkFaultPath(types.LatencyFaultType, types.StartNetworkFaultPostfix),
+		handler.StartNetworkLatency()).Methods(http.MethodPost)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, `{"Status":"running","RemainingDurationSeconds":120}`, recorder.Body.String())
+	assert.Len(t, handler.taskFaults(taskARN), 1)
+
+	setStopTCFaultExpectations(mockExec, ctrl, []string{deviceName, deviceName2}, tcLatencyFaultExistsCommandOutput)
+	mockStore.EXPECT().DeleteFault(faultID(nspathHost, types.LatencyFaultType)).Return(nil)
+	require.NotNil(t, expire)
+	expire()
+	assert.Nil(t, handler.faultRemainingDuration(faultID(nspathHost, types.LatencyFaultType)))
+	assert.Empty(t, handler.taskFaults(taskARN))
+}
@@ -0,0 +1,16 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:generate mockgen -build_flags=--mod=mod -destination=mocks/handlers_mocks.go -copyright_file=../../../../../../scripts/copyright_file github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/handlers FaultStore,DNSResponder,ResourceStressor
+
+package handlers
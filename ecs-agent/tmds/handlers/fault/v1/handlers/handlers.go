@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/audit"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/metrics"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds"
@@ -37,6 +38,7 @@ import (
 	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/utils"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/utils/netconfig"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/utils/execwrapper"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/utils/ttime"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
@@ -49,12 +51,11 @@ const (
 	stopFaultRequestType        = "stop %s"
 	checkStatusFaultRequestType = "check status %s"
 	// Fault injection operation error messages
-	internalError                      = "internal error"
-	invalidNetworkModeError            = "%s mode is not supported. Please use either host or awsvpc mode."
-	faultInjectionEnabledError         = "enableFaultInjection is not enabled for task: %s"
-	requestTimedOutError               = "%s: request timed out"
-	latencyFaultAlreadyRunningError    = "There is already one network latency fault running"
-	packetLossFaultAlreadyRunningError = "There is already one network packet loss fault running"
+	internalError                  = "internal error"
+	invalidNetworkModeError        = "%s mode is not supported. Please use either host or awsvpc mode."
+	faultInjectionEnabledError     = "enableFaultInjection is not enabled for task: %s"
+	requestTimedOutError           = "%s: request timed out"
+	faultAlreadyRunningErrorFormat = "There is already one %s fault running"
 	// This is our initial assumption of how much time it would take for the Linux commands used to inject faults
 	// to finish. This will be confirmed/updated after more testing.
 	requestTimeoutSeconds = 5
@@ -69,14 +70,19 @@ const (
 	iptablesDeleteFromTableCmd = "%s -w %d -D %s -j %s"
 	iptablesDeleteChainCmd     = "%s -w %d -X %s"
 	nsenterCommandString       = "nsenter --net=%s "
-	// netem is added to upto 100 bands but always to at least 1 (otherwise no fault is injected) so checking the first band only
-	tcCheckInjectionCommandString    = "tc -j q show dev %s parent 100:1"
+	// netem is added to upto 100 bands but always to at least 1 (otherwise no fault is injected), or to the first root band
+	// for aggregate faults, so only the netem qdiscs of the first flow band and of the first root band are checked
+	tcCheckInjectionCommandString    = "tc -j q show dev %s"
 	tcAddQdiscRootCommandString      = "tc qdisc add dev %s root handle 1: prio priomap 2 2 2 2 2 2 2 2 2 2 2 2 2 2 2 2"
 	tcAddQdiscChildCommandString     = "tc qdisc add dev %s parent %s handle %s prio bands 10 priomap 2 2 2 2 2 2 2 2 2 2 2 2 2 2 2 2"
 	tcAddQdiscNetemFaultBaseString   = "tc qdisc add dev %s parent %s handle %s netem %s"
 	tcLayerBands                     = 10
 	netemLatencyArgumentsString      = "delay %dms %dms"
 	netemLossArgumentsString         = "loss %d%%"
+	netemRateArgumentsString         = "rate %dkbit"
+	netemCorruptArgumentsString      = "corrupt %d%%"
+	netemDuplicateArgumentsString    = "duplicate %d%%"
+	netemReorderArgumentsString      = "delay %dms reorder %d%%"
 	tcAllowlistIPCommandString       = "tc filter add dev %s protocol all parent 1:0 prio 1 u32 match %s dst %s flowid 1:3"
 	tcAddFilterForIPCommandString    = "tc filter add dev %s protocol all parent 1:0 prio 2 u32 match %s dst %s flowid 1:1"
 	tcAddFlowHashFilterCommandString = "tc filter add dev %s protocol all parent %d: handle %d prio 2 flow hash keys src,dst,proto,proto-src,proto-dst divisor 10 baseclass %s"
@@ -94,21 +100,77 @@ const (
 
 type FaultHandler struct {
 	// mutexMap is used to avoid multiple clients to manipulate same resource at same
-	// time. The 'key' is the the network namespace path and 'value' is the RWMutex.
+	// time. The 'key' is the the network namespace path, or the task ARN for the faults scoped to the task
+	// cgroup, and 'value' is the RWMutex.
 	// Using concurrent map here because the handler is shared by all requests.
 	mutexMap       sync.Map
 	AgentState     state.AgentState
 	MetricsFactory metrics.EntryFactory
 	osExecWrapper  execwrapper.Exec
+	// store persists the injected faults so that they can be reconciled after an agent restart.
+	// Faults are only tracked in memory if it's nil.
+	store FaultStore
+	// faults holds the injected faults and expiries holds the timers that automatically stop the
+	// faults started with a duration. Both are keyed by the ID generated via faultID.
+	faults       map[string]*types.InjectedFault
+	faultsLock   sync.Mutex
+	expiries     map[string]*faultExpiry
+	expiriesLock sync.Mutex
+	time         ttime.Time
+	// dnsResponder answers the DNS queries redirected by network DNS faults.
+	dnsResponder DNSResponder
+	// resourceStressor consumes the task cgroup resources for the CPU stress and memory pressure faults.
+	resourceStressor ResourceStressor
+	// auditLogger records the faults started and stopped. Faults aren't audited if it's nil.
+	auditLogger audit.FaultAuditLogger
 }
 
-func New(agentState state.AgentState, mf metrics.EntryFactory, execWrapper execwrapper.Exec) *FaultHandler {
-	return &FaultHandler{
+// FaultHandlerOption allows for configuration of a FaultHandler.
+type FaultHandlerOption func(*FaultHandler)
+
+// WithFaultStore is a FaultHandlerOption that configures the store the injected faults are persisted to.
+func WithFaultStore(store FaultStore) FaultHandlerOption {
+	return func(h *FaultHandler) {
+		h.store = store
+	}
+}
+
+// WithResourceStressor is a FaultHandlerOption that configures the stressor used by the CPU stress and
+// memory pressure faults. The faults can't be started without one.
+func WithResourceStressor(resourceStressor ResourceStressor) FaultHandlerOption {
+	return func(h *FaultHandler) {
+		if resourceStressor != nil {
+			h.resourceStressor = resourceStressor
+		}
+	}
+}
+
+// WithAuditLogger is a FaultHandlerOption that configures the logger the faults started and stopped are
+// audited with.
+func WithAuditLogger(auditLogger audit.FaultAuditLogger) FaultHandlerOption {
+	return func(h *FaultHandler) {
+		h.auditLogger = auditLogger
+	}
+}
+
+func New(agentState state.AgentState, mf metrics.EntryFactory, execWrapper execwrapper.Exec,
+	options ...FaultHandlerOption) *FaultHandler {
+	handler := &FaultHandler{
 		AgentState:     agentState,
 		MetricsFactory: mf,
 		mutexMap:       sync.Map{},
 		osExecWrapper:  execWrapper,
+		faults:         make(map[string]*types.InjectedFault),
+		expiries:       make(map[string]*faultExpiry),
+		time:           &ttime.DefaultTime{},
+		dnsResponder:   newDNSResponder(),
+		// The CPU stress and memory pressure faults can't be injected unless a stressor is configured
+		resourceStressor: noopResourceStressor{},
+	}
+	for _, option := range options {
+		option(handler)
 	}
+	return handler
 }
 
 // NetworkFaultPath will take in a fault type and return the TMDS endpoint path
@@ -170,11 +232,8 @@ func (h *FaultHandler) StartNetworkBlackholePort() func(http.ResponseWriter, *ht
 		taskArn := taskMetadata.TaskARN
 		stringToBeLogged := "Failed to start fault"
 		port := strconv.FormatUint(uint64(aws.ToUint16(request.Port)), 10)
-		chainName := fmt.Sprintf("%s-%s-%s", aws.ToString(request.TrafficType), aws.ToString(request.Protocol), port)
-		insertTable := "INPUT"
-		if aws.ToString(request.TrafficType) == "egress" {
-			insertTable = "OUTPUT"
-		}
+		chainName := blackholeChainName(aws.ToString(request.TrafficType), aws.ToString(request.Protocol), port)
+		insertTable := blackholeInsertTable(aws.ToString(request.TrafficType))
 
 		_, cmdErr := h.startNetworkBlackholePort(ctxWithTimeout,
 			aws.ToString(request.Protocol),
@@ -195,8 +254,17 @@ func (h *FaultHandler) StartNetworkBlackholePort() func(http.ResponseWriter, *ht
 		} else {
 			statusCode = http.StatusOK
 			responseBody = types.NewNetworkFaultInjectionSuccessResponse("running")
+			fault := h.newInjectedFault(taskMetadata, types.BlackHolePortFaultType,
+				faultID(networkNSPath, chainName), request.ToString(), request.DurationSeconds)
+			fault.Protocol = aws.ToString(request.Protocol)
+			fault.Port = port
+			fault.TrafficType = aws.ToString(request.TrafficType)
+			h.recordFault(fault)
+			responseBody.RemainingDurationSeconds = request.DurationSeconds
 			stringToBeLogged = "Successfully started fault"
 		}
+		h.auditFault(r, taskArn, statusCode, audit.StartFaultEventType, types.BlackHolePortFaultType,
+			request.ToString())
 		logger.Info(stringToBeLogged, logger.Fields{
 			field.RequestType: requestType,
 			field.Request:     request.ToString(),
@@ -382,11 +450,8 @@ func (h *FaultHandler) StopNetworkBlackHolePort() func(http.ResponseWriter, *htt
 		taskArn := taskMetadata.TaskARN
 		stringToBeLogged := "Failed to stop fault"
 		port := strconv.FormatUint(uint64(aws.ToUint16(request.Port)), 10)
-		chainName := fmt.Sprintf("%s-%s-%s", aws.ToString(request.TrafficType), aws.ToString(request.Protocol), port)
-		insertTable := "INPUT"
-		if aws.ToString(request.TrafficType) == "egress" {
-			insertTable = "OUTPUT"
-		}
+		chainName := blackholeChainName(aws.ToString(request.TrafficType), aws.ToString(request.Protocol), port)
+		insertTable := blackholeInsertTable(aws.ToString(request.TrafficType))
 
 		_, cmdErr := h.stopNetworkBlackHolePort(ctxWithTimeout,
 			aws.ToString(request.Protocol),
@@ -407,8 +472,11 @@ func (h *FaultHandler) StopNetworkBlackHolePort() func(http.ResponseWriter, *htt
 		} else {
 			statusCode = http.StatusOK
 			responseBody = types.NewNetworkFaultInjectionSuccessResponse("stopped")
+			h.forgetFault(faultID(networkNSPath, chainName))
 			stringToBeLogged = "Successfully stopped fault"
 		}
+		h.auditFault(r, taskArn, statusCode, audit.StopFaultEventType, types.BlackHolePortFaultType,
+			request.ToString())
 		logger.Info(stringToBeLogged, logger.Fields{
 			field.RequestType: requestType,
 			field.Request:     request.ToString(),
@@ -572,7 +640,7 @@ func (h *FaultHandler) CheckNetworkBlackHolePort() func(http.ResponseWriter, *ht
 		taskArn := taskMetadata.TaskARN
 		stringToBeLogged := "Failed to check fault"
 		port := strconv.FormatUint(uint64(aws.ToUint16(request.Port)), 10)
-		chainName := fmt.Sprintf("%s-%s-%s", aws.ToString(request.TrafficType), aws.ToString(request.Protocol), port)
+		chainName := blackholeChainName(aws.ToString(request.TrafficType), aws.ToString(request.Protocol), port)
 		running, _, cmdErr := h.checkNetworkBlackHolePort(ctxWithTimeout, aws.ToString(request.Protocol), port, chainName,
 			networkMode, networkNSPath, taskArn)
 
@@ -587,6 +655,7 @@ func (h *FaultHandler) CheckNetworkBlackHolePort() func(http.ResponseWriter, *ht
 			statusCode = http.StatusOK
 			if running {
 				responseBody = types.NewNetworkFaultInjectionSuccessResponse("running")
+				responseBody.RemainingDurationSeconds = h.faultRemainingDuration(faultID(networkNSPath, chainName))
 			} else {
 				responseBody = types.NewNetworkFaultInjectionSuccessResponse("not-running")
 			}
@@ -661,224 +730,121 @@ func (h *FaultHandler) checkNetworkBlackHolePort(
 
 // StartNetworkLatency starts a network latency fault in the associated ENI if no existing same fault.
 func (h *FaultHandler) StartNetworkLatency() func(http.ResponseWriter, *http.Request) {
-	return func(w http.ResponseWriter, r *http.Request) {
-		var request types.NetworkLatencyRequest
-		requestType := fmt.Sprintf(startFaultRequestType, types.LatencyFaultType)
-		// Parse the fault request
-		err := decodeRequest(w, &request, requestType, r)
-		if err != nil {
-			return
-		}
+	return h.startNetemFaultHandler(types.LatencyFaultType, func() types.NetworkFaultRequest {
+		return &types.NetworkLatencyRequest{}
+	})
+}
 
-		// Validate the fault request
-		err = validateRequest(w, request, requestType)
-		if err != nil {
-			return
-		}
+// StopNetworkLatency stops a network latency fault in the associated ENI if there is one existing same fault.
+func (h *FaultHandler) StopNetworkLatency() func(http.ResponseWriter, *http.Request) {
+	return h.stopNetemFaultHandler(types.LatencyFaultType, types.NetworkLatencyRequest{})
+}
 
-		// Obtain the task metadata via the endpoint container ID
-		taskMetadata, err := validateTaskMetadata(w, h.AgentState, requestType, r)
-		if err != nil {
-			return
-		}
+// CheckNetworkLatency checks the status of given network latency fault.
+func (h *FaultHandler) CheckNetworkLatency() func(http.ResponseWriter, *http.Request) {
+	return h.checkNetemFaultHandler(types.LatencyFaultType, types.NetworkLatencyRequest{})
+}
 
-		// To avoid multiple requests to manipulate same network resource
-		networkNSPath := taskMetadata.TaskNetworkConfig.NetworkNamespaces[0].Path
-		rwMu := h.loadLock(networkNSPath)
-		rwMu.Lock()
-		defer rwMu.Unlock()
+// StartNetworkPacketLoss starts a network packet loss fault in the associated ENI if no existing same fault.
+func (h *FaultHandler) StartNetworkPacketLoss() func(http.ResponseWriter, *http.Request) {
+	return h.startNetemFaultHandler(types.PacketLossFaultType, func() types.NetworkFaultRequest {
+		return &types.NetworkPacketLossRequest{}
+	})
+}
 
-		var responseBody types.NetworkFaultInjectionResponse
-		var httpStatusCode int
-		stringToBeLogged := "Failed to start fault"
-		// All command executions for the start network latency workflow all together should finish within 5 seconds.
-		// Thus, create the context here so that it can be shared by all os/exec calls.
-		ctx, cancel := h.osExecWrapper.NewExecContextWithTimeout(context.Background(), requestTimeoutSeconds*time.Second)
-		defer cancel()
-		// Check the status of current fault injection.
-		latencyFaultExists, packetLossFaultExists, err := h.checkTCFault(ctx, taskMetadata)
-		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
-			responseBody = types.NewNetworkFaultInjectionErrorResponse(fmt.Sprintf(requestTimedOutError, requestType))
-			httpStatusCode = http.StatusInternalServerError
-		} else if err != nil {
-			responseBody = types.NewNetworkFaultInjectionErrorResponse(internalError)
-			httpStatusCode = http.StatusInternalServerError
-		} else {
-			// If there already exists a fault in the task network namespace.
-			if latencyFaultExists {
-				responseBody = types.NewNetworkFaultInjectionErrorResponse(latencyFaultAlreadyRunningError)
-				httpStatusCode = http.StatusConflict
-			} else if packetLossFaultExists {
-				responseBody = types.NewNetworkFaultInjectionErrorResponse(packetLossFaultAlreadyRunningError)
-				httpStatusCode = http.StatusConflict
-			} else {
-				// Invoke the start fault injection functionality if not running.
-				err := h.startNetworkLatencyFault(ctx, taskMetadata, request)
-				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
-					responseBody = types.NewNetworkFaultInjectionErrorResponse(fmt.Sprintf(requestTimedOutError, requestType))
-					httpStatusCode = http.StatusInternalServerError
-				} else if err != nil {
-					responseBody = types.NewNetworkFaultInjectionErrorResponse(internalError)
-					httpStatusCode = http.StatusInternalServerError
-				} else {
-					stringToBeLogged = "Successfully started fault"
-					responseBody = types.NewNetworkFaultInjectionSuccessResponse("running")
-					httpStatusCode = http.StatusOK
-				}
-			}
-		}
-		logger.Info(stringToBeLogged, logger.Fields{
-			field.RequestType: requestType,
-			field.Request:     request.ToString(),
-			field.Response:    responseBody.ToString(),
-		})
-		handlerutils.WriteJSONResponse(
-			w,
-			httpStatusCode,
-			responseBody,
-			requestType,
-		)
-	}
+// StopNetworkPacketLoss stops a network packet loss fault in the associated ENI if there is one existing same fault.
+func (h *FaultHandler) StopNetworkPacketLoss() func(http.ResponseWriter, *http.Request) {
+	return h.stopNetemFaultHandler(types.PacketLossFaultType, types.NetworkPacketLossRequest{})
 }
 
-// StopNetworkLatency stops a network latency fault in the associated ENI if there is one existing same fault.
-func (h *FaultHandler) StopNetworkLatency() func(http.ResponseWriter, *http.Request) {
-	return func(w http.ResponseWriter, r *http.Request) {
-		var request types.NetworkLatencyRequest
-		requestType := fmt.Sprintf(stopFaultRequestType, types.LatencyFaultType)
-		logRequest(requestType, r)
+// CheckNetworkPacketLoss checks the status of given network packet loss fault.
+func (h *FaultHandler) CheckNetworkPacketLoss() func(http.ResponseWriter, *http.Request) {
+	return h.checkNetemFaultHandler(types.PacketLossFaultType, types.NetworkPacketLossRequest{})
+}
 
-		// Obtain the task metadata via the endpoint container ID
-		taskMetadata, err := validateTaskMetadata(w, h.AgentState, requestType, r)
-		if err != nil {
-			return
-		}
+// StartNetworkBandwidth starts a network bandwidth fault in the associated ENI if no existing same fault.
+func (h *FaultHandler) StartNetworkBandwidth() func(http.ResponseWriter, *http.Request) {
+	return h.startNetemFaultHandler(types.BandwidthFaultType, func() types.NetworkFaultRequest {
+		return &types.NetworkBandwidthRequest{}
+	})
+}
 
-		// To avoid multiple requests to manipulate same network resource
-		networkNSPath := taskMetadata.TaskNetworkConfig.NetworkNamespaces[0].Path
-		rwMu := h.loadLock(networkNSPath)
-		rwMu.Lock()
-		defer rwMu.Unlock()
+// StopNetworkBandwidth stops a network bandwidth fault in the associated ENI if there is one existing same fault.
+func (h *FaultHandler) StopNetworkBandwidth() func(http.ResponseWriter, *http.Request) {
+	return h.stopNetemFaultHandler(types.BandwidthFaultType, types.NetworkBandwidthRequest{})
+}
 
-		var responseBody types.NetworkFaultInjectionResponse
-		var httpStatusCode int
-		stringToBeLogged := "Failed to stop fault"
-		// All command executions for the stop network latency workflow all together should finish within 5 seconds.
-		// Thus, create the context here so that it can be shared by all os/exec calls.
-		ctx, cancel := h.osExecWrapper.NewExecContextWithTimeout(context.Background(), requestTimeoutSeconds*time.Second)
-		defer cancel()
-		// Check the status of current fault injection.
-		latencyFaultExists, _, err := h.checkTCFault(ctx, taskMetadata)
-		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
-			responseBody = types.NewNetworkFaultInjectionErrorResponse(fmt.Sprintf(requestTimedOutError, requestType))
-			httpStatusCode = http.StatusInternalServerError
-		} else if err != nil {
-			responseBody = types.NewNetworkFaultInjectionErrorResponse(internalError)
-			httpStatusCode = http.StatusInternalServerError
-		} else {
-			// If there doesn't already exist a network-latency fault
-			if !latencyFaultExists {
-				stringToBeLogged = "No fault running"
-				responseBody = types.NewNetworkFaultInjectionSuccessResponse("stopped")
-				httpStatusCode = http.StatusOK
-			} else {
-				// Invoke the stop fault injection functionality if running.
-				err := h.stopTCFault(ctx, taskMetadata)
-				if errors.Is(err, context.DeadlineExceeded) {
-					responseBody = types.NewNetworkFaultInjectionErrorResponse(fmt.Sprintf(requestTimedOutError, requestType))
-					httpStatusCode = http.StatusInternalServerError
-				} else if err != nil {
-					responseBody = types.NewNetworkFaultInjectionErrorResponse(internalError)
-					httpStatusCode = http.StatusInternalServerError
-				} else {
-					stringToBeLogged = "Successfully stopped fault"
-					responseBody = types.NewNetworkFaultInjectionSuccessResponse("stopped")
-					httpStatusCode = http.StatusOK
-				}
-			}
-		}
-		logger.Info(stringToBeLogged, logger.Fields{
-			field.RequestType: requestType,
-			field.Request:     request.ToString(),
-			field.Response:    responseBody.ToString(),
-		})
-		handlerutils.WriteJSONResponse(
-			w,
-			httpStatusCode,
-			responseBody,
-			requestType,
-		)
-	}
+// CheckNetworkBandwidth checks the status of given network bandwidth fault.
+func (h *FaultHandler) CheckNetworkBandwidth() func(http.ResponseWriter, *http.Request) {
+	return h.checkNetemFaultHandler(types.BandwidthFaultType, types.NetworkBandwidthRequest{})
 }
 
-// CheckNetworkLatency checks the status of given network latency fault.
-func (h *FaultHandler) CheckNetworkLatency() func(http.ResponseWriter, *http.Request) {
-	return func(w http.ResponseWriter, r *http.Request) {
-		var request types.NetworkLatencyRequest
-		requestType := fmt.Sprintf(checkStatusFaultRequestType, types.LatencyFaultType)
-		logRequest(requestType, r)
+// StartNetworkCorruption starts a network packet corruption fault in the associated ENI if no existing same fault.
+func (h *FaultHandler) StartNetworkCorruption() func(http.ResponseWriter, *http.Request) {
+	return h.startNetemFaultHandler(types.CorruptionFaultType, func() types.NetworkFaultRequest {
+		return &types.NetworkCorruptionRequest{}
+	})
+}
 
-		// Obtain the task metadata via the endpoint container ID
-		taskMetadata, err := validateTaskMetadata(w, h.AgentState, requestType, r)
-		if err != nil {
-			return
-		}
+// StopNetworkCorruption stops a network packet corruption fault in the associated ENI if there is one existing
+// same fault.
+func (h *FaultHandler) StopNetworkCorruption() func(http.ResponseWriter, *http.Request) {
+	return h.stopNetemFaultHandler(types.CorruptionFaultType, types.NetworkCorruptionRequest{})
+}
 
-		// To avoid multiple requests to manipulate same network resource
-		networkNSPath := taskMetadata.TaskNetworkConfig.NetworkNamespaces[0].Path
-		rwMu := h.loadLock(networkNSPath)
-		rwMu.RLock()
-		defer rwMu.RUnlock()
+// CheckNetworkCorruption checks the status of given network packet corruption fault.
+func (h *FaultHandler) CheckNetworkCorruption() func(http.ResponseWriter, *http.Request) {
+	return h.checkNetemFaultHandler(types.CorruptionFaultType, types.NetworkCorruptionRequest{})
+}
 
-		// Check and return the status of current fault injection.
-		var responseBody types.NetworkFaultInjectionResponse
-		var httpStatusCode int
-		stringToBeLogged := "Failed to check status for fault"
-		// All command executions for the check network latency workflow all together should finish within 5 seconds.
-		// Thus, create the context here so that it can be shared by all os/exec calls.
-		ctx, cancel := h.osExecWrapper.NewExecContextWithTimeout(context.Background(), requestTimeoutSeconds*time.Second)
-		defer cancel()
-		// Check the status of current fault injection.
-		latencyFaultExists, _, err := h.checkTCFault(ctx, taskMetadata)
-		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
-			responseBody = types.NewNetworkFaultInjectionErrorResponse(fmt.Sprintf(requestTimedOutError, requestType))
-			httpStatusCode = http.StatusInternalServerError
-		} else if err != nil {
-			responseBody = types.NewNetworkFaultInjectionErrorResponse(internalError)
-			httpStatusCode = http.StatusInternalServerError
-		} else {
-			stringToBeLogged = "Successfully checked fault status"
-			// If there already exists a fault in the task network namespace.
-			if latencyFaultExists {
-				responseBody = types.NewNetworkFaultInjectionSuccessResponse("running")
-				httpStatusCode = http.StatusOK
-			} else {
-				responseBody = types.NewNetworkFaultInjectionSuccessResponse("not-running")
-				httpStatusCode = http.StatusOK
-			}
-		}
-		logger.Info(stringToBeLogged, logger.Fields{
-			field.RequestType: requestType,
-			field.Request:     request.ToString(),
-			field.Response:    responseBody.ToString(),
-		})
-		handlerutils.WriteJSONResponse(
-			w,
-			httpStatusCode,
-			responseBody,
-			requestType,
-		)
-	}
+// StartNetworkDuplication starts a network packet duplication fault in the associated ENI if no existing same
+// fault.
+func (h *FaultHandler) StartNetworkDuplication() func(http.ResponseWriter, *http.Request) {
+	return h.startNetemFaultHandler(types.DuplicationFaultType, func() types.NetworkFaultRequest {
+		return &types.NetworkDuplicationRequest{}
+	})
 }
 
-// StartNetworkPacketLoss starts a network packet loss fault in the associated ENI if no existing same fault.
-func (h *FaultHandler) StartNetworkPacketLoss() func(http.ResponseWriter, *http.Request) {
-	return func(w http.ResponseWriter, r *http.Request) {
-		var request types.NetworkPacketLossRequest
-		requestType := fmt.Sprintf(startFaultRequestType, types.PacketLossFaultType)
+// StopNetworkDuplication stops a network packet duplication fault in the associated ENI if there is one existing
+// same fault.
+func (h *FaultHandler) StopNetworkDuplication() func(http.ResponseWriter, *http.Request) {
+	return h.stopNetemFaultHandler(types.DuplicationFaultType, types.NetworkDuplicationRequest{})
+}
+
+// CheckNetworkDuplication checks the status of given network packet duplication fault.
+func (h *FaultHandler) CheckNetworkDuplication() func(http.ResponseWriter, *http.Request) {
+	return h.checkNetemFaultHandler(types.DuplicationFaultType, types.NetworkDuplicationRequest{})
+}
+
+// StartNetworkReorder starts a network packet reordering fault in the associated ENI if no existing same fault.
+func (h *FaultHandler) StartNetworkReorder() func(http.ResponseWriter, *http.Request) {
+	return h.startNetemFaultHandler(types.ReorderFaultType, func() types.NetworkFaultRequest {
+		return &types.NetworkReorderRequest{}
+	})
+}
+
+// StopNetworkReorder stops a network packet reordering fault in the associated ENI if there is one existing same
+// fault.
+func (h *FaultHandler) StopNetworkReorder() func(http.ResponseWriter, *http.Request) {
+	return h.stopNetemFaultHandler(types.ReorderFaultType, types.NetworkReorderRequest{})
+}
 
+// CheckNetworkReorder checks the status of given network packet reordering fault.
+func (h *FaultHandler) CheckNetworkReorder() func(http.ResponseWriter, *http.Request) {
+	return h.checkNetemFaultHandler(types.ReorderFaultType, types.NetworkReorderRequest{})
+}
+
+// startNetemFaultHandler returns the handler starting a network fault of the given type, injected with tc netem,
+// in the associated ENI. Only one fault injected with tc can run at a time, so the fault is only started if no
+// other one is running. newRequest returns the request the fault is started with.
+func (h *FaultHandler) startNetemFaultHandler(
+	faultType string, newRequest func() types.NetworkFaultRequest,
+) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		request := newRequest()
+		requestType := fmt.Sprintf(startFaultRequestType, faultType)
 		// Parse the fault request
-		err := decodeRequest(w, &request, requestType, r)
+		err := decodeRequest(w, request, requestType, r)
 		if err != nil {
 			return
 		}
@@ -904,42 +870,45 @@ func (h *FaultHandler) StartNetworkPacketLoss() func(http.ResponseWriter, *http.
 		var responseBody types.NetworkFaultInjectionResponse
 		var httpStatusCode int
 		stringToBeLogged := "Failed to start fault"
-		// All command executions for the start network packet loss workflow all together should finish within 5 seconds.
+		// All command executions for the start fault workflow all together should finish within 5 seconds.
 		// Thus, create the context here so that it can be shared by all os/exec calls.
 		ctx, cancel := h.osExecWrapper.NewExecContextWithTimeout(context.Background(), requestTimeoutSeconds*time.Second)
 		defer cancel()
 		// Check the status of current fault injection.
-		latencyFaultExists, packetLossFaultExists, err := h.checkTCFault(ctx, taskMetadata)
+		runningFaultType, err := h.checkTCFault(ctx, taskMetadata)
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 			responseBody = types.NewNetworkFaultInjectionErrorResponse(fmt.Sprintf(requestTimedOutError, requestType))
 			httpStatusCode = http.StatusInternalServerError
 		} else if err != nil {
 			responseBody = types.NewNetworkFaultInjectionErrorResponse(internalError)
 			httpStatusCode = http.StatusInternalServerError
-		} else {
+		} else if runningFaultType != "" {
 			// If there already exists a fault in the task network namespace.
-			if latencyFaultExists {
-				responseBody = types.NewNetworkFaultInjectionErrorResponse(latencyFaultAlreadyRunningError)
-				httpStatusCode = http.StatusConflict
-			} else if packetLossFaultExists {
-				responseBody = types.NewNetworkFaultInjectionErrorResponse(packetLossFaultAlreadyRunningError)
-				httpStatusCode = http.StatusConflict
+			responseBody = types.NewNetworkFaultInjectionErrorResponse(faultAlreadyRunningError(runningFaultType))
+			httpStatusCode = http.StatusConflict
+		} else {
+			// Invoke the start fault injection functionality if not running.
+			fault, err := newNetemFault(request)
+			if err == nil {
+				err = h.startNetemFault(ctx, taskMetadata, fault)
+			}
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				responseBody = types.NewNetworkFaultInjectionErrorResponse(fmt.Sprintf(requestTimedOutError, requestType))
+				httpStatusCode = http.StatusInternalServerError
+			} else if err != nil {
+				responseBody = types.NewNetworkFaultInjectionErrorResponse(internalError)
+				httpStatusCode = http.StatusInternalServerError
 			} else {
-				// Invoke the start fault injection functionality if not running.
-				err := h.startNetworkPacketLossFault(ctx, taskMetadata, request)
-				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
-					responseBody = types.NewNetworkFaultInjectionErrorResponse(fmt.Sprintf(requestTimedOutError, requestType))
-					httpStatusCode = http.StatusInternalServerError
-				} else if err != nil {
-					responseBody = types.NewNetworkFaultInjectionErrorResponse(internalError)
-					httpStatusCode = http.StatusInternalServerError
-				} else {
-					stringToBeLogged = "Successfully started fault"
-					responseBody = types.NewNetworkFaultInjectionSuccessResponse("running")
-					httpStatusCode = http.StatusOK
-				}
+				stringToBeLogged = "Successfully started fault"
+				responseBody = types.NewNetworkFaultInjectionSuccessResponse("running")
+				h.recordFault(h.newInjectedFault(taskMetadata, faultType, faultID(networkNSPath, faultType),
+					request.ToString(), fault.durationSeconds))
+				responseBody.RemainingDurationSeconds = fault.durationSeconds
+				httpStatusCode = http.StatusOK
 			}
 		}
+		h.auditFault(r, taskMetadata.TaskARN, httpStatusCode, audit.StartFaultEventType, faultType,
+			request.ToString())
 		logger.Info(stringToBeLogged, logger.Fields{
 			field.RequestType: requestType,
 			field.Request:     request.ToString(),
@@ -954,11 +923,13 @@ func (h *FaultHandler) StartNetworkPacketLoss() func(http.ResponseWriter, *http.
 	}
 }
 
-// StopNetworkPacketLoss stops a network packet loss fault in the associated ENI if there is one existing same fault.
-func (h *FaultHandler) StopNetworkPacketLoss() func(http.ResponseWriter, *http.Request) {
+// stopNetemFaultHandler returns the handler stopping a network fault of the given type, injected with tc netem,
+// in the associated ENI if there is one existing same fault. request is only used for logging.
+func (h *FaultHandler) stopNetemFaultHandler(
+	faultType string, request types.NetworkFaultRequest,
+) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var request types.NetworkPacketLossRequest
-		requestType := fmt.Sprintf(stopFaultRequestType, types.PacketLossFaultType)
+		requestType := fmt.Sprintf(stopFaultRequestType, faultType)
 		logRequest(requestType, r)
 
 		// Obtain the task metadata via the endpoint container ID
@@ -976,40 +947,43 @@ func (h *FaultHandler) StopNetworkPacketLoss() func(http.ResponseWriter, *http.R
 		var responseBody types.NetworkFaultInjectionResponse
 		var httpStatusCode int
 		stringToBeLogged := "Failed to stop fault"
-		// All command executions for the stop network packet loss workflow all together should finish within 5 seconds.
+		// All command executions for the stop fault workflow all together should finish within 5 seconds.
 		// Thus, create the context here so that it can be shared by all os/exec calls.
 		ctx, cancel := h.osExecWrapper.NewExecContextWithTimeout(context.Background(), requestTimeoutSeconds*time.Second)
 		defer cancel()
 		// Check the status of current fault injection.
-		_, packetLossFaultExists, err := h.checkTCFault(ctx, taskMetadata)
+		runningFaultType, err := h.checkTCFault(ctx, taskMetadata)
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 			responseBody = types.NewNetworkFaultInjectionErrorResponse(fmt.Sprintf(requestTimedOutError, requestType))
 			httpStatusCode = http.StatusInternalServerError
 		} else if err != nil {
 			responseBody = types.NewNetworkFaultInjectionErrorResponse(internalError)
 			httpStatusCode = http.StatusInternalServerError
+		} else if runningFaultType != faultType {
+			// If there doesn't already exist a fault of the given type
+			stringToBeLogged = "No fault running"
+			responseBody = types.NewNetworkFaultInjectionSuccessResponse("stopped")
+			httpStatusCode = http.StatusOK
 		} else {
-			// If there doesn't already exist a network-packet-loss fault
-			if !packetLossFaultExists {
-				stringToBeLogged = "No fault running"
+			// Invoke the stop fault injection functionality if running.
+			err := h.stopTCFault(ctx, taskMetadata)
+			if errors.Is(err, context.DeadlineExceeded) {
+				responseBody = types.NewNetworkFaultInjectionErrorResponse(fmt.Sprintf(requestTimedOutError, requestType))
+				httpStatusCode = http.StatusInternalServerError
+			} else if err != nil {
+				responseBody = types.NewNetworkFaultInjectionErrorResponse(internalError)
+				httpStatusCode = http.StatusInternalServerError
+			} else {
+				stringToBeLogged = "Successfully stopped fault"
 				responseBody = types.NewNetworkFaultInjectionSuccessResponse("stopped")
 				httpStatusCode = http.StatusOK
-			} else {
-				// Invoke the stop fault injection functionality if running.
-				err := h.stopTCFault(ctx, taskMetadata)
-				if errors.Is(err, context.DeadlineExceeded) {
-					responseBody = types.NewNetworkFaultInjectionErrorResponse(fmt.Sprintf(requestTimedOutError, requestType))
-					httpStatusCode = http.StatusInternalServerError
-				} else if err != nil {
-					responseBody = types.NewNetworkFaultInjectionErrorResponse(internalError)
-					httpStatusCode = http.StatusInternalServerError
-				} else {
-					stringToBeLogged = "Successfully stopped fault"
-					responseBody = types.NewNetworkFaultInjectionSuccessResponse("stopped")
-					httpStatusCode = http.StatusOK
-				}
 			}
 		}
+		parameters := h.faultParameters(faultID(networkNSPath, faultType))
+		if httpStatusCode == http.StatusOK {
+			h.forgetFault(faultID(networkNSPath, faultType))
+		}
+		h.auditFault(r, taskMetadata.TaskARN, httpStatusCode, audit.StopFaultEventType, faultType, parameters)
 		logger.Info(stringToBeLogged, logger.Fields{
 			field.RequestType: requestType,
 			field.Request:     request.ToString(),
@@ -1024,20 +998,22 @@ func (h *FaultHandler) StopNetworkPacketLoss() func(http.ResponseWriter, *http.R
 	}
 }
 
-// CheckNetworkPacketLoss checks the status of given network packet loss fault.
-func (h *FaultHandler) CheckNetworkPacketLoss() func(http.ResponseWriter, *http.Request) {
+// checkNetemFaultHandler returns the handler checking the status of a network fault of the given type, injected
+// with tc netem, in the associated ENI. request is only used for logging.
+func (h *FaultHandler) checkNetemFaultHandler(
+	faultType string, request types.NetworkFaultRequest,
+) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var request types.NetworkPacketLossRequest
-		requestType := fmt.Sprintf(checkStatusFaultRequestType, types.PacketLossFaultType)
+		requestType := fmt.Sprintf(checkStatusFaultRequestType, faultType)
 		logRequest(requestType, r)
 
-		// Obtain the task metadata via the endpoint container ID.
+		// Obtain the task metadata via the endpoint container ID
 		taskMetadata, err := validateTaskMetadata(w, h.AgentState, requestType, r)
 		if err != nil {
 			return
 		}
 
-		// To avoid multiple requests to manipulate same network resource.
+		// To avoid multiple requests to manipulate same network resource
 		networkNSPath := taskMetadata.TaskNetworkConfig.NetworkNamespaces[0].Path
 		rwMu := h.loadLock(networkNSPath)
 		rwMu.RLock()
@@ -1047,12 +1023,12 @@ func (h *FaultHandler) CheckNetworkPacketLoss() func(http.ResponseWriter, *http.
 		var responseBody types.NetworkFaultInjectionResponse
 		var httpStatusCode int
 		stringToBeLogged := "Failed to check status for fault"
-		// All command executions for the check network packet loss workflow all together should finish within 5 seconds.
+		// All command executions for the check fault workflow all together should finish within 5 seconds.
 		// Thus, create the context here so that it can be shared by all os/exec calls.
 		ctx, cancel := h.osExecWrapper.NewExecContextWithTimeout(context.Background(), requestTimeoutSeconds*time.Second)
 		defer cancel()
 		// Check the status of current fault injection.
-		_, packetLossFaultExists, err := h.checkTCFault(ctx, taskMetadata)
+		runningFaultType, err := h.checkTCFault(ctx, taskMetadata)
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 			responseBody = types.NewNetworkFaultInjectionErrorResponse(fmt.Sprintf(requestTimedOutError, requestType))
 			httpStatusCode = http.StatusInternalServerError
@@ -1061,9 +1037,10 @@ func (h *FaultHandler) CheckNetworkPacketLoss() func(http.ResponseWriter, *http.
 			httpStatusCode = http.StatusInternalServerError
 		} else {
 			stringToBeLogged = "Successfully checked fault status"
-			// If there already exists a fault in the task network namespace.
-			if packetLossFaultExists {
+			// If there already exists a fault of the given type in the task network namespace.
+			if runningFaultType == faultType {
 				responseBody = types.NewNetworkFaultInjectionSuccessResponse("running")
+				responseBody.RemainingDurationSeconds = h.faultRemainingDuration(faultID(networkNSPath, faultType))
 				httpStatusCode = http.StatusOK
 			} else {
 				responseBody = types.NewNetworkFaultInjectionSuccessResponse("not-running")
@@ -1084,6 +1061,12 @@ func (h *FaultHandler) CheckNetworkPacketLoss() func(http.ResponseWriter, *http.
 	}
 }
 
+// faultAlreadyRunningError returns the error message of a fault that can't be started because a fault of the
+// given type is running.
+func faultAlreadyRunningError(faultType string) string {
+	return fmt.Sprintf(faultAlreadyRunningErrorFormat, strings.ReplaceAll(faultType, "-", " "))
+}
+
 // decodeRequest will log the request and then translate/unmarshal an incoming fault injection request into
 // one of the network fault structs which requires the reqeust body to non-empty.
 func decodeRequest(w http.ResponseWriter, request types.NetworkFaultRequest, requestType string, r *http.Request) error {
@@ -1140,16 +1123,20 @@ func validateRequest(w http.ResponseWriter, request types.NetworkFaultRequest, r
 // validateTaskMetadata will first fetch the associated task metadata and then validate it to make sure
 // the task has enabled fault injection and the corresponding network mode is supported.
 func validateTaskMetadata(w http.ResponseWriter, agentState state.AgentState, requestType string, r *http.Request) (*state.TaskResponse, error) {
-	var taskMetadata state.TaskResponse
-	endpointContainerID := mux.Vars(r)[v4.EndpointContainerIDMuxName]
-	taskMetadata, err := agentState.GetTaskMetadataWithTaskNetworkConfig(endpointContainerID, netconfig.NewNetworkConfigClient())
+	taskMetadata, err := getFaultInjectionTaskMetadata(w, agentState, requestType, r)
 	if err != nil {
+		return nil, err
+	}
+	endpointContainerID := mux.Vars(r)[v4.EndpointContainerIDMuxName]
+
+	if err := validateTaskNetworkConfig(taskMetadata.TaskNetworkConfig); err != nil {
 		code, errResponse := getTaskMetadataErrorResponse(endpointContainerID, requestType, err)
 		responseBody := types.NewNetworkFaultInjectionErrorResponse(fmt.Sprintf("%v", errResponse))
-		logger.Error("Error: Unable to obtain task metadata", logger.Fields{
-			field.Error:       errResponse,
-			field.RequestType: requestType,
-			field.Response:    responseBody.ToString(),
+		logger.Error("Error: Unable to resolve task network config within task metadata", logger.Fields{
+			field.Error:                   err,
+			field.RequestType:             requestType,
+			field.Response:                responseBody.ToString(),
+			field.TMDSEndpointContainerID: endpointContainerID,
 		})
 		handlerutils.WriteJSONResponse(
 			w,
@@ -1160,16 +1147,15 @@ func validateTaskMetadata(w http.ResponseWriter, agentState state.AgentState, re
 		return nil, errResponse
 	}
 
-	// Check if task is FIS-enabled
-	if !taskMetadata.FaultInjectionEnabled {
-		errResponse := fmt.Sprintf(faultInjectionEnabledError, taskMetadata.TaskARN)
+	// Check if task is using a valid network mode
+	networkMode := ecstypes.NetworkMode(taskMetadata.TaskNetworkConfig.NetworkMode)
+	if networkMode != ecstypes.NetworkModeHost && networkMode != ecstypes.NetworkModeAwsvpc {
+		errResponse := fmt.Sprintf(invalidNetworkModeError, networkMode)
 		responseBody := types.NewNetworkFaultInjectionErrorResponse(errResponse)
-		logger.Error("Error: Task is not fault injection enabled.", logger.Fields{
-			field.RequestType:             requestType,
-			field.TMDSEndpointContainerID: endpointContainerID,
-			field.Response:                responseBody.ToString(),
-			field.TaskARN:                 taskMetadata.TaskARN,
-			field.Error:                   errResponse,
+		logger.Error("Error: Invalid network mode for fault injection", logger.Fields{
+			field.RequestType: requestType,
+			field.NetworkMode: networkMode,
+			field.Response:    responseBody.ToString(),
 		})
 		handlerutils.WriteJSONResponse(
 			w,
@@ -1180,14 +1166,25 @@ func validateTaskMetadata(w http.ResponseWriter, agentState state.AgentState, re
 		return nil, errors.New(errResponse)
 	}
 
-	if err := validateTaskNetworkConfig(taskMetadata.TaskNetworkConfig); err != nil {
+	return taskMetadata, nil
+}
+
+// getFaultInjectionTaskMetadata fetches the associated task metadata and makes sure the task has enabled fault
+// injection.
+func getFaultInjectionTaskMetadata(
+	w http.ResponseWriter, agentState state.AgentState, requestType string, r *http.Request,
+) (*state.TaskResponse, error) {
+	var taskMetadata state.TaskResponse
+	endpointContainerID := mux.Vars(r)[v4.EndpointContainerIDMuxName]
+
+	taskMetadata, err := agentState.GetTaskMetadataWithTaskNetworkConfig(endpointContainerID, netconfig.NewNetworkConfigClient())
+	if err != nil {
 		code, errResponse := getTaskMetadataErrorResponse(endpointContainerID, requestType, err)
 		responseBody := types.NewNetworkFaultInjectionErrorResponse(fmt.Sprintf("%v", errResponse))
-		logger.Error("Error: Unable to resolve task network config within task metadata", logger.Fields{
-			field.Error:                   err,
-			field.RequestType:             requestType,
-			field.Response:                responseBody.ToString(),
-			field.TMDSEndpointContainerID: endpointContainerID,
+		logger.Error("Error: Unable to obtain task metadata", logger.Fields{
+			field.Error:       errResponse,
+			field.RequestType: requestType,
+			field.Response:    responseBody.ToString(),
 		})
 		handlerutils.WriteJSONResponse(
 			w,
@@ -1198,15 +1195,16 @@ func validateTaskMetadata(w http.ResponseWriter, agentState state.AgentState, re
 		return nil, errResponse
 	}
 
-	// Check if task is using a valid network mode
-	networkMode := ecstypes.NetworkMode(taskMetadata.TaskNetworkConfig.NetworkMode)
-	if networkMode != ecstypes.NetworkModeHost && networkMode != ecstypes.NetworkModeAwsvpc {
-		errResponse := fmt.Sprintf(invalidNetworkModeError, networkMode)
+	// Check if task is FIS-enabled
+	if !taskMetadata.FaultInjectionEnabled {
+		errResponse := fmt.Sprintf(faultInjectionEnabledError, taskMetadata.TaskARN)
 		responseBody := types.NewNetworkFaultInjectionErrorResponse(errResponse)
-		logger.Error("Error: Invalid network mode for fault injection", logger.Fields{
-			field.RequestType: requestType,
-			field.NetworkMode: networkMode,
-			field.Response:    responseBody.ToString(),
+		logger.Error("Error: Task is not fault injection enabled.", logger.Fields{
+			field.RequestType:             requestType,
+			field.TMDSEndpointContainerID: endpointContainerID,
+			field.Response:                responseBody.ToString(),
+			field.TaskARN:                 taskMetadata.TaskARN,
+			field.Error:                   errResponse,
 		})
 		handlerutils.WriteJSONResponse(
 			w,
@@ -1333,77 +1331,91 @@ func validateTaskNetworkConfig(taskNetworkConfig *state.TaskNetworkConfig) error
 	return nil
 }
 
-// startNetworkLatencyFault invokes the linux TC utility tool to start the
-// network-latency fault for the given task.
-func (h *FaultHandler) startNetworkLatencyFault(
-	ctx context.Context, taskMetadata *state.TaskResponse, request types.NetworkLatencyRequest,
-) error {
-	for _, netInterface := range taskMetadata.TaskNetworkConfig.NetworkNamespaces[0].NetworkInterfaces {
-		err := h.startNetworkLatencyFaultForInterface(ctx, taskMetadata, request, netInterface.DeviceName)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+// netemFault holds the parameters of a network fault injected with tc netem.
+type netemFault struct {
+	// netemArguments are the arguments of the netem queueing discipline emulating the fault.
+	netemArguments string
+	// aggregate is set when a single netem queueing discipline has to emulate the fault for all the impacted
+	// traffic, e.g. to throttle its aggregate bandwidth, rather than one per flow band.
+	aggregate       bool
+	sources         []*string
+	sourcesToFilter []*string
+	flowsPercent    *int
+	durationSeconds *uint64
 }
 
-// startNetworkLatencyFaultForInterface invokes the linux TC utility tool to start the
-// network-latency fault for the given interface.
-func (h *FaultHandler) startNetworkLatencyFaultForInterface(
-	ctx context.Context, taskMetadata *state.TaskResponse, request types.NetworkLatencyRequest,
-	interfaceName string,
-) error {
-	networkMode := ecstypes.NetworkMode(taskMetadata.TaskNetworkConfig.NetworkMode)
-	// If task's network mode is awsvpc, we need to run nsenter to access the task's network namespace.
-	nsenterPrefix := ""
-	if networkMode == ecstypes.NetworkModeAwsvpc {
-		nsenterPrefix = fmt.Sprintf(nsenterCommandString, taskMetadata.TaskNetworkConfig.NetworkNamespaces[0].Path)
-	}
-	delayInMs := aws.ToUint64(request.DelayMilliseconds)
-	jitterInMs := aws.ToUint64(request.JitterMilliseconds)
-	flowsPercent := defaultFlowsPercent
-	if request.FlowsPercent != nil {
-		flowsPercent = aws.ToInt(request.FlowsPercent)
+// newNetemFault returns the parameters of the network fault injected with tc netem for the request.
+func newNetemFault(request types.NetworkFaultRequest) (netemFault, error) {
+	switch request := request.(type) {
+	case *types.NetworkLatencyRequest:
+		return netemFault{
+			// delay %dms %dms
+			netemArguments: fmt.Sprintf(netemLatencyArgumentsString,
+				aws.ToUint64(request.DelayMilliseconds), aws.ToUint64(request.JitterMilliseconds)),
+			sources:         request.Sources,
+			sourcesToFilter: request.SourcesToFilter,
+			flowsPercent:    request.FlowsPercent,
+			durationSeconds: request.DurationSeconds,
+		}, nil
+	case *types.NetworkPacketLossRequest:
+		return netemFault{
+			// loss %d%%
+			netemArguments:  fmt.Sprintf(netemLossArgumentsString, aws.ToUint64(request.LossPercent)),
+			sources:         request.Sources,
+			sourcesToFilter: request.SourcesToFilter,
+			flowsPercent:    request.FlowsPercent,
+			durationSeconds: request.DurationSeconds,
+		}, nil
+	case *types.NetworkBandwidthRequest:
+		return netemFault{
+			// rate %dkbit
+			netemArguments:  fmt.Sprintf(netemRateArgumentsString, aws.ToUint64(request.RateKbps)),
+			aggregate:       true,
+			sources:         request.Sources,
+			sourcesToFilter: request.SourcesToFilter,
+			flowsPercent:    request.FlowsPercent,
+			durationSeconds: request.DurationSeconds,
+		}, nil
+	case *types.NetworkCorruptionRequest:
+		return netemFault{
+			// corrupt %d%%
+			netemArguments:  fmt.Sprintf(netemCorruptArgumentsString, aws.ToUint64(request.CorruptionPercent)),
+			sources:         request.Sources,
+			sourcesToFilter: request.SourcesToFilter,
+			flowsPercent:    request.FlowsPercent,
+			durationSeconds: request.DurationSeconds,
+		}, nil
+	case *types.NetworkDuplicationRequest:
+		return netemFault{
+			// duplicate %d%%
+			netemArguments:  fmt.Sprintf(netemDuplicateArgumentsString, aws.ToUint64(request.DuplicationPercent)),
+			sources:         request.Sources,
+			sourcesToFilter: request.SourcesToFilter,
+			flowsPercent:    request.FlowsPercent,
+			durationSeconds: request.DurationSeconds,
+		}, nil
+	case *types.NetworkReorderRequest:
+		return netemFault{
+			// delay %dms reorder %d%%
+			netemArguments: fmt.Sprintf(netemReorderArgumentsString,
+				aws.ToUint64(request.DelayMilliseconds), aws.ToUint64(request.ReorderPercent)),
+			sources:         request.Sources,
+			sourcesToFilter: request.SourcesToFilter,
+			flowsPercent:    request.FlowsPercent,
+			durationSeconds: request.DurationSeconds,
+		}, nil
+	default:
+		return netemFault{}, fmt.Errorf("unsupported network fault request: %s", request.ToString())
 	}
-
-	// Create three layer qdisc hierarchy allowing traffic distribution among 100 bands.
-	// Need 100 bands to accurately reflect flows percentage.
-	if err := h.createQdiscHierarchyForInterface(ctx, taskMetadata, nsenterPrefix, interfaceName); err != nil {
-		return err
-	}
-
-	// delay %dms %dms
-	netemLatencyArguments := fmt.Sprintf(netemLatencyArgumentsString, delayInMs, jitterInMs)
-	// Add netem delay to <flowsPercent> bands.
-	if err := h.addNetemFaultToInterfaceFlows(
-		ctx, taskMetadata, nsenterPrefix, interfaceName, flowsPercent, netemLatencyArguments,
-	); err != nil {
-		return err
-	}
-
-	// After creating the queueing disciplines, create filters to associate the IPs in the request with the handle.
-	// First redirect the allowlisted ip addresses to band 1:3 where is no network impairments.
-	if err := h.addIPAddressesToFilter(ctx, request.SourcesToFilter, taskMetadata, nsenterPrefix, tcAllowlistIPCommandString, interfaceName); err != nil {
-		return err
-	}
-	// After processing the allowlisted ips, associate the ip addresses in Sources with the qdisc hierarchy.
-	if err := h.addIPAddressesToFilter(ctx, request.Sources, taskMetadata, nsenterPrefix, tcAddFilterForIPCommandString, interfaceName); err != nil {
-		return err
-	}
-	// Finally, add hash filters based on flow to distribute the traffic among the 100 bands.
-	if err := h.addFlowHashFilters(ctx, taskMetadata, nsenterPrefix, interfaceName); err != nil {
-		return err
-	}
-
-	return nil
 }
 
-// startNetworkPacketLossFault invokes the linux TC utility tool to start the network-packet-loss fault.
-func (h *FaultHandler) startNetworkPacketLossFault(
-	ctx context.Context, taskMetadata *state.TaskResponse, request types.NetworkPacketLossRequest,
+// startNetemFault invokes the linux TC utility tool to start the network fault emulated by netem for the
+// given task.
+func (h *FaultHandler) startNetemFault(
+	ctx context.Context, taskMetadata *state.TaskResponse, fault netemFault,
 ) error {
 	for _, netInterface := range taskMetadata.TaskNetworkConfig.NetworkNamespaces[0].NetworkInterfaces {
-		err := h.startNetworkPacketLossFaultForInterface(ctx, taskMetadata, request, netInterface.DeviceName)
+		err := h.startNetemFaultForInterface(ctx, taskMetadata, fault, netInterface.DeviceName)
 		if err != nil {
 			return err
 		}
@@ -1411,11 +1423,10 @@ func (h *FaultHandler) startNetworkPacketLossFault(
 	return nil
 }
 
-// startNetworkPacketLossFault invokes the linux TC utility tool to start the network-packet-loss fault
+// startNetemFaultForInterface invokes the linux TC utility tool to start the network fault emulated by netem
 // for the given network interface.
-func (h *FaultHandler) startNetworkPacketLossFaultForInterface(
-	ctx context.Context, taskMetadata *state.TaskResponse, request types.NetworkPacketLossRequest,
-	interfaceName string,
+func (h *FaultHandler) startNetemFaultForInterface(
+	ctx context.Context, taskMetadata *state.TaskResponse, fault netemFault, interfaceName string,
 ) error {
 	networkMode := ecstypes.NetworkMode(taskMetadata.TaskNetworkConfig.NetworkMode)
 	// If task's network mode is awsvpc, we need to run nsenter to access the task's network namespace.
@@ -1423,36 +1434,45 @@ func (h *FaultHandler) startNetworkPacketLossFaultForInterface(
 	if networkMode == ecstypes.NetworkModeAwsvpc {
 		nsenterPrefix = fmt.Sprintf(nsenterCommandString, taskMetadata.TaskNetworkConfig.NetworkNamespaces[0].Path)
 	}
-	lossPercent := aws.ToUint64(request.LossPercent)
 	flowsPercent := defaultFlowsPercent
-	if request.FlowsPercent != nil {
-		flowsPercent = aws.ToInt(request.FlowsPercent)
+	if fault.flowsPercent != nil {
+		flowsPercent = aws.ToInt(fault.flowsPercent)
 	}
 
-	// Create three layer qdisc hierarchy allowing traffic distribution among 100 bands.
-	// Need 100 bands to accurately reflect flows percentage.
-	if err := h.createQdiscHierarchyForInterface(ctx, taskMetadata, nsenterPrefix, interfaceName); err != nil {
-		return err
-	}
+	if fault.aggregate {
+		// Add the netem fault right below the root qdisc so that all the impacted traffic goes through it.
+		if err := h.addAggregateNetemFaultToInterface(
+			ctx, taskMetadata, nsenterPrefix, interfaceName, fault.netemArguments,
+		); err != nil {
+			return err
+		}
+	} else {
+		// Create three layer qdisc hierarchy allowing traffic distribution among 100 bands.
+		// Need 100 bands to accurately reflect flows percentage.
+		if err := h.createQdiscHierarchyForInterface(ctx, taskMetadata, nsenterPrefix, interfaceName); err != nil {
+			return err
+		}
 
-	// loss %d%%
-	netemLossArguments := fmt.Sprintf(netemLossArgumentsString, lossPercent)
-	// Add netem packet loss to <flowsPercent> bands.
-	if err := h.addNetemFaultToInterfaceFlows(
-		ctx, taskMetadata, nsenterPrefix, interfaceName, flowsPercent, netemLossArguments,
-	); err != nil {
-		return err
+		// Add the netem fault to <flowsPercent> bands.
+		if err := h.addNetemFaultToInterfaceFlows(
+			ctx, taskMetadata, nsenterPrefix, interfaceName, flowsPercent, fault.netemArguments,
+		); err != nil {
+			return err
+		}
 	}
 
 	// After creating the queueing disciplines, create filters to associate the IPs in the request with the handle.
 	// First redirect the allowlisted ip addresses to band 1:3 where is no network impairments.
-	if err := h.addIPAddressesToFilter(ctx, request.SourcesToFilter, taskMetadata, nsenterPrefix, tcAllowlistIPCommandString, interfaceName); err != nil {
+	if err := h.addIPAddressesToFilter(ctx, fault.sourcesToFilter, taskMetadata, nsenterPrefix, tcAllowlistIPCommandString, interfaceName); err != nil {
 		return err
 	}
 	// After processing the allowlisted ips, associate the ip addresses in Sources with the qdisc hierarchy.
-	if err := h.addIPAddressesToFilter(ctx, request.Sources, taskMetadata, nsenterPrefix, tcAddFilterForIPCommandString, interfaceName); err != nil {
+	if err := h.addIPAddressesToFilter(ctx, fault.sources, taskMetadata, nsenterPrefix, tcAddFilterForIPCommandString, interfaceName); err != nil {
 		return err
 	}
+	if fault.aggregate {
+		return nil
+	}
 	// Finally, add hash filters based on flow to distribute the traffic among the 100 bands.
 	if err := h.addFlowHashFilters(ctx, taskMetadata, nsenterPrefix, interfaceName); err != nil {
 		return err
@@ -1461,12 +1481,11 @@ func (h *FaultHandler) startNetworkPacketLossFaultForInterface(
 	return nil
 }
 
-func (h *FaultHandler) createQdiscHierarchyForInterface(
+// addRootQdiscForInterface adds the root qdisc with 3 bands. Impacted traffic is directed to the first band,
+// protected traffic to the third band and all other traffic goes to the second band.
+func (h *FaultHandler) addRootQdiscForInterface(
 	ctx context.Context, taskMetadata *state.TaskResponse, nsenterPrefix string, interfaceName string,
 ) error {
-	// Add root qdisc with 3 bands, the first band will consist of a hierarchy of 100 possible bands where traffic is
-	// impaired depending on flow. Protected traffic will be directed to the third band. All other traffic goes
-	// to the second band.
 	// tc qdisc add dev %s root handle 1: prio priomap 2 2 2 2 2 2 2 2 2 2 2 2 2 2 2 2
 	tcAddQdiscRootCommandComposed := nsenterPrefix + fmt.Sprintf(tcAddQdiscRootCommandString, interfaceName)
 	cmdList := strings.Split(tcAddQdiscRootCommandComposed, " ")
@@ -1486,13 +1505,57 @@ func (h *FaultHandler) createQdiscHierarchyForInterface(
 		field.CommandOutput:    string(cmdOutput[:]),
 		field.NetworkInterface: interfaceName,
 	})
+	return nil
+}
+
+// addAggregateNetemFaultToInterface adds the root qdisc and a single netem qdisc to its first band, so that the
+// fault applies to all the impacted traffic at once.
+func (h *FaultHandler) addAggregateNetemFaultToInterface(
+	ctx context.Context, taskMetadata *state.TaskResponse, nsenterPrefix string, interfaceName string,
+	netemArguments string,
+) error {
+	if err := h.addRootQdiscForInterface(ctx, taskMetadata, nsenterPrefix, interfaceName); err != nil {
+		return err
+	}
+
+	// tc qdisc add dev %s parent 1:1 handle 10: netem %s
+	tcAddQdiscNetemFaultCommandComposed := nsenterPrefix + fmt.Sprintf(
+		tcAddQdiscNetemFaultBaseString, interfaceName, "1:1", "10:", netemArguments)
+	cmdList := strings.Split(tcAddQdiscNetemFaultCommandComposed, " ")
+	cmdOutput, err := h.runExecCommand(ctx, cmdList)
+	if err != nil {
+		logger.Error("Command execution failed", logger.Fields{
+			field.CommandString:    tcAddQdiscNetemFaultCommandComposed,
+			field.Error:            err,
+			field.CommandOutput:    string(cmdOutput[:]),
+			field.TaskARN:          taskMetadata.TaskARN,
+			field.NetworkInterface: interfaceName,
+		})
+		return err
+	}
+	logger.Info("Command execution completed", logger.Fields{
+		field.CommandString:    tcAddQdiscNetemFaultCommandComposed,
+		field.CommandOutput:    string(cmdOutput[:]),
+		field.NetworkInterface: interfaceName,
+	})
+	return nil
+}
+
+func (h *FaultHandler) createQdiscHierarchyForInterface(
+	ctx context.Context, taskMetadata *state.TaskResponse, nsenterPrefix string, interfaceName string,
+) error {
+	// Add root qdisc with 3 bands, the first band will consist of a hierarchy of 100 possible bands where traffic is
+	// impaired depending on flow.
+	if err := h.addRootQdiscForInterface(ctx, taskMetadata, nsenterPrefix, interfaceName); err != nil {
+		return err
+	}
 
 	// Add second qdisc layer with 10 bands from 10:1 to 10:a, each of them will have 10 bands totalling 100.
 	// By then splitting traffic in the resulting 100 bands we can represent a percentage.
 	// <nsenterPrefix> tc qdisc add dev <interfaceName> parent %s handle %s prio bands 10 priomap 2 2 2 2 2 2 2 2 2 2 2 2 2 2 2 2
 	tcAddQdiscSecondLayerCommandComposed := nsenterPrefix + fmt.Sprintf(tcAddQdiscChildCommandString, interfaceName, "1:1", "10:")
-	cmdList = strings.Split(tcAddQdiscSecondLayerCommandComposed, " ")
-	cmdOutput, err = h.runExecCommand(ctx, cmdList)
+	cmdList := strings.Split(tcAddQdiscSecondLayerCommandComposed, " ")
+	cmdOutput, err := h.runExecCommand(ctx, cmdList)
 	if err != nil {
 		logger.Error("Command execution failed", logger.Fields{
 			field.CommandString:    tcAddQdiscSecondLayerCommandComposed,
@@ -1650,31 +1713,29 @@ func (h *FaultHandler) stopTCFaultForInterface(
 	return nil
 }
 
-// checkTCFault checks if there's existing network-latency fault or network-packet-loss fault.
+// checkTCFault checks if there's an existing network fault injected with tc netem and returns its type, or an
+// empty string if there is none.
 func (h *FaultHandler) checkTCFault(
 	ctx context.Context, taskMetadata *state.TaskResponse,
-) (bool, bool, error) {
-	var latencyFound, packetLossFound bool
+) (string, error) {
+	runningFaultType := ""
 	for _, netInterface := range taskMetadata.TaskNetworkConfig.NetworkNamespaces[0].NetworkInterfaces {
-		hasLatency, hasPacketLoss, err := h.checkTCFaultForInterface(ctx, taskMetadata, netInterface.DeviceName)
+		faultType, err := h.checkTCFaultForInterface(ctx, taskMetadata, netInterface.DeviceName)
 		if err != nil {
-			return false, false, err
+			return "", err
 		}
-		if hasLatency {
-			latencyFound = true
-		}
-		if hasPacketLoss {
-			packetLossFound = true
+		if runningFaultType == "" {
+			runningFaultType = faultType
 		}
 	}
-	return latencyFound, packetLossFound, nil
+	return runningFaultType, nil
 }
 
-// checkTCFaultForInterface checks if there's existing network-latency fault or
-// network-packet-loss fault for the given network interface.
+// checkTCFaultForInterface checks if there's an existing network fault injected with tc netem for the given
+// network interface and returns its type, or an empty string if there is none.
 func (h *FaultHandler) checkTCFaultForInterface(
 	ctx context.Context, taskMetadata *state.TaskResponse, interfaceName string,
-) (bool, bool, error) {
+) (string, error) {
 	networkMode := ecstypes.NetworkMode(taskMetadata.TaskNetworkConfig.NetworkMode)
 	// If task's network mode is awsvpc, we need to run nsenter to access the task's network namespace.
 	nsenterPrefix := ""
@@ -1683,8 +1744,8 @@ func (h *FaultHandler) checkTCFaultForInterface(
 	}
 
 	// We will run the following Linux command to assess if there existing fault.
-	// "tc -j q show dev {INTERFACE} parent 1:1"
-	// The command above gives the output of "tc q show dev {INTERFACE} parent 1:1" in json format.
+	// "tc -j q show dev {INTERFACE}"
+	// The command above gives the output of "tc q show dev {INTERFACE}" in json format.
 	// We will then unmarshall the json string and evaluate the fields of it.
 	tcCheckInjectionCommandComposed := nsenterPrefix + fmt.Sprintf(tcCheckInjectionCommandString, interfaceName)
 	cmdList := strings.Split(tcCheckInjectionCommandComposed, " ")
@@ -1697,7 +1758,7 @@ func (h *FaultHandler) checkTCFaultForInterface(
 			field.TaskARN:          taskMetadata.TaskARN,
 			field.NetworkInterface: interfaceName,
 		})
-		return false, false, fmt.Errorf("failed to check existing network fault: '%s' command failed with the following error: '%s'. std output: '%s'. TaskArn: %s",
+		return "", fmt.Errorf("failed to check existing network fault: '%s' command failed with the following error: '%s'. std output: '%s'. TaskArn: %s",
 			tcCheckInjectionCommandComposed, err, string(cmdOutput[:]), taskMetadata.TaskARN)
 	}
 	// Log the command output to better help us debug.
@@ -1707,62 +1768,51 @@ func (h *FaultHandler) checkTCFaultForInterface(
 		field.NetworkInterface: interfaceName,
 	})
 
-	// Check whether latency fault exists and whether packet loss fault exists separately.
 	var outputUnmarshalled []map[string]interface{}
 	err = json.Unmarshal(cmdOutput, &outputUnmarshalled)
 	if err != nil {
-		return false, false, fmt.Errorf("failed to check existing network fault: failed to unmarshal tc command output: %s. TaskArn: %s", err.Error(), taskMetadata.TaskARN)
-	}
-	latencyFaultExists, err := checkLatencyFault(outputUnmarshalled)
-	if err != nil {
-		return false, false, fmt.Errorf("failed to check existing network fault: failed to unmarshal tc command output: %s. TaskArn: %s", err.Error(), taskMetadata.TaskARN)
-	}
-	packetLossFaultExists, err := checkPacketLossFault(outputUnmarshalled)
-	if err != nil {
-		return false, false, fmt.Errorf("failed to check existing network fault: failed to unmarshal tc command output: %s. TaskArn: %s", err.Error(), taskMetadata.TaskARN)
+		return "", fmt.Errorf("failed to check existing network fault: failed to unmarshal tc command output: %s. TaskArn: %s", err.Error(), taskMetadata.TaskARN)
 	}
-	return latencyFaultExists, packetLossFaultExists, nil
+	return netemFaultType(outputUnmarshalled), nil
 }
 
-// checkLatencyFault parses the tc command output and checks if there's existing network-latency fault running.
-func checkLatencyFault(outputUnmarshalled []map[string]interface{}) (bool, error) {
-	packetLossFaultExist, err := checkPacketLossFault(outputUnmarshalled)
-	if err != nil {
-		return false, err
-	}
-
-	// Make sure no existing packet loss fault because only one tc fault is allowed.
-	if packetLossFaultExist {
-		return false, nil
-	}
-
-	for _, line := range outputUnmarshalled {
-		// Check if field "kind":"netem" exists.
-		if line["kind"] == "netem" {
-			if options := line["options"]; options != nil {
-				// We don't check the "delay" field in the output of "options" intentionally because
-				// it is not present if the delay is zero and the jitter is non-zero.
-				return true, nil
-			}
-		}
-	}
-	return false, nil
+// netemFaultOptions maps the options of a netem queueing discipline to the type of the network fault they
+// emulate, in the order they are looked for. A network reorder fault also has the "delay" option, so the
+// options take precedence over the network latency fault.
+var netemFaultOptions = []struct {
+	option    string
+	faultType string
+}{
+	{option: "loss-random", faultType: types.PacketLossFaultType},
+	{option: "corrupt", faultType: types.CorruptionFaultType},
+	{option: "duplicate", faultType: types.DuplicationFaultType},
+	{option: "reorder", faultType: types.ReorderFaultType},
+	{option: "rate", faultType: types.BandwidthFaultType},
 }
 
-// checkPacketLossFault parses the tc command output and checks if there's existing network-packet-loss fault running.
-func checkPacketLossFault(outputUnmarshalled []map[string]interface{}) (bool, error) {
+// netemFaultType parses the tc command output and returns the type of the network fault emulated by the netem
+// queueing discipline, or an empty string if there is none.
+func netemFaultType(outputUnmarshalled []map[string]interface{}) string {
 	for _, line := range outputUnmarshalled {
-		// First check field "kind":"netem" exists.
-		if line["kind"] == "netem" {
-			// Now check if field "loss":"<loss percent>" exists, and if the percentage matches with the value in the request.
-			if options := line["options"]; options != nil {
-				if lossRandom := options.(map[string]interface{})["loss-random"]; lossRandom != nil {
-					return true, nil
-				}
+		// Check if field "kind":"netem" exists. Only the netem qdiscs added to the first band of the root qdisc,
+		// for aggregate faults, or to the first flow band are considered.
+		if line["kind"] != "netem" || (line["parent"] != "1:1" && line["parent"] != "100:1") {
+			continue
+		}
+		options, ok := line["options"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, netemFaultOption := range netemFaultOptions {
+			if options[netemFaultOption.option] != nil {
+				return netemFaultOption.faultType
 			}
 		}
+		// We don't check the "delay" field in the output of "options" intentionally because
+		// it is not present if the delay is zero and the jitter is non-zero.
+		return types.LatencyFaultType
 	}
-	return false, nil
+	return ""
 }
 
 func (h *FaultHandler) addIPAddressesToFilter(
@@ -45,30 +45,38 @@ import (
 )
 
 const (
-	endpointId         = "endpointId"
-	port               = 1234
-	protocol           = "tcp"
-	trafficType        = "ingress"
-	delayMilliseconds  = 123456789
-	jitterMilliseconds = 4567
-	lossPercent        = 6
-	flowsPercent       = 100
-	taskARN            = "taskArn"
-	awsvpcNetworkMode  = "awsvpc"
-	hostNetworkMode    = "host"
-	deviceName         = "eth0"
-	deviceName2        = "eth1"
-	ipv4Addr           = "10.0.0.1"
-	ipv6Addr           = "2600:1f13:4d9:e602:6aea:cdb1:2b2b:8d62"
-	invalidNetworkMode = "invalid"
-	nspath             = "/some/path"
-	nspathHost         = "host"
-	noNsenterPrefix    = ""
+	endpointId               = "endpointId"
+	port                     = 1234
+	protocol                 = "tcp"
+	trafficType              = "ingress"
+	delayMilliseconds        = 123456789
+	jitterMilliseconds       = 4567
+	lossPercent              = 6
+	rateKbps                 = 100
+	reorderDelayMilliseconds = 10
+	flowsPercent             = 100
+	taskARN                  = "taskArn"
+	awsvpcNetworkMode        = "awsvpc"
+	hostNetworkMode          = "host"
+	deviceName               = "eth0"
+	deviceName2              = "eth1"
+	ipv4Addr                 = "10.0.0.1"
+	ipv6Addr                 = "2600:1f13:4d9:e602:6aea:cdb1:2b2b:8d62"
+	invalidNetworkMode       = "invalid"
+	nspath                   = "/some/path"
+	nspathHost               = "host"
+	noNsenterPrefix          = ""
 	// Fault injection tooling errors output
 	iptablesChainNotFoundError                     = "iptables: Bad rule (does a matching rule exist in that chain?)."
 	tcLatencyFaultExistsCommandOutput              = `[{"kind":"netem","handle":"10:","parent":"1:1","options":{"limit":1000,"delay":{"delay":123456789,"jitter":4567,"correlation":0},"ecn":false,"gap":0}}]`
 	tcLatencyFaultWithZeroDelayExistsCommandOutput = `[{"kind":"netem","handle":"10:","parent":"1:1","options":{"limit":1000,"ecn":false,"gap":0}}]`
 	tcLossFaultExistsCommandOutput                 = `[{"kind":"netem","handle":"10:","dev":"eth0","parent":"1:1","options":{"limit":1000,"loss-random":{"loss":0.06,"correlation":0},"ecn":false,"gap":0}}]`
+	tcBandwidthFaultExistsCommandOutput            = `[{"kind":"netem","handle":"10:","parent":"1:1","options":{"limit":1000,"rate":{"rate":12500,"packetoverhead":0,"cellsize":0,"celloverhead":0},"ecn":false,"gap":0}}]`
+	tcCorruptionFaultExistsCommandOutput           = `[{"kind":"netem","handle":"10:","parent":"1:1","options":{"limit":1000,"corrupt":{"corrupt":0.06,"correlation":0},"ecn":false,"gap":0}}]`
+	tcDuplicationFaultExistsCommandOutput          = `[{"kind":"netem","handle":"10:","parent":"1:1","options":{"limit":1000,"duplicate":{"duplicate":0.06,"correlation":0},"ecn":false,"gap":0}}]`
+	tcReorderFaultExistsCommandOutput              = `[{"kind":"netem","handle":"10:","parent":"1:1","options":{"limit":1000,"reorder":{"reorder":0.06,"correlation":0},"delay":{"delay":0.1,"jitter":0,"correlation":0},"ecn":false,"gap":0}}]`
+	tcFlowBandLatencyFaultExistsCommandOutput      = `[{"kind":"prio","handle":"1:","root":true,"options":{"bands":3}},{"kind":"prio","handle":"10:","parent":"1:1","options":{"bands":10}},{"kind":"netem","handle":"200:","parent":"100:1","options":{"limit":1000,"delay":{"delay":0.1,"jitter":0,"correlation":0},"ecn":false,"gap":0}}]`
+	tcUnrelatedNetemCommandOutput                  = `[{"kind":"netem","handle":"8001:","root":true,"options":{"limit":1000,"delay":{"delay":0.1,"jitter":0,"correlation":0},"ecn":false,"gap":0}}]`
 	tcCommandEmptyOutput                           = `[]`
 	// Common Fault injection JSON responses
 	happyFaultRunningResponse    = `{"Status":"running"}`
@@ -251,6 +259,31 @@ var (
 		"SourcesToFilter": ipSourcesToFilter,
 	}
 
+	happyNetworkBandwidthReqBody = map[string]interface{}{
+		"RateKbps":        rateKbps,
+		"Sources":         ipSources,
+		"SourcesToFilter": ipSourcesToFilter,
+	}
+
+	happyNetworkCorruptionReqBody = map[string]interface{}{
+		"CorruptionPercent": lossPercent,
+		"Sources":           ipSources,
+		"SourcesToFilter":   ipSourcesToFilter,
+	}
+
+	happyNetworkDuplicationReqBody = map[string]interface{}{
+		"DuplicationPercent": lossPercent,
+		"Sources":            ipSources,
+		"SourcesToFilter":    ipSourcesToFilter,
+	}
+
+	happyNetworkReorderReqBody = map[string]interface{}{
+		"ReorderPercent":    lossPercent,
+		"DelayMilliseconds": reorderDelayMilliseconds,
+		"Sources":           ipSources,
+		"SourcesToFilter":   ipSourcesToFilter,
+	}
+
 	ipSources = []string{"52.95.154.1", "52.95.154.2", "1:2:3:4::"}
 
 	ipSourcesToFilter = []string{"8.8.8.8", "5:5:5:5::"}
@@ -296,7 +329,7 @@ func setStartTCFaultExpectations(
 
 	// Check existing faults for each interface
 	for _, interfaceName := range interfaces {
-		expectedCommands = append(expectedCommands, fmt.Sprintf("tc -j q show dev %s parent 100:1", interfaceName))
+		expectedCommands = append(expectedCommands, fmt.Sprintf("tc -j q show dev %s", interfaceName))
 	}
 
 	expectations = addExpectedCommandsWithPrefix(exec, nsenterPrefix, expectedCommands, expectations, mockCMD)
@@ -375,6 +408,57 @@ func setStartTCFaultExpectations(
 	gomock.InOrder(expectations...)
 }
 
+// setStartTCAggregateFaultExpectations sets up all mock expectations for starting a network fault emulated by a
+// single netem qdisc for all the impacted traffic, such as a network bandwidth fault.
+func setStartTCAggregateFaultExpectations(
+	exec *mock_execwrapper.MockExec,
+	ctrl *gomock.Controller,
+	interfaces []string,
+	expectedNetemArguments string,
+	sourcesToFilter []string,
+	sources []string,
+	nsenterPrefix string,
+) {
+	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeoutDuration)
+	mockCMD := mock_execwrapper.NewMockCmd(ctrl)
+
+	expectations := []*gomock.Call{
+		exec.EXPECT().
+			NewExecContextWithTimeout(gomock.Any(), gomock.Any()).Times(1).
+			Return(ctx, cancel),
+	}
+	expectedCommands := []string{}
+	for _, interfaceName := range interfaces {
+		expectedCommands = append(expectedCommands, fmt.Sprintf("tc -j q show dev %s", interfaceName))
+	}
+	for _, interfaceName := range interfaces {
+		expectedCommands = append(expectedCommands,
+			fmt.Sprintf("tc qdisc add dev %s root handle 1: prio priomap 2 2 2 2 2 2 2 2 2 2 2 2 2 2 2 2", interfaceName),
+			fmt.Sprintf("tc qdisc add dev %s parent 1:1 handle 10: netem %s", interfaceName, expectedNetemArguments))
+		for _, source := range sourcesToFilter {
+			ipProtocol := "ip6"
+			if isIPv4(source) {
+				ipProtocol = "ip"
+			}
+			expectedCommands = append(expectedCommands, fmt.Sprintf(
+				"tc filter add dev %s protocol all parent 1:0 prio 1 u32 match %s dst %s flowid 1:3",
+				interfaceName, ipProtocol, source))
+		}
+		// All the sources are directed to the single netem qdisc, there are no flow hash filters.
+		for _, source := range sources {
+			ipProtocol := "ip6"
+			if isIPv4(source) {
+				ipProtocol = "ip"
+			}
+			expectedCommands = append(expectedCommands, fmt.Sprintf(
+				"tc filter add dev %s protocol all parent 1:0 prio 2 u32 match %s dst %s flowid 1:1",
+				interfaceName, ipProtocol, source))
+		}
+	}
+	expectations = addExpectedCommandsWithPrefix(exec, nsenterPrefix, expectedCommands, expectations, mockCMD)
+	gomock.InOrder(expectations...)
+}
+
 // Adds exec expectations
 func addExpectedCommandsWithPrefix(exec *mock_execwrapper.MockExec, nsenterPrefix string, expectedCommands []string, expectations []*gomock.Call, mockCMD *mock_execwrapper.MockCmd) []*gomock.Call {
 	for _, expectedCommand := range expectedCommands {
@@ -409,7 +493,7 @@ func setStopTCFaultExpectations(
 	for _, iface := range interfaces {
 		expectations = append(expectations,
 			exec.EXPECT().
-				CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", iface, "parent", "100:1"}).
+				CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", iface}).
 				Return(mockCMD),
 			mockCMD.EXPECT().CombinedOutput().Return([]byte(lossExistsCommandOutput), nil),
 		)
@@ -529,6 +613,42 @@ func testNetworkFaultInjectionCommon(t *testing.T,
 			case NetworkFaultPath(types.PacketLossFaultType, types.CheckNetworkFaultPostfix):
 				tmdsAPI = "/api/%s/fault/v1/network-packet-loss/status"
 				handleMethod = handler.CheckNetworkPacketLoss()
+			case NetworkFaultPath(types.BandwidthFaultType, types.StartNetworkFaultPostfix):
+				tmdsAPI = "/api/%s/fault/v1/network-bandwidth/start"
+				handleMethod = handler.StartNetworkBandwidth()
+			case NetworkFaultPath(types.BandwidthFaultType, types.StopNetworkFaultPostfix):
+				tmdsAPI = "/api/%s/fault/v1/network-bandwidth/stop"
+				handleMethod = handler.StopNetworkBandwidth()
+			case NetworkFaultPath(types.BandwidthFaultType, types.CheckNetworkFaultPostfix):
+				tmdsAPI = "/api/%s/fault/v1/network-bandwidth/status"
+				handleMethod = handler.CheckNetworkBandwidth()
+			case NetworkFaultPath(types.CorruptionFaultType, types.StartNetworkFaultPostfix):
+				tmdsAPI = "/api/%s/fault/v1/network-corruption/start"
+				handleMethod = handler.StartNetworkCorruption()
+			case NetworkFaultPath(types.CorruptionFaultType, types.StopNetworkFaultPostfix):
+				tmdsAPI = "/api/%s/fault/v1/network-corruption/stop"
+				handleMethod = handler.StopNetworkCorruption()
+			case NetworkFaultPath(types.CorruptionFaultType, types.CheckNetworkFaultPostfix):
+				tmdsAPI = "/api/%s/fault/v1/network-corruption/status"
+				handleMethod = handler.CheckNetworkCorruption()
+			case NetworkFaultPath(types.DuplicationFaultType, types.StartNetworkFaultPostfix):
+				tmdsAPI = "/api/%s/fault/v1/network-duplication/start"
+				handleMethod = handler.StartNetworkDuplication()
+			case NetworkFaultPath(types.DuplicationFaultType, types.StopNetworkFaultPostfix):
+				tmdsAPI = "/api/%s/fault/v1/network-duplication/stop"
+				handleMethod = handler.StopNetworkDuplication()
+			case NetworkFaultPath(types.DuplicationFaultType, types.CheckNetworkFaultPostfix):
+				tmdsAPI = "/api/%s/fault/v1/network-duplication/status"
+				handleMethod = handler.CheckNetworkDuplication()
+			case NetworkFaultPath(types.ReorderFaultType, types.StartNetworkFaultPostfix):
+				tmdsAPI = "/api/%s/fault/v1/network-reorder/start"
+				handleMethod = handler.StartNetworkReorder()
+			case NetworkFaultPath(types.ReorderFaultType, types.StopNetworkFaultPostfix):
+				tmdsAPI = "/api/%s/fault/v1/network-reorder/stop"
+				handleMethod = handler.StopNetworkReorder()
+			case NetworkFaultPath(types.ReorderFaultType, types.CheckNetworkFaultPostfix):
+				tmdsAPI = "/api/%s/fault/v1/network-reorder/status"
+				handleMethod = handler.CheckNetworkReorder()
 			default:
 				t.Error("Unrecognized TMDS Endpoint")
 			}
@@ -2092,7 +2212,7 @@ func generateStartNetworkLatencyTestCases() []networkFaultInjectionTestCase {
 			name:                 "existing-network-latency-fault",
 			expectedStatusCode:   409,
 			requestBody:          happyNetworkLatencyReqBody,
-			expectedResponseBody: types.NewNetworkFaultInjectionErrorResponse(latencyFaultAlreadyRunningError),
+			expectedResponseBody: types.NewNetworkFaultInjectionErrorResponse(faultAlreadyRunningError(types.LatencyFaultType)),
 			setAgentStateExpectations: func(agentState *mock_state.MockAgentState, netConfigClient *netconfig.NetworkConfigClient) {
 				agentState.EXPECT().GetTaskMetadataWithTaskNetworkConfig(endpointId, netConfigClient).Return(happyTaskResponse, nil)
 			},
@@ -2103,13 +2223,13 @@ func generateStartNetworkLatencyTestCases() []networkFaultInjectionTestCase {
 				exec.EXPECT().CommandContext(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(mockCMD)
 				mockCMD.EXPECT().CombinedOutput().Times(1).Return([]byte(tcLatencyFaultExistsCommandOutput), nil)
 			},
-			expectedResponseJSON: fmt.Sprintf(errorResponse, latencyFaultAlreadyRunningError),
+			expectedResponseJSON: fmt.Sprintf(errorResponse, faultAlreadyRunningError(types.LatencyFaultType)),
 		},
 		{
 			name:                 "existing-network-latency-fault-with-0-delay",
 			expectedStatusCode:   409,
 			requestBody:          happyNetworkLatencyReqBody,
-			expectedResponseBody: types.NewNetworkFaultInjectionErrorResponse(latencyFaultAlreadyRunningError),
+			expectedResponseBody: types.NewNetworkFaultInjectionErrorResponse(faultAlreadyRunningError(types.LatencyFaultType)),
 			setAgentStateExpectations: func(agentState *mock_state.MockAgentState, netConfigClient *netconfig.NetworkConfigClient) {
 				agentState.EXPECT().GetTaskMetadataWithTaskNetworkConfig(endpointId, netConfigClient).Return(happyTaskResponse, nil)
 			},
@@ -2120,13 +2240,13 @@ func generateStartNetworkLatencyTestCases() []networkFaultInjectionTestCase {
 				exec.EXPECT().CommandContext(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(mockCMD)
 				mockCMD.EXPECT().CombinedOutput().Times(1).Return([]byte(tcLatencyFaultWithZeroDelayExistsCommandOutput), nil)
 			},
-			expectedResponseJSON: fmt.Sprintf(errorResponse, latencyFaultAlreadyRunningError),
+			expectedResponseJSON: fmt.Sprintf(errorResponse, faultAlreadyRunningError(types.LatencyFaultType)),
 		},
 		{
 			name:                 "existing-network-packet-loss-fault",
 			expectedStatusCode:   409,
 			requestBody:          happyNetworkLatencyReqBody,
-			expectedResponseBody: types.NewNetworkFaultInjectionErrorResponse(packetLossFaultAlreadyRunningError),
+			expectedResponseBody: types.NewNetworkFaultInjectionErrorResponse(faultAlreadyRunningError(types.PacketLossFaultType)),
 			setAgentStateExpectations: func(agentState *mock_state.MockAgentState, netConfigClient *netconfig.NetworkConfigClient) {
 				agentState.EXPECT().GetTaskMetadataWithTaskNetworkConfig(endpointId, netConfigClient).Return(happyTaskResponse, nil)
 			},
@@ -2137,7 +2257,7 @@ func generateStartNetworkLatencyTestCases() []networkFaultInjectionTestCase {
 				exec.EXPECT().CommandContext(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(mockCMD)
 				mockCMD.EXPECT().CombinedOutput().Times(1).Return([]byte(tcLossFaultExistsCommandOutput), nil)
 			},
-			expectedResponseJSON: fmt.Sprintf(errorResponse, packetLossFaultAlreadyRunningError),
+			expectedResponseJSON: fmt.Sprintf(errorResponse, faultAlreadyRunningError(types.PacketLossFaultType)),
 		},
 		{
 			name:               "unknown-request-body-no-existing-fault",
@@ -2569,7 +2689,7 @@ func generateCheckNetworkLatencyTestCases() []networkFaultInjectionTestCase {
 				for _, iface := range []string{"eth0", "eth1"} {
 					expectations = append(expectations,
 						exec.EXPECT().
-							CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", iface, "parent", "100:1"}).
+							CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", iface}).
 							Return(mockCMD),
 						mockCMD.EXPECT().CombinedOutput().Return([]byte(tcLatencyFaultExistsCommandOutput), nil),
 					)
@@ -2883,7 +3003,7 @@ func generateStartNetworkPacketLossTestCases() []networkFaultInjectionTestCase {
 			name:                 "existing-network-latency-fault",
 			expectedStatusCode:   409,
 			requestBody:          happyNetworkPacketLossReqBody,
-			expectedResponseBody: types.NewNetworkFaultInjectionErrorResponse(latencyFaultAlreadyRunningError),
+			expectedResponseBody: types.NewNetworkFaultInjectionErrorResponse(faultAlreadyRunningError(types.LatencyFaultType)),
 			setAgentStateExpectations: func(agentState *mock_state.MockAgentState, netConfigClient *netconfig.NetworkConfigClient) {
 				agentState.EXPECT().GetTaskMetadataWithTaskNetworkConfig(endpointId, netConfigClient).Return(happyTaskResponse, nil)
 			},
@@ -2894,13 +3014,13 @@ func generateStartNetworkPacketLossTestCases() []networkFaultInjectionTestCase {
 				exec.EXPECT().CommandContext(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(mockCMD)
 				mockCMD.EXPECT().CombinedOutput().Times(1).Return([]byte(tcLatencyFaultExistsCommandOutput), nil)
 			},
-			expectedResponseJSON: fmt.Sprintf(errorResponse, latencyFaultAlreadyRunningError),
+			expectedResponseJSON: fmt.Sprintf(errorResponse, faultAlreadyRunningError(types.LatencyFaultType)),
 		},
 		{
 			name:                 "existing-network-latency-fault-with-0-delay",
 			expectedStatusCode:   409,
 			requestBody:          happyNetworkPacketLossReqBody,
-			expectedResponseBody: types.NewNetworkFaultInjectionErrorResponse(latencyFaultAlreadyRunningError),
+			expectedResponseBody: types.NewNetworkFaultInjectionErrorResponse(faultAlreadyRunningError(types.LatencyFaultType)),
 			setAgentStateExpectations: func(agentState *mock_state.MockAgentState, netConfigClient *netconfig.NetworkConfigClient) {
 				agentState.EXPECT().GetTaskMetadataWithTaskNetworkConfig(endpointId, netConfigClient).Return(happyTaskResponse, nil)
 			},
@@ -2911,13 +3031,13 @@ func generateStartNetworkPacketLossTestCases() []networkFaultInjectionTestCase {
 				exec.EXPECT().CommandContext(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(mockCMD)
 				mockCMD.EXPECT().CombinedOutput().Times(1).Return([]byte(tcLatencyFaultWithZeroDelayExistsCommandOutput), nil)
 			},
-			expectedResponseJSON: fmt.Sprintf(errorResponse, latencyFaultAlreadyRunningError),
+			expectedResponseJSON: fmt.Sprintf(errorResponse, faultAlreadyRunningError(types.LatencyFaultType)),
 		},
 		{
 			name:                 "existing-network-packet-loss-fault",
 			expectedStatusCode:   409,
 			requestBody:          happyNetworkPacketLossReqBody,
-			expectedResponseBody: types.NewNetworkFaultInjectionErrorResponse(packetLossFaultAlreadyRunningError),
+			expectedResponseBody: types.NewNetworkFaultInjectionErrorResponse(faultAlreadyRunningError(types.PacketLossFaultType)),
 			setAgentStateExpectations: func(agentState *mock_state.MockAgentState, netConfigClient *netconfig.NetworkConfigClient) {
 				agentState.EXPECT().GetTaskMetadataWithTaskNetworkConfig(endpointId, netConfigClient).Return(happyTaskResponse, nil)
 			},
@@ -2928,7 +3048,7 @@ func generateStartNetworkPacketLossTestCases() []networkFaultInjectionTestCase {
 				exec.EXPECT().CommandContext(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(mockCMD)
 				mockCMD.EXPECT().CombinedOutput().Times(1).Return([]byte(tcLossFaultExistsCommandOutput), nil)
 			},
-			expectedResponseJSON: fmt.Sprintf(errorResponse, packetLossFaultAlreadyRunningError),
+			expectedResponseJSON: fmt.Sprintf(errorResponse, faultAlreadyRunningError(types.PacketLossFaultType)),
 		},
 		{
 			name:               "unknown-request-body-no-existing-fault-no-allowlist-filter",
@@ -3322,7 +3442,7 @@ func generateCheckNetworkPacketLossTestCases() []networkFaultInjectionTestCase {
 				for _, iface := range []string{"eth0", "eth1"} {
 					expectations = append(expectations,
 						exec.EXPECT().
-							CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", iface, "parent", "100:1"}).
+							CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", iface}).
 							Return(mockCMD),
 						mockCMD.EXPECT().CombinedOutput().Return([]byte(tcLossFaultExistsCommandOutput), nil),
 					)
@@ -3532,122 +3652,172 @@ func TestCheckTCFault(t *testing.T) {
 	tests := []struct {
 		name                string
 		deviceNames         []string
-		expectedLatency     bool
-		expectedPacketLoss  bool
+		expectedFaultType   string
 		expectedError       error
 		commandExpectations func(*mock_execwrapper.MockExec, *mock_execwrapper.MockCmd)
 	}{
 		{
-			name:               "No faults exist",
-			deviceNames:        []string{"eth0"},
-			expectedLatency:    false,
-			expectedPacketLoss: false,
-			expectedError:      nil,
+			name:              "No faults exist",
+			deviceNames:       []string{"eth0"},
+			expectedFaultType: "",
+			expectedError:     nil,
 			commandExpectations: func(exec *mock_execwrapper.MockExec, cmd *mock_execwrapper.MockCmd) {
-				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0", "parent", "100:1"}).Return(cmd)
+				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0"}).Return(cmd)
 				cmd.EXPECT().CombinedOutput().Return([]byte(tcCommandEmptyOutput), nil)
 			},
 		},
 		{
-			name:               "Latency fault exists",
-			deviceNames:        []string{"eth0"},
-			expectedLatency:    true,
-			expectedPacketLoss: false,
-			expectedError:      nil,
+			name:              "Latency fault exists",
+			deviceNames:       []string{"eth0"},
+			expectedFaultType: types.LatencyFaultType,
+			expectedError:     nil,
 			commandExpectations: func(exec *mock_execwrapper.MockExec, cmd *mock_execwrapper.MockCmd) {
-				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0", "parent", "100:1"}).Return(cmd)
+				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0"}).Return(cmd)
 				cmd.EXPECT().CombinedOutput().Return([]byte(tcLatencyFaultExistsCommandOutput), nil)
 			},
 		},
 		{
-			name:               "Latency fault with 0 delay exists",
-			deviceNames:        []string{"eth0"},
-			expectedLatency:    true,
-			expectedPacketLoss: false,
-			expectedError:      nil,
+			name:              "Latency fault exists on the flow bands",
+			deviceNames:       []string{"eth0"},
+			expectedFaultType: types.LatencyFaultType,
+			expectedError:     nil,
 			commandExpectations: func(exec *mock_execwrapper.MockExec, cmd *mock_execwrapper.MockCmd) {
-				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0", "parent", "100:1"}).Return(cmd)
+				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0"}).Return(cmd)
+				cmd.EXPECT().CombinedOutput().Return([]byte(tcFlowBandLatencyFaultExistsCommandOutput), nil)
+			},
+		},
+		{
+			name:              "Netem qdisc not added by a fault",
+			deviceNames:       []string{"eth0"},
+			expectedFaultType: "",
+			expectedError:     nil,
+			commandExpectations: func(exec *mock_execwrapper.MockExec, cmd *mock_execwrapper.MockCmd) {
+				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0"}).Return(cmd)
+				cmd.EXPECT().CombinedOutput().Return([]byte(tcUnrelatedNetemCommandOutput), nil)
+			},
+		},
+		{
+			name:              "Latency fault with 0 delay exists",
+			deviceNames:       []string{"eth0"},
+			expectedFaultType: types.LatencyFaultType,
+			expectedError:     nil,
+			commandExpectations: func(exec *mock_execwrapper.MockExec, cmd *mock_execwrapper.MockCmd) {
+				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0"}).Return(cmd)
 				cmd.EXPECT().CombinedOutput().Return([]byte(tcLatencyFaultWithZeroDelayExistsCommandOutput), nil)
 			},
 		},
 		{
-			name:               "Packet loss fault exists",
-			deviceNames:        []string{"eth0"},
-			expectedLatency:    false,
-			expectedPacketLoss: true,
-			expectedError:      nil,
+			name:              "Packet loss fault exists",
+			deviceNames:       []string{"eth0"},
+			expectedFaultType: types.PacketLossFaultType,
+			expectedError:     nil,
 			commandExpectations: func(exec *mock_execwrapper.MockExec, cmd *mock_execwrapper.MockCmd) {
-				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0", "parent", "100:1"}).Return(cmd)
+				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0"}).Return(cmd)
 				cmd.EXPECT().CombinedOutput().Return([]byte(tcLossFaultExistsCommandOutput), nil)
 			},
 		},
 		{
-			name:               "Command execution error",
-			deviceNames:        []string{"eth0"},
-			expectedLatency:    false,
-			expectedPacketLoss: false,
-			expectedError:      errors.New("failed to check existing network fault: 'tc -j q show dev eth0 parent 100:1' command failed with the following error: 'command failed'. std output: ''. TaskArn: test-task"),
+			name:              "Bandwidth fault exists",
+			deviceNames:       []string{"eth0"},
+			expectedFaultType: types.BandwidthFaultType,
+			expectedError:     nil,
 			commandExpectations: func(exec *mock_execwrapper.MockExec, cmd *mock_execwrapper.MockCmd) {
-				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0", "parent", "100:1"}).Return(cmd)
+				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0"}).Return(cmd)
+				cmd.EXPECT().CombinedOutput().Return([]byte(tcBandwidthFaultExistsCommandOutput), nil)
+			},
+		},
+		{
+			name:              "Corruption fault exists",
+			deviceNames:       []string{"eth0"},
+			expectedFaultType: types.CorruptionFaultType,
+			expectedError:     nil,
+			commandExpectations: func(exec *mock_execwrapper.MockExec, cmd *mock_execwrapper.MockCmd) {
+				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0"}).Return(cmd)
+				cmd.EXPECT().CombinedOutput().Return([]byte(tcCorruptionFaultExistsCommandOutput), nil)
+			},
+		},
+		{
+			name:              "Duplication fault exists",
+			deviceNames:       []string{"eth0"},
+			expectedFaultType: types.DuplicationFaultType,
+			expectedError:     nil,
+			commandExpectations: func(exec *mock_execwrapper.MockExec, cmd *mock_execwrapper.MockCmd) {
+				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0"}).Return(cmd)
+				cmd.EXPECT().CombinedOutput().Return([]byte(tcDuplicationFaultExistsCommandOutput), nil)
+			},
+		},
+		{
+			name:              "Reorder fault exists",
+			deviceNames:       []string{"eth0"},
+			expectedFaultType: types.ReorderFaultType,
+			expectedError:     nil,
+			commandExpectations: func(exec *mock_execwrapper.MockExec, cmd *mock_execwrapper.MockCmd) {
+				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0"}).Return(cmd)
+				cmd.EXPECT().CombinedOutput().Return([]byte(tcReorderFaultExistsCommandOutput), nil)
+			},
+		},
+		{
+			name:              "Command execution error",
+			deviceNames:       []string{"eth0"},
+			expectedFaultType: "",
+			expectedError:     errors.New("failed to check existing network fault: 'tc -j q show dev eth0' command failed with the following error: 'command failed'. std output: ''. TaskArn: test-task"),
+			commandExpectations: func(exec *mock_execwrapper.MockExec, cmd *mock_execwrapper.MockCmd) {
+				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0"}).Return(cmd)
 				cmd.EXPECT().CombinedOutput().Return([]byte(""), errors.New("command failed"))
 			},
 		},
 		{
-			name:               "Invalid JSON output",
-			deviceNames:        []string{"eth0"},
-			expectedLatency:    false,
-			expectedPacketLoss: false,
-			expectedError:      errors.New("failed to check existing network fault: failed to unmarshal tc command output: invalid character 'i' looking for beginning of value. TaskArn: test-task"),
+			name:              "Invalid JSON output",
+			deviceNames:       []string{"eth0"},
+			expectedFaultType: "",
+			expectedError:     errors.New("failed to check existing network fault: failed to unmarshal tc command output: invalid character 'i' looking for beginning of value. TaskArn: test-task"),
 			commandExpectations: func(exec *mock_execwrapper.MockExec, cmd *mock_execwrapper.MockCmd) {
-				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0", "parent", "100:1"}).Return(cmd)
+				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0"}).Return(cmd)
 				cmd.EXPECT().CombinedOutput().Return([]byte("invalid json"), nil)
 			},
 		},
 		{
-			name:               "Multiple network interfaces - no faults",
-			deviceNames:        []string{"eth0", "eth1"},
-			expectedLatency:    false,
-			expectedPacketLoss: false,
-			expectedError:      nil,
+			name:              "Multiple network interfaces - no faults",
+			deviceNames:       []string{"eth0", "eth1"},
+			expectedFaultType: "",
+			expectedError:     nil,
 			commandExpectations: func(exec *mock_execwrapper.MockExec, cmd *mock_execwrapper.MockCmd) {
 				// First interface check
-				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0", "parent", "100:1"}).Return(cmd)
+				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0"}).Return(cmd)
 				cmd.EXPECT().CombinedOutput().Return([]byte(tcCommandEmptyOutput), nil)
 
 				// Second interface check
-				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth1", "parent", "100:1"}).Return(cmd)
+				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth1"}).Return(cmd)
 				cmd.EXPECT().CombinedOutput().Return([]byte(tcCommandEmptyOutput), nil)
 			},
 		},
 		{
-			name:               "Multiple network interfaces - latency fault on second interface",
-			deviceNames:        []string{"eth0", "eth1"},
-			expectedLatency:    true,
-			expectedPacketLoss: false,
-			expectedError:      nil,
+			name:              "Multiple network interfaces - latency fault on second interface",
+			deviceNames:       []string{"eth0", "eth1"},
+			expectedFaultType: types.LatencyFaultType,
+			expectedError:     nil,
 			commandExpectations: func(exec *mock_execwrapper.MockExec, cmd *mock_execwrapper.MockCmd) {
 				// First interface check - no fault
-				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0", "parent", "100:1"}).Return(cmd)
+				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0"}).Return(cmd)
 				cmd.EXPECT().CombinedOutput().Return([]byte(tcCommandEmptyOutput), nil)
 
 				// Second interface check - has fault
-				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth1", "parent", "100:1"}).Return(cmd)
+				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth1"}).Return(cmd)
 				cmd.EXPECT().CombinedOutput().Return([]byte(tcLatencyFaultExistsCommandOutput), nil)
 			},
 		},
 		{
-			name:               "Multiple network interfaces - packet loss fault on second interface",
-			deviceNames:        []string{"eth0", "eth1"},
-			expectedLatency:    false,
-			expectedPacketLoss: true,
-			expectedError:      nil,
+			name:              "Multiple network interfaces - packet loss fault on second interface",
+			deviceNames:       []string{"eth0", "eth1"},
+			expectedFaultType: types.PacketLossFaultType,
+			expectedError:     nil,
 			commandExpectations: func(exec *mock_execwrapper.MockExec, cmd *mock_execwrapper.MockCmd) {
 				// First interface check - no fault
-				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0", "parent", "100:1"}).Return(cmd)
+				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth0"}).Return(cmd)
 				cmd.EXPECT().CombinedOutput().Return([]byte(tcCommandEmptyOutput), nil)
 
 				// Second interface check - has fault
-				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth1", "parent", "100:1"}).Return(cmd)
+				exec.EXPECT().CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", "eth1"}).Return(cmd)
 				cmd.EXPECT().CombinedOutput().Return([]byte(tcLossFaultExistsCommandOutput), nil)
 			},
 		},
@@ -3668,7 +3838,7 @@ func TestCheckTCFault(t *testing.T) {
 			tc.commandExpectations(mockExec, mockCmd)
 
 			taskMetadata := createTestTaskMetadata(tc.deviceNames)
-			latencyExists, packetLossExists, err := handler.checkTCFault(context.Background(), taskMetadata)
+			faultType, err := handler.checkTCFault(context.Background(), taskMetadata)
 
 			if tc.expectedError != nil {
 				assert.EqualError(t, err, tc.expectedError.Error())
@@ -3676,8 +3846,142 @@ func TestCheckTCFault(t *testing.T) {
 				assert.NoError(t, err)
 			}
 
-			assert.Equal(t, tc.expectedLatency, latencyExists)
-			assert.Equal(t, tc.expectedPacketLoss, packetLossExists)
+			assert.Equal(t, tc.expectedFaultType, faultType)
+		})
+	}
+}
+
+// generateNetemFaultTestCases generates the start, stop and check test cases of a network fault injected with
+// tc netem. faultExistsCommandOutput is the tc command output when the fault is running.
+func generateNetemFaultTestCases(requestBody map[string]interface{}, netemArguments, faultExistsCommandOutput string,
+	aggregate bool,
+) (startTcs, stopTcs, checkTcs []networkFaultInjectionTestCase) {
+	interfaces := []string{deviceName, deviceName2}
+	setAgentStateExpectations := func(agentState *mock_state.MockAgentState, netConfigClient *netconfig.NetworkConfigClient) {
+		agentState.EXPECT().
+			GetTaskMetadataWithTaskNetworkConfig(endpointId, netConfigClient).
+			Return(happyTaskResponseTwoInterfaces, nil)
+	}
+	startTcs = []networkFaultInjectionTestCase{
+		{
+			name:                      "no-existing-fault",
+			expectedStatusCode:        200,
+			requestBody:               requestBody,
+			expectedResponseBody:      types.NewNetworkFaultInjectionSuccessResponse("running"),
+			setAgentStateExpectations: setAgentStateExpectations,
+			setExecExpectations: func(exec *mock_execwrapper.MockExec, ctrl *gomock.Controller) {
+				if aggregate {
+					setStartTCAggregateFaultExpectations(exec, ctrl, interfaces, netemArguments, ipSourcesToFilter,
+						ipSources, noNsenterPrefix)
+					return
+				}
+				setStartTCFaultExpectations(exec, ctrl, interfaces, netemArguments, ipSourcesToFilter, ipSources,
+					flowsPercent, noNsenterPrefix)
+			},
+			expectedResponseJSON: happyFaultRunningResponse,
+		},
+		{
+			name:                      "existing-network-packet-loss-fault",
+			expectedStatusCode:        409,
+			requestBody:               requestBody,
+			expectedResponseBody:      types.NewNetworkFaultInjectionErrorResponse(faultAlreadyRunningError(types.PacketLossFaultType)),
+			setAgentStateExpectations: setAgentStateExpectations,
+			setExecExpectations: func(exec *mock_execwrapper.MockExec, ctrl *gomock.Controller) {
+				setCheckTCFaultExpectations(exec, ctrl, interfaces, tcLossFaultExistsCommandOutput)
+			},
+			expectedResponseJSON: fmt.Sprintf(errorResponse, faultAlreadyRunningError(types.PacketLossFaultType)),
+		},
+	}
+	stopTcs = []networkFaultInjectionTestCase{
+		{
+			name:                      "existing-fault",
+			expectedStatusCode:        200,
+			expectedResponseBody:      types.NewNetworkFaultInjectionSuccessResponse("stopped"),
+			setAgentStateExpectations: setAgentStateExpectations,
+			setExecExpectations: func(exec *mock_execwrapper.MockExec, ctrl *gomock.Controller) {
+				setStopTCFaultExpectations(exec, ctrl, interfaces, faultExistsCommandOutput)
+			},
+			expectedResponseJSON: happyFaultStoppedResponse,
+		},
+		{
+			name:                      "existing-network-packet-loss-fault",
+			expectedStatusCode:        200,
+			expectedResponseBody:      types.NewNetworkFaultInjectionSuccessResponse("stopped"),
+			setAgentStateExpectations: setAgentStateExpectations,
+			setExecExpectations: func(exec *mock_execwrapper.MockExec, ctrl *gomock.Controller) {
+				setCheckTCFaultExpectations(exec, ctrl, interfaces, tcLossFaultExistsCommandOutput)
+			},
+			expectedResponseJSON: happyFaultStoppedResponse,
+		},
+	}
+	checkTcs = []networkFaultInjectionTestCase{
+		{
+			name:                      "existing-fault",
+			expectedStatusCode:        200,
+			expectedResponseBody:      types.NewNetworkFaultInjectionSuccessResponse("running"),
+			setAgentStateExpectations: setAgentStateExpectations,
+			setExecExpectations: func(exec *mock_execwrapper.MockExec, ctrl *gomock.Controller) {
+				setCheckTCFaultExpectations(exec, ctrl, interfaces, faultExistsCommandOutput)
+			},
+			expectedResponseJSON: happyFaultRunningResponse,
+		},
+		{
+			name:                      "existing-network-latency-fault",
+			expectedStatusCode:        200,
+			expectedResponseBody:      types.NewNetworkFaultInjectionSuccessResponse("not-running"),
+			setAgentStateExpectations: setAgentStateExpectations,
+			setExecExpectations: func(exec *mock_execwrapper.MockExec, ctrl *gomock.Controller) {
+				setCheckTCFaultExpectations(exec, ctrl, interfaces, tcLatencyFaultExistsCommandOutput)
+			},
+			expectedResponseJSON: happyFaultNotRunningResponse,
+		},
+	}
+	return startTcs, stopTcs, checkTcs
+}
+
+// TestNetemFaults tests the start, stop and check APIs of the network faults injected with tc netem
+// other than latency and packet loss.
+func TestNetemFaults(t *testing.T) {
+	testCases := []struct {
+		faultType                string
+		requestBody              map[string]interface{}
+		netemArguments           string
+		faultExistsCommandOutput string
+		aggregate                bool
+	}{
+		{
+			faultType:                types.BandwidthFaultType,
+			requestBody:              happyNetworkBandwidthReqBody,
+			netemArguments:           fmt.Sprintf("rate %dkbit", rateKbps),
+			faultExistsCommandOutput: tcBandwidthFaultExistsCommandOutput,
+			aggregate:                true,
+		},
+		{
+			faultType:                types.CorruptionFaultType,
+			requestBody:              happyNetworkCorruptionReqBody,
+			netemArguments:           fmt.Sprintf("corrupt %d%%", lossPercent),
+			faultExistsCommandOutput: tcCorruptionFaultExistsCommandOutput,
+		},
+		{
+			faultType:                types.DuplicationFaultType,
+			requestBody:              happyNetworkDuplicationReqBody,
+			netemArguments:           fmt.Sprintf("duplicate %d%%", lossPercent),
+			faultExistsCommandOutput: tcDuplicationFaultExistsCommandOutput,
+		},
+		{
+			faultType:                types.ReorderFaultType,
+			requestBody:              happyNetworkReorderReqBody,
+			netemArguments:           fmt.Sprintf("delay %dms reorder %d%%", reorderDelayMilliseconds, lossPercent),
+			faultExistsCommandOutput: tcReorderFaultExistsCommandOutput,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.faultType, func(t *testing.T) {
+			startTcs, stopTcs, checkTcs := generateNetemFaultTestCases(tc.requestBody, tc.netemArguments,
+				tc.faultExistsCommandOutput, tc.aggregate)
+			testNetworkFaultInjectionCommon(t, startTcs, NetworkFaultPath(tc.faultType, types.StartNetworkFaultPostfix))
+			testNetworkFaultInjectionCommon(t, stopTcs, NetworkFaultPath(tc.faultType, types.StopNetworkFaultPostfix))
+			testNetworkFaultInjectionCommon(t, checkTcs, NetworkFaultPath(tc.faultType, types.CheckNetworkFaultPostfix))
 		})
 	}
 }
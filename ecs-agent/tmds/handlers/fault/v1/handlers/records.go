@@ -0,0 +1,381 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/audit"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/audit/request"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/types"
+	v2 "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/v2"
+	state "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/v4/state"
+
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// FaultStore persists the records of the faults injected by the FaultHandler.
+type FaultStore interface {
+	// SaveFault saves the record of an injected fault.
+	SaveFault(*types.InjectedFault) error
+	// DeleteFault deletes the record of an injected fault.
+	DeleteFault(string) error
+	// GetFaults gets the records of all the injected faults.
+	GetFaults() ([]*types.InjectedFault, error)
+}
+
+// faultID returns the ID of a fault within the given lock key, see faultLockKey. The fault is identified by the
+// chain name for network blackhole port faults and by the fault type for the other faults.
+func faultID(lockKey, fault string) string {
+	return fmt.Sprintf("%s/%s", lockKey, fault)
+}
+
+// faultLockKey returns the key of the lock guarding the resource the fault is injected into. It's the task ARN
+// for the faults scoped to the task cgroup, since host and bridge mode tasks share their network namespace, and
+// the network namespace path for network faults.
+func faultLockKey(fault *types.InjectedFault) string {
+	if isTaskCgroupFault(fault.FaultType) {
+		return fault.TaskARN
+	}
+	return fault.NetworkNamespacePath()
+}
+
+// isTaskCgroupFault returns whether the fault is injected into the task cgroup rather than into the task network
+// namespace. These faults don't depend on the task network configuration.
+func isTaskCgroupFault(faultType string) bool {
+	return faultType == types.CPUStressFaultType || faultType == types.MemoryPressureFaultType
+}
+
+// blackholeChainName returns the name of the iptables chain of a network blackhole port fault.
+func blackholeChainName(trafficType, protocol, port string) string {
+	return fmt.Sprintf("%s-%s-%s", trafficType, protocol, port)
+}
+
+// blackholeInsertTable returns the built-in table the chain of a network blackhole port fault is inserted into.
+func blackholeInsertTable(trafficType string) string {
+	if trafficType == types.TrafficTypeEgress {
+		return "OUTPUT"
+	}
+	return "INPUT"
+}
+
+// newInjectedFault creates the record of a fault started for the task with the given request parameters. The
+// fault expires after durationSeconds if set.
+func (h *FaultHandler) newInjectedFault(taskMetadata *state.TaskResponse, faultType, id, parameters string,
+	durationSeconds *uint64) *types.InjectedFault {
+	now := h.getTime().Now()
+	fault := &types.InjectedFault{
+		ID:                id,
+		TaskARN:           taskMetadata.TaskARN,
+		FaultType:         faultType,
+		TaskNetworkConfig: taskMetadata.TaskNetworkConfig,
+		Parameters:        parameters,
+		StartedAt:         now,
+	}
+	if durationSeconds != nil {
+		expiresAt := now.Add(time.Duration(*durationSeconds) * time.Second)
+		fault.ExpiresAt = &expiresAt
+	}
+	return fault
+}
+
+// recordFault keeps track of a started fault, persists it and schedules its expiry if it has one.
+//
+// The caller is expected to hold the lock of the fault, see faultLockKey.
+func (h *FaultHandler) recordFault(fault *types.InjectedFault) {
+	h.trackFault(fault)
+	if h.store != nil {
+		if err := h.store.SaveFault(fault); err != nil {
+			logger.Error("Failed to save injected fault", logger.Fields{
+				field.TaskARN: fault.TaskARN,
+				"fault":       fault.ID,
+				field.Error:   err,
+			})
+		}
+	}
+	h.scheduleFaultExpiry(fault)
+}
+
+// trackFault keeps track of the fault in memory only.
+func (h *FaultHandler) trackFault(fault *types.InjectedFault) {
+	h.faultsLock.Lock()
+	defer h.faultsLock.Unlock()
+	if h.faults == nil {
+		h.faults = make(map[string]*types.InjectedFault)
+	}
+	h.faults[fault.ID] = fault
+}
+
+// forgetFault stops tracking a fault that is no longer injected.
+//
+// The caller is expected to hold the lock of the fault, see faultLockKey.
+func (h *FaultHandler) forgetFault(id string) {
+	h.cancelFaultExpiry(id)
+	h.faultsLock.Lock()
+	delete(h.faults, id)
+	h.faultsLock.Unlock()
+	if h.store != nil {
+		if err := h.store.DeleteFault(id); err != nil {
+			logger.Error("Failed to delete injected fault", logger.Fields{
+				"fault":     id,
+				field.Error: err,
+			})
+		}
+	}
+}
+
+// faultParameters returns the parameters the tracked fault was started with, if any.
+func (h *FaultHandler) faultParameters(id string) string {
+	h.faultsLock.Lock()
+	defer h.faultsLock.Unlock()
+	if fault, ok := h.faults[id]; ok {
+		return fault.Parameters
+	}
+	return ""
+}
+
+// auditFault records a fault event in the audit log. The request is nil for the events which weren't caused by
+// a request, i.e. when faults expire or are cleaned up.
+func (h *FaultHandler) auditFault(r *http.Request, taskARN string, httpResponseCode int,
+	eventType, faultType, parameters string) {
+	if h.auditLogger == nil {
+		return
+	}
+	h.auditLogger.LogFault(request.LogRequest{Request: r, ARN: taskARN}, httpResponseCode, eventType, faultType,
+		parameters)
+}
+
+// taskFaults returns the faults tracked for the task.
+func (h *FaultHandler) taskFaults(taskARN string) []*types.InjectedFault {
+	h.faultsLock.Lock()
+	defer h.faultsLock.Unlock()
+	var faults []*types.InjectedFault
+	for _, fault := range h.faults {
+		if fault.TaskARN == taskARN {
+			faults = append(faults, fault)
+		}
+	}
+	return faults
+}
+
+// GetFaults returns the faults currently injected by the handler. The faults returned are copies, so that they
+// can be read while the handler keeps updating its own records.
+func (h *FaultHandler) GetFaults() []*types.InjectedFault {
+	h.faultsLock.Lock()
+	defer h.faultsLock.Unlock()
+	faults := make([]*types.InjectedFault, 0, len(h.faults))
+	for _, fault := range h.faults {
+		faultCopy := *fault
+		faults = append(faults, &faultCopy)
+	}
+	return faults
+}
+
+// ReconcileFaults loads the faults persisted before the agent restarted and reconciles them against the task
+// network namespaces. Faults of tasks that are no longer active are stopped, faults that are no longer injected
+// are forgotten and the expiry of the remaining faults is scheduled again.
+func (h *FaultHandler) ReconcileFaults(isTaskActive func(taskARN string) bool) {
+	if h.store == nil {
+		return
+	}
+	faults, err := h.store.GetFaults()
+	if err != nil {
+		logger.Error("Failed to load injected faults", logger.Fields{
+			field.Error: err,
+		})
+		return
+	}
+	for _, fault := range faults {
+		h.reconcileFault(fault, isTaskActive(fault.TaskARN))
+	}
+}
+
+func (h *FaultHandler) reconcileFault(fault *types.InjectedFault, taskActive bool) {
+	rwMu := h.loadLock(faultLockKey(fault))
+	rwMu.Lock()
+	defer rwMu.Unlock()
+
+	ctx, cancel := h.osExecWrapper.NewExecContextWithTimeout(context.Background(), requestTimeoutSeconds*time.Second)
+	defer cancel()
+
+	expired := fault.ExpiresAt != nil && !h.getTime().Now().Before(*fault.ExpiresAt)
+	if !taskActive || expired {
+		eventType := audit.CleanupFaultEventType
+		if expired {
+			eventType = audit.ExpireFaultEventType
+		}
+		httpResponseCode := http.StatusOK
+		if err := h.stopInjectedFault(ctx, fault); err != nil {
+			logger.Warn("Failed to stop injected fault while reconciling faults", logger.Fields{
+				field.TaskARN: fault.TaskARN,
+				"fault":       fault.ID,
+				field.Error:   err,
+			})
+			httpResponseCode = http.StatusInternalServerError
+		}
+		h.auditFault(nil, fault.TaskARN, httpResponseCode, eventType, fault.FaultType, fault.Parameters)
+		h.forgetFault(fault.ID)
+		return
+	}
+
+	injected, err := h.isFaultInjected(ctx, fault)
+	if err != nil {
+		// Keep tracking the fault so that it is cleaned up once the task stops.
+		logger.Warn("Failed to check injected fault while reconciling faults", logger.Fields{
+			field.TaskARN: fault.TaskARN,
+			"fault":       fault.ID,
+			field.Error:   err,
+		})
+		h.trackFault(fault)
+		return
+	}
+	if injected && h.isFaultOrphaned(fault) {
+		if err := h.stopNetworkDNSFault(ctx, injectedFaultTaskMetadata(fault), fault.DNSAction); err != nil {
+			logger.Warn("Failed to stop orphaned fault while reconciling faults", logger.Fields{
+				field.TaskARN: fault.TaskARN,
+				"fault":       fault.ID,
+				field.Error:   err,
+			})
+			h.trackFault(fault)
+			return
+		}
+		injected = false
+	}
+	if !injected {
+		h.forgetFault(fault.ID)
+		return
+	}
+	logger.Info("Restored injected fault", logger.Fields{
+		field.TaskARN: fault.TaskARN,
+		"fault":       fault.ID,
+	})
+	h.trackFault(fault)
+	h.scheduleFaultExpiry(fault)
+}
+
+// CleanupTaskFaults stops all the faults injected into the task and forgets about them. It is meant to be
+// called once the task has stopped. Faults that can't be stopped are forgotten as well since the task network
+// namespace is usually gone by then.
+func (h *FaultHandler) CleanupTaskFaults(taskARN string) {
+	for _, fault := range h.taskFaults(taskARN) {
+		h.cleanupFault(fault)
+	}
+}
+
+func (h *FaultHandler) cleanupFault(fault *types.InjectedFault) {
+	rwMu := h.loadLock(faultLockKey(fault))
+	rwMu.Lock()
+	defer rwMu.Unlock()
+
+	ctx, cancel := h.osExecWrapper.NewExecContextWithTimeout(context.Background(), requestTimeoutSeconds*time.Second)
+	defer cancel()
+	httpResponseCode := http.StatusOK
+	if err := h.stopInjectedFault(ctx, fault); err != nil {
+		logger.Warn("Failed to stop injected fault of stopped task", logger.Fields{
+			field.TaskARN: fault.TaskARN,
+			"fault":       fault.ID,
+			field.Error:   err,
+		})
+		httpResponseCode = http.StatusInternalServerError
+	} else {
+		logger.Info("Stopped injected fault of stopped task", logger.Fields{
+			field.TaskARN: fault.TaskARN,
+			"fault":       fault.ID,
+		})
+	}
+	h.auditFault(nil, fault.TaskARN, httpResponseCode, audit.CleanupFaultEventType, fault.FaultType, fault.Parameters)
+	h.forgetFault(fault.ID)
+}
+
+// injectedFaultTaskMetadata rebuilds the task metadata needed to manipulate the fault.
+func injectedFaultTaskMetadata(fault *types.InjectedFault) *state.TaskResponse {
+	return &state.TaskResponse{
+		TaskResponse:      &v2.TaskResponse{TaskARN: fault.TaskARN},
+		TaskNetworkConfig: fault.TaskNetworkConfig,
+	}
+}
+
+// stopInjectedFault stops the fault if it is still injected.
+func (h *FaultHandler) stopInjectedFault(ctx context.Context, fault *types.InjectedFault) error {
+	if isTaskCgroupFault(fault.FaultType) {
+		return h.resourceStressor.Stop(fault.TaskARN, fault.FaultType)
+	}
+	if err := validateTaskNetworkConfig(fault.TaskNetworkConfig); err != nil {
+		return err
+	}
+	taskMetadata := injectedFaultTaskMetadata(fault)
+	networkMode := ecstypes.NetworkMode(fault.TaskNetworkConfig.NetworkMode)
+	switch fault.FaultType {
+	case types.BlackHolePortFaultType:
+		_, err := h.stopNetworkBlackHolePort(ctx, fault.Protocol, fault.Port,
+			blackholeChainName(fault.TrafficType, fault.Protocol, fault.Port), networkMode,
+			fault.NetworkNamespacePath(), blackholeInsertTable(fault.TrafficType), fault.TaskARN,
+			isIPv6OnlyTask(taskMetadata))
+		return err
+	case types.LatencyFaultType, types.PacketLossFaultType, types.BandwidthFaultType, types.CorruptionFaultType,
+		types.DuplicationFaultType, types.ReorderFaultType:
+		injected, err := h.isFaultInjected(ctx, fault)
+		if err != nil || !injected {
+			return err
+		}
+		return h.stopTCFault(ctx, taskMetadata)
+	case types.DNSFaultType:
+		action, err := h.checkNetworkDNSFault(ctx, taskMetadata)
+		if err != nil || action == "" {
+			return err
+		}
+		return h.stopNetworkDNSFault(ctx, taskMetadata, action)
+	default:
+		return fmt.Errorf("unknown fault type %s", fault.FaultType)
+	}
+}
+
+// isFaultInjected checks whether the fault is still injected into the task network namespace or cgroup.
+func (h *FaultHandler) isFaultInjected(ctx context.Context, fault *types.InjectedFault) (bool, error) {
+	if isTaskCgroupFault(fault.FaultType) {
+		return h.resourceStressor.IsRunning(fault.TaskARN, fault.FaultType), nil
+	}
+	if err := validateTaskNetworkConfig(fault.TaskNetworkConfig); err != nil {
+		return false, err
+	}
+	taskMetadata := injectedFaultTaskMetadata(fault)
+	switch fault.FaultType {
+	case types.BlackHolePortFaultType:
+		running, _, err := h.checkNetworkBlackHolePort(ctx, fault.Protocol, fault.Port,
+			blackholeChainName(fault.TrafficType, fault.Protocol, fault.Port),
+			ecstypes.NetworkMode(fault.TaskNetworkConfig.NetworkMode), fault.NetworkNamespacePath(), fault.TaskARN)
+		return running, err
+	case types.LatencyFaultType, types.PacketLossFaultType, types.BandwidthFaultType, types.CorruptionFaultType,
+		types.DuplicationFaultType, types.ReorderFaultType:
+		runningFaultType, err := h.checkTCFault(ctx, taskMetadata)
+		return runningFaultType == fault.FaultType, err
+	case types.DNSFaultType:
+		action, err := h.checkNetworkDNSFault(ctx, taskMetadata)
+		return action != "", err
+	default:
+		return false, fmt.Errorf("unknown fault type %s", fault.FaultType)
+	}
+}
+
+// isFaultOrphaned checks whether the injected fault depends on a DNS responder that isn't running anymore, e.g.
+// after an agent restart. The redirected queries would just fail, so the fault has to be removed instead.
+func (h *FaultHandler) isFaultOrphaned(fault *types.InjectedFault) bool {
+	return fault.FaultType == types.DNSFaultType && fault.DNSAction != types.DNSActionBlackhole &&
+		!h.dnsResponder.IsRunning(fault.NetworkNamespacePath())
+}
@@ -0,0 +1,288 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	mock_handlers "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/handlers/mocks"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/types"
+	mock_execwrapper "github.com/aws/amazon-ecs-agent/ecs-agent/utils/execwrapper/mocks"
+	mock_ttime "github.com/aws/amazon-ecs-agent/ecs-agent/utils/ttime/mocks"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// setCheckTCFaultExpectations sets the expectations of checking the tc faults of a host mode task.
+func setCheckTCFaultExpectations(exec *mock_execwrapper.MockExec, ctrl *gomock.Controller, interfaces []string,
+	commandOutput string) {
+	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeoutDuration)
+	mockCMD := mock_execwrapper.NewMockCmd(ctrl)
+	exec.EXPECT().NewExecContextWithTimeout(gomock.Any(), gomock.Any()).Times(1).Return(ctx, cancel)
+	expectations := []*gomock.Call{}
+	for _, iface := range interfaces {
+		expectations = append(expectations,
+			exec.EXPECT().
+				CommandContext(gomock.Any(), "tc", []string{"-j", "q", "show", "dev", iface}).
+				Return(mockCMD),
+			mockCMD.EXPECT().CombinedOutput().Return([]byte(commandOutput), nil),
+		)
+	}
+	gomock.InOrder(expectations...)
+}
+
+func TestRecordFault(t *testing.T) {
+	handler, _, _, mockStore, ctrl := setupFaultRecordTest(t)
+	defer ctrl.Finish()
+
+	fault := newTestLatencyFault(nil)
+	mockStore.EXPECT().SaveFault(fault).Return(errors.New("error"))
+	handler.recordFault(fault)
+	assert.Equal(t, []*types.InjectedFault{fault}, handler.taskFaults(taskARN))
+	assert.Empty(t, handler.taskFaults("other"))
+
+	mockStore.EXPECT().DeleteFault(fault.ID).Return(nil)
+	handler.forgetFault(fault.ID)
+	assert.Empty(t, handler.taskFaults(taskARN))
+}
+
+func TestGetFaults(t *testing.T) {
+	handler := New(nil, nil, nil)
+	assert.Empty(t, handler.GetFaults())
+
+	// The faults are listed from memory, even if they aren't persisted
+	fault := newTestLatencyFault(nil)
+	handler.recordFault(fault)
+	faults := handler.GetFaults()
+	assert.Equal(t, []*types.InjectedFault{fault}, faults)
+	assert.NotSame(t, fault, faults[0])
+
+	handler.forgetFault(fault.ID)
+	assert.Empty(t, handler.GetFaults())
+}
+
+func TestReconcileFaults(t *testing.T) {
+	now := time.Now()
+	future := now.Add(30 * time.Second)
+	past := now.Add(-30 * time.Second)
+	interfaces := []string{deviceName, deviceName2}
+
+	testCases := []struct {
+		name             string
+		expiresAt        *time.Time
+		taskActive       bool
+		setExpectations  func(*mock_execwrapper.MockExec, *mock_ttime.MockTime, *gomock.Controller)
+		expectDelete     bool
+		expectTracked    bool
+		expectedDuration *uint64
+	}{
+		{
+			name:       "task no longer active",
+			taskActive: false,
+			setExpectations: func(exec *mock_execwrapper.MockExec, _ *mock_ttime.MockTime, ctrl *gomock.Controller) {
+				setStopTCFaultExpectations(exec, ctrl, interfaces, tcLatencyFaultExistsCommandOutput)
+			},
+			expectDelete: true,
+		},
+		{
+			name:       "fault expired",
+			expiresAt:  &past,
+			taskActive: true,
+			setExpectations: func(exec *mock_execwrapper.MockExec, _ *mock_ttime.MockTime, ctrl *gomock.Controller) {
+				setStopTCFaultExpectations(exec, ctrl, interfaces, tcLatencyFaultExistsCommandOutput)
+			},
+			expectDelete: true,
+		},
+		{
+			name:       "fault no longer injected",
+			taskActive: true,
+			setExpectations: func(exec *mock_execwrapper.MockExec, _ *mock_ttime.MockTime, ctrl *gomock.Controller) {
+				setCheckTCFaultExpectations(exec, ctrl, interfaces, tcCommandEmptyOutput)
+			},
+			expectDelete: true,
+		},
+		{
+			name:       "fault still injected",
+			taskActive: true,
+			setExpectations: func(exec *mock_execwrapper.MockExec, _ *mock_ttime.MockTime, ctrl *gomock.Controller) {
+				setCheckTCFaultExpectations(exec, ctrl, interfaces, tcLatencyFaultExistsCommandOutput)
+			},
+			expectTracked: true,
+		},
+		{
+			name:       "fault still injected with expiry",
+			expiresAt:  &future,
+			taskActive: true,
+			setExpectations: func(exec *mock_execwrapper.MockExec, mockTime *mock_ttime.MockTime, ctrl *gomock.Controller) {
+				setCheckTCFaultExpectations(exec, ctrl, interfaces, tcLatencyFaultExistsCommandOutput)
+				mockTime.EXPECT().AfterFunc(30*time.Second, gomock.Any()).Return(mock_ttime.NewMockTimer(ctrl))
+			},
+			expectTracked:    true,
+			expectedDuration: aws.Uint64(30),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, mockTime, mockExec, mockStore, ctrl := setupFaultRecordTest(t)
+			defer ctrl.Finish()
+
+			fault := newTestLatencyFault(tc.expiresAt)
+			mockTime.EXPECT().Now().Return(now).AnyTimes()
+			mockStore.EXPECT().GetFaults().Return([]*types.InjectedFault{fault}, nil)
+			tc.setExpectations(mockExec, mockTime, ctrl)
+			if tc.expectDelete {
+				mockStore.EXPECT().DeleteFault(fault.ID).Return(nil)
+			}
+
+			handler.ReconcileFaults(func(arn string) bool {
+				assert.Equal(t, taskARN, arn)
+				return tc.taskActive
+			})
+			if tc.expectTracked {
+				assert.Equal(t, []*types.InjectedFault{fault}, handler.taskFaults(taskARN))
+			} else {
+				assert.Empty(t, handler.taskFaults(taskARN))
+			}
+			assert.Equal(t, tc.expectedDuration, handler.faultRemainingDuration(fault.ID))
+		})
+	}
+}
+
+func TestReconcileFaultsLoadError(t *testing.T) {
+	handler, _, _, mockStore, ctrl := setupFaultRecordTest(t)
+	defer ctrl.Finish()
+
+	mockStore.EXPECT().GetFaults().Return(nil, errors.New("error"))
+	handler.ReconcileFaults(func(string) bool { return true })
+	assert.Empty(t, handler.taskFaults(taskARN))
+}
+
+func TestCleanupTaskFaults(t *testing.T) {
+	handler, _, mockExec, mockStore, ctrl := setupFaultRecordTest(t)
+	defer ctrl.Finish()
+
+	fault := newTestLatencyFault(nil)
+	otherFault := newTestLatencyFault(nil)
+	otherFault.ID = "other"
+	otherFault.TaskARN = "otherTask"
+	handler.trackFault(fault)
+	handler.trackFault(otherFault)
+
+	setStopTCFaultExpectations(mockExec, ctrl, []string{deviceName, deviceName2}, tcLatencyFaultExistsCommandOutput)
+	mockStore.EXPECT().DeleteFault(fault.ID).Return(nil)
+	handler.CleanupTaskFaults(taskARN)
+	assert.Empty(t, handler.taskFaults(taskARN))
+	assert.Len(t, handler.taskFaults("otherTask"), 1)
+}
+
+func TestCleanupTaskFaultsStopError(t *testing.T) {
+	handler, _, mockExec, mockStore, ctrl := setupFaultRecordTest(t)
+	defer ctrl.Finish()
+
+	fault := newTestLatencyFault(nil)
+	fault.TaskNetworkConfig = nil
+	handler.trackFault(fault)
+
+	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeoutDuration)
+	mockExec.EXPECT().NewExecContextWithTimeout(gomock.Any(), gomock.Any()).Return(ctx, cancel)
+	mockStore.EXPECT().DeleteFault(fault.ID).Return(nil)
+	handler.CleanupTaskFaults(taskARN)
+	assert.Empty(t, handler.taskFaults(taskARN))
+}
+
+func TestReconcileDNSFaultWithoutResponder(t *testing.T) {
+	handler, _, mockExec, mockStore, ctrl := setupFaultRecordTest(t)
+	defer ctrl.Finish()
+	mockResponder := mock_handlers.NewMockDNSResponder(ctrl)
+	handler.dnsResponder = mockResponder
+
+	fault := &types.InjectedFault{
+		ID:                faultID(nspath, types.DNSFaultType),
+		TaskARN:           taskARN,
+		FaultType:         types.DNSFaultType,
+		TaskNetworkConfig: &happyTaskNetworkConfig,
+		DNSAction:         types.DNSActionNXDomain,
+	}
+	mockStore.EXPECT().GetFaults().Return([]*types.InjectedFault{fault}, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeoutDuration)
+	mockExec.EXPECT().NewExecContextWithTimeout(gomock.Any(), gomock.Any()).Return(ctx, cancel)
+	mockCMD := mock_execwrapper.NewMockCmd(ctrl)
+	expectations := expectDNSFaultCheck(mockExec, mockCMD, iptablesOutputChainPolicy,
+		iptablesOutputChainPolicy+"-A OUTPUT -j dns-nxdomain\n")
+	expectations = append(expectations, mockResponder.EXPECT().IsRunning(nspath).Return(false))
+	expectations = addExpectedCommandsWithPrefix(mockExec, fmt.Sprintf(nsenterCommandString, nspath),
+		dnsFaultStopCommands(types.DNSActionNXDomain), expectations, mockCMD)
+	expectations = append(expectations,
+		mockResponder.EXPECT().Stop(nspath).Return(nil),
+		mockStore.EXPECT().DeleteFault(fault.ID).Return(nil),
+	)
+	gomock.InOrder(expectations...)
+
+	handler.ReconcileFaults(func(string) bool { return true })
+	assert.Empty(t, handler.taskFaults(taskARN))
+}
+
+func TestCleanupTaskResourceStressFault(t *testing.T) {
+	handler, _, mockExec, mockStore, ctrl := setupFaultRecordTest(t)
+	defer ctrl.Finish()
+	stressor := mock_handlers.NewMockResourceStressor(ctrl)
+	handler.resourceStressor = stressor
+
+	fault := &types.InjectedFault{
+		ID:        faultID(taskARN, types.MemoryPressureFaultType),
+		TaskARN:   taskARN,
+		FaultType: types.MemoryPressureFaultType,
+	}
+	handler.trackFault(fault)
+	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeoutDuration)
+	mockExec.EXPECT().NewExecContextWithTimeout(gomock.Any(), gomock.Any()).Return(ctx, cancel)
+	gomock.InOrder(
+		stressor.EXPECT().Stop(taskARN, types.MemoryPressureFaultType).Return(nil),
+		mockStore.EXPECT().DeleteFault(fault.ID).Return(nil),
+	)
+
+	handler.CleanupTaskFaults(taskARN)
+	assert.Empty(t, handler.taskFaults(taskARN))
+}
+
+func TestCleanupTaskResourceStressFaultWithoutStressor(t *testing.T) {
+	handler, _, mockExec, mockStore, ctrl := setupFaultRecordTest(t)
+	defer ctrl.Finish()
+
+	fault := &types.InjectedFault{
+		ID:        faultID(taskARN, types.CPUStressFaultType),
+		TaskARN:   taskARN,
+		FaultType: types.CPUStressFaultType,
+	}
+	injected, err := handler.isFaultInjected(context.Background(), fault)
+	assert.NoError(t, err)
+	assert.False(t, injected)
+
+	handler.trackFault(fault)
+	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeoutDuration)
+	mockExec.EXPECT().NewExecContextWithTimeout(gomock.Any(), gomock.Any()).Return(ctx, cancel)
+	mockStore.EXPECT().DeleteFault(fault.ID).Return(nil)
+	handler.CleanupTaskFaults(taskARN)
+	assert.Empty(t, handler.taskFaults(taskARN))
+}
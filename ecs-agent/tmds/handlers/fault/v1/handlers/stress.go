@@ -0,0 +1,263 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/audit"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/types"
+	handlerutils "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/utils"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// ResourceStressor consumes a share of the CPU or memory quota of task cgroups for the CPU stress and memory
+// pressure faults. The resource is identified by the fault type, either cpu-stress or memory-pressure.
+type ResourceStressor interface {
+	// Start starts consuming the percentage of the quota of the resource of the task cgroup. The resource stops
+	// being consumed once the duration has elapsed.
+	Start(taskARN, resource string, percent uint64, duration time.Duration) error
+	// Stop stops consuming the resource of the task cgroup. It's a no-op if the resource isn't being consumed.
+	Stop(taskARN, resource string) error
+	// IsRunning returns whether the resource of the task cgroup is being consumed.
+	IsRunning(taskARN, resource string) bool
+}
+
+// noopResourceStressor is the ResourceStressor of the handlers configured without one. The CPU stress and memory
+// pressure faults can't be started, and are never running.
+type noopResourceStressor struct{}
+
+// errNoResourceStressor is returned when starting a fault without a ResourceStressor configured.
+var errNoResourceStressor = errors.New("CPU stress and memory pressure faults are not supported by this agent")
+
+func (noopResourceStressor) Start(string, string, uint64, time.Duration) error {
+	return errNoResourceStressor
+}
+
+func (noopResourceStressor) Stop(string, string) error {
+	return nil
+}
+
+func (noopResourceStressor) IsRunning(string, string) bool {
+	return false
+}
+
+// StartCPUStress starts a CPU stress fault in the associated task cgroup if no existing same fault.
+func (h *FaultHandler) StartCPUStress() func(http.ResponseWriter, *http.Request) {
+	return h.startResourceStressHandler(types.CPUStressFaultType)
+}
+
+// StopCPUStress stops a CPU stress fault in the associated task cgroup if there is one existing same fault.
+func (h *FaultHandler) StopCPUStress() func(http.ResponseWriter, *http.Request) {
+	return h.stopResourceStressHandler(types.CPUStressFaultType)
+}
+
+// CheckCPUStress checks the status of given CPU stress fault.
+func (h *FaultHandler) CheckCPUStress() func(http.ResponseWriter, *http.Request) {
+	return h.checkResourceStressHandler(types.CPUStressFaultType)
+}
+
+// StartMemoryPressure starts a memory pressure fault in the associated task cgroup if no existing same fault.
+func (h *FaultHandler) StartMemoryPressure() func(http.ResponseWriter, *http.Request) {
+	return h.startResourceStressHandler(types.MemoryPressureFaultType)
+}
+
+// StopMemoryPressure stops a memory pressure fault in the associated task cgroup if there is one existing same
+// fault.
+func (h *FaultHandler) StopMemoryPressure() func(http.ResponseWriter, *http.Request) {
+	return h.stopResourceStressHandler(types.MemoryPressureFaultType)
+}
+
+// CheckMemoryPressure checks the status of given memory pressure fault.
+func (h *FaultHandler) CheckMemoryPressure() func(http.ResponseWriter, *http.Request) {
+	return h.checkResourceStressHandler(types.MemoryPressureFaultType)
+}
+
+// startResourceStressHandler returns the handler starting a fault of the given type, which consumes the
+// resources of the task cgroup, if no existing same fault.
+func (h *FaultHandler) startResourceStressHandler(faultType string) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request types.ResourceStressRequest
+		requestType := fmt.Sprintf(startFaultRequestType, faultType)
+		// Parse the fault request
+		err := decodeRequest(w, &request, requestType, r)
+		if err != nil {
+			return
+		}
+
+		// Validate the fault request
+		err = validateRequest(w, request, requestType)
+		if err != nil {
+			return
+		}
+
+		// Obtain the task metadata via the endpoint container ID
+		taskMetadata, err := getFaultInjectionTaskMetadata(w, h.AgentState, requestType, r)
+		if err != nil {
+			return
+		}
+
+		id := faultID(taskMetadata.TaskARN, faultType)
+		rwMu := h.loadLock(taskMetadata.TaskARN)
+		rwMu.Lock()
+		defer rwMu.Unlock()
+
+		var responseBody types.NetworkFaultInjectionResponse
+		var httpStatusCode int
+		stringToBeLogged := "Failed to start fault"
+		if h.resourceStressor.IsRunning(taskMetadata.TaskARN, faultType) {
+			// If there already exists a fault of the given type in the task cgroup.
+			responseBody = types.NewNetworkFaultInjectionErrorResponse(faultAlreadyRunningError(faultType))
+			httpStatusCode = http.StatusConflict
+		} else if err := h.resourceStressor.Start(taskMetadata.TaskARN, faultType, aws.ToUint64(request.Percent),
+			time.Duration(aws.ToUint64(request.DurationSeconds))*time.Second); err != nil {
+			logger.Error("Unable to consume the task cgroup resources", logger.Fields{
+				field.RequestType: requestType,
+				field.TaskARN:     taskMetadata.TaskARN,
+				field.Error:       err,
+			})
+			responseBody = types.NewNetworkFaultInjectionErrorResponse(internalError)
+			httpStatusCode = http.StatusInternalServerError
+			if errors.Is(err, errNoResourceStressor) {
+				responseBody = types.NewNetworkFaultInjectionErrorResponse(err.Error())
+				httpStatusCode = http.StatusNotImplemented
+			}
+		} else {
+			stringToBeLogged = "Successfully started fault"
+			responseBody = types.NewNetworkFaultInjectionSuccessResponse("running")
+			h.recordFault(h.newInjectedFault(taskMetadata, faultType, id,
+				request.ToString(), request.DurationSeconds))
+			responseBody.RemainingDurationSeconds = request.DurationSeconds
+			httpStatusCode = http.StatusOK
+		}
+		h.auditFault(r, taskMetadata.TaskARN, httpStatusCode, audit.StartFaultEventType, faultType,
+			request.ToString())
+		logger.Info(stringToBeLogged, logger.Fields{
+			field.RequestType: requestType,
+			field.Request:     request.ToString(),
+			field.Response:    responseBody.ToString(),
+		})
+		handlerutils.WriteJSONResponse(
+			w,
+			httpStatusCode,
+			responseBody,
+			requestType,
+		)
+	}
+}
+
+// stopResourceStressHandler returns the handler stopping a fault of the given type, which consumes the
+// resources of the task cgroup, if there is one existing same fault.
+func (h *FaultHandler) stopResourceStressHandler(faultType string) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request types.ResourceStressRequest
+		requestType := fmt.Sprintf(stopFaultRequestType, faultType)
+		logRequest(requestType, r)
+
+		// Obtain the task metadata via the endpoint container ID
+		taskMetadata, err := getFaultInjectionTaskMetadata(w, h.AgentState, requestType, r)
+		if err != nil {
+			return
+		}
+
+		id := faultID(taskMetadata.TaskARN, faultType)
+		rwMu := h.loadLock(taskMetadata.TaskARN)
+		rwMu.Lock()
+		defer rwMu.Unlock()
+
+		var responseBody types.NetworkFaultInjectionResponse
+		var httpStatusCode int
+		stringToBeLogged := "Failed to stop fault"
+		if !h.resourceStressor.IsRunning(taskMetadata.TaskARN, faultType) {
+			// If there doesn't already exist a fault of the given type
+			stringToBeLogged = "No fault running"
+			responseBody = types.NewNetworkFaultInjectionSuccessResponse("stopped")
+			httpStatusCode = http.StatusOK
+		} else if err := h.resourceStressor.Stop(taskMetadata.TaskARN, faultType); err != nil {
+			logger.Error("Unable to stop consuming the task cgroup resources", logger.Fields{
+				field.RequestType: requestType,
+				field.TaskARN:     taskMetadata.TaskARN,
+				field.Error:       err,
+			})
+			responseBody = types.NewNetworkFaultInjectionErrorResponse(internalError)
+			httpStatusCode = http.StatusInternalServerError
+		} else {
+			stringToBeLogged = "Successfully stopped fault"
+			responseBody = types.NewNetworkFaultInjectionSuccessResponse("stopped")
+			httpStatusCode = http.StatusOK
+		}
+		parameters := h.faultParameters(id)
+		if httpStatusCode == http.StatusOK {
+			h.forgetFault(id)
+		}
+		h.auditFault(r, taskMetadata.TaskARN, httpStatusCode, audit.StopFaultEventType, faultType, parameters)
+		logger.Info(stringToBeLogged, logger.Fields{
+			field.RequestType: requestType,
+			field.Request:     request.ToString(),
+			field.Response:    responseBody.ToString(),
+		})
+		handlerutils.WriteJSONResponse(
+			w,
+			httpStatusCode,
+			responseBody,
+			requestType,
+		)
+	}
+}
+
+// checkResourceStressHandler returns the handler checking the status of a fault of the given type, which
+// consumes the resources of the task cgroup.
+func (h *FaultHandler) checkResourceStressHandler(faultType string) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request types.ResourceStressRequest
+		requestType := fmt.Sprintf(checkStatusFaultRequestType, faultType)
+		logRequest(requestType, r)
+
+		// Obtain the task metadata via the endpoint container ID
+		taskMetadata, err := getFaultInjectionTaskMetadata(w, h.AgentState, requestType, r)
+		if err != nil {
+			return
+		}
+
+		id := faultID(taskMetadata.TaskARN, faultType)
+		rwMu := h.loadLock(taskMetadata.TaskARN)
+		rwMu.RLock()
+		defer rwMu.RUnlock()
+
+		var responseBody types.NetworkFaultInjectionResponse
+		if h.resourceStressor.IsRunning(taskMetadata.TaskARN, faultType) {
+			responseBody = types.NewNetworkFaultInjectionSuccessResponse("running")
+			responseBody.RemainingDurationSeconds = h.faultRemainingDuration(id)
+		} else {
+			responseBody = types.NewNetworkFaultInjectionSuccessResponse("not-running")
+		}
+		logger.Info("Successfully checked fault status", logger.Fields{
+			field.RequestType: requestType,
+			field.Request:     request.ToString(),
+			field.Response:    responseBody.ToString(),
+		})
+		handlerutils.WriteJSONResponse(
+			w,
+			http.StatusOK,
+			responseBody,
+			requestType,
+		)
+	}
+}
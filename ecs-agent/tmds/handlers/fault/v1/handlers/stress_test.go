@@ -0,0 +1,349 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/audit"
+	mock_audit "github.com/aws/amazon-ecs-agent/ecs-agent/logger/audit/mocks"
+	mock_metrics "github.com/aws/amazon-ecs-agent/ecs-agent/metrics/mocks"
+	mock_handlers "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/handlers/mocks"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/types"
+	v2 "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/v2"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/v4/state"
+	mock_state "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/v4/state/mocks"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/utils/netconfig"
+	mock_execwrapper "github.com/aws/amazon-ecs-agent/ecs-agent/utils/execwrapper/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceStressFault(t *testing.T) {
+	for _, faultType := range []string{types.CPUStressFaultType, types.MemoryPressureFaultType} {
+		tcs := []struct {
+			name                 string
+			operation            string
+			requestBody          interface{}
+			setExpectations      func(*mock_handlers.MockResourceStressor)
+			expectedAuditEvent   string
+			expectedStatusCode   int
+			expectedResponseJSON string
+		}{
+			{
+				name:        "start",
+				operation:   types.StartNetworkFaultPostfix,
+				requestBody: map[string]interface{}{"Percent": 80, "DurationSeconds": 60},
+				setExpectations: func(stressor *mock_handlers.MockResourceStressor) {
+					gomock.InOrder(
+						stressor.EXPECT().IsRunning(taskARN, faultType).Return(false),
+						stressor.EXPECT().Start(taskARN, faultType, uint64(80), time.Minute).Return(nil),
+					)
+				},
+				expectedAuditEvent:   audit.StartFaultEventType,
+				expectedStatusCode:   http.StatusOK,
+				expectedResponseJSON: `{"Status":"running","RemainingDurationSeconds":60}`,
+			},
+			{
+				name:        "start existing fault",
+				operation:   types.StartNetworkFaultPostfix,
+				requestBody: map[string]interface{}{"Percent": 80, "DurationSeconds": 60},
+				setExpectations: func(stressor *mock_handlers.MockResourceStressor) {
+					stressor.EXPECT().IsRunning(taskARN, faultType).Return(true)
+				},
+				expectedAuditEvent:   audit.StartFaultEventType,
+				expectedStatusCode:   http.StatusConflict,
+				expectedResponseJSON: fmt.Sprintf(errorResponse, faultAlreadyRunningError(faultType)),
+			},
+			{
+				name:        "start error",
+				operation:   types.StartNetworkFaultPostfix,
+				requestBody: map[string]interface{}{"Percent": 80, "DurationSeconds": 60},
+				setExpectations: func(stressor *mock_handlers.MockResourceStressor) {
+					gomock.InOrder(
+						stressor.EXPECT().IsRunning(taskARN, faultType).Return(false),
+						stressor.EXPECT().Start(taskARN, faultType, uint64(80), time.Minute).
+							Return(errors.New("no task cgroup")),
+					)
+				},
+				expectedAuditEvent:   audit.StartFaultEventType,
+				expectedStatusCode:   http.StatusInternalServerError,
+				expectedResponseJSON: fmt.Sprintf(errorResponse, internalError),
+			},
+			{
+				name:                 "start without duration",
+				operation:            types.StartNetworkFaultPostfix,
+				requestBody:          map[string]interface{}{"Percent": 80},
+				expectedStatusCode:   http.StatusBadRequest,
+				expectedResponseJSON: fmt.Sprintf(errorResponse, "required parameter DurationSeconds is missing"),
+			},
+			{
+				name:      "start with duration above maximum",
+				operation: types.StartNetworkFaultPostfix,
+				requestBody: map[string]interface{}{
+					"Percent": 80, "DurationSeconds": types.MaxDurationSeconds + 1,
+				},
+				expectedStatusCode: http.StatusBadRequest,
+				expectedResponseJSON: fmt.Sprintf(errorResponse,
+					fmt.Sprintf("invalid value %d for parameter DurationSeconds", types.MaxDurationSeconds+1)),
+			},
+			{
+				name:        "stop",
+				operation:   types.StopNetworkFaultPostfix,
+				requestBody: map[string]interface{}{},
+				setExpectations: func(stressor *mock_handlers.MockResourceStressor) {
+					gomock.InOrder(
+						stressor.EXPECT().IsRunning(taskARN, faultType).Return(true),
+						stressor.EXPECT().Stop(taskARN, faultType).Return(nil),
+					)
+				},
+				expectedAuditEvent:   audit.StopFaultEventType,
+				expectedStatusCode:   http.StatusOK,
+				expectedResponseJSON: happyFaultStoppedResponse,
+			},
+			{
+				name:        "stop no fault",
+				operation:   types.StopNetworkFaultPostfix,
+				requestBody: map[string]interface{}{},
+				setExpectations: func(stressor *mock_handlers.MockResourceStressor) {
+					stressor.EXPECT().IsRunning(taskARN, faultType).Return(false)
+				},
+				expectedAuditEvent:   audit.StopFaultEventType,
+				expectedStatusCode:   http.StatusOK,
+				expectedResponseJSON: happyFaultStoppedResponse,
+			},
+			{
+				name:        "stop error",
+				operation:   types.StopNetworkFaultPostfix,
+				requestBody: map[string]interface{}{},
+				setExpectations: func(stressor *mock_handlers.MockResourceStressor) {
+					gomock.InOrder(
+						stressor.EXPECT().IsRunning(taskARN, faultType).Return(true),
+						stressor.EXPECT().Stop(taskARN, faultType).Return(errors.New("error")),
+					)
+				},
+				expectedAuditEvent:   audit.StopFaultEventType,
+				expectedStatusCode:   http.StatusInternalServerError,
+				expectedResponseJSON: fmt.Sprintf(errorResponse, internalError),
+			},
+			{
+				name:        "check running",
+				operation:   types.CheckNetworkFaultPostfix,
+				requestBody: map[string]interface{}{},
+				setExpectations: func(stressor *mock_handlers.MockResourceStressor) {
+					stressor.EXPECT().IsRunning(taskARN, faultType).Return(true)
+				},
+				expectedStatusCode:   http.StatusOK,
+				expectedResponseJSON: happyFaultRunningResponse,
+			},
+			{
+				name:        "check not running",
+				operation:   types.CheckNetworkFaultPostfix,
+				requestBody: map[string]interface{}{},
+				setExpectations: func(stressor *mock_handlers.MockResourceStressor) {
+					stressor.EXPECT().IsRunning(taskARN, faultType).Return(false)
+				},
+				expectedStatusCode:   http.StatusOK,
+				expectedResponseJSON: happyFaultNotRunningResponse,
+			},
+		}
+
+		for _, tc := range tcs {
+			t.Run(fmt.Sprintf("%s %s", faultType, tc.name), func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+
+				agentState := mock_state.NewMockAgentState(ctrl)
+				stressor := mock_handlers.NewMockResourceStressor(ctrl)
+				auditLogger := mock_audit.NewMockFaultAuditLogger(ctrl)
+				handler := New(agentState, mock_metrics.NewMockEntryFactory(ctrl),
+					mock_execwrapper.NewMockExec(ctrl), WithResourceStressor(stressor), WithAuditLogger(auditLogger))
+
+				agentState.EXPECT().GetTaskMetadataWithTaskNetworkConfig(endpointId,
+					netconfig.NewNetworkConfigClient()).Return(happyTaskResponse, nil).MaxTimes(1)
+				if tc.setExpectations != nil {
+					tc.setExpectations(stressor)
+				}
+				if tc.expectedAuditEvent != "" {
+					auditLogger.EXPECT().LogFault(gomock.Any(), tc.expectedStatusCode, tc.expectedAuditEvent,
+						faultType, gomock.Any())
+				}
+
+				var handleMethod func(http.ResponseWriter, *http.Request)
+				switch {
+				case tc.operation == types.StartNetworkFaultPostfix && faultType == types.CPUStressFaultType:
+					handleMethod = handler.StartCPUStress()
+				case tc.operation == types.StopNetworkFaultPostfix && faultType == types.CPUStressFaultType:
+					handleMethod = handler.StopCPUStress()
+				case faultType == types.CPUStressFaultType:
+					handleMethod = handler.CheckCPUStress()
+				case tc.operation == types.StartNetworkFaultPostfix:
+					handleMethod = handler.StartMemoryPressure()
+				case tc.operation == types.StopNetworkFaultPostfix:
+					handleMethod = handler.StopMemoryPressure()
+				default:
+					handleMethod = handler.CheckMemoryPressure()
+				}
+				router := mux.NewRouter()
+				router.HandleFunc(NetworkFaultPath(faultType, tc.operation), handleMethod).
+					Methods(http.MethodPost)
+
+				requestBody, err := json.Marshal(tc.requestBody)
+				require.NoError(t, err)
+				req, err := http.NewRequest(http.MethodPost,
+					fmt.Sprintf("/api/%s/fault/v1/%s/%s", endpointId, faultType, tc.operation),
+					bytes.NewReader(requestBody))
+				require.NoError(t, err)
+				recorder := httptest.NewRecorder()
+				router.ServeHTTP(recorder, req)
+
+				assert.Equal(t, tc.expectedStatusCode, recorder.Code)
+				assert.Equal(t, tc.expectedResponseJSON, recorder.Body.String())
+			})
+		}
+	}
+}
+
+// TestResourceStressFaultWithoutStressor tests that the faults can't be started, and are never running, when the
+// handler is configured without a resource stressor.
+func TestResourceStressFaultWithoutStressor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agentState := mock_state.NewMockAgentState(ctrl)
+	handler := New(agentState, mock_metrics.NewMockEntryFactory(ctrl), mock_execwrapper.NewMockExec(ctrl))
+	router := mux.NewRouter()
+	router.HandleFunc(NetworkFaultPath(types.CPUStressFaultType, types.StartNetworkFaultPostfix),
+		handler.StartCPUStress()).Methods(http.MethodPost)
+	router.HandleFunc(NetworkFaultPath(types.CPUStressFaultType, types.StopNetworkFaultPostfix),
+		handler.StopCPUStress()).Methods(http.MethodPost)
+	router.HandleFunc(NetworkFaultPath(types.CPUStressFaultType, types.CheckNetworkFaultPostfix),
+		handler.CheckCPUStress()).Methods(http.MethodPost)
+	serve := func(operation, body string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodPost,
+			fmt.Sprintf("/api/%s/fault/v1/%s/%s", endpointId, types.CPUStressFaultType, operation),
+			bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	agentState.EXPECT().GetTaskMetadataWithTaskNetworkConfig(endpointId, gomock.Any()).
+		Return(happyTaskResponse, nil).AnyTimes()
+	recorder := serve(types.StartNetworkFaultPostfix, `{"Percent":50,"DurationSeconds":60}`)
+	assert.Equal(t, http.StatusNotImplemented, recorder.Code)
+	assert.Equal(t, fmt.Sprintf(errorResponse, errNoResourceStressor.Error()), recorder.Body.String())
+	assert.Empty(t, handler.taskFaults(taskARN))
+
+	recorder = serve(types.StopNetworkFaultPostfix, `{}`)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, happyFaultStoppedResponse, recorder.Body.String())
+
+	recorder = serve(types.CheckNetworkFaultPostfix, `{}`)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, happyFaultNotRunningResponse, recorder.Body.String())
+}
+
+// TestResourceStressFaultSharedNetworkNamespace tests that the faults of tasks sharing the network namespace of
+// the instance, such as host and bridge mode tasks, are tracked separately.
+func TestResourceStressFaultSharedNetworkNamespace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const (
+		hostTaskARN        = "hostTask"
+		hostEndpointID     = "hostEndpoint"
+		bridgeTaskARN      = "bridgeTask"
+		bridgeEndpointID   = "bridgeEndpoint"
+		hostNamespacePath  = "host"
+		memoryPressureBody = `{"Percent":50,"DurationSeconds":60}`
+	)
+	hostTaskResponse := state.TaskResponse{
+		TaskResponse: &v2.TaskResponse{TaskARN: hostTaskARN},
+		TaskNetworkConfig: &state.TaskNetworkConfig{
+			NetworkMode: hostNetworkMode,
+			NetworkNamespaces: []*state.NetworkNamespace{
+				{Path: hostNamespacePath, NetworkInterfaces: []*state.NetworkInterface{{DeviceName: deviceName}}},
+			},
+		},
+		FaultInjectionEnabled: true,
+	}
+	// Bridge mode tasks have no task network interfaces.
+	bridgeTaskResponse := state.TaskResponse{
+		TaskResponse: &v2.TaskResponse{TaskARN: bridgeTaskARN},
+		TaskNetworkConfig: &state.TaskNetworkConfig{
+			NetworkMode:       "bridge",
+			NetworkNamespaces: []*state.NetworkNamespace{{Path: hostNamespacePath}},
+		},
+		FaultInjectionEnabled: true,
+	}
+
+	agentState := mock_state.NewMockAgentState(ctrl)
+	stressor := mock_handlers.NewMockResourceStressor(ctrl)
+	handler := New(agentState, mock_metrics.NewMockEntryFactory(ctrl), mock_execwrapper.NewMockExec(ctrl),
+		WithResourceStressor(stressor))
+	router := mux.NewRouter()
+	router.HandleFunc(NetworkFaultPath(types.MemoryPressureFaultType, types.StartNetworkFaultPostfix),
+		handler.StartMemoryPressure()).Methods(http.MethodPost)
+	router.HandleFunc(NetworkFaultPath(types.MemoryPressureFaultType, types.StopNetworkFaultPostfix),
+		handler.StopMemoryPressure()).Methods(http.MethodPost)
+	serve := func(endpointID, operation, body string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodPost,
+			fmt.Sprintf("/api/%s/fault/v1/%s/%s", endpointID, types.MemoryPressureFaultType, operation),
+			bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	agentState.EXPECT().GetTaskMetadataWithTaskNetworkConfig(hostEndpointID, gomock.Any()).
+		Return(hostTaskResponse, nil).AnyTimes()
+	agentState.EXPECT().GetTaskMetadataWithTaskNetworkConfig(bridgeEndpointID, gomock.Any()).
+		Return(bridgeTaskResponse, nil).AnyTimes()
+	for _, arn := range []string{hostTaskARN, bridgeTaskARN} {
+		gomock.InOrder(
+			stressor.EXPECT().IsRunning(arn, types.MemoryPressureFaultType).Return(false),
+			stressor.EXPECT().Start(arn, types.MemoryPressureFaultType, uint64(50), time.Minute).Return(nil),
+		)
+	}
+	assert.Equal(t, http.StatusOK, serve(hostEndpointID, types.StartNetworkFaultPostfix, memoryPressureBody).Code)
+	assert.Equal(t, http.StatusOK, serve(bridgeEndpointID, types.StartNetworkFaultPostfix, memoryPressureBody).Code)
+	require.Len(t, handler.taskFaults(hostTaskARN), 1)
+	require.Len(t, handler.taskFaults(bridgeTaskARN), 1)
+
+	// Stopping the fault of one task leaves the fault of the other task alone.
+	gomock.InOrder(
+		stressor.EXPECT().IsRunning(hostTaskARN, types.MemoryPressureFaultType).Return(true),
+		stressor.EXPECT().Stop(hostTaskARN, types.MemoryPressureFaultType).Return(nil),
+	)
+	assert.Equal(t, http.StatusOK, serve(hostEndpointID, types.StopNetworkFaultPostfix, `{}`).Code)
+	assert.Empty(t, handler.taskFaults(hostTaskARN))
+	assert.Len(t, handler.taskFaults(bridgeTaskARN), 1)
+	assert.NotNil(t, handler.faultRemainingDuration(faultID(bridgeTaskARN, types.MemoryPressureFaultType)))
+}
@@ -17,7 +17,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/v4/state"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/utils"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/pkg/errors"
@@ -27,16 +30,32 @@ const (
 	BlackHolePortFaultType   = "network-blackhole-port"
 	LatencyFaultType         = "network-latency"
 	PacketLossFaultType      = "network-packet-loss"
+	BandwidthFaultType       = "network-bandwidth"
+	CorruptionFaultType      = "network-corruption"
+	DuplicationFaultType     = "network-duplication"
+	ReorderFaultType         = "network-reorder"
+	DNSFaultType             = "network-dns"
+	CPUStressFaultType       = "cpu-stress"
+	MemoryPressureFaultType  = "memory-pressure"
 	StartNetworkFaultPostfix = "start"
 	StopNetworkFaultPostfix  = "stop"
 	CheckNetworkFaultPostfix = "status"
 	TrafficTypeIngress       = "ingress"
 	TrafficTypeEgress        = "egress"
+	// Actions of network DNS faults
+	DNSActionBlackhole = "blackhole"
+	DNSActionDelay     = "delay"
+	DNSActionNXDomain  = "nxdomain"
+	DNSActionServFail  = "servfail"
 	// Request Payload Errors
 	MissingRequiredFieldError = "required parameter %s is missing"
 	MissingRequestBodyError   = "required request body is missing"
 	ZeroDelayAndJitterError   = "required either DelayMilliseconds or JitterMilliseconds to be non-zero"
 	InvalidValueError         = "invalid value %s for parameter %s"
+
+	// MaxDurationSeconds is the longest duration of a time-bounded fault, 7 days. It keeps the expiry of the
+	// faults far within the range of time.Duration.
+	MaxDurationSeconds = 7 * 24 * 60 * 60
 )
 
 type NetworkFaultRequest interface {
@@ -51,11 +70,49 @@ type NetworkBlackholePortRequest struct {
 	// SourcesToFilter is a list including IPv4/IPv6 addresses or IPv4/IPv6 CIDR blocks that will be excluded
 	// from the fault.
 	SourcesToFilter []*string `json:"SourcesToFilter,omitempty"`
+	// DurationSeconds is the optional number of seconds after which the fault is automatically stopped, at most
+	// MaxDurationSeconds.
+	DurationSeconds *uint64 `json:"DurationSeconds,omitempty"`
+}
+
+// InjectedFault is the record of a network fault injected into a task network namespace. It holds everything
+// needed to check and stop the fault without the original request, so that the fault can be reconciled after
+// an agent restart and cleaned up once the task stops.
+type InjectedFault struct {
+	// ID identifies the fault on the instance. It is derived from the network namespace path, or the task ARN for
+	// the faults scoped to the task cgroup, and the fault.
+	ID        string
+	TaskARN   string
+	FaultType string
+	// TaskNetworkConfig is the network configuration of the task when the fault was injected.
+	TaskNetworkConfig *state.TaskNetworkConfig
+	// Protocol, Port and TrafficType are only set for network blackhole port faults.
+	Protocol    string `json:",omitempty"`
+	Port        string `json:",omitempty"`
+	TrafficType string `json:",omitempty"`
+	// DNSAction is only set for network DNS faults.
+	DNSAction string `json:",omitempty"`
+	// Parameters are the parameters of the request the fault was started with.
+	Parameters string `json:",omitempty"`
+	StartedAt  time.Time
+	// ExpiresAt is set for faults started with a duration.
+	ExpiresAt *time.Time `json:",omitempty"`
+}
+
+// NetworkNamespacePath returns the path of the network namespace the fault was injected into.
+func (fault *InjectedFault) NetworkNamespacePath() string {
+	if fault.TaskNetworkConfig == nil || len(fault.TaskNetworkConfig.NetworkNamespaces) == 0 {
+		return ""
+	}
+	return fault.TaskNetworkConfig.NetworkNamespaces[0].Path
 }
 
 type NetworkFaultInjectionResponse struct {
 	Status string `json:"Status,omitempty"`
 	Error  string `json:"Error,omitempty"`
+	// RemainingDurationSeconds is the number of seconds left before a time-bounded fault is automatically
+	// stopped. It is omitted for faults that were started without a duration.
+	RemainingDurationSeconds *uint64 `json:"RemainingDurationSeconds,omitempty"`
 }
 
 func (request NetworkBlackholePortRequest) ValidateRequest() error {
@@ -79,6 +136,9 @@ func (request NetworkBlackholePortRequest) ValidateRequest() error {
 	if err := requireIPInRequestSources(request.SourcesToFilter, "SourcesToFilter"); err != nil {
 		return err
 	}
+	if err := validateDurationSeconds(request.DurationSeconds); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -122,6 +182,9 @@ type NetworkLatencyRequest struct {
 	// network latency fault.
 	SourcesToFilter []*string `json:"SourcesToFilter,omitempty"`
 	FlowsPercent    *int      `json:"FlowsPercent"`
+	// DurationSeconds is the optional number of seconds after which the fault is automatically stopped, at most
+	// MaxDurationSeconds.
+	DurationSeconds *uint64 `json:"DurationSeconds,omitempty"`
 }
 
 // ValidateRequest validates required fields are present and its value.
@@ -153,6 +216,9 @@ func (request NetworkLatencyRequest) ValidateRequest() error {
 			return fmt.Errorf(InvalidValueError, strconv.Itoa(flowsPercent), "flowsPercent")
 		}
 	}
+	if err := validateDurationSeconds(request.DurationSeconds); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -173,6 +239,9 @@ type NetworkPacketLossRequest struct {
 	// network packet loss fault.
 	SourcesToFilter []*string `json:"SourcesToFilter,omitempty"`
 	FlowsPercent    *int      `json:"FlowsPercent"`
+	// DurationSeconds is the optional number of seconds after which the fault is automatically stopped, at most
+	// MaxDurationSeconds.
+	DurationSeconds *uint64 `json:"DurationSeconds,omitempty"`
 }
 
 // ValidateRequest validates required fields are present and its value.
@@ -200,6 +269,9 @@ func (request NetworkPacketLossRequest) ValidateRequest() error {
 			return fmt.Errorf(InvalidValueError, strconv.Itoa(flowsPercent), "flowsPercent")
 		}
 	}
+	if err := validateDurationSeconds(request.DurationSeconds); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -211,6 +283,261 @@ func (request NetworkPacketLossRequest) ToString() string {
 	return string(data)
 }
 
+// NetworkBandwidthRequest is struct for the network bandwidth fault request.
+type NetworkBandwidthRequest struct {
+	// RateKbps is the rate, in kilobits per second, all the impacted traffic of each network interface is
+	// throttled to.
+	RateKbps *uint64 `json:"RateKbps"`
+	// Sources is a list including IPv4 addresses or IPv4 CIDR blocks.
+	Sources []*string `json:"Sources"`
+	// SourcesToFilter is a list including IPv4 addresses or IPv4 CIDR blocks that will be excluded from the
+	// network bandwidth fault.
+	SourcesToFilter []*string `json:"SourcesToFilter,omitempty"`
+	// FlowsPercent can only be 100 since the bandwidth is shared by all the impacted flows.
+	FlowsPercent *int `json:"FlowsPercent"`
+	// DurationSeconds is the optional number of seconds after which the fault is automatically stopped, at most
+	// MaxDurationSeconds.
+	DurationSeconds *uint64 `json:"DurationSeconds,omitempty"`
+}
+
+// ValidateRequest validates required fields are present and its value.
+func (request NetworkBandwidthRequest) ValidateRequest() error {
+	if request.RateKbps == nil {
+		return fmt.Errorf(MissingRequiredFieldError, "RateKbps")
+	}
+	if *request.RateKbps == 0 {
+		return fmt.Errorf(InvalidValueError, strconv.FormatUint(*request.RateKbps, 10), "RateKbps")
+	}
+	if err := validateNetemFaultTraffic(request.Sources, request.SourcesToFilter, request.FlowsPercent,
+		request.DurationSeconds); err != nil {
+		return err
+	}
+	if request.FlowsPercent != nil && *request.FlowsPercent != 100 {
+		return fmt.Errorf(InvalidValueError, strconv.Itoa(*request.FlowsPercent), "flowsPercent")
+	}
+	return nil
+}
+
+func (request NetworkBandwidthRequest) ToString() string {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Sprintf("Error: Unable to parse %s request with error %v.", BandwidthFaultType, err)
+	}
+	return string(data)
+}
+
+// NetworkCorruptionRequest is struct for the network packet corruption fault request.
+type NetworkCorruptionRequest struct {
+	CorruptionPercent *uint64 `json:"CorruptionPercent"`
+	// Sources is a list including IPv4 addresses or IPv4 CIDR blocks.
+	Sources []*string `json:"Sources"`
+	// SourcesToFilter is a list including IPv4 addresses or IPv4 CIDR blocks that will be excluded from the
+	// network corruption fault.
+	SourcesToFilter []*string `json:"SourcesToFilter,omitempty"`
+	FlowsPercent    *int      `json:"FlowsPercent"`
+	// DurationSeconds is the optional number of seconds after which the fault is automatically stopped, at most
+	// MaxDurationSeconds.
+	DurationSeconds *uint64 `json:"DurationSeconds,omitempty"`
+}
+
+// ValidateRequest validates required fields are present and its value.
+func (request NetworkCorruptionRequest) ValidateRequest() error {
+	if err := validatePercent(request.CorruptionPercent, "CorruptionPercent"); err != nil {
+		return err
+	}
+	return validateNetemFaultTraffic(request.Sources, request.SourcesToFilter, request.FlowsPercent,
+		request.DurationSeconds)
+}
+
+func (request NetworkCorruptionRequest) ToString() string {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Sprintf("Error: Unable to parse %s request with error %v.", CorruptionFaultType, err)
+	}
+	return string(data)
+}
+
+// NetworkDuplicationRequest is struct for the network packet duplication fault request.
+type NetworkDuplicationRequest struct {
+	DuplicationPercent *uint64 `json:"DuplicationPercent"`
+	// Sources is a list including IPv4 addresses or IPv4 CIDR blocks.
+	Sources []*string `json:"Sources"`
+	// SourcesToFilter is a list including IPv4 addresses or IPv4 CIDR blocks that will be excluded from the
+	// network duplication fault.
+	SourcesToFilter []*string `json:"SourcesToFilter,omitempty"`
+	FlowsPercent    *int      `json:"FlowsPercent"`
+	// DurationSeconds is the optional number of seconds after which the fault is automatically stopped, at most
+	// MaxDurationSeconds.
+	DurationSeconds *uint64 `json:"DurationSeconds,omitempty"`
+}
+
+// ValidateRequest validates required fields are present and its value.
+func (request NetworkDuplicationRequest) ValidateRequest() error {
+	if err := validatePercent(request.DuplicationPercent, "DuplicationPercent"); err != nil {
+		return err
+	}
+	return validateNetemFaultTraffic(request.Sources, request.SourcesToFilter, request.FlowsPercent,
+		request.DurationSeconds)
+}
+
+func (request NetworkDuplicationRequest) ToString() string {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Sprintf("Error: Unable to parse %s request with error %v.", DuplicationFaultType, err)
+	}
+	return string(data)
+}
+
+// NetworkReorderRequest is struct for the network packet reordering fault request.
+type NetworkReorderRequest struct {
+	// ReorderPercent is the percentage of packets sent immediately, the other packets being delayed by
+	// DelayMilliseconds. Packets are only reordered if the delay is larger than the time between packets.
+	ReorderPercent    *uint64 `json:"ReorderPercent"`
+	DelayMilliseconds *uint64 `json:"DelayMilliseconds"`
+	// Sources is a list including IPv4 addresses or IPv4 CIDR blocks.
+	Sources []*string `json:"Sources"`
+	// SourcesToFilter is a list including IPv4 addresses or IPv4 CIDR blocks that will be excluded from the
+	// network reorder fault.
+	SourcesToFilter []*string `json:"SourcesToFilter,omitempty"`
+	FlowsPercent    *int      `json:"FlowsPercent"`
+	// DurationSeconds is the optional number of seconds after which the fault is automatically stopped, at most
+	// MaxDurationSeconds.
+	DurationSeconds *uint64 `json:"DurationSeconds,omitempty"`
+}
+
+// ValidateRequest validates required fields are present and its value.
+func (request NetworkReorderRequest) ValidateRequest() error {
+	if err := validatePercent(request.ReorderPercent, "ReorderPercent"); err != nil {
+		return err
+	}
+	// netem only reorders packets if they are delayed.
+	if request.DelayMilliseconds == nil {
+		return fmt.Errorf(MissingRequiredFieldError, "DelayMilliseconds")
+	}
+	if *request.DelayMilliseconds == 0 {
+		return fmt.Errorf(InvalidValueError, strconv.FormatUint(*request.DelayMilliseconds, 10), "DelayMilliseconds")
+	}
+	return validateNetemFaultTraffic(request.Sources, request.SourcesToFilter, request.FlowsPercent,
+		request.DurationSeconds)
+}
+
+func (request NetworkReorderRequest) ToString() string {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Sprintf("Error: Unable to parse %s request with error %v.", ReorderFaultType, err)
+	}
+	return string(data)
+}
+
+// NetworkDNSRequest is struct for the network DNS fault request.
+type NetworkDNSRequest struct {
+	// Domains is a list of domain names whose queries are impacted by the fault. A domain name also matches
+	// all of its subdomains and may be written with a leading "*." wildcard.
+	Domains []*string `json:"Domains"`
+	// Action is what happens to the matching queries. It is one of "blackhole", "delay", "nxdomain" and
+	// "servfail".
+	Action *string `json:"Action"`
+	// DelayMilliseconds is only used, and required, by the "delay" action.
+	DelayMilliseconds *uint64 `json:"DelayMilliseconds,omitempty"`
+	// Resolver is the optional IP address of the DNS server the delayed queries are forwarded to. It defaults
+	// to the Amazon provided DNS server.
+	Resolver *string `json:"Resolver,omitempty"`
+	// DurationSeconds is the optional number of seconds after which the fault is automatically stopped, at most
+	// MaxDurationSeconds.
+	DurationSeconds *uint64 `json:"DurationSeconds,omitempty"`
+}
+
+// ValidateRequest validates required fields are present and its value.
+func (request NetworkDNSRequest) ValidateRequest() error {
+	if len(request.Domains) == 0 {
+		return fmt.Errorf(MissingRequiredFieldError, "Domains")
+	}
+	for _, domain := range request.Domains {
+		if domain == nil {
+			return fmt.Errorf(InvalidValueError, "null", "Domains")
+		}
+		if !isValidDomainPattern(*domain) {
+			return fmt.Errorf(InvalidValueError, *domain, "Domains")
+		}
+	}
+	if request.Action == nil {
+		return fmt.Errorf(MissingRequiredFieldError, "Action")
+	}
+	switch *request.Action {
+	case DNSActionBlackhole, DNSActionNXDomain, DNSActionServFail:
+	case DNSActionDelay:
+		if request.DelayMilliseconds == nil {
+			return fmt.Errorf(MissingRequiredFieldError, "DelayMilliseconds")
+		}
+		if *request.DelayMilliseconds == 0 {
+			return fmt.Errorf(InvalidValueError, strconv.FormatUint(*request.DelayMilliseconds, 10),
+				"DelayMilliseconds")
+		}
+	default:
+		return fmt.Errorf(InvalidValueError, *request.Action, "Action")
+	}
+	if request.Resolver != nil && !utils.IsIPv4(*request.Resolver) && !utils.IsIPv6(*request.Resolver) {
+		return fmt.Errorf(InvalidValueError, *request.Resolver, "Resolver")
+	}
+	return validateDurationSeconds(request.DurationSeconds)
+}
+
+func (request NetworkDNSRequest) ToString() string {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Sprintf("Error: Unable to parse %s request with error %v.", DNSFaultType, err)
+	}
+	return string(data)
+}
+
+// ResourceStressRequest is struct for the CPU stress and memory pressure fault requests.
+type ResourceStressRequest struct {
+	// Percent is the percentage of the CPU or memory quota of the task cgroup that is consumed by the fault.
+	Percent *uint64 `json:"Percent"`
+	// DurationSeconds is the number of seconds after which the fault is automatically stopped, at most
+	// MaxDurationSeconds. It is required since the resources consumed by the fault are taken from the task.
+	DurationSeconds *uint64 `json:"DurationSeconds"`
+}
+
+// ValidateRequest validates required fields are present and its value.
+func (request ResourceStressRequest) ValidateRequest() error {
+	if err := validatePercent(request.Percent, "Percent"); err != nil {
+		return err
+	}
+	if request.DurationSeconds == nil {
+		return fmt.Errorf(MissingRequiredFieldError, "DurationSeconds")
+	}
+	return validateDurationSeconds(request.DurationSeconds)
+}
+
+func (request ResourceStressRequest) ToString() string {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Sprintf("Error: Unable to parse resource stress request with error %v.", err)
+	}
+	return string(data)
+}
+
+// isValidDomainPattern checks that the domain pattern of a network DNS fault is a domain name, optionally
+// preceded by a "*." wildcard.
+func isValidDomainPattern(pattern string) bool {
+	domain := strings.TrimSuffix(strings.TrimPrefix(pattern, "*."), ".")
+	if domain == "" || len(domain) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(domain, ".") {
+		if label == "" || len(label) > 63 {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 func NewNetworkFaultInjectionSuccessResponse(status string) NetworkFaultInjectionResponse {
 	return NetworkFaultInjectionResponse{
 		Status: status,
@@ -223,6 +550,46 @@ func NewNetworkFaultInjectionErrorResponse(err string) NetworkFaultInjectionResp
 	}
 }
 
+// validateDurationSeconds requires the optional fault duration to be a positive number of seconds no greater than
+// MaxDurationSeconds.
+func validateDurationSeconds(durationSeconds *uint64) error {
+	if durationSeconds != nil && (*durationSeconds == 0 || *durationSeconds > MaxDurationSeconds) {
+		return fmt.Errorf(InvalidValueError, strconv.FormatUint(*durationSeconds, 10), "DurationSeconds")
+	}
+	return nil
+}
+
+// validatePercent requires the percentage to be present and an integer between 1 and 100 (inclusive).
+func validatePercent(percent *uint64, name string) error {
+	if percent == nil {
+		return fmt.Errorf(MissingRequiredFieldError, name)
+	}
+	if *percent < 1 || *percent > 100 {
+		return fmt.Errorf(InvalidValueError, strconv.FormatUint(*percent, 10), name)
+	}
+	return nil
+}
+
+// validateNetemFaultTraffic validates the parameters shared by the network faults injected with netem, which
+// select the traffic impacted by the fault and how long the fault lasts.
+func validateNetemFaultTraffic(sources, sourcesToFilter []*string, flowsPercent *int, durationSeconds *uint64) error {
+	if len(sources) == 0 {
+		return fmt.Errorf(MissingRequiredFieldError, "Sources")
+	}
+	if err := requireIPInRequestSources(sources, "Sources"); err != nil {
+		return err
+	}
+	if err := requireIPInRequestSources(sourcesToFilter, "SourcesToFilter"); err != nil {
+		return err
+	}
+	if flowsPercent != nil {
+		if *flowsPercent <= 0 || *flowsPercent > 100 {
+			return fmt.Errorf(InvalidValueError, strconv.Itoa(*flowsPercent), "flowsPercent")
+		}
+	}
+	return validateDurationSeconds(durationSeconds)
+}
+
 // requireIPInRequestSources requires each source is IPv4/IPv6 or IPv4/IPv6 CIDR block.
 func requireIPInRequestSources(sources []*string, sourcesType string) error {
 	for _, element := range sources {
@@ -15,6 +15,7 @@ package types
 
 import (
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -71,3 +72,181 @@ func TestRequireIPInRequestSources(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateDurationSeconds(t *testing.T) {
+	blackholeRequest := NetworkBlackholePortRequest{
+		Port:        aws.Uint16(1234),
+		Protocol:    aws.String("tcp"),
+		TrafficType: aws.String(TrafficTypeIngress),
+	}
+	latencyRequest := NetworkLatencyRequest{
+		DelayMilliseconds:  aws.Uint64(100),
+		JitterMilliseconds: aws.Uint64(10),
+		Sources:            aws.StringSlice([]string{"1.2.3.4"}),
+	}
+	packetLossRequest := NetworkPacketLossRequest{
+		LossPercent: aws.Uint64(10),
+		Sources:     aws.StringSlice([]string{"1.2.3.4"}),
+	}
+	tcs := []struct {
+		Name            string
+		DurationSeconds *uint64
+		ExpectedError   string
+	}{
+		{"Duration not set", nil, ""},
+		{"Positive duration", aws.Uint64(60), ""},
+		{"Zero duration", aws.Uint64(0), "invalid value 0 for parameter DurationSeconds"},
+		{"Maximum duration", aws.Uint64(MaxDurationSeconds), ""},
+		{"Duration above maximum", aws.Uint64(MaxDurationSeconds + 1),
+			"invalid value 604801 for parameter DurationSeconds"},
+		{"Duration overflowing time.Duration", aws.Uint64(math.MaxUint64),
+			"invalid value 18446744073709551615 for parameter DurationSeconds"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.Name, func(t *testing.T) {
+			blackholeRequest.DurationSeconds = tc.DurationSeconds
+			latencyRequest.DurationSeconds = tc.DurationSeconds
+			packetLossRequest.DurationSeconds = tc.DurationSeconds
+			for _, request := range []NetworkFaultRequest{blackholeRequest, latencyRequest, packetLossRequest} {
+				err := request.ValidateRequest()
+				if tc.ExpectedError == "" {
+					require.NoError(t, err)
+				} else {
+					require.EqualError(t, err, tc.ExpectedError)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateNetemFaultRequests(t *testing.T) {
+	sources := aws.StringSlice([]string{"1.2.3.4"})
+	tcs := []struct {
+		Name          string
+		Request       NetworkFaultRequest
+		ExpectedError string
+	}{
+		{"Valid bandwidth", NetworkBandwidthRequest{RateKbps: aws.Uint64(512), Sources: sources}, ""},
+		{"Missing rate", NetworkBandwidthRequest{Sources: sources}, "required parameter RateKbps is missing"},
+		{"Zero rate", NetworkBandwidthRequest{RateKbps: aws.Uint64(0), Sources: sources},
+			"invalid value 0 for parameter RateKbps"},
+		{"Bandwidth for all flows", NetworkBandwidthRequest{RateKbps: aws.Uint64(512), Sources: sources,
+			FlowsPercent: aws.Int(100)}, ""},
+		{"Bandwidth for some flows", NetworkBandwidthRequest{RateKbps: aws.Uint64(512), Sources: sources,
+			FlowsPercent: aws.Int(50)}, "invalid value 50 for parameter flowsPercent"},
+		{"Valid corruption", NetworkCorruptionRequest{CorruptionPercent: aws.Uint64(5), Sources: sources}, ""},
+		{"Missing corruption percent", NetworkCorruptionRequest{Sources: sources},
+			"required parameter CorruptionPercent is missing"},
+		{"Corruption percent over 100", NetworkCorruptionRequest{CorruptionPercent: aws.Uint64(101), Sources: sources},
+			"invalid value 101 for parameter CorruptionPercent"},
+		{"Valid duplication", NetworkDuplicationRequest{DuplicationPercent: aws.Uint64(100), Sources: sources}, ""},
+		{"Zero duplication percent", NetworkDuplicationRequest{DuplicationPercent: aws.Uint64(0), Sources: sources},
+			"invalid value 0 for parameter DuplicationPercent"},
+		{"Valid reorder", NetworkReorderRequest{ReorderPercent: aws.Uint64(25), DelayMilliseconds: aws.Uint64(10),
+			Sources: sources}, ""},
+		{"Missing reorder delay", NetworkReorderRequest{ReorderPercent: aws.Uint64(25), Sources: sources},
+			"required parameter DelayMilliseconds is missing"},
+		{"Zero reorder delay", NetworkReorderRequest{ReorderPercent: aws.Uint64(25), DelayMilliseconds: aws.Uint64(0),
+			Sources: sources}, "invalid value 0 for parameter DelayMilliseconds"},
+		{"Missing sources", NetworkCorruptionRequest{CorruptionPercent: aws.Uint64(5)},
+			"required parameter Sources is missing"},
+		{"Invalid source", NetworkCorruptionRequest{CorruptionPercent: aws.Uint64(5),
+			Sources: aws.StringSlice([]string{"invalid"})}, "invalid value invalid for parameter Sources"},
+		{"Invalid source to filter", NetworkDuplicationRequest{DuplicationPercent: aws.Uint64(5), Sources: sources,
+			SourcesToFilter: aws.StringSlice([]string{"invalid"})}, "invalid value invalid for parameter SourcesToFilter"},
+		{"Invalid flows percent", NetworkBandwidthRequest{RateKbps: aws.Uint64(512), Sources: sources,
+			FlowsPercent: aws.Int(0)}, "invalid value 0 for parameter flowsPercent"},
+		{"Zero duration", NetworkReorderRequest{ReorderPercent: aws.Uint64(25), DelayMilliseconds: aws.Uint64(10),
+			Sources: sources, DurationSeconds: aws.Uint64(0)}, "invalid value 0 for parameter DurationSeconds"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := tc.Request.ValidateRequest()
+			if tc.ExpectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.ExpectedError)
+			}
+		})
+	}
+}
+
+func TestValidateNetworkDNSRequest(t *testing.T) {
+	domains := aws.StringSlice([]string{"example.com", "*.amazonaws.com."})
+	tcs := []struct {
+		Name          string
+		Request       NetworkDNSRequest
+		ExpectedError string
+	}{
+		{"Valid blackhole", NetworkDNSRequest{Domains: domains, Action: aws.String(DNSActionBlackhole)}, ""},
+		{"Valid nxdomain", NetworkDNSRequest{Domains: domains, Action: aws.String(DNSActionNXDomain)}, ""},
+		{"Valid servfail", NetworkDNSRequest{Domains: domains, Action: aws.String(DNSActionServFail),
+			DurationSeconds: aws.Uint64(60)}, ""},
+		{"Valid delay", NetworkDNSRequest{Domains: domains, Action: aws.String(DNSActionDelay),
+			DelayMilliseconds: aws.Uint64(500), Resolver: aws.String("10.0.0.2")}, ""},
+		{"Missing domains", NetworkDNSRequest{Action: aws.String(DNSActionBlackhole)},
+			"required parameter Domains is missing"},
+		{"Invalid domain", NetworkDNSRequest{Domains: aws.StringSlice([]string{"exa mple.com"}),
+			Action: aws.String(DNSActionBlackhole)}, "invalid value exa mple.com for parameter Domains"},
+		{"Empty label", NetworkDNSRequest{Domains: aws.StringSlice([]string{"example..com"}),
+			Action: aws.String(DNSActionBlackhole)}, "invalid value example..com for parameter Domains"},
+		{"Wildcard only", NetworkDNSRequest{Domains: aws.StringSlice([]string{"*."}),
+			Action: aws.String(DNSActionBlackhole)}, "invalid value *. for parameter Domains"},
+		{"Missing action", NetworkDNSRequest{Domains: domains}, "required parameter Action is missing"},
+		{"Invalid action", NetworkDNSRequest{Domains: domains, Action: aws.String("refused")},
+			"invalid value refused for parameter Action"},
+		{"Missing delay", NetworkDNSRequest{Domains: domains, Action: aws.String(DNSActionDelay)},
+			"required parameter DelayMilliseconds is missing"},
+		{"Zero delay", NetworkDNSRequest{Domains: domains, Action: aws.String(DNSActionDelay),
+			DelayMilliseconds: aws.Uint64(0)}, "invalid value 0 for parameter DelayMilliseconds"},
+		{"Invalid resolver", NetworkDNSRequest{Domains: domains, Action: aws.String(DNSActionDelay),
+			DelayMilliseconds: aws.Uint64(500), Resolver: aws.String("resolver")},
+			"invalid value resolver for parameter Resolver"},
+		{"Zero duration", NetworkDNSRequest{Domains: domains, Action: aws.String(DNSActionBlackhole),
+			DurationSeconds: aws.Uint64(0)}, "invalid value 0 for parameter DurationSeconds"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := tc.Request.ValidateRequest()
+			if tc.ExpectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.ExpectedError)
+			}
+		})
+	}
+}
+
+func TestValidateResourceStressRequest(t *testing.T) {
+	tcs := []struct {
+		Name          string
+		Request       ResourceStressRequest
+		ExpectedError string
+	}{
+		{"Valid", ResourceStressRequest{Percent: aws.Uint64(80), DurationSeconds: aws.Uint64(60)}, ""},
+		{"Missing percent", ResourceStressRequest{DurationSeconds: aws.Uint64(60)},
+			"required parameter Percent is missing"},
+		{"Zero percent", ResourceStressRequest{Percent: aws.Uint64(0), DurationSeconds: aws.Uint64(60)},
+			"invalid value 0 for parameter Percent"},
+		{"Percent over 100", ResourceStressRequest{Percent: aws.Uint64(101), DurationSeconds: aws.Uint64(60)},
+			"invalid value 101 for parameter Percent"},
+		{"Missing duration", ResourceStressRequest{Percent: aws.Uint64(80)},
+			"required parameter DurationSeconds is missing"},
+		{"Zero duration", ResourceStressRequest{Percent: aws.Uint64(80), DurationSeconds: aws.Uint64(0)},
+			"invalid value 0 for parameter DurationSeconds"},
+		{"Maximum duration", ResourceStressRequest{Percent: aws.Uint64(80),
+			DurationSeconds: aws.Uint64(MaxDurationSeconds)}, ""},
+		{"Duration above maximum", ResourceStressRequest{Percent: aws.Uint64(80),
+			DurationSeconds: aws.Uint64(MaxDurationSeconds + 1)}, "invalid value 604801 for parameter DurationSeconds"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := tc.Request.ValidateRequest()
+			if tc.ExpectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.ExpectedError)
+			}
+		})
+	}
+}
@@ -14,6 +14,8 @@
 // This package defines some common types used by all task metadata functions
 package response
 
+import "time"
+
 // VolumeResponse is the schema for the volume response JSON object
 type VolumeResponse struct {
 	DockerName  string `json:"DockerName,omitempty"`
@@ -36,3 +38,14 @@ type Network struct {
 	IPv4Addresses []string `json:"IPv4Addresses,omitempty"`
 	IPv6Addresses []string `json:"IPv6Addresses,omitempty"`
 }
+
+// BlockedReasonResponse is the schema for the reason why a container can't progress to its next
+// status yet
+type BlockedReasonResponse struct {
+	Type      string    `json:"Type"`
+	Container string    `json:"Container,omitempty"`
+	Condition string    `json:"Condition,omitempty"`
+	Resource  string    `json:"Resource,omitempty"`
+	Message   string    `json:"Message"`
+	Since     time.Time `json:"Since"`
+}
@@ -98,6 +98,15 @@ type ContainerResponse struct {
 	Networks     []Network `json:"Networks,omitempty"`
 	Snapshotter  string    `json:"Snapshotter,omitempty"`
 	RestartCount *int      `json:"RestartCount,omitempty"`
+	// NextRestartAt is the time at which the container is restarted while its restart is
+	// delayed by the backoff of its restart policy.
+	NextRestartAt *time.Time `json:"NextRestartAt,omitempty"`
+	// RestartLimitReached is set once the container has been restarted the maximum number of
+	// times allowed by its restart policy.
+	RestartLimitReached bool `json:"RestartLimitReached,omitempty"`
+	// BlockedReason is the reason why the container can't progress to its next status yet, such
+	// as a dependency on another container which isn't healthy yet.
+	BlockedReason *response.BlockedReasonResponse `json:"BlockedReason,omitempty"`
 }
 
 // Network is the v4 Network response. It adds a bunch of information about network
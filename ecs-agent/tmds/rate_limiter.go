@@ -0,0 +1,83 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+package tmds
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/didip/tollbooth"
+	"github.com/didip/tollbooth/limiter"
+)
+
+// RateLimiter limits the rate of the requests to TMDS. Its rates can be changed while the server
+// is running.
+type RateLimiter struct {
+	steadyStateRate float64
+	burstRate       int
+	onLimitReached  func(http.ResponseWriter, *http.Request)
+	limiter         *limiter.Limiter
+	lock            sync.RWMutex
+}
+
+// NewRateLimiter returns a RateLimiter allowing steadyStateRate requests per second, with bursts
+// of up to burstRate requests
+func NewRateLimiter(steadyStateRate float64, burstRate int) *RateLimiter {
+	rateLimiter := &RateLimiter{
+		steadyStateRate: steadyStateRate,
+		burstRate:       burstRate,
+	}
+	rateLimiter.limiter = rateLimiter.newLimiter()
+	return rateLimiter
+}
+
+// SetRates changes the rates of the rate limiter. The requests made so far are forgotten.
+func (l *RateLimiter) SetRates(steadyStateRate float64, burstRate int) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.steadyStateRate = steadyStateRate
+	l.burstRate = burstRate
+	l.limiter = l.newLimiter()
+}
+
+// Rates returns the steady state rate and the burst rate of the rate limiter
+func (l *RateLimiter) Rates() (float64, int) {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return l.steadyStateRate, l.burstRate
+}
+
+// limitHandler returns a handler rejecting the requests exceeding the rates, and passing the
+// other requests to the next handler
+func (l *RateLimiter) limitHandler(next http.Handler,
+	onLimitReached func(http.ResponseWriter, *http.Request)) http.Handler {
+	l.lock.Lock()
+	l.onLimitReached = onLimitReached
+	l.limiter = l.newLimiter()
+	l.lock.Unlock()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l.lock.RLock()
+		lmt := l.limiter
+		l.lock.RUnlock()
+		tollbooth.LimitHandler(lmt, next).ServeHTTP(w, r)
+	})
+}
+
+// newLimiter returns a request limiter with the current rates
+func (l *RateLimiter) newLimiter() *limiter.Limiter {
+	return tollbooth.
+		NewLimiter(l.steadyStateRate, nil).
+		SetOnLimitReached(l.onLimitReached).
+		SetBurst(l.burstRate)
+}
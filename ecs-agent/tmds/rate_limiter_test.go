@@ -0,0 +1,63 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+package tmds
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mock_audit "github.com/aws/amazon-ecs-agent/ecs-agent/logger/audit/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that the rates of the rate limiter can be changed while the server is running.
+func TestRateLimiterSetRates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	auditLogger := mock_audit.NewMockAuditLogger(ctrl)
+	auditLogger.EXPECT().Log(gomock.Any(), http.StatusTooManyRequests, "").Times(1)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	rateLimiter := NewRateLimiter(1, 1)
+	server, err := NewServer(auditLogger, WithHandler(router), WithRateLimiter(rateLimiter))
+	require.NoError(t, err)
+
+	get := func() int {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		server.Handler.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusOK, get())
+	assert.Equal(t, http.StatusTooManyRequests, get())
+
+	rateLimiter.SetRates(10, 5)
+	steadyStateRate, burstRate := rateLimiter.Rates()
+	assert.Equal(t, float64(10), steadyStateRate)
+	assert.Equal(t, 5, burstRate)
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, get())
+	}
+}
@@ -23,7 +23,6 @@ import (
 	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/logging"
 	muxutils "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/utils/mux"
 
-	"github.com/didip/tollbooth"
 	"github.com/gorilla/mux"
 )
 
@@ -47,6 +46,7 @@ type Config struct {
 	writeTimeout    time.Duration // http server write timeout
 	steadyStateRate float64       // steady request rate limit
 	burstRate       int           // burst request rate limit
+	rateLimiter     *RateLimiter  // request rate limiter, takes precedence over the rates
 	handler         http.Handler  // HTTP handler with routes configured
 }
 
@@ -88,6 +88,13 @@ func WithBurstRate(burstRate int) ConfigOpt {
 	}
 }
 
+// Set TMDS request rate limiter, whose rates can be changed while the server is running
+func WithRateLimiter(rateLimiter *RateLimiter) ConfigOpt {
+	return func(c *Config) {
+		c.rateLimiter = rateLimiter
+	}
+}
+
 // Set TMDS handler
 func WithHandler(handler http.Handler) ConfigOpt {
 	return func(c *Config) {
@@ -111,18 +118,18 @@ func setup(auditLogger audit.AuditLogger, config *Config) (*http.Server, error)
 	}
 
 	// Define a reqeuest rate limiter
-	limiter := tollbooth.
-		NewLimiter(config.steadyStateRate, nil).
-		SetOnLimitReached(utils.LimitReachedHandler(auditLogger)).
-		SetBurst(config.burstRate)
+	rateLimiter := config.rateLimiter
+	if rateLimiter == nil {
+		rateLimiter = NewRateLimiter(config.steadyStateRate, config.burstRate)
+	}
 
 	// Log all requests and then pass through to muxRouter.
 	loggingMuxRouter := mux.NewRouter()
 
 	// rootPath is a path for any traffic to this endpoint
 	rootPath := "/" + muxutils.ConstructMuxVar("root", muxutils.AnythingRegEx)
-	loggingMuxRouter.Handle(rootPath, tollbooth.LimitHandler(
-		limiter, logging.NewLoggingHandler(config.handler)))
+	loggingMuxRouter.Handle(rootPath, rateLimiter.limitHandler(
+		logging.NewLoggingHandler(config.handler), utils.LimitReachedHandler(auditLogger)))
 
 	// explicitly enable path cleaning
 	loggingMuxRouter.SkipClean(false)
@@ -0,0 +1,178 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package tracing traces the operations of the agent on the tasks with OpenTelemetry. All the spans
+// of a task belong to a trace whose ID is derived from the task ARN, so that the lifecycle of a task
+// can be followed end to end, even across restarts of the agent.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// tracerName is the name of the tracer of the agent spans
+	tracerName = "github.com/aws/amazon-ecs-agent"
+
+	// TaskARNKey is the attribute key of the task ARN
+	TaskARNKey = attribute.Key("aws.ecs.task.arn")
+	// TaskIDKey is the attribute key of the task ID
+	TaskIDKey = attribute.Key("aws.ecs.task.id")
+	// ContainerNameKey is the attribute key of the container name
+	ContainerNameKey = attribute.Key("aws.ecs.container.name")
+	// ContainerImageKey is the attribute key of the container image
+	ContainerImageKey = attribute.Key("aws.ecs.container.image")
+	// RuntimeIDKey is the attribute key of the runtime ID of the container
+	RuntimeIDKey = attribute.Key("aws.ecs.container.runtime_id")
+	// ResourceNameKey is the attribute key of the name of a task resource
+	ResourceNameKey = attribute.Key("aws.ecs.resource.name")
+	// StatusKey is the attribute key of the status a task, a container or a resource transitions to
+	StatusKey = attribute.Key("aws.ecs.status")
+)
+
+// Config is the configuration of the export of the spans
+type Config struct {
+	// Endpoint is the URL of the OTLP/HTTP traces endpoint of the collector, e.g.
+	// http://localhost:4318/v1/traces
+	Endpoint string
+	// ServiceName is the name of the service reported with the spans
+	ServiceName string
+	// ServiceVersion is the version of the service reported with the spans
+	ServiceVersion string
+}
+
+// ShutdownFunc flushes the pending spans and stops their export
+type ShutdownFunc func(ctx context.Context) error
+
+// Init exports the spans of the agent to the OTLP/HTTP endpoint of the configuration. Until Init is
+// called, spans are not recorded.
+func Init(cfg Config) (ShutdownFunc, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tracing endpoint %q: %w", cfg.Endpoint, err)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid tracing endpoint %q: an http or https URL is expected", cfg.Endpoint)
+	}
+	exporter, err := otlptracehttp.New(context.Background(), otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the OTLP trace exporter: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithIDGenerator(&taskIDGenerator{}),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", cfg.ServiceName),
+			attribute.String("service.version", cfg.ServiceVersion),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// taskRootKey is the context key of the task ARN whose root span is being started
+type taskRootKey struct{}
+
+// StartTaskSpan starts the root span of the trace of a task. The trace and span IDs of the root span
+// are derived from the task ARN, which makes the spans started by StartSpan with a TaskContext of
+// the same task its children. It must be started at most once for each task.
+func StartTaskSpan(ctx context.Context, taskARN string, name string,
+	attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	ctx = trace.ContextWithSpanContext(ctx, trace.SpanContext{})
+	ctx = context.WithValue(ctx, taskRootKey{}, taskARN)
+	attrs = append(attrs, TaskARNKey.String(taskARN))
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...),
+		trace.WithNewRoot())
+}
+
+// TaskContext returns a context whose spans belong to the trace of the task. Spans started with it
+// are children of the span of ctx if it has one, or of the root span of the task otherwise.
+func TaskContext(ctx context.Context, taskARN string) context.Context {
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	traceID, spanID := taskRootIDs(taskARN)
+	return trace.ContextWithRemoteSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	}))
+}
+
+// StartSpan starts a span as a child of the span of ctx, if any
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan records the error of the operation, if any, and ends the span
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TaskTraceID returns the ID of the trace of the task
+func TaskTraceID(taskARN string) trace.TraceID {
+	traceID, _ := taskRootIDs(taskARN)
+	return traceID
+}
+
+// taskRootIDs derives the trace ID and the span ID of the root span of a task from its ARN
+func taskRootIDs(taskARN string) (trace.TraceID, trace.SpanID) {
+	sum := sha256.Sum256([]byte(taskARN))
+	var traceID trace.TraceID
+	var spanID trace.SpanID
+	copy(traceID[:], sum[:len(traceID)])
+	copy(spanID[:], sum[len(traceID):len(traceID)+len(spanID)])
+	return traceID, spanID
+}
+
+// taskIDGenerator generates the IDs of the root span of a task from its ARN, and random IDs for
+// all the other spans.
+type taskIDGenerator struct{}
+
+// NewIDs returns the trace ID and the span ID of a new root span
+func (g *taskIDGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	if taskARN, ok := ctx.Value(taskRootKey{}).(string); ok {
+		return taskRootIDs(taskARN)
+	}
+	var traceID trace.TraceID
+	for !traceID.IsValid() {
+		rand.Read(traceID[:])
+	}
+	return traceID, g.NewSpanID(ctx, traceID)
+}
+
+// NewSpanID returns the span ID of a new child span
+func (g *taskIDGenerator) NewSpanID(ctx context.Context, traceID trace.TraceID) trace.SpanID {
+	var spanID trace.SpanID
+	for !spanID.IsValid() {
+		rand.Read(spanID[:])
+	}
+	return spanID
+}
@@ -0,0 +1,125 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testTaskARN      = "arn:aws:ecs:us-west-2:123456789012:task/cluster/0123456789abcdef"
+	otherTestTaskARN = "arn:aws:ecs:us-west-2:123456789012:task/cluster/fedcba9876543210"
+)
+
+// setupTestTracerProvider records the spans ended during the test in the returned recorder
+func setupTestTracerProvider(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(recorder),
+		sdktrace.WithIDGenerator(&taskIDGenerator{}),
+	)
+	otel.SetTracerProvider(provider)
+	return recorder
+}
+
+func TestTaskTraceID(t *testing.T) {
+	assert.True(t, TaskTraceID(testTaskARN).IsValid())
+	assert.Equal(t, TaskTraceID(testTaskARN), TaskTraceID(testTaskARN))
+	assert.NotEqual(t, TaskTraceID(testTaskARN), TaskTraceID(otherTestTaskARN))
+}
+
+func TestTaskSpans(t *testing.T) {
+	recorder := setupTestTracerProvider(t)
+
+	_, rootSpan := StartTaskSpan(context.Background(), testTaskARN, "LaunchTask")
+	ctx, span := StartSpan(TaskContext(context.Background(), testTaskARN), "PullImage")
+	_, childSpan := StartSpan(TaskContext(ctx, testTaskARN), "PullImageLayer")
+	EndSpan(childSpan, nil)
+	EndSpan(span, errors.New("pull failed"))
+	EndSpan(rootSpan, nil)
+	_, otherSpan := StartSpan(context.Background(), "Other")
+	EndSpan(otherSpan, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+	child, pull, root, other := spans[0], spans[1], spans[2], spans[3]
+
+	traceID, rootSpanID := taskRootIDs(testTaskARN)
+	assert.Equal(t, traceID, root.SpanContext().TraceID())
+	assert.Equal(t, rootSpanID, root.SpanContext().SpanID())
+	assert.False(t, root.Parent().IsValid())
+	assert.Contains(t, root.Attributes(), TaskARNKey.String(testTaskARN))
+
+	assert.Equal(t, traceID, pull.SpanContext().TraceID())
+	assert.Equal(t, rootSpanID, pull.Parent().SpanID())
+	assert.Equal(t, codes.Error, pull.Status().Code)
+	assert.Equal(t, "pull failed", pull.Status().Description)
+
+	assert.Equal(t, traceID, child.SpanContext().TraceID())
+	assert.Equal(t, pull.SpanContext().SpanID(), child.Parent().SpanID())
+	assert.Equal(t, codes.Unset, child.Status().Code)
+
+	assert.True(t, other.SpanContext().TraceID().IsValid())
+	assert.NotEqual(t, traceID, other.SpanContext().TraceID())
+}
+
+func TestTaskContext(t *testing.T) {
+	spanContext := trace.SpanContextFromContext(TaskContext(context.Background(), testTaskARN))
+	assert.Equal(t, TaskTraceID(testTaskARN), spanContext.TraceID())
+	assert.True(t, spanContext.IsRemote())
+	assert.True(t, spanContext.IsSampled())
+
+	// The span of the context is kept
+	ctx := TaskContext(context.Background(), otherTestTaskARN)
+	assert.Equal(t, ctx, TaskContext(ctx, testTaskARN))
+}
+
+func TestInitInvalidEndpoint(t *testing.T) {
+	for _, endpoint := range []string{"", "localhost:4318", "ftp://localhost:4318", "http://"} {
+		t.Run(endpoint, func(t *testing.T) {
+			_, err := Init(Config{Endpoint: endpoint})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestInit(t *testing.T) {
+	shutdown, err := Init(Config{
+		Endpoint:       "http://localhost:4318/v1/traces",
+		ServiceName:    "test",
+		ServiceVersion: "1.0.0",
+	})
+	require.NoError(t, err)
+	_, span := StartSpan(context.Background(), "Test")
+	assert.True(t, span.IsRecording())
+	span.End()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	shutdown(ctx)
+}
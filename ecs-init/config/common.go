@@ -85,6 +85,37 @@ const (
 	// GPUSupportEnvVar indicates that the AMI has support for GPU
 	GPUSupportEnvVar = "ECS_ENABLE_GPU_SUPPORT"
 
+	// CDISupportEnvVar indicates that the agent injects the devices of the CDI spec files of the host
+	CDISupportEnvVar = "ECS_ENABLE_CDI_SUPPORT"
+
+	// SecretFilesEnvVar indicates that the agent delivers the secrets of type MOUNT_POINT to the containers as files
+	SecretFilesEnvVar = "ECS_ENABLE_SECRET_FILES"
+
+	// EngineAuthTypeEnvVar is the type of the auth data of the private registries used by the agent
+	EngineAuthTypeEnvVar = "ECS_ENGINE_AUTH_TYPE"
+
+	// CredentialHelperAuthType is the engine auth type getting the credentials of the private registries from the
+	// docker credential helpers
+	CredentialHelperAuthType = "credentialhelper"
+
+	// ImageTrustPolicyFileEnvVar is the path of the trust policy file the agent verifies the signatures of the images
+	// against
+	ImageTrustPolicyFileEnvVar = "ECS_IMAGE_TRUST_POLICY_FILE"
+
+	// ImageCleanupPolicyEnvVar selects the policy used by the agent to remove images
+	ImageCleanupPolicyEnvVar = "ECS_IMAGE_CLEANUP_POLICY"
+
+	// DiskUsageImageCleanupPolicy is the image cleanup policy removing images based on the usage of the disk holding
+	// the Docker data root
+	DiskUsageImageCleanupPolicy = "disk-usage"
+
+	// TerminationDrainingEnvVar indicates that the agent drains the container instance before exiting
+	TerminationDrainingEnvVar = "ECS_ENABLE_TERMINATION_DRAINING"
+
+	// TerminationDrainingTimeoutEnvVar specifies for how long the agent waits for the tasks to be stopped
+	// after draining the container instance
+	TerminationDrainingTimeoutEnvVar = "ECS_TERMINATION_DRAINING_TIMEOUT"
+
 	// DockerHostEnvVar is the environment variable that specifies the location of the Docker daemon socket.
 	DockerHostEnvVar = "DOCKER_HOST"
 
@@ -189,6 +220,12 @@ func AgentDataDirectory() string {
 	return directoryPrefix + "/var/lib/ecs/data"
 }
 
+// InstanceShutdownFile returns the location on disk of the file telling the Agent that it's stopped because
+// the instance is shutting down, which the Agent reads from its data directory
+func InstanceShutdownFile() string {
+	return AgentDataDirectory() + "/instance-shutdown"
+}
+
 // CacheDirectory returns the location on disk where Agent images should be cached
 func CacheDirectory() string {
 	return directoryPrefix + "/var/cache/ecs"
@@ -264,6 +301,24 @@ func MountDirectoryEBS() string {
 	return directoryPrefix + "/mnt/ecs/ebs"
 }
 
+// SecretFilesDirectory returns the location on the tmpfs of the host where the Agent writes the secrets delivered
+// as files to the containers
+func SecretFilesDirectory() string {
+	return directoryPrefix + "/run/ecs/secrets"
+}
+
+// CredentialHelpersDirectory returns the location on the host of the docker credential helper binaries run by the
+// Agent for the credentialhelper engine auth type
+func CredentialHelpersDirectory() string {
+	return directoryPrefix + "/var/lib/ecs/credential-helpers"
+}
+
+// ImageVerifiersDirectory returns the location on the host of the cosign and notation binaries run by the Agent to
+// verify the signatures of the images
+func ImageVerifiersDirectory() string {
+	return directoryPrefix + "/var/lib/ecs/image-verifiers"
+}
+
 // HostCertsDirPath returns the CA store path on the host
 func HostCertsDirPath() string {
 	if _, err := OsStat(hostCertsDirPath); err != nil {
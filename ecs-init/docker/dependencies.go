@@ -48,6 +48,7 @@ type dockerclient interface {
 	WaitContainer(id string) (int, error)
 	StopContainer(id string, timeout uint) error
 	Ping() error
+	Info() (*godocker.DockerInfo, error)
 	FilteredListNetworks(opts godocker.NetworkFilterOpts) ([]godocker.Network, error)
 }
 
@@ -218,6 +219,10 @@ func (d *_dockerclient) Ping() error {
 	return d.docker.Ping()
 }
 
+func (d *_dockerclient) Info() (*godocker.DockerInfo, error) {
+	return d.docker.Info()
+}
+
 type fileSystem interface {
 	ReadFile(filename string) ([]byte, error)
 }
@@ -77,6 +77,21 @@ func (mr *MockdockerclientMockRecorder) FilteredListNetworks(opts interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilteredListNetworks", reflect.TypeOf((*Mockdockerclient)(nil).FilteredListNetworks), opts)
 }
 
+// Info mocks base method.
+func (m *Mockdockerclient) Info() (*docker.DockerInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Info")
+	ret0, _ := ret[0].(*docker.DockerInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Info indicates an expected call of Info.
+func (mr *MockdockerclientMockRecorder) Info() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*Mockdockerclient)(nil).Info))
+}
+
 // ListContainers mocks base method.
 func (m *Mockdockerclient) ListContainers(opts docker.ListContainersOptions) ([]docker.APIContainers, error) {
 	m.ctrl.T.Helper()
@@ -38,6 +38,11 @@ const (
 	logDir = "/log"
 	// dataDir specifies the location of Agent state file in the container
 	dataDir = "/data"
+	// defaultAgentPath is the PATH of the Agent container set by docker, since the Agent image doesn't set one
+	defaultAgentPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+	// dockerDataRootDir specifies the location of the Docker data root of the host in the container. The Agent
+	// reads the usage of the disk holding it when the disk-usage image cleanup policy is selected
+	dockerDataRootDir = "/docker-data-root"
 	// readOnly specifies the read-only suffix for mounting host volumes
 	// when creating the Agent container
 	readOnly = ":ro"
@@ -70,6 +75,12 @@ const (
 	// pingDockerSocketMaxRetries specifies the maximum number of retries for ping to return
 	// a successful response from the docker socket
 	pingDockerSocketMaxRetries = 5
+	// stopAgentTimeout specifies the time docker waits for the Agent to exit after sending it SIGTERM
+	stopAgentTimeout = 10 * time.Second
+	// defaultTerminationDrainingTimeout and maximumTerminationDrainingTimeout mirror the default and the maximum
+	// of ECS_TERMINATION_DRAINING_TIMEOUT in the Agent's config
+	defaultTerminationDrainingTimeout = time.Minute
+	maximumTerminationDrainingTimeout = 5 * time.Minute
 	// DefaultCgroupMountpoint is the default mount point for the cgroup subsystem
 	DefaultCgroupMountpoint = "/sys/fs/cgroup"
 	// pluginSocketFilesDir specifies the location of UNIX domain socket files of
@@ -157,6 +168,12 @@ var pluginDirs = []string{
 	pluginSpecFilesUsrDir,
 }
 
+// cdiSpecDirs are the dirs of the Container Device Interface (CDI) spec files
+var cdiSpecDirs = []string{
+	"/etc/cdi",
+	"/var/run/cdi",
+}
+
 var (
 	dockerOnce                    sync.Once
 	dockerClient                  *client
@@ -165,6 +182,9 @@ var (
 	execCommand                   = exec.Command
 	execLookPath                  = exec.LookPath
 	checkNvidiaGPUDevicesPresence = nvidiaGPUDevicesPresent
+	checkSystemShuttingDown       = isSystemShuttingDown
+	writeFile                     = os.WriteFile
+	removeFile                    = os.Remove
 	// ErrNoBridgeNetwork indicates no docker bridge network interface was found
 	ErrNoBridgeNetwork = errors.New(
 		"unable to find any virtual docker bridge network interfaces on the host")
@@ -264,6 +284,11 @@ func (c *client) findAgentContainer() (string, error) {
 
 // StartAgent starts the Agent in Docker and returns the exit code from the container
 func (c *client) StartAgent() (int, error) {
+	// The Agent is started, so any shutdown file left from a previous stop is stale
+	if err := removeFile(config.InstanceShutdownFile()); err != nil && !os.IsNotExist(err) {
+		log.Warnf("Unable to remove the instance shutdown file: %v", err)
+	}
+
 	envVarsFromFiles := c.LoadEnvVars()
 
 	hostConfig := c.getHostConfig(envVarsFromFiles)
@@ -341,6 +366,18 @@ func (c *client) getContainerConfig(envVarsFromFiles map[string]string) *godocke
 		envVariables[envKey] = envValue
 	}
 
+	// the agent runs the credential helpers and the image signature verifiers mounted from the host
+	var binaryDirs []string
+	if envVarsFromFiles[config.EngineAuthTypeEnvVar] == config.CredentialHelperAuthType {
+		binaryDirs = append(binaryDirs, config.CredentialHelpersDirectory())
+	}
+	if envVarsFromFiles[config.ImageTrustPolicyFileEnvVar] != "" {
+		binaryDirs = append(binaryDirs, config.ImageVerifiersDirectory())
+	}
+	if len(binaryDirs) > 0 {
+		envVariables["PATH"] = strings.Join(append(binaryDirs, defaultAgentPath), ":")
+	}
+
 	envVar := os.Getenv(config.ECSGMSASupportEnvVar)
 	// check for the domain join only if ECS_GMSA_SUPPORTED environment variable is set to true
 	if envVar == "true" {
@@ -489,6 +526,37 @@ func (c *client) getHostConfig(envVarsFromFiles map[string]string) *godocker.Hos
 			}
 		}
 
+		if key == config.CDISupportEnvVar && val == "true" {
+			// bind mount the CDI spec dirs read by the agent
+			binds = append(binds, getCDISpecDirBinds()...)
+		}
+
+		if key == config.SecretFilesEnvVar && val == "true" {
+			// bind mount the dir of the host tmpfs where the agent writes the secrets delivered as files
+			binds = append(binds, config.SecretFilesDirectory()+":"+config.SecretFilesDirectory())
+		}
+
+		if key == config.EngineAuthTypeEnvVar && val == config.CredentialHelperAuthType {
+			// bind mount the dir of the credential helper binaries run by the agent
+			if credentialHelpersDir := config.CredentialHelpersDirectory(); isPathValid(credentialHelpersDir, true) {
+				binds = append(binds, credentialHelpersDir+":"+credentialHelpersDir+readOnly)
+			}
+		}
+
+		if key == config.ImageTrustPolicyFileEnvVar && val != "" {
+			// bind mount the dir of the image signature verifier binaries run by the agent
+			if imageVerifiersDir := config.ImageVerifiersDirectory(); isPathValid(imageVerifiersDir, true) {
+				binds = append(binds, imageVerifiersDir+":"+imageVerifiersDir+readOnly)
+			}
+		}
+
+		if key == config.ImageCleanupPolicyEnvVar && val == config.DiskUsageImageCleanupPolicy {
+			// bind mount the Docker data root so that the agent can find the usage of the disk holding it
+			if dockerDataRootBind, ok := c.getDockerDataRootBind(); ok {
+				binds = append(binds, dockerDataRootBind)
+			}
+		}
+
 		if key == config.ECSGMSASupportEnvVar && val == "true" {
 			// only bind if the env variable gmsa support is set to true and the path to bind exists
 			credentialsfetcherSocketBind, volumeExists := getCredentialsFetcherSocketBind()
@@ -506,6 +574,29 @@ func (c *client) getHostConfig(envVarsFromFiles map[string]string) *godocker.Hos
 	return createHostConfig(binds)
 }
 
+// getCDISpecDirBinds returns the read-only binds of the CDI spec dirs present on the host. The dirs are mounted at
+// the same path so that the paths of the specs are the same in the agent container and on the host.
+func getCDISpecDirBinds() []string {
+	var binds []string
+	for _, specDir := range cdiSpecDirs {
+		if isPathValid(specDir, true) {
+			binds = append(binds, specDir+":"+specDir+readOnly)
+		}
+	}
+	return binds
+}
+
+// getDockerDataRootBind returns the read-only bind of the Docker data root of the host, which is looked up from
+// Docker.
+func (c *client) getDockerDataRootBind() (string, bool) {
+	info, err := c.docker.Info()
+	if err != nil || info.DockerRootDir == "" {
+		log.Warnf("Unable to find the Docker data root, the disk-usage image cleanup policy won't be applied: %v", err)
+		return "", false
+	}
+	return info.DockerRootDir + ":" + dockerDataRootDir + readOnly, true
+}
+
 // getCredentialsFetcherSocketBind returns the corresponding bind for credentials fetcher socket.
 func getCredentialsFetcherSocketBind() (string, bool) {
 	credentialsFetcherUnixSocketHostPath, ok := config.HostCredentialsFetcherPath()
@@ -633,7 +724,16 @@ func (c *client) StopAgent() error {
 		log.Info("No running Agent to stop")
 		return nil
 	}
-	stopContainerTimeoutSeconds := uint(10)
+	envVariables := c.loadStopEnvVars()
+	if envVariables[config.TerminationDrainingEnvVar] == "true" && checkSystemShuttingDown() {
+		// The Agent only drains the container instance when the instance is shutting down, and not when
+		// the Agent is only restarted
+		log.Info("Instance is shutting down, the Agent will drain the container instance")
+		if err := writeFile(config.InstanceShutdownFile(), nil, 0644); err != nil {
+			log.Warnf("Unable to create the instance shutdown file: %v", err)
+		}
+	}
+	stopContainerTimeoutSeconds := uint(getStopAgentTimeout(envVariables).Seconds())
 	err = c.docker.StopContainer(id, stopContainerTimeoutSeconds)
 	if _, ok := err.(*godocker.ContainerNotRunning); ok {
 		log.Info("Agent is already stopped")
@@ -642,6 +742,47 @@ func (c *client) StopAgent() error {
 	return err
 }
 
+// loadStopEnvVars returns the environment variables of the Agent which change how it's stopped
+func (c *client) loadStopEnvVars() map[string]string {
+	envVariables := c.loadCustomInstanceEnvVars()
+	for envKey, envValue := range c.loadUsrEnvVars() {
+		envVariables[envKey] = envValue
+	}
+	return envVariables
+}
+
+// getStopAgentTimeout returns the time docker waits for the Agent to exit before killing it. When termination
+// draining is enabled, the Agent waits up to the draining timeout for its tasks to be stopped before saving
+// its state, so the draining timeout is added to the stop timeout.
+func getStopAgentTimeout(envVariables map[string]string) time.Duration {
+	if envVariables[config.TerminationDrainingEnvVar] != "true" {
+		return stopAgentTimeout
+	}
+
+	drainingTimeout := defaultTerminationDrainingTimeout
+	if envValue := envVariables[config.TerminationDrainingTimeoutEnvVar]; envValue != "" {
+		parsed, err := time.ParseDuration(envValue)
+		if err != nil || parsed < time.Second {
+			log.Warnf("Invalid value %q for %s, using the default value: %s",
+				envValue, config.TerminationDrainingTimeoutEnvVar, defaultTerminationDrainingTimeout.String())
+		} else {
+			drainingTimeout = parsed
+		}
+	}
+	if drainingTimeout > maximumTerminationDrainingTimeout {
+		drainingTimeout = maximumTerminationDrainingTimeout
+	}
+	return drainingTimeout + stopAgentTimeout
+}
+
+// isSystemShuttingDown returns whether systemd is shutting down the instance, as opposed to only restarting
+// the ecs service
+func isSystemShuttingDown() bool {
+	// is-system-running exits with a non-zero code when the system isn't running, so only its output matters
+	output, _ := execCommand("systemctl", "is-system-running").Output()
+	return strings.TrimSpace(string(output)) == "stopping"
+}
+
 // isDomainJoined is used to validate if container instance is part of a valid active directory.
 func isDomainJoined() bool {
 	realmPath, err := execLookPath("realm")
@@ -19,7 +19,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/amazon-ecs-agent/ecs-init/config"
 	"github.com/aws/amazon-ecs-agent/ecs-init/gpu"
@@ -433,6 +435,367 @@ func TestStartAgentWithGPUConfig(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestStartAgentWithCDIConfig(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	isPathValid = func(path string, isDir bool) bool {
+		return path == "/etc/cdi"
+	}
+	defer func() {
+		isPathValid = defaultIsPathValid
+	}()
+
+	config.OsStat = func(name string) (os.FileInfo, error) {
+		return nil, nil
+	}
+	defer func() {
+		config.OsStat = os.Stat
+	}()
+
+	envFile := "\nECS_ENABLE_CDI_SUPPORT=true\n"
+	containerID := "container id"
+
+	mockFS := NewMockfileSystem(mockCtrl)
+	mockDocker := NewMockdockerclient(mockCtrl)
+
+	mockFS.EXPECT().ReadFile(config.InstanceConfigFile()).Return([]byte(envFile), nil).AnyTimes()
+	mockFS.EXPECT().ReadFile(config.AgentConfigFile()).Return(nil, errors.New("not found")).AnyTimes()
+	mockDocker.EXPECT().CreateContainer(gomock.Any()).Do(func(opts godocker.CreateContainerOptions) {
+		validateCommonCreateContainerOptions(t, opts)
+		assert.Contains(t, opts.HostConfig.Binds, "/etc/cdi:/etc/cdi:ro")
+		assert.NotContains(t, opts.HostConfig.Binds, "/var/run/cdi:/var/run/cdi:ro")
+	}).Return(&godocker.Container{
+		ID: containerID,
+	}, nil)
+	mockDocker.EXPECT().StartContainer(containerID, nil)
+	mockDocker.EXPECT().WaitContainer(containerID)
+
+	client := &client{
+		docker: mockDocker,
+		fs:     mockFS,
+	}
+
+	_, err := client.StartAgent()
+	assert.NoError(t, err)
+}
+
+func TestStartAgentWithSecretFilesConfig(t *testing.T) {
+	testCases := []struct {
+		name         string
+		envFile      string
+		expectedBind bool
+	}{
+		{
+			name:         "secret files enabled",
+			envFile:      "\nECS_ENABLE_SECRET_FILES=true\n",
+			expectedBind: true,
+		},
+		{
+			name:    "secret files disabled",
+			envFile: "\nECS_ENABLE_SECRET_FILES=false\n",
+		},
+		{
+			name:    "secret files not set",
+			envFile: "\nAGENT_TEST_VAR=val\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			isPathValid = func(path string, isDir bool) bool {
+				return false
+			}
+			defer func() {
+				isPathValid = defaultIsPathValid
+			}()
+
+			config.OsStat = func(name string) (os.FileInfo, error) {
+				return nil, nil
+			}
+			defer func() {
+				config.OsStat = os.Stat
+			}()
+
+			containerID := "container id"
+			secretFilesBind := config.SecretFilesDirectory() + ":" + config.SecretFilesDirectory()
+
+			mockFS := NewMockfileSystem(mockCtrl)
+			mockDocker := NewMockdockerclient(mockCtrl)
+
+			mockFS.EXPECT().ReadFile(config.InstanceConfigFile()).Return([]byte(tc.envFile), nil).AnyTimes()
+			mockFS.EXPECT().ReadFile(config.AgentConfigFile()).Return(nil, errors.New("not found")).AnyTimes()
+			mockDocker.EXPECT().CreateContainer(gomock.Any()).Do(func(opts godocker.CreateContainerOptions) {
+				validateCommonCreateContainerOptions(t, opts)
+				if tc.expectedBind {
+					assert.Contains(t, opts.HostConfig.Binds, secretFilesBind)
+				} else {
+					assert.NotContains(t, opts.HostConfig.Binds, secretFilesBind)
+				}
+			}).Return(&godocker.Container{
+				ID: containerID,
+			}, nil)
+			mockDocker.EXPECT().StartContainer(containerID, nil)
+			mockDocker.EXPECT().WaitContainer(containerID)
+
+			client := &client{
+				docker: mockDocker,
+				fs:     mockFS,
+			}
+
+			_, err := client.StartAgent()
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestStartAgentWithDiskUsageImageCleanupPolicy(t *testing.T) {
+	dockerDataRootBind := "/mnt/docker:" + dockerDataRootDir + readOnly
+	testCases := []struct {
+		name         string
+		envFile      string
+		info         *godocker.DockerInfo
+		infoErr      error
+		expectedBind bool
+	}{
+		{
+			name:         "disk-usage policy",
+			envFile:      "\nECS_IMAGE_CLEANUP_POLICY=disk-usage\n",
+			info:         &godocker.DockerInfo{DockerRootDir: "/mnt/docker"},
+			expectedBind: true,
+		},
+		{
+			name:    "disk-usage policy without docker data root",
+			envFile: "\nECS_IMAGE_CLEANUP_POLICY=disk-usage\n",
+			infoErr: errors.New("docker error"),
+		},
+		{
+			name:    "lru policy",
+			envFile: "\nECS_IMAGE_CLEANUP_POLICY=lru\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			isPathValid = func(path string, isDir bool) bool {
+				return false
+			}
+			defer func() {
+				isPathValid = defaultIsPathValid
+			}()
+
+			config.OsStat = func(name string) (os.FileInfo, error) {
+				return nil, nil
+			}
+			defer func() {
+				config.OsStat = os.Stat
+			}()
+
+			containerID := "container id"
+
+			mockFS := NewMockfileSystem(mockCtrl)
+			mockDocker := NewMockdockerclient(mockCtrl)
+
+			mockFS.EXPECT().ReadFile(config.InstanceConfigFile()).Return([]byte(tc.envFile), nil).AnyTimes()
+			mockFS.EXPECT().ReadFile(config.AgentConfigFile()).Return(nil, errors.New("not found")).AnyTimes()
+			if tc.info != nil || tc.infoErr != nil {
+				mockDocker.EXPECT().Info().Return(tc.info, tc.infoErr)
+			}
+			mockDocker.EXPECT().CreateContainer(gomock.Any()).Do(func(opts godocker.CreateContainerOptions) {
+				validateCommonCreateContainerOptions(t, opts)
+				if tc.expectedBind {
+					assert.Contains(t, opts.HostConfig.Binds, dockerDataRootBind)
+				} else {
+					assert.NotContains(t, opts.HostConfig.Binds, dockerDataRootBind)
+				}
+			}).Return(&godocker.Container{
+				ID: containerID,
+			}, nil)
+			mockDocker.EXPECT().StartContainer(containerID, nil)
+			mockDocker.EXPECT().WaitContainer(containerID)
+
+			client := &client{
+				docker: mockDocker,
+				fs:     mockFS,
+			}
+
+			_, err := client.StartAgent()
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestStartAgentWithCredentialHelperAuthType(t *testing.T) {
+	credentialHelpersDir := config.CredentialHelpersDirectory()
+	credentialHelpersBind := credentialHelpersDir + ":" + credentialHelpersDir + readOnly
+	credentialHelpersPath := "PATH=" + credentialHelpersDir + ":" + defaultAgentPath
+	testCases := []struct {
+		name             string
+		envFile          string
+		helpersDirExists bool
+		expectedBind     bool
+		expectedPath     bool
+	}{
+		{
+			name:             "credentialhelper auth type",
+			envFile:          "\nECS_ENGINE_AUTH_TYPE=credentialhelper\n",
+			helpersDirExists: true,
+			expectedBind:     true,
+			expectedPath:     true,
+		},
+		{
+			name:         "credentialhelper auth type without credential helpers dir",
+			envFile:      "\nECS_ENGINE_AUTH_TYPE=credentialhelper\n",
+			expectedPath: true,
+		},
+		{
+			name:             "docker auth type",
+			envFile:          "\nECS_ENGINE_AUTH_TYPE=docker\n",
+			helpersDirExists: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			isPathValid = func(path string, isDir bool) bool {
+				return tc.helpersDirExists && path == credentialHelpersDir
+			}
+			defer func() {
+				isPathValid = defaultIsPathValid
+			}()
+
+			config.OsStat = func(name string) (os.FileInfo, error) {
+				return nil, nil
+			}
+			defer func() {
+				config.OsStat = os.Stat
+			}()
+
+			containerID := "container id"
+
+			mockFS := NewMockfileSystem(mockCtrl)
+			mockDocker := NewMockdockerclient(mockCtrl)
+
+			mockFS.EXPECT().ReadFile(config.InstanceConfigFile()).Return([]byte(tc.envFile), nil).AnyTimes()
+			mockFS.EXPECT().ReadFile(config.AgentConfigFile()).Return(nil, errors.New("not found")).AnyTimes()
+			mockDocker.EXPECT().CreateContainer(gomock.Any()).Do(func(opts godocker.CreateContainerOptions) {
+				validateCommonCreateContainerOptions(t, opts)
+				if tc.expectedBind {
+					assert.Contains(t, opts.HostConfig.Binds, credentialHelpersBind)
+				} else {
+					assert.NotContains(t, opts.HostConfig.Binds, credentialHelpersBind)
+				}
+				if tc.expectedPath {
+					assert.Contains(t, opts.Config.Env, credentialHelpersPath)
+				} else {
+					assert.NotContains(t, opts.Config.Env, credentialHelpersPath)
+				}
+			}).Return(&godocker.Container{
+				ID: containerID,
+			}, nil)
+			mockDocker.EXPECT().StartContainer(containerID, nil)
+			mockDocker.EXPECT().WaitContainer(containerID)
+
+			client := &client{
+				docker: mockDocker,
+				fs:     mockFS,
+			}
+
+			_, err := client.StartAgent()
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestStartAgentWithImageTrustPolicyFile(t *testing.T) {
+	imageVerifiersDir := config.ImageVerifiersDirectory()
+	imageVerifiersBind := imageVerifiersDir + ":" + imageVerifiersDir + readOnly
+	testCases := []struct {
+		name         string
+		envFile      string
+		expectedBind bool
+		expectedPath string
+	}{
+		{
+			name:         "image trust policy file",
+			envFile:      "\nECS_IMAGE_TRUST_POLICY_FILE=/etc/ecs/image-trust-policy.json\n",
+			expectedBind: true,
+			expectedPath: "PATH=" + imageVerifiersDir + ":" + defaultAgentPath,
+		},
+		{
+			name: "image trust policy file with credential helpers",
+			envFile: "\nECS_IMAGE_TRUST_POLICY_FILE=/etc/ecs/image-trust-policy.json\n" +
+				"ECS_ENGINE_AUTH_TYPE=credentialhelper\n",
+			expectedBind: true,
+			expectedPath: "PATH=" + config.CredentialHelpersDirectory() + ":" + imageVerifiersDir + ":" + defaultAgentPath,
+		},
+		{
+			name:    "image trust policy file not set",
+			envFile: "\nAGENT_TEST_VAR=val\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			isPathValid = func(path string, isDir bool) bool {
+				return path == imageVerifiersDir
+			}
+			defer func() {
+				isPathValid = defaultIsPathValid
+			}()
+
+			config.OsStat = func(name string) (os.FileInfo, error) {
+				return nil, nil
+			}
+			defer func() {
+				config.OsStat = os.Stat
+			}()
+
+			containerID := "container id"
+
+			mockFS := NewMockfileSystem(mockCtrl)
+			mockDocker := NewMockdockerclient(mockCtrl)
+
+			mockFS.EXPECT().ReadFile(config.InstanceConfigFile()).Return([]byte(tc.envFile), nil).AnyTimes()
+			mockFS.EXPECT().ReadFile(config.AgentConfigFile()).Return(nil, errors.New("not found")).AnyTimes()
+			mockDocker.EXPECT().CreateContainer(gomock.Any()).Do(func(opts godocker.CreateContainerOptions) {
+				validateCommonCreateContainerOptions(t, opts)
+				if tc.expectedBind {
+					assert.Contains(t, opts.HostConfig.Binds, imageVerifiersBind)
+				} else {
+					assert.NotContains(t, opts.HostConfig.Binds, imageVerifiersBind)
+				}
+				if tc.expectedPath != "" {
+					assert.Contains(t, opts.Config.Env, tc.expectedPath)
+				} else {
+					for _, env := range opts.Config.Env {
+						assert.False(t, strings.HasPrefix(env, "PATH="), "PATH should not be set: %s", env)
+					}
+				}
+			}).Return(&godocker.Container{
+				ID: containerID,
+			}, nil)
+			mockDocker.EXPECT().StartContainer(containerID, nil)
+			mockDocker.EXPECT().WaitContainer(containerID)
+
+			client := &client{
+				docker: mockDocker,
+				fs:     mockFS,
+			}
+
+			_, err := client.StartAgent()
+			assert.NoError(t, err)
+		})
+	}
+}
+
 func TestStartAgentWithGPUConfigNoDevices(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -675,6 +1038,8 @@ func TestStopAgent(t *testing.T) {
 		listEmpty            bool
 		stopFailedNotRunning bool
 		stopFailedOther      bool
+		drainingEnabled      bool
+		shuttingDown         bool
 		expectedError        bool
 	}{
 		{
@@ -696,6 +1061,19 @@ func TestStopAgent(t *testing.T) {
 			name:            "List containers succeeded, stop agent failed on error other than not running",
 			stopFailedOther: true,
 		},
+		{
+			name:            "Draining enabled, instance not shutting down",
+			drainingEnabled: true,
+		},
+		{
+			name:            "Draining enabled, instance shutting down",
+			drainingEnabled: true,
+			shuttingDown:    true,
+		},
+		{
+			name:         "Draining disabled, instance shutting down",
+			shuttingDown: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -704,10 +1082,32 @@ func TestStopAgent(t *testing.T) {
 			defer mockCtrl.Finish()
 
 			mockDocker := NewMockdockerclient(mockCtrl)
+			mockFS := NewMockfileSystem(mockCtrl)
+			mockFS.EXPECT().ReadFile(config.InstanceConfigFile()).Return(nil, errors.New("not found")).AnyTimes()
+			envFile := []byte{}
+			stopTimeout := uint(10)
+			if tc.drainingEnabled {
+				envFile = []byte("ECS_ENABLE_TERMINATION_DRAINING=true\n")
+				stopTimeout = uint(70)
+			}
+			mockFS.EXPECT().ReadFile(config.AgentConfigFile()).Return(envFile, nil).AnyTimes()
 			client := &client{
 				docker: mockDocker,
+				fs:     mockFS,
 			}
 
+			shutdownFileWritten := false
+			checkSystemShuttingDown = func() bool { return tc.shuttingDown }
+			writeFile = func(name string, data []byte, perm os.FileMode) error {
+				assert.Equal(t, config.InstanceShutdownFile(), name)
+				shutdownFileWritten = true
+				return nil
+			}
+			defer func() {
+				checkSystemShuttingDown = isSystemShuttingDown
+				writeFile = os.WriteFile
+			}()
+
 			var listInput godocker.ListContainersOptions
 			var listOutput []godocker.APIContainers
 			var listErr error
@@ -742,7 +1142,7 @@ func TestStopAgent(t *testing.T) {
 			}
 
 			if !tc.listEmpty && !tc.listFailed {
-				mockDocker.EXPECT().StopContainer("id", uint(10)).Return(stopErr)
+				mockDocker.EXPECT().StopContainer("id", stopTimeout).Return(stopErr)
 			}
 
 			if tc.listFailed || tc.stopFailedOther {
@@ -750,6 +1150,57 @@ func TestStopAgent(t *testing.T) {
 			} else {
 				assert.NoError(t, client.StopAgent())
 			}
+			assert.Equal(t, !tc.listFailed && !tc.listEmpty && tc.drainingEnabled && tc.shuttingDown, shutdownFileWritten,
+				"the instance shutdown file should only be written when the instance is shutting down")
+		})
+	}
+}
+
+func TestGetStopAgentTimeout(t *testing.T) {
+	testCases := []struct {
+		name            string
+		envFile         string
+		expectedTimeout time.Duration
+	}{
+		{
+			name:            "draining disabled",
+			envFile:         "ECS_TERMINATION_DRAINING_TIMEOUT=3m\n",
+			expectedTimeout: 10 * time.Second,
+		},
+		{
+			name:            "draining enabled with the default timeout",
+			envFile:         "ECS_ENABLE_TERMINATION_DRAINING=true\n",
+			expectedTimeout: time.Minute + 10*time.Second,
+		},
+		{
+			name:            "draining enabled with a custom timeout",
+			envFile:         "ECS_ENABLE_TERMINATION_DRAINING=true\nECS_TERMINATION_DRAINING_TIMEOUT=3m\n",
+			expectedTimeout: 3*time.Minute + 10*time.Second,
+		},
+		{
+			name:            "draining enabled with a timeout above the maximum",
+			envFile:         "ECS_ENABLE_TERMINATION_DRAINING=true\nECS_TERMINATION_DRAINING_TIMEOUT=1h\n",
+			expectedTimeout: 5*time.Minute + 10*time.Second,
+		},
+		{
+			name:            "draining enabled with an invalid timeout",
+			envFile:         "ECS_ENABLE_TERMINATION_DRAINING=true\nECS_TERMINATION_DRAINING_TIMEOUT=invalid\n",
+			expectedTimeout: time.Minute + 10*time.Second,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockFS := NewMockfileSystem(mockCtrl)
+			mockFS.EXPECT().ReadFile(config.InstanceConfigFile()).Return(nil, errors.New("not found"))
+			mockFS.EXPECT().ReadFile(config.AgentConfigFile()).Return([]byte(tc.envFile), nil)
+			client := &client{
+				fs: mockFS,
+			}
+			assert.Equal(t, tc.expectedTimeout, getStopAgentTimeout(client.loadStopEnvVars()))
 		})
 	}
 }